@@ -0,0 +1,23 @@
+package federation
+
+import (
+	"strings"
+
+	"github.com/stellar/go/internal/muxedaccount"
+	"github.com/stellar/go/xdr"
+)
+
+// parseAccount parses an account ID or a muxed account.
+func parseAccount(address string) (xdr.MuxedAccount, error) {
+	if !strings.HasPrefix(address, "M") {
+		var muxed xdr.MuxedAccount
+		err := muxed.SetAddress(address)
+		return muxed, err
+	}
+
+	med, err := muxedaccount.Decode(address)
+	if err != nil {
+		return xdr.MuxedAccount{}, err
+	}
+	return xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeMuxedEd25519, med)
+}
@@ -0,0 +1,490 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/internal/muxedaccount"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Resolver resolves the destinations of payments, which may be Stellar
+// addresses (name*domain), account IDs or muxed accounts. It is intended for
+// services that resolve many addresses, such as payment batching services.
+//
+// stellar.toml files and federation responses are cached for as long as the
+// max-age of their Cache-Control header allows, concurrent requests for the
+// same URL are made once, and the rate of requests made to each domain can be
+// limited.
+type Resolver struct {
+	// HTTP is the client used to fetch stellar.toml files and federation
+	// responses. http.DefaultClient is used if nil.
+	HTTP HTTP
+
+	// AllowHTTP allows federation servers that do not use https.
+	AllowHTTP bool
+
+	// Muxed returns destinations with an id memo as a muxed account (an M...
+	// address, see SEP-23) without a memo.
+	Muxed bool
+
+	// DefaultTTL is how long responses without a Cache-Control max-age are
+	// cached for. Defaults to 5 minutes if zero.
+	DefaultTTL time.Duration
+
+	// MaxTTL is the longest responses are cached for, regardless of their
+	// Cache-Control max-age. Defaults to 24 hours if zero.
+	MaxTTL time.Duration
+
+	// CacheSize is the maximum number of responses cached. Defaults to 10000
+	// if zero.
+	CacheSize int
+
+	// DomainRequestsPerSecond is the maximum rate of requests made to each
+	// domain. Requests are not limited if zero.
+	DomainRequestsPerSecond float64
+
+	// Concurrency is the number of destinations that ResolveBatch resolves
+	// concurrently. Defaults to 10 if zero.
+	Concurrency int
+
+	init   sync.Once
+	client *Client
+	http   *cachingHTTP
+}
+
+// Destination is the resolved destination of a payment.
+type Destination struct {
+	// Address is the address that was resolved.
+	Address string
+	// AccountID is the account ID or muxed account to use as the destination
+	// of the payment.
+	AccountID string
+	// MemoType is the type of the memo that must be set on the transaction
+	// containing the payment, one of "id", "text" or "hash", or empty if no
+	// memo is required.
+	MemoType string
+	// Memo is the memo that must be set on the transaction containing the
+	// payment, or nil if no memo is required.
+	Memo txnbuild.Memo
+
+	muxed *xdr.MuxedAccountMed25519
+}
+
+// Payment returns a payment of the amount of the asset to the destination.
+// The destination's Memo must be set on the transaction it is submitted in.
+//
+// txnbuild.Payment does not accept muxed accounts as its destination, so the
+// payment to a muxed account is an operation that sets the muxed account as
+// the destination of the XDR operation it builds.
+func (d *Destination) Payment(amount string, asset txnbuild.Asset) txnbuild.Operation {
+	if d.muxed == nil {
+		return &txnbuild.Payment{
+			Destination: d.AccountID,
+			Amount:      amount,
+			Asset:       asset,
+		}
+	}
+
+	aid := xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519, Med25519: d.muxed}.ToAccountId()
+	return &muxedPayment{
+		Payment: txnbuild.Payment{
+			Destination: aid.Address(),
+			Amount:      amount,
+			Asset:       asset,
+		},
+		destination: *d.muxed,
+	}
+}
+
+// muxedPayment is a payment to a muxed account.
+type muxedPayment struct {
+	txnbuild.Payment
+	destination xdr.MuxedAccountMed25519
+}
+
+// BuildXDR builds the payment, replacing its destination with the muxed
+// account.
+func (p *muxedPayment) BuildXDR() (xdr.Operation, error) {
+	op, err := p.Payment.BuildXDR()
+	if err != nil {
+		return xdr.Operation{}, err
+	}
+	payment := op.Body.MustPaymentOp()
+	payment.Destination, err = xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeMuxedEd25519, p.destination)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "failed to set destination address")
+	}
+	op.Body.PaymentOp = &payment
+	return op, nil
+}
+
+// BatchResult is the result of resolving one of the destinations passed to
+// ResolveBatch.
+type BatchResult struct {
+	Destination *Destination
+	Err         error
+}
+
+// Resolve resolves the destination, which may be a Stellar address
+// (name*domain), an account ID or a muxed account.
+func (r *Resolver) Resolve(ctx context.Context, destination string) (*Destination, error) {
+	r.initClient()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !strings.Contains(destination, "*") {
+		muxed, err := parseAccount(destination)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid destination")
+		}
+		return &Destination{Address: destination, AccountID: destination, muxed: muxed.Med25519}, nil
+	}
+
+	resp, err := r.client.LookupByAddress(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	muxed, err := parseAccount(resp.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid federation response (account_id)")
+	}
+
+	d := &Destination{
+		Address:   destination,
+		AccountID: resp.AccountID,
+		MemoType:  resp.MemoType,
+		muxed:     muxed.Med25519,
+	}
+	d.Memo, err = memoFromResponse(resp.MemoType, resp.Memo.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid federation response (memo)")
+	}
+
+	if r.Muxed && d.MemoType == "id" && muxed.Type == xdr.CryptoKeyTypeKeyTypeEd25519 {
+		d.muxed = &xdr.MuxedAccountMed25519{
+			Id:      xdr.Uint64(d.Memo.(txnbuild.MemoID)),
+			Ed25519: muxed.MustEd25519(),
+		}
+		d.AccountID = muxedaccount.Encode(*d.muxed)
+		d.MemoType = ""
+		d.Memo = nil
+	}
+
+	return d, nil
+}
+
+// ResolveBatch resolves the destinations concurrently, returning a result for
+// each destination in the same order. Destinations that have not started
+// resolving when the context is done fail with the context's error.
+func (r *Resolver) ResolveBatch(ctx context.Context, destinations []string) []BatchResult {
+	results := make([]BatchResult, len(destinations))
+
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				d, err := r.Resolve(ctx, destinations[i])
+				results[i] = BatchResult{Destination: d, Err: err}
+			}
+		}()
+	}
+	for i := range destinations {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (r *Resolver) initClient() {
+	r.init.Do(func() {
+		h := r.HTTP
+		if h == nil {
+			h = http.DefaultClient
+		}
+		r.http = &cachingHTTP{
+			HTTP:       h,
+			DefaultTTL: r.DefaultTTL,
+			MaxTTL:     r.MaxTTL,
+			Size:       r.CacheSize,
+			limiter:    newHostLimiter(r.DomainRequestsPerSecond),
+		}
+		if r.http.DefaultTTL == 0 {
+			r.http.DefaultTTL = 5 * time.Minute
+		}
+		if r.http.MaxTTL == 0 {
+			r.http.MaxTTL = 24 * time.Hour
+		}
+		if r.http.Size == 0 {
+			r.http.Size = 10000
+		}
+		r.client = &Client{
+			HTTP:        r.http,
+			StellarTOML: &stellartoml.Client{HTTP: r.http},
+			AllowHTTP:   r.AllowHTTP,
+		}
+	})
+}
+
+// memoFromResponse returns the txnbuild memo of a federation response.
+func memoFromResponse(memoType, memo string) (txnbuild.Memo, error) {
+	switch memoType {
+	case "":
+		return nil, nil
+	case "id":
+		id, err := strconv.ParseUint(memo, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing id memo")
+		}
+		return txnbuild.MemoID(id), nil
+	case "text":
+		if len(memo) > 28 {
+			return nil, errors.New("text memo is longer than 28 bytes")
+		}
+		return txnbuild.MemoText(memo), nil
+	case "hash":
+		b, err := base64.StdEncoding.DecodeString(memo)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding hash memo")
+		}
+		if len(b) != 32 {
+			return nil, errors.New("hash memo is not 32 bytes")
+		}
+		var hash txnbuild.MemoHash
+		copy(hash[:], b)
+		return hash, nil
+	default:
+		return nil, errors.Errorf("unsupported memo type %q", memoType)
+	}
+}
+
+// cachingHTTP is an HTTP client that caches successful responses for as long
+// as their Cache-Control header allows.
+type cachingHTTP struct {
+	HTTP       HTTP
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+	Size       int
+
+	limiter *hostLimiter
+
+	mu       sync.Mutex
+	cache    map[string]cachedResponse
+	inflight map[string]*inflightRequest
+}
+
+type cachedResponse struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+type inflightRequest struct {
+	done   chan struct{}
+	status int
+	body   []byte
+	err    error
+}
+
+// cachingHTTPMaxBodySize is the maximum size of the bodies of responses
+// read, one byte more than the largest response the clients accept so that
+// they detect responses that are too large.
+var cachingHTTPMaxBodySize = int64(FederationResponseMaxSize)
+
+func init() {
+	if stellartoml.StellarTomlMaxSize > cachingHTTPMaxBodySize {
+		cachingHTTPMaxBodySize = stellartoml.StellarTomlMaxSize
+	}
+	cachingHTTPMaxBodySize++
+}
+
+// Get returns the cached response for the URL, or gets it.
+func (c *cachingHTTP) Get(rawurl string) (*http.Response, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if cr, ok := c.cache[rawurl]; ok {
+		if now.Before(cr.expiresAt) {
+			c.mu.Unlock()
+			return newResponse(http.StatusOK, cr.body), nil
+		}
+		delete(c.cache, rawurl)
+	}
+	if req, ok := c.inflight[rawurl]; ok {
+		c.mu.Unlock()
+		<-req.done
+		if req.err != nil {
+			return nil, req.err
+		}
+		return newResponse(req.status, req.body), nil
+	}
+	req := &inflightRequest{done: make(chan struct{})}
+	if c.inflight == nil {
+		c.inflight = map[string]*inflightRequest{}
+	}
+	c.inflight[rawurl] = req
+	c.mu.Unlock()
+
+	var ttl time.Duration
+	req.status, req.body, ttl, req.err = c.get(rawurl)
+
+	c.mu.Lock()
+	delete(c.inflight, rawurl)
+	if req.err == nil && req.status == http.StatusOK && ttl > 0 {
+		c.add(rawurl, cachedResponse{body: req.body, expiresAt: time.Now().Add(ttl)})
+	}
+	c.mu.Unlock()
+	close(req.done)
+
+	if req.err != nil {
+		return nil, req.err
+	}
+	return newResponse(req.status, req.body), nil
+}
+
+// get gets the URL, waiting for the rate limit of its host, and returns the
+// response status, body and how long the response can be cached for.
+func (c *cachingHTTP) get(rawurl string) (int, []byte, time.Duration, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return 0, nil, 0, errors.Wrap(err, "parsing url")
+	}
+	c.limiter.wait(strings.ToLower(u.Hostname()))
+
+	resp, err := c.HTTP.Get(rawurl)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, cachingHTTPMaxBodySize))
+	if err != nil {
+		return 0, nil, 0, errors.Wrap(err, "reading response")
+	}
+
+	return resp.StatusCode, body, c.ttl(resp.Header.Get("Cache-Control")), nil
+}
+
+// ttl returns how long a response with the Cache-Control header value can be
+// cached for.
+func (c *cachingHTTP) ttl(cacheControl string) time.Duration {
+	ttl := c.DefaultTTL
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(directive, "max-age="), 10, 64)
+			if err != nil || seconds < 0 {
+				return 0
+			}
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+	if ttl > c.MaxTTL {
+		ttl = c.MaxTTL
+	}
+	return ttl
+}
+
+// add adds the response to the cache, evicting expired responses if the
+// cache is full, and any response if none have expired. The mutex must be
+// held.
+func (c *cachingHTTP) add(rawurl string, cr cachedResponse) {
+	if c.cache == nil {
+		c.cache = map[string]cachedResponse{}
+	}
+	if len(c.cache) >= c.Size {
+		now := time.Now()
+		for k, v := range c.cache {
+			if !now.Before(v.expiresAt) {
+				delete(c.cache, k)
+			}
+		}
+		for k := range c.cache {
+			if len(c.cache) < c.Size {
+				break
+			}
+			delete(c.cache, k)
+		}
+	}
+	c.cache[rawurl] = cr
+}
+
+func newResponse(status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+// hostLimiter limits the rate of requests made to each host.
+type hostLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newHostLimiter(requestsPerSecond float64) *hostLimiter {
+	l := &hostLimiter{next: map[string]time.Time{}}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return l
+}
+
+// wait blocks until a request can be made to the host.
+func (l *hostLimiter) wait(host string) {
+	if l.interval == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	next := l.next[host]
+	if next.Before(now) {
+		next = now
+	}
+	l.next[host] = next.Add(l.interval)
+	// Forget hosts that have not been requested recently so that the map does
+	// not grow without bound.
+	if len(l.next) > 1000 {
+		for h, n := range l.next {
+			if n.Before(now) {
+				delete(l.next, h)
+			}
+		}
+	}
+	l.mu.Unlock()
+
+	time.Sleep(next.Sub(now))
+}
+
+var _ HTTP = &cachingHTTP{}
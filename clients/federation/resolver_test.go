@@ -0,0 +1,262 @@
+package federation
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResponse struct {
+	status       int
+	cacheControl string
+	body         string
+}
+
+// stubHTTP responds to requests with the response for their URL and counts
+// the requests made.
+type stubHTTP struct {
+	responses map[string]stubResponse
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (h *stubHTTP) Get(url string) (*http.Response, error) {
+	h.mu.Lock()
+	if h.calls == nil {
+		h.calls = map[string]int{}
+	}
+	h.calls[url]++
+	h.mu.Unlock()
+
+	r, ok := h.responses[url]
+	if !ok {
+		r = stubResponse{status: http.StatusNotFound}
+	}
+	header := http.Header{}
+	if r.cacheControl != "" {
+		header.Set("Cache-Control", r.cacheControl)
+	}
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(r.body)),
+	}, nil
+}
+
+func (h *stubHTTP) count(url string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls[url]
+}
+
+const (
+	testTOMLURL       = "https://stellar.org/.well-known/stellar.toml"
+	testFederationURL = "https://stellar.org/federation"
+)
+
+func newStubHTTP() *stubHTTP {
+	return &stubHTTP{responses: map[string]stubResponse{
+		testTOMLURL: {
+			status:       http.StatusOK,
+			cacheControl: "max-age=3600",
+			body:         `FEDERATION_SERVER="https://stellar.org/federation"`,
+		},
+		testFederationURL + "?q=scott%2Astellar.org&type=name": {
+			status:       http.StatusOK,
+			cacheControl: "max-age=60",
+			body:         `{"stellar_address":"scott*stellar.org","account_id":"GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"}`,
+		},
+		testFederationURL + "?q=bartek%2Astellar.org&type=name": {
+			status:       http.StatusOK,
+			cacheControl: "no-cache",
+			body:         `{"stellar_address":"bartek*stellar.org","account_id":"GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ","memo_type":"id","memo":9223372036854775808}`,
+		},
+		testFederationURL + "?q=jed%2Astellar.org&type=name": {
+			status: http.StatusOK,
+			body:   `{"stellar_address":"jed*stellar.org","account_id":"GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG","memo_type":"text","memo":"jed"}`,
+		},
+	}}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	h := newStubHTTP()
+	r := &Resolver{HTTP: h}
+	ctx := context.Background()
+
+	d, err := r.Resolve(ctx, "scott*stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, &Destination{
+		Address:   "scott*stellar.org",
+		AccountID: "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG",
+	}, d)
+
+	d, err = r.Resolve(ctx, "bartek*stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, &Destination{
+		Address:   "bartek*stellar.org",
+		AccountID: "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ",
+		MemoType:  "id",
+		Memo:      txnbuild.MemoID(9223372036854775808),
+	}, d)
+
+	d, err = r.Resolve(ctx, "jed*stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, txnbuild.MemoText("jed"), d.Memo)
+
+	payment, ok := d.Payment("10", txnbuild.NativeAsset{}).(*txnbuild.Payment)
+	require.True(t, ok)
+	assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", payment.Destination)
+	assert.Equal(t, "10", payment.Amount)
+
+	// Account IDs and muxed accounts are returned without a lookup.
+	d, err = r.Resolve(ctx, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK")
+	require.NoError(t, err)
+	assert.Equal(t, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK", d.AccountID)
+	assert.Nil(t, d.Memo)
+
+	_, err = r.Resolve(ctx, "not an address")
+	assert.Error(t, err)
+
+	// The checksum is invalid.
+	_, err = r.Resolve(ctx, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUR")
+	assert.Error(t, err)
+
+	_, err = r.Resolve(ctx, "unknown*stellar.org")
+	assert.Error(t, err)
+
+	// stellar.toml was fetched once and is cached.
+	assert.Equal(t, 1, h.count(testTOMLURL))
+}
+
+func TestResolver_Resolve_muxed(t *testing.T) {
+	r := &Resolver{HTTP: newStubHTTP(), Muxed: true}
+	ctx := context.Background()
+
+	d, err := r.Resolve(ctx, "bartek*stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, "bartek*stellar.org", d.Address)
+	assert.Equal(t, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK", d.AccountID)
+	assert.Empty(t, d.MemoType)
+	assert.Nil(t, d.Memo)
+
+	// The payment is made to the muxed account.
+	sourceAccount := txnbuild.NewSimpleAccount("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", 1)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &sourceAccount,
+		Operations:    []txnbuild.Operation{d.Payment("10", txnbuild.NativeAsset{})},
+		BaseFee:       txnbuild.MinBaseFee,
+		Timebounds:    txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	env, err := tx.TxEnvelope()
+	require.NoError(t, err)
+	destination := env.Operations()[0].Body.MustPaymentOp().Destination
+	assert.Equal(t, xdr.CryptoKeyTypeKeyTypeMuxedEd25519, destination.Type)
+	assert.Equal(t, xdr.Uint64(9223372036854775808), destination.MustMed25519().Id)
+	aid := destination.ToAccountId()
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", aid.Address())
+
+	// Muxed accounts are parsed.
+	d, err = r.Resolve(ctx, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUQ")
+	require.NoError(t, err)
+	require.NotNil(t, d.muxed)
+	assert.Equal(t, xdr.Uint64(0), d.muxed.Id)
+
+	// Other memos are left unchanged.
+	d, err = r.Resolve(ctx, "jed*stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", d.AccountID)
+	assert.Equal(t, txnbuild.MemoText("jed"), d.Memo)
+}
+
+func TestResolver_caching(t *testing.T) {
+	h := newStubHTTP()
+	r := &Resolver{HTTP: h}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		for _, address := range []string{"scott*stellar.org", "bartek*stellar.org", "jed*stellar.org"} {
+			_, err := r.Resolve(ctx, address)
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, 1, h.count(testTOMLURL))
+	// Cached for its max-age.
+	assert.Equal(t, 1, h.count(testFederationURL+"?q=scott%2Astellar.org&type=name"))
+	// Not cached because of no-cache.
+	assert.Equal(t, 3, h.count(testFederationURL+"?q=bartek%2Astellar.org&type=name"))
+	// Cached for the default TTL.
+	assert.Equal(t, 1, h.count(testFederationURL+"?q=jed%2Astellar.org&type=name"))
+}
+
+func TestResolver_ResolveBatch(t *testing.T) {
+	h := newStubHTTP()
+	r := &Resolver{HTTP: h, Concurrency: 3}
+
+	addresses := []string{
+		"scott*stellar.org",
+		"unknown*stellar.org",
+		"bartek*stellar.org",
+		"GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG",
+		"scott*stellar.org",
+	}
+	results := r.ResolveBatch(context.Background(), addresses)
+	require.Len(t, results, len(addresses))
+
+	for i, result := range results {
+		if addresses[i] == "unknown*stellar.org" {
+			assert.Error(t, result.Err)
+			continue
+		}
+		require.NoError(t, result.Err, addresses[i])
+		assert.Equal(t, addresses[i], result.Destination.Address)
+	}
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", results[2].Destination.AccountID)
+
+	// Concurrent requests for stellar.toml are made once.
+	assert.Equal(t, 1, h.count(testTOMLURL))
+
+	// Destinations fail once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = r.ResolveBatch(ctx, addresses)
+	for _, result := range results {
+		assert.Equal(t, context.Canceled, result.Err)
+	}
+}
+
+func TestResolver_domainRateLimit(t *testing.T) {
+	r := &Resolver{HTTP: newStubHTTP(), DomainRequestsPerSecond: 20, Concurrency: 1}
+
+	// stellar.toml and two uncached federation requests, the first made
+	// immediately and each following after 50ms.
+	start := time.Now()
+	results := r.ResolveBatch(context.Background(), []string{"bartek*stellar.org", "bartek*stellar.org"})
+	elapsed := time.Since(start)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+	assert.True(t, elapsed >= 100*time.Millisecond, "elapsed %s", elapsed)
+}
+
+func TestCachingHTTP_ttl(t *testing.T) {
+	c := &cachingHTTP{DefaultTTL: time.Minute, MaxTTL: time.Hour}
+
+	assert.Equal(t, time.Minute, c.ttl(""))
+	assert.Equal(t, 30*time.Second, c.ttl("public, max-age=30"))
+	assert.Equal(t, time.Hour, c.ttl("max-age=86400"))
+	assert.Equal(t, time.Duration(0), c.ttl("max-age=60, no-store"))
+	assert.Equal(t, time.Duration(0), c.ttl("No-Cache"))
+	assert.Equal(t, time.Duration(0), c.ttl("max-age=invalid"))
+}
@@ -560,6 +560,20 @@ func (c *Client) StrictSendPaths(request StrictSendPathsRequest) (paths hProtoco
 	return
 }
 
+// StrictReceiveSplitPaths returns strict receive payments split across several payment paths.
+// The paths of each record are meant to be submitted, in order, as operations of a single transaction.
+func (c *Client) StrictReceiveSplitPaths(request SplitPathsRequest) (paths hProtocol.SplitPathsPage, err error) {
+	err = c.sendRequest(request, &paths)
+	return
+}
+
+// StrictSendSplitPaths returns strict send payments split across several payment paths.
+// The paths of each record are meant to be submitted, in order, as operations of a single transaction.
+func (c *Client) StrictSendSplitPaths(request StrictSendSplitPathsRequest) (paths hProtocol.SplitPathsPage, err error) {
+	err = c.sendRequest(request, &paths)
+	return
+}
+
 // Payments returns stellar account_merge, create_account, path payment and payment operations.
 // It can be used to return payments for an account, a ledger, a transaction and all payments on the network.
 func (c *Client) Payments(request OperationRequest) (ops operations.OperationsPage, err error) {
@@ -823,5 +837,73 @@ func (c *Client) ClaimableBalance(id string) (cb hProtocol.ClaimableBalance, err
 	return
 }
 
+// IterateAccounts returns an iterator walking every page of accounts matching
+// the request. Its records are hProtocol.Account.
+func (c *Client) IterateAccounts(ctx context.Context, request AccountsRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.Account{}))
+}
+
+// IterateAssets returns an iterator walking every page of assets matching the
+// request. Its records are hProtocol.AssetStat.
+func (c *Client) IterateAssets(ctx context.Context, request AssetRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.AssetStat{}))
+}
+
+// IterateLedgers returns an iterator walking every page of ledgers matching
+// the request. Its records are hProtocol.Ledger.
+func (c *Client) IterateLedgers(ctx context.Context, request LedgerRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.Ledger{}))
+}
+
+// IterateEffects returns an iterator walking every page of effects matching
+// the request. Its records are effects.Effect.
+func (c *Client) IterateEffects(ctx context.Context, request EffectRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeEffect)
+}
+
+// IterateTransactions returns an iterator walking every page of transactions
+// matching the request. Its records are hProtocol.Transaction.
+func (c *Client) IterateTransactions(ctx context.Context, request TransactionRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.Transaction{}))
+}
+
+// IterateOperations returns an iterator walking every page of operations
+// matching the request. Its records are operations.Operation.
+func (c *Client) IterateOperations(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request.SetOperationsEndpoint(), opts, decodeOperation)
+}
+
+// IteratePayments returns an iterator walking every page of payments matching
+// the request. Its records are operations.Operation.
+func (c *Client) IteratePayments(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request.SetPaymentsEndpoint(), opts, decodeOperation)
+}
+
+// IterateOffers returns an iterator walking every page of offers matching the
+// request. Its records are hProtocol.Offer.
+func (c *Client) IterateOffers(ctx context.Context, request OfferRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.Offer{}))
+}
+
+// IterateTrades returns an iterator walking every page of trades matching the
+// request. Its records are hProtocol.Trade.
+func (c *Client) IterateTrades(ctx context.Context, request TradeRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.Trade{}))
+}
+
+// IterateTradeAggregations returns an iterator walking every page of trade
+// aggregations matching the request. Its records are
+// hProtocol.TradeAggregation.
+func (c *Client) IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.TradeAggregation{}))
+}
+
+// IterateClaimableBalances returns an iterator walking every page of
+// claimable balances matching the request. Its records are
+// hProtocol.ClaimableBalance.
+func (c *Client) IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, opts IteratorOpts) *Iterator {
+	return newIterator(ctx, c, request, opts, decodeAs(hProtocol.ClaimableBalance{}))
+}
+
 // ensure that the horizon client implements ClientInterface
 var _ ClientInterface = &Client{}
@@ -0,0 +1,266 @@
+package horizonclient
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/stellar/go/protocols/horizon/effects"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// IteratorOpts contains options for walking a collection endpoint with an
+// iterator.
+type IteratorOpts struct {
+	// MaxRecords is the maximum number of records returned by the iterator.
+	// If it is 0 the iterator walks the collection until it is exhausted.
+	MaxRecords uint
+	// Prefetch is the number of pages fetched ahead of the caller in a
+	// separate goroutine. If it is 0 pages are fetched on demand, only when
+	// the records of the current page have all been consumed.
+	Prefetch uint
+}
+
+// rawPage is a page of any collection endpoint, with its records left
+// undecoded. Every collection endpoint returns pages with the links and
+// embedded records of a hal.Page.
+type rawPage struct {
+	Links    hal.Links `json:"_links"`
+	Embedded struct {
+		Records []json.RawMessage `json:"records"`
+	} `json:"_embedded"`
+}
+
+// decodeFunc decodes a single record of a collection endpoint.
+type decodeFunc func(json.RawMessage) (interface{}, error)
+
+type pageResult struct {
+	page rawPage
+	err  error
+}
+
+// Iterator walks the records of a collection endpoint, fetching the pages
+// that follow the first one through their next links. It stops when a page
+// without records is returned, when the page has no next link, when
+// opts.MaxRecords records were returned or on the first error.
+type Iterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   IteratorOpts
+	client *Client
+	decode decodeFunc
+
+	// fetch fetches the next page, and is nil once the last page was
+	// fetched.
+	fetch   func() (rawPage, error)
+	pages   chan pageResult
+	records []interface{}
+	current interface{}
+	count   uint
+	done    bool
+	err     error
+}
+
+func newIterator(ctx context.Context, client *Client, request HorizonRequest, opts IteratorOpts, decode decodeFunc) *Iterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator{
+		ctx:    ctx,
+		cancel: cancel,
+		opts:   opts,
+		client: client,
+		decode: decode,
+	}
+	it.fetch = func() (page rawPage, err error) {
+		err = client.sendRequest(request, &page)
+		return
+	}
+
+	if opts.Prefetch > 0 {
+		it.pages = make(chan pageResult, opts.Prefetch-1)
+		go it.prefetch(it.fetch)
+	}
+
+	return it
+}
+
+// NewRecordsIterator returns an iterator over the given records, which does
+// not fetch any page. It is meant to be returned by the Iterate methods of a
+// MockClient.
+func NewRecordsIterator(records ...interface{}) *Iterator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Iterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		records: records,
+	}
+}
+
+// nextFetch returns the function fetching the page following the page, or
+// nil if the page is the last one.
+func (it *Iterator) nextFetch(page rawPage) func() (rawPage, error) {
+	href := page.Links.Next.Href
+	if href == "" || len(page.Embedded.Records) == 0 {
+		return nil
+	}
+	return func() (next rawPage, err error) {
+		err = it.client.sendRequestURL(href, "get", &next)
+		return
+	}
+}
+
+// prefetch fetches pages in the background until the collection is
+// exhausted, an error occurs or the iterator is closed.
+func (it *Iterator) prefetch(fetch func() (rawPage, error)) {
+	defer close(it.pages)
+
+	for fetch != nil {
+		page, err := fetch()
+		select {
+		case it.pages <- pageResult{page: page, err: err}:
+		case <-it.ctx.Done():
+			return
+		}
+
+		if err != nil {
+			return
+		}
+		fetch = it.nextFetch(page)
+	}
+}
+
+// nextPage returns the next page either from the prefetching goroutine or by
+// fetching it directly. It returns a page without records once the
+// collection is exhausted.
+func (it *Iterator) nextPage() (rawPage, error) {
+	if it.pages == nil {
+		if it.fetch == nil {
+			return rawPage{}, nil
+		}
+		page, err := it.fetch()
+		if err == nil {
+			it.fetch = it.nextFetch(page)
+		}
+		return page, err
+	}
+
+	select {
+	case result, ok := <-it.pages:
+		if !ok {
+			return rawPage{}, nil
+		}
+		return result.page, result.err
+	case <-it.ctx.Done():
+		return rawPage{}, it.ctx.Err()
+	}
+}
+
+// Next advances the iterator to the next record. It returns false when there
+// are no more records or when an error occurred, in which case Err returns
+// the error.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if it.opts.MaxRecords > 0 && it.count >= it.opts.MaxRecords {
+		it.Close()
+		return false
+	}
+
+	for len(it.records) == 0 {
+		if err := it.ctx.Err(); err != nil {
+			it.fail(err)
+			return false
+		}
+
+		page, err := it.nextPage()
+		if err != nil {
+			it.fail(err)
+			return false
+		}
+
+		if len(page.Embedded.Records) == 0 {
+			it.Close()
+			return false
+		}
+		if it.records, err = it.decodePage(page); err != nil {
+			it.fail(err)
+			return false
+		}
+	}
+
+	it.current = it.records[0]
+	it.records = it.records[1:]
+	it.count++
+	return true
+}
+
+// decodePage decodes the records of the page.
+func (it *Iterator) decodePage(page rawPage) ([]interface{}, error) {
+	records := make([]interface{}, len(page.Embedded.Records))
+	for i, data := range page.Embedded.Records {
+		record, err := it.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// Record returns the current record. Its type is the type of the records of
+// the endpoint walked, e.g. hProtocol.Account for the accounts endpoint or
+// operations.Operation for the operations endpoint.
+func (it *Iterator) Record() interface{} {
+	return it.current
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and releases the prefetching goroutine. It is
+// safe to call Close multiple times.
+func (it *Iterator) Close() {
+	it.done = true
+	it.records = nil
+	it.cancel()
+}
+
+func (it *Iterator) fail(err error) {
+	it.err = err
+	it.Close()
+}
+
+// decodeAs returns a decodeFunc decoding records into values of the type of
+// record.
+func decodeAs(record interface{}) decodeFunc {
+	t := reflect.TypeOf(record)
+	return func(data json.RawMessage) (interface{}, error) {
+		v := reflect.New(t)
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+}
+
+// decodeOperation decodes an operation into the struct of its type.
+func decodeOperation(data json.RawMessage) (interface{}, error) {
+	var base operations.Base
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	return operations.UnmarshalOperation(base.TypeI, data)
+}
+
+// decodeEffect decodes an effect into the struct of its type.
+func decodeEffect(data json.RawMessage) (interface{}, error) {
+	var base effects.Base
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	return effects.UnmarshalEffect(base.Type, data)
+}
@@ -0,0 +1,246 @@
+package horizonclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pageResponse returns the JSON of a page with the records and a next link
+// to the URL, if not empty.
+func pageResponse(next string, records ...string) string {
+	return fmt.Sprintf(
+		`{"_links": {"next": {"href": %q}}, "_embedded": {"records": [%s]}}`,
+		next, strings.Join(records, ","),
+	)
+}
+
+func paymentResponse(id string) string {
+	return fmt.Sprintf(`{"id": %q, "paging_token": %q, "type": "payment", "type_i": 1}`, id, id)
+}
+
+func collectOperations(it *Iterator) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Record().(operations.Operation).GetID())
+	}
+	return ids
+}
+
+func mockOperationsPages(hmock *httptest.Client, first string) {
+	hmock.On("GET", first).
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=2", paymentResponse("1"), paymentResponse("2")))
+	hmock.On("GET", "https://localhost/operations?cursor=2").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=4", paymentResponse("3"), paymentResponse("4")))
+	hmock.On("GET", "https://localhost/operations?cursor=4").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=5", paymentResponse("5")))
+	hmock.On("GET", "https://localhost/operations?cursor=5").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=5"))
+}
+
+func TestIterateOperations(t *testing.T) {
+	for _, prefetch := range []uint{0, 1, 3} {
+		t.Run(fmt.Sprintf("prefetch %d", prefetch), func(t *testing.T) {
+			hmock := httptest.NewClient()
+			client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+			mockOperationsPages(hmock, "https://localhost/accounts/GABC/operations?limit=2")
+
+			request := OperationRequest{ForAccount: "GABC", Limit: 2}
+			it := client.IterateOperations(context.Background(), request, IteratorOpts{Prefetch: prefetch})
+			defer it.Close()
+
+			assert.Equal(t, []string{"1", "2", "3", "4", "5"}, collectOperations(it))
+			assert.NoError(t, it.Err())
+			assert.False(t, it.Next())
+		})
+	}
+}
+
+func TestIterateOperations_maxRecords(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/operations?limit=2").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=2", paymentResponse("1"), paymentResponse("2")))
+	hmock.On("GET", "https://localhost/operations?cursor=2").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=4", paymentResponse("3"), paymentResponse("4")))
+
+	// The third page is not fetched, as it has no responder and would fail
+	// the iterator.
+	it := client.IterateOperations(context.Background(), OperationRequest{Limit: 2}, IteratorOpts{MaxRecords: 3})
+	defer it.Close()
+
+	assert.Equal(t, []string{"1", "2", "3"}, collectOperations(it))
+	assert.NoError(t, it.Err())
+}
+
+func TestIterateOperations_lastPage(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/operations").
+		ReturnString(200, pageResponse("", paymentResponse("1")))
+
+	it := client.IterateOperations(context.Background(), OperationRequest{}, IteratorOpts{Prefetch: 2})
+	defer it.Close()
+
+	assert.Equal(t, []string{"1"}, collectOperations(it))
+	assert.NoError(t, it.Err())
+}
+
+func TestIterateOperations_error(t *testing.T) {
+	for _, prefetch := range []uint{0, 2} {
+		t.Run(fmt.Sprintf("prefetch %d", prefetch), func(t *testing.T) {
+			hmock := httptest.NewClient()
+			client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+			hmock.On("GET", "https://localhost/operations").
+				ReturnString(200, pageResponse("https://localhost/operations?cursor=1", paymentResponse("1")))
+			hmock.On("GET", "https://localhost/operations?cursor=1").
+				ReturnError("horizon unavailable")
+
+			it := client.IterateOperations(context.Background(), OperationRequest{}, IteratorOpts{Prefetch: prefetch})
+			defer it.Close()
+
+			assert.Equal(t, []string{"1"}, collectOperations(it))
+			if assert.Error(t, it.Err()) {
+				assert.Contains(t, it.Err().Error(), "horizon unavailable")
+			}
+		})
+	}
+}
+
+func TestIterateOperations_contextCancelled(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/operations").
+		ReturnString(200, pageResponse("https://localhost/operations?cursor=1", paymentResponse("1")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.IterateOperations(ctx, OperationRequest{}, IteratorOpts{})
+	defer it.Close()
+
+	require.True(t, it.Next())
+	cancel()
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
+
+func TestIteratePayments(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/ledgers/10/payments").
+		ReturnString(200, pageResponse("https://localhost/ledgers/10/payments?cursor=1", paymentResponse("1")))
+	hmock.On("GET", "https://localhost/ledgers/10/payments?cursor=1").
+		ReturnString(200, pageResponse("https://localhost/ledgers/10/payments?cursor=1"))
+
+	it := client.IteratePayments(context.Background(), OperationRequest{ForLedger: 10}, IteratorOpts{})
+	defer it.Close()
+
+	assert.Equal(t, []string{"1"}, collectOperations(it))
+	assert.NoError(t, it.Err())
+}
+
+func TestIterateTrades(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/accounts/GABC/trades").
+		ReturnString(200, pageResponse("https://localhost/trades?cursor=b", `{"id": "a"}`, `{"id": "b"}`))
+	hmock.On("GET", "https://localhost/trades?cursor=b").
+		ReturnString(200, pageResponse("https://localhost/trades?cursor=b"))
+
+	it := client.IterateTrades(context.Background(), TradeRequest{ForAccount: "GABC"}, IteratorOpts{Prefetch: 1})
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Record().(hProtocol.Trade).ID)
+	}
+	assert.Equal(t, []string{"a", "b"}, ids)
+	assert.NoError(t, it.Err())
+}
+
+func TestIterateClaimableBalances(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/claimable_balances?sponsor=GABC").
+		ReturnString(200, pageResponse(
+			"https://localhost/claimable_balances?cursor=2&sponsor=GABC",
+			`{"id": "1", "amount": "10.0000000"}`,
+			`{"id": "2", "amount": "20.0000000"}`,
+		))
+	hmock.On("GET", "https://localhost/claimable_balances?cursor=2&sponsor=GABC").
+		ReturnString(200, pageResponse(
+			"https://localhost/claimable_balances?cursor=3&sponsor=GABC",
+			`{"id": "3", "amount": "30.0000000"}`,
+		))
+	hmock.On("GET", "https://localhost/claimable_balances?cursor=3&sponsor=GABC").
+		ReturnString(200, pageResponse("https://localhost/claimable_balances?cursor=3&sponsor=GABC"))
+
+	it := client.IterateClaimableBalances(context.Background(), ClaimableBalanceRequest{Sponsor: "GABC"}, IteratorOpts{})
+	defer it.Close()
+
+	var balances []hProtocol.ClaimableBalance
+	for it.Next() {
+		balances = append(balances, it.Record().(hProtocol.ClaimableBalance))
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, balances, 3)
+	assert.Equal(t, "1", balances[0].BalanceID)
+	assert.Equal(t, "20.0000000", balances[1].Amount)
+	assert.Equal(t, "3", balances[2].BalanceID)
+}
+
+func TestIterator_decodeError(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	hmock.On("GET", "https://localhost/accounts/GABC/trades").
+		ReturnString(200, pageResponse("", `{"id": 1}`))
+
+	it := client.IterateTrades(context.Background(), TradeRequest{ForAccount: "GABC"}, IteratorOpts{})
+	defer it.Close()
+
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+}
+
+// countPayments is a consumer of the iterators, counting the payments of the
+// account through any ClientInterface.
+func countPayments(ctx context.Context, client ClientInterface, account string) (int, error) {
+	it := client.IteratePayments(ctx, OperationRequest{ForAccount: account}, IteratorOpts{Prefetch: 1})
+	defer it.Close()
+
+	count := 0
+	for it.Next() {
+		if _, ok := it.Record().(operations.Payment); ok {
+			count++
+		}
+	}
+	return count, it.Err()
+}
+
+func TestIterator_mockClient(t *testing.T) {
+	client := &MockClient{}
+	client.On("IteratePayments", mock.Anything, OperationRequest{ForAccount: "GABC"}, IteratorOpts{Prefetch: 1}).
+		Return(NewRecordsIterator(
+			operations.Payment{Base: operations.Base{ID: "1"}},
+			operations.CreateAccount{Base: operations.Base{ID: "2"}},
+			operations.Payment{Base: operations.Base{ID: "3"}},
+		)).Once()
+
+	count, err := countPayments(context.Background(), client, "GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	client.AssertExpectations(t)
+}
+
+func TestNewRecordsIterator_empty(t *testing.T) {
+	it := NewRecordsIterator()
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+}
@@ -207,6 +207,17 @@ type ClientInterface interface {
 	HomeDomainForAccount(aid string) (string, error)
 	NextTradeAggregationsPage(hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
 	PrevTradeAggregationsPage(hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	IterateAccounts(ctx context.Context, request AccountsRequest, opts IteratorOpts) *Iterator
+	IterateAssets(ctx context.Context, request AssetRequest, opts IteratorOpts) *Iterator
+	IterateLedgers(ctx context.Context, request LedgerRequest, opts IteratorOpts) *Iterator
+	IterateEffects(ctx context.Context, request EffectRequest, opts IteratorOpts) *Iterator
+	IterateTransactions(ctx context.Context, request TransactionRequest, opts IteratorOpts) *Iterator
+	IterateOperations(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator
+	IteratePayments(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator
+	IterateOffers(ctx context.Context, request OfferRequest, opts IteratorOpts) *Iterator
+	IterateTrades(ctx context.Context, request TradeRequest, opts IteratorOpts) *Iterator
+	IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, opts IteratorOpts) *Iterator
+	IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, opts IteratorOpts) *Iterator
 }
 
 // DefaultTestNetClient is a default client to connect to test network.
@@ -374,6 +385,20 @@ type StrictSendPathsRequest struct {
 	SourceAmount       string
 }
 
+// SplitPathsRequest struct contains data for getting strict receive payments split
+// across at most MaxSplits payment paths from a horizon server.
+type SplitPathsRequest struct {
+	PathsRequest
+	MaxSplits uint
+}
+
+// StrictSendSplitPathsRequest struct contains data for getting strict send payments split
+// across at most MaxSplits payment paths from a horizon server.
+type StrictSendSplitPathsRequest struct {
+	StrictSendPathsRequest
+	MaxSplits uint
+}
+
 // TradeRequest struct contains data for getting trade details from a horizon server.
 // "ForAccount", "ForOfferID": Only one of these can be set at a time. If none are provided, the
 // default is to return all trades.
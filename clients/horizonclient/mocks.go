@@ -329,5 +329,71 @@ func (m *MockClient) PrevTradeAggregationsPage(page hProtocol.TradeAggregationsP
 	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
 }
 
+// IterateAccounts is a mocking method
+func (m *MockClient) IterateAccounts(ctx context.Context, request AccountsRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateAssets is a mocking method
+func (m *MockClient) IterateAssets(ctx context.Context, request AssetRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateLedgers is a mocking method
+func (m *MockClient) IterateLedgers(ctx context.Context, request LedgerRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateEffects is a mocking method
+func (m *MockClient) IterateEffects(ctx context.Context, request EffectRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateTransactions is a mocking method
+func (m *MockClient) IterateTransactions(ctx context.Context, request TransactionRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateOperations is a mocking method
+func (m *MockClient) IterateOperations(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IteratePayments is a mocking method
+func (m *MockClient) IteratePayments(ctx context.Context, request OperationRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateOffers is a mocking method
+func (m *MockClient) IterateOffers(ctx context.Context, request OfferRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateTrades is a mocking method
+func (m *MockClient) IterateTrades(ctx context.Context, request TradeRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateTradeAggregations is a mocking method
+func (m *MockClient) IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
+// IterateClaimableBalances is a mocking method
+func (m *MockClient) IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, opts IteratorOpts) *Iterator {
+	a := m.Called(ctx, request, opts)
+	return a.Get(0).(*Iterator)
+}
+
 // ensure that the MockClient implements ClientInterface
 var _ ClientInterface = &MockClient{}
@@ -0,0 +1,150 @@
+package horizonclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// BuildURL creates the endpoint to be queried based on the data in the SplitPathsRequest struct.
+func (pr SplitPathsRequest) BuildURL() (endpoint string, err error) {
+	endpoint = "paths/strict-receive"
+
+	// add the parameters to a map here so it is easier for addQueryParams to populate the parameter list
+	// We can't use assetCode and assetIssuer types here because the paremeter names are different
+	paramMap := make(map[string]string)
+	paramMap["destination_account"] = pr.DestinationAccount
+	paramMap["destination_asset_type"] = string(pr.DestinationAssetType)
+	paramMap["destination_asset_code"] = pr.DestinationAssetCode
+	paramMap["destination_asset_issuer"] = pr.DestinationAssetIssuer
+	paramMap["destination_amount"] = pr.DestinationAmount
+	paramMap["source_account"] = pr.SourceAccount
+	paramMap["source_assets"] = pr.SourceAssets
+	if pr.MaxSplits > 0 {
+		paramMap["max_splits"] = strconv.FormatUint(uint64(pr.MaxSplits), 10)
+	}
+
+	queryParams := addQueryParams(paramMap)
+	if queryParams != "" {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, queryParams)
+	}
+
+	_, err = url.Parse(endpoint)
+	if err != nil {
+		err = errors.Wrap(err, "failed to parse endpoint")
+	}
+
+	return endpoint, err
+}
+
+// BuildURL creates the endpoint to be queried based on the data in the StrictSendSplitPathsRequest struct.
+func (pr StrictSendSplitPathsRequest) BuildURL() (endpoint string, err error) {
+	endpoint = "paths/strict-send"
+
+	// add the parameters to a map here so it is easier for addQueryParams to populate the parameter list
+	// We can't use assetCode and assetIssuer types here because the parameter names are different
+	paramMap := make(map[string]string)
+	paramMap["destination_assets"] = pr.DestinationAssets
+	paramMap["destination_account"] = pr.DestinationAccount
+	paramMap["source_asset_type"] = string(pr.SourceAssetType)
+	paramMap["source_asset_code"] = pr.SourceAssetCode
+	paramMap["source_asset_issuer"] = pr.SourceAssetIssuer
+	paramMap["source_amount"] = pr.SourceAmount
+	if pr.MaxSplits > 0 {
+		paramMap["max_splits"] = strconv.FormatUint(uint64(pr.MaxSplits), 10)
+	}
+
+	queryParams := addQueryParams(paramMap)
+	if queryParams != "" {
+		endpoint = fmt.Sprintf("%s?%s", endpoint, queryParams)
+	}
+
+	_, err = url.Parse(endpoint)
+	if err != nil {
+		err = errors.Wrap(err, "failed to parse endpoint")
+	}
+
+	return endpoint, err
+}
+
+// StrictReceiveSplitPathOperations returns one path payment strict receive operation
+// for each path of a split path returned by StrictReceiveSplitPaths. Each operation
+// delivers its part of the destination amount to destination and spends at most the
+// source amount quoted for its path. The operations must be submitted in order in a
+// single transaction.
+func StrictReceiveSplitPathOperations(split hProtocol.SplitPath, destination string) ([]txnbuild.Operation, error) {
+	ops := make([]txnbuild.Operation, 0, len(split.Paths))
+	for i, p := range split.Paths {
+		sendAsset, destAsset, path, err := pathAssets(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid path %d", i)
+		}
+		ops = append(ops, &txnbuild.PathPaymentStrictReceive{
+			SendAsset:   sendAsset,
+			SendMax:     p.SourceAmount,
+			Destination: destination,
+			DestAsset:   destAsset,
+			DestAmount:  p.DestinationAmount,
+			Path:        path,
+		})
+	}
+	return ops, nil
+}
+
+// StrictSendSplitPathOperations returns one path payment strict send operation
+// for each path of a split path returned by StrictSendSplitPaths. Each operation
+// spends its part of the source amount and requires at least the destination amount
+// quoted for its path to be delivered to destination. The operations must be
+// submitted in order in a single transaction.
+func StrictSendSplitPathOperations(split hProtocol.SplitPath, destination string) ([]txnbuild.Operation, error) {
+	ops := make([]txnbuild.Operation, 0, len(split.Paths))
+	for i, p := range split.Paths {
+		sendAsset, destAsset, path, err := pathAssets(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid path %d", i)
+		}
+		ops = append(ops, &txnbuild.PathPaymentStrictSend{
+			SendAsset:   sendAsset,
+			SendAmount:  p.SourceAmount,
+			Destination: destination,
+			DestAsset:   destAsset,
+			DestMin:     p.DestinationAmount,
+			Path:        path,
+		})
+	}
+	return ops, nil
+}
+
+func pathAssets(p hProtocol.Path) (txnbuild.Asset, txnbuild.Asset, []txnbuild.Asset, error) {
+	sendAsset, err := assetFromHorizon(p.SourceAssetType, p.SourceAssetCode, p.SourceAssetIssuer)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid source asset")
+	}
+	destAsset, err := assetFromHorizon(p.DestinationAssetType, p.DestinationAssetCode, p.DestinationAssetIssuer)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid destination asset")
+	}
+	path := make([]txnbuild.Asset, len(p.Path))
+	for i, a := range p.Path {
+		path[i], err = assetFromHorizon(a.Type, a.Code, a.Issuer)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "invalid asset %d in path", i)
+		}
+	}
+	return sendAsset, destAsset, path, nil
+}
+
+func assetFromHorizon(assetType, code, issuer string) (txnbuild.Asset, error) {
+	switch assetType {
+	case "native":
+		return txnbuild.NativeAsset{}, nil
+	case "credit_alphanum4", "credit_alphanum12":
+		return txnbuild.CreditAsset{Code: code, Issuer: issuer}, nil
+	default:
+		return nil, errors.Errorf("unknown asset type %q", assetType)
+	}
+}
@@ -0,0 +1,165 @@
+package horizonclient
+
+import (
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPathsRequestBuildUrl(t *testing.T) {
+	endpoint, err := SplitPathsRequest{}.BuildURL()
+	require.NoError(t, err)
+	assert.Equal(t, "paths/strict-receive", endpoint)
+
+	endpoint, err = SplitPathsRequest{
+		PathsRequest: PathsRequest{
+			DestinationAmount:      "100",
+			DestinationAssetCode:   "NGN",
+			DestinationAssetIssuer: "GDZST3XVCDTUJ76ZAV2HA72KYQODXXZ5PTMAPZGDHZ6CS7RO7MGG3DBM",
+			DestinationAssetType:   AssetType4,
+			SourceAssets:           "native",
+		},
+		MaxSplits: 3,
+	}.BuildURL()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"paths/strict-receive?destination_amount=100&destination_asset_code=NGN&destination_asset_issuer=GDZST3XVCDTUJ76ZAV2HA72KYQODXXZ5PTMAPZGDHZ6CS7RO7MGG3DBM&destination_asset_type=credit_alphanum4&max_splits=3&source_assets=native",
+		endpoint,
+	)
+
+	endpoint, err = StrictSendSplitPathsRequest{
+		StrictSendPathsRequest: StrictSendPathsRequest{
+			DestinationAssets: "native",
+			SourceAssetCode:   "NGN",
+			SourceAssetIssuer: "GDZST3XVCDTUJ76ZAV2HA72KYQODXXZ5PTMAPZGDHZ6CS7RO7MGG3DBM",
+			SourceAssetType:   AssetType4,
+			SourceAmount:      "100",
+		},
+		MaxSplits: 2,
+	}.BuildURL()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"paths/strict-send?destination_assets=native&max_splits=2&source_amount=100&source_asset_code=NGN&source_asset_issuer=GDZST3XVCDTUJ76ZAV2HA72KYQODXXZ5PTMAPZGDHZ6CS7RO7MGG3DBM&source_asset_type=credit_alphanum4",
+		endpoint,
+	)
+}
+
+func TestStrictReceiveSplitPaths(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/paths/strict-receive?destination_amount=15&destination_asset_code=EUR&destination_asset_issuer=GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&destination_asset_type=credit_alphanum4&max_splits=2&source_assets=native",
+	).ReturnString(200, splitPathsResponse)
+
+	splits, err := client.StrictReceiveSplitPaths(SplitPathsRequest{
+		PathsRequest: PathsRequest{
+			DestinationAmount:      "15",
+			DestinationAssetCode:   "EUR",
+			DestinationAssetIssuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+			DestinationAssetType:   AssetType4,
+			SourceAssets:           "native",
+		},
+		MaxSplits: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, splits.Embedded.Records, 1)
+	split := splits.Embedded.Records[0]
+	assert.Equal(t, "25.0000000", split.SourceAmount)
+	assert.Len(t, split.Paths, 2)
+
+	eur := txnbuild.CreditAsset{Code: "EUR", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+	destination := "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"
+
+	ops, err := StrictReceiveSplitPathOperations(split, destination)
+	require.NoError(t, err)
+	assert.Equal(t, []txnbuild.Operation{
+		&txnbuild.PathPaymentStrictReceive{
+			SendAsset:   txnbuild.NativeAsset{},
+			SendMax:     "10.0000000",
+			Destination: destination,
+			DestAsset:   eur,
+			DestAmount:  "10.0000000",
+			Path:        []txnbuild.Asset{},
+		},
+		&txnbuild.PathPaymentStrictReceive{
+			SendAsset:   txnbuild.NativeAsset{},
+			SendMax:     "15.0000000",
+			Destination: destination,
+			DestAsset:   eur,
+			DestAmount:  "5.0000000",
+			Path:        []txnbuild.Asset{usd},
+		},
+	}, ops)
+
+	ops, err = StrictSendSplitPathOperations(split, destination)
+	require.NoError(t, err)
+	assert.Equal(t, &txnbuild.PathPaymentStrictSend{
+		SendAsset:   txnbuild.NativeAsset{},
+		SendAmount:  "15.0000000",
+		Destination: destination,
+		DestAsset:   eur,
+		DestMin:     "5.0000000",
+		Path:        []txnbuild.Asset{usd},
+	}, ops[1])
+
+	split.Paths[0].SourceAssetType = "liquidity_pool"
+	_, err = StrictReceiveSplitPathOperations(split, destination)
+	assert.EqualError(t, err, `invalid path 0: invalid source asset: unknown asset type "liquidity_pool"`)
+}
+
+var splitPathsResponse = `{
+  "_links": {
+    "self": {
+      "href": ""
+    }
+  },
+  "_embedded": {
+    "records": [
+      {
+        "source_asset_type": "native",
+        "source_amount": "25.0000000",
+        "destination_asset_type": "credit_alphanum4",
+        "destination_asset_code": "EUR",
+        "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "destination_amount": "15.0000000",
+        "paths": [
+          {
+            "source_asset_type": "native",
+            "source_amount": "10.0000000",
+            "destination_asset_type": "credit_alphanum4",
+            "destination_asset_code": "EUR",
+            "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+            "destination_amount": "10.0000000",
+            "path": []
+          },
+          {
+            "source_asset_type": "native",
+            "source_amount": "15.0000000",
+            "destination_asset_type": "credit_alphanum4",
+            "destination_asset_code": "EUR",
+            "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+            "destination_amount": "5.0000000",
+            "path": [
+              {
+                "asset_type": "credit_alphanum4",
+                "asset_code": "USD",
+                "asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"
+              }
+            ]
+          }
+        ]
+      }
+    ]
+  }
+}`
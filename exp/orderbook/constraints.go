@@ -0,0 +1,161 @@
+package orderbook
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// PathConstraints restricts the payment paths considered when searching the
+// order book graph. The zero value does not restrict payment paths.
+type PathConstraints struct {
+	// ExcludedAssets may not appear anywhere on a payment path
+	ExcludedAssets []xdr.Asset
+	// ExcludedIssuers are accounts whose assets may not appear anywhere on a
+	// payment path
+	ExcludedIssuers []xdr.AccountId
+	// IntermediateAssets, if not empty, are the only assets which may appear
+	// as interior nodes of a payment path
+	IntermediateAssets []xdr.Asset
+	// MaxPrice, if not nil, is the highest acceptable price of a payment path
+	// in units of the source asset per unit of the destination asset
+	MaxPrice *big.Rat
+	// MinDestinationAmount is the smallest acceptable destination amount of
+	// a payment path. It only applies when the source amount is fixed.
+	MinDestinationAmount xdr.Int64
+	// MaxHops limits the number of order books crossed by the payment paths
+	// of individual assets: the source assets of a strict receive search and
+	// the destination assets of a strict send search
+	MaxHops []AssetHops
+}
+
+// AssetHops is the maximum number of order books crossed by the payment paths
+// of an asset
+type AssetHops struct {
+	Asset xdr.Asset
+	Hops  int
+}
+
+// pathFilter is the form of PathConstraints evaluated during the DFS.
+// A nil pathFilter accepts every payment path.
+type pathFilter struct {
+	excludedAssets       map[string]bool
+	excludedIssuers      []string
+	intermediateAssets   map[string]bool
+	maxPrice             *big.Rat
+	minDestinationAmount xdr.Int64
+	maxHops              map[string]int
+}
+
+func (c PathConstraints) filter() *pathFilter {
+	if len(c.ExcludedAssets) == 0 &&
+		len(c.ExcludedIssuers) == 0 &&
+		len(c.IntermediateAssets) == 0 &&
+		c.MaxPrice == nil &&
+		c.MinDestinationAmount == 0 &&
+		len(c.MaxHops) == 0 {
+		return nil
+	}
+
+	filter := &pathFilter{
+		excludedAssets:       map[string]bool{},
+		maxPrice:             c.MaxPrice,
+		minDestinationAmount: c.MinDestinationAmount,
+	}
+	for _, asset := range c.ExcludedAssets {
+		filter.excludedAssets[asset.String()] = true
+	}
+	for _, issuer := range c.ExcludedIssuers {
+		// the string representation of credit assets ends with the issuer
+		filter.excludedIssuers = append(filter.excludedIssuers, "/"+issuer.Address())
+	}
+	if len(c.IntermediateAssets) > 0 {
+		filter.intermediateAssets = map[string]bool{}
+		for _, asset := range c.IntermediateAssets {
+			filter.intermediateAssets[asset.String()] = true
+		}
+	}
+	if len(c.MaxHops) > 0 {
+		filter.maxHops = map[string]int{}
+		for _, limit := range c.MaxHops {
+			filter.maxHops[limit.Asset.String()] = limit.Hops
+		}
+	}
+	return filter
+}
+
+// allowsAsset returns false if the asset may not appear on a payment path
+func (f *pathFilter) allowsAsset(asset string) bool {
+	if f == nil {
+		return true
+	}
+	if f.excludedAssets[asset] {
+		return false
+	}
+	for _, issuer := range f.excludedIssuers {
+		if strings.HasSuffix(asset, issuer) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsInteriorNode returns false if the asset may not be an interior node
+// of a payment path
+func (f *pathFilter) allowsInteriorNode(asset string) bool {
+	return f == nil || f.intermediateAssets == nil || f.intermediateAssets[asset]
+}
+
+// allowsPrice returns false if a payment path exchanging `sourceAmount`
+// for `destinationAmount` exceeds the maximum price, or if there is a
+// maximum price and the path delivers nothing
+func (f *pathFilter) allowsPrice(sourceAmount, destinationAmount xdr.Int64) bool {
+	if f == nil || f.maxPrice == nil {
+		return true
+	}
+	if destinationAmount <= 0 {
+		return false
+	}
+	price := big.NewRat(int64(sourceAmount), int64(destinationAmount))
+	return price.Cmp(f.maxPrice) <= 0
+}
+
+// allowsDestinationAmount returns false if a payment path delivering
+// `destinationAmount` delivers less than the minimum destination amount
+func (f *pathFilter) allowsDestinationAmount(destinationAmount xdr.Int64) bool {
+	return f == nil || destinationAmount >= f.minDestinationAmount
+}
+
+// allowsHops returns false if a payment path of the asset may not cross
+// `hops` order books
+func (f *pathFilter) allowsHops(asset string, hops int) bool {
+	if f == nil {
+		return true
+	}
+	maxHops, ok := f.maxHops[asset]
+	return !ok || hops <= maxHops
+}
+
+// maxPathLength returns the maximum path length of a search for payment paths
+// of the target assets, so that the search is not extended beyond the hops
+// allowed for every target asset
+func (f *pathFilter) maxPathLength(maxPathLength int, targetAssets []string) int {
+	if f == nil || len(f.maxHops) == 0 {
+		return maxPathLength
+	}
+	longest := 0
+	for _, asset := range targetAssets {
+		maxHops, ok := f.maxHops[asset]
+		if !ok {
+			return maxPathLength
+		}
+		if maxHops > longest {
+			longest = maxHops
+		}
+	}
+	if longest < maxPathLength {
+		return longest
+	}
+	return maxPathLength
+}
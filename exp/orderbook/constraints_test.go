@@ -0,0 +1,247 @@
+package orderbook
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// directPath is the path in splitTestGraph which sells 100 native for usd
+// directly, viaEurPath is the one which goes through eur
+var (
+	directPath = Path{
+		SourceAsset:       nativeAsset,
+		SourceAmount:      100,
+		InteriorNodes:     []xdr.Asset{},
+		DestinationAsset:  usdAsset,
+		DestinationAmount: 66,
+	}
+	viaEurPath = Path{
+		SourceAsset:       nativeAsset,
+		SourceAmount:      100,
+		InteriorNodes:     []xdr.Asset{eurAsset},
+		DestinationAsset:  usdAsset,
+		DestinationAmount: 50,
+	}
+)
+
+func findFixedPathsWithConstraints(t *testing.T, graph *OrderBookGraph, constraints PathConstraints) []Path {
+	paths, _, err := graph.FindFixedPaths(
+		3,
+		nativeAsset,
+		100,
+		[]xdr.Asset{usdAsset},
+		5,
+		constraints,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return paths
+}
+
+func TestExcludedAssets(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	paths := findFixedPathsWithConstraints(t, graph, PathConstraints{})
+	assertPathEquals(t, []Path{directPath, viaEurPath}, paths)
+
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		ExcludedAssets: []xdr.Asset{eurAsset},
+	})
+	assertPathEquals(t, []Path{directPath}, paths)
+
+	// excluding the source or destination asset excludes every path
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		ExcludedAssets: []xdr.Asset{nativeAsset},
+	})
+	assertPathEquals(t, []Path{}, paths)
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		ExcludedAssets: []xdr.Asset{usdAsset},
+	})
+	assertPathEquals(t, []Path{}, paths)
+
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		ExcludedIssuers: []xdr.AccountId{issuer},
+	})
+	assertPathEquals(t, []Path{}, paths)
+}
+
+func TestIntermediateAssets(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	paths := findFixedPathsWithConstraints(t, graph, PathConstraints{
+		IntermediateAssets: []xdr.Asset{chfAsset},
+	})
+	assertPathEquals(t, []Path{directPath}, paths)
+
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		IntermediateAssets: []xdr.Asset{eurAsset},
+	})
+	assertPathEquals(t, []Path{directPath, viaEurPath}, paths)
+
+	// eur is a destination asset but it may not be used as an interior node
+	paths, _, err := graph.FindFixedPaths(
+		3,
+		nativeAsset,
+		100,
+		[]xdr.Asset{usdAsset, eurAsset},
+		5,
+		PathConstraints{IntermediateAssets: []xdr.Asset{chfAsset}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertPathEquals(t, []Path{
+		{
+			SourceAsset:       nativeAsset,
+			SourceAmount:      100,
+			InteriorNodes:     []xdr.Asset{},
+			DestinationAsset:  eurAsset,
+			DestinationAmount: 100,
+		},
+		directPath,
+	}, paths)
+}
+
+func TestMinDestinationAmount(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	paths := findFixedPathsWithConstraints(t, graph, PathConstraints{
+		MinDestinationAmount: 60,
+	})
+	assertPathEquals(t, []Path{directPath}, paths)
+
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		MinDestinationAmount: 67,
+	})
+	assertPathEquals(t, []Path{}, paths)
+}
+
+func TestMaxPrice(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	// 100 native for 66 usd is a price of 50/33
+	paths := findFixedPathsWithConstraints(t, graph, PathConstraints{
+		MaxPrice: big.NewRat(50, 33),
+	})
+	assertPathEquals(t, []Path{directPath}, paths)
+
+	findPaths := func(maxPrice *big.Rat) []Path {
+		paths, _, err := graph.FindPaths(
+			3,
+			usdAsset,
+			50,
+			nil,
+			[]xdr.Asset{nativeAsset},
+			[]xdr.Int64{0},
+			false,
+			5,
+			PathConstraints{MaxPrice: maxPrice},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return paths
+	}
+
+	// 50 usd cost 50 native directly and 100 native through eur
+	assertPathEquals(t, []Path{
+		{
+			SourceAsset:       nativeAsset,
+			SourceAmount:      50,
+			InteriorNodes:     []xdr.Asset{},
+			DestinationAsset:  usdAsset,
+			DestinationAmount: 50,
+		},
+		{
+			SourceAsset:       nativeAsset,
+			SourceAmount:      100,
+			InteriorNodes:     []xdr.Asset{eurAsset},
+			DestinationAsset:  usdAsset,
+			DestinationAmount: 50,
+		},
+	}, findPaths(big.NewRat(2, 1)))
+	assertPathEquals(t, []Path{
+		{
+			SourceAsset:       nativeAsset,
+			SourceAmount:      50,
+			InteriorNodes:     []xdr.Asset{},
+			DestinationAsset:  usdAsset,
+			DestinationAmount: 50,
+		},
+	}, findPaths(big.NewRat(3, 2)))
+	assertPathEquals(t, []Path{}, findPaths(big.NewRat(1, 2)))
+
+	// A path delivering nothing has no price
+	filter := &pathFilter{maxPrice: big.NewRat(2, 1)}
+	if filter.allowsPrice(1, 0) {
+		t.Fatal("expected a path delivering nothing to be rejected")
+	}
+}
+
+func TestMaxHops(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	paths := findFixedPathsWithConstraints(t, graph, PathConstraints{
+		MaxHops: []AssetHops{{Asset: usdAsset, Hops: 1}},
+	})
+	assertPathEquals(t, []Path{directPath}, paths)
+
+	// limits of assets which are not searched for do not apply
+	paths = findFixedPathsWithConstraints(t, graph, PathConstraints{
+		MaxHops: []AssetHops{{Asset: eurAsset, Hops: 1}},
+	})
+	assertPathEquals(t, []Path{directPath, viaEurPath}, paths)
+
+	findPaths := func(maxHops int) []Path {
+		paths, _, err := graph.FindPaths(
+			3,
+			usdAsset,
+			50,
+			nil,
+			[]xdr.Asset{nativeAsset},
+			[]xdr.Int64{0},
+			false,
+			5,
+			PathConstraints{MaxHops: []AssetHops{{Asset: nativeAsset, Hops: maxHops}}},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return paths
+	}
+	if paths = findPaths(2); len(paths) != 2 {
+		t.Fatalf("expected 2 paths but got %v", paths)
+	}
+	paths = findPaths(1)
+	if len(paths) != 1 || len(paths[0].InteriorNodes) != 0 {
+		t.Fatalf("expected the direct path but got %v", paths)
+	}
+}
+
+func TestPathFilterMaxPathLength(t *testing.T) {
+	filter := PathConstraints{
+		MaxHops: []AssetHops{{Asset: usdAsset, Hops: 1}, {Asset: eurAsset, Hops: 2}},
+	}.filter()
+
+	for _, testCase := range []struct {
+		targetAssets []string
+		expected     int
+	}{
+		{[]string{usdAsset.String()}, 1},
+		{[]string{usdAsset.String(), eurAsset.String()}, 2},
+		// the search is not limited if one of the assets has no limit
+		{[]string{usdAsset.String(), nativeAsset.String()}, 3},
+	} {
+		if length := filter.maxPathLength(3, testCase.targetAssets); length != testCase.expected {
+			t.Fatalf("expected max path length %v but got %v", testCase.expected, length)
+		}
+	}
+
+	var none *pathFilter
+	if length := none.maxPathLength(3, []string{usdAsset.String()}); length != 3 {
+		t.Fatalf("expected max path length 3 but got %v", length)
+	}
+}
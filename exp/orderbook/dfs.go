@@ -55,6 +55,17 @@ type searchState interface {
 		currentAssetAmount xdr.Int64,
 		offers []xdr.OfferEntry,
 	) (xdr.Asset, xdr.Int64, error)
+
+	// allowsAsset returns false if the asset may not appear on a payment path
+	allowsAsset(asset string) bool
+
+	// allowsInteriorNode returns false if the asset may not be an
+	// interior node of a payment path
+	allowsInteriorNode(asset string) bool
+
+	// allowsHops returns false if a payment path ending at the asset may not
+	// cross `hops` order books
+	allowsHops(asset string, hops int) bool
 }
 
 func dfs(
@@ -75,22 +86,30 @@ func dfs(
 	if len(visitedList) > maxPathLength {
 		return nil
 	}
+	if !state.allowsAsset(currentAssetString) {
+		return nil
+	}
 	visited[currentAssetString] = true
 	defer func() {
 		visited[currentAssetString] = false
 	}()
 
 	updatedVisitedList := append(visitedList, currentAsset)
-	if state.isTerminalNode(currentAssetString, currentAssetAmount) {
+	if state.isTerminalNode(currentAssetString, currentAssetAmount) &&
+		state.allowsHops(currentAssetString, len(visitedList)) {
 		state.appendToPaths(
 			updatedVisitedList,
 			currentAssetString,
 			currentAssetAmount,
 		)
 	}
+	// continuing the search makes the current asset an interior node
+	if len(visitedList) > 0 && !state.allowsInteriorNode(currentAssetString) {
+		return nil
+	}
 
 	for nextAssetString, offers := range state.edges(currentAssetString) {
-		if len(offers) == 0 {
+		if len(offers) == 0 || !state.allowsAsset(nextAssetString) {
 			continue
 		}
 
@@ -126,6 +145,7 @@ func dfs(
 // no offers are consumed from the `ignoreOffersFrom` account
 // each payment path must begin with an asset in `targetAssets`
 // also, the required source asset amount cannot exceed the balance in `targetAssets`
+// and every payment path must satisfy the constraints of `pathFilter`
 type sellingGraphSearchState struct {
 	graph                  *OrderBookGraph
 	destinationAsset       xdr.Asset
@@ -134,6 +154,10 @@ type sellingGraphSearchState struct {
 	targetAssets           map[string]xdr.Int64
 	validateSourceBalance  bool
 	paths                  []Path
+	*pathFilter
+	// residual, if not nil, holds the amounts of offers already consumed by
+	// other parts of a split payment
+	residual residualOffers
 }
 
 func (state *sellingGraphSearchState) isTerminalNode(
@@ -141,7 +165,8 @@ func (state *sellingGraphSearchState) isTerminalNode(
 	currentAssetAmount xdr.Int64,
 ) bool {
 	targetAssetBalance, ok := state.targetAssets[currentAsset]
-	return ok && (!state.validateSourceBalance || targetAssetBalance >= currentAssetAmount)
+	return ok && (!state.validateSourceBalance || targetAssetBalance >= currentAssetAmount) &&
+		state.allowsPrice(currentAssetAmount, state.destinationAssetAmount)
 }
 
 func (state *sellingGraphSearchState) appendToPaths(
@@ -181,6 +206,9 @@ func (state *sellingGraphSearchState) consumeOffers(
 	offers []xdr.OfferEntry,
 ) (xdr.Asset, xdr.Int64, error) {
 	var nextAsset xdr.Asset
+	if offers = state.residual.apply(offers); len(offers) == 0 {
+		return nextAsset, 0, nil
+	}
 	nextAmount, err := consumeOffersForSellingAsset(offers, state.ignoreOffersFrom, currentAssetAmount)
 	if err == nil {
 		nextAsset = offers[0].Buying
@@ -196,19 +224,26 @@ func (state *sellingGraphSearchState) consumeOffers(
 // no offers are consumed from the `ignoreOffersFrom` account
 // each payment path must terminate with an asset in `targetAssets`
 // each payment path must begin with `sourceAsset`
+// every payment path must satisfy the constraints of `pathFilter`
 type buyingGraphSearchState struct {
 	graph             *OrderBookGraph
 	sourceAsset       xdr.Asset
 	sourceAssetAmount xdr.Int64
 	targetAssets      map[string]bool
 	paths             []Path
+	*pathFilter
+	// residual, if not nil, holds the amounts of offers already consumed by
+	// other parts of a split payment
+	residual residualOffers
 }
 
 func (state *buyingGraphSearchState) isTerminalNode(
 	currentAsset string,
 	currentAssetAmount xdr.Int64,
 ) bool {
-	return state.targetAssets[currentAsset]
+	return state.targetAssets[currentAsset] &&
+		state.allowsDestinationAmount(currentAssetAmount) &&
+		state.allowsPrice(state.sourceAssetAmount, currentAssetAmount)
 }
 
 func (state *buyingGraphSearchState) appendToPaths(
@@ -244,6 +279,9 @@ func (state *buyingGraphSearchState) consumeOffers(
 	offers []xdr.OfferEntry,
 ) (xdr.Asset, xdr.Int64, error) {
 	var nextAsset xdr.Asset
+	if offers = state.residual.apply(offers); len(offers) == 0 {
+		return nextAsset, 0, nil
+	}
 	nextAmount, err := consumeOffersForBuyingAsset(offers, currentAssetAmount)
 	if err == nil {
 		nextAsset = offers[0].Selling
@@ -256,6 +294,18 @@ func consumeOffersForSellingAsset(
 	offers []xdr.OfferEntry,
 	ignoreOffersFrom *xdr.AccountId,
 	currentAssetAmount xdr.Int64,
+) (xdr.Int64, error) {
+	return fillOffersForSellingAsset(offers, ignoreOffersFrom, currentAssetAmount, nil)
+}
+
+// fillOffersForSellingAsset is like consumeOffersForSellingAsset but also
+// calls fill, if not nil, with the amounts sold and bought by each offer which
+// is crossed.
+func fillOffersForSellingAsset(
+	offers []xdr.OfferEntry,
+	ignoreOffersFrom *xdr.AccountId,
+	currentAssetAmount xdr.Int64,
+	fill func(offer xdr.OfferEntry, sold, bought xdr.Int64),
 ) (xdr.Int64, error) {
 	totalConsumed := xdr.Int64(0)
 
@@ -286,6 +336,9 @@ func consumeOffersForSellingAsset(
 			return -1, err
 		}
 
+		if fill != nil && sellingUnitsFromOffer > 0 {
+			fill(offers[i], xdr.Int64(sellingUnitsFromOffer), xdr.Int64(buyingUnitsFromOffer))
+		}
 		totalConsumed += xdr.Int64(buyingUnitsFromOffer)
 		currentAssetAmount -= xdr.Int64(sellingUnitsFromOffer)
 
@@ -303,6 +356,17 @@ func consumeOffersForSellingAsset(
 func consumeOffersForBuyingAsset(
 	offers []xdr.OfferEntry,
 	currentAssetAmount xdr.Int64,
+) (xdr.Int64, error) {
+	return fillOffersForBuyingAsset(offers, currentAssetAmount, nil)
+}
+
+// fillOffersForBuyingAsset is like consumeOffersForBuyingAsset but also
+// calls fill, if not nil, with the amounts sold and bought by each offer which
+// is crossed.
+func fillOffersForBuyingAsset(
+	offers []xdr.OfferEntry,
+	currentAssetAmount xdr.Int64,
+	fill func(offer xdr.OfferEntry, sold, bought xdr.Int64),
 ) (xdr.Int64, error) {
 	totalConsumed := xdr.Int64(0)
 
@@ -331,6 +395,9 @@ func consumeOffersForBuyingAsset(
 				return -1, errSoldTooMuch
 			}
 			if amountSoldXDR <= offers[i].Amount {
+				if fill != nil {
+					fill(offers[i], amountSoldXDR, currentAssetAmount)
+				}
 				totalConsumed += amountSoldXDR
 				return totalConsumed, nil
 			}
@@ -352,6 +419,9 @@ func consumeOffersForBuyingAsset(
 			return -1, err
 		}
 
+		if fill != nil && sellingUnitsFromOffer > 0 {
+			fill(offers[i], xdr.Int64(sellingUnitsFromOffer), xdr.Int64(buyingUnitsFromOffer))
+		}
 		totalConsumed += xdr.Int64(sellingUnitsFromOffer)
 		currentAssetAmount -= xdr.Int64(buyingUnitsFromOffer)
 
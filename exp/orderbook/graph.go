@@ -295,6 +295,7 @@ func (graph *OrderBookGraph) IsEmpty() bool {
 
 // FindPaths returns a list of payment paths originating from a source account
 // and ending with a given destinaton asset and amount.
+// Only payment paths which satisfy `constraints` are considered.
 func (graph *OrderBookGraph) FindPaths(
 	maxPathLength int,
 	destinationAsset xdr.Asset,
@@ -304,13 +305,17 @@ func (graph *OrderBookGraph) FindPaths(
 	sourceAssetBalances []xdr.Int64,
 	validateSourceBalance bool,
 	maxAssetsPerPath int,
+	constraints PathConstraints,
 ) ([]Path, uint32, error) {
 	destinationAssetString := destinationAsset.String()
 	sourceAssetsMap := map[string]xdr.Int64{}
+	sourceAssetStrings := make([]string, 0, len(sourceAssets))
 	for i, sourceAsset := range sourceAssets {
 		sourceAssetString := sourceAsset.String()
 		sourceAssetsMap[sourceAssetString] = sourceAssetBalances[i]
+		sourceAssetStrings = append(sourceAssetStrings, sourceAssetString)
 	}
+	filter := constraints.filter()
 
 	searchState := &sellingGraphSearchState{
 		graph:                  graph,
@@ -320,11 +325,12 @@ func (graph *OrderBookGraph) FindPaths(
 		targetAssets:           sourceAssetsMap,
 		validateSourceBalance:  validateSourceBalance,
 		paths:                  []Path{},
+		pathFilter:             filter,
 	}
 	graph.lock.RLock()
 	err := dfs(
 		searchState,
-		maxPathLength,
+		filter.maxPathLength(maxPathLength, sourceAssetStrings),
 		map[string]bool{},
 		[]xdr.Asset{},
 		destinationAssetString,
@@ -349,19 +355,24 @@ func (graph *OrderBookGraph) FindPaths(
 // assets are fixed. All returned payment paths will start by spending `amountToSpend`
 // of `sourceAsset` and will end with some positive balance of `destinationAsset`.
 // `sourceAccountID` is optional. if `sourceAccountID` is provided then no offers
-// created by `sourceAccountID` will be considered when evaluating payment paths
+// created by `sourceAccountID` will be considered when evaluating payment paths.
+// Only payment paths which satisfy `constraints` are considered.
 func (graph *OrderBookGraph) FindFixedPaths(
 	maxPathLength int,
 	sourceAsset xdr.Asset,
 	amountToSpend xdr.Int64,
 	destinationAssets []xdr.Asset,
 	maxAssetsPerPath int,
+	constraints PathConstraints,
 ) ([]Path, uint32, error) {
 	target := map[string]bool{}
+	destinationAssetStrings := make([]string, 0, len(destinationAssets))
 	for _, destinationAsset := range destinationAssets {
 		destinationAssetString := destinationAsset.String()
 		target[destinationAssetString] = true
+		destinationAssetStrings = append(destinationAssetStrings, destinationAssetString)
 	}
+	filter := constraints.filter()
 
 	searchState := &buyingGraphSearchState{
 		graph:             graph,
@@ -369,11 +380,12 @@ func (graph *OrderBookGraph) FindFixedPaths(
 		sourceAssetAmount: amountToSpend,
 		targetAssets:      target,
 		paths:             []Path{},
+		pathFilter:        filter,
 	}
 	graph.lock.RLock()
 	err := dfs(
 		searchState,
-		maxPathLength,
+		filter.maxPathLength(maxPathLength, destinationAssetStrings),
 		map[string]bool{},
 		[]xdr.Asset{},
 		sourceAsset.String(),
@@ -1537,6 +1537,7 @@ func TestFindPaths(t *testing.T) {
 		},
 		true,
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1561,6 +1562,7 @@ func TestFindPaths(t *testing.T) {
 		},
 		true,
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1615,6 +1617,7 @@ func TestFindPaths(t *testing.T) {
 		},
 		false,
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1639,6 +1642,7 @@ func TestFindPaths(t *testing.T) {
 		},
 		true,
 		5,
+		PathConstraints{},
 	)
 	if lastLedger != 2 {
 		t.Fatalf("expected last ledger to be %v but got %v", 2, lastLedger)
@@ -1705,6 +1709,7 @@ func TestFindPaths(t *testing.T) {
 		},
 		true,
 		5,
+		PathConstraints{},
 	)
 	if lastLedger != 2 {
 		t.Fatalf("expected last ledger to be %v but got %v", 2, lastLedger)
@@ -1847,6 +1852,7 @@ func TestFindPathsStartingAt(t *testing.T) {
 		5,
 		[]xdr.Asset{nativeAsset},
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1882,6 +1888,7 @@ func TestFindPathsStartingAt(t *testing.T) {
 		5,
 		[]xdr.Asset{nativeAsset},
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1900,6 +1907,7 @@ func TestFindPathsStartingAt(t *testing.T) {
 		5,
 		[]xdr.Asset{nativeAsset},
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1929,6 +1937,7 @@ func TestFindPathsStartingAt(t *testing.T) {
 		5,
 		[]xdr.Asset{nativeAsset},
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
@@ -1969,6 +1978,7 @@ func TestFindPathsStartingAt(t *testing.T) {
 		5,
 		[]xdr.Asset{nativeAsset, usdAsset},
 		5,
+		PathConstraints{},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
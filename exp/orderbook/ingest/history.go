@@ -0,0 +1,183 @@
+package ingest
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// ReplayTo rebuilds the graph as it was at the end of the given ledger. The
+// graph is loaded from the latest history archive checkpoint at or before the
+// ledger and the offer changes of the subsequent ledgers, up to and including
+// the given ledger, are applied on top of it.
+func (i *Ingester) ReplayTo(ctx context.Context, ledger uint32) error {
+	i.init()
+
+	if ledger < historyarchive.CheckpointFreq-1 {
+		return errors.Errorf("ledger %d precedes the first checkpoint", ledger)
+	}
+	checkpoint := historyarchive.PrevCheckpoint(ledger)
+
+	i.Log.WithField("ledger", checkpoint).Info("Loading order book from checkpoint")
+	if err := i.loadCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrapf(err, "could not load checkpoint %d", checkpoint)
+	}
+	if ledger == checkpoint {
+		return nil
+	}
+
+	ledgerRange := ledgerbackend.BoundedRange(checkpoint+1, ledger)
+	prepared, err := i.LedgerBackend.IsPrepared(ledgerRange)
+	if err != nil {
+		return errors.Wrap(err, "error checking prepared range")
+	}
+	if !prepared {
+		if err = i.LedgerBackend.PrepareRange(ledgerRange); err != nil {
+			return errors.Wrap(err, "error preparing range")
+		}
+	}
+
+	i.Log.WithField("from", checkpoint+1).WithField("to", ledger).Info("Replaying ledgers")
+	for sequence := checkpoint + 1; sequence <= ledger; sequence++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = i.ingestLedger(sequence)
+		if err == io.ErrNotFound {
+			return errors.Errorf("ledger %d is not available", sequence)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not ingest ledger %d", sequence)
+		}
+	}
+	return nil
+}
+
+// Offer is an offer of an order book.
+type Offer struct {
+	ID     int64         `json:"id"`
+	Seller string        `json:"seller"`
+	Amount string        `json:"amount"`
+	PriceR horizon.Price `json:"price_r"`
+	Price  string        `json:"price"`
+}
+
+// OrderBook lists the offers of a trading pair. Asks sell `Selling` for
+// `Buying` and bids sell `Buying` for `Selling`.
+type OrderBook struct {
+	Bids    []Offer       `json:"bids"`
+	Asks    []Offer       `json:"asks"`
+	Selling horizon.Asset `json:"base"`
+	Buying  horizon.Asset `json:"counter"`
+}
+
+func toHorizonAsset(asset xdr.Asset) (horizon.Asset, error) {
+	var result horizon.Asset
+	err := asset.Extract(&result.Type, &result.Code, &result.Issuer)
+	return result, err
+}
+
+func toOffers(entries []xdr.OfferEntry) []Offer {
+	offers := make([]Offer, len(entries))
+	for i, entry := range entries {
+		offers[i] = Offer{
+			ID:     int64(entry.OfferId),
+			Seller: entry.SellerId.Address(),
+			Amount: amount.String(entry.Amount),
+			PriceR: horizon.Price{N: int32(entry.Price.N), D: int32(entry.Price.D)},
+			Price:  entry.Price.String(),
+		}
+	}
+	return offers
+}
+
+// FindOrderBook returns the offers of the graph which trade `selling` for
+// `buying`, in either direction, spanning at most `maxPriceLevels` price
+// levels on each side.
+func FindOrderBook(
+	graph *orderbook.OrderBookGraph,
+	selling, buying xdr.Asset,
+	maxPriceLevels int,
+) (OrderBook, error) {
+	var result OrderBook
+	var err error
+	if result.Selling, err = toHorizonAsset(selling); err != nil {
+		return result, errors.Wrap(err, "invalid selling asset")
+	}
+	if result.Buying, err = toHorizonAsset(buying); err != nil {
+		return result, errors.Wrap(err, "invalid buying asset")
+	}
+
+	asks, bids, _ := graph.FindAsksAndBids(selling, buying, maxPriceLevels)
+	result.Asks = toOffers(asks)
+	result.Bids = toOffers(bids)
+	return result, nil
+}
+
+// toPriceLevels aggregates offers, which are sorted by price, into price
+// levels. The prices of bids are inverted so that they are expressed in
+// terms of the same asset as the prices of asks.
+func toPriceLevels(offers []xdr.OfferEntry, invert bool) []horizon.PriceLevel {
+	levels := []horizon.PriceLevel{}
+	for start := 0; start < len(offers); {
+		end := start
+		total := new(big.Int)
+		for ; end < len(offers) && offers[end].Price.Equal(offers[start].Price); end++ {
+			total.Add(total, big.NewInt(int64(offers[end].Amount)))
+		}
+
+		price := big.NewRat(int64(offers[start].Price.N), int64(offers[start].Price.D))
+		if invert {
+			price.Inv(price)
+		}
+		levelAmount, err := amount.IntStringToAmount(total.String())
+		if err != nil {
+			// total is the string representation of an integer so it
+			// is always parsed successfully
+			panic(err)
+		}
+		levels = append(levels, horizon.PriceLevel{
+			PriceR: horizon.Price{
+				N: int32(price.Num().Int64()),
+				D: int32(price.Denom().Int64()),
+			},
+			Price:  price.FloatString(7),
+			Amount: levelAmount,
+		})
+		start = end
+	}
+	return levels
+}
+
+// FindOrderBookSummary aggregates the offers of the graph which trade
+// `selling` for `buying` into at most `maxPriceLevels` price levels on each
+// side. The summary has the same format as the response of Horizon's
+// /order_book endpoint.
+func FindOrderBookSummary(
+	graph *orderbook.OrderBookGraph,
+	selling, buying xdr.Asset,
+	maxPriceLevels int,
+) (horizon.OrderBookSummary, error) {
+	var result horizon.OrderBookSummary
+	var err error
+	if result.Selling, err = toHorizonAsset(selling); err != nil {
+		return result, errors.Wrap(err, "invalid selling asset")
+	}
+	if result.Buying, err = toHorizonAsset(buying); err != nil {
+		return result, errors.Wrap(err, "invalid buying asset")
+	}
+
+	asks, bids, _ := graph.FindAsksAndBids(selling, buying, maxPriceLevels)
+	result.Asks = toPriceLevels(asks, false)
+	result.Bids = toPriceLevels(bids, true)
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/xdr"
+)
+
+func TestIngesterReplayTo(t *testing.T) {
+	ingester, archive, backend := newTestIngester()
+
+	backend.On("IsPrepared", ledgerbackend.BoundedRange(128, 129)).Return(false, nil).Once()
+	backend.On("PrepareRange", ledgerbackend.BoundedRange(128, 129)).Return(nil).Once()
+
+	stateReader := changeReader(
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(1, 100)},
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(2, 200)},
+	)
+	ledgerReaders := map[uint32]*io.MockChangeReader{
+		128: changeReader(
+			io.Change{Type: xdr.LedgerEntryTypeOffer, Pre: offerEntry(1, 100), Post: offerEntry(1, 50)},
+		),
+		129: changeReader(
+			io.Change{Type: xdr.LedgerEntryTypeOffer, Pre: offerEntry(2, 200)},
+		),
+	}
+	ingester.NewStateReader = func(_ context.Context, _ historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error) {
+		assert.Equal(t, uint32(127), sequence)
+		return stateReader, nil
+	}
+	ingester.NewLedgerReader = func(_ ledgerbackend.LedgerBackend, _ string, sequence uint32) (io.ChangeReader, error) {
+		reader, ok := ledgerReaders[sequence]
+		if !ok {
+			t.Fatalf("unexpected ledger %d", sequence)
+		}
+		return reader, nil
+	}
+
+	assert.NoError(t, ingester.ReplayTo(context.Background(), 129))
+
+	offers := ingester.Graph.OffersMap()
+	assert.Len(t, offers, 1)
+	assert.Equal(t, xdr.Int64(50), offers[1].Amount)
+	_, _, lastLedger := ingester.Graph.FindAsksAndBids(usdAsset, nativeAsset, 1)
+	assert.Equal(t, uint32(129), lastLedger)
+
+	archive.AssertExpectations(t)
+	backend.AssertExpectations(t)
+	stateReader.AssertExpectations(t)
+	for _, reader := range ledgerReaders {
+		reader.AssertExpectations(t)
+	}
+}
+
+func TestIngesterReplayToCheckpoint(t *testing.T) {
+	ingester, _, backend := newTestIngester()
+
+	ingester.NewStateReader = func(_ context.Context, _ historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error) {
+		assert.Equal(t, uint32(127), sequence)
+		return changeReader(io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(1, 100)}), nil
+	}
+
+	assert.NoError(t, ingester.ReplayTo(context.Background(), 127))
+	assert.Len(t, ingester.Graph.Offers(), 1)
+	backend.AssertExpectations(t)
+
+	err := ingester.ReplayTo(context.Background(), 62)
+	assert.EqualError(t, err, "ledger 62 precedes the first checkpoint")
+}
+
+func TestIngesterReplayToUnavailableLedger(t *testing.T) {
+	ingester, _, backend := newTestIngester()
+
+	backend.On("IsPrepared", ledgerbackend.BoundedRange(64, 70)).Return(true, nil).Once()
+	ingester.NewStateReader = func(context.Context, historyarchive.ArchiveInterface, uint32) (io.ChangeReader, error) {
+		return changeReader(), nil
+	}
+	ingester.NewLedgerReader = func(ledgerbackend.LedgerBackend, string, uint32) (io.ChangeReader, error) {
+		return nil, io.ErrNotFound
+	}
+
+	err := ingester.ReplayTo(context.Background(), 70)
+	assert.EqualError(t, err, "ledger 64 is not available")
+	backend.AssertExpectations(t)
+}
+
+func TestFindOrderBookSummary(t *testing.T) {
+	graph := orderbook.NewOrderBookGraph()
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  1,
+		Selling:  usdAsset,
+		Buying:   nativeAsset,
+		Price:    xdr.Price{N: 2, D: 1},
+		Amount:   100000000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  2,
+		Selling:  usdAsset,
+		Buying:   nativeAsset,
+		Price:    xdr.Price{N: 4, D: 2},
+		Amount:   50000000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  3,
+		Selling:  usdAsset,
+		Buying:   nativeAsset,
+		Price:    xdr.Price{N: 3, D: 1},
+		Amount:   10000000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  4,
+		Selling:  nativeAsset,
+		Buying:   usdAsset,
+		Price:    xdr.Price{N: 1, D: 4},
+		Amount:   200000000,
+	})
+	assert.NoError(t, graph.Apply(10))
+
+	summary, err := FindOrderBookSummary(graph, usdAsset, nativeAsset, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, horizon.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()}, summary.Selling)
+	assert.Equal(t, horizon.Asset{Type: "native"}, summary.Buying)
+	assert.Equal(t, []horizon.PriceLevel{
+		{PriceR: horizon.Price{N: 2, D: 1}, Price: "2.0000000", Amount: "15.0000000"},
+		{PriceR: horizon.Price{N: 3, D: 1}, Price: "3.0000000", Amount: "1.0000000"},
+	}, summary.Asks)
+	assert.Equal(t, []horizon.PriceLevel{
+		{PriceR: horizon.Price{N: 4, D: 1}, Price: "4.0000000", Amount: "20.0000000"},
+	}, summary.Bids)
+
+	summary, err = FindOrderBookSummary(graph, usdAsset, nativeAsset, 1)
+	assert.NoError(t, err)
+	assert.Len(t, summary.Asks, 1)
+
+	orderBook, err := FindOrderBook(graph, usdAsset, nativeAsset, 20)
+	assert.NoError(t, err)
+	assert.Len(t, orderBook.Asks, 3)
+	assert.Equal(t, Offer{
+		ID:     4,
+		Seller: issuer.Address(),
+		Amount: "20.0000000",
+		PriceR: horizon.Price{N: 1, D: 4},
+		Price:  "0.2500000",
+	}, orderBook.Bids[0])
+}
@@ -0,0 +1,185 @@
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// Ingester keeps an order book graph in sync with the Stellar network. It
+// populates the graph from the latest history archive checkpoint and then
+// applies the offer changes of every subsequent ledger obtained from the
+// ledger backend.
+type Ingester struct {
+	Graph             *orderbook.OrderBookGraph
+	LedgerBackend     ledgerbackend.LedgerBackend
+	HistoryArchive    historyarchive.ArchiveInterface
+	NetworkPassphrase string
+	Log               *supportlog.Entry
+	// PollInterval is how long to wait before polling the ledger backend
+	// again when the next ledger is not available yet
+	PollInterval time.Duration
+	// RetryInterval is how long to wait before rebuilding the graph after
+	// an error
+	RetryInterval time.Duration
+	// NewStateReader and NewLedgerReader are optional. They default to
+	// io.MakeSingleLedgerStateReader and io.NewLedgerChangeReader.
+	NewStateReader  func(ctx context.Context, archive historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error)
+	NewLedgerReader func(backend ledgerbackend.LedgerBackend, networkPassphrase string, sequence uint32) (io.ChangeReader, error)
+}
+
+func makeSingleLedgerStateReader(
+	ctx context.Context,
+	archive historyarchive.ArchiveInterface,
+	sequence uint32,
+) (io.ChangeReader, error) {
+	return io.MakeSingleLedgerStateReader(ctx, archive, sequence)
+}
+
+func newLedgerChangeReader(
+	backend ledgerbackend.LedgerBackend,
+	networkPassphrase string,
+	sequence uint32,
+) (io.ChangeReader, error) {
+	return io.NewLedgerChangeReader(backend, networkPassphrase, sequence)
+}
+
+// Run ingests ledgers until ctx is cancelled. Whenever ingestion fails the
+// graph is cleared, so that queries fail instead of returning stale paths,
+// and rebuilt from the latest checkpoint after RetryInterval.
+func (i *Ingester) Run(ctx context.Context) {
+	i.init()
+
+	for {
+		err := i.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		i.Log.WithError(err).Error("could not ingest order book")
+		i.Graph.Clear()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(i.RetryInterval):
+		}
+	}
+}
+
+func (i *Ingester) init() {
+	if i.NewStateReader == nil {
+		i.NewStateReader = makeSingleLedgerStateReader
+	}
+	if i.NewLedgerReader == nil {
+		i.NewLedgerReader = newLedgerChangeReader
+	}
+}
+
+func (i *Ingester) run(ctx context.Context) error {
+	has, err := i.HistoryArchive.GetRootHAS()
+	if err != nil {
+		return errors.Wrap(err, "could not get root HAS")
+	}
+	checkpoint := has.CurrentLedger
+
+	i.Log.WithField("ledger", checkpoint).Info("Loading order book from checkpoint")
+	if err = i.loadCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrapf(err, "could not load checkpoint %d", checkpoint)
+	}
+	i.Log.WithField("ledger", checkpoint).Info("Loaded order book from checkpoint")
+
+	ledgerRange := ledgerbackend.UnboundedRange(checkpoint)
+	prepared, err := i.LedgerBackend.IsPrepared(ledgerRange)
+	if err != nil {
+		return errors.Wrap(err, "error checking prepared range")
+	}
+	if !prepared {
+		if err = i.LedgerBackend.PrepareRange(ledgerRange); err != nil {
+			return errors.Wrap(err, "error preparing range")
+		}
+	}
+
+	for sequence := checkpoint + 1; ; {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = i.ingestLedger(sequence)
+		if err == io.ErrNotFound {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(i.PollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not ingest ledger %d", sequence)
+		}
+		sequence++
+	}
+}
+
+// loadCheckpoint replaces the offers of the graph with the offers present
+// in the ledger state at the given checkpoint.
+func (i *Ingester) loadCheckpoint(ctx context.Context, checkpoint uint32) error {
+	reader, err := i.NewStateReader(ctx, i.HistoryArchive, checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "could not create state reader")
+	}
+	defer reader.Close()
+
+	i.Graph.Clear()
+	defer i.Graph.Discard()
+	if err = applyChanges(i.Graph, reader); err != nil {
+		return err
+	}
+	return i.Graph.Apply(checkpoint)
+}
+
+// ingestLedger applies the offer changes of the given ledger to the graph.
+// io.ErrNotFound is returned if the ledger is not available yet.
+func (i *Ingester) ingestLedger(sequence uint32) error {
+	reader, err := i.NewLedgerReader(i.LedgerBackend, i.NetworkPassphrase, sequence)
+	if err == io.ErrNotFound {
+		return err
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not create ledger reader")
+	}
+	defer reader.Close()
+
+	defer i.Graph.Discard()
+	if err = applyChanges(i.Graph, reader); err != nil {
+		return err
+	}
+	return i.Graph.Apply(sequence)
+}
+
+func applyChanges(graph *orderbook.OrderBookGraph, reader io.ChangeReader) error {
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not read change")
+		}
+		if change.Type != xdr.LedgerEntryTypeOffer {
+			continue
+		}
+
+		if change.Post == nil {
+			graph.RemoveOffer(change.Pre.Data.MustOffer().OfferId)
+		} else {
+			graph.AddOffer(change.Post.Data.MustOffer())
+		}
+	}
+}
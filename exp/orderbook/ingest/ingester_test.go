@@ -0,0 +1,141 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	issuer      = xdr.MustAddress(keypair.MustRandom().Address())
+	nativeAsset = xdr.MustNewNativeAsset()
+	usdAsset    = xdr.MustNewCreditAsset("USD", issuer.Address())
+)
+
+func offerEntry(id int64, amount int64) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeOffer,
+			Offer: &xdr.OfferEntry{
+				SellerId: issuer,
+				OfferId:  xdr.Int64(id),
+				Selling:  usdAsset,
+				Buying:   nativeAsset,
+				Price:    xdr.Price{N: 1, D: 1},
+				Amount:   xdr.Int64(amount),
+			},
+		},
+	}
+}
+
+func changeReader(changes ...io.Change) *io.MockChangeReader {
+	reader := &io.MockChangeReader{}
+	for _, change := range changes {
+		reader.On("Read").Return(change, nil).Once()
+	}
+	reader.On("Read").Return(io.Change{}, io.EOF).Once()
+	reader.On("Close").Return(nil).Once()
+	return reader
+}
+
+func newTestIngester() (*Ingester, *historyarchive.MockArchive, *ledgerbackend.MockDatabaseBackend) {
+	archive := &historyarchive.MockArchive{}
+	backend := &ledgerbackend.MockDatabaseBackend{}
+	return &Ingester{
+		Graph:             orderbook.NewOrderBookGraph(),
+		LedgerBackend:     backend,
+		HistoryArchive:    archive,
+		NetworkPassphrase: "passphrase",
+		Log:               log.New(),
+	}, archive, backend
+}
+
+func TestIngesterRun(t *testing.T) {
+	ingester, archive, backend := newTestIngester()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 63}, nil).Once()
+	backend.On("IsPrepared", ledgerbackend.UnboundedRange(63)).Return(false, nil).Once()
+	backend.On("PrepareRange", ledgerbackend.UnboundedRange(63)).Return(nil).Once()
+
+	stateReader := changeReader(
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(1, 100)},
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(2, 200)},
+		io.Change{Type: xdr.LedgerEntryTypeAccount, Post: &xdr.LedgerEntry{}},
+	)
+	ledgerReader := changeReader(
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Pre: offerEntry(1, 100), Post: offerEntry(1, 50)},
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Pre: offerEntry(2, 200)},
+		io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(3, 300)},
+	)
+	ingester.NewStateReader = func(_ context.Context, _ historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error) {
+		assert.Equal(t, uint32(63), sequence)
+		return stateReader, nil
+	}
+	ingester.NewLedgerReader = func(_ ledgerbackend.LedgerBackend, passphrase string, sequence uint32) (io.ChangeReader, error) {
+		assert.Equal(t, "passphrase", passphrase)
+		switch sequence {
+		case 64:
+			return ledgerReader, nil
+		case 65:
+			cancel()
+			return nil, io.ErrNotFound
+		}
+		t.Fatalf("unexpected ledger %d", sequence)
+		return nil, nil
+	}
+
+	ingester.Run(ctx)
+
+	offers := ingester.Graph.OffersMap()
+	assert.Len(t, offers, 2)
+	assert.Equal(t, xdr.Int64(50), offers[1].Amount)
+	assert.Equal(t, xdr.Int64(300), offers[3].Amount)
+	_, lastLedger, err := ingester.Graph.FindFixedPaths(1, nativeAsset, 10, []xdr.Asset{usdAsset}, 5, orderbook.PathConstraints{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(64), lastLedger)
+
+	archive.AssertExpectations(t)
+	backend.AssertExpectations(t)
+	stateReader.AssertExpectations(t)
+	ledgerReader.AssertExpectations(t)
+}
+
+func TestIngesterClearsGraphOnError(t *testing.T) {
+	ingester, archive, backend := newTestIngester()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 63}, nil).Once()
+	archive.On("GetRootHAS").
+		Return(historyarchive.HistoryArchiveState{}, errors.New("archive unavailable")).
+		Run(func(mock.Arguments) { cancel() }).
+		Once()
+	backend.On("IsPrepared", ledgerbackend.UnboundedRange(63)).Return(true, nil).Once()
+
+	ingester.NewStateReader = func(context.Context, historyarchive.ArchiveInterface, uint32) (io.ChangeReader, error) {
+		return changeReader(io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerEntry(1, 100)}), nil
+	}
+	ingester.NewLedgerReader = func(ledgerbackend.LedgerBackend, string, uint32) (io.ChangeReader, error) {
+		assert.False(t, ingester.Graph.IsEmpty())
+		return nil, errors.New("transient error")
+	}
+
+	ingester.Run(ctx)
+
+	assert.True(t, ingester.Graph.IsEmpty())
+	archive.AssertExpectations(t)
+	backend.AssertExpectations(t)
+}
@@ -0,0 +1,167 @@
+package orderbook
+
+import (
+	"math/big"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// QuotedOffer is an offer crossed by a quote along with the amounts it trades.
+type QuotedOffer struct {
+	Offer xdr.OfferEntry
+	// Sold is the amount of the offer's selling asset which is sold
+	Sold xdr.Int64
+	// Bought is the amount of the offer's buying asset which is bought
+	Bought xdr.Int64
+}
+
+// Quote describes the outcome of selling `SourceAmount` of `SourceAsset` for
+// `DestinationAsset` through the best payment path in the order book.
+// All prices are expressed in units of the destination asset per unit of the
+// source asset.
+type Quote struct {
+	SourceAsset       xdr.Asset
+	SourceAmount      xdr.Int64
+	DestinationAsset  xdr.Asset
+	DestinationAmount xdr.Int64
+	InteriorNodes     []xdr.Asset
+
+	// BestPrice is the price obtained from the first offer of every hop
+	BestPrice *big.Rat
+	// AveragePrice is DestinationAmount / SourceAmount
+	AveragePrice *big.Rat
+	// WorstPrice is the price obtained from the last offer crossed on every hop
+	WorstPrice *big.Rat
+	// MidPrice is the product of the mid prices, halfway between the best bid
+	// and the best ask, of every hop. It is nil when a hop has no asks.
+	MidPrice *big.Rat
+	// PriceImpact is (MidPrice - AveragePrice) / MidPrice, nil when
+	// MidPrice is nil
+	PriceImpact *big.Rat
+	// Slippage is (BestPrice - AveragePrice) / BestPrice
+	Slippage *big.Rat
+
+	// Offers lists the offers crossed, in the order they are crossed
+	Offers []QuotedOffer
+}
+
+// Quote returns the quote for selling `amountToSpend` of `sourceAsset` for
+// `destinationAsset` through the payment path, of at most `maxPathLength`
+// hops, which delivers the largest amount of `destinationAsset`. The quote
+// is nil when `destinationAsset` cannot be reached. The quote is consistent
+// with the returned ledger sequence.
+func (graph *OrderBookGraph) Quote(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+) (*Quote, uint32, error) {
+	graph.lock.RLock()
+	defer graph.lock.RUnlock()
+
+	if sourceAsset.Equals(destinationAsset) {
+		return nil, graph.lastLedger, errors.New("source and destination assets are equal")
+	}
+
+	searchState := &buyingGraphSearchState{
+		graph:             graph,
+		sourceAsset:       sourceAsset,
+		sourceAssetAmount: amountToSpend,
+		targetAssets:      map[string]bool{destinationAsset.String(): true},
+		paths:             []Path{},
+	}
+	err := dfs(
+		searchState,
+		maxPathLength,
+		map[string]bool{},
+		[]xdr.Asset{},
+		sourceAsset.String(),
+		sourceAsset,
+		amountToSpend,
+	)
+	if err != nil {
+		return nil, graph.lastLedger, errors.Wrap(err, "could not determine paths")
+	}
+	if len(searchState.paths) == 0 {
+		return nil, graph.lastLedger, nil
+	}
+
+	best := 0
+	for i := range searchState.paths {
+		if compareDestinationAsset(searchState.paths, i, best) {
+			best = i
+		}
+	}
+	path := searchState.paths[best]
+
+	quote, err := graph.quotePath(pathAssets(path), amountToSpend)
+	if err != nil {
+		return nil, graph.lastLedger, errors.Wrap(err, "could not quote path")
+	}
+	quote.InteriorNodes = path.InteriorNodes
+	return quote, graph.lastLedger, nil
+}
+
+// quotePath sells `amountToSpend` along assets, which go from the source
+// asset to the destination asset, and records the offers crossed.
+func (graph *OrderBookGraph) quotePath(assets []xdr.Asset, amountToSpend xdr.Int64) (*Quote, error) {
+	quote := &Quote{
+		SourceAsset:      assets[0],
+		SourceAmount:     amountToSpend,
+		DestinationAsset: assets[len(assets)-1],
+		BestPrice:        big.NewRat(1, 1),
+		WorstPrice:       big.NewRat(1, 1),
+		MidPrice:         big.NewRat(1, 1),
+	}
+
+	amount := amountToSpend
+	for i := 0; i < len(assets)-1; i++ {
+		// offers buying the asset we sell, their price is in units of
+		// the asset we sell per unit of the asset we receive
+		bids := graph.edgesForBuyingAsset[assets[i].String()][assets[i+1].String()]
+		var last xdr.OfferEntry
+		received, err := fillOffersForBuyingAsset(bids, amount, func(offer xdr.OfferEntry, sold, bought xdr.Int64) {
+			quote.Offers = append(quote.Offers, QuotedOffer{Offer: offer, Sold: sold, Bought: bought})
+			last = offer
+		})
+		if err != nil {
+			return nil, err
+		}
+		if received <= 0 {
+			return nil, errNoLiquidity
+		}
+
+		bestBid := big.NewRat(int64(bids[0].Price.D), int64(bids[0].Price.N))
+		quote.BestPrice.Mul(quote.BestPrice, bestBid)
+		quote.WorstPrice.Mul(quote.WorstPrice, big.NewRat(int64(last.Price.D), int64(last.Price.N)))
+
+		// offers selling the asset we sell, their price is in units of
+		// the asset we receive per unit of the asset we sell
+		asks := graph.edgesForSellingAsset[assets[i].String()][assets[i+1].String()]
+		if len(asks) == 0 || quote.MidPrice == nil {
+			quote.MidPrice = nil
+		} else {
+			mid := big.NewRat(int64(asks[0].Price.N), int64(asks[0].Price.D))
+			mid.Add(mid, bestBid)
+			mid.Quo(mid, big.NewRat(2, 1))
+			quote.MidPrice.Mul(quote.MidPrice, mid)
+		}
+
+		amount = received
+	}
+
+	quote.DestinationAmount = amount
+	quote.AveragePrice = big.NewRat(int64(quote.DestinationAmount), int64(quote.SourceAmount))
+	quote.Slippage = relativeDifference(quote.BestPrice, quote.AveragePrice)
+	if quote.MidPrice != nil {
+		quote.PriceImpact = relativeDifference(quote.MidPrice, quote.AveragePrice)
+	}
+	return quote, nil
+}
+
+// relativeDifference returns (reference - value) / reference
+func relativeDifference(reference, value *big.Rat) *big.Rat {
+	difference := new(big.Rat).Sub(reference, value)
+	return difference.Quo(difference, reference)
+}
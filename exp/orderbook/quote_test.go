@@ -0,0 +1,82 @@
+package orderbook
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+func assertRatEquals(t *testing.T, expected string, actual *big.Rat) {
+	if actual == nil {
+		t.Fatalf("expected %v but got nil", expected)
+	}
+	if actual.RatString() != expected {
+		t.Fatalf("expected %v but got %v", expected, actual.RatString())
+	}
+}
+
+func TestQuote(t *testing.T) {
+	graph := NewOrderBookGraph()
+	// bids for native in usd: 1 usd per native and then 0.5 usd per native
+	graph.AddOffer(splitTestOffer(1, usdAsset, nativeAsset, 1, 1, 50))
+	graph.AddOffer(splitTestOffer(2, usdAsset, nativeAsset, 2, 1, 1000))
+	// ask for native in usd: 1.5 usd per native
+	graph.AddOffer(splitTestOffer(3, nativeAsset, usdAsset, 3, 2, 1000))
+	// eur can only be bought through usd
+	graph.AddOffer(splitTestOffer(4, eurAsset, usdAsset, 1, 2, 1000))
+	if err := graph.Apply(3); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	quote, lastLedger, err := graph.Quote(3, nativeAsset, 100, usdAsset)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if lastLedger != 3 {
+		t.Fatalf("expected last ledger to be %v but got %v", 3, lastLedger)
+	}
+	if quote.DestinationAmount != 75 || len(quote.InteriorNodes) != 0 {
+		t.Fatalf("unexpected quote %v", quote)
+	}
+	assertRatEquals(t, "1", quote.BestPrice)
+	assertRatEquals(t, "3/4", quote.AveragePrice)
+	assertRatEquals(t, "1/2", quote.WorstPrice)
+	assertRatEquals(t, "5/4", quote.MidPrice)
+	assertRatEquals(t, "2/5", quote.PriceImpact)
+	assertRatEquals(t, "1/4", quote.Slippage)
+	if len(quote.Offers) != 2 {
+		t.Fatalf("expected 2 offers but got %v", len(quote.Offers))
+	}
+	if quote.Offers[0].Offer.OfferId != 1 || quote.Offers[0].Sold != 50 || quote.Offers[0].Bought != 50 {
+		t.Fatalf("unexpected offer %v", quote.Offers[0])
+	}
+	if quote.Offers[1].Offer.OfferId != 2 || quote.Offers[1].Sold != 25 || quote.Offers[1].Bought != 50 {
+		t.Fatalf("unexpected offer %v", quote.Offers[1])
+	}
+
+	quote, _, err = graph.Quote(3, nativeAsset, 10, eurAsset)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if quote.DestinationAmount != 20 {
+		t.Fatalf("expected destination amount to be 20 but got %v", quote.DestinationAmount)
+	}
+	assertPathEquals(t, []Path{{InteriorNodes: []xdr.Asset{usdAsset}}}, []Path{{InteriorNodes: quote.InteriorNodes}})
+	assertRatEquals(t, "2", quote.AveragePrice)
+	// there are no asks for usd in eur
+	if quote.MidPrice != nil || quote.PriceImpact != nil {
+		t.Fatalf("expected no mid price but got %v", quote.MidPrice)
+	}
+	if len(quote.Offers) != 2 {
+		t.Fatalf("expected 2 offers but got %v", len(quote.Offers))
+	}
+
+	quote, _, err = graph.Quote(3, nativeAsset, 10, yenAsset)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if quote != nil {
+		t.Fatalf("expected no quote but got %v", quote)
+	}
+}
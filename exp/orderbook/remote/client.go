@@ -0,0 +1,216 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Client is an http client for issuing path finding queries against a
+// remote Handler. Its methods mirror the methods of orderbook.OrderBookGraph.
+type Client struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// ClientOption values can be passed into NewClient to customize a Client instance.
+type ClientOption func(c *Client)
+
+// Timeout configures the timeout of requests sent to the remote Handler.
+func Timeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.client.Timeout = d
+	}
+}
+
+// NewClient returns a new Client instance.
+//
+// Only the serverURL parameter is required.
+func NewClient(serverURL string, options ...ClientOption) (Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return Client{}, errors.Wrap(err, "unparseable url")
+	}
+
+	client := Client{
+		url:    u,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, option := range options {
+		option(&client)
+	}
+	return client, nil
+}
+
+func (c Client) post(endpoint string, request, response interface{}) error {
+	u := *c.url
+	u.Path = path.Join(u.Path, endpoint)
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	r, err := c.client.Post(u.String(), "application/json; charset=utf-8", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to execute request")
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode == http.StatusServiceUnavailable {
+		return ErrOrderBookNotReady
+	}
+	if r.StatusCode != http.StatusOK {
+		message, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return errors.Wrap(err, "failed to read response body")
+		}
+
+		return errors.New(string(message))
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(response); err != nil {
+		return errors.Wrap(err, "failed to decode json payload")
+	}
+	return nil
+}
+
+// FindPaths returns a list of payment paths originating from a source account
+// and ending with a given destinaton asset and amount.
+// See orderbook.OrderBookGraph.FindPaths.
+func (c Client) FindPaths(
+	maxPathLength int,
+	destinationAsset xdr.Asset,
+	destinationAmount xdr.Int64,
+	sourceAccountID *xdr.AccountId,
+	sourceAssets []xdr.Asset,
+	sourceAssetBalances []xdr.Int64,
+	validateSourceBalance bool,
+	maxAssetsPerPath int,
+	constraints orderbook.PathConstraints,
+) ([]orderbook.Path, uint32, error) {
+	var response PathsResponse
+	err := c.post("paths/strict-receive", FindPathsRequest{
+		MaxPathLength:         maxPathLength,
+		DestinationAsset:      Asset(destinationAsset),
+		DestinationAmount:     destinationAmount,
+		SourceAccount:         formatSourceAccount(sourceAccountID),
+		SourceAssets:          toAssets(sourceAssets),
+		SourceAssetBalances:   sourceAssetBalances,
+		ValidateSourceBalance: validateSourceBalance,
+		MaxAssetsPerPath:      maxAssetsPerPath,
+		Constraints:           toConstraints(constraints),
+	}, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fromPaths(response.Paths), response.LastLedger, nil
+}
+
+// FindFixedPaths returns a list of payment paths where the source and destination
+// assets are fixed. See orderbook.OrderBookGraph.FindFixedPaths.
+func (c Client) FindFixedPaths(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxAssetsPerPath int,
+	constraints orderbook.PathConstraints,
+) ([]orderbook.Path, uint32, error) {
+	var response PathsResponse
+	err := c.post("paths/strict-send", FindFixedPathsRequest{
+		MaxPathLength:     maxPathLength,
+		SourceAsset:       Asset(sourceAsset),
+		AmountToSpend:     amountToSpend,
+		DestinationAssets: toAssets(destinationAssets),
+		MaxAssetsPerPath:  maxAssetsPerPath,
+		Constraints:       toConstraints(constraints),
+	}, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fromPaths(response.Paths), response.LastLedger, nil
+}
+
+// FindSplitPaths returns, for each source asset, the payment of
+// `destinationAmount` split across at most `maxSplits` payment paths.
+// See orderbook.OrderBookGraph.FindSplitPaths.
+func (c Client) FindSplitPaths(
+	maxPathLength int,
+	destinationAsset xdr.Asset,
+	destinationAmount xdr.Int64,
+	sourceAccountID *xdr.AccountId,
+	sourceAssets []xdr.Asset,
+	sourceAssetBalances []xdr.Int64,
+	validateSourceBalance bool,
+	maxSplits int,
+) ([]orderbook.SplitPath, uint32, error) {
+	var response SplitPathsResponse
+	err := c.post("split-paths/strict-receive", FindSplitPathsRequest{
+		MaxPathLength:         maxPathLength,
+		DestinationAsset:      Asset(destinationAsset),
+		DestinationAmount:     destinationAmount,
+		SourceAccount:         formatSourceAccount(sourceAccountID),
+		SourceAssets:          toAssets(sourceAssets),
+		SourceAssetBalances:   sourceAssetBalances,
+		ValidateSourceBalance: validateSourceBalance,
+		MaxSplits:             maxSplits,
+	}, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fromSplitPaths(response.SplitPaths), response.LastLedger, nil
+}
+
+// FindFixedSplitPaths returns, for each destination asset, the payment of
+// `amountToSpend` split across at most `maxSplits` payment paths.
+// See orderbook.OrderBookGraph.FindFixedSplitPaths.
+func (c Client) FindFixedSplitPaths(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxSplits int,
+) ([]orderbook.SplitPath, uint32, error) {
+	var response SplitPathsResponse
+	err := c.post("split-paths/strict-send", FindFixedSplitPathsRequest{
+		MaxPathLength:     maxPathLength,
+		SourceAsset:       Asset(sourceAsset),
+		AmountToSpend:     amountToSpend,
+		DestinationAssets: toAssets(destinationAssets),
+		MaxSplits:         maxSplits,
+	}, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fromSplitPaths(response.SplitPaths), response.LastLedger, nil
+}
+
+// Quote returns the quote for selling `amountToSpend` of `sourceAsset` for
+// `destinationAsset`. See orderbook.OrderBookGraph.Quote.
+func (c Client) Quote(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+) (*orderbook.Quote, uint32, error) {
+	var response QuoteResponse
+	err := c.post("quote", QuoteRequest{
+		MaxPathLength:    maxPathLength,
+		SourceAsset:      Asset(sourceAsset),
+		AmountToSpend:    amountToSpend,
+		DestinationAsset: Asset(destinationAsset),
+	}, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fromQuote(response.Quote), response.LastLedger, nil
+}
@@ -0,0 +1,192 @@
+package remote
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	issuer      = xdr.MustAddress(keypair.MustRandom().Address())
+	seller      = xdr.MustAddress(keypair.MustRandom().Address())
+	nativeAsset = xdr.MustNewNativeAsset()
+	usdAsset    = xdr.MustNewCreditAsset("USD", issuer.Address())
+	eurAsset    = xdr.MustNewCreditAsset("EUR", issuer.Address())
+	chfAsset    = xdr.MustNewCreditAsset("CHF", issuer.Address())
+)
+
+func offer(id int64, selling, buying xdr.Asset, n, d int32, amount int64) xdr.OfferEntry {
+	return xdr.OfferEntry{
+		SellerId: seller,
+		OfferId:  xdr.Int64(id),
+		Selling:  selling,
+		Buying:   buying,
+		Price:    xdr.Price{N: xdr.Int32(n), D: xdr.Int32(d)},
+		Amount:   xdr.Int64(amount),
+	}
+}
+
+func testGraph(t *testing.T) *orderbook.OrderBookGraph {
+	graph := orderbook.NewOrderBookGraph()
+	graph.AddOffer(offer(1, usdAsset, nativeAsset, 1, 1, 50))
+	graph.AddOffer(offer(2, usdAsset, nativeAsset, 3, 1, 1000))
+	graph.AddOffer(offer(3, eurAsset, nativeAsset, 1, 1, 1000))
+	graph.AddOffer(offer(4, usdAsset, eurAsset, 2, 1, 1000))
+	graph.AddOffer(offer(5, nativeAsset, usdAsset, 1, 2, 1000))
+	assert.NoError(t, graph.Apply(7))
+	return graph
+}
+
+func newTestClient(t *testing.T, graph *orderbook.OrderBookGraph) (Client, func()) {
+	server := httptest.NewServer(Handler(graph, 3, log.New()))
+	client, err := NewClient(server.URL)
+	assert.NoError(t, err)
+	return client, server.Close
+}
+
+func TestClientFindPaths(t *testing.T) {
+	graph := testGraph(t)
+	client, closeServer := newTestClient(t, graph)
+	defer closeServer()
+
+	expected, expectedLedger, err := graph.FindPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 5,
+		orderbook.PathConstraints{},
+	)
+	assert.NoError(t, err)
+	paths, lastLedger, err := client.FindPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 5,
+		orderbook.PathConstraints{},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedLedger, lastLedger)
+	assert.Len(t, paths, 2)
+	assert.Equal(t, toPaths(expected), toPaths(paths))
+
+	sourceAccount := seller
+	paths, _, err = client.FindPaths(
+		3, usdAsset, 100, &sourceAccount, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 5,
+		orderbook.PathConstraints{},
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	expected, _, err = graph.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, orderbook.PathConstraints{})
+	assert.NoError(t, err)
+	paths, lastLedger, err = client.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, orderbook.PathConstraints{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedLedger, lastLedger)
+	assert.Equal(t, toPaths(expected), toPaths(paths))
+}
+
+func TestClientFindSplitPaths(t *testing.T) {
+	graph := testGraph(t)
+	client, closeServer := newTestClient(t, graph)
+	defer closeServer()
+
+	expected, _, err := graph.FindSplitPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 2,
+	)
+	assert.NoError(t, err)
+	splits, lastLedger, err := client.FindSplitPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 2,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), lastLedger)
+	assert.Len(t, splits, 1)
+	assert.Equal(t, toSplitPaths(expected), toSplitPaths(splits))
+
+	expected, _, err = graph.FindFixedSplitPaths(3, nativeAsset, 150, []xdr.Asset{usdAsset}, 2)
+	assert.NoError(t, err)
+	splits, _, err = client.FindFixedSplitPaths(3, nativeAsset, 150, []xdr.Asset{usdAsset}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, toSplitPaths(expected), toSplitPaths(splits))
+}
+
+func TestClientQuote(t *testing.T) {
+	graph := testGraph(t)
+	client, closeServer := newTestClient(t, graph)
+	defer closeServer()
+
+	expected, _, err := graph.Quote(3, nativeAsset, 100, usdAsset)
+	assert.NoError(t, err)
+	quote, lastLedger, err := client.Quote(3, nativeAsset, 100, usdAsset)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), lastLedger)
+	assert.Equal(t, toQuote(expected), toQuote(quote))
+	assert.Equal(t, "33/50", quote.AveragePrice.RatString())
+
+	quote, _, err = client.Quote(3, nativeAsset, 100, chfAsset)
+	assert.NoError(t, err)
+	assert.Nil(t, quote)
+}
+
+func TestClientErrors(t *testing.T) {
+	graph := orderbook.NewOrderBookGraph()
+	client, closeServer := newTestClient(t, graph)
+	defer closeServer()
+
+	_, _, err := client.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, orderbook.PathConstraints{})
+	assert.Equal(t, ErrOrderBookNotReady, err)
+
+	graph.AddOffer(offer(1, usdAsset, nativeAsset, 1, 1, 50))
+	assert.NoError(t, graph.Apply(1))
+
+	_, _, err = client.FindFixedPaths(4, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, orderbook.PathConstraints{})
+	assert.EqualError(t, err, "max_path_length must be between 1 and 3")
+
+	_, _, err = client.FindPaths(3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, nil, false, 5, orderbook.PathConstraints{})
+	assert.EqualError(t, err, "source_assets and source_asset_balances must have the same length")
+
+	_, _, err = client.FindFixedSplitPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 0)
+	assert.EqualError(t, err, "max_splits must be positive")
+}
+
+func TestClientPathConstraints(t *testing.T) {
+	graph := testGraph(t)
+	client, closeServer := newTestClient(t, graph)
+	defer closeServer()
+
+	constraints := orderbook.PathConstraints{
+		ExcludedAssets:  []xdr.Asset{eurAsset},
+		ExcludedIssuers: []xdr.AccountId{xdr.MustAddress(keypair.MustRandom().Address())},
+		MaxPrice:        big.NewRat(3, 1),
+	}
+	expected, _, err := graph.FindPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 5, constraints,
+	)
+	assert.NoError(t, err)
+	paths, _, err := client.FindPaths(
+		3, usdAsset, 100, nil, []xdr.Asset{nativeAsset}, []xdr.Int64{0}, false, 5, constraints,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	assert.Empty(t, paths[0].InteriorNodes)
+	assert.Equal(t, toPaths(expected), toPaths(paths))
+
+	constraints = orderbook.PathConstraints{MinDestinationAmount: 67}
+	paths, _, err = client.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, constraints)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	constraints = orderbook.PathConstraints{MaxHops: []orderbook.AssetHops{{Asset: usdAsset, Hops: 1}}}
+	expected, _, err = graph.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, constraints)
+	assert.NoError(t, err)
+	paths, _, err = client.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, constraints)
+	assert.NoError(t, err)
+	assert.Equal(t, toPaths(expected), toPaths(paths))
+	for _, path := range paths {
+		assert.Empty(t, path.InteriorNodes)
+	}
+
+	constraints = orderbook.PathConstraints{MaxHops: []orderbook.AssetHops{{Asset: usdAsset, Hops: -1}}}
+	_, _, err = client.FindFixedPaths(3, nativeAsset, 100, []xdr.Asset{usdAsset}, 5, constraints)
+	assert.EqualError(t, err, "max_hops must not be negative")
+}
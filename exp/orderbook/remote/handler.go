@@ -0,0 +1,232 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/support/errors"
+	supporthttp "github.com/stellar/go/support/http"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+type server struct {
+	graph         *orderbook.OrderBookGraph
+	maxPathLength int
+	log           *supportlog.Entry
+}
+
+func (s server) serializeResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	response interface{},
+	err error,
+) {
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.log.WithContext(r.Context()).WithError(err).Warn("could not serialize response")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// decodeRequest parses the request body into payload and writes an error
+// response if the request cannot be served.
+func (s server) decodeRequest(w http.ResponseWriter, r *http.Request, payload interface{}) bool {
+	if s.graph.IsEmpty() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(ErrOrderBookNotReady.Error()))
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		s.badRequest(w, err)
+		return false
+	}
+	return true
+}
+
+func (s server) badRequest(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte(err.Error()))
+}
+
+func (s server) validatePathLength(maxPathLength int) error {
+	if maxPathLength <= 0 || maxPathLength > s.maxPathLength {
+		return errors.Errorf("max_path_length must be between 1 and %d", s.maxPathLength)
+	}
+	return nil
+}
+
+func validateSplits(maxSplits int) error {
+	if maxSplits <= 0 {
+		return errors.New("max_splits must be positive")
+	}
+	return nil
+}
+
+// Handler returns an HTTP handler which serves the path finding queries of
+// graph. Queries which exceed maxPathLength are rejected and all queries
+// fail with 503 Service Unavailable while the graph is empty.
+func Handler(graph *orderbook.OrderBookGraph, maxPathLength int, log *supportlog.Entry) http.Handler {
+	s := server{graph: graph, maxPathLength: maxPathLength, log: log}
+	mux := supporthttp.NewMux(log)
+
+	mux.Post("/paths/strict-receive", func(w http.ResponseWriter, r *http.Request) {
+		req := FindPathsRequest{}
+		if !s.decodeRequest(w, r, &req) {
+			return
+		}
+		if err := s.validatePathLength(req.MaxPathLength); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		if len(req.SourceAssets) != len(req.SourceAssetBalances) {
+			s.badRequest(w, errors.New("source_assets and source_asset_balances must have the same length"))
+			return
+		}
+		sourceAccount, err := parseSourceAccount(req.SourceAccount)
+		if err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		constraints, err := fromConstraints(req.Constraints)
+		if err != nil {
+			s.badRequest(w, err)
+			return
+		}
+
+		paths, lastLedger, err := graph.FindPaths(
+			req.MaxPathLength,
+			xdr.Asset(req.DestinationAsset),
+			req.DestinationAmount,
+			sourceAccount,
+			fromAssets(req.SourceAssets),
+			req.SourceAssetBalances,
+			req.ValidateSourceBalance,
+			req.MaxAssetsPerPath,
+			constraints,
+		)
+		s.serializeResponse(w, r, PathsResponse{LastLedger: lastLedger, Paths: toPaths(paths)}, err)
+	})
+
+	mux.Post("/paths/strict-send", func(w http.ResponseWriter, r *http.Request) {
+		req := FindFixedPathsRequest{}
+		if !s.decodeRequest(w, r, &req) {
+			return
+		}
+		if err := s.validatePathLength(req.MaxPathLength); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		constraints, err := fromConstraints(req.Constraints)
+		if err != nil {
+			s.badRequest(w, err)
+			return
+		}
+
+		paths, lastLedger, err := graph.FindFixedPaths(
+			req.MaxPathLength,
+			xdr.Asset(req.SourceAsset),
+			req.AmountToSpend,
+			fromAssets(req.DestinationAssets),
+			req.MaxAssetsPerPath,
+			constraints,
+		)
+		s.serializeResponse(w, r, PathsResponse{LastLedger: lastLedger, Paths: toPaths(paths)}, err)
+	})
+
+	mux.Post("/split-paths/strict-receive", func(w http.ResponseWriter, r *http.Request) {
+		req := FindSplitPathsRequest{}
+		if !s.decodeRequest(w, r, &req) {
+			return
+		}
+		if err := s.validatePathLength(req.MaxPathLength); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		if err := validateSplits(req.MaxSplits); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		if len(req.SourceAssets) != len(req.SourceAssetBalances) {
+			s.badRequest(w, errors.New("source_assets and source_asset_balances must have the same length"))
+			return
+		}
+		sourceAccount, err := parseSourceAccount(req.SourceAccount)
+		if err != nil {
+			s.badRequest(w, err)
+			return
+		}
+
+		splits, lastLedger, err := graph.FindSplitPaths(
+			req.MaxPathLength,
+			xdr.Asset(req.DestinationAsset),
+			req.DestinationAmount,
+			sourceAccount,
+			fromAssets(req.SourceAssets),
+			req.SourceAssetBalances,
+			req.ValidateSourceBalance,
+			req.MaxSplits,
+		)
+		s.serializeResponse(
+			w, r,
+			SplitPathsResponse{LastLedger: lastLedger, SplitPaths: toSplitPaths(splits)},
+			err,
+		)
+	})
+
+	mux.Post("/split-paths/strict-send", func(w http.ResponseWriter, r *http.Request) {
+		req := FindFixedSplitPathsRequest{}
+		if !s.decodeRequest(w, r, &req) {
+			return
+		}
+		if err := s.validatePathLength(req.MaxPathLength); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+		if err := validateSplits(req.MaxSplits); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+
+		splits, lastLedger, err := graph.FindFixedSplitPaths(
+			req.MaxPathLength,
+			xdr.Asset(req.SourceAsset),
+			req.AmountToSpend,
+			fromAssets(req.DestinationAssets),
+			req.MaxSplits,
+		)
+		s.serializeResponse(
+			w, r,
+			SplitPathsResponse{LastLedger: lastLedger, SplitPaths: toSplitPaths(splits)},
+			err,
+		)
+	})
+
+	mux.Post("/quote", func(w http.ResponseWriter, r *http.Request) {
+		req := QuoteRequest{}
+		if !s.decodeRequest(w, r, &req) {
+			return
+		}
+		if err := s.validatePathLength(req.MaxPathLength); err != nil {
+			s.badRequest(w, err)
+			return
+		}
+
+		quote, lastLedger, err := graph.Quote(
+			req.MaxPathLength,
+			xdr.Asset(req.SourceAsset),
+			req.AmountToSpend,
+			xdr.Asset(req.DestinationAsset),
+		)
+		s.serializeResponse(w, r, QuoteResponse{LastLedger: lastLedger, Quote: toQuote(quote)}, err)
+	})
+
+	return mux
+}
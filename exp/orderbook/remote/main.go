@@ -0,0 +1,404 @@
+// Package remote exposes the path finding queries of an
+// orderbook.OrderBookGraph over HTTP so that path finding can run in a
+// process separate from the one serving the rest of the API.
+//
+// Handler serves the queries of a graph and Client issues them against a
+// remote Handler. Both use the JSON messages defined in this file, where
+// assets and offers are encoded as base64 XDR strings.
+package remote
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrOrderBookNotReady is returned by Client when the remote order book has
+// not been populated yet.
+var ErrOrderBookNotReady = errors.New("remote order book is not ready")
+
+// Asset extends xdr.Asset with JSON encoding and decoding
+type Asset xdr.Asset
+
+func (a *Asset) UnmarshalJSON(b []byte) error {
+	var base64 string
+	if err := json.Unmarshal(b, &base64); err != nil {
+		return err
+	}
+
+	var parsed xdr.Asset
+	if err := xdr.SafeUnmarshalBase64(base64, &parsed); err != nil {
+		return err
+	}
+	*a = Asset(parsed)
+
+	return nil
+}
+
+func (a Asset) MarshalJSON() ([]byte, error) {
+	base64, err := xdr.MarshalBase64(xdr.Asset(a))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(base64)
+}
+
+// Offer extends xdr.OfferEntry with JSON encoding and decoding
+type Offer xdr.OfferEntry
+
+func (o *Offer) UnmarshalJSON(b []byte) error {
+	var base64 string
+	if err := json.Unmarshal(b, &base64); err != nil {
+		return err
+	}
+
+	var parsed xdr.OfferEntry
+	if err := xdr.SafeUnmarshalBase64(base64, &parsed); err != nil {
+		return err
+	}
+	*o = Offer(parsed)
+
+	return nil
+}
+
+func (o Offer) MarshalJSON() ([]byte, error) {
+	base64, err := xdr.MarshalBase64(xdr.OfferEntry(o))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(base64)
+}
+
+// FindPathsRequest is the request for the strict receive path finding query.
+// See orderbook.OrderBookGraph.FindPaths.
+type FindPathsRequest struct {
+	MaxPathLength         int             `json:"max_path_length"`
+	DestinationAsset      Asset           `json:"destination_asset"`
+	DestinationAmount     xdr.Int64       `json:"destination_amount"`
+	SourceAccount         string          `json:"source_account,omitempty"`
+	SourceAssets          []Asset         `json:"source_assets"`
+	SourceAssetBalances   []xdr.Int64     `json:"source_asset_balances"`
+	ValidateSourceBalance bool            `json:"validate_source_balance"`
+	MaxAssetsPerPath      int             `json:"max_assets_per_path"`
+	Constraints           PathConstraints `json:"constraints"`
+}
+
+// FindFixedPathsRequest is the request for the strict send path finding query.
+// See orderbook.OrderBookGraph.FindFixedPaths.
+type FindFixedPathsRequest struct {
+	MaxPathLength     int             `json:"max_path_length"`
+	SourceAsset       Asset           `json:"source_asset"`
+	AmountToSpend     xdr.Int64       `json:"amount_to_spend"`
+	DestinationAssets []Asset         `json:"destination_assets"`
+	MaxAssetsPerPath  int             `json:"max_assets_per_path"`
+	Constraints       PathConstraints `json:"constraints"`
+}
+
+// PathConstraints is the JSON representation of orderbook.PathConstraints.
+// Issuers are encoded as account addresses.
+type PathConstraints struct {
+	ExcludedAssets       []Asset     `json:"excluded_assets,omitempty"`
+	ExcludedIssuers      []string    `json:"excluded_issuers,omitempty"`
+	IntermediateAssets   []Asset     `json:"intermediate_assets,omitempty"`
+	MaxPrice             *big.Rat    `json:"max_price,omitempty"`
+	MinDestinationAmount xdr.Int64   `json:"min_destination_amount,omitempty"`
+	MaxHops              []AssetHops `json:"max_hops,omitempty"`
+}
+
+// AssetHops is the JSON representation of orderbook.AssetHops.
+type AssetHops struct {
+	Asset Asset `json:"asset"`
+	Hops  int   `json:"hops"`
+}
+
+// FindSplitPathsRequest is the request for the strict receive split path
+// finding query. See orderbook.OrderBookGraph.FindSplitPaths.
+type FindSplitPathsRequest struct {
+	MaxPathLength         int         `json:"max_path_length"`
+	DestinationAsset      Asset       `json:"destination_asset"`
+	DestinationAmount     xdr.Int64   `json:"destination_amount"`
+	SourceAccount         string      `json:"source_account,omitempty"`
+	SourceAssets          []Asset     `json:"source_assets"`
+	SourceAssetBalances   []xdr.Int64 `json:"source_asset_balances"`
+	ValidateSourceBalance bool        `json:"validate_source_balance"`
+	MaxSplits             int         `json:"max_splits"`
+}
+
+// FindFixedSplitPathsRequest is the request for the strict send split path
+// finding query. See orderbook.OrderBookGraph.FindFixedSplitPaths.
+type FindFixedSplitPathsRequest struct {
+	MaxPathLength     int       `json:"max_path_length"`
+	SourceAsset       Asset     `json:"source_asset"`
+	AmountToSpend     xdr.Int64 `json:"amount_to_spend"`
+	DestinationAssets []Asset   `json:"destination_assets"`
+	MaxSplits         int       `json:"max_splits"`
+}
+
+// QuoteRequest is the request for the quote query.
+// See orderbook.OrderBookGraph.Quote.
+type QuoteRequest struct {
+	MaxPathLength    int       `json:"max_path_length"`
+	SourceAsset      Asset     `json:"source_asset"`
+	AmountToSpend    xdr.Int64 `json:"amount_to_spend"`
+	DestinationAsset Asset     `json:"destination_asset"`
+}
+
+// Path is the JSON representation of orderbook.Path
+type Path struct {
+	SourceAsset       Asset     `json:"source_asset"`
+	SourceAmount      xdr.Int64 `json:"source_amount"`
+	DestinationAsset  Asset     `json:"destination_asset"`
+	DestinationAmount xdr.Int64 `json:"destination_amount"`
+	InteriorNodes     []Asset   `json:"interior_nodes"`
+}
+
+// SplitPath is the JSON representation of orderbook.SplitPath
+type SplitPath struct {
+	SourceAsset       Asset     `json:"source_asset"`
+	SourceAmount      xdr.Int64 `json:"source_amount"`
+	DestinationAsset  Asset     `json:"destination_asset"`
+	DestinationAmount xdr.Int64 `json:"destination_amount"`
+	Paths             []Path    `json:"paths"`
+}
+
+// QuotedOffer is the JSON representation of orderbook.QuotedOffer
+type QuotedOffer struct {
+	Offer  Offer     `json:"offer"`
+	Sold   xdr.Int64 `json:"sold"`
+	Bought xdr.Int64 `json:"bought"`
+}
+
+// Quote is the JSON representation of orderbook.Quote. Prices are encoded
+// as fractions, e.g. "3/4".
+type Quote struct {
+	SourceAsset       Asset         `json:"source_asset"`
+	SourceAmount      xdr.Int64     `json:"source_amount"`
+	DestinationAsset  Asset         `json:"destination_asset"`
+	DestinationAmount xdr.Int64     `json:"destination_amount"`
+	InteriorNodes     []Asset       `json:"interior_nodes"`
+	BestPrice         *big.Rat      `json:"best_price"`
+	AveragePrice      *big.Rat      `json:"average_price"`
+	WorstPrice        *big.Rat      `json:"worst_price"`
+	MidPrice          *big.Rat      `json:"mid_price"`
+	PriceImpact       *big.Rat      `json:"price_impact"`
+	Slippage          *big.Rat      `json:"slippage"`
+	Offers            []QuotedOffer `json:"offers"`
+}
+
+// PathsResponse is the response for the strict receive and strict send
+// path finding queries.
+type PathsResponse struct {
+	LastLedger uint32 `json:"last_ledger"`
+	Paths      []Path `json:"paths"`
+}
+
+// SplitPathsResponse is the response for the split path finding queries.
+type SplitPathsResponse struct {
+	LastLedger uint32      `json:"last_ledger"`
+	SplitPaths []SplitPath `json:"split_paths"`
+}
+
+// QuoteResponse is the response for the quote query. Quote is nil when the
+// destination asset cannot be reached.
+type QuoteResponse struct {
+	LastLedger uint32 `json:"last_ledger"`
+	Quote      *Quote `json:"quote"`
+}
+
+func fromAssets(assets []Asset) []xdr.Asset {
+	result := make([]xdr.Asset, len(assets))
+	for i, asset := range assets {
+		result[i] = xdr.Asset(asset)
+	}
+	return result
+}
+
+func toAssets(assets []xdr.Asset) []Asset {
+	result := make([]Asset, len(assets))
+	for i, asset := range assets {
+		result[i] = Asset(asset)
+	}
+	return result
+}
+
+func fromPaths(paths []Path) []orderbook.Path {
+	result := make([]orderbook.Path, len(paths))
+	for i, path := range paths {
+		result[i] = orderbook.Path{
+			SourceAsset:       xdr.Asset(path.SourceAsset),
+			SourceAmount:      path.SourceAmount,
+			DestinationAsset:  xdr.Asset(path.DestinationAsset),
+			DestinationAmount: path.DestinationAmount,
+			InteriorNodes:     fromAssets(path.InteriorNodes),
+		}
+	}
+	return result
+}
+
+func toPaths(paths []orderbook.Path) []Path {
+	result := make([]Path, len(paths))
+	for i, path := range paths {
+		result[i] = Path{
+			SourceAsset:       Asset(path.SourceAsset),
+			SourceAmount:      path.SourceAmount,
+			DestinationAsset:  Asset(path.DestinationAsset),
+			DestinationAmount: path.DestinationAmount,
+			InteriorNodes:     toAssets(path.InteriorNodes),
+		}
+	}
+	return result
+}
+
+func fromSplitPaths(splits []SplitPath) []orderbook.SplitPath {
+	result := make([]orderbook.SplitPath, len(splits))
+	for i, split := range splits {
+		result[i] = orderbook.SplitPath{
+			SourceAsset:       xdr.Asset(split.SourceAsset),
+			SourceAmount:      split.SourceAmount,
+			DestinationAsset:  xdr.Asset(split.DestinationAsset),
+			DestinationAmount: split.DestinationAmount,
+			Paths:             fromPaths(split.Paths),
+		}
+	}
+	return result
+}
+
+func toSplitPaths(splits []orderbook.SplitPath) []SplitPath {
+	result := make([]SplitPath, len(splits))
+	for i, split := range splits {
+		result[i] = SplitPath{
+			SourceAsset:       Asset(split.SourceAsset),
+			SourceAmount:      split.SourceAmount,
+			DestinationAsset:  Asset(split.DestinationAsset),
+			DestinationAmount: split.DestinationAmount,
+			Paths:             toPaths(split.Paths),
+		}
+	}
+	return result
+}
+
+func fromQuote(quote *Quote) *orderbook.Quote {
+	if quote == nil {
+		return nil
+	}
+	result := &orderbook.Quote{
+		SourceAsset:       xdr.Asset(quote.SourceAsset),
+		SourceAmount:      quote.SourceAmount,
+		DestinationAsset:  xdr.Asset(quote.DestinationAsset),
+		DestinationAmount: quote.DestinationAmount,
+		InteriorNodes:     fromAssets(quote.InteriorNodes),
+		BestPrice:         quote.BestPrice,
+		AveragePrice:      quote.AveragePrice,
+		WorstPrice:        quote.WorstPrice,
+		MidPrice:          quote.MidPrice,
+		PriceImpact:       quote.PriceImpact,
+		Slippage:          quote.Slippage,
+		Offers:            make([]orderbook.QuotedOffer, len(quote.Offers)),
+	}
+	for i, offer := range quote.Offers {
+		result.Offers[i] = orderbook.QuotedOffer{
+			Offer:  xdr.OfferEntry(offer.Offer),
+			Sold:   offer.Sold,
+			Bought: offer.Bought,
+		}
+	}
+	return result
+}
+
+func toQuote(quote *orderbook.Quote) *Quote {
+	if quote == nil {
+		return nil
+	}
+	result := &Quote{
+		SourceAsset:       Asset(quote.SourceAsset),
+		SourceAmount:      quote.SourceAmount,
+		DestinationAsset:  Asset(quote.DestinationAsset),
+		DestinationAmount: quote.DestinationAmount,
+		InteriorNodes:     toAssets(quote.InteriorNodes),
+		BestPrice:         quote.BestPrice,
+		AveragePrice:      quote.AveragePrice,
+		WorstPrice:        quote.WorstPrice,
+		MidPrice:          quote.MidPrice,
+		PriceImpact:       quote.PriceImpact,
+		Slippage:          quote.Slippage,
+		Offers:            make([]QuotedOffer, len(quote.Offers)),
+	}
+	for i, offer := range quote.Offers {
+		result.Offers[i] = QuotedOffer{
+			Offer:  Offer(offer.Offer),
+			Sold:   offer.Sold,
+			Bought: offer.Bought,
+		}
+	}
+	return result
+}
+
+func fromConstraints(constraints PathConstraints) (orderbook.PathConstraints, error) {
+	result := orderbook.PathConstraints{
+		ExcludedAssets:       fromAssets(constraints.ExcludedAssets),
+		IntermediateAssets:   fromAssets(constraints.IntermediateAssets),
+		MaxPrice:             constraints.MaxPrice,
+		MinDestinationAmount: constraints.MinDestinationAmount,
+	}
+	for _, address := range constraints.ExcludedIssuers {
+		issuer, err := xdr.AddressToAccountId(address)
+		if err != nil {
+			return orderbook.PathConstraints{}, errors.Wrap(err, "invalid excluded issuer")
+		}
+		result.ExcludedIssuers = append(result.ExcludedIssuers, issuer)
+	}
+	for _, limit := range constraints.MaxHops {
+		if limit.Hops < 0 {
+			return orderbook.PathConstraints{}, errors.New("max_hops must not be negative")
+		}
+		result.MaxHops = append(result.MaxHops, orderbook.AssetHops{
+			Asset: xdr.Asset(limit.Asset),
+			Hops:  limit.Hops,
+		})
+	}
+	return result, nil
+}
+
+func toConstraints(constraints orderbook.PathConstraints) PathConstraints {
+	result := PathConstraints{
+		MaxPrice:             constraints.MaxPrice,
+		MinDestinationAmount: constraints.MinDestinationAmount,
+	}
+	if len(constraints.ExcludedAssets) > 0 {
+		result.ExcludedAssets = toAssets(constraints.ExcludedAssets)
+	}
+	if len(constraints.IntermediateAssets) > 0 {
+		result.IntermediateAssets = toAssets(constraints.IntermediateAssets)
+	}
+	for _, issuer := range constraints.ExcludedIssuers {
+		result.ExcludedIssuers = append(result.ExcludedIssuers, issuer.Address())
+	}
+	for _, limit := range constraints.MaxHops {
+		result.MaxHops = append(result.MaxHops, AssetHops{
+			Asset: Asset(limit.Asset),
+			Hops:  limit.Hops,
+		})
+	}
+	return result
+}
+
+func parseSourceAccount(address string) (*xdr.AccountId, error) {
+	if address == "" {
+		return nil, nil
+	}
+	var accountID xdr.AccountId
+	if err := accountID.SetAddress(address); err != nil {
+		return nil, errors.Wrap(err, "invalid source account")
+	}
+	return &accountID, nil
+}
+
+func formatSourceAccount(accountID *xdr.AccountId) string {
+	if accountID == nil {
+		return ""
+	}
+	return accountID.Address()
+}
@@ -0,0 +1,125 @@
+package orderbook
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// snapshotMagic identifies order book snapshots, snapshotVersion must be
+// incremented whenever the format changes.
+var snapshotMagic = [4]byte{'O', 'B', 'G', 'S'}
+
+const snapshotVersion = uint32(1)
+
+// ErrInvalidSnapshot is returned by ReadSnapshot when the snapshot is
+// truncated, corrupt or of an unsupported version.
+var ErrInvalidSnapshot = errors.New("invalid order book snapshot")
+
+// WriteSnapshot writes a binary snapshot of the given offers, which are
+// accurate up to `lastLedger`, to w. The snapshot consists of a header with
+// the format version, `lastLedger` and the number of offers, followed by the
+// XDR encoded offers and a SHA-256 checksum of everything preceding it.
+func WriteSnapshot(w io.Writer, lastLedger uint32, offers []xdr.OfferEntry) error {
+	hash := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(w, hash))
+
+	header := make([]byte, 16)
+	copy(header, snapshotMagic[:])
+	binary.BigEndian.PutUint32(header[4:], snapshotVersion)
+	binary.BigEndian.PutUint32(header[8:], lastLedger)
+	binary.BigEndian.PutUint32(header[12:], uint32(len(offers)))
+	if _, err := buffered.Write(header); err != nil {
+		return errors.Wrap(err, "could not write snapshot header")
+	}
+
+	for _, offer := range offers {
+		if _, err := xdr.Marshal(buffered, offer); err != nil {
+			return errors.Wrapf(err, "could not write offer %d", offer.OfferId)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return errors.Wrap(err, "could not write offers")
+	}
+
+	if _, err := w.Write(hash.Sum(nil)); err != nil {
+		return errors.Wrap(err, "could not write snapshot checksum")
+	}
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot and returns the
+// ledger it is accurate up to along with its offers.
+func ReadSnapshot(r io.Reader) (uint32, []xdr.OfferEntry, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "could not read snapshot")
+	}
+	if len(raw) < 16+sha256.Size {
+		return 0, nil, ErrInvalidSnapshot
+	}
+
+	body, checksum := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if sum := sha256.Sum256(body); !bytes.Equal(sum[:], checksum) {
+		return 0, nil, ErrInvalidSnapshot
+	}
+	if !bytes.Equal(body[:4], snapshotMagic[:]) ||
+		binary.BigEndian.Uint32(body[4:]) != snapshotVersion {
+		return 0, nil, ErrInvalidSnapshot
+	}
+	lastLedger := binary.BigEndian.Uint32(body[8:])
+	count := binary.BigEndian.Uint32(body[12:])
+
+	reader := bytes.NewReader(body[16:])
+	offers := make([]xdr.OfferEntry, 0, count)
+	for i := uint32(0); i < count; i++ {
+		var offer xdr.OfferEntry
+		if _, err := xdr.Unmarshal(reader, &offer); err != nil {
+			return 0, nil, ErrInvalidSnapshot
+		}
+		offers = append(offers, offer)
+	}
+	if reader.Len() != 0 {
+		return 0, nil, ErrInvalidSnapshot
+	}
+
+	return lastLedger, offers, nil
+}
+
+// WriteSnapshot writes a binary snapshot of the graph to w. See the
+// package level WriteSnapshot for the format.
+func (graph *OrderBookGraph) WriteSnapshot(w io.Writer) error {
+	graph.lock.RLock()
+	defer graph.lock.RUnlock()
+
+	offers := make([]xdr.OfferEntry, 0, len(graph.tradingPairForOffer))
+	for _, edges := range graph.edgesForSellingAsset {
+		for _, offersForPair := range edges {
+			offers = append(offers, offersForPair...)
+		}
+	}
+	return WriteSnapshot(w, graph.lastLedger, offers)
+}
+
+// LoadSnapshot replaces the offers of the graph with the offers of a snapshot
+// written by WriteSnapshot. The graph is left unchanged if the snapshot
+// cannot be read and is left empty if its offers cannot be applied.
+func (graph *OrderBookGraph) LoadSnapshot(r io.Reader) error {
+	lastLedger, offers, err := ReadSnapshot(r)
+	if err != nil {
+		return err
+	}
+
+	graph.Clear()
+	defer graph.Discard()
+	for _, offer := range offers {
+		graph.AddOffer(offer)
+	}
+	return graph.Apply(lastLedger)
+}
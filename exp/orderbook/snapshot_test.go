@@ -0,0 +1,91 @@
+package orderbook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	graph := NewOrderBookGraph()
+	graph.AddOffer(dollarOffer)
+	graph.AddOffer(eurOffer)
+	graph.AddOffer(twoEurOffer)
+	graph.AddOffer(fiftyCentsOffer)
+	if err := graph.Apply(42); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := graph.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	loaded := NewOrderBookGraph()
+	loaded.AddOffer(quarterOffer)
+	if err := loaded.Apply(1); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := loaded.LoadSnapshot(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertGraphEquals(t, graph, loaded)
+
+	lastLedger, offers, err := ReadSnapshot(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if lastLedger != 42 {
+		t.Fatalf("expected last ledger to be 42 but got %v", lastLedger)
+	}
+	if len(offers) != 4 {
+		t.Fatalf("expected 4 offers but got %v", len(offers))
+	}
+
+	// the graph can be updated after loading the snapshot
+	loaded.RemoveOffer(dollarOffer.OfferId)
+	if err := loaded.Apply(43); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestEmptySnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, 7, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	lastLedger, offers, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if lastLedger != 7 || len(offers) != 0 {
+		t.Fatalf("unexpected snapshot %v %v", lastLedger, offers)
+	}
+}
+
+func TestInvalidSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, 42, []xdr.OfferEntry{dollarOffer, eurOffer}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	valid := buf.Bytes()
+
+	corrupt := append([]byte{}, valid...)
+	corrupt[20] ^= 0xff
+	truncated := valid[:len(valid)-1]
+	for _, snapshot := range [][]byte{nil, corrupt, truncated} {
+		graph := NewOrderBookGraph()
+		graph.AddOffer(quarterOffer)
+		if err := graph.Apply(1); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if err := graph.LoadSnapshot(bytes.NewReader(snapshot)); err != ErrInvalidSnapshot {
+			t.Fatalf("expected error %v but got %v", ErrInvalidSnapshot, err)
+		}
+		if offers := graph.Offers(); len(offers) != 1 || graph.lastLedger != 1 {
+			t.Fatalf("expected graph to be unchanged")
+		}
+	}
+}
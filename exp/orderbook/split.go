@@ -0,0 +1,457 @@
+package orderbook
+
+import (
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// splitChunks is the number of parts a split payment is divided into. Each
+// part is routed through the best path given the offers consumed by the
+// previous parts, so a larger value gets closer to the optimal allocation at
+// the cost of more searches.
+const splitChunks = 20
+
+// SplitPath is a payment divided across several payment paths. The paths are
+// meant to be executed in order as operations of a single transaction: the
+// amounts of each path account for the offers consumed by the previous ones.
+type SplitPath struct {
+	SourceAsset       xdr.Asset
+	SourceAmount      xdr.Int64
+	DestinationAsset  xdr.Asset
+	DestinationAmount xdr.Int64
+	Paths             []Path
+}
+
+// residualOffers maps offer ids to the amount of the offer which has already
+// been consumed.
+type residualOffers map[xdr.Int64]xdr.Int64
+
+// apply returns offers with their amounts reduced by the amounts already
+// consumed, omitting offers which have been fully consumed. offers is
+// returned as is when none of them have been consumed.
+func (r residualOffers) apply(offers []xdr.OfferEntry) []xdr.OfferEntry {
+	if len(r) == 0 {
+		return offers
+	}
+
+	var adjusted []xdr.OfferEntry
+	for i, offer := range offers {
+		consumed, ok := r[offer.OfferId]
+		if !ok {
+			if adjusted != nil {
+				adjusted = append(adjusted, offer)
+			}
+			continue
+		}
+		if adjusted == nil {
+			adjusted = make([]xdr.OfferEntry, i, len(offers))
+			copy(adjusted, offers[:i])
+		}
+		if offer.Amount > consumed {
+			offer.Amount -= consumed
+			adjusted = append(adjusted, offer)
+		}
+	}
+
+	if adjusted == nil {
+		return offers
+	}
+	return adjusted
+}
+
+// route is a sequence of assets from a source asset to a destination asset
+// with the amount allocated to it.
+type route struct {
+	assets []xdr.Asset
+	amount xdr.Int64
+}
+
+func (r route) key() string {
+	key := ""
+	for _, asset := range r.assets {
+		key += asset.String() + ","
+	}
+	return key
+}
+
+// chunks divides amount into at most splitChunks positive parts.
+func chunks(amount xdr.Int64) []xdr.Int64 {
+	n := xdr.Int64(splitChunks)
+	if amount < n {
+		n = amount
+	}
+	parts := make([]xdr.Int64, 0, n)
+	for i := xdr.Int64(0); i < n; i++ {
+		part := amount / n
+		if i < amount%n {
+			part++
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// FindSplitPaths is like FindPaths but, for each source asset, divides the
+// destination amount across at most `maxSplits` payment paths so that the
+// total source amount is as small as possible. Source assets which cannot
+// deliver the destination amount are omitted.
+func (graph *OrderBookGraph) FindSplitPaths(
+	maxPathLength int,
+	destinationAsset xdr.Asset,
+	destinationAmount xdr.Int64,
+	sourceAccountID *xdr.AccountId,
+	sourceAssets []xdr.Asset,
+	sourceAssetBalances []xdr.Int64,
+	validateSourceBalance bool,
+	maxSplits int,
+) ([]SplitPath, uint32, error) {
+	graph.lock.RLock()
+	defer graph.lock.RUnlock()
+
+	results := []SplitPath{}
+	for i, sourceAsset := range sourceAssets {
+		split, found, err := graph.splitStrictReceive(
+			maxPathLength,
+			sourceAsset,
+			destinationAsset,
+			destinationAmount,
+			sourceAccountID,
+			maxSplits,
+		)
+		if err != nil {
+			return nil, graph.lastLedger, errors.Wrap(err, "could not determine split paths")
+		}
+		if !found || (validateSourceBalance && split.SourceAmount > sourceAssetBalances[i]) {
+			continue
+		}
+		results = append(results, split)
+	}
+	return results, graph.lastLedger, nil
+}
+
+// FindFixedSplitPaths is like FindFixedPaths but, for each destination asset,
+// divides `amountToSpend` across at most `maxSplits` payment paths so that the
+// total destination amount is as large as possible. Destination assets which
+// cannot be reached are omitted.
+func (graph *OrderBookGraph) FindFixedSplitPaths(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxSplits int,
+) ([]SplitPath, uint32, error) {
+	graph.lock.RLock()
+	defer graph.lock.RUnlock()
+
+	results := []SplitPath{}
+	for _, destinationAsset := range destinationAssets {
+		split, found, err := graph.splitStrictSend(
+			maxPathLength,
+			sourceAsset,
+			amountToSpend,
+			destinationAsset,
+			maxSplits,
+		)
+		if err != nil {
+			return nil, graph.lastLedger, errors.Wrap(err, "could not determine split paths")
+		}
+		if found {
+			results = append(results, split)
+		}
+	}
+	return results, graph.lastLedger, nil
+}
+
+// allowed returns the paths which may receive the next part of a split
+// payment: once `maxSplits` routes are in use, only those routes are allowed.
+func allowed(paths []Path, routes []route, maxSplits int, assets func(Path) []xdr.Asset) []Path {
+	if len(routes) < maxSplits {
+		return paths
+	}
+	used := map[string]bool{}
+	for _, r := range routes {
+		used[r.key()] = true
+	}
+	filtered := []Path{}
+	for _, p := range paths {
+		if used[route{assets: assets(p)}.key()] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// allocate adds amount to the route going through assets.
+func allocate(routes []route, assets []xdr.Asset, amount xdr.Int64) []route {
+	key := route{assets: assets}.key()
+	for i := range routes {
+		if routes[i].key() == key {
+			routes[i].amount += amount
+			return routes
+		}
+	}
+	return append(routes, route{assets: assets, amount: amount})
+}
+
+// direct returns the split path of a payment which does not cross any offers.
+func direct(asset xdr.Asset, amount xdr.Int64) SplitPath {
+	return SplitPath{
+		SourceAsset:       asset,
+		SourceAmount:      amount,
+		DestinationAsset:  asset,
+		DestinationAmount: amount,
+		Paths: []Path{{
+			SourceAsset:       asset,
+			SourceAmount:      amount,
+			DestinationAsset:  asset,
+			DestinationAmount: amount,
+			InteriorNodes:     []xdr.Asset{},
+		}},
+	}
+}
+
+// pathAssets returns the assets of p, from the source to the destination.
+func pathAssets(p Path) []xdr.Asset {
+	assets := append([]xdr.Asset{p.SourceAsset}, p.InteriorNodes...)
+	return append(assets, p.DestinationAsset)
+}
+
+func (graph *OrderBookGraph) splitStrictReceive(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	destinationAsset xdr.Asset,
+	destinationAmount xdr.Int64,
+	ignoreOffersFrom *xdr.AccountId,
+	maxSplits int,
+) (SplitPath, bool, error) {
+	if sourceAsset.Equals(destinationAsset) {
+		return direct(sourceAsset, destinationAmount), true, nil
+	}
+
+	residual := residualOffers{}
+	routes := []route{}
+	for _, part := range chunks(destinationAmount) {
+		searchState := &sellingGraphSearchState{
+			graph:                  graph,
+			destinationAsset:       destinationAsset,
+			destinationAssetAmount: part,
+			ignoreOffersFrom:       ignoreOffersFrom,
+			targetAssets:           map[string]xdr.Int64{sourceAsset.String(): 0},
+			paths:                  []Path{},
+			residual:               residual,
+		}
+		err := dfs(
+			searchState,
+			maxPathLength,
+			map[string]bool{},
+			[]xdr.Asset{},
+			destinationAsset.String(),
+			destinationAsset,
+			part,
+		)
+		if err != nil {
+			return SplitPath{}, false, err
+		}
+
+		candidates := allowed(searchState.paths, routes, maxSplits, pathAssets)
+		if len(candidates) == 0 {
+			return SplitPath{}, false, nil
+		}
+		best := 0
+		for i := range candidates {
+			if compareSourceAsset(candidates, i, best) {
+				best = i
+			}
+		}
+
+		assets := pathAssets(candidates[best])
+		_, err = graph.executeStrictReceive(residual, ignoreOffersFrom, assets, part)
+		if err == errNoLiquidity {
+			return SplitPath{}, false, nil
+		} else if err != nil {
+			return SplitPath{}, false, err
+		}
+		routes = allocate(routes, assets, part)
+	}
+
+	// Price the routes again as they will be executed: one after the other.
+	residual = residualOffers{}
+	split := SplitPath{
+		SourceAsset:       sourceAsset,
+		DestinationAsset:  destinationAsset,
+		DestinationAmount: destinationAmount,
+	}
+	for _, r := range routes {
+		sourceAmount, err := graph.executeStrictReceive(residual, ignoreOffersFrom, r.assets, r.amount)
+		if err == errNoLiquidity {
+			return SplitPath{}, false, nil
+		} else if err != nil {
+			return SplitPath{}, false, err
+		}
+		split.SourceAmount += sourceAmount
+		split.Paths = append(split.Paths, Path{
+			SourceAsset:       sourceAsset,
+			SourceAmount:      sourceAmount,
+			DestinationAsset:  destinationAsset,
+			DestinationAmount: r.amount,
+			InteriorNodes:     r.assets[1 : len(r.assets)-1],
+		})
+	}
+	return split, true, nil
+}
+
+func (graph *OrderBookGraph) splitStrictSend(
+	maxPathLength int,
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+	maxSplits int,
+) (SplitPath, bool, error) {
+	if sourceAsset.Equals(destinationAsset) {
+		return direct(sourceAsset, amountToSpend), true, nil
+	}
+
+	residual := residualOffers{}
+	routes := []route{}
+	for _, part := range chunks(amountToSpend) {
+		searchState := &buyingGraphSearchState{
+			graph:             graph,
+			sourceAsset:       sourceAsset,
+			sourceAssetAmount: part,
+			targetAssets:      map[string]bool{destinationAsset.String(): true},
+			paths:             []Path{},
+			residual:          residual,
+		}
+		err := dfs(
+			searchState,
+			maxPathLength,
+			map[string]bool{},
+			[]xdr.Asset{},
+			sourceAsset.String(),
+			sourceAsset,
+			part,
+		)
+		if err != nil {
+			return SplitPath{}, false, err
+		}
+
+		candidates := allowed(searchState.paths, routes, maxSplits, pathAssets)
+		if len(candidates) == 0 {
+			return SplitPath{}, false, nil
+		}
+		best := 0
+		for i := range candidates {
+			if compareDestinationAsset(candidates, i, best) {
+				best = i
+			}
+		}
+
+		assets := pathAssets(candidates[best])
+		_, err = graph.executeStrictSend(residual, assets, part)
+		if err == errNoLiquidity {
+			return SplitPath{}, false, nil
+		} else if err != nil {
+			return SplitPath{}, false, err
+		}
+		routes = allocate(routes, assets, part)
+	}
+
+	residual = residualOffers{}
+	split := SplitPath{
+		SourceAsset:      sourceAsset,
+		SourceAmount:     amountToSpend,
+		DestinationAsset: destinationAsset,
+	}
+	for _, r := range routes {
+		destinationAmount, err := graph.executeStrictSend(residual, r.assets, r.amount)
+		if err == errNoLiquidity {
+			return SplitPath{}, false, nil
+		} else if err != nil {
+			return SplitPath{}, false, err
+		}
+		split.DestinationAmount += destinationAmount
+		split.Paths = append(split.Paths, Path{
+			SourceAsset:       sourceAsset,
+			SourceAmount:      r.amount,
+			DestinationAsset:  destinationAsset,
+			DestinationAmount: destinationAmount,
+			InteriorNodes:     r.assets[1 : len(r.assets)-1],
+		})
+	}
+	return split, true, nil
+}
+
+var errNoLiquidity = errors.New("not enough liquidity to execute path")
+
+// executeStrictReceive crosses the offers needed to deliver
+// `destinationAmount` through assets, which go from the source asset to the
+// destination asset, and returns the source amount required. The consumed
+// amounts are recorded in residual.
+func (graph *OrderBookGraph) executeStrictReceive(
+	residual residualOffers,
+	ignoreOffersFrom *xdr.AccountId,
+	assets []xdr.Asset,
+	destinationAmount xdr.Int64,
+) (xdr.Int64, error) {
+	fills := residualOffers{}
+	fill := func(offer xdr.OfferEntry, sold, bought xdr.Int64) {
+		fills[offer.OfferId] += sold
+	}
+
+	amount := destinationAmount
+	for i := len(assets) - 1; i > 0; i-- {
+		offers := graph.edgesForSellingAsset[assets[i].String()][assets[i-1].String()]
+		if offers = residual.apply(offers); len(offers) == 0 {
+			return -1, errNoLiquidity
+		}
+		needed, err := fillOffersForSellingAsset(offers, ignoreOffersFrom, amount, fill)
+		if err != nil {
+			return -1, err
+		}
+		if needed <= 0 {
+			return -1, errNoLiquidity
+		}
+		amount = needed
+	}
+
+	for offerID, sold := range fills {
+		residual[offerID] += sold
+	}
+	return amount, nil
+}
+
+// executeStrictSend crosses the offers needed to spend `sourceAmount` through
+// assets, which go from the source asset to the destination asset, and
+// returns the destination amount delivered. The consumed amounts are recorded
+// in residual.
+func (graph *OrderBookGraph) executeStrictSend(
+	residual residualOffers,
+	assets []xdr.Asset,
+	sourceAmount xdr.Int64,
+) (xdr.Int64, error) {
+	fills := residualOffers{}
+	fill := func(offer xdr.OfferEntry, sold, bought xdr.Int64) {
+		fills[offer.OfferId] += sold
+	}
+
+	amount := sourceAmount
+	for i := 0; i < len(assets)-1; i++ {
+		offers := graph.edgesForBuyingAsset[assets[i].String()][assets[i+1].String()]
+		if offers = residual.apply(offers); len(offers) == 0 {
+			return -1, errNoLiquidity
+		}
+		received, err := fillOffersForBuyingAsset(offers, amount, fill)
+		if err != nil {
+			return -1, err
+		}
+		if received <= 0 {
+			return -1, errNoLiquidity
+		}
+		amount = received
+	}
+
+	for offerID, sold := range fills {
+		residual[offerID] += sold
+	}
+	return amount, nil
+}
@@ -0,0 +1,255 @@
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+func splitTestOffer(id int64, selling, buying xdr.Asset, n, d int32, amount int64) xdr.OfferEntry {
+	return xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  xdr.Int64(id),
+		Selling:  selling,
+		Buying:   buying,
+		Price:    xdr.Price{N: xdr.Int32(n), D: xdr.Int32(d)},
+		Amount:   xdr.Int64(amount),
+	}
+}
+
+// splitTestGraph returns a graph where usd can be bought with native
+// directly, 50 units at a price of 1 and then at a price of 3, or through eur
+// at a price of 2.
+func splitTestGraph(t *testing.T) *OrderBookGraph {
+	graph := NewOrderBookGraph()
+	graph.AddOffer(splitTestOffer(1, usdAsset, nativeAsset, 1, 1, 50))
+	graph.AddOffer(splitTestOffer(2, usdAsset, nativeAsset, 3, 1, 1000))
+	graph.AddOffer(splitTestOffer(3, eurAsset, nativeAsset, 1, 1, 1000))
+	graph.AddOffer(splitTestOffer(4, usdAsset, eurAsset, 2, 1, 1000))
+	if err := graph.Apply(1); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return graph
+}
+
+func assertSplitPathEquals(t *testing.T, a, b SplitPath) {
+	if !a.SourceAsset.Equals(b.SourceAsset) || a.SourceAmount != b.SourceAmount {
+		t.Fatalf("expected source %v %v but got %v %v", a.SourceAmount, a.SourceAsset, b.SourceAmount, b.SourceAsset)
+	}
+	if !a.DestinationAsset.Equals(b.DestinationAsset) || a.DestinationAmount != b.DestinationAmount {
+		t.Fatalf(
+			"expected destination %v %v but got %v %v",
+			a.DestinationAmount, a.DestinationAsset, b.DestinationAmount, b.DestinationAsset,
+		)
+	}
+	assertPathEquals(t, a.Paths, b.Paths)
+}
+
+func TestResidualOffers(t *testing.T) {
+	offers := []xdr.OfferEntry{
+		splitTestOffer(1, usdAsset, nativeAsset, 1, 1, 50),
+		splitTestOffer(2, usdAsset, nativeAsset, 2, 1, 50),
+		splitTestOffer(3, usdAsset, nativeAsset, 3, 1, 50),
+	}
+
+	var none residualOffers
+	if adjusted := none.apply(offers); &adjusted[0] != &offers[0] {
+		t.Fatalf("expected offers to be returned as is")
+	}
+	if adjusted := (residualOffers{4: 10}).apply(offers); &adjusted[0] != &offers[0] {
+		t.Fatalf("expected offers to be returned as is")
+	}
+
+	adjusted := residualOffers{1: 50, 2: 20}.apply(offers)
+	if len(adjusted) != 2 {
+		t.Fatalf("expected 2 offers but got %v", len(adjusted))
+	}
+	if adjusted[0].OfferId != 2 || adjusted[0].Amount != 30 {
+		t.Fatalf("unexpected offer %v", adjusted[0])
+	}
+	if adjusted[1].OfferId != 3 || adjusted[1].Amount != 50 {
+		t.Fatalf("unexpected offer %v", adjusted[1])
+	}
+	if offers[1].Amount != 50 {
+		t.Fatalf("expected original offers to be unchanged")
+	}
+}
+
+func TestFindSplitPaths(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	splits, lastLedger, err := graph.FindSplitPaths(
+		3,
+		usdAsset,
+		100,
+		nil,
+		[]xdr.Asset{nativeAsset, yenAsset},
+		[]xdr.Int64{0, 0},
+		false,
+		2,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if lastLedger != 1 {
+		t.Fatalf("expected last ledger to be %v but got %v", 1, lastLedger)
+	}
+	if len(splits) != 1 {
+		t.Fatalf("expected 1 split path but got %v", len(splits))
+	}
+	assertSplitPathEquals(t, SplitPath{
+		SourceAsset:       nativeAsset,
+		SourceAmount:      150,
+		DestinationAsset:  usdAsset,
+		DestinationAmount: 100,
+		Paths: []Path{
+			{
+				SourceAsset:       nativeAsset,
+				SourceAmount:      50,
+				InteriorNodes:     []xdr.Asset{},
+				DestinationAsset:  usdAsset,
+				DestinationAmount: 50,
+			},
+			{
+				SourceAsset:       nativeAsset,
+				SourceAmount:      100,
+				InteriorNodes:     []xdr.Asset{eurAsset},
+				DestinationAsset:  usdAsset,
+				DestinationAmount: 50,
+			},
+		},
+	}, splits[0])
+
+	// a single path is as expensive as buying everything directly
+	splits, _, err = graph.FindSplitPaths(
+		3,
+		usdAsset,
+		100,
+		nil,
+		[]xdr.Asset{nativeAsset},
+		[]xdr.Int64{0},
+		false,
+		1,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(splits) != 1 || len(splits[0].Paths) != 1 || splits[0].SourceAmount != 200 {
+		t.Fatalf("unexpected split paths %v", splits)
+	}
+
+	// the source balance is not enough to pay for the split payment
+	splits, _, err = graph.FindSplitPaths(
+		3,
+		usdAsset,
+		100,
+		nil,
+		[]xdr.Asset{nativeAsset},
+		[]xdr.Int64{149},
+		true,
+		2,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(splits) != 0 {
+		t.Fatalf("expected no split paths but got %v", splits)
+	}
+}
+
+func TestFindFixedSplitPaths(t *testing.T) {
+	graph := splitTestGraph(t)
+
+	splits, lastLedger, err := graph.FindFixedSplitPaths(
+		3,
+		nativeAsset,
+		150,
+		[]xdr.Asset{usdAsset, nativeAsset, yenAsset},
+		2,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if lastLedger != 1 {
+		t.Fatalf("expected last ledger to be %v but got %v", 1, lastLedger)
+	}
+	if len(splits) != 2 {
+		t.Fatalf("expected 2 split paths but got %v", len(splits))
+	}
+	// the amount is split in parts of 7 or 8 units so the direct path also
+	// takes part of the more expensive offer
+	assertSplitPathEquals(t, SplitPath{
+		SourceAsset:       nativeAsset,
+		SourceAmount:      150,
+		DestinationAsset:  usdAsset,
+		DestinationAmount: 99,
+		Paths: []Path{
+			{
+				SourceAsset:       nativeAsset,
+				SourceAmount:      56,
+				InteriorNodes:     []xdr.Asset{},
+				DestinationAsset:  usdAsset,
+				DestinationAmount: 52,
+			},
+			{
+				SourceAsset:       nativeAsset,
+				SourceAmount:      94,
+				InteriorNodes:     []xdr.Asset{eurAsset},
+				DestinationAsset:  usdAsset,
+				DestinationAmount: 47,
+			},
+		},
+	}, splits[0])
+	assertSplitPathEquals(t, direct(nativeAsset, 150), splits[1])
+}
+
+func TestSplitPathsRunOutOfLiquidity(t *testing.T) {
+	// only the first parts of the payment can be filled
+	graph := NewOrderBookGraph()
+	graph.AddOffer(splitTestOffer(1, usdAsset, nativeAsset, 1, 1, 50))
+	if err := graph.Apply(1); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	splits, _, err := graph.FindSplitPaths(
+		3,
+		usdAsset,
+		100,
+		nil,
+		[]xdr.Asset{nativeAsset},
+		[]xdr.Int64{0},
+		false,
+		2,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(splits) != 0 {
+		t.Fatalf("expected no split paths but got %v", splits)
+	}
+
+	splits, _, err = graph.FindFixedSplitPaths(
+		3,
+		nativeAsset,
+		100,
+		[]xdr.Asset{usdAsset},
+		2,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(splits) != 0 {
+		t.Fatalf("expected no split paths but got %v", splits)
+	}
+
+	// executing a route whose offers have been consumed by the previous parts
+	// fails with errNoLiquidity, which is not a search error
+	residual := residualOffers{1: 50}
+	assets := []xdr.Asset{nativeAsset, usdAsset}
+	if _, err := graph.executeStrictReceive(residual, nil, assets, 10); err != errNoLiquidity {
+		t.Fatalf("expected errNoLiquidity but got %v", err)
+	}
+	if _, err := graph.executeStrictSend(residual, assets, 10); err != errNoLiquidity {
+		t.Fatalf("expected errNoLiquidity but got %v", err)
+	}
+}
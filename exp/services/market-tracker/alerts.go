@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	alertFiring   = "firing"
+	alertResolved = "resolved"
+)
+
+// marketSnapshot holds the state of the market of a trade pair the alerting rules
+// are evaluated against. The USD orders are only available for XLM-based pairs.
+type marketSnapshot struct {
+	spreadPct    float64
+	usdBids      []usdOrder
+	usdAsks      []usdOrder
+	fairValuePct float64
+	hasUsdOrders bool
+}
+
+// alert is a notification of a rule starting or ceasing to hold for a trade pair.
+type alert struct {
+	Rule      string    `json:"rule"`
+	TradePair string    `json:"tradePair"`
+	Metric    string    `json:"metric"`
+	Depth     float64   `json:"depth"`
+	Threshold float64   `json:"threshold"`
+	Value     float64   `json:"value"`
+	Status    string    `json:"status"`
+	Since     time.Time `json:"since"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (a alert) String() string {
+	return fmt.Sprintf(
+		"[%s] %s on %s: %s at depth %.0f is %.4f%% (threshold %.4f%%) since %s",
+		a.Status, a.Rule, a.TradePair, a.Metric, a.Depth, a.Value, a.Threshold, a.Since.Format(time.RFC3339),
+	)
+}
+
+// alertNotifier delivers alerts.
+type alertNotifier interface {
+	notify(a alert) error
+}
+
+// logNotifier prints alerts to stdout.
+type logNotifier struct{}
+
+func (logNotifier) notify(a alert) error {
+	fmt.Printf("alert: %s\n", a)
+	return nil
+}
+
+// webhookNotifier posts alerts as JSON to a URL.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (n webhookNotifier) notify(a alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("got status code %d from webhook", resp.StatusCode)
+	}
+	return nil
+}
+
+// ruleState tracks a rule for a trade pair across evaluations.
+type ruleState struct {
+	// breachedSince is when the rule started to hold, or the zero time if it does not.
+	breachedSince time.Time
+	// notified is when the firing alert was last sent, or the zero time if it was not.
+	notified time.Time
+}
+
+// alerter evaluates the alerting rules and sends the resulting alerts, deduplicating
+// them so that a rule holding for a trade pair is only sent once per repeat interval,
+// followed by a single resolved alert once the rule stops holding.
+type alerter struct {
+	rules          []AlertRule
+	notifier       alertNotifier
+	repeatInterval time.Duration
+
+	mu     sync.Mutex
+	states map[string]*ruleState
+}
+
+func newAlerter(cfg AlertsConfig) *alerter {
+	var notifier alertNotifier = logNotifier{}
+	if cfg.WebhookURL != "" {
+		notifier = webhookNotifier{url: cfg.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+	}
+	return &alerter{
+		rules:          cfg.Rules,
+		notifier:       notifier,
+		repeatInterval: time.Duration(cfg.RepeatIntervalMinutes) * time.Minute,
+		states:         make(map[string]*ruleState),
+	}
+}
+
+func validateAlertRule(rule AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("alert rules must have a name")
+	}
+	switch rule.Metric {
+	case "spread", "fairValue":
+	case "bidSlippage", "askSlippage":
+		if rule.Depth <= 0 {
+			return fmt.Errorf("alert rule %s: slippage rules require a positive depth", rule.Name)
+		}
+	default:
+		return fmt.Errorf("alert rule %s: unrecognized metric %q", rule.Name, rule.Metric)
+	}
+	if rule.ForMinutes < 0 || rule.Depth < 0 {
+		return fmt.Errorf("alert rule %s: forMinutes and depth cannot be negative", rule.Name)
+	}
+	return nil
+}
+
+// evaluate checks the rules applying to the trade pair against its market
+// snapshot, and sends the alerts of the rules which changed state.
+func (a *alerter) evaluate(tp TradePair, snapshot marketSnapshot, now time.Time) {
+	if a == nil {
+		return
+	}
+
+	for _, rule := range a.rules {
+		if rule.TradePair != "" && rule.TradePair != tp.String() {
+			continue
+		}
+		value, ok := ruleValue(rule, snapshot)
+		if !ok {
+			continue
+		}
+
+		if al, send := a.update(rule, tp, value, now); send {
+			if err := a.notifier.notify(al); err != nil {
+				fmt.Printf("error while sending alert %s: %s\n", al, err)
+			}
+		}
+	}
+}
+
+// update records the latest value of a rule for a trade pair, and returns the
+// alert to send, if any.
+func (a *alerter) update(rule AlertRule, tp TradePair, value float64, now time.Time) (alert, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := rule.Name + "|" + tp.String()
+	state, ok := a.states[key]
+	if !ok {
+		state = &ruleState{}
+		a.states[key] = state
+	}
+
+	al := alert{
+		Rule:      rule.Name,
+		TradePair: tp.String(),
+		Metric:    rule.Metric,
+		Depth:     rule.Depth,
+		Threshold: rule.Threshold,
+		Value:     value,
+		Since:     state.breachedSince,
+		Timestamp: now,
+	}
+
+	if value <= rule.Threshold {
+		resolved := !state.notified.IsZero()
+		*state = ruleState{}
+		al.Status = alertResolved
+		return al, resolved
+	}
+
+	if state.breachedSince.IsZero() {
+		state.breachedSince = now
+		al.Since = now
+	}
+	if now.Sub(state.breachedSince) < time.Duration(rule.ForMinutes)*time.Minute {
+		return al, false
+	}
+	if !state.notified.IsZero() && (a.repeatInterval == 0 || now.Sub(state.notified) < a.repeatInterval) {
+		return al, false
+	}
+
+	state.notified = now
+	al.Status = alertFiring
+	return al, true
+}
+
+// ruleValue returns the value of the metric of a rule, in percent. ok is false if
+// the metric cannot be computed for the snapshot.
+func ruleValue(rule AlertRule, s marketSnapshot) (value float64, ok bool) {
+	if !s.hasUsdOrders && (rule.Depth > 0 || rule.Metric != "spread") {
+		return 0, false
+	}
+
+	switch rule.Metric {
+	case "spread":
+		if rule.Depth == 0 {
+			return s.spreadPct, true
+		}
+		return calcSpreadPctAtDepth(s.usdBids, s.usdAsks, rule.Depth), true
+	case "bidSlippage":
+		return calcSlippageAtDepth(s.usdBids, s.usdAsks, rule.Depth, true), true
+	case "askSlippage":
+		return calcSlippageAtDepth(s.usdBids, s.usdAsks, rule.Depth, false), true
+	case "fairValue":
+		return s.fairValuePct, true
+	}
+	return 0, false
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	alerts []alert
+}
+
+func (n *recordingNotifier) notify(a alert) error {
+	n.alerts = append(n.alerts, a)
+	return nil
+}
+
+var alertTP = TradePair{
+	BuyingAsset:  Asset{Code: "USD", IssuerName: "AnchorUSD"},
+	SellingAsset: Asset{Code: "XLM", IssuerAddress: "native"},
+}
+
+func TestAlerterSpreadForDuration(t *testing.T) {
+	n := &recordingNotifier{}
+	a := newAlerter(AlertsConfig{
+		Rules: []AlertRule{{Name: "wide-spread", Metric: "spread", Threshold: 5, ForMinutes: 10}},
+	})
+	a.notifier = n
+
+	start := time.Unix(pts, 0)
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, start)
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 7}, start.Add(5*time.Minute))
+	assert.Empty(t, n.alerts, "rule did not hold for long enough")
+
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 8}, start.Add(10*time.Minute))
+	require.Len(t, n.alerts, 1)
+	assert.Equal(t, alert{
+		Rule:      "wide-spread",
+		TradePair: "USD:AnchorUSD / XLM:native",
+		Metric:    "spread",
+		Threshold: 5,
+		Value:     8,
+		Status:    alertFiring,
+		Since:     start,
+		Timestamp: start.Add(10 * time.Minute),
+	}, n.alerts[0])
+
+	// firing alerts are deduplicated
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 9}, start.Add(20*time.Minute))
+	assert.Len(t, n.alerts, 1)
+
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 1}, start.Add(21*time.Minute))
+	require.Len(t, n.alerts, 2)
+	assert.Equal(t, alertResolved, n.alerts[1].Status)
+	assert.Equal(t, start, n.alerts[1].Since)
+
+	// a breach restarts the duration, and resolving an alert which was not sent is silent
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, start.Add(22*time.Minute))
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 1}, start.Add(23*time.Minute))
+	assert.Len(t, n.alerts, 2)
+}
+
+func TestAlerterRepeatInterval(t *testing.T) {
+	n := &recordingNotifier{}
+	a := newAlerter(AlertsConfig{
+		Rules:                 []AlertRule{{Name: "wide-spread", Metric: "spread", Threshold: 5}},
+		RepeatIntervalMinutes: 30,
+	})
+	a.notifier = n
+
+	start := time.Unix(pts, 0)
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, start)
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, start.Add(29*time.Minute))
+	assert.Len(t, n.alerts, 1)
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, start.Add(30*time.Minute))
+	assert.Len(t, n.alerts, 2)
+}
+
+func TestAlerterRuleSelection(t *testing.T) {
+	n := &recordingNotifier{}
+	a := newAlerter(AlertsConfig{
+		Rules: []AlertRule{
+			{Name: "other-pair", TradePair: "EUR:AnchorEUR / XLM:native", Metric: "spread", Threshold: 1},
+			{Name: "ask-slippage", Metric: "askSlippage", Depth: 10, Threshold: 5},
+		},
+	})
+	a.notifier = n
+
+	// depth-based metrics are not available without USD orders
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 50}, time.Unix(pts, 0))
+	assert.Empty(t, n.alerts)
+
+	snapshot := marketSnapshot{
+		spreadPct:    25,
+		usdBids:      []usdOrder{lowUsdOrder},
+		usdAsks:      []usdOrder{highUsdOrder, {usdAmount: 10., usdPrice: 35.}},
+		hasUsdOrders: true,
+	}
+	a.evaluate(alertTP, snapshot, time.Unix(pts, 0))
+	require.Len(t, n.alerts, 1)
+	assert.Equal(t, "ask-slippage", n.alerts[0].Rule)
+	assert.Equal(t, calcSlippageAtDepth(snapshot.usdBids, snapshot.usdAsks, 10, false), n.alerts[0].Value)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	a := newAlerter(AlertsConfig{
+		Rules:      []AlertRule{{Name: "wide-spread", Metric: "spread", Threshold: 5}},
+		WebhookURL: server.URL,
+	})
+	a.evaluate(alertTP, marketSnapshot{spreadPct: 6}, time.Unix(pts, 0))
+	assert.Equal(t, "wide-spread", got.Rule)
+	assert.Equal(t, alertFiring, got.Status)
+	assert.Equal(t, 6., got.Value)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err := webhookNotifier{url: failing.URL, client: http.DefaultClient}.notify(got)
+	assert.EqualError(t, err, "got status code 500 from webhook")
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	assert.NoError(t, validateAlertRule(AlertRule{Name: "spread", Metric: "spread"}))
+	assert.EqualError(t, validateAlertRule(AlertRule{Metric: "spread"}), "alert rules must have a name")
+	assert.EqualError(t, validateAlertRule(AlertRule{Name: "r", Metric: "volume"}), `alert rule r: unrecognized metric "volume"`)
+	assert.EqualError(t, validateAlertRule(AlertRule{Name: "r", Metric: "bidSlippage"}), "alert rule r: slippage rules require a positive depth")
+	assert.EqualError(t, validateAlertRule(AlertRule{Name: "r", Metric: "spread", ForMinutes: -1}), "alert rule r: forMinutes and depth cannot be negative")
+}
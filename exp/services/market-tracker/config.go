@@ -37,10 +37,53 @@ func (tp TradePair) String() string {
 	return fmt.Sprintf("%s / %s", tp.BuyingAsset, tp.SellingAsset)
 }
 
+// PriceSourceConfig selects the source of the USD price of XLM.
+// Type is one of "stellarExpert" (the default), "horizon", "ticker", "static" or "csv".
+type PriceSourceConfig struct {
+	Type string `json:"type"`
+	// QuoteAsset is the USD asset XLM is priced against by the horizon and ticker sources.
+	QuoteAsset Asset `json:"quoteAsset"`
+	// DatabaseURL is the URL of the ticker database used by the ticker source.
+	DatabaseURL string `json:"databaseURL"`
+	// Price is the constant price returned by the static source.
+	Price float64 `json:"price"`
+	// CSVPath is the path of the "timestamp,price" file read by the csv source.
+	CSVPath string `json:"csvPath"`
+}
+
+// AlertRule defines a condition on a metric of the watched trade pairs which triggers
+// an alert once it holds for ForMinutes minutes.
+type AlertRule struct {
+	Name string `json:"name"`
+	// TradePair restricts the rule to the trade pair with the given name
+	// (e.g. "USD:AnchorUSD / XLM:native"). The rule applies to every pair if empty.
+	TradePair string `json:"tradePair"`
+	// Metric is one of "spread", "bidSlippage", "askSlippage" or "fairValue",
+	// all expressed in percent.
+	Metric string `json:"metric"`
+	// Depth is the market depth, in USD, the spread or slippage is computed at.
+	// A zero depth means the top of the orderbook for the spread.
+	Depth      float64 `json:"depth"`
+	Threshold  float64 `json:"threshold"`
+	ForMinutes int64   `json:"forMinutes"`
+}
+
+// AlertsConfig defines the alerting rules and where alerts are sent.
+type AlertsConfig struct {
+	Rules []AlertRule `json:"rules"`
+	// WebhookURL is the URL alerts are posted to as JSON. Alerts are logged if empty.
+	WebhookURL string `json:"webhookURL"`
+	// RepeatIntervalMinutes is the interval at which an alert which is still firing is
+	// sent again. Firing alerts are only sent once if zero.
+	RepeatIntervalMinutes int64 `json:"repeatIntervalMinutes"`
+}
+
 // Config represents the overall config of the application
 type Config struct {
-	TradePairs           []TradePair `json:"tradePairs"`
-	CheckIntervalSeconds int64       `json:"checkIntervalSeconds"`
+	TradePairs           []TradePair       `json:"tradePairs"`
+	CheckIntervalSeconds int64             `json:"checkIntervalSeconds"`
+	PriceSource          PriceSourceConfig `json:"priceSource"`
+	Alerts               AlertsConfig      `json:"alerts"`
 }
 
 func computeAssetType(a *Asset) (err error) {
@@ -64,7 +107,11 @@ func check(err error) {
 }
 
 func loadConfig() Config {
-	configFile, err := os.Open("config_sample.json")
+	// config.json is used if it exists, falling back to the sample config.
+	configFile, err := os.Open("config.json")
+	if os.IsNotExist(err) {
+		configFile, err = os.Open("config_sample.json")
+	}
 	check(err)
 
 	defer configFile.Close()
@@ -83,5 +130,15 @@ func loadConfig() Config {
 		check(err)
 	}
 
+	if config.PriceSource.QuoteAsset.AssetType != "" {
+		err = computeAssetType(&config.PriceSource.QuoteAsset)
+		check(err)
+	}
+
+	for _, rule := range config.Alerts.Rules {
+		err = validateAlertRule(rule)
+		check(err)
+	}
+
 	return config
 }
@@ -5,6 +5,7 @@ go 1.13
 require (
 	github.com/cheekybits/is v0.0.0-20150225183255-68e9c0620927 // indirect
 	github.com/joho/godotenv v1.3.0
+	github.com/lib/pq v1.2.0
 	github.com/matryer/try v0.0.0-20161228173917-9ac251b645a2 // indirect
 	github.com/prometheus/client_golang v0.9.3-0.20190127221311-3c4408c8b829
 	github.com/stellar/go v0.0.0-20200210221749-6c651ebf3c29
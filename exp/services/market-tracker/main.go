@@ -22,9 +22,12 @@ var watchedTradePairs []prometheusWatchedTP
 func main() {
 	cfg := loadConfig()
 	c := trackerClient{hClient.DefaultPublicNetClient}
+	src, err := newPriceSource(cfg.PriceSource, c)
+	check(err)
+
 	watchedTPs := configPrometheusWatchers(cfg.TradePairs)
-	trackSpreads(cfg, c, &watchedTPs)
-	trackVolumes(cfg, c, &watchedTPs)
+	trackSpreads(cfg, c, src, newAlerter(cfg.Alerts), &watchedTPs)
+	trackVolumes(cfg, c, src, &watchedTPs)
 
 	http.Handle("/metrics", promhttp.Handler())
 	http.ListenAndServe(":2112", nil)
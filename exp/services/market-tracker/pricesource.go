@@ -0,0 +1,253 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq" // the ticker database is a PostgreSQL database
+	hClient "github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+)
+
+// PriceSource provides the USD price of XLM.
+type PriceSource interface {
+	// LatestPrice returns the most recent price.
+	LatestPrice() (float64, error)
+	// PriceHistory returns the prices between start and end, sorted by decreasing
+	// timestamp (i.e. most recent first).
+	PriceHistory(start, end time.Time) ([]xlmPrice, error)
+}
+
+// historyResolution is the resolution of the price histories
+// fetched from Horizon and the ticker.
+const historyResolution = 15 * time.Minute
+
+var xlmAsset = Asset{
+	ProtocolAssetType: hClient.AssetTypeNative,
+	AssetType:         "AssetTypeNative",
+	Code:              "XLM",
+	IssuerAddress:     "native",
+}
+
+func newPriceSource(cfg PriceSourceConfig, c trackerClient) (PriceSource, error) {
+	switch cfg.Type {
+	case "", "stellarExpert":
+		return stellarExpertPriceSource{req: mustCreateXlmPriceRequest()}, nil
+	case "horizon":
+		if cfg.QuoteAsset.Code == "" {
+			return nil, fmt.Errorf("horizon price source requires a quote asset")
+		}
+		return horizonPriceSource{client: c, quoteAsset: cfg.QuoteAsset}, nil
+	case "ticker":
+		if cfg.QuoteAsset.Code == "" || cfg.DatabaseURL == "" {
+			return nil, fmt.Errorf("ticker price source requires a quote asset and a database url")
+		}
+		db, err := sql.Open("postgres", cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not open ticker database: %s", err)
+		}
+		return tickerPriceSource{db: db, quoteAsset: cfg.QuoteAsset}, nil
+	case "static":
+		if cfg.Price <= 0 {
+			return nil, fmt.Errorf("static price source requires a positive price")
+		}
+		return staticPriceSource{price: cfg.Price}, nil
+	case "csv":
+		f, err := os.Open(cfg.CSVPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not open csv price file: %s", err)
+		}
+		defer f.Close()
+		return parseCSVPrices(f)
+	default:
+		return nil, fmt.Errorf("unrecognized price source type %q", cfg.Type)
+	}
+}
+
+// stellarExpertPriceSource gets prices from the Stellar Expert price API.
+type stellarExpertPriceSource struct {
+	req *http.Request
+}
+
+func (s stellarExpertPriceSource) LatestPrice() (float64, error) {
+	return getLatestXlmPrice(s.req)
+}
+
+func (s stellarExpertPriceSource) PriceHistory(start, end time.Time) ([]xlmPrice, error) {
+	return getXlmPriceHistory(s.req)
+}
+
+// horizonPriceSource derives prices from the Horizon trade aggregations of XLM
+// against a USD asset.
+type horizonPriceSource struct {
+	client     trackerClient
+	quoteAsset Asset
+}
+
+func (s horizonPriceSource) LatestPrice() (float64, error) {
+	end := time.Now()
+	prices, err := s.PriceHistory(end.Add(-24*time.Hour), end)
+	if err != nil {
+		return 0.0, err
+	}
+	if len(prices) == 0 {
+		return 0.0, fmt.Errorf("no trades of XLM against %s in the last day", s.quoteAsset)
+	}
+	return prices[0].price, nil
+}
+
+func (s horizonPriceSource) PriceHistory(start, end time.Time) ([]xlmPrice, error) {
+	// trade aggregations are requested with XLM as the base asset, so that
+	// prices are expressed in units of the quote asset per XLM.
+	tp := TradePair{BuyingAsset: xlmAsset, SellingAsset: s.quoteAsset}
+	taps, err := s.client.getAggTradesForTradePair(tp, start, end, historyResolution)
+	if err != nil {
+		return []xlmPrice{}, fmt.Errorf("could not get trade aggregations from horizon: %s", err)
+	}
+
+	return aggRecordsToPrices(getAggRecords(taps), historyResolution)
+}
+
+// aggRecordsToPrices converts trade aggregations of the given resolution to the
+// close prices at the end of each aggregation, in the same order.
+func aggRecordsToPrices(records []hProtocol.TradeAggregation, res time.Duration) ([]xlmPrice, error) {
+	var prices []xlmPrice
+	for _, record := range records {
+		p, err := strconv.ParseFloat(record.Close, 64)
+		if err != nil {
+			return []xlmPrice{}, err
+		}
+		ts := record.Timestamp/1000 + int64(res/time.Second) // timestamps are milliseconds since epoch time
+		prices = append(prices, xlmPrice{timestamp: ts, price: p})
+	}
+	return prices, nil
+}
+
+// tickerPriceSource reads prices from the database of a Stellar ticker, using its
+// trades for the latest price and its candles for the price history.
+type tickerPriceSource struct {
+	db         *sql.DB
+	quoteAsset Asset
+}
+
+// tickerMarketJoin joins the base and counter assets of the ticker trades and
+// candles (aliased t) to select the XLM market of the quote asset. The ticker
+// always stores XLM as the base asset of its markets.
+const tickerMarketJoin = `
+	JOIN assets b ON b.id = t.base_asset_id
+	JOIN assets c ON c.id = t.counter_asset_id
+	WHERE b.code = 'XLM' AND b.issuer_account = 'native'
+	AND c.code = $1 AND c.issuer_account = $2`
+
+func (s tickerPriceSource) LatestPrice() (float64, error) {
+	var price float64
+	err := s.db.QueryRow(
+		`SELECT t.price FROM trades t`+tickerMarketJoin+`
+		ORDER BY t.ledger_close_time DESC LIMIT 1`,
+		s.quoteAsset.Code, s.quoteAsset.IssuerAddress,
+	).Scan(&price)
+	if err == sql.ErrNoRows {
+		return 0.0, fmt.Errorf("no trades of XLM against %s in the ticker database", s.quoteAsset)
+	}
+	return price, err
+}
+
+func (s tickerPriceSource) PriceHistory(start, end time.Time) ([]xlmPrice, error) {
+	rows, err := s.db.Query(
+		`SELECT EXTRACT(epoch FROM t.open_time)::bigint + t.resolution, t.close FROM candles t`+tickerMarketJoin+`
+		AND t.resolution = $3 AND t.open_time >= $4 AND t.open_time < $5
+		ORDER BY t.open_time DESC`,
+		s.quoteAsset.Code, s.quoteAsset.IssuerAddress,
+		int64(historyResolution/time.Second), start, end,
+	)
+	if err != nil {
+		return []xlmPrice{}, fmt.Errorf("could not query ticker candles: %s", err)
+	}
+	defer rows.Close()
+
+	var prices []xlmPrice
+	for rows.Next() {
+		var p xlmPrice
+		if err = rows.Scan(&p.timestamp, &p.price); err != nil {
+			return []xlmPrice{}, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// staticPriceSource returns a constant price, or the prices of a fixed history.
+type staticPriceSource struct {
+	price float64
+	// history is sorted by decreasing timestamp. It is only used if price is zero.
+	history []xlmPrice
+}
+
+func (s staticPriceSource) LatestPrice() (float64, error) {
+	if s.price != 0 {
+		return s.price, nil
+	}
+	if len(s.history) == 0 {
+		return 0.0, fmt.Errorf("empty price history")
+	}
+	return s.history[0].price, nil
+}
+
+func (s staticPriceSource) PriceHistory(start, end time.Time) ([]xlmPrice, error) {
+	if s.price != 0 {
+		return []xlmPrice{
+			{timestamp: end.Unix(), price: s.price},
+			{timestamp: start.Unix(), price: s.price},
+		}, nil
+	}
+
+	var prices []xlmPrice
+	for _, p := range s.history {
+		if p.timestamp >= start.Unix() && p.timestamp <= end.Unix() {
+			prices = append(prices, p)
+		}
+	}
+	return prices, nil
+}
+
+// parseCSVPrices reads a price history from "timestamp,price" records, with
+// timestamps in seconds since epoch. A header record is allowed.
+func parseCSVPrices(r io.Reader) (staticPriceSource, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return staticPriceSource{}, fmt.Errorf("could not read csv prices: %s", err)
+	}
+	if len(records) > 0 && strings.EqualFold(strings.TrimSpace(records[0][0]), "timestamp") {
+		records = records[1:]
+	}
+
+	var history []xlmPrice
+	for _, record := range records {
+		ts, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 64)
+		if err != nil {
+			return staticPriceSource{}, fmt.Errorf("invalid csv timestamp %q: %s", record[0], err)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return staticPriceSource{}, fmt.Errorf("invalid csv price %q: %s", record[1], err)
+		}
+		history = append(history, xlmPrice{timestamp: ts, price: p})
+	}
+	if len(history) == 0 {
+		return staticPriceSource{}, fmt.Errorf("csv prices are empty")
+	}
+
+	sort.Slice(history, func(i, j int) bool { return history[i].timestamp > history[j].timestamp })
+	return staticPriceSource{history: history}, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticPriceSource(t *testing.T) {
+	src, err := newPriceSource(PriceSourceConfig{Type: "static", Price: 0.1}, trackerClient{})
+	assert.NoError(t, err)
+
+	price, err := src.LatestPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, price)
+
+	start := time.Unix(pts-3600, 0)
+	end := time.Unix(pts, 0)
+	history, err := src.PriceHistory(start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []xlmPrice{{timestamp: pts, price: 0.1}, {timestamp: pts - 3600, price: 0.1}}, history)
+
+	_, err = newPriceSource(PriceSourceConfig{Type: "static"}, trackerClient{})
+	assert.EqualError(t, err, "static price source requires a positive price")
+
+	_, err = newPriceSource(PriceSourceConfig{Type: "unknown"}, trackerClient{})
+	assert.EqualError(t, err, `unrecognized price source type "unknown"`)
+}
+
+func TestParseCSVPrices(t *testing.T) {
+	csv := "timestamp,price\n# oldest first\n1594665200,0.08\n1594668800, 0.09\n1594667000,0.085\n"
+	src, err := parseCSVPrices(strings.NewReader(csv))
+	assert.NoError(t, err)
+
+	price, err := src.LatestPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.09, price)
+
+	history, err := src.PriceHistory(time.Unix(1594667000, 0), time.Unix(1594668800, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, []xlmPrice{{timestamp: 1594668800, price: 0.09}, {timestamp: 1594667000, price: 0.085}}, history)
+
+	_, err = parseCSVPrices(strings.NewReader("timestamp,price\n"))
+	assert.EqualError(t, err, "csv prices are empty")
+
+	_, err = parseCSVPrices(strings.NewReader("1594665200,cheap\n"))
+	assert.Error(t, err)
+
+	_, err = parseCSVPrices(strings.NewReader("1594665200\n"))
+	assert.Error(t, err)
+}
+
+func TestAggRecordsToPrices(t *testing.T) {
+	ta1 := hProtocol.TradeAggregation{Timestamp: 1000 * pts}
+	ta1.Close = "0.09"
+	ta2 := hProtocol.TradeAggregation{Timestamp: 1000 * (pts - 900)}
+	ta2.Close = "0.08"
+
+	prices, err := aggRecordsToPrices([]hProtocol.TradeAggregation{ta1, ta2}, 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []xlmPrice{{timestamp: pts + 900, price: 0.09}, {timestamp: pts, price: 0.08}}, prices)
+
+	ta2.Close = "price"
+	_, err = aggRecordsToPrices([]hProtocol.TradeAggregation{ta1, ta2}, 15*time.Minute)
+	assert.Error(t, err)
+}
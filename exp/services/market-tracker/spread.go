@@ -34,13 +34,12 @@ type FairValue struct {
 	DexPrice prometheus.Gauge
 }
 
-func trackSpreads(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatchedTP) {
+func trackSpreads(cfg Config, c trackerClient, src PriceSource, al *alerter, watchedTPsPtr *[]prometheusWatchedTP) {
 	watchedTPs := *watchedTPsPtr
 	priceCache := createPriceCache(watchedTPs)
-	req := mustCreateXlmPriceRequest()
 	go func() {
 		for {
-			xlmPrice, err := getLatestXlmPrice(req)
+			xlmPrice, err := src.LatestPrice()
 			if err != nil {
 				fmt.Printf("error while getting latest price: %s", err)
 			}
@@ -61,6 +60,7 @@ func trackSpreads(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatche
 				// we only compute spreads at various depths for xlm-based pairs,
 				// because our usd prices are in terms of xlm.
 				if wtp.TradePair.SellingAsset.Code != "XLM" {
+					al.evaluate(wtp.TradePair, marketSnapshot{spreadPct: spreadPct}, time.Now())
 					continue
 				}
 
@@ -115,8 +115,17 @@ func trackSpreads(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatche
 				watchedTPs[i].Slippage.BidD5K.Set(calcSlippageAtDepth(usdBids, usdAsks, 5000., true))
 				watchedTPs[i].Slippage.AskD5K.Set(calcSlippageAtDepth(usdBids, usdAsks, 5000., false))
 
-				watchedTPs[i].FairValue.Percent.Set(calcFairValuePct(usdBids, usdAsks, trueAssetUsdPrice))
+				fairValuePct := calcFairValuePct(usdBids, usdAsks, trueAssetUsdPrice)
+				watchedTPs[i].FairValue.Percent.Set(fairValuePct)
 				watchedTPs[i].FairValue.RefPrice.Set(trueAssetUsdPrice)
+
+				al.evaluate(wtp.TradePair, marketSnapshot{
+					spreadPct:    spreadPct,
+					usdBids:      usdBids,
+					usdAsks:      usdAsks,
+					fairValuePct: fairValuePct,
+					hasUsdOrders: true,
+				}, time.Now())
 			}
 
 			time.Sleep(time.Duration(cfg.CheckIntervalSeconds) * time.Second)
@@ -36,25 +36,25 @@ type volumeHist struct {
 	counterVolumeUsd       float64
 }
 
-func trackVolumes(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatchedTP) {
+func trackVolumes(cfg Config, c trackerClient, src PriceSource, watchedTPsPtr *[]prometheusWatchedTP) {
 	watchedTPs := *watchedTPsPtr
-	volumeMap := initVolumes(cfg, c, watchedTPs)
+	volumeMap := initVolumes(cfg, c, src, watchedTPs)
 
 	go func() {
-		updateVolume(cfg, c, watchedTPsPtr, volumeMap)
+		updateVolume(cfg, c, src, watchedTPsPtr, volumeMap)
 	}()
 }
 
-func initVolumes(cfg Config, c trackerClient, watchedTPs []prometheusWatchedTP) map[string][]volumeHist {
-	xlmReq := mustCreateXlmPriceRequest()
-	xlmPriceHist, err := getXlmPriceHistory(xlmReq)
+func initVolumes(cfg Config, c trackerClient, src PriceSource, watchedTPs []prometheusWatchedTP) map[string][]volumeHist {
+	volumeHistMap := make(map[string][]volumeHist)
+	end := time.Now()
+	start := end.Add(time.Duration(-24 * time.Hour))
+
+	xlmPriceHist, err := src.PriceHistory(start, end)
 	if err != nil {
 		fmt.Printf("got error when getting xlm price history: %s\n", err)
 	}
 
-	volumeHistMap := make(map[string][]volumeHist)
-	end := time.Now()
-	start := end.Add(time.Duration(-24 * time.Hour))
 	res := 15 * 60                                     // resolution length, in seconds
 	cRes := time.Duration(res*1000) * time.Millisecond // horizon request must be in milliseconds
 
@@ -95,8 +95,7 @@ func initVolumes(cfg Config, c trackerClient, watchedTPs []prometheusWatchedTP)
 	return volumeHistMap
 }
 
-func updateVolume(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatchedTP, volumeHistMap map[string][]volumeHist) {
-	req := mustCreateXlmPriceRequest()
+func updateVolume(cfg Config, c trackerClient, src PriceSource, watchedTPsPtr *[]prometheusWatchedTP, volumeHistMap map[string][]volumeHist) {
 	historyUnit := time.Duration(15 * 60 * time.Second) // length of each individual unit of volume history
 	cRes := time.Duration(60*1000) * time.Millisecond   // horizon client requests have a 1 minute resolution, in milliseconds
 	day := time.Duration(24 * 60 * 60 * time.Second)    // number of seconds in a day
@@ -107,7 +106,7 @@ func updateVolume(cfg Config, c trackerClient, watchedTPsPtr *[]prometheusWatche
 	for {
 		time.Sleep(historyUnit - forLoopDuration) // wait before starting the update
 
-		xlmUsdPrice, err := getLatestXlmPrice(req)
+		xlmUsdPrice, err := src.LatestPrice()
 		if err != nil {
 			fmt.Printf("error while getting latest price: %s", err)
 		}
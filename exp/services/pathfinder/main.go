@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/exp/orderbook/ingest"
+	"github.com/stellar/go/exp/orderbook/remote"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/config"
+	supporthttp "github.com/stellar/go/support/http"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func main() {
+	var port, maxPathLength int
+	var networkPassphrase, binaryPath, configPath, remoteCaptiveCoreURL string
+	var historyArchiveURLs []string
+	var logLevel logrus.Level
+	logger := supportlog.New()
+
+	configOpts := config.ConfigOptions{
+		{
+			Name:        "port",
+			Usage:       "Port to listen and serve on",
+			OptType:     types.Int,
+			ConfigKey:   &port,
+			FlagDefault: 8000,
+			Required:    true,
+		},
+		{
+			Name:        "network-passphrase",
+			Usage:       "Network passphrase of the Stellar network",
+			OptType:     types.String,
+			ConfigKey:   &networkPassphrase,
+			FlagDefault: network.TestNetworkPassphrase,
+			Required:    true,
+		},
+		&config.ConfigOption{
+			Name:        "stellar-core-binary-path",
+			OptType:     types.String,
+			FlagDefault: "",
+			Required:    false,
+			Usage:       "path to stellar core binary, required unless remote-captive-core-url is set",
+			ConfigKey:   &binaryPath,
+		},
+		&config.ConfigOption{
+			Name:        "stellar-core-config-path",
+			OptType:     types.String,
+			FlagDefault: "",
+			Required:    false,
+			Usage:       "path to stellar core config file",
+			ConfigKey:   &configPath,
+		},
+		&config.ConfigOption{
+			Name:        "remote-captive-core-url",
+			OptType:     types.String,
+			FlagDefault: "",
+			Required:    false,
+			Usage:       "url of a remote captive core server to stream ledgers from instead of running stellar core",
+			ConfigKey:   &remoteCaptiveCoreURL,
+		},
+		&config.ConfigOption{
+			Name:        "history-archive-urls",
+			ConfigKey:   &historyArchiveURLs,
+			OptType:     types.String,
+			Required:    true,
+			FlagDefault: "",
+			CustomSetValue: func(co *config.ConfigOption) {
+				stringOfUrls := viper.GetString(co.Name)
+				urlStrings := strings.Split(stringOfUrls, ",")
+
+				*(co.ConfigKey.(*[]string)) = urlStrings
+			},
+			Usage: "comma-separated list of stellar history archives to connect with",
+		},
+		&config.ConfigOption{
+			Name:        "max-path-length",
+			OptType:     types.Int,
+			FlagDefault: 5,
+			Required:    false,
+			Usage:       "maximum length of the payment paths which can be queried",
+			ConfigKey:   &maxPathLength,
+		},
+		&config.ConfigOption{
+			Name:        "log-level",
+			ConfigKey:   &logLevel,
+			OptType:     types.String,
+			FlagDefault: "info",
+			CustomSetValue: func(co *config.ConfigOption) {
+				ll, err := logrus.ParseLevel(viper.GetString(co.Name))
+				if err != nil {
+					logger.Fatalf("Could not parse log-level: %v", viper.GetString(co.Name))
+				}
+				*(co.ConfigKey.(*logrus.Level)) = ll
+			},
+			Usage: "minimum log severity (debug, info, warn, error) to log",
+		},
+	}
+	newIngester := func(ctx context.Context) *ingest.Ingester {
+		var backend ledgerbackend.LedgerBackend
+		var err error
+		if remoteCaptiveCoreURL != "" {
+			backend, err = ledgerbackend.NewRemoteCaptive(remoteCaptiveCoreURL)
+		} else if binaryPath != "" {
+			backend, err = ledgerbackend.NewCaptive(ledgerbackend.CaptiveCoreConfig{
+				StellarCoreBinaryPath: binaryPath,
+				StellarCoreConfigPath: configPath,
+				NetworkPassphrase:     networkPassphrase,
+				HistoryArchiveURLs:    historyArchiveURLs,
+			})
+		} else {
+			logger.Fatal("Either stellar-core-binary-path or remote-captive-core-url must be set")
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("Could not create captive core instance")
+		}
+
+		archive, err := historyarchive.Connect(
+			historyArchiveURLs[0],
+			historyarchive.ConnectOptions{
+				Context:           ctx,
+				NetworkPassphrase: networkPassphrase,
+			},
+		)
+		if err != nil {
+			logger.WithError(err).Fatal("Could not connect to history archive")
+		}
+
+		return &ingest.Ingester{
+			Graph:             orderbook.NewOrderBookGraph(),
+			LedgerBackend:     backend,
+			HistoryArchive:    archive,
+			NetworkPassphrase: networkPassphrase,
+			Log:               logger,
+			PollInterval:      time.Second,
+			RetryInterval:     5 * time.Second,
+		}
+	}
+
+	cmd := &cobra.Command{
+		Use:   "pathfinder",
+		Short: "Run the path finding server",
+		Run: func(_ *cobra.Command, _ []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+			logger.Level = logLevel
+
+			ctx, cancel := context.WithCancel(context.Background())
+			ingester := newIngester(ctx)
+			graph := ingester.Graph
+			backend := ingester.LedgerBackend
+			done := make(chan struct{})
+			go func() {
+				ingester.Run(ctx)
+				close(done)
+			}()
+
+			supporthttp.Run(supporthttp.Config{
+				ListenAddr: fmt.Sprintf(":%d", port),
+				Handler:    remote.Handler(graph, maxPathLength, logger),
+				OnStarting: func() {
+					logger.Infof("Starting path finding server on %v", port)
+				},
+				OnStopping: func() {
+					cancel()
+					<-done
+					backend.Close()
+				},
+			})
+		},
+	}
+
+	if err := configOpts.Init(cmd); err != nil {
+		logger.WithError(err).Fatal("could not parse config options")
+	}
+
+	orderBookCmd := newOrderBookCommand(logger, func() {
+		configOpts.Require()
+		configOpts.SetValues()
+		logger.Level = logLevel
+	}, newIngester)
+	cmd.AddCommand(orderBookCmd)
+
+	if err := cmd.Execute(); err != nil {
+		logger.WithError(err).Fatal("could not run")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"go/types"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/go/exp/orderbook/ingest"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+type tradingPair struct {
+	selling xdr.Asset
+	buying  xdr.Asset
+}
+
+// parseTradingPairs parses a comma separated list of trading pairs where the
+// assets of each pair are separated by a slash, e.g.
+// "EUR:GD.../native,USD:GD.../native".
+func parseTradingPairs(value string) ([]tradingPair, error) {
+	var pairs []tradingPair
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.Split(pair, "/")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("%s is not a trading pair of the form base/counter", pair)
+		}
+
+		assets, err := xdr.BuildAssets(parts[0] + "," + parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trading pair %s", pair)
+		}
+		pairs = append(pairs, tradingPair{selling: assets[0], buying: assets[1]})
+	}
+	return pairs, nil
+}
+
+func newOrderBookCommand(
+	logger *supportlog.Entry,
+	setRootValues func(),
+	newIngester func(ctx context.Context) *ingest.Ingester,
+) *cobra.Command {
+	var ledger uint32
+	var pairs string
+	var listOffers bool
+	var maxPriceLevels int
+
+	configOpts := config.ConfigOptions{
+		{
+			Name:        "ledger",
+			Usage:       "Ledger at the end of which the order book is reconstructed",
+			OptType:     types.Uint32,
+			ConfigKey:   &ledger,
+			FlagDefault: uint32(0),
+			Required:    true,
+		},
+		{
+			Name:      "pairs",
+			Usage:     "Comma-separated list of trading pairs of the form base/counter where assets are either native or code:issuer",
+			OptType:   types.String,
+			ConfigKey: &pairs,
+			Required:  true,
+		},
+		{
+			Name:        "offers",
+			Usage:       "List every offer instead of aggregating offers into price levels",
+			OptType:     types.Bool,
+			ConfigKey:   &listOffers,
+			FlagDefault: false,
+			Required:    false,
+		},
+		{
+			Name:        "max-price-levels",
+			Usage:       "Maximum number of price levels on each side of an order book",
+			OptType:     types.Int,
+			ConfigKey:   &maxPriceLevels,
+			FlagDefault: 20,
+			Required:    false,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "order-book",
+		Short: "Print the order books of trading pairs as they were at a past ledger",
+		Long: "Rebuilds the order book as it was at the end of the given ledger, starting from the " +
+			"latest history archive checkpoint at or before the ledger and replaying the offer " +
+			"changes of the subsequent ledgers, and prints the order books of the given trading " +
+			"pairs as JSON.",
+		Run: func(_ *cobra.Command, _ []string) {
+			setRootValues()
+			configOpts.Require()
+			configOpts.SetValues()
+
+			tradingPairs, err := parseTradingPairs(pairs)
+			if err != nil {
+				logger.WithError(err).Fatal("Could not parse trading pairs")
+			}
+
+			ctx := context.Background()
+			ingester := newIngester(ctx)
+			defer ingester.LedgerBackend.Close()
+			if err = ingester.ReplayTo(ctx, ledger); err != nil {
+				logger.WithError(err).Fatal("Could not reconstruct order book")
+			}
+
+			orderBooks := make([]interface{}, len(tradingPairs))
+			for i, pair := range tradingPairs {
+				if listOffers {
+					orderBooks[i], err = ingest.FindOrderBook(ingester.Graph, pair.selling, pair.buying, maxPriceLevels)
+				} else {
+					orderBooks[i], err = ingest.FindOrderBookSummary(ingester.Graph, pair.selling, pair.buying, maxPriceLevels)
+				}
+				if err != nil {
+					logger.WithError(err).Fatal("Could not find order book")
+				}
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(struct {
+				Ledger     uint32        `json:"ledger"`
+				OrderBooks []interface{} `json:"order_books"`
+			}{ledger, orderBooks})
+			if err != nil {
+				logger.WithError(err).Fatal("Could not print order books")
+			}
+		},
+	}
+
+	if err := configOpts.Init(cmd); err != nil {
+		logger.WithError(err).Fatal("could not parse config options")
+	}
+	return cmd
+}
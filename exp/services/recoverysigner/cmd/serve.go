@@ -53,10 +53,31 @@ func (c *ServeCommand) Command() *cobra.Command {
 		},
 		{
 			Name:      "signing-key",
-			Usage:     "Stellar signing key(s) used for signing transactions comma separated (first key is preferred signer) (will be deprecated with per-account keys in the future)",
+			Usage:     "Stellar signing key(s) used for signing transactions comma separated (first key is preferred signer) (will be deprecated with per-account keys in the future) (one of signing-key, signing-key-file or signing-key-url is required)",
 			OptType:   types.String,
 			ConfigKey: &opts.SigningKeys,
-			Required:  true,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-file",
+			Usage:     "Path of an encrypted file containing the Stellar signing keys used for signing transactions, managed with the keys command and reloaded when changed",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyFile,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-file-passphrase",
+			Usage:     "Passphrase the keys of the signing key file are encrypted with",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyFilePassphrase,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-url",
+			Usage:     "URL of a remote signing service holding the Stellar signing keys used for signing transactions",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyURL,
+			Required:  false,
 		},
 		{
 			Name:      "sep10-jwks",
@@ -79,9 +100,34 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey: &opts.FirebaseProjectID,
 			Required:  true,
 		},
+		{
+			Name:           "recovery-code-expires-in",
+			Usage:          "The time period in seconds after which a recovery request that has not been confirmed with the code sent to the identity expires",
+			OptType:        types.Int,
+			ConfigKey:      &opts.RecoveryCodeExpiresIn,
+			CustomSetValue: config.SetDuration,
+			FlagDefault:    600,
+			Required:       true,
+		},
+		{
+			Name:           "recovery-approval-expires-in",
+			Usage:          "The time period in seconds after a recovery request is approved that it can be used to sign transactions",
+			OptType:        types.Int,
+			ConfigKey:      &opts.RecoveryApprovalExpiresIn,
+			CustomSetValue: config.SetDuration,
+			FlagDefault:    86400,
+			Required:       true,
+		},
+		{
+			Name:      "notifier-webhook-url",
+			Usage:     "URL of a webhook that recovery codes and notifications are posted to for delivery to the identities of accounts (if not set notifications are not delivered, for development only)",
+			OptType:   types.String,
+			ConfigKey: &opts.NotifierWebhookURL,
+			Required:  false,
+		},
 		{
 			Name:        "admin-port",
-			Usage:       "Port to listen and serve admin functionality including metrics",
+			Usage:       "Port to listen and serve admin functionality including metrics and signing keys",
 			OptType:     types.Int,
 			ConfigKey:   &opts.AdminPort,
 			FlagDefault: 0,
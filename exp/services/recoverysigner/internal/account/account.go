@@ -1,11 +1,15 @@
 package account
 
 type Account struct {
-	Address    string
-	Identities []Identity
+	Address       string
+	Identities    []Identity
+	SigningPolicy SigningPolicy
 }
 
 type Identity struct {
+	// ID is assigned by the store when the identity is stored. Identities
+	// are stored again, with new IDs, whenever their account is updated.
+	ID          int64
 	Role        string
 	AuthMethods []AuthMethod
 }
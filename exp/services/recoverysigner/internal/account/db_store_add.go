@@ -1,8 +1,17 @@
 package account
 
-import "github.com/lib/pq"
+import (
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
 
 func (s *DBStore) Add(a Account) error {
+	signingPolicy, err := json.Marshal(a.SigningPolicy)
+	if err != nil {
+		return err
+	}
+
 	tx, err := s.DB.Beginx()
 	if err != nil {
 		return err
@@ -11,10 +20,10 @@ func (s *DBStore) Add(a Account) error {
 
 	accountID := int64(0)
 	err = tx.Get(&accountID, `
-		INSERT INTO accounts (address)
-		VALUES ($1)
+		INSERT INTO accounts (address, signing_policy)
+		VALUES ($1, $2)
 		RETURNING id
-	`, a.Address)
+	`, a.Address, signingPolicy)
 	if err != nil {
 		// 23505 is the PostgreSQL error for Unique Violation.
 		// See https://www.postgresql.org/docs/9.2/errcodes-appendix.html.
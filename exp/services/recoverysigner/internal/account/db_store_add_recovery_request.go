@@ -0,0 +1,9 @@
+package account
+
+func (s *DBStore) AddRecoveryRequest(r RecoveryRequest) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO recovery_requests (id, created_at, address, identity, auth_method_type, auth_method_value, code_hash, attempts, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, r.ID, r.CreatedAt, r.Address, r.Identity, r.AuthMethod.Type, r.AuthMethod.Value, r.CodeHash, r.Attempts, r.Status, r.ExpiresAt)
+	return err
+}
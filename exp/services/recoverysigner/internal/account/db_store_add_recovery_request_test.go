@@ -0,0 +1,56 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRecoveryRequest(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r, code, err := NewRecoveryRequest(
+		"GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		"1",
+		AuthMethod{Type: AuthMethodTypePhoneNumber, Value: "+10000000000"},
+		now,
+		10*time.Minute,
+	)
+	require.NoError(t, err)
+
+	err = store.AddRecoveryRequest(r)
+	require.NoError(t, err)
+
+	// Addresses are matched case insensitively like accounts.
+	got, err := store.GetRecoveryRequest("gcllt3vg4f6ezahzebkwbwv5jgvpcvikucgty3qeoaizu5ijgmwct2tt", r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, got.ID)
+	assert.Equal(t, "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT", got.Address)
+	assert.Equal(t, "1", got.Identity)
+	assert.Equal(t, AuthMethod{Type: AuthMethodTypePhoneNumber, Value: "+10000000000"}, got.AuthMethod)
+	assert.Equal(t, RecoveryRequestStatusPending, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+	assert.True(t, now.Equal(got.CreatedAt))
+	assert.True(t, now.Add(10*time.Minute).Equal(got.ExpiresAt))
+	assert.True(t, got.ConfirmedAt.IsZero())
+	assert.True(t, got.ApprovedAt.IsZero())
+	assert.True(t, got.ApprovalExpiresAt.IsZero())
+	assert.True(t, got.CancelledAt.IsZero())
+	assert.True(t, got.CheckCode(code))
+
+	_, err = store.GetRecoveryRequest("GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT", "unknown")
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+
+	// The request is not found for another account.
+	_, err = store.GetRecoveryRequest("GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6", r.ID)
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+}
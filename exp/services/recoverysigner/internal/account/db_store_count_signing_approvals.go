@@ -0,0 +1,23 @@
+package account
+
+import "github.com/lib/pq"
+
+// CountSigningApprovals returns the number of distinct identities, out of the
+// given identities, that have had a request to sign the transaction for the
+// account approved or signed. Approvals of identities that are not given, such
+// as identities since removed from the account, are not counted.
+func (s *DBStore) CountSigningApprovals(address, transactionHash string, identities []string) (int, error) {
+	count := int(0)
+	err := s.DB.Get(&count, `
+		SELECT COUNT(DISTINCT identity)
+		FROM signing_decisions
+		WHERE UPPER(address) = UPPER($1)
+		AND transaction_hash = $2
+		AND decision IN ('approved', 'signed')
+		AND identity = ANY($3)
+	`, address, transactionHash, pq.StringArray(identities))
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,65 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSigningApprovals(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	address := "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT"
+	hash := "0ea3a1da8c2f1bb8a0ec3ad0e7e8e5b1a6e2f3c4d5e6f708192a3b4c5d6e7f80"
+	otherHash := "93c2a2ab5a35e04d5ab3fd6e3e6b3e5d2fc1b17ed2acb06f8cf4b3b6b4a4e1d2"
+
+	identities := []string{SigningIdentityAccount, "1", "2"}
+
+	count, err := store.CountSigningApprovals(address, hash, identities)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	decisions := []SigningDecision{
+		{Address: address, TransactionHash: hash, Identity: "1", Decision: SigningDecisionApproved},
+		// Repeated approvals by the same identity are counted once.
+		{Address: address, TransactionHash: hash, Identity: "1", Decision: SigningDecisionApproved},
+		{Address: address, TransactionHash: hash, Identity: "2", Decision: SigningDecisionRejected},
+		{Address: address, TransactionHash: otherHash, Identity: "2", Decision: SigningDecisionApproved},
+		{Address: "GBJCOYGKIJYX3VUEOZ6GVMFP522UO4OEBI5KB5HHWZAZ2DEJTHS6VOHP", TransactionHash: hash, Identity: "3", Decision: SigningDecisionApproved},
+	}
+	for _, d := range decisions {
+		d.SigningAddress = "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"
+		err = store.RecordSigningDecision(d)
+		require.NoError(t, err)
+	}
+
+	count, err = store.CountSigningApprovals(address, hash, identities)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	err = store.RecordSigningDecision(SigningDecision{
+		Address:         address,
+		SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+		TransactionHash: hash,
+		Identity:        SigningIdentityAccount,
+		Decision:        SigningDecisionSigned,
+	})
+	require.NoError(t, err)
+
+	count, err = store.CountSigningApprovals(address, hash, identities)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Approvals of identities that are not given, such as identities removed
+	// from the account, are not counted.
+	count, err = store.CountSigningApprovals(address, hash, []string{"2", "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
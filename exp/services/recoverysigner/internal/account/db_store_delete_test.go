@@ -63,12 +63,12 @@ func TestDelete(t *testing.T) {
 	// Get account 1 to check it exists
 	a1Got, err := store.Get(a1Address)
 	require.NoError(t, err)
-	assert.Equal(t, a1, a1Got)
+	assert.Equal(t, a1, withoutIdentityIDs(t, a1Got))
 
 	// Get account 2 to check it exists
 	a2Got, err := store.Get(a2Address)
 	require.NoError(t, err)
-	assert.Equal(t, a2, a2Got)
+	assert.Equal(t, a2, withoutIdentityIDs(t, a2Got))
 
 	// Delete account 1
 	err = store.Delete(a1Address)
@@ -81,7 +81,7 @@ func TestDelete(t *testing.T) {
 	// Get account 2 to check it was not deleted
 	a2Got, err = store.Get(a2Address)
 	require.NoError(t, err)
-	assert.Equal(t, a2, a2Got)
+	assert.Equal(t, a2, withoutIdentityIDs(t, a2Got))
 
 	// Check that account 1 is gone and account 2 remains
 	{
@@ -220,12 +220,12 @@ func TestDelete(t *testing.T) {
 	// Get account 3 to check it exists
 	a3Got, err := store.Get(a3Address)
 	require.NoError(t, err)
-	assert.Equal(t, a3, a3Got)
+	assert.Equal(t, a3, withoutIdentityIDs(t, a3Got))
 
 	// Get account 2 to check it exists
 	a2Got, err = store.Get(a2Address)
 	require.NoError(t, err)
-	assert.Equal(t, a2, a2Got)
+	assert.Equal(t, a2, withoutIdentityIDs(t, a2Got))
 }
 
 func TestDelete_notFound(t *testing.T) {
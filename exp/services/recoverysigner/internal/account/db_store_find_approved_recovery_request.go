@@ -0,0 +1,22 @@
+package account
+
+import "time"
+
+// FindApprovedRecoveryRequest returns the most recent recovery request of the
+// identity of the account that is approved at time now.
+func (s *DBStore) FindApprovedRecoveryRequest(address, identity string, now time.Time) (RecoveryRequest, error) {
+	rows, err := s.getRecoveryRequests(`
+		UPPER(address) = UPPER($1)
+		AND identity = $2
+		AND status = 'confirmed'
+		AND approved_at <= $3
+		AND approval_expires_at > $3
+	`, address, identity, now)
+	if err != nil {
+		return RecoveryRequest{}, err
+	}
+	if len(rows) == 0 {
+		return RecoveryRequest{}, ErrRecoveryRequestNotFound
+	}
+	return rows[0], nil
+}
@@ -0,0 +1,71 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindApprovedRecoveryRequest(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	address := "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT"
+	authMethod := AuthMethod{Type: AuthMethodTypePhoneNumber, Value: "+10000000000"}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// A request that is pending confirmation.
+	pending, _, err := NewRecoveryRequest(address, "1", authMethod, now, 10*time.Minute)
+	require.NoError(t, err)
+	err = store.AddRecoveryRequest(pending)
+	require.NoError(t, err)
+
+	_, err = store.FindApprovedRecoveryRequest(address, "1", now)
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+
+	// A request that is confirmed and time locked for an hour.
+	confirmed, _, err := NewRecoveryRequest(address, "1", authMethod, now, 10*time.Minute)
+	require.NoError(t, err)
+	err = store.AddRecoveryRequest(confirmed)
+	require.NoError(t, err)
+	prev := confirmed
+	confirmed.Status = RecoveryRequestStatusConfirmed
+	confirmed.ConfirmedAt = now
+	confirmed.ApprovedAt = now.Add(time.Hour)
+	confirmed.ApprovalExpiresAt = now.Add(2 * time.Hour)
+	err = store.UpdateRecoveryRequest(prev, confirmed)
+	require.NoError(t, err)
+
+	_, err = store.FindApprovedRecoveryRequest(address, "1", now)
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+
+	got, err := store.FindApprovedRecoveryRequest(address, "1", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, confirmed.ID, got.ID)
+
+	// The request is not approved for other identities.
+	_, err = store.FindApprovedRecoveryRequest(address, "2", now.Add(time.Hour))
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+
+	// The request is no longer approved once its approval expires.
+	_, err = store.FindApprovedRecoveryRequest(address, "1", now.Add(2*time.Hour))
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+
+	// The request is no longer approved once it is cancelled.
+	prev = confirmed
+	confirmed.Status = RecoveryRequestStatusCancelled
+	confirmed.CancelledAt = now.Add(time.Hour)
+	confirmed.CancelledBy = "2"
+	err = store.UpdateRecoveryRequest(prev, confirmed)
+	require.NoError(t, err)
+
+	_, err = store.FindApprovedRecoveryRequest(address, "1", now.Add(time.Hour))
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+}
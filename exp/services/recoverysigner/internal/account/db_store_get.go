@@ -1,5 +1,7 @@
 package account
 
+import "encoding/json"
+
 func (s *DBStore) Get(address string) (Account, error) {
 	accounts, err := s.getAccounts("accounts.address = $1", address)
 	if err != nil {
@@ -20,6 +22,7 @@ func (s *DBStore) getAccounts(where string, args ...interface{}) ([]Account, err
 	query := `SELECT
 			accounts.id AS account_id,
 			accounts.address AS account_address,
+			accounts.signing_policy AS account_signing_policy,
 			identities.id AS identity_id,
 			identities.role AS identity_role,
 			auth_methods.type_ AS auth_method_type,
@@ -41,12 +44,13 @@ func (s *DBStore) getAccounts(where string, args ...interface{}) ([]Account, err
 
 	for rows.Next() {
 		var r struct {
-			AccountID       int64   `db:"account_id"`
-			AccountAddress  string  `db:"account_address"`
-			IdentityID      *int64  `db:"identity_id"`
-			IdentityRole    *string `db:"identity_role"`
-			AuthMethodType  *string `db:"auth_method_type"`
-			AuthMethodValue *string `db:"auth_method_value"`
+			AccountID            int64   `db:"account_id"`
+			AccountAddress       string  `db:"account_address"`
+			AccountSigningPolicy []byte  `db:"account_signing_policy"`
+			IdentityID           *int64  `db:"identity_id"`
+			IdentityRole         *string `db:"identity_role"`
+			AuthMethodType       *string `db:"auth_method_type"`
+			AuthMethodValue      *string `db:"auth_method_value"`
 		}
 		err = rows.StructScan(&r)
 		if err != nil {
@@ -56,6 +60,10 @@ func (s *DBStore) getAccounts(where string, args ...interface{}) ([]Account, err
 		accountIndex, ok := accountIndexByAccountID[r.AccountID]
 		if !ok {
 			a := Account{Address: r.AccountAddress}
+			err = json.Unmarshal(r.AccountSigningPolicy, &a.SigningPolicy)
+			if err != nil {
+				return nil, err
+			}
 			accounts = append(accounts, a)
 			accountIndex = len(accounts) - 1
 			accountIndexByAccountID[r.AccountID] = accountIndex
@@ -70,7 +78,7 @@ func (s *DBStore) getAccounts(where string, args ...interface{}) ([]Account, err
 
 			identityIndex, ok := identityIndexByIdentityID[identityID]
 			if !ok {
-				i := Identity{Role: identityRole}
+				i := Identity{ID: identityID, Role: identityRole}
 				a.Identities = append(a.Identities, i)
 				identityIndex = len(a.Identities) - 1
 				identityIndexByIdentityID[identityID] = identityIndex
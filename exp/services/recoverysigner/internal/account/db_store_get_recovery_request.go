@@ -0,0 +1,95 @@
+package account
+
+import (
+	"database/sql"
+	"time"
+)
+
+func (s *DBStore) GetRecoveryRequest(address, id string) (RecoveryRequest, error) {
+	rows, err := s.getRecoveryRequests(`UPPER(address) = UPPER($1) AND id = $2`, address, id)
+	if err != nil {
+		return RecoveryRequest{}, err
+	}
+	if len(rows) == 0 {
+		return RecoveryRequest{}, ErrRecoveryRequestNotFound
+	}
+	return rows[0], nil
+}
+
+func (s *DBStore) getRecoveryRequests(where string, args ...interface{}) ([]RecoveryRequest, error) {
+	query := `SELECT
+			id,
+			created_at,
+			address,
+			identity,
+			auth_method_type,
+			auth_method_value,
+			code_hash,
+			attempts,
+			status,
+			expires_at,
+			confirmed_at,
+			approved_at,
+			approval_expires_at,
+			cancelled_at,
+			cancelled_by
+		FROM recovery_requests
+		WHERE ` + where + `
+		ORDER BY created_at DESC`
+
+	rows, err := s.DB.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []RecoveryRequest{}
+	for rows.Next() {
+		var r struct {
+			ID                string       `db:"id"`
+			CreatedAt         time.Time    `db:"created_at"`
+			Address           string       `db:"address"`
+			Identity          string       `db:"identity"`
+			AuthMethodType    string       `db:"auth_method_type"`
+			AuthMethodValue   string       `db:"auth_method_value"`
+			CodeHash          string       `db:"code_hash"`
+			Attempts          int          `db:"attempts"`
+			Status            string       `db:"status"`
+			ExpiresAt         time.Time    `db:"expires_at"`
+			ConfirmedAt       sql.NullTime `db:"confirmed_at"`
+			ApprovedAt        sql.NullTime `db:"approved_at"`
+			ApprovalExpiresAt sql.NullTime `db:"approval_expires_at"`
+			CancelledAt       sql.NullTime `db:"cancelled_at"`
+			CancelledBy       string       `db:"cancelled_by"`
+		}
+		err = rows.StructScan(&r)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, RecoveryRequest{
+			ID:       r.ID,
+			Address:  r.Address,
+			Identity: r.Identity,
+			AuthMethod: AuthMethod{
+				Type:  AuthMethodType(r.AuthMethodType),
+				Value: r.AuthMethodValue,
+			},
+			CodeHash:          r.CodeHash,
+			Attempts:          r.Attempts,
+			Status:            RecoveryRequestStatus(r.Status),
+			CreatedAt:         r.CreatedAt,
+			ExpiresAt:         r.ExpiresAt,
+			ConfirmedAt:       r.ConfirmedAt.Time,
+			ApprovedAt:        r.ApprovedAt.Time,
+			ApprovalExpiresAt: r.ApprovalExpiresAt.Time,
+			CancelledAt:       r.CancelledAt.Time,
+			CancelledBy:       r.CancelledBy,
+		})
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
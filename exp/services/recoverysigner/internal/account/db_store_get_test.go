@@ -8,6 +8,31 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// withoutIdentityIDs checks that the identities of the account have been
+// assigned IDs by the store and returns the account with the IDs cleared, so
+// that it can be compared to the account that was stored.
+func withoutIdentityIDs(t *testing.T, a Account) Account {
+	if a.Identities == nil {
+		return a
+	}
+	identities := make([]Identity, len(a.Identities))
+	for i, identity := range a.Identities {
+		assert.NotZero(t, identity.ID)
+		identity.ID = 0
+		identities[i] = identity
+	}
+	a.Identities = identities
+	return a
+}
+
+func withoutAccountsIdentityIDs(t *testing.T, accounts []Account) []Account {
+	result := make([]Account, len(accounts))
+	for i, a := range accounts {
+		result[i] = withoutIdentityIDs(t, a)
+	}
+	return result
+}
+
 func TestGet(t *testing.T) {
 	db := dbtest.Open(t)
 	session := db.Open()
@@ -46,7 +71,7 @@ func TestGet(t *testing.T) {
 	// Reading the account out results in the same data.
 	aRoundTrip, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, a, aRoundTrip)
+	assert.Equal(t, a, withoutIdentityIDs(t, aRoundTrip))
 }
 
 func TestGet_noIdentities(t *testing.T) {
@@ -69,7 +94,7 @@ func TestGet_noIdentities(t *testing.T) {
 	// Reading the account out results in the same data.
 	aRoundTrip, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, a, aRoundTrip)
+	assert.Equal(t, a, withoutIdentityIDs(t, aRoundTrip))
 }
 
 func TestGet_noAuthMethods(t *testing.T) {
@@ -93,7 +118,7 @@ func TestGet_noAuthMethods(t *testing.T) {
 	// Reading the account out results in the same data.
 	aRoundTrip, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, a, aRoundTrip)
+	assert.Equal(t, a, withoutIdentityIDs(t, aRoundTrip))
 }
 
 func TestGet_notFound(t *testing.T) {
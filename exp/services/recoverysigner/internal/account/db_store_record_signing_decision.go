@@ -0,0 +1,9 @@
+package account
+
+func (s *DBStore) RecordSigningDecision(d SigningDecision) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO signing_decisions (address, signing_address, transaction_hash, identity, decision, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, d.Address, d.SigningAddress, d.TransactionHash, d.Identity, d.Decision, d.Reason)
+	return err
+}
@@ -0,0 +1,89 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordSigningDecision(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	d := SigningDecision{
+		Address:         "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+		TransactionHash: "0ea3a1da8c2f1bb8a0ec3ad0e7e8e5b1a6e2f3c4d5e6f708192a3b4c5d6e7f80",
+		Identity:        "owner",
+		Decision:        SigningDecisionRejected,
+		Reason:          "transaction has no max time but time bounds are required",
+	}
+	err := store.RecordSigningDecision(d)
+	require.NoError(t, err)
+
+	type row struct {
+		CreatedAt       time.Time `db:"created_at"`
+		Address         string    `db:"address"`
+		SigningAddress  string    `db:"signing_address"`
+		TransactionHash string    `db:"transaction_hash"`
+		Identity        string    `db:"identity"`
+		Decision        string    `db:"decision"`
+		Reason          string    `db:"reason"`
+	}
+	rows := []row{}
+	err = session.Select(&rows, `SELECT created_at, address, signing_address, transaction_hash, identity, decision, reason FROM signing_decisions`)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.NotZero(t, rows[0].CreatedAt)
+	rows[0].CreatedAt = time.Time{}
+	wantRows := []row{
+		{
+			Address:         "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+			SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+			TransactionHash: "0ea3a1da8c2f1bb8a0ec3ad0e7e8e5b1a6e2f3c4d5e6f708192a3b4c5d6e7f80",
+			Identity:        "owner",
+			Decision:        "rejected",
+			Reason:          "transaction has no max time but time bounds are required",
+		},
+	}
+	assert.Equal(t, wantRows, rows)
+}
+
+func TestRecordSigningDecision_appendOnly(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	err := store.RecordSigningDecision(SigningDecision{
+		Address:         "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+		TransactionHash: "0ea3a1da8c2f1bb8a0ec3ad0e7e8e5b1a6e2f3c4d5e6f708192a3b4c5d6e7f80",
+		Identity:        SigningIdentityAccount,
+		Decision:        SigningDecisionSigned,
+	})
+	require.NoError(t, err)
+
+	_, err = session.Exec(`UPDATE signing_decisions SET decision = 'rejected'`)
+	assert.EqualError(t, err, "pq: signing_decisions is append-only")
+
+	_, err = session.Exec(`DELETE FROM signing_decisions`)
+	assert.EqualError(t, err, "pq: signing_decisions is append-only")
+
+	_, err = session.Exec(`TRUNCATE signing_decisions`)
+	assert.EqualError(t, err, "pq: signing_decisions is append-only")
+
+	count := 0
+	err = session.Get(&count, `SELECT COUNT(*) FROM signing_decisions WHERE decision = 'signed'`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
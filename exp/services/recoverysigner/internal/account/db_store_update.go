@@ -2,11 +2,17 @@ package account
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/lib/pq"
 )
 
 func (s *DBStore) Update(a Account) error {
+	signingPolicy, err := json.Marshal(a.SigningPolicy)
+	if err != nil {
+		return err
+	}
+
 	tx, err := s.DB.Beginx()
 	if err != nil {
 		return err
@@ -32,6 +38,17 @@ func (s *DBStore) Update(a Account) error {
 		return err
 	}
 
+	// Only update the account row when the signing policy changes so that
+	// unchanged accounts are not recorded in the audit table.
+	_, err = tx.Exec(`
+		UPDATE accounts
+		SET signing_policy = $2, updated_at = NOW()
+		WHERE id = $1 AND signing_policy <> $2::jsonb
+	`, accountID, signingPolicy)
+	if err != nil {
+		return err
+	}
+
 	for _, i := range a.Identities {
 		var authTypes, authValues pq.StringArray
 		for _, m := range i.AuthMethods {
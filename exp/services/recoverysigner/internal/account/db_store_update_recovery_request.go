@@ -0,0 +1,47 @@
+package account
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UpdateRecoveryRequest updates the attempts, status and times of the
+// recovery request from those of prev to those of r. The other fields of a
+// recovery request cannot be changed. The request is only updated if its
+// status and attempts are still those of prev, otherwise
+// ErrRecoveryRequestConflict is returned, so that concurrent updates of the
+// same request cannot overwrite each other.
+func (s *DBStore) UpdateRecoveryRequest(prev, r RecoveryRequest) error {
+	result, err := s.DB.Exec(`
+		UPDATE recovery_requests
+		SET attempts = $3,
+			status = $4,
+			confirmed_at = $5,
+			approved_at = $6,
+			approval_expires_at = $7,
+			cancelled_at = $8,
+			cancelled_by = $9,
+			updated_at = NOW()
+		WHERE UPPER(address) = UPPER($1) AND id = $2
+		AND status = $10 AND attempts = $11
+	`, r.Address, r.ID, r.Attempts, r.Status, nullTime(r.ConfirmedAt), nullTime(r.ApprovedAt), nullTime(r.ApprovalExpiresAt), nullTime(r.CancelledAt), r.CancelledBy, prev.Status, prev.Attempts)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		_, err = s.GetRecoveryRequest(r.Address, r.ID)
+		if err != nil {
+			return err
+		}
+		return ErrRecoveryRequestConflict
+	}
+	return nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
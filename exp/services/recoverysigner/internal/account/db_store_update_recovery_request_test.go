@@ -0,0 +1,131 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRecoveryRequest(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r, _, err := NewRecoveryRequest(
+		"GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		"1",
+		AuthMethod{Type: AuthMethodTypeEmail, Value: "user1@example.com"},
+		now,
+		10*time.Minute,
+	)
+	require.NoError(t, err)
+	err = store.AddRecoveryRequest(r)
+	require.NoError(t, err)
+
+	prev := r
+	r.Attempts = 1
+	r.Status = RecoveryRequestStatusConfirmed
+	r.ConfirmedAt = now.Add(time.Minute)
+	r.ApprovedAt = now.Add(time.Hour)
+	r.ApprovalExpiresAt = now.Add(2 * time.Hour)
+	err = store.UpdateRecoveryRequest(prev, r)
+	require.NoError(t, err)
+
+	got, err := store.GetRecoveryRequest(r.Address, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, RecoveryRequestStatusConfirmed, got.Status)
+	assert.True(t, now.Add(time.Minute).Equal(got.ConfirmedAt))
+	assert.True(t, now.Add(time.Hour).Equal(got.ApprovedAt))
+	assert.True(t, now.Add(2*time.Hour).Equal(got.ApprovalExpiresAt))
+	assert.True(t, got.CancelledAt.IsZero())
+	assert.Equal(t, "", got.CancelledBy)
+
+	prev = r
+	r.Status = RecoveryRequestStatusCancelled
+	r.CancelledAt = now.Add(2 * time.Minute)
+	r.CancelledBy = "2"
+	err = store.UpdateRecoveryRequest(prev, r)
+	require.NoError(t, err)
+
+	got, err = store.GetRecoveryRequest(r.Address, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RecoveryRequestStatusCancelled, got.Status)
+	assert.True(t, now.Add(2*time.Minute).Equal(got.CancelledAt))
+	assert.Equal(t, "2", got.CancelledBy)
+}
+
+func TestUpdateRecoveryRequest_notFound(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	r := RecoveryRequest{
+		ID:      "unknown",
+		Address: "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		Status:  RecoveryRequestStatusPending,
+	}
+	cancelled := r
+	cancelled.Status = RecoveryRequestStatusCancelled
+	err := store.UpdateRecoveryRequest(r, cancelled)
+	assert.Equal(t, ErrRecoveryRequestNotFound, err)
+}
+
+func TestUpdateRecoveryRequest_conflict(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r, _, err := NewRecoveryRequest(
+		"GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT",
+		"1",
+		AuthMethod{Type: AuthMethodTypeEmail, Value: "user1@example.com"},
+		now,
+		10*time.Minute,
+	)
+	require.NoError(t, err)
+	err = store.AddRecoveryRequest(r)
+	require.NoError(t, err)
+
+	// Two concurrent incorrect attempts read the same request.
+	attempt := r
+	attempt.Attempts = 1
+	err = store.UpdateRecoveryRequest(r, attempt)
+	require.NoError(t, err)
+	err = store.UpdateRecoveryRequest(r, attempt)
+	assert.Equal(t, ErrRecoveryRequestConflict, err)
+
+	// A confirmation of the request as it was before the attempt does not
+	// overwrite a concurrent cancellation.
+	cancelled := attempt
+	cancelled.Status = RecoveryRequestStatusCancelled
+	cancelled.CancelledAt = now.Add(time.Minute)
+	cancelled.CancelledBy = "2"
+	err = store.UpdateRecoveryRequest(attempt, cancelled)
+	require.NoError(t, err)
+	confirmed := attempt
+	confirmed.Status = RecoveryRequestStatusConfirmed
+	confirmed.ConfirmedAt = now.Add(time.Minute)
+	err = store.UpdateRecoveryRequest(attempt, confirmed)
+	assert.Equal(t, ErrRecoveryRequestConflict, err)
+
+	got, err := store.GetRecoveryRequest(r.Address, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, RecoveryRequestStatusCancelled, got.Status)
+	assert.True(t, got.ConfirmedAt.IsZero())
+}
@@ -63,7 +63,7 @@ func TestUpdate(t *testing.T) {
 
 	updatedAcc, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, b, updatedAcc)
+	assert.Equal(t, b, withoutIdentityIDs(t, updatedAcc))
 
 	// Check the account row has not been changed.
 	{
@@ -187,7 +187,7 @@ func TestUpdate_removeIdentities(t *testing.T) {
 
 	updatedAcc, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, b, updatedAcc)
+	assert.Equal(t, b, withoutIdentityIDs(t, updatedAcc))
 
 	{
 		type row struct {
@@ -267,7 +267,7 @@ func TestUpdate_noAuthMethods(t *testing.T) {
 
 	updatedAcc, err := store.Get(address)
 	require.NoError(t, err)
-	assert.Equal(t, b, updatedAcc)
+	assert.Equal(t, b, withoutIdentityIDs(t, updatedAcc))
 
 	// check there is no row in auth_methods
 	type row struct {
@@ -322,3 +322,72 @@ func TestUpdate_notFound_properlyClosesDBConnections(t *testing.T) {
 		assert.Equal(t, ErrNotFound, err)
 	}
 }
+
+func TestUpdate_signingPolicy(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{
+		DB: session,
+	}
+
+	address := "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT"
+
+	// Store the account with a signing policy
+	a := Account{
+		Address: address,
+		Identities: []Identity{
+			{
+				Role: "owner",
+				AuthMethods: []AuthMethod{
+					{Type: AuthMethodTypeAddress, Value: "GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6"},
+				},
+			},
+		},
+		SigningPolicy: SigningPolicy{
+			OnlySignerChanges: true,
+			Quorum:            2,
+		},
+	}
+	err := store.Add(a)
+	require.NoError(t, err)
+
+	addedAcc, err := store.Get(address)
+	require.NoError(t, err)
+	assert.Equal(t, a, withoutIdentityIDs(t, addedAcc))
+
+	// Update the account without changing the signing policy
+	err = store.Update(a)
+	require.NoError(t, err)
+
+	// Update the signing policy on the account
+	b := a
+	b.SigningPolicy = SigningPolicy{
+		DenyPayments:       true,
+		MaxOperations:      3,
+		RequireTimeBounds:  true,
+		MaxValiditySeconds: 600,
+	}
+	err = store.Update(b)
+	require.NoError(t, err)
+
+	updatedAcc, err := store.Get(address)
+	require.NoError(t, err)
+	assert.Equal(t, b, withoutIdentityIDs(t, updatedAcc))
+
+	// Check the account row was only updated when the signing policy changed.
+	{
+		type row struct {
+			AuditOp       string `db:"audit_op"`
+			SigningPolicy string `db:"signing_policy"`
+		}
+		rows := []row{}
+		err = session.Select(&rows, `SELECT audit_op, signing_policy FROM accounts_audit ORDER BY audit_id`)
+		require.NoError(t, err)
+		wantRows := []row{
+			{AuditOp: "INSERT", SigningPolicy: `{"quorum": 2, "only_signer_changes": true}`},
+			{AuditOp: "UPDATE", SigningPolicy: `{"deny_payments": true, "max_operations": 3, "require_time_bounds": true, "max_validity_seconds": 600}`},
+		}
+		assert.Equal(t, wantRows, rows)
+	}
+}
@@ -0,0 +1,132 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+type RecoveryRequestStatus string
+
+const (
+	// RecoveryRequestStatusPending is the status of a recovery request that
+	// has been created but not yet confirmed with the code sent to the
+	// identity that created it.
+	RecoveryRequestStatusPending   RecoveryRequestStatus = "pending"
+	RecoveryRequestStatusConfirmed RecoveryRequestStatus = "confirmed"
+	RecoveryRequestStatusCancelled RecoveryRequestStatus = "cancelled"
+)
+
+// RecoveryRequestState is the state of a recovery request at a point in
+// time, derived from its status and times.
+type RecoveryRequestState string
+
+const (
+	RecoveryRequestStatePendingConfirmation RecoveryRequestState = "pending_confirmation"
+	RecoveryRequestStateTimeLocked          RecoveryRequestState = "time_locked"
+	RecoveryRequestStateApproved            RecoveryRequestState = "approved"
+	RecoveryRequestStateCancelled           RecoveryRequestState = "cancelled"
+	RecoveryRequestStateExpired             RecoveryRequestState = "expired"
+)
+
+// RecoveryRequestMaxAttempts is the number of times an incorrect code can be
+// used to confirm a recovery request before it is cancelled.
+const RecoveryRequestMaxAttempts = 5
+
+// RecoveryRequest is a request by an identity of an account to recover the
+// account. Transactions are only signed for the identity once the request has
+// been confirmed with the code sent to the identity and any time lock of the
+// account's signing policy has passed.
+type RecoveryRequest struct {
+	ID      string
+	Address string
+	// Identity is the ID, in decimal, of the identity that created the
+	// request.
+	Identity string
+	// AuthMethod is the auth method the code was sent to.
+	AuthMethod AuthMethod
+	CodeHash   string
+	Attempts   int
+	Status     RecoveryRequestStatus
+
+	CreatedAt time.Time
+	// ExpiresAt is the time the request must be confirmed by.
+	ExpiresAt   time.Time
+	ConfirmedAt time.Time
+	// ApprovedAt is the time the time lock of the request ends, which is set
+	// when the request is confirmed.
+	ApprovedAt time.Time
+	// ApprovalExpiresAt is the time after which the approved request can no
+	// longer be used to sign transactions.
+	ApprovalExpiresAt time.Time
+	CancelledAt       time.Time
+	// CancelledBy is the ID, in decimal, of the identity that cancelled the
+	// request, or SigningIdentityAccount if cancelled by the account itself,
+	// or empty if the request was cancelled because of too many incorrect
+	// attempts.
+	CancelledBy string
+}
+
+// State returns the state of the request at time now.
+func (r RecoveryRequest) State(now time.Time) RecoveryRequestState {
+	switch r.Status {
+	case RecoveryRequestStatusCancelled:
+		return RecoveryRequestStateCancelled
+	case RecoveryRequestStatusConfirmed:
+		if now.Before(r.ApprovedAt) {
+			return RecoveryRequestStateTimeLocked
+		}
+		if now.Before(r.ApprovalExpiresAt) {
+			return RecoveryRequestStateApproved
+		}
+		return RecoveryRequestStateExpired
+	default:
+		if now.Before(r.ExpiresAt) {
+			return RecoveryRequestStatePendingConfirmation
+		}
+		return RecoveryRequestStateExpired
+	}
+}
+
+// CheckCode returns true if the code is the code of the request.
+func (r RecoveryRequest) CheckCode(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.CodeHash), []byte(hashRecoveryCode(r.ID, code))) == 1
+}
+
+// NewRecoveryRequest returns a recovery request with a random ID and code,
+// and the code that must be sent to the identity to confirm the request.
+func NewRecoveryRequest(address, identity string, authMethod AuthMethod, now time.Time, expiresIn time.Duration) (RecoveryRequest, string, error) {
+	idBytes := make([]byte, 16)
+	_, err := rand.Read(idBytes)
+	if err != nil {
+		return RecoveryRequest{}, "", err
+	}
+	id := hex.EncodeToString(idBytes)
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return RecoveryRequest{}, "", err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	r := RecoveryRequest{
+		ID:         id,
+		Address:    address,
+		Identity:   identity,
+		AuthMethod: authMethod,
+		CodeHash:   hashRecoveryCode(id, code),
+		Status:     RecoveryRequestStatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(expiresIn),
+	}
+	return r, code, nil
+}
+
+func hashRecoveryCode(id, code string) string {
+	h := sha256.Sum256([]byte(id + ":" + code))
+	return hex.EncodeToString(h[:])
+}
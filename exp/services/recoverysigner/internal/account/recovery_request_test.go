@@ -0,0 +1,66 @@
+package account
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecoveryRequest(t *testing.T) {
+	now := time.Date(2020, 10, 21, 0, 0, 0, 0, time.UTC)
+	authMethod := AuthMethod{Type: AuthMethodTypePhoneNumber, Value: "+10000000000"}
+
+	r, code, err := NewRecoveryRequest("GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT", "1", authMethod, now, 10*time.Minute)
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{32}$`), r.ID)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{6}$`), code)
+	assert.Equal(t, "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT", r.Address)
+	assert.Equal(t, "1", r.Identity)
+	assert.Equal(t, authMethod, r.AuthMethod)
+	assert.Equal(t, RecoveryRequestStatusPending, r.Status)
+	assert.Equal(t, now, r.CreatedAt)
+	assert.Equal(t, now.Add(10*time.Minute), r.ExpiresAt)
+	assert.NotContains(t, r.CodeHash, code)
+
+	assert.True(t, r.CheckCode(code))
+	assert.False(t, r.CheckCode(""))
+	assert.False(t, r.CheckCode("1"+code))
+
+	r2, _, err := NewRecoveryRequest("GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT", "1", authMethod, now, 10*time.Minute)
+	require.NoError(t, err)
+	assert.NotEqual(t, r.ID, r2.ID)
+}
+
+func TestRecoveryRequest_State(t *testing.T) {
+	now := time.Date(2020, 10, 21, 0, 0, 0, 0, time.UTC)
+
+	pending := RecoveryRequest{
+		Status:    RecoveryRequestStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(10 * time.Minute),
+	}
+	assert.Equal(t, RecoveryRequestStatePendingConfirmation, pending.State(now))
+	assert.Equal(t, RecoveryRequestStateExpired, pending.State(now.Add(10*time.Minute)))
+
+	confirmed := RecoveryRequest{
+		Status:            RecoveryRequestStatusConfirmed,
+		CreatedAt:         now,
+		ExpiresAt:         now.Add(10 * time.Minute),
+		ConfirmedAt:       now.Add(time.Minute),
+		ApprovedAt:        now.Add(time.Hour),
+		ApprovalExpiresAt: now.Add(2 * time.Hour),
+	}
+	assert.Equal(t, RecoveryRequestStateTimeLocked, confirmed.State(now.Add(time.Minute)))
+	assert.Equal(t, RecoveryRequestStateTimeLocked, confirmed.State(now.Add(59*time.Minute)))
+	assert.Equal(t, RecoveryRequestStateApproved, confirmed.State(now.Add(time.Hour)))
+	assert.Equal(t, RecoveryRequestStateExpired, confirmed.State(now.Add(2*time.Hour)))
+
+	cancelled := confirmed
+	cancelled.Status = RecoveryRequestStatusCancelled
+	cancelled.CancelledAt = now.Add(2 * time.Minute)
+	assert.Equal(t, RecoveryRequestStateCancelled, cancelled.State(now.Add(time.Hour)))
+}
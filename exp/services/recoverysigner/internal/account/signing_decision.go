@@ -0,0 +1,29 @@
+package account
+
+type SigningDecisionType string
+
+const (
+	// SigningDecisionApproved is recorded when an identity requested a
+	// transaction be signed but the quorum of the account's signing policy has
+	// not yet been reached.
+	SigningDecisionApproved SigningDecisionType = "approved"
+	SigningDecisionSigned   SigningDecisionType = "signed"
+	SigningDecisionRejected SigningDecisionType = "rejected"
+)
+
+// SigningIdentityAccount is the identity recorded for signing decisions
+// requested by a client authenticated as the account itself.
+const SigningIdentityAccount = "account"
+
+// SigningDecision is the outcome of a request to sign a transaction for an
+// account.
+type SigningDecision struct {
+	Address         string
+	SigningAddress  string
+	TransactionHash string
+	// Identity is the ID, in decimal, of the identity that requested the
+	// transaction be signed, or SigningIdentityAccount.
+	Identity string
+	Decision SigningDecisionType
+	Reason   string
+}
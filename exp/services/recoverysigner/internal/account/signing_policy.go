@@ -0,0 +1,142 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+// SigningPolicy restricts the transactions that will be signed for an
+// account. The zero value places no restrictions on transactions.
+type SigningPolicy struct {
+	// OnlySignerChanges restricts transactions to set options operations that
+	// only change signers, at least one of which adds or replaces a signer.
+	OnlySignerChanges bool `json:"only_signer_changes,omitempty"`
+
+	// DenyPayments rejects transactions containing operations that move
+	// funds out of the account, including offers that trade them away.
+	DenyPayments bool `json:"deny_payments,omitempty"`
+
+	// MaxOperations is the maximum number of operations a transaction may
+	// contain, if not zero.
+	MaxOperations int `json:"max_operations,omitempty"`
+
+	// RequireTimeBounds rejects transactions that have no max time.
+	RequireTimeBounds bool `json:"require_time_bounds,omitempty"`
+
+	// MaxValiditySeconds, if not zero, rejects transactions that have no max
+	// time or that are valid for longer than this many seconds from when they
+	// are signed.
+	MaxValiditySeconds int64 `json:"max_validity_seconds,omitempty"`
+
+	// Quorum is the number of distinct identities that must request a
+	// transaction be signed before it is signed, if greater than one.
+	Quorum int `json:"quorum,omitempty"`
+
+	// RequireRecoveryRequest restricts transactions requested by identities
+	// to those requested by an identity with an approved recovery request.
+	// Transactions requested by the account itself do not require one.
+	RequireRecoveryRequest bool `json:"require_recovery_request,omitempty"`
+
+	// RecoveryTimeLockSeconds is the number of seconds after a recovery
+	// request is confirmed that it is approved, giving the other identities
+	// of the account time to cancel it.
+	RecoveryTimeLockSeconds int64 `json:"recovery_time_lock_seconds,omitempty"`
+}
+
+// Validate checks that the values of the policy are within range.
+func (p SigningPolicy) Validate() error {
+	if p.MaxOperations < 0 {
+		return errors.New("max operations cannot be negative")
+	}
+	if p.MaxValiditySeconds < 0 {
+		return errors.New("max validity seconds cannot be negative")
+	}
+	if p.Quorum < 0 {
+		return errors.New("quorum cannot be negative")
+	}
+	if p.RecoveryTimeLockSeconds < 0 {
+		return errors.New("recovery time lock seconds cannot be negative")
+	}
+	return nil
+}
+
+// Check returns an error describing the first rule of the policy that the
+// transaction violates, or nil if the transaction can be signed at time now.
+// The quorum and recovery requirements of the policy are not checked as they
+// depend on the identities that requested the transaction be signed.
+func (p SigningPolicy) Check(tx *txnbuild.Transaction, now time.Time) error {
+	ops := tx.Operations()
+
+	if p.MaxOperations > 0 && len(ops) > p.MaxOperations {
+		return fmt.Errorf("transaction has %d operations but at most %d are permitted", len(ops), p.MaxOperations)
+	}
+
+	tb := tx.Timebounds()
+	if (p.RequireTimeBounds || p.MaxValiditySeconds > 0) && tb.MaxTime == txnbuild.TimeoutInfinite {
+		return errors.New("transaction has no max time but time bounds are required")
+	}
+	if p.MaxValiditySeconds > 0 && tb.MaxTime > now.Unix()+p.MaxValiditySeconds {
+		return fmt.Errorf("transaction is valid for longer than %d seconds", p.MaxValiditySeconds)
+	}
+
+	addsSigner := false
+	for i, op := range ops {
+		if p.DenyPayments && isPayment(op) {
+			return fmt.Errorf("operation %d is a payment but payments are not permitted", i)
+		}
+		if p.OnlySignerChanges {
+			so, ok := op.(*txnbuild.SetOptions)
+			if !ok || !onlyChangesSigner(so) {
+				return fmt.Errorf("operation %d is not a signer change but only signer changes are permitted", i)
+			}
+			if so.Signer.Weight > 0 {
+				addsSigner = true
+			}
+		}
+	}
+	if p.OnlySignerChanges && !addsSigner {
+		return errors.New("transaction does not add or replace a signer")
+	}
+
+	return nil
+}
+
+// isPayment returns true if the operation can move value out of its source
+// account: by sending or merging it, by trading it away through offers, by
+// locking it in a claimable balance or by committing it to the reserves of
+// the entries of another account. Clawback operations are not supported by
+// the XDR of this version and transactions containing them fail to decode
+// before they are checked.
+func isPayment(op txnbuild.Operation) bool {
+	switch op.(type) {
+	case *txnbuild.Payment,
+		*txnbuild.PathPaymentStrictReceive,
+		*txnbuild.PathPaymentStrictSend,
+		*txnbuild.CreateAccount,
+		*txnbuild.AccountMerge,
+		*txnbuild.CreateClaimableBalance,
+		*txnbuild.ManageSellOffer,
+		*txnbuild.ManageBuyOffer,
+		*txnbuild.CreatePassiveSellOffer,
+		*txnbuild.BeginSponsoringFutureReserves:
+		return true
+	}
+	return false
+}
+
+// onlyChangesSigner returns true if the set options operation changes a
+// signer and nothing else.
+func onlyChangesSigner(so *txnbuild.SetOptions) bool {
+	return so.Signer != nil &&
+		so.InflationDestination == nil &&
+		len(so.SetFlags) == 0 &&
+		len(so.ClearFlags) == 0 &&
+		so.MasterWeight == nil &&
+		so.LowThreshold == nil &&
+		so.MediumThreshold == nil &&
+		so.HighThreshold == nil &&
+		so.HomeDomain == nil
+}
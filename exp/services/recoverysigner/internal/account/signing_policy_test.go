@@ -0,0 +1,264 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransaction(t *testing.T, timebounds txnbuild.Timebounds, ops ...txnbuild.Operation) *txnbuild.Transaction {
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: "GCLLT3VG4F6EZAHZEBKWBWV5JGVPCVIKUCGTY3QEOAIZU5IJGMWCT2TT"},
+		Operations:    ops,
+		BaseFee:       txnbuild.MinBaseFee,
+		Timebounds:    timebounds,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+// roundTripTransaction encodes and decodes the transaction as the
+// transactions checked are always decoded from requests.
+func roundTripTransaction(t *testing.T, tx *txnbuild.Transaction) *txnbuild.Transaction {
+	txXDR, err := tx.Base64()
+	require.NoError(t, err)
+	parsed, err := txnbuild.TransactionFromXDR(txXDR)
+	require.NoError(t, err)
+	parsedTx, ok := parsed.Transaction()
+	require.True(t, ok)
+	return parsedTx
+}
+
+func TestSigningPolicy_Validate(t *testing.T) {
+	assert.NoError(t, SigningPolicy{}.Validate())
+	assert.NoError(t, SigningPolicy{MaxOperations: 2, MaxValiditySeconds: 300, Quorum: 2}.Validate())
+	assert.EqualError(t, SigningPolicy{MaxOperations: -1}.Validate(), "max operations cannot be negative")
+	assert.EqualError(t, SigningPolicy{MaxValiditySeconds: -1}.Validate(), "max validity seconds cannot be negative")
+	assert.EqualError(t, SigningPolicy{Quorum: -1}.Validate(), "quorum cannot be negative")
+	assert.EqualError(t, SigningPolicy{RecoveryTimeLockSeconds: -1}.Validate(), "recovery time lock seconds cannot be negative")
+}
+
+func TestSigningPolicy_Check(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	addSigner := &txnbuild.SetOptions{
+		Signer: &txnbuild.Signer{Address: "GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6", Weight: 20},
+	}
+	removeSigner := &txnbuild.SetOptions{
+		Signer: &txnbuild.Signer{Address: "GBJCOYGKIJYX3VUEOZ6GVMFP522UO4OEBI5KB5HHWZAZ2DEJTHS6VOHP", Weight: 0},
+	}
+	setHomeDomain := &txnbuild.SetOptions{
+		HomeDomain: txnbuild.NewHomeDomain("example.com"),
+	}
+	payment := &txnbuild.Payment{
+		Destination: "GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6",
+		Amount:      "10",
+		Asset:       txnbuild.NativeAsset{},
+	}
+	merge := &txnbuild.AccountMerge{
+		Destination: "GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6",
+	}
+	noTimeout := txnbuild.NewInfiniteTimeout()
+	timeout := txnbuild.NewTimebounds(0, now.Unix()+300)
+
+	testCases := []struct {
+		name    string
+		policy  SigningPolicy
+		tx      *txnbuild.Transaction
+		wantErr string
+	}{
+		{
+			name:   "zero policy",
+			policy: SigningPolicy{},
+			tx:     newTestTransaction(t, noTimeout, payment, setHomeDomain, merge),
+		},
+		{
+			name:   "max operations",
+			policy: SigningPolicy{MaxOperations: 2},
+			tx:     newTestTransaction(t, noTimeout, addSigner, removeSigner),
+		},
+		{
+			name:    "too many operations",
+			policy:  SigningPolicy{MaxOperations: 1},
+			tx:      newTestTransaction(t, noTimeout, addSigner, removeSigner),
+			wantErr: "transaction has 2 operations but at most 1 are permitted",
+		},
+		{
+			name:   "time bounds required",
+			policy: SigningPolicy{RequireTimeBounds: true},
+			tx:     newTestTransaction(t, timeout, addSigner),
+		},
+		{
+			name:    "time bounds required but missing",
+			policy:  SigningPolicy{RequireTimeBounds: true},
+			tx:      newTestTransaction(t, noTimeout, addSigner),
+			wantErr: "transaction has no max time but time bounds are required",
+		},
+		{
+			name:   "max validity",
+			policy: SigningPolicy{MaxValiditySeconds: 300},
+			tx:     newTestTransaction(t, timeout, addSigner),
+		},
+		{
+			name:    "max validity without time bounds",
+			policy:  SigningPolicy{MaxValiditySeconds: 300},
+			tx:      newTestTransaction(t, noTimeout, addSigner),
+			wantErr: "transaction has no max time but time bounds are required",
+		},
+		{
+			name:    "max validity exceeded",
+			policy:  SigningPolicy{MaxValiditySeconds: 299},
+			tx:      newTestTransaction(t, timeout, addSigner),
+			wantErr: "transaction is valid for longer than 299 seconds",
+		},
+		{
+			name:   "deny payments",
+			policy: SigningPolicy{DenyPayments: true},
+			tx:     newTestTransaction(t, noTimeout, addSigner, setHomeDomain),
+		},
+		{
+			name:    "deny payments with payment",
+			policy:  SigningPolicy{DenyPayments: true},
+			tx:      newTestTransaction(t, noTimeout, addSigner, payment),
+			wantErr: "operation 1 is a payment but payments are not permitted",
+		},
+		{
+			name:    "deny payments with account merge",
+			policy:  SigningPolicy{DenyPayments: true},
+			tx:      newTestTransaction(t, noTimeout, merge),
+			wantErr: "operation 0 is a payment but payments are not permitted",
+		},
+		{
+			name:   "only signer changes adding signer",
+			policy: SigningPolicy{OnlySignerChanges: true},
+			tx:     newTestTransaction(t, noTimeout, addSigner),
+		},
+		{
+			name:   "only signer changes replacing signer",
+			policy: SigningPolicy{OnlySignerChanges: true},
+			tx:     newTestTransaction(t, noTimeout, addSigner, removeSigner),
+		},
+		{
+			name:    "only signer changes removing signer",
+			policy:  SigningPolicy{OnlySignerChanges: true},
+			tx:      newTestTransaction(t, noTimeout, removeSigner),
+			wantErr: "transaction does not add or replace a signer",
+		},
+		{
+			name:    "only signer changes with other set options",
+			policy:  SigningPolicy{OnlySignerChanges: true},
+			tx:      newTestTransaction(t, noTimeout, addSigner, setHomeDomain),
+			wantErr: "operation 1 is not a signer change but only signer changes are permitted",
+		},
+		{
+			name:    "only signer changes with payment",
+			policy:  SigningPolicy{OnlySignerChanges: true},
+			tx:      newTestTransaction(t, noTimeout, payment, addSigner),
+			wantErr: "operation 0 is not a signer change but only signer changes are permitted",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := roundTripTransaction(t, tc.tx)
+			err := tc.policy.Check(tx, now)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSigningPolicy_Check_denyPayments(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	destination := "GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6"
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: "GBJCOYGKIJYX3VUEOZ6GVMFP522UO4OEBI5KB5HHWZAZ2DEJTHS6VOHP"}
+	policy := SigningPolicy{DenyPayments: true}
+
+	payments := map[string]txnbuild.Operation{
+		"payment": &txnbuild.Payment{
+			Destination: destination,
+			Amount:      "10",
+			Asset:       txnbuild.NativeAsset{},
+		},
+		"path payment strict receive": &txnbuild.PathPaymentStrictReceive{
+			SendAsset:   txnbuild.NativeAsset{},
+			SendMax:     "10",
+			Destination: destination,
+			DestAsset:   usd,
+			DestAmount:  "10",
+		},
+		"path payment strict send": &txnbuild.PathPaymentStrictSend{
+			SendAsset:   txnbuild.NativeAsset{},
+			SendAmount:  "10",
+			Destination: destination,
+			DestAsset:   usd,
+			DestMin:     "10",
+		},
+		"create account": &txnbuild.CreateAccount{
+			Destination: destination,
+			Amount:      "10",
+		},
+		"account merge": &txnbuild.AccountMerge{
+			Destination: destination,
+		},
+		"create claimable balance": &txnbuild.CreateClaimableBalance{
+			Amount:       "10",
+			Asset:        txnbuild.NativeAsset{},
+			Destinations: []txnbuild.Claimant{txnbuild.NewClaimant(destination, nil)},
+		},
+		"manage sell offer": &txnbuild.ManageSellOffer{
+			Selling: txnbuild.NativeAsset{},
+			Buying:  usd,
+			Amount:  "10",
+			Price:   "1",
+		},
+		"manage buy offer": &txnbuild.ManageBuyOffer{
+			Selling: txnbuild.NativeAsset{},
+			Buying:  usd,
+			Amount:  "10",
+			Price:   "1",
+		},
+		"create passive sell offer": &txnbuild.CreatePassiveSellOffer{
+			Selling: txnbuild.NativeAsset{},
+			Buying:  usd,
+			Amount:  "10",
+			Price:   "1",
+		},
+		"begin sponsoring future reserves": &txnbuild.BeginSponsoringFutureReserves{
+			SponsoredID: destination,
+		},
+	}
+	for name, op := range payments {
+		t.Run(name, func(t *testing.T) {
+			tx := roundTripTransaction(t, newTestTransaction(t, txnbuild.NewInfiniteTimeout(), op))
+			err := policy.Check(tx, now)
+			assert.EqualError(t, err, "operation 0 is a payment but payments are not permitted")
+		})
+	}
+
+	others := map[string]txnbuild.Operation{
+		"set options": &txnbuild.SetOptions{
+			HomeDomain: txnbuild.NewHomeDomain("example.com"),
+		},
+		"change trust": &txnbuild.ChangeTrust{
+			Line: usd,
+		},
+		"manage data": &txnbuild.ManageData{
+			Name:  "key",
+			Value: []byte("value"),
+		},
+		"bump sequence": &txnbuild.BumpSequence{
+			BumpTo: 1,
+		},
+	}
+	for name, op := range others {
+		t.Run(name, func(t *testing.T) {
+			tx := roundTripTransaction(t, newTestTransaction(t, txnbuild.NewInfiniteTimeout(), op))
+			assert.NoError(t, policy.Check(tx, now))
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package account
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type Store interface {
 	Add(a Account) error
@@ -11,7 +14,15 @@ type Store interface {
 	FindWithIdentityPhoneNumber(phoneNumber string) ([]Account, error)
 	FindWithIdentityEmail(email string) ([]Account, error)
 	Count() (int, error)
+	RecordSigningDecision(d SigningDecision) error
+	CountSigningApprovals(address, transactionHash string, identities []string) (int, error)
+	AddRecoveryRequest(r RecoveryRequest) error
+	GetRecoveryRequest(address, id string) (RecoveryRequest, error)
+	UpdateRecoveryRequest(prev, r RecoveryRequest) error
+	FindApprovedRecoveryRequest(address, identity string, now time.Time) (RecoveryRequest, error)
 }
 
 var ErrNotFound = errors.New("account not found")
 var ErrAlreadyExists = errors.New("account already exists")
+var ErrRecoveryRequestNotFound = errors.New("recovery request not found")
+var ErrRecoveryRequestConflict = errors.New("recovery request changed concurrently")
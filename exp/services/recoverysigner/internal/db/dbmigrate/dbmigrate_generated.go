@@ -8,6 +8,9 @@
 // migrations/20200320000000-create-accounts-audit.sql (1.23kB)
 // migrations/20200320000001-create-identities-audit.sql (1.166kB)
 // migrations/20200320000002-create-auth-methods-audit.sql (1.192kB)
+// migrations/20201020000000-add-accounts-signing-policy.sql (297B)
+// migrations/20201020000001-create-signing-decisions.sql (1.459kB)
+// migrations/20201021000000-create-recovery-requests.sql (1.057kB)
 
 package dbmigrate
 
@@ -236,6 +239,66 @@ func migrations20200320000002CreateAuthMethodsAuditSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20201020000000AddAccountsSigningPolicySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4c\x4e\xce\x2f\xcd\x2b\x29\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xce\x4c\xcf\xcb\xcc\x4b\x8f\x2f\xc8\xcf\xc9\x4c\xae\x54\xf0\x0a\xf6\xf7\x73\x52\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xaf\xae\x55\xb7\xc6\x6a\x56\x7c\x62\x69\x4a\x66\x09\xb9\x26\x72\xe9\x22\x39\xd7\x25\xbf\x3c\x8f\x0b\x9f\x25\x2e\x41\xfe\x01\xd8\x6d\xc1\xee\x36\xbc\x1a\x00\x9d\x48\x01\xdd\x29\x01\x00\x00")
+
+func migrations20201020000000AddAccountsSigningPolicySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20201020000000AddAccountsSigningPolicySql,
+		"migrations/20201020000000-add-accounts-signing-policy.sql",
+	)
+}
+
+func migrations20201020000000AddAccountsSigningPolicySql() (*asset, error) {
+	bytes, err := migrations20201020000000AddAccountsSigningPolicySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20201020000000-add-accounts-signing-policy.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x15, 0x5c, 0x8d, 0x7a, 0x58, 0xe2, 0x10, 0xc5, 0xfd, 0x97, 0x9c, 0x6b, 0x75, 0x5c, 0xef, 0xb7, 0x80, 0xae, 0x88, 0xfe, 0xe7, 0x3, 0x7f, 0x2, 0x17, 0x5a, 0x70, 0x6, 0x82, 0xf4, 0x6d, 0x32}}
+	return a, nil
+}
+
+var _migrations20201020000001CreateSigningDecisionsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9d\x54\x51\x93\x9a\x30\x10\x7e\xe7\x57\xec\xc3\xcd\xa8\x53\xed\x1f\xe0\x29\xca\xea\x31\x45\x60\x62\x18\xa5\x2f\x4e\x0a\x39\xa5\xe3\x05\x1a\x72\xd7\xf1\xdf\x77\x51\xd1\xbb\x81\xf6\xbc\x3e\x00\x09\xbb\xfb\xed\xf7\xed\x6e\x32\x99\xc0\x97\xe7\x62\x67\xa4\x55\x90\x54\x8e\x33\xe3\xc8\x04\x82\x48\x63\x84\xba\xd8\xe9\x42\xef\xb6\xb9\xca\x8a\xba\x28\x35\xb0\x15\x60\x98\x2c\x61\xe8\x00\x0c\x64\x55\x99\xf2\x55\xe5\x83\x71\xb3\x6b\x7c\xdb\xb5\x51\x3f\x55\x66\x69\xe7\x8c\x5c\xc7\x99\x4c\x40\xec\x55\x0b\x06\x2d\x58\x0d\xd2\x28\xd0\xa5\x05\xa3\x9e\x94\x51\x3a\x6b\xac\x96\x3c\x65\x96\x95\x2f\xda\xd6\x60\xe5\x8f\x03\x05\x96\xf4\x57\xda\xc6\x74\x6c\xc0\x9a\x30\xa3\xac\x2c\x28\x1f\xc8\x27\xab\x0c\x48\xdd\x06\x41\x51\x53\x86\x83\xa2\xec\x5f\xaf\x5a\xd8\x34\xe8\x8a\xa9\x4f\x2a\x8a\x1c\xa6\xfe\xc2\x0f\x05\x84\x11\x3d\x49\x10\x40\xcc\xfd\x25\xe3\x29\x7c\xc3\x14\x16\x18\x22\x27\x0c\x0f\x58\xb0\x66\xe9\xaa\xa9\x80\xef\x61\x28\x7c\x91\x8e\x1d\x8a\xcf\x8c\xa2\xca\xe5\x5b\xe2\x27\xfc\x25\xae\x04\x5b\xc6\xb0\xf6\xc5\xe3\x69\x0b\xdf\xa3\x10\x6f\xc8\x1e\xce\x59\x12\x34\xa9\xd6\xc3\xd1\x29\x5c\xe6\xb9\x51\x75\x0d\x02\x37\x37\x06\x4d\x0d\x5b\xb6\x7f\x75\xb0\x46\xea\x5a\x66\x96\x94\x6c\xf7\xb2\xde\x77\x3d\x8a\x5c\x69\x5b\xd8\x63\xd7\x72\xed\x67\xa7\xc1\x6f\xbd\x48\x59\x4d\xbf\xde\x45\x5f\x25\x0c\xce\xbd\xbd\x54\xd8\x0f\x3d\xdc\x40\x14\xf6\x15\x39\x89\x63\xe4\xc3\x8b\x8e\xd1\xb8\x43\xfc\x32\x22\xd7\x21\x5c\x59\x7a\x3f\x13\xf5\xa9\xda\x15\xba\xcd\x30\x4f\xc2\x99\xf0\x29\x43\x65\xd4\x2b\x19\xb7\x9d\x4c\xdb\x6c\x2f\xf5\x4e\x0d\x47\xc0\x51\x24\x3c\x5c\x81\xe0\xfe\x62\x81\xbc\xe9\xd9\xc3\x34\xf2\xd2\x07\x12\x35\x45\x6a\x36\x7d\x01\x38\xf3\x57\x08\xb8\x99\x61\x7c\x02\x1e\x74\xb9\xd3\x2c\xd1\x90\x2b\x9d\x4f\x4a\x7d\x38\x0e\x5c\x8a\xc3\xd0\x73\x9d\x33\x1a\x04\x2c\x5c\x24\x6c\x81\x50\x1d\xaa\x5d\xfd\xeb\xe0\xf6\xeb\x40\x9d\xdf\x8e\xd5\x85\xd2\x47\x2a\x9c\x29\xce\x23\x8e\x90\xc4\x5e\x13\x16\x71\x2a\x7c\x80\xcd\xaa\xa7\xc6\x44\x8b\x9c\x01\xd9\xec\x11\x78\xb4\x26\x4d\x38\x4b\xc8\x37\xe6\xd1\x0c\xbd\x84\x60\x3e\x2e\x9a\xfb\x09\x8a\xd6\xbc\xe8\x8c\xc4\xb5\x24\x05\xa7\xe6\xb0\x3b\xc8\xd1\xe9\x10\xb8\xa4\xd3\xf3\xbf\x14\xdf\xd6\xd7\x2b\x7f\x6b\xc7\xf1\x78\x14\x7f\x82\x72\x2f\x45\xf7\x5e\x94\x33\x93\x7f\x61\xdc\x3d\xa6\x6d\xce\xfe\xab\xa9\xb5\xf6\x5d\xc2\xae\xf3\x07\x02\x6d\xf0\xe9\xb3\x05\x00\x00")
+
+func migrations20201020000001CreateSigningDecisionsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20201020000001CreateSigningDecisionsSql,
+		"migrations/20201020000001-create-signing-decisions.sql",
+	)
+}
+
+func migrations20201020000001CreateSigningDecisionsSql() (*asset, error) {
+	bytes, err := migrations20201020000001CreateSigningDecisionsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20201020000001-create-signing-decisions.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc9, 0x70, 0x39, 0x84, 0x27, 0x85, 0x8c, 0x8f, 0x3b, 0x57, 0x54, 0x4d, 0x74, 0xb6, 0xb8, 0xc8, 0xc2, 0x19, 0x27, 0xcd, 0xd0, 0x24, 0xb1, 0x2d, 0xa6, 0xc, 0x4e, 0xb9, 0x1b, 0xfd, 0x9f, 0x8e}}
+	return a, nil
+}
+
+var _migrations20201021000000CreateRecoveryRequestsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x53\xc1\x6e\xdb\x30\x0c\xbd\xeb\x2b\x78\x4b\x8b\x39\xc3\xee\x39\x79\x8b\x86\x19\x4b\x1c\xc3\x95\xb1\xa6\x17\x43\xb5\x98\x58\x98\x23\x79\x12\xdd\x36\x7f\x3f\xd9\x73\x8d\xb6\x69\xba\x1c\x7c\xa0\xf8\xf8\x48\x3e\x3f\xce\xe7\xf0\xe9\xa0\xf7\x4e\x12\x42\xd1\x32\xf6\x2d\xe7\xb1\xe0\x20\xb6\x19\x07\x87\x95\x7d\x40\x77\x2c\x1d\xfe\xe9\xd0\x53\xe9\x49\x52\xe7\x21\xbe\x01\x9e\x16\x6b\xb8\x62\x00\xb3\x16\x8d\xd2\x66\x3f\x8b\xfa\xa0\xb2\x66\xa7\xdd\x01\xd5\x18\x4a\x53\x61\xd3\x84\x90\x5d\x2f\x18\x9b\xcf\x41\xd4\x38\xd1\xc2\x48\xeb\x41\x3a\x04\x63\x29\x3c\xec\xd0\xa1\xa9\x02\x1f\x50\x40\xca\xaa\xb2\x9d\x09\x00\x92\xf7\x0d\x82\xb7\xe1\x55\x52\x9f\x3a\xf6\x64\x7d\x99\x43\x92\xda\xa0\x02\xb9\x23\x74\x20\xcd\x73\x11\x68\x0f\x0a\x1b\x24\x54\x11\x34\xfa\x37\x0e\x8c\x5e\xef\x4d\xcf\xae\xb0\xd2\x5e\x5b\xe3\x3f\x4f\x1b\xc7\x5f\x57\xa7\x2b\xfb\x61\x49\xad\x40\xf0\x5b\x01\xe9\x26\x7c\xc5\x6a\x05\x59\x9e\xac\xe3\x7c\x0b\x3f\xf9\x36\x62\x01\x50\x39\x0c\x02\xaa\x32\x0c\x27\x92\x35\xbf\x11\xf1\x3a\x83\x5f\x89\xf8\x31\x84\x70\xb7\x49\xf9\x54\xdc\x2b\xd3\xb5\xea\x7f\xf8\x81\x57\x2a\xe5\xd0\xfb\xd7\xdd\xa3\x61\x22\x34\xa4\xe9\x78\x9a\x91\x1d\xd5\xe5\x01\xa9\xb6\xaa\xa4\x63\x8b\xa7\x0f\xe7\xd0\x0f\xb2\xe9\xf0\x94\xb0\xb2\x0a\xcb\x5a\xfa\xfa\x9d\x5e\x44\x78\x68\x83\x48\x49\xfa\x42\x9c\x25\xff\x1e\x17\x2b\x01\x5f\x7a\xc8\x68\x99\x73\x56\x7a\xc9\x86\x4f\xad\x0e\xdb\x5e\x2c\xe2\x64\xb6\x8f\x65\x0c\x63\xb6\xad\x0b\xdd\x2f\xc4\xc9\xa6\xbc\x60\x92\x61\x80\x67\x7b\x5f\x0e\xbc\x7f\xf3\xc7\x26\xb1\x66\xff\x4e\x64\x34\x63\x92\x2e\xf9\x2d\x6c\xd2\xf7\xfc\x58\x64\x19\xcf\xaf\x46\x67\x5c\x47\x93\x15\xc6\x0b\x9b\xae\x79\x69\x1f\x0d\x63\xcb\x7c\x93\x9d\xf3\xf6\x62\xcc\x7e\x70\xeb\x0b\xf6\x17\xda\x67\x0c\x5e\x21\x04\x00\x00")
+
+func migrations20201021000000CreateRecoveryRequestsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20201021000000CreateRecoveryRequestsSql,
+		"migrations/20201021000000-create-recovery-requests.sql",
+	)
+}
+
+func migrations20201021000000CreateRecoveryRequestsSql() (*asset, error) {
+	bytes, err := migrations20201021000000CreateRecoveryRequestsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20201021000000-create-recovery-requests.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xef, 0xeb, 0x13, 0x29, 0xf0, 0x74, 0x8b, 0x3e, 0x86, 0x89, 0x57, 0x37, 0x6e, 0xf0, 0xdc, 0xd8, 0xe6, 0xa5, 0x56, 0x50, 0x46, 0xf0, 0x39, 0x36, 0x56, 0xf2, 0xaf, 0x2d, 0x9c, 0x5, 0x70, 0x88}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -327,25 +390,30 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations/20200309000000-initial-1.sql":                 migrations20200309000000Initial1Sql,
-	"migrations/20200309000001-initial-2.sql":                 migrations20200309000001Initial2Sql,
-	"migrations/20200311000000-create-accounts.sql":           migrations20200311000000CreateAccountsSql,
-	"migrations/20200311000001-create-identities.sql":         migrations20200311000001CreateIdentitiesSql,
-	"migrations/20200311000002-create-auth-methods.sql":       migrations20200311000002CreateAuthMethodsSql,
-	"migrations/20200320000000-create-accounts-audit.sql":     migrations20200320000000CreateAccountsAuditSql,
-	"migrations/20200320000001-create-identities-audit.sql":   migrations20200320000001CreateIdentitiesAuditSql,
-	"migrations/20200320000002-create-auth-methods-audit.sql": migrations20200320000002CreateAuthMethodsAuditSql,
+	"migrations/20200309000000-initial-1.sql":                   migrations20200309000000Initial1Sql,
+	"migrations/20200309000001-initial-2.sql":                   migrations20200309000001Initial2Sql,
+	"migrations/20200311000000-create-accounts.sql":             migrations20200311000000CreateAccountsSql,
+	"migrations/20200311000001-create-identities.sql":           migrations20200311000001CreateIdentitiesSql,
+	"migrations/20200311000002-create-auth-methods.sql":         migrations20200311000002CreateAuthMethodsSql,
+	"migrations/20200320000000-create-accounts-audit.sql":       migrations20200320000000CreateAccountsAuditSql,
+	"migrations/20200320000001-create-identities-audit.sql":     migrations20200320000001CreateIdentitiesAuditSql,
+	"migrations/20200320000002-create-auth-methods-audit.sql":   migrations20200320000002CreateAuthMethodsAuditSql,
+	"migrations/20201020000000-add-accounts-signing-policy.sql": migrations20201020000000AddAccountsSigningPolicySql,
+	"migrations/20201020000001-create-signing-decisions.sql":    migrations20201020000001CreateSigningDecisionsSql,
+	"migrations/20201021000000-create-recovery-requests.sql":    migrations20201021000000CreateRecoveryRequestsSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -379,14 +447,17 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations": &bintree{nil, map[string]*bintree{
-		"20200309000000-initial-1.sql":                 &bintree{migrations20200309000000Initial1Sql, map[string]*bintree{}},
-		"20200309000001-initial-2.sql":                 &bintree{migrations20200309000001Initial2Sql, map[string]*bintree{}},
-		"20200311000000-create-accounts.sql":           &bintree{migrations20200311000000CreateAccountsSql, map[string]*bintree{}},
-		"20200311000001-create-identities.sql":         &bintree{migrations20200311000001CreateIdentitiesSql, map[string]*bintree{}},
-		"20200311000002-create-auth-methods.sql":       &bintree{migrations20200311000002CreateAuthMethodsSql, map[string]*bintree{}},
-		"20200320000000-create-accounts-audit.sql":     &bintree{migrations20200320000000CreateAccountsAuditSql, map[string]*bintree{}},
-		"20200320000001-create-identities-audit.sql":   &bintree{migrations20200320000001CreateIdentitiesAuditSql, map[string]*bintree{}},
-		"20200320000002-create-auth-methods-audit.sql": &bintree{migrations20200320000002CreateAuthMethodsAuditSql, map[string]*bintree{}},
+		"20200309000000-initial-1.sql":                   &bintree{migrations20200309000000Initial1Sql, map[string]*bintree{}},
+		"20200309000001-initial-2.sql":                   &bintree{migrations20200309000001Initial2Sql, map[string]*bintree{}},
+		"20200311000000-create-accounts.sql":             &bintree{migrations20200311000000CreateAccountsSql, map[string]*bintree{}},
+		"20200311000001-create-identities.sql":           &bintree{migrations20200311000001CreateIdentitiesSql, map[string]*bintree{}},
+		"20200311000002-create-auth-methods.sql":         &bintree{migrations20200311000002CreateAuthMethodsSql, map[string]*bintree{}},
+		"20200320000000-create-accounts-audit.sql":       &bintree{migrations20200320000000CreateAccountsAuditSql, map[string]*bintree{}},
+		"20200320000001-create-identities-audit.sql":     &bintree{migrations20200320000001CreateIdentitiesAuditSql, map[string]*bintree{}},
+		"20200320000002-create-auth-methods-audit.sql":   &bintree{migrations20200320000002CreateAuthMethodsAuditSql, map[string]*bintree{}},
+		"20201020000000-add-accounts-signing-policy.sql": &bintree{migrations20201020000000AddAccountsSigningPolicySql, map[string]*bintree{}},
+		"20201020000001-create-signing-decisions.sql":    &bintree{migrations20201020000001CreateSigningDecisionsSql, map[string]*bintree{}},
+		"20201021000000-create-recovery-requests.sql":    &bintree{migrations20201021000000CreateRecoveryRequestsSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -157,6 +157,9 @@ func TestMigrate_upApplyAll(t *testing.T) {
 		"20200320000000-create-accounts-audit.sql",
 		"20200320000001-create-identities-audit.sql",
 		"20200320000002-create-auth-methods-audit.sql",
+		"20201020000000-add-accounts-signing-policy.sql",
+		"20201020000001-create-signing-decisions.sql",
+		"20201021000000-create-recovery-requests.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -186,6 +189,9 @@ func TestMigrate_upApplyNone(t *testing.T) {
 		"20200320000000-create-accounts-audit.sql",
 		"20200320000001-create-identities-audit.sql",
 		"20200320000002-create-auth-methods-audit.sql",
+		"20201020000000-add-accounts-signing-policy.sql",
+		"20201020000001-create-signing-decisions.sql",
+		"20201021000000-create-recovery-requests.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+ALTER TABLE accounts ADD COLUMN signing_policy JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE accounts_audit ADD COLUMN signing_policy JSONB NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+
+ALTER TABLE accounts_audit DROP COLUMN signing_policy;
+ALTER TABLE accounts DROP COLUMN signing_policy;
@@ -0,0 +1,48 @@
+-- +migrate Up
+
+CREATE TYPE signing_decision AS ENUM (
+  'approved',
+  'signed',
+  'rejected'
+);
+
+-- The signing decisions are not referencing the accounts table so that they
+-- are retained after an account is deleted.
+CREATE TABLE signing_decisions (
+  id BIGINT NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+  address TEXT NOT NULL,
+  signing_address TEXT NOT NULL,
+  transaction_hash TEXT NOT NULL,
+  identity TEXT NOT NULL,
+  decision signing_decision NOT NULL,
+  reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ON signing_decisions (UPPER(address), transaction_hash);
+
+-- +migrate StatementBegin
+CREATE FUNCTION prevent_signing_decisions_change() RETURNS TRIGGER AS $BODY$
+  BEGIN
+    RAISE EXCEPTION 'signing_decisions is append-only';
+  END;
+$BODY$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER prevent_signing_decisions_change
+BEFORE UPDATE OR DELETE ON signing_decisions
+  FOR EACH ROW EXECUTE PROCEDURE prevent_signing_decisions_change();
+
+CREATE TRIGGER prevent_signing_decisions_truncate
+BEFORE TRUNCATE ON signing_decisions
+  FOR EACH STATEMENT EXECUTE PROCEDURE prevent_signing_decisions_change();
+
+-- +migrate Down
+
+DROP TRIGGER prevent_signing_decisions_truncate ON signing_decisions;
+DROP TRIGGER prevent_signing_decisions_change ON signing_decisions;
+DROP FUNCTION prevent_signing_decisions_change;
+DROP TABLE signing_decisions;
+DROP TYPE signing_decision;
@@ -0,0 +1,37 @@
+-- +migrate Up
+
+CREATE TYPE recovery_request_status AS ENUM (
+  'pending',
+  'confirmed',
+  'cancelled'
+);
+
+-- The recovery requests are not referencing the accounts table so that they
+-- are retained after an account is deleted, like the signing decisions.
+CREATE TABLE recovery_requests (
+  id TEXT NOT NULL PRIMARY KEY,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE,
+
+  address TEXT NOT NULL,
+  identity TEXT NOT NULL,
+  auth_method_type auth_method_type NOT NULL,
+  auth_method_value TEXT NOT NULL,
+  code_hash TEXT NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  status recovery_request_status NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  confirmed_at TIMESTAMP WITH TIME ZONE,
+  approved_at TIMESTAMP WITH TIME ZONE,
+  approval_expires_at TIMESTAMP WITH TIME ZONE,
+  cancelled_at TIMESTAMP WITH TIME ZONE,
+  cancelled_by TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ON recovery_requests (UPPER(address), identity);
+
+-- +migrate Down
+
+DROP TABLE recovery_requests;
+DROP TYPE recovery_request_status;
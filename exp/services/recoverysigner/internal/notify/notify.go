@@ -0,0 +1,32 @@
+// Package notify sends notifications to the identities of accounts through
+// their auth methods, such as recovery codes and alerts that a recovery of
+// their account has been requested.
+package notify
+
+import (
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// Notifier sends messages to auth methods. Implementations deliver messages
+// to the phone number, email, or Stellar address of the auth method, or
+// return an error if they do not support the type of auth method.
+type Notifier interface {
+	Notify(to account.AuthMethod, message string) error
+}
+
+// LogNotifier is a Notifier that logs that messages would have been sent
+// instead of delivering them, for use in development and testing. The
+// messages themselves are not logged as they contain recovery codes that
+// anyone with access to the logs could use to confirm recoveries.
+type LogNotifier struct {
+	Logger *supportlog.Entry
+}
+
+// Notify logs that the message was not delivered, without the message.
+func (n LogNotifier) Notify(to account.AuthMethod, message string) error {
+	n.Logger.
+		WithField("type", to.Type).
+		Info("Notification not delivered, no notifier is configured.")
+	return nil
+}
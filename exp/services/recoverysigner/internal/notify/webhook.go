@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/support/errors"
+)
+
+// WebhookNotifier is a Notifier that delivers messages by posting them to a
+// webhook, which is responsible for sending them by SMS, email or otherwise
+// to the auth method. The webhook is posted
+//
+//	{"type":"phone_number","to":"+10000000000","message":"..."}
+//
+// and must respond with a 2xx status once it has accepted the message.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+type webhookRequest struct {
+	Type    account.AuthMethodType `json:"type"`
+	To      string                 `json:"to"`
+	Message string                 `json:"message"`
+}
+
+// Notify posts the message to the webhook.
+func (n WebhookNotifier) Notify(to account.AuthMethod, message string) error {
+	body, err := json.Marshal(webhookRequest{Type: to.Type, To: to.Value, Message: message})
+	if err != nil {
+		return err
+	}
+	resp, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "posting notification to webhook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("posting notification to webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
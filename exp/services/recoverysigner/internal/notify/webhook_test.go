@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var got webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		err := json.NewDecoder(r.Body).Decode(&got)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := WebhookNotifier{URL: server.URL, Client: server.Client()}
+	err := n.Notify(account.AuthMethod{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"}, "Your code is 123456.")
+	require.NoError(t, err)
+
+	want := webhookRequest{
+		Type:    account.AuthMethodTypePhoneNumber,
+		To:      "+10000000000",
+		Message: "Your code is 123456.",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestWebhookNotifier_errorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := WebhookNotifier{URL: server.URL, Client: server.Client()}
+	err := n.Notify(account.AuthMethod{Type: account.AuthMethodTypeEmail, Value: "user1@example.com"}, "message")
+	assert.EqualError(t, err, "posting notification to webhook: unexpected status 500")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/http/httpdecode"
 	supportlog "github.com/stellar/go/support/log"
@@ -12,9 +13,9 @@ import (
 )
 
 type accountDeleteHandler struct {
-	Logger           *supportlog.Entry
-	SigningAddresses []*keypair.FromAddress
-	AccountStore     account.Store
+	Logger       *supportlog.Entry
+	SigningKeys  signer.KeySource
+	AccountStore account.Store
 }
 
 type accountDeleteRequest struct {
@@ -42,6 +43,13 @@ func (h accountDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	l.Info("Request to delete account.")
 
+	signers, err := newAccountResponseSigners(h.SigningKeys)
+	if err != nil {
+		l.Error("Error getting signing keys: ", err)
+		serverError.Render(w)
+		return
+	}
+
 	acc, err := h.AccountStore.Get(req.Address.Address())
 	if err == account.ErrNotFound {
 		l.Info("Account not found.")
@@ -54,13 +62,10 @@ func (h accountDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	resp := accountResponse{
-		Address: acc.Address,
-	}
-	for _, signingAddress := range h.SigningAddresses {
-		resp.Signers = append(resp.Signers, accountResponseSigner{
-			Key: signingAddress.Address(),
-		})
+		Address:       acc.Address,
+		SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 	}
+	resp.Signers = signers
 
 	// Authorized if authenticated as the account.
 	authorized := claims.Address == req.Address.Address()
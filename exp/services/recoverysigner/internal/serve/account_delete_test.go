@@ -11,7 +11,7 @@ import (
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,9 +35,9 @@ func TestAccountDelete_authenticatedNotAuthorized(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -81,9 +81,9 @@ func TestAccountDelete_notAuthenticated(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -127,9 +127,9 @@ func TestAccountDelete_notFound(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -179,9 +179,9 @@ func TestAccountDelete_authenticatedByIdentityAddress(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -254,9 +254,9 @@ func TestAccountDelete_authenticatedByAccountAddress(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -328,9 +328,9 @@ func TestAccountDelete_authenticatedByPhoneNumber(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -403,9 +403,9 @@ func TestAccountDelete_authenticatedByEmail(t *testing.T) {
 	h := accountDeleteHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
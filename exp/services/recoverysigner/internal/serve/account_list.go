@@ -5,15 +5,15 @@ import (
 
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
 )
 
 type accountListHandler struct {
-	Logger           *supportlog.Entry
-	SigningAddresses []*keypair.FromAddress
-	AccountStore     account.Store
+	Logger       *supportlog.Entry
+	SigningKeys  signer.KeySource
+	AccountStore account.Store
 }
 
 type accountListResponse struct {
@@ -33,6 +33,13 @@ func (h accountListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	l.Info("Request to get accounts.")
 
+	signers, err := newAccountResponseSigners(h.SigningKeys)
+	if err != nil {
+		l.Error("Error getting signing keys: ", err)
+		serverError.Render(w)
+		return
+	}
+
 	resp := accountListResponse{
 		Accounts: []accountResponse{},
 	}
@@ -49,13 +56,10 @@ func (h accountListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		} else {
 			accResp := accountResponse{
-				Address: acc.Address,
-			}
-			for _, signingAddress := range h.SigningAddresses {
-				accResp.Signers = append(accResp.Signers, accountResponseSigner{
-					Key: signingAddress.Address(),
-				})
+				Address:       acc.Address,
+				SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 			}
+			accResp.Signers = signers
 			for _, i := range acc.Identities {
 				accRespIdentity := accountResponseIdentity{
 					Role: i.Role,
@@ -77,13 +81,10 @@ func (h accountListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		for _, acc := range accs {
 			accResp := accountResponse{
-				Address: acc.Address,
-			}
-			for _, signingAddress := range h.SigningAddresses {
-				accResp.Signers = append(accResp.Signers, accountResponseSigner{
-					Key: signingAddress.Address(),
-				})
+				Address:       acc.Address,
+				SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 			}
+			accResp.Signers = signers
 			for _, i := range acc.Identities {
 				accRespIdentity := accountResponseIdentity{
 					Role: i.Role,
@@ -113,13 +114,10 @@ func (h accountListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		for _, acc := range accs {
 			accResp := accountResponse{
-				Address: acc.Address,
-			}
-			for _, signingAddress := range h.SigningAddresses {
-				accResp.Signers = append(accResp.Signers, accountResponseSigner{
-					Key: signingAddress.Address(),
-				})
+				Address:       acc.Address,
+				SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 			}
+			accResp.Signers = signers
 			for _, i := range acc.Identities {
 				accRespIdentity := accountResponseIdentity{
 					Role: i.Role,
@@ -149,13 +147,10 @@ func (h accountListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		for _, acc := range accs {
 			accResp := accountResponse{
-				Address: acc.Address,
-			}
-			for _, signingAddress := range h.SigningAddresses {
-				accResp.Signers = append(accResp.Signers, accountResponseSigner{
-					Key: signingAddress.Address(),
-				})
+				Address:       acc.Address,
+				SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 			}
+			accResp.Signers = signers
 			for _, i := range acc.Identities {
 				accRespIdentity := accountResponseIdentity{
 					Role: i.Role,
@@ -10,7 +10,7 @@ import (
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -48,9 +48,9 @@ func TestAccountList_authenticatedButNonePermitted(t *testing.T) {
 	h := accountListHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -113,9 +113,9 @@ func TestAccountList_authenticatedByPhoneNumber(t *testing.T) {
 	h := accountListHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -211,9 +211,9 @@ func TestAccountList_authenticatedByEmail(t *testing.T) {
 	h := accountListHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -309,9 +309,9 @@ func TestAccountList_notAuthenticated(t *testing.T) {
 	h := accountListHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httpdecode"
@@ -13,20 +14,27 @@ import (
 )
 
 type accountPostHandler struct {
-	Logger           *supportlog.Entry
-	SigningAddresses []*keypair.FromAddress
-	AccountStore     account.Store
+	Logger       *supportlog.Entry
+	SigningKeys  signer.KeySource
+	AccountStore account.Store
 }
 
 type accountPostRequest struct {
-	Address    *keypair.FromAddress         `path:"address"`
-	Identities []accountPostRequestIdentity `json:"identities" form:"identities"`
+	Address       *keypair.FromAddress         `path:"address"`
+	Identities    []accountPostRequestIdentity `json:"identities" form:"identities"`
+	SigningPolicy *accountSigningPolicy        `json:"signing_policy"`
 }
 
 func (r accountPostRequest) Validate() error {
 	if len(r.Identities) == 0 {
 		return errors.Errorf("no identities provided but at least one is required")
 	}
+	if r.SigningPolicy != nil {
+		err := r.SigningPolicy.Validate()
+		if err != nil {
+			return err
+		}
+	}
 	for _, i := range r.Identities {
 		err := i.Validate()
 		if err != nil {
@@ -91,6 +99,13 @@ func (h accountPostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	l.Info("Request to register account.")
 
+	signers, err := newAccountResponseSigners(h.SigningKeys)
+	if err != nil {
+		l.Error("Error getting signing keys: ", err)
+		serverError.Render(w)
+		return
+	}
+
 	if req.Address.Address() != claims.Address {
 		l.WithField("address", claims.Address).
 			Info("Not authorized as self, authorized as other address.")
@@ -108,6 +123,9 @@ func (h accountPostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	acc := account.Account{
 		Address: req.Address.Address(),
 	}
+	if req.SigningPolicy != nil {
+		acc.SigningPolicy = req.SigningPolicy.SigningPolicy()
+	}
 	for _, i := range req.Identities {
 		accIdentity := account.Identity{
 			Role: i.Role,
@@ -139,13 +157,10 @@ func (h accountPostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l.Info("Account registered.")
 
 	resp := accountResponse{
-		Address: acc.Address,
-	}
-	for _, signingAddress := range h.SigningAddresses {
-		resp.Signers = append(resp.Signers, accountResponseSigner{
-			Key: signingAddress.Address(),
-		})
+		Address:       acc.Address,
+		SigningPolicy: newAccountSigningPolicy(acc.SigningPolicy),
 	}
+	resp.Signers = signers
 	for _, i := range acc.Identities {
 		respIdentity := accountResponseIdentity{
 			Role: i.Role,
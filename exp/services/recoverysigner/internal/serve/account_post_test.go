@@ -13,7 +13,7 @@ import (
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,9 +24,9 @@ func TestAccountPost_newWithRoleOwnerContentTypeJSON(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -100,9 +100,9 @@ func TestAccountPost_newWithRoleOwnerContentTypeForm(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -175,9 +175,9 @@ func TestAccountPost_newWithRolesSenderReceiverContentTypeJSON(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -268,9 +268,9 @@ func TestAccountPost_newWithRolesSenderReceiverContentTypeForm(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -362,9 +362,9 @@ func TestAccountPost_accountAddressInvalid(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -403,9 +403,9 @@ func TestAccountPost_accountAlreadyExists(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -454,9 +454,9 @@ func TestAccountPost_identitiesNotProvided(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -492,9 +492,9 @@ func TestAccountPost_roleNotProvided(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -540,9 +540,9 @@ func TestAccountPost_authMethodsNotProvided(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -587,9 +587,9 @@ func TestAccountPost_authMethodTypeUnrecognized(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -641,9 +641,9 @@ func TestAccountPost_notAuthenticatedForAccount(t *testing.T) {
 	h := accountPostHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
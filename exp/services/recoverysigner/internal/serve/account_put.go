@@ -5,6 +5,7 @@ import (
 
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httpdecode"
@@ -13,20 +14,27 @@ import (
 )
 
 type accountPutHandler struct {
-	Logger           *supportlog.Entry
-	SigningAddresses []*keypair.FromAddress
-	AccountStore     account.Store
+	Logger       *supportlog.Entry
+	SigningKeys  signer.KeySource
+	AccountStore account.Store
 }
 
 type accountPutRequest struct {
-	Address    *keypair.FromAddress        `path:"address"`
-	Identities []accountPutRequestIdentity `json:"identities" form:"identities"`
+	Address       *keypair.FromAddress        `path:"address"`
+	Identities    []accountPutRequestIdentity `json:"identities" form:"identities"`
+	SigningPolicy *accountSigningPolicy       `json:"signing_policy"`
 }
 
 func (r accountPutRequest) Validate() error {
 	if len(r.Identities) == 0 {
 		return errors.Errorf("no identities provided but at least one is required")
 	}
+	if r.SigningPolicy != nil {
+		err := r.SigningPolicy.Validate()
+		if err != nil {
+			return err
+		}
+	}
 	for _, i := range r.Identities {
 		err := i.Validate()
 		if err != nil {
@@ -91,6 +99,13 @@ func (h accountPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	l.Info("Request to update account.")
 
+	signers, err := newAccountResponseSigners(h.SigningKeys)
+	if err != nil {
+		l.Error("Error getting signing keys: ", err)
+		serverError.Render(w)
+		return
+	}
+
 	if req.Validate() != nil {
 		l.Info("Request validation failed.")
 		badRequest.Render(w)
@@ -109,7 +124,8 @@ func (h accountPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authorized if authenticated as the account.
-	authorized := claims.Address == req.Address.Address()
+	authorizedSelf := claims.Address == req.Address.Address()
+	authorized := authorizedSelf
 	l.Infof("Authorized with self: %v.", authorized)
 
 	// Authorized if authenticated as an identity registered with the account.
@@ -129,10 +145,24 @@ func (h accountPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The signing policy is kept if it is not in the request. It can only be
+	// changed by the account, otherwise an identity could loosen the policy
+	// that restricts it, such as the quorum of identities.
+	signingPolicy := acc.SigningPolicy
+	if req.SigningPolicy != nil {
+		signingPolicy = req.SigningPolicy.SigningPolicy()
+	}
+	if signingPolicy != acc.SigningPolicy && !authorizedSelf {
+		l.Info("Not authorized as self to change the signing policy.")
+		unauthorized.Render(w)
+		return
+	}
+
 	authMethodCount := 0
 	accWithNewIdentiies := account.Account{
-		Address:    req.Address.Address(),
-		Identities: []account.Identity{},
+		Address:       req.Address.Address(),
+		Identities:    []account.Identity{},
+		SigningPolicy: signingPolicy,
 	}
 	for _, i := range req.Identities {
 		accIdentity := account.Identity{
@@ -166,13 +196,10 @@ func (h accountPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l.Info("Account updated.")
 
 	resp := accountResponse{
-		Address: accWithNewIdentiies.Address,
-	}
-	for _, signingAddress := range h.SigningAddresses {
-		resp.Signers = append(resp.Signers, accountResponseSigner{
-			Key: signingAddress.Address(),
-		})
+		Address:       accWithNewIdentiies.Address,
+		SigningPolicy: newAccountSigningPolicy(accWithNewIdentiies.SigningPolicy),
 	}
+	resp.Signers = signers
 	for _, i := range accWithNewIdentiies.Identities {
 		resp.Identities = append(resp.Identities, accountResponseIdentity{
 			Role: i.Role,
@@ -12,7 +12,7 @@ import (
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,9 +40,9 @@ func TestAccountPut_authenticatedNotAuthorized(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -104,9 +104,9 @@ func TestAccountPut_notAuthenticated(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -168,9 +168,9 @@ func TestAccountPut_authenticatedByAccountAddress(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -264,9 +264,9 @@ func TestAccountPut_authenticatedByIdentityAddress(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -360,9 +360,9 @@ func TestAccountPut_authenticatedByPhoneNumber(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -456,9 +456,9 @@ func TestAccountPut_authenticatedByEmail(t *testing.T) {
 	h := accountPutHandler{
 		Logger:       supportlog.DefaultLogger,
 		AccountStore: s,
-		SigningAddresses: []*keypair.FromAddress{
-			keypair.MustParseAddress("GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"),
-			keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"),
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+			{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"},
 		},
 	}
 
@@ -529,3 +529,149 @@ func TestAccountPut_authenticatedByEmail(t *testing.T) {
 	}
 	assert.Equal(t, wantAcc, acc)
 }
+
+func TestAccountPut_signingPolicyChangedByAccount(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	s.Add(account.Account{
+		Address: "GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N",
+		Identities: []account.Identity{
+			{
+				Role: "owner",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"},
+				},
+			},
+		},
+	})
+	h := accountPutHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+		},
+	}
+
+	ctx := context.Background()
+	ctx = auth.NewContext(ctx, auth.Auth{Address: "GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N"})
+	req := `{
+	"identities": [
+		{
+			"role": "owner",
+			"auth_methods": [
+				{ "type": "phone_number", "value": "+10000000000" }
+			]
+		}
+	],
+	"signing_policy": {
+		"only_signer_changes": true,
+		"max_operations": 2,
+		"quorum": 2
+	}
+}`
+	r := httptest.NewRequest("PUT", "/GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N", strings.NewReader(req))
+	r = r.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	m := chi.NewMux()
+	m.Put("/{address}", h.ServeHTTP)
+	m.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `{
+	"address": "GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N",
+	"identities": [
+		{ "role": "owner" }
+	],
+	"signers": [
+		{ "key": "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE", "added_at": "0001-01-01T00:00:00Z" }
+	],
+	"signing_policy": {
+		"only_signer_changes": true,
+		"deny_payments": false,
+		"max_operations": 2,
+		"require_time_bounds": false,
+		"max_validity_seconds": 0,
+		"quorum": 2,
+		"require_recovery_request": false,
+		"recovery_time_lock_seconds": 0
+	}
+}`
+	assert.JSONEq(t, wantBody, string(body))
+
+	acc, err := s.Get("GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N")
+	require.NoError(t, err)
+	wantSigningPolicy := account.SigningPolicy{
+		OnlySignerChanges: true,
+		MaxOperations:     2,
+		Quorum:            2,
+	}
+	assert.Equal(t, wantSigningPolicy, acc.SigningPolicy)
+}
+
+func TestAccountPut_signingPolicyChangedByIdentity(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	s.Add(account.Account{
+		Address: "GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N",
+		Identities: []account.Identity{
+			{
+				Role: "owner",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"},
+				},
+			},
+		},
+		SigningPolicy: account.SigningPolicy{
+			Quorum: 2,
+		},
+	})
+	h := accountPutHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		SigningKeys: signer.Keys{
+			{Address: "GCAPXRXSU7P6D353YGXMP6ROJIC744HO5OZCIWTXZQK2X757YU5KCHUE"},
+		},
+	}
+
+	ctx := context.Background()
+	ctx = auth.NewContext(ctx, auth.Auth{PhoneNumber: "+10000000000"})
+	req := `{
+	"identities": [
+		{
+			"role": "owner",
+			"auth_methods": [
+				{ "type": "phone_number", "value": "+10000000000" }
+			]
+		}
+	],
+	"signing_policy": {}
+}`
+	r := httptest.NewRequest("PUT", "/GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N", strings.NewReader(req))
+	r = r.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	m := chi.NewMux()
+	m.Put("/{address}", h.ServeHTTP)
+	m.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `{
+	"error": "The request could not be authenticated."
+}`
+	assert.JSONEq(t, wantBody, string(body))
+
+	acc, err := s.Get("GDIXCQJ2W2N6TAS6AYW4LW2EBV7XNRUCLNHQB37FARDEWBQXRWP47Q6N")
+	require.NoError(t, err)
+	assert.Equal(t, account.SigningPolicy{Quorum: 2}, acc.SigningPolicy)
+}
@@ -0,0 +1,87 @@
+package serve
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/notify"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+type accountRecoveryResponse struct {
+	ID                string     `json:"id"`
+	Address           string     `json:"address"`
+	Identity          string     `json:"identity"`
+	State             string     `json:"state"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	ConfirmedAt       *time.Time `json:"confirmed_at,omitempty"`
+	ApprovedAt        *time.Time `json:"approved_at,omitempty"`
+	ApprovalExpiresAt *time.Time `json:"approval_expires_at,omitempty"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`
+	CancelledBy       string     `json:"cancelled_by,omitempty"`
+}
+
+func newAccountRecoveryResponse(r account.RecoveryRequest, now time.Time) accountRecoveryResponse {
+	return accountRecoveryResponse{
+		ID:                r.ID,
+		Address:           r.Address,
+		Identity:          r.Identity,
+		State:             string(r.State(now)),
+		CreatedAt:         r.CreatedAt,
+		ExpiresAt:         r.ExpiresAt,
+		ConfirmedAt:       optionalTime(r.ConfirmedAt),
+		ApprovedAt:        optionalTime(r.ApprovedAt),
+		ApprovalExpiresAt: optionalTime(r.ApprovalExpiresAt),
+		CancelledAt:       optionalTime(r.CancelledAt),
+		CancelledBy:       r.CancelledBy,
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// authenticatedIdentity returns the identity of the account that the claims
+// are authenticated as, and the auth method it authenticated with.
+func authenticatedIdentity(acc account.Account, claims auth.Auth) (account.Identity, account.AuthMethod, bool) {
+	for _, i := range acc.Identities {
+		for _, m := range i.AuthMethods {
+			if m.Value != "" && ((m.Type == account.AuthMethodTypeAddress && m.Value == claims.Address) ||
+				(m.Type == account.AuthMethodTypePhoneNumber && m.Value == claims.PhoneNumber) ||
+				(m.Type == account.AuthMethodTypeEmail && m.Value == claims.Email)) {
+				return i, m, true
+			}
+		}
+	}
+	return account.Identity{}, account.AuthMethod{}, false
+}
+
+// identityID returns the ID of the identity in decimal, which is how
+// recovery requests and signing decisions record identities since several
+// identities of an account can share a role.
+func identityID(i account.Identity) string {
+	return strconv.FormatInt(i.ID, 10)
+}
+
+// notifyIdentities sends the message to every auth method of the identities
+// of the account other than the identity with the ID given. Errors are logged
+// and do not stop the other identities being notified.
+func notifyIdentities(l *supportlog.Entry, n notify.Notifier, acc account.Account, exceptIdentity, message string) {
+	for _, i := range acc.Identities {
+		if identityID(i) == exceptIdentity {
+			continue
+		}
+		for _, m := range i.AuthMethods {
+			err := n.Notify(m, message)
+			if err != nil {
+				l.Warnf("Error notifying identity %s with %s: %v", i.Role, m.Type, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/notify"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+type accountRecoveryCancelHandler struct {
+	Logger       *supportlog.Entry
+	AccountStore account.Store
+	Notifier     notify.Notifier
+}
+
+type accountRecoveryCancelRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+	ID      string               `path:"id"`
+}
+
+func (h accountRecoveryCancelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims, _ := auth.FromContext(ctx)
+	if claims.Address == "" && claims.PhoneNumber == "" && claims.Email == "" {
+		unauthorized.Render(w)
+		return
+	}
+
+	req := accountRecoveryCancelRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil || req.ID == "" {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(ctx).
+		WithField("account", req.Address.Address()).
+		WithField("recovery_request", req.ID)
+
+	l.Info("Request to cancel recovery request.")
+
+	acc, err := h.AccountStore.Get(req.Address.Address())
+	if err == account.ErrNotFound {
+		l.Info("Account not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Authorized if authenticated as the account or any of its identities,
+	// so that an identity can cancel a recovery requested by another.
+	// The name of who cancelled is used in notifications, in which
+	// identities are referred to by role.
+	cancelledBy, cancelledByName := "", ""
+	if claims.Address == req.Address.Address() {
+		cancelledBy = account.SigningIdentityAccount
+		cancelledByName = "the account"
+	} else if identity, _, ok := authenticatedIdentity(acc, claims); ok {
+		cancelledBy = identityID(identity)
+		cancelledByName = "identity " + identity.Role
+	}
+	l.Infof("Authorized: %v.", cancelledBy != "")
+	if cancelledBy == "" {
+		notFound.Render(w)
+		return
+	}
+
+	rr, err := h.AccountStore.GetRecoveryRequest(acc.Address, req.ID)
+	if err == account.ErrRecoveryRequestNotFound {
+		l.Info("Recovery request not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	now := time.Now()
+	state := rr.State(now)
+	if state == account.RecoveryRequestStateCancelled || state == account.RecoveryRequestStateExpired {
+		l.Infof("Recovery request cannot be cancelled in state %s.", state)
+		recoveryRequestNotPending.Render(w)
+		return
+	}
+
+	prev := rr
+	rr.Status = account.RecoveryRequestStatusCancelled
+	rr.CancelledAt = now
+	rr.CancelledBy = cancelledBy
+	err = h.AccountStore.UpdateRecoveryRequest(prev, rr)
+	if err == account.ErrRecoveryRequestConflict {
+		l.Info("Recovery request changed concurrently.")
+		recoveryRequestNotPending.Render(w)
+		return
+	} else if err != nil {
+		l.Error("Error updating recovery request:", err)
+		serverError.Render(w)
+		return
+	}
+
+	l.Infof("Recovery request cancelled by %s.", cancelledBy)
+
+	notifyIdentities(l, h.Notifier, acc, cancelledBy, fmt.Sprintf("The recovery of account %s (request %s) was cancelled by %s.", acc.Address, rr.ID, cancelledByName))
+
+	resp := newAccountRecoveryResponse(rr, now)
+	httpjson.Render(w, resp, httpjson.JSON)
+}
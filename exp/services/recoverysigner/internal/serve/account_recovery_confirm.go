@@ -0,0 +1,142 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/notify"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+type accountRecoveryConfirmHandler struct {
+	Logger       *supportlog.Entry
+	AccountStore account.Store
+	Notifier     notify.Notifier
+	// ApprovalExpiresIn is the time period after a recovery request is
+	// approved that it can be used to sign transactions.
+	ApprovalExpiresIn time.Duration
+}
+
+type accountRecoveryConfirmRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+	ID      string               `path:"id"`
+	Code    string               `json:"code" form:"code"`
+}
+
+func (h accountRecoveryConfirmHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims, _ := auth.FromContext(ctx)
+	if claims.Address == "" && claims.PhoneNumber == "" && claims.Email == "" {
+		unauthorized.Render(w)
+		return
+	}
+
+	req := accountRecoveryConfirmRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil || req.ID == "" || req.Code == "" {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(ctx).
+		WithField("account", req.Address.Address()).
+		WithField("recovery_request", req.ID)
+
+	l.Info("Request to confirm recovery request.")
+
+	acc, err := h.AccountStore.Get(req.Address.Address())
+	if err == account.ErrNotFound {
+		l.Info("Account not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	identity, _, authorized := authenticatedIdentity(acc, claims)
+	l.Infof("Authorized: %v.", authorized)
+	if !authorized {
+		notFound.Render(w)
+		return
+	}
+
+	rr, err := h.AccountStore.GetRecoveryRequest(acc.Address, req.ID)
+	if err == account.ErrRecoveryRequestNotFound {
+		l.Info("Recovery request not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Only the identity that created the request can confirm it.
+	if rr.Identity != identityID(identity) {
+		l.Infof("Recovery request belongs to identity %s not %s.", rr.Identity, identityID(identity))
+		notFound.Render(w)
+		return
+	}
+
+	now := time.Now()
+	state := rr.State(now)
+	if state != account.RecoveryRequestStatePendingConfirmation {
+		l.Infof("Recovery request cannot be confirmed in state %s.", state)
+		recoveryRequestNotPending.Render(w)
+		return
+	}
+
+	prev := rr
+	if !rr.CheckCode(req.Code) {
+		rr.Attempts++
+		if rr.Attempts >= account.RecoveryRequestMaxAttempts {
+			rr.Status = account.RecoveryRequestStatusCancelled
+			rr.CancelledAt = now
+		}
+		err = h.AccountStore.UpdateRecoveryRequest(prev, rr)
+		if err == account.ErrRecoveryRequestConflict {
+			l.Info("Recovery request changed concurrently.")
+			recoveryRequestNotPending.Render(w)
+			return
+		} else if err != nil {
+			l.Error("Error updating recovery request:", err)
+			serverError.Render(w)
+			return
+		}
+		l.Infof("Recovery request code incorrect, attempt %d of %d.", rr.Attempts, account.RecoveryRequestMaxAttempts)
+		unauthorized.Render(w)
+		return
+	}
+
+	timeLock := time.Duration(acc.SigningPolicy.RecoveryTimeLockSeconds) * time.Second
+	rr.Status = account.RecoveryRequestStatusConfirmed
+	rr.ConfirmedAt = now
+	rr.ApprovedAt = now.Add(timeLock)
+	rr.ApprovalExpiresAt = rr.ApprovedAt.Add(h.ApprovalExpiresIn)
+	err = h.AccountStore.UpdateRecoveryRequest(prev, rr)
+	if err == account.ErrRecoveryRequestConflict {
+		l.Info("Recovery request changed concurrently.")
+		recoveryRequestNotPending.Render(w)
+		return
+	} else if err != nil {
+		l.Error("Error updating recovery request:", err)
+		serverError.Render(w)
+		return
+	}
+
+	l.Infof("Recovery request confirmed, approved at %s.", rr.ApprovedAt.Format(time.RFC3339))
+
+	notifyIdentities(l, h.Notifier, acc, identityID(identity), fmt.Sprintf("Identity %s confirmed the recovery of account %s (request %s), which will be approved at %s. If this was not expected, cancel the request.", identity.Role, acc.Address, rr.ID, rr.ApprovedAt.UTC().Format(time.RFC3339)))
+
+	resp := newAccountRecoveryResponse(rr, now)
+	httpjson.Render(w, resp, httpjson.JSON)
+}
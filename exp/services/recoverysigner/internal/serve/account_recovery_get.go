@@ -0,0 +1,82 @@
+package serve
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+type accountRecoveryGetHandler struct {
+	Logger       *supportlog.Entry
+	AccountStore account.Store
+}
+
+type accountRecoveryGetRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+	ID      string               `path:"id"`
+}
+
+func (h accountRecoveryGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims, _ := auth.FromContext(ctx)
+	if claims.Address == "" && claims.PhoneNumber == "" && claims.Email == "" {
+		unauthorized.Render(w)
+		return
+	}
+
+	req := accountRecoveryGetRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil || req.ID == "" {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(ctx).
+		WithField("account", req.Address.Address()).
+		WithField("recovery_request", req.ID)
+
+	l.Info("Request to get recovery request.")
+
+	acc, err := h.AccountStore.Get(req.Address.Address())
+	if err == account.ErrNotFound {
+		l.Info("Account not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Authorized if authenticated as the account or any of its identities.
+	authorized := claims.Address == req.Address.Address()
+	if !authorized {
+		_, _, authorized = authenticatedIdentity(acc, claims)
+	}
+	l.Infof("Authorized: %v.", authorized)
+	if !authorized {
+		notFound.Render(w)
+		return
+	}
+
+	rr, err := h.AccountStore.GetRecoveryRequest(acc.Address, req.ID)
+	if err == account.ErrRecoveryRequestNotFound {
+		l.Info("Recovery request not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	resp := newAccountRecoveryResponse(rr, time.Now())
+	httpjson.Render(w, resp, httpjson.JSON)
+}
@@ -0,0 +1,101 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/notify"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+type accountRecoveryPostHandler struct {
+	Logger       *supportlog.Entry
+	AccountStore account.Store
+	Notifier     notify.Notifier
+	// CodeExpiresIn is the time period after which a recovery request that
+	// has not been confirmed expires.
+	CodeExpiresIn time.Duration
+}
+
+type accountRecoveryPostRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+}
+
+func (h accountRecoveryPostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims, _ := auth.FromContext(ctx)
+	if claims.Address == "" && claims.PhoneNumber == "" && claims.Email == "" {
+		unauthorized.Render(w)
+		return
+	}
+
+	req := accountRecoveryPostRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(ctx).
+		WithField("account", req.Address.Address())
+
+	l.Info("Request to create recovery request.")
+
+	acc, err := h.AccountStore.Get(req.Address.Address())
+	if err == account.ErrNotFound {
+		l.Info("Account not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Only identities of the account can request a recovery, the account
+	// itself has no need to recover.
+	identity, authMethod, authorized := authenticatedIdentity(acc, claims)
+	l.Infof("Authorized: %v.", authorized)
+	if !authorized {
+		notFound.Render(w)
+		return
+	}
+	l = l.WithField("identity", identityID(identity)).
+		WithField("identity_role", identity.Role)
+	l.Infof("Authorized with %s.", authMethod.Type)
+
+	now := time.Now()
+	rr, code, err := account.NewRecoveryRequest(acc.Address, identityID(identity), authMethod, now, h.CodeExpiresIn)
+	if err != nil {
+		l.Error("Error creating recovery request:", err)
+		serverError.Render(w)
+		return
+	}
+	err = h.AccountStore.AddRecoveryRequest(rr)
+	if err != nil {
+		l.Error("Error storing recovery request:", err)
+		serverError.Render(w)
+		return
+	}
+
+	l = l.WithField("recovery_request", rr.ID)
+	l.Info("Recovery request created.")
+
+	err = h.Notifier.Notify(authMethod, fmt.Sprintf("Your code to confirm the recovery of account %s is %s.", acc.Address, code))
+	if err != nil {
+		l.Error("Error sending recovery code:", err)
+		serverError.Render(w)
+		return
+	}
+	notifyIdentities(l, h.Notifier, acc, identityID(identity), fmt.Sprintf("Identity %s requested the recovery of account %s (request %s). If this was not expected, cancel the request.", identity.Role, acc.Address, rr.ID))
+
+	resp := newAccountRecoveryResponse(rr, now)
+	httpjson.Render(w, resp, httpjson.JSON)
+}
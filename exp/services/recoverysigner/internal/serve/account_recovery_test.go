@@ -0,0 +1,408 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notification struct {
+	To      account.AuthMethod
+	Message string
+}
+
+// recordingNotifier records the notifications sent so that tests can inspect
+// them and use the recovery codes sent.
+type recordingNotifier struct {
+	Notifications []notification
+}
+
+func (n *recordingNotifier) Notify(to account.AuthMethod, message string) error {
+	n.Notifications = append(n.Notifications, notification{To: to, Message: message})
+	return nil
+}
+
+// addApprovedRecoveryRequest stores a recovery request of the identity that is
+// approved, so that transactions are signed for the identity.
+func addApprovedRecoveryRequest(t *testing.T, s account.Store, address, identity string, m account.AuthMethod) {
+	now := time.Now()
+	r, _, err := account.NewRecoveryRequest(address, identity, m, now.Add(-time.Minute), 10*time.Minute)
+	require.NoError(t, err)
+	err = s.AddRecoveryRequest(r)
+	require.NoError(t, err)
+	approved := r
+	approved.Status = account.RecoveryRequestStatusConfirmed
+	approved.ConfirmedAt = now.Add(-time.Minute)
+	approved.ApprovedAt = now.Add(-time.Minute)
+	approved.ApprovalExpiresAt = now.Add(time.Hour)
+	err = s.UpdateRecoveryRequest(r, approved)
+	require.NoError(t, err)
+}
+
+var recoveryCodeRegexp = regexp.MustCompile(`is ([0-9]{6})\.$`)
+
+// code returns the recovery code in the last notification sent to the auth
+// method.
+func (n *recordingNotifier) code(t *testing.T, to account.AuthMethod) string {
+	for i := len(n.Notifications) - 1; i >= 0; i-- {
+		if n.Notifications[i].To != to {
+			continue
+		}
+		m := recoveryCodeRegexp.FindStringSubmatch(n.Notifications[i].Message)
+		if m != nil {
+			return m[1]
+		}
+	}
+	t.Fatalf("no recovery code sent to %v", to)
+	return ""
+}
+
+const recoveryTestAddress = "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"
+
+var (
+	recoveryTestOwner    = account.AuthMethod{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"}
+	recoveryTestReceiver = account.AuthMethod{Type: account.AuthMethodTypeEmail, Value: "user2@example.com"}
+)
+
+func recoveryTestMux(s account.Store, n *recordingNotifier) *chi.Mux {
+	m := chi.NewMux()
+	m.Post("/{address}/recovery", accountRecoveryPostHandler{
+		Logger:        supportlog.DefaultLogger,
+		AccountStore:  s,
+		Notifier:      n,
+		CodeExpiresIn: 10 * time.Minute,
+	}.ServeHTTP)
+	m.Get("/{address}/recovery/{id}", accountRecoveryGetHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+	}.ServeHTTP)
+	m.Post("/{address}/recovery/{id}/confirm", accountRecoveryConfirmHandler{
+		Logger:            supportlog.DefaultLogger,
+		AccountStore:      s,
+		Notifier:          n,
+		ApprovalExpiresIn: time.Hour,
+	}.ServeHTTP)
+	m.Post("/{address}/recovery/{id}/cancel", accountRecoveryCancelHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		Notifier:     n,
+	}.ServeHTTP)
+	m.Post("/{address}/sign/{signing-address}", accountSignHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		SigningKeys: signer.MustNewInMemory(
+			"SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+		),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}.ServeHTTP)
+	return m
+}
+
+// addRecoveryTestAccount stores the account of the recovery tests, with the
+// owner and receiver identities, and returns it as stored so that the IDs of
+// its identities are set.
+func addRecoveryTestAccount(t *testing.T, s account.Store, policy account.SigningPolicy) account.Account {
+	err := s.Add(account.Account{
+		Address: recoveryTestAddress,
+		Identities: []account.Identity{
+			{Role: "owner", AuthMethods: []account.AuthMethod{recoveryTestOwner}},
+			{Role: "receiver", AuthMethods: []account.AuthMethod{recoveryTestReceiver}},
+		},
+		SigningPolicy: policy,
+	})
+	require.NoError(t, err)
+	acc, err := s.Get(recoveryTestAddress)
+	require.NoError(t, err)
+	return acc
+}
+
+func doRecoveryTestRequest(t *testing.T, m http.Handler, a auth.Auth, method, path, body string) (int, string) {
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+	r := httptest.NewRequest(method, path, bodyReader)
+	r = r.WithContext(auth.NewContext(context.Background(), a))
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+	resp := w.Result()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func createRecoveryTestRequest(t *testing.T, m http.Handler) accountRecoveryResponse {
+	status, body := doRecoveryTestRequest(t, m, auth.Auth{PhoneNumber: recoveryTestOwner.Value}, "POST", "/"+recoveryTestAddress+"/recovery", "")
+	require.Equal(t, http.StatusOK, status, body)
+	resp := accountRecoveryResponse{}
+	err := json.Unmarshal([]byte(body), &resp)
+	require.NoError(t, err)
+	return resp
+}
+
+func recoveryTestTransaction(t *testing.T) string {
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: recoveryTestAddress},
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.SetOptions{
+					Signer: &txnbuild.Signer{
+						Address: "GD7CGJSJ5OBOU5KOP2UQDH3MPY75UTEY27HVV5XPSL2X6DJ2VGTOSXEU",
+						Weight:  20,
+					},
+				},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	txEnc, err := tx.Base64()
+	require.NoError(t, err)
+	return `{"transaction": "` + txEnc + `"}`
+}
+
+// Test that when the signing policy requires a recovery request transactions
+// are only signed for an identity once its recovery request is confirmed.
+func TestAccountRecovery_confirmed(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	acc := addRecoveryTestAccount(t, s, account.SigningPolicy{RequireRecoveryRequest: true})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	ownerAuth := auth.Auth{PhoneNumber: recoveryTestOwner.Value}
+	signPath := "/" + recoveryTestAddress + "/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"
+
+	// Signing is refused before the recovery is requested.
+	status, body := doRecoveryTestRequest(t, m, ownerAuth, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusForbidden, status)
+	assert.JSONEq(t, `{"error": "The transaction cannot be signed until a recovery request of the identity is approved."}`, body)
+
+	// The recovery is requested, the code sent to the owner and the other
+	// identities notified.
+	rr := createRecoveryTestRequest(t, m)
+	assert.Equal(t, recoveryTestAddress, rr.Address)
+	assert.Equal(t, identityID(acc.Identities[0]), rr.Identity)
+	assert.Equal(t, "pending_confirmation", rr.State)
+	require.Len(t, n.Notifications, 2)
+	assert.Equal(t, recoveryTestOwner, n.Notifications[0].To)
+	assert.Equal(t, recoveryTestReceiver, n.Notifications[1].To)
+	assert.Contains(t, n.Notifications[1].Message, "Identity owner requested the recovery of account "+recoveryTestAddress)
+	code := n.code(t, recoveryTestOwner)
+
+	// An incorrect code is not accepted.
+	status, body = doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "x"}`)
+	require.Equal(t, http.StatusUnauthorized, status)
+	assert.JSONEq(t, `{"error": "The request could not be authenticated."}`, body)
+
+	// The code cannot be used by another identity.
+	status, _ = doRecoveryTestRequest(t, m, auth.Auth{Email: recoveryTestReceiver.Value}, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusNotFound, status)
+
+	// The code is accepted and with no time lock the request is approved.
+	status, body = doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusOK, status, body)
+	resp := accountRecoveryResponse{}
+	err := json.Unmarshal([]byte(body), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "approved", resp.State)
+	require.NotNil(t, resp.ConfirmedAt)
+	require.NotNil(t, resp.ApprovedAt)
+	require.NotNil(t, resp.ApprovalExpiresAt)
+	assert.Equal(t, time.Hour, resp.ApprovalExpiresAt.Sub(*resp.ApprovedAt))
+	assert.Equal(t, recoveryTestReceiver, n.Notifications[len(n.Notifications)-1].To)
+
+	// The request cannot be confirmed twice.
+	status, _ = doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusConflict, status)
+
+	// Signing is permitted for the owner.
+	status, body = doRecoveryTestRequest(t, m, ownerAuth, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"signature"`)
+
+	// Signing is still refused for the receiver who has no recovery request.
+	status, _ = doRecoveryTestRequest(t, m, auth.Auth{Email: recoveryTestReceiver.Value}, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusForbidden, status)
+
+	// Signing is not restricted for the account itself.
+	status, _ = doRecoveryTestRequest(t, m, auth.Auth{Address: recoveryTestAddress}, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusOK, status)
+}
+
+// Test that when the signing policy does not require a recovery request, as
+// for accounts registered before recovery requests existed, transactions are
+// signed for identities without one.
+func TestAccountRecovery_notRequired(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	addRecoveryTestAccount(t, s, account.SigningPolicy{})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	signPath := "/" + recoveryTestAddress + "/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"
+
+	status, body := doRecoveryTestRequest(t, m, auth.Auth{PhoneNumber: recoveryTestOwner.Value}, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"signature"`)
+}
+
+// Test that a confirmed recovery request is not approved until the time lock
+// of the account's signing policy has passed.
+func TestAccountRecovery_timeLocked(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	addRecoveryTestAccount(t, s, account.SigningPolicy{RequireRecoveryRequest: true, RecoveryTimeLockSeconds: 3600})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	ownerAuth := auth.Auth{PhoneNumber: recoveryTestOwner.Value}
+
+	rr := createRecoveryTestRequest(t, m)
+	code := n.code(t, recoveryTestOwner)
+
+	status, body := doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusOK, status, body)
+	resp := accountRecoveryResponse{}
+	err := json.Unmarshal([]byte(body), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "time_locked", resp.State)
+	assert.Equal(t, time.Hour, resp.ApprovedAt.Sub(*resp.ConfirmedAt))
+
+	status, _ = doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", recoveryTestTransaction(t))
+	require.Equal(t, http.StatusForbidden, status)
+}
+
+// Test that another identity of the account can cancel a recovery request.
+func TestAccountRecovery_cancelledByOtherIdentity(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	acc := addRecoveryTestAccount(t, s, account.SigningPolicy{RequireRecoveryRequest: true, RecoveryTimeLockSeconds: 3600})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	ownerAuth := auth.Auth{PhoneNumber: recoveryTestOwner.Value}
+	receiverAuth := auth.Auth{Email: recoveryTestReceiver.Value}
+
+	rr := createRecoveryTestRequest(t, m)
+	code := n.code(t, recoveryTestOwner)
+
+	status, body := doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusOK, status, body)
+
+	status, body = doRecoveryTestRequest(t, m, receiverAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/cancel", "")
+	require.Equal(t, http.StatusOK, status, body)
+	resp := accountRecoveryResponse{}
+	err := json.Unmarshal([]byte(body), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", resp.State)
+	assert.Equal(t, identityID(acc.Identities[1]), resp.CancelledBy)
+	assert.Equal(t, recoveryTestOwner, n.Notifications[len(n.Notifications)-1].To)
+	assert.Contains(t, n.Notifications[len(n.Notifications)-1].Message, "was cancelled by identity receiver.")
+
+	status, body = doRecoveryTestRequest(t, m, ownerAuth, "GET", "/"+recoveryTestAddress+"/recovery/"+rr.ID, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"state": "cancelled"`)
+
+	status, _ = doRecoveryTestRequest(t, m, receiverAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/cancel", "")
+	require.Equal(t, http.StatusConflict, status)
+
+	stored, err := s.GetRecoveryRequest(recoveryTestAddress, rr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.RecoveryRequestStatusCancelled, stored.Status)
+}
+
+// Test that when identities share a role, an identity cannot confirm the
+// recovery request of another identity or sign using its approval.
+func TestAccountRecovery_sharedRole(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	err := s.Add(account.Account{
+		Address: recoveryTestAddress,
+		Identities: []account.Identity{
+			{Role: "owner", AuthMethods: []account.AuthMethod{recoveryTestOwner}},
+			{Role: "owner", AuthMethods: []account.AuthMethod{recoveryTestReceiver}},
+		},
+		SigningPolicy: account.SigningPolicy{RequireRecoveryRequest: true},
+	})
+	require.NoError(t, err)
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	ownerAuth := auth.Auth{PhoneNumber: recoveryTestOwner.Value}
+	otherAuth := auth.Auth{Email: recoveryTestReceiver.Value}
+	signPath := "/" + recoveryTestAddress + "/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"
+
+	// The other identity sharing the role is notified of the request.
+	rr := createRecoveryTestRequest(t, m)
+	require.Len(t, n.Notifications, 2)
+	assert.Equal(t, recoveryTestReceiver, n.Notifications[1].To)
+	code := n.code(t, recoveryTestOwner)
+
+	status, _ := doRecoveryTestRequest(t, m, otherAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, body := doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusOK, status, body)
+
+	status, _ = doRecoveryTestRequest(t, m, otherAuth, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRecoveryTestRequest(t, m, ownerAuth, "POST", signPath, recoveryTestTransaction(t))
+	require.Equal(t, http.StatusOK, status, body)
+}
+
+// Test that a recovery request is cancelled after too many incorrect codes.
+func TestAccountRecovery_tooManyAttempts(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	addRecoveryTestAccount(t, s, account.SigningPolicy{RequireRecoveryRequest: true})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+	ownerAuth := auth.Auth{PhoneNumber: recoveryTestOwner.Value}
+
+	rr := createRecoveryTestRequest(t, m)
+	code := n.code(t, recoveryTestOwner)
+
+	for i := 0; i < account.RecoveryRequestMaxAttempts; i++ {
+		status, _ := doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "x"}`)
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+
+	status, _ := doRecoveryTestRequest(t, m, ownerAuth, "POST", "/"+recoveryTestAddress+"/recovery/"+rr.ID+"/confirm", `{"code": "`+code+`"}`)
+	require.Equal(t, http.StatusConflict, status)
+
+	stored, err := s.GetRecoveryRequest(recoveryTestAddress, rr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.RecoveryRequestStatusCancelled, stored.Status)
+	assert.Equal(t, account.RecoveryRequestMaxAttempts, stored.Attempts)
+}
+
+// Test that only identities of the account can request a recovery.
+func TestAccountRecovery_notIdentity(t *testing.T) {
+	s := &account.DBStore{DB: dbtest.Open(t).Open()}
+	addRecoveryTestAccount(t, s, account.SigningPolicy{RequireRecoveryRequest: true})
+	n := &recordingNotifier{}
+	m := recoveryTestMux(s, n)
+
+	status, body := doRecoveryTestRequest(t, m, auth.Auth{PhoneNumber: "+99999999999"}, "POST", "/"+recoveryTestAddress+"/recovery", "")
+	require.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"error": "The resource at the url requested was not found."}`, body)
+
+	status, _ = doRecoveryTestRequest(t, m, auth.Auth{Address: recoveryTestAddress}, "POST", "/"+recoveryTestAddress+"/recovery", "")
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, _ = doRecoveryTestRequest(t, m, auth.Auth{}, "POST", "/"+recoveryTestAddress+"/recovery", "")
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	assert.Empty(t, n.Notifications)
+}
@@ -1,11 +1,16 @@
 package serve
 
-import "time"
+import (
+	"time"
+
+	"github.com/stellar/go/exp/support/signer"
+)
 
 type accountResponse struct {
-	Address    string                    `json:"address"`
-	Identities []accountResponseIdentity `json:"identities"`
-	Signers    []accountResponseSigner   `json:"signers"`
+	Address       string                    `json:"address"`
+	Identities    []accountResponseIdentity `json:"identities"`
+	Signers       []accountResponseSigner   `json:"signers"`
+	SigningPolicy *accountSigningPolicy     `json:"signing_policy,omitempty"`
 }
 
 type accountResponseIdentity struct {
@@ -17,3 +22,20 @@ type accountResponseSigner struct {
 	Key     string    `json:"key"`
 	AddedAt time.Time `json:"added_at"`
 }
+
+// newAccountResponseSigners returns the signers of the signing keys, the
+// preferred key first. Inactive keys are included so that clients keep them
+// as signers of their accounts until they are removed from the keyring.
+func newAccountResponseSigners(keys signer.KeySource) ([]accountResponseSigner, error) {
+	signingKeys, err := keys.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var signers []accountResponseSigner
+	for _, k := range signingKeys {
+		signers = append(signers, accountResponseSigner{
+			Key: k.Address,
+		})
+	}
+	return signers, nil
+}
@@ -1,10 +1,13 @@
 package serve
 
 import (
+	"encoding/base64"
 	"net/http"
+	"time"
 
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/http/httpdecode"
 	supportlog "github.com/stellar/go/support/log"
@@ -14,7 +17,7 @@ import (
 
 type accountSignHandler struct {
 	Logger            *supportlog.Entry
-	SigningKeys       []*keypair.Full
+	SigningKeys       signer.Keyring
 	NetworkPassphrase string
 	AccountStore      account.Store
 }
@@ -30,6 +33,14 @@ type accountSignResponse struct {
 	NetworkPassphrase string `json:"network_passphrase"`
 }
 
+// accountSignPendingResponse is returned when the transaction has been
+// approved by the identity but the quorum of identities required by the
+// signing policy of the account has not yet been reached.
+type accountSignPendingResponse struct {
+	Approvals int `json:"approvals"`
+	Quorum    int `json:"quorum"`
+}
+
 func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -56,17 +67,15 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	l.Info("Request to sign transaction.")
 
-	var signingKey *keypair.Full
-	for _, sk := range h.SigningKeys {
-		if req.SigningAddress.Address() == sk.Address() {
-			signingKey = sk
-			break
-		}
-	}
-	if signingKey == nil {
+	signingKey, err := h.SigningKeys.Signer(req.SigningAddress.Address())
+	if err == signer.ErrNotFound {
 		l.Info("Signing key not found.")
 		notFound.Render(w)
 		return
+	} else if err != nil {
+		l.Error("Error getting signing key:", err)
+		serverError.Render(w)
+		return
 	}
 
 	// Find the account that the request is for.
@@ -85,6 +94,15 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	authorized := claims.Address == req.Address.Address()
 	l.Infof("Authorized with self: %v.", authorized)
 
+	// The identity the signing decision is recorded for, which is the first
+	// identity authorized. Identities are recorded by their ID since several
+	// identities of an account can share a role.
+	identity := ""
+	identityRole := ""
+	if authorized {
+		identity = account.SigningIdentityAccount
+	}
+
 	// Authorized if authenticated as an identity registered with the account.
 	for _, i := range acc.Identities {
 		for _, m := range i.AuthMethods {
@@ -92,6 +110,10 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				(m.Type == account.AuthMethodTypePhoneNumber && m.Value == claims.PhoneNumber) ||
 				(m.Type == account.AuthMethodTypeEmail && m.Value == claims.Email)) {
 				authorized = true
+				if identity == "" {
+					identity = identityID(i)
+					identityRole = i.Role
+				}
 				l.Infof("Authorized with %s.", m.Type)
 				break
 			}
@@ -125,15 +147,25 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	l = l.WithField("transaction_hash", hashHex)
+	l = l.WithField("transaction_hash", hashHex).
+		WithField("identity", identity).
+		WithField("identity_role", identityRole)
 
 	l.Info("Signing transaction.")
 
+	// Every decision made about the transaction from here on is recorded.
+	decision := account.SigningDecision{
+		Address:         req.Address.Address(),
+		SigningAddress:  signingKey.Address(),
+		TransactionHash: hashHex,
+		Identity:        identity,
+	}
+
 	// Check that the transaction's source account and any operations it
 	// contains references only to this account.
 	if tx.SourceAccount().AccountID != req.Address.Address() {
 		l.Info("Transaction's source account is not the account in the request.")
-		badRequest.Render(w)
+		h.reject(w, l, decision, "transaction's source account is not the account", badRequest)
 		return
 	}
 	for _, op := range tx.Operations() {
@@ -143,7 +175,65 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		if op.GetSourceAccount().GetAccountID() != req.Address.Address() {
 			l.Info("Operation's source account is not the account.")
-			badRequest.Render(w)
+			h.reject(w, l, decision, "operation's source account is not the account", badRequest)
+			return
+		}
+	}
+
+	// Check that the transaction is permitted by the account's signing policy.
+	err = acc.SigningPolicy.Check(tx, time.Now())
+	if err != nil {
+		l.Infof("Transaction not permitted by signing policy: %v.", err)
+		h.reject(w, l, decision, err.Error(), forbidden)
+		return
+	}
+
+	// Check that the identity has an approved recovery request if the
+	// signing policy requires one.
+	if acc.SigningPolicy.RequireRecoveryRequest && identity != account.SigningIdentityAccount {
+		var rr account.RecoveryRequest
+		rr, err = h.AccountStore.FindApprovedRecoveryRequest(req.Address.Address(), identity, time.Now())
+		if err == account.ErrRecoveryRequestNotFound {
+			l.Info("Identity has no approved recovery request.")
+			h.reject(w, l, decision, "identity has no approved recovery request", recoveryRequired)
+			return
+		} else if err != nil {
+			l.Error("Error finding approved recovery request:", err)
+			serverError.Render(w)
+			return
+		}
+		l.Infof("Approved recovery request %s.", rr.ID)
+	}
+
+	// Check that enough identities have requested the transaction be signed.
+	if acc.SigningPolicy.Quorum > 1 {
+		decision.Decision = account.SigningDecisionApproved
+		err = h.AccountStore.RecordSigningDecision(decision)
+		if err != nil {
+			l.Error("Error recording signing decision:", err)
+			serverError.Render(w)
+			return
+		}
+		// Only the approvals of the current identities of the account count
+		// towards the quorum.
+		identities := []string{account.SigningIdentityAccount}
+		for _, i := range acc.Identities {
+			identities = append(identities, identityID(i))
+		}
+		var approvals int
+		approvals, err = h.AccountStore.CountSigningApprovals(req.Address.Address(), hashHex, identities)
+		if err != nil {
+			l.Error("Error counting signing approvals:", err)
+			serverError.Render(w)
+			return
+		}
+		l.Infof("Transaction approved by %d of %d identities.", approvals, acc.SigningPolicy.Quorum)
+		if approvals < acc.SigningPolicy.Quorum {
+			resp := accountSignPendingResponse{
+				Approvals: approvals,
+				Quorum:    acc.SigningPolicy.Quorum,
+			}
+			httpjson.RenderStatus(w, http.StatusAccepted, resp, httpjson.JSON)
 			return
 		}
 	}
@@ -155,12 +245,22 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		serverError.Render(w)
 		return
 	}
-	sig, err := signingKey.SignBase64(hash[:])
+	sigBytes, err := signingKey.Sign(hash[:])
 	if err != nil {
 		l.Error("Error signing transaction:", err)
 		serverError.Render(w)
 		return
 	}
+	sig := base64.StdEncoding.EncodeToString(sigBytes)
+
+	// The signature is only returned once the decision has been recorded.
+	decision.Decision = account.SigningDecisionSigned
+	err = h.AccountStore.RecordSigningDecision(decision)
+	if err != nil {
+		l.Error("Error recording signing decision:", err)
+		serverError.Render(w)
+		return
+	}
 
 	l.Info("Transaction signed.")
 
@@ -170,3 +270,17 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	httpjson.Render(w, resp, httpjson.JSON)
 }
+
+// reject records that the transaction was rejected for the reason given and
+// renders the error response.
+func (h accountSignHandler) reject(w http.ResponseWriter, l *supportlog.Entry, d account.SigningDecision, reason string, resp errorResponse) {
+	d.Decision = account.SigningDecisionRejected
+	d.Reason = reason
+	err := h.AccountStore.RecordSigningDecision(d)
+	if err != nil {
+		l.Error("Error recording signing decision:", err)
+		serverError.Render(w)
+		return
+	}
+	resp.Render(w)
+}
@@ -0,0 +1,254 @@
+package serve
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/support/signer"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signingDecisionRow struct {
+	Address         string `db:"address"`
+	SigningAddress  string `db:"signing_address"`
+	TransactionHash string `db:"transaction_hash"`
+	Identity        string `db:"identity"`
+	Decision        string `db:"decision"`
+	Reason          string `db:"reason"`
+}
+
+// Test that when the transaction is not permitted by the signing policy of the
+// account it is not signed and the rejection is recorded.
+func TestAccountSign_signingPolicyRejected(t *testing.T) {
+	db := dbtest.Open(t).Open()
+	s := &account.DBStore{DB: db}
+	s.Add(account.Account{
+		Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+		Identities: []account.Identity{
+			{
+				Role: "sender",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"},
+				},
+			},
+		},
+		SigningPolicy: account.SigningPolicy{
+			DenyPayments: true,
+		},
+	})
+	h := accountSignHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		SigningKeys: signer.MustNewInMemory(
+			"SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+		),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}
+	acc, err := s.Get("GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4")
+	require.NoError(t, err)
+	identity := strconv.FormatInt(acc.Identities[0].ID, 10)
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"},
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.Payment{
+					Destination: "GD7CGJSJ5OBOU5KOP2UQDH3MPY75UTEY27HVV5XPSL2X6DJ2VGTOSXEU",
+					Amount:      "100",
+					Asset:       txnbuild.NativeAsset{},
+				},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	txEnc, err := tx.Base64()
+	require.NoError(t, err)
+	txHash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ctx = auth.NewContext(ctx, auth.Auth{PhoneNumber: "+10000000000"})
+	req := `{
+	"transaction": "` + txEnc + `"
+}`
+	r := httptest.NewRequest("POST", "/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", strings.NewReader(req))
+	r = r.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	m := chi.NewMux()
+	m.Post("/{address}/sign/{signing-address}", h.ServeHTTP)
+	m.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `{
+	"error": "The transaction is not permitted by the signing policy of the account."
+}`
+	assert.JSONEq(t, wantBody, string(body))
+
+	rows := []signingDecisionRow{}
+	err = db.Select(&rows, `SELECT address, signing_address, transaction_hash, identity, decision, reason FROM signing_decisions ORDER BY id`)
+	require.NoError(t, err)
+	wantRows := []signingDecisionRow{
+		{
+			Address:         "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+			SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+			TransactionHash: txHash,
+			Identity:        identity,
+			Decision:        "rejected",
+			Reason:          "operation 0 is a payment but payments are not permitted",
+		},
+	}
+	assert.Equal(t, wantRows, rows)
+}
+
+// Test that when the signing policy of the account requires a quorum of
+// identities the transaction is only signed once enough distinct identities
+// have requested it be signed, and that every decision is recorded.
+func TestAccountSign_signingPolicyQuorum(t *testing.T) {
+	db := dbtest.Open(t).Open()
+	s := &account.DBStore{DB: db}
+	s.Add(account.Account{
+		Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+		Identities: []account.Identity{
+			{
+				Role: "owner",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"},
+				},
+			},
+			{
+				Role: "owner",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypeEmail, Value: "user1@example.com"},
+				},
+			},
+		},
+		SigningPolicy: account.SigningPolicy{
+			OnlySignerChanges:      true,
+			Quorum:                 2,
+			RequireRecoveryRequest: true,
+		},
+	})
+	signingKey := keypair.MustParseFull("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK") // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+	h := accountSignHandler{
+		Logger:            supportlog.DefaultLogger,
+		AccountStore:      s,
+		SigningKeys:       signer.MustNewInMemory(signingKey.Seed()),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}
+	acc, err := s.Get("GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4")
+	require.NoError(t, err)
+	firstIdentity := strconv.FormatInt(acc.Identities[0].ID, 10)
+	secondIdentity := strconv.FormatInt(acc.Identities[1].ID, 10)
+	addApprovedRecoveryRequest(t, s, acc.Address, firstIdentity, acc.Identities[0].AuthMethods[0])
+	addApprovedRecoveryRequest(t, s, acc.Address, secondIdentity, acc.Identities[1].AuthMethods[0])
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"},
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.SetOptions{
+					Signer: &txnbuild.Signer{
+						Address: "GD7CGJSJ5OBOU5KOP2UQDH3MPY75UTEY27HVV5XPSL2X6DJ2VGTOSXEU",
+						Weight:  20,
+					},
+				},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	txEnc, err := tx.Base64()
+	require.NoError(t, err)
+	txHash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	txHashBytes, err := tx.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	wantSig, err := signingKey.SignBase64(txHashBytes[:])
+	require.NoError(t, err)
+
+	sign := func(a auth.Auth) (int, string) {
+		ctx := context.Background()
+		ctx = auth.NewContext(ctx, a)
+		req := `{
+	"transaction": "` + txEnc + `"
+}`
+		r := httptest.NewRequest("POST", "/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", strings.NewReader(req))
+		r = r.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		m := chi.NewMux()
+		m.Post("/{address}/sign/{signing-address}", h.ServeHTTP)
+		m.ServeHTTP(w, r)
+		resp := w.Result()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	// The first identity approves the transaction.
+	status, body := sign(auth.Auth{PhoneNumber: "+10000000000"})
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.JSONEq(t, `{"approvals": 1, "quorum": 2}`, body)
+
+	// The same identity approving again does not reach the quorum.
+	status, body = sign(auth.Auth{PhoneNumber: "+10000000000"})
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.JSONEq(t, `{"approvals": 1, "quorum": 2}`, body)
+
+	// The second identity approves the transaction and it is signed, even
+	// though it has the same role as the first identity.
+	status, body = sign(auth.Auth{Email: "user1@example.com"})
+	assert.Equal(t, http.StatusOK, status)
+	wantBody := `{
+	"signature": "` + wantSig + `",
+	"network_passphrase": "Test SDF Network ; September 2015"
+}`
+	assert.JSONEq(t, wantBody, body)
+
+	rows := []signingDecisionRow{}
+	err = db.Select(&rows, `SELECT address, signing_address, transaction_hash, identity, decision, reason FROM signing_decisions ORDER BY id`)
+	require.NoError(t, err)
+	wantRow := func(identity, decision string) signingDecisionRow {
+		return signingDecisionRow{
+			Address:         "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+			SigningAddress:  "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H",
+			TransactionHash: txHash,
+			Identity:        identity,
+			Decision:        decision,
+		}
+	}
+	wantRows := []signingDecisionRow{
+		wantRow(firstIdentity, "approved"),
+		wantRow(firstIdentity, "approved"),
+		wantRow(secondIdentity, "approved"),
+		wantRow(secondIdentity, "signed"),
+	}
+	assert.Equal(t, wantRows, rows)
+}
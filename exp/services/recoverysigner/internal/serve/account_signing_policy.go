@@ -0,0 +1,56 @@
+package serve
+
+import (
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+)
+
+// accountSigningPolicy is the signing policy of an account as it is set in
+// requests and returned in responses.
+type accountSigningPolicy struct {
+	OnlySignerChanges  bool  `json:"only_signer_changes"`
+	DenyPayments       bool  `json:"deny_payments"`
+	MaxOperations      int   `json:"max_operations"`
+	RequireTimeBounds  bool  `json:"require_time_bounds"`
+	MaxValiditySeconds int64 `json:"max_validity_seconds"`
+	Quorum             int   `json:"quorum"`
+
+	RequireRecoveryRequest  bool  `json:"require_recovery_request"`
+	RecoveryTimeLockSeconds int64 `json:"recovery_time_lock_seconds"`
+}
+
+func (p accountSigningPolicy) Validate() error {
+	return p.SigningPolicy().Validate()
+}
+
+func (p accountSigningPolicy) SigningPolicy() account.SigningPolicy {
+	return account.SigningPolicy{
+		OnlySignerChanges:  p.OnlySignerChanges,
+		DenyPayments:       p.DenyPayments,
+		MaxOperations:      p.MaxOperations,
+		RequireTimeBounds:  p.RequireTimeBounds,
+		MaxValiditySeconds: p.MaxValiditySeconds,
+		Quorum:             p.Quorum,
+
+		RequireRecoveryRequest:  p.RequireRecoveryRequest,
+		RecoveryTimeLockSeconds: p.RecoveryTimeLockSeconds,
+	}
+}
+
+// newAccountSigningPolicy returns the signing policy for a response, or nil
+// if the policy places no restrictions so that it is omitted.
+func newAccountSigningPolicy(p account.SigningPolicy) *accountSigningPolicy {
+	if p == (account.SigningPolicy{}) {
+		return nil
+	}
+	return &accountSigningPolicy{
+		OnlySignerChanges:  p.OnlySignerChanges,
+		DenyPayments:       p.DenyPayments,
+		MaxOperations:      p.MaxOperations,
+		RequireTimeBounds:  p.RequireTimeBounds,
+		MaxValiditySeconds: p.MaxValiditySeconds,
+		Quorum:             p.Quorum,
+
+		RequireRecoveryRequest:  p.RequireRecoveryRequest,
+		RecoveryTimeLockSeconds: p.RecoveryTimeLockSeconds,
+	}
+}
@@ -26,6 +26,18 @@ var conflict = errorResponse{
 	Status: http.StatusConflict,
 	Error:  "The request could not be completed because the resource already exists.",
 }
+var forbidden = errorResponse{
+	Status: http.StatusForbidden,
+	Error:  "The transaction is not permitted by the signing policy of the account.",
+}
+var recoveryRequired = errorResponse{
+	Status: http.StatusForbidden,
+	Error:  "The transaction cannot be signed until a recovery request of the identity is approved.",
+}
+var recoveryRequestNotPending = errorResponse{
+	Status: http.StatusConflict,
+	Error:  "The recovery request is not in a state that permits the request.",
+}
 var unauthorized = errorResponse{
 	Status: http.StatusUnauthorized,
 	Error:  "The request could not be authenticated.",
@@ -4,15 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	firebaseauth "firebase.google.com/go/auth"
 	"github.com/go-chi/chi"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/notify"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
@@ -21,15 +22,22 @@ import (
 )
 
 type Options struct {
-	Logger               *supportlog.Entry
-	DatabaseURL          string
-	DatabaseMaxOpenConns int
-	Port                 int
-	NetworkPassphrase    string
-	SigningKeys          string
-	SEP10JWKS            string
-	SEP10JWTIssuer       string
-	FirebaseProjectID    string
+	Logger                   *supportlog.Entry
+	DatabaseURL              string
+	DatabaseMaxOpenConns     int
+	Port                     int
+	NetworkPassphrase        string
+	SigningKeys              string
+	SigningKeyFile           string
+	SigningKeyFilePassphrase string
+	SigningKeyURL            string
+	SEP10JWKS                string
+	SEP10JWTIssuer           string
+	FirebaseProjectID        string
+
+	RecoveryCodeExpiresIn     time.Duration
+	RecoveryApprovalExpiresIn time.Duration
+	NotifierWebhookURL        string
 
 	AdminPort        int
 	MetricsNamespace string
@@ -46,6 +54,7 @@ func Serve(opts Options) {
 		adminDeps := adminDeps{
 			Logger:          opts.Logger,
 			MetricsGatherer: deps.MetricsRegistry,
+			SigningKeys:     deps.SigningKeys,
 		}
 		go serveAdmin(opts, adminDeps)
 	}
@@ -65,33 +74,42 @@ func Serve(opts Options) {
 type handlerDeps struct {
 	Logger             *supportlog.Entry
 	NetworkPassphrase  string
-	SigningKeys        []*keypair.Full
-	SigningAddresses   []*keypair.FromAddress
+	SigningKeys        signer.Keyring
 	AccountStore       account.Store
 	SEP10JWKS          jose.JSONWebKeySet
 	SEP10JWTIssuer     string
 	FirebaseAuthClient *firebaseauth.Client
 	MetricsRegistry    *prometheus.Registry
+
+	Notifier                  notify.Notifier
+	RecoveryCodeExpiresIn     time.Duration
+	RecoveryApprovalExpiresIn time.Duration
 }
 
 func getHandlerDeps(opts Options) (handlerDeps, error) {
 	// TODO: Replace this signing key with randomly generating a unique signing
 	// key for each account so that it is not possible to identify which
 	// accounts are recoverable via a recovery signer.
-	signingKeys := []*keypair.Full{}
-	signingAddresses := []*keypair.FromAddress{}
-	for i, signingKeyStr := range strings.Split(opts.SigningKeys, ",") {
-		signingKey, err := keypair.ParseFull(signingKeyStr)
-		if err != nil {
-			return handlerDeps{}, errors.Wrap(err, "parsing signing key seed")
-		}
-		signingKeys = append(signingKeys, signingKey)
-		signingAddresses = append(signingAddresses, signingKey.FromAddress())
-		opts.Logger.Info("Signing key ", i, ": ", signingKey.Address())
+	signingKeys, err := signer.Open(signer.Options{
+		Logger:            opts.Logger,
+		Keys:              opts.SigningKeys,
+		KeyFile:           opts.SigningKeyFile,
+		KeyFilePassphrase: opts.SigningKeyFilePassphrase,
+		URL:               opts.SigningKeyURL,
+	})
+	if err != nil {
+		return handlerDeps{}, errors.Wrap(err, "opening signing keys")
+	}
+	keys, err := signingKeys.Keys()
+	if err != nil {
+		return handlerDeps{}, errors.Wrap(err, "getting signing keys")
+	}
+	for i, k := range keys {
+		opts.Logger.Info("Signing key ", i, ": ", k.Address, " (active: ", k.Active, ")")
 	}
 
 	sep10JWKS := jose.JSONWebKeySet{}
-	err := json.Unmarshal([]byte(opts.SEP10JWKS), &sep10JWKS)
+	err = json.Unmarshal([]byte(opts.SEP10JWKS), &sep10JWKS)
 	if err != nil {
 		return handlerDeps{}, errors.Wrap(err, "parsing SEP-10 JSON Web Key (JWK) Set")
 	}
@@ -141,16 +159,30 @@ func getHandlerDeps(opts Options) (handlerDeps, error) {
 		opts.Logger.Warn("Error registering metric for accounts count: ", err)
 	}
 
+	var notifier notify.Notifier
+	if opts.NotifierWebhookURL != "" {
+		notifier = notify.WebhookNotifier{
+			URL:    opts.NotifierWebhookURL,
+			Client: &http.Client{Timeout: 10 * time.Second},
+		}
+	} else {
+		opts.Logger.Warn("No notifier webhook URL is configured, recovery codes and notifications will not be delivered.")
+		notifier = notify.LogNotifier{Logger: opts.Logger}
+	}
+
 	deps := handlerDeps{
 		Logger:             opts.Logger,
 		NetworkPassphrase:  opts.NetworkPassphrase,
 		SigningKeys:        signingKeys,
-		SigningAddresses:   signingAddresses,
 		AccountStore:       accountStore,
 		SEP10JWKS:          sep10JWKS,
 		SEP10JWTIssuer:     opts.SEP10JWTIssuer,
 		FirebaseAuthClient: firebaseAuthClient,
 		MetricsRegistry:    metricsRegistry,
+
+		Notifier:                  notifier,
+		RecoveryCodeExpiresIn:     opts.RecoveryCodeExpiresIn,
+		RecoveryApprovalExpiresIn: opts.RecoveryApprovalExpiresIn,
 	}
 
 	return deps, nil
@@ -167,30 +199,30 @@ func handler(deps handlerDeps) http.Handler {
 		mux.Use(auth.SEP10Middleware(deps.SEP10JWTIssuer, deps.SEP10JWKS))
 		mux.Use(auth.FirebaseMiddleware(auth.FirebaseTokenVerifierLive{AuthClient: deps.FirebaseAuthClient}))
 		mux.Get("/", accountListHandler{
-			Logger:           deps.Logger,
-			SigningAddresses: deps.SigningAddresses,
-			AccountStore:     deps.AccountStore,
+			Logger:       deps.Logger,
+			SigningKeys:  deps.SigningKeys,
+			AccountStore: deps.AccountStore,
 		}.ServeHTTP)
 		mux.Route("/{address}", func(mux chi.Router) {
 			mux.Post("/", accountPostHandler{
-				Logger:           deps.Logger,
-				SigningAddresses: deps.SigningAddresses,
-				AccountStore:     deps.AccountStore,
+				Logger:       deps.Logger,
+				SigningKeys:  deps.SigningKeys,
+				AccountStore: deps.AccountStore,
 			}.ServeHTTP)
 			mux.Put("/", accountPutHandler{
-				Logger:           deps.Logger,
-				SigningAddresses: deps.SigningAddresses,
-				AccountStore:     deps.AccountStore,
+				Logger:       deps.Logger,
+				SigningKeys:  deps.SigningKeys,
+				AccountStore: deps.AccountStore,
 			}.ServeHTTP)
 			mux.Get("/", accountGetHandler{
-				Logger:           deps.Logger,
-				SigningAddresses: deps.SigningAddresses,
-				AccountStore:     deps.AccountStore,
+				Logger:       deps.Logger,
+				SigningKeys:  deps.SigningKeys,
+				AccountStore: deps.AccountStore,
 			}.ServeHTTP)
 			mux.Delete("/", accountDeleteHandler{
-				Logger:           deps.Logger,
-				SigningAddresses: deps.SigningAddresses,
-				AccountStore:     deps.AccountStore,
+				Logger:       deps.Logger,
+				SigningKeys:  deps.SigningKeys,
+				AccountStore: deps.AccountStore,
 			}.ServeHTTP)
 			signHandler := accountSignHandler{
 				Logger:            deps.Logger,
@@ -200,6 +232,29 @@ func handler(deps handlerDeps) http.Handler {
 			}
 			mux.Post("/sign", signHandler.ServeHTTP)
 			mux.Post("/sign/{signing-address}", signHandler.ServeHTTP)
+			mux.Route("/recovery", func(mux chi.Router) {
+				mux.Post("/", accountRecoveryPostHandler{
+					Logger:        deps.Logger,
+					AccountStore:  deps.AccountStore,
+					Notifier:      deps.Notifier,
+					CodeExpiresIn: deps.RecoveryCodeExpiresIn,
+				}.ServeHTTP)
+				mux.Get("/{id}", accountRecoveryGetHandler{
+					Logger:       deps.Logger,
+					AccountStore: deps.AccountStore,
+				}.ServeHTTP)
+				mux.Post("/{id}/confirm", accountRecoveryConfirmHandler{
+					Logger:            deps.Logger,
+					AccountStore:      deps.AccountStore,
+					Notifier:          deps.Notifier,
+					ApprovalExpiresIn: deps.RecoveryApprovalExpiresIn,
+				}.ServeHTTP)
+				mux.Post("/{id}/cancel", accountRecoveryCancelHandler{
+					Logger:       deps.Logger,
+					AccountStore: deps.AccountStore,
+					Notifier:     deps.Notifier,
+				}.ServeHTTP)
+			})
 		})
 	})
 
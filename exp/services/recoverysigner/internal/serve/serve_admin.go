@@ -6,6 +6,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stellar/go/exp/support/signer"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
 )
@@ -26,10 +27,12 @@ func serveAdmin(opts Options, deps adminDeps) {
 type adminDeps struct {
 	Logger          *supportlog.Entry
 	MetricsGatherer prometheus.Gatherer
+	SigningKeys     signer.KeySource
 }
 
 func adminHandler(deps adminDeps) http.Handler {
 	mux := supporthttp.NewMux(deps.Logger)
 	mux.Handle("/metrics", promhttp.HandlerFor(deps.MetricsGatherer, promhttp.HandlerOpts{}))
+	mux.Handle("/keys", signer.KeysHandler{Logger: deps.Logger, Keys: deps.SigningKeys})
 	return mux
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -119,3 +120,33 @@ func TestAdminHandler_metricsAccountsCountSomeDeleted(t *testing.T) {
 	wantBody := `accounts_count 1`
 	assert.Contains(t, string(body), wantBody)
 }
+
+func TestAdminHandler_keys(t *testing.T) {
+	deps := adminDeps{
+		Logger:          supportlog.DefaultLogger,
+		MetricsGatherer: prometheus.NewRegistry(),
+		SigningKeys: signer.MustNewInMemory(
+			"SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+			"SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD", // GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS
+		),
+	}
+	h := adminHandler(deps)
+
+	r := httptest.NewRequest("GET", "/keys", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `{
+	"keys": [
+		{"address": "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", "active": true},
+		{"address": "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS", "active": false}
+	]
+}`
+	assert.JSONEq(t, wantBody, string(body))
+}
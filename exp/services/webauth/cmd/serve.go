@@ -46,10 +46,31 @@ func (c *ServeCommand) Command() *cobra.Command {
 		},
 		{
 			Name:      "signing-key",
-			Usage:     "Stellar signing key(s) used for signing transactions comma separated (first key is used for signing, others used for verifying challenges)",
+			Usage:     "Stellar signing key(s) used for signing transactions comma separated (first key is used for signing, others used for verifying challenges) (one of signing-key, signing-key-file or signing-key-url is required)",
 			OptType:   types.String,
 			ConfigKey: &opts.SigningKeys,
-			Required:  true,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-file",
+			Usage:     "Path of an encrypted file containing the Stellar signing keys (the active key is used for signing, others used for verifying challenges), managed with the keys command and reloaded when changed",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyFile,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-file-passphrase",
+			Usage:     "Passphrase the keys of the signing key file are encrypted with",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyFilePassphrase,
+			Required:  false,
+		},
+		{
+			Name:      "signing-key-url",
+			Usage:     "URL of a remote signing service holding the Stellar signing keys (the active key is used for signing, others used for verifying challenges)",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningKeyURL,
+			Required:  false,
 		},
 		{
 			Name:      "auth-home-domain",
@@ -90,6 +111,22 @@ func (c *ServeCommand) Command() *cobra.Command {
 			FlagDefault:    300,
 			Required:       true,
 		},
+		{
+			Name:      "auth-home-domain-jwts",
+			Usage:     "JSON object of the JWT issuer and JSON Web Key (JWK) used for signing JWTs of home domains that do not use the jwt-issuer and jwk, e.g. {\"example.com\":{\"jwt_issuer\":\"https://example.com/auth\",\"jwk\":{...}}} (home domains must also be auth home domains)",
+			OptType:   types.String,
+			ConfigKey: &opts.AuthHomeDomainJWTs,
+			Required:  false,
+		},
+		{
+			Name:           "client-domain-cache-duration",
+			Usage:          "The time period in seconds for which the SIGNING_KEY of a client domain's stellar.toml is cached",
+			OptType:        types.Int,
+			CustomSetValue: config.SetDuration,
+			ConfigKey:      &opts.ClientDomainCacheDuration,
+			FlagDefault:    300,
+			Required:       true,
+		},
 		{
 			Name:        "allow-accounts-that-do-not-exist",
 			Usage:       "Allow accounts that do not exist",
@@ -97,6 +134,14 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey:   &opts.AllowAccountsThatDoNotExist,
 			FlagDefault: false,
 		},
+		{
+			Name:        "admin-port",
+			Usage:       "Port to listen and serve admin functionality including signing keys",
+			OptType:     types.Int,
+			ConfigKey:   &opts.AdminPort,
+			FlagDefault: 0,
+			Required:    false,
+		},
 	}
 	cmd := &cobra.Command{
 		Use:   "serve",
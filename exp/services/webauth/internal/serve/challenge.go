@@ -1,11 +1,12 @@
 package serve
 
 import (
+	"encoding/base64"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/strkey"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
@@ -17,9 +18,10 @@ import (
 type challengeHandler struct {
 	Logger             *supportlog.Entry
 	NetworkPassphrase  string
-	SigningKey         *keypair.Full
+	SigningKeys        signer.Keyring
 	ChallengeExpiresIn time.Duration
 	HomeDomains        []string
+	ClientDomains      *clientDomainSigningKeys
 }
 
 type challengeResponse struct {
@@ -56,11 +58,35 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		homeDomain = h.HomeDomains[0]
 	}
 
-	tx, err := txnbuild.BuildChallengeTx(
-		h.SigningKey.Seed(),
+	// The client domain is optional, and if present the challenge is built to
+	// be signed by the SIGNING_KEY of the client domain's stellar.toml.
+	clientDomain := queryValues.Get("client_domain")
+	clientSigningKey := ""
+	if clientDomain != "" {
+		var err error
+		clientSigningKey, err = h.ClientDomains.SigningKey(clientDomain)
+		if err != nil {
+			h.Logger.Ctx(ctx).
+				WithField("clientdomain", clientDomain).
+				Info("Failed to get client domain signing key: ", err)
+			badRequest.Render(w)
+			return
+		}
+	}
+
+	signingKey, err := signer.ActiveSigner(h.SigningKeys)
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	tx, err := txnbuild.BuildUnsignedChallengeTx(
+		signingKey.Address(),
 		account,
 		homeDomain,
-		h.NetworkPassphrase,
+		clientDomain,
+		clientSigningKey,
 		h.ChallengeExpiresIn,
 	)
 	if err != nil {
@@ -69,6 +95,25 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	txHash, err := tx.Hash(h.NetworkPassphrase)
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+	sig, err := signingKey.Sign(txHash[:])
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+	tx, err = tx.AddSignatureBase64(h.NetworkPassphrase, signingKey.Address(), base64.StdEncoding.EncodeToString(sig))
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
 	hash, err := tx.HashHex(h.NetworkPassphrase)
 	if err != nil {
 		h.Logger.Ctx(ctx).WithStack(err).Error(err)
@@ -79,8 +124,9 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l := h.Logger.Ctx(ctx).
 		WithField("tx", hash).
 		WithField("account", account).
-		WithField("serversigner", h.SigningKey.Address()).
-		WithField("homedomain", homeDomain)
+		WithField("serversigner", signingKey.Address()).
+		WithField("homedomain", homeDomain).
+		WithField("clientdomain", clientDomain)
 
 	l.Info("Generated challenge transaction for account.")
 
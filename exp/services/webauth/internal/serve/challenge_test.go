@@ -9,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	supportlog "github.com/stellar/go/support/log"
@@ -24,7 +26,7 @@ func TestChallenge(t *testing.T) {
 	h := challengeHandler{
 		Logger:             supportlog.DefaultLogger,
 		NetworkPassphrase:  network.TestNetworkPassphrase,
-		SigningKey:         serverKey,
+		SigningKeys:        signer.MustNewInMemory(serverKey.Seed()),
 		ChallengeExpiresIn: time.Minute,
 		HomeDomains:        []string{"testdomain"},
 	}
@@ -66,6 +68,53 @@ func TestChallenge(t *testing.T) {
 	assert.Equal(t, network.TestNetworkPassphrase, res.NetworkPassphrase)
 }
 
+// Test that the challenge is signed by the active key of a keyring whose keys
+// are held by a remote signing service.
+func TestChallenge_remoteSigningKeys(t *testing.T) {
+	oldServerKey := keypair.MustRandom()
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+
+	signingService := httptest.NewServer(signer.RemoteHandler(supportlog.DefaultLogger, signer.MustNewInMemory(serverKey.Seed(), oldServerKey.Seed())))
+	defer signingService.Close()
+	signingKeys, err := signer.NewRemoteKeyring(signingService.URL, signingService.Client(), supportlog.DefaultLogger)
+	require.NoError(t, err)
+
+	h := challengeHandler{
+		Logger:             supportlog.DefaultLogger,
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SigningKeys:        signingKeys,
+		ChallengeExpiresIn: time.Minute,
+		HomeDomains:        []string{"testdomain"},
+	}
+
+	r := httptest.NewRequest("GET", "/?account="+account.Address(), nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := struct {
+		Transaction       string `json:"transaction"`
+		NetworkPassphrase string `json:"network_passphrase"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+
+	var tx xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(res.Transaction, &tx)
+	require.NoError(t, err)
+
+	assert.Len(t, tx.Signatures(), 1)
+	sourceAccount := tx.SourceAccount().ToAccountId()
+	assert.Equal(t, serverKey.Address(), sourceAccount.Address())
+
+	hash, err := network.HashTransactionInEnvelope(tx, res.NetworkPassphrase)
+	require.NoError(t, err)
+	assert.NoError(t, serverKey.FromAddress().Verify(hash[:], tx.Signatures()[0].Signature))
+}
+
 func TestChallenge_anotherHomeDomain(t *testing.T) {
 	serverKey := keypair.MustRandom()
 	account := keypair.MustRandom()
@@ -74,7 +123,7 @@ func TestChallenge_anotherHomeDomain(t *testing.T) {
 	h := challengeHandler{
 		Logger:             supportlog.DefaultLogger,
 		NetworkPassphrase:  network.TestNetworkPassphrase,
-		SigningKey:         serverKey,
+		SigningKeys:        signer.MustNewInMemory(serverKey.Seed()),
 		ChallengeExpiresIn: time.Minute,
 		HomeDomains:        []string{"testdomain", anotherDomain},
 	}
@@ -118,7 +167,7 @@ func TestChallenge_anotherHomeDomain(t *testing.T) {
 
 func TestChallenge_noAccount(t *testing.T) {
 	h := challengeHandler{
-		SigningKey: keypair.MustRandom(),
+		SigningKeys: signer.MustNewInMemory(keypair.MustRandom().Seed()),
 	}
 
 	r := httptest.NewRequest("GET", "/", nil)
@@ -136,7 +185,7 @@ func TestChallenge_noAccount(t *testing.T) {
 
 func TestChallenge_invalidAccount(t *testing.T) {
 	h := challengeHandler{
-		SigningKey: keypair.MustRandom(),
+		SigningKeys: signer.MustNewInMemory(keypair.MustRandom().Seed()),
 	}
 
 	r := httptest.NewRequest("GET", "/?account=GREATACCOUNT", nil)
@@ -157,7 +206,7 @@ func TestChallenge_invalidHomeDomain(t *testing.T) {
 	anotherDomain := "anotherdomain"
 
 	h := challengeHandler{
-		SigningKey:  keypair.MustRandom(),
+		SigningKeys: signer.MustNewInMemory(keypair.MustRandom().Seed()),
 		HomeDomains: []string{"testdomain"},
 	}
 
@@ -173,3 +222,81 @@ func TestChallenge_invalidHomeDomain(t *testing.T) {
 	require.NoError(t, err)
 	assert.JSONEq(t, `{"error":"The request was invalid in some way."}`, string(body))
 }
+
+func TestChallenge_clientDomain(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+	clientDomainKey := keypair.MustRandom()
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: clientDomainKey.Address()}, nil)
+
+	h := challengeHandler{
+		Logger:             supportlog.DefaultLogger,
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SigningKeys:        signer.MustNewInMemory(serverKey.Seed()),
+		ChallengeExpiresIn: time.Minute,
+		HomeDomains:        []string{"testdomain"},
+		ClientDomains: &clientDomainSigningKeys{
+			StellarTOML:   stellarTOML,
+			CacheDuration: time.Minute,
+		},
+	}
+
+	r := httptest.NewRequest("GET", "/?account="+account.Address()+"&client_domain=wallet.example.com", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := struct {
+		Transaction       string `json:"transaction"`
+		NetworkPassphrase string `json:"network_passphrase"`
+	}{}
+	err := json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+
+	var tx xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(res.Transaction, &tx)
+	require.NoError(t, err)
+
+	assert.Len(t, tx.Signatures(), 1)
+	require.Len(t, tx.Operations(), 2)
+	op := tx.Operations()[1]
+	opSourceAccount := op.SourceAccount.ToAccountId()
+	assert.Equal(t, clientDomainKey.Address(), opSourceAccount.Address())
+	assert.Equal(t, xdr.OperationTypeManageData, op.Body.Type)
+	assert.Equal(t, xdr.String64("client_domain"), op.Body.ManageDataOp.DataName)
+	assert.Equal(t, xdr.DataValue("wallet.example.com"), *op.Body.ManageDataOp.DataValue)
+}
+
+func TestChallenge_clientDomainWithoutSigningKey(t *testing.T) {
+	account := keypair.MustRandom()
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{}, nil)
+
+	h := challengeHandler{
+		Logger:             supportlog.DefaultLogger,
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SigningKeys:        signer.MustNewInMemory(keypair.MustRandom().Seed()),
+		ChallengeExpiresIn: time.Minute,
+		HomeDomains:        []string{"testdomain"},
+		ClientDomains: &clientDomainSigningKeys{
+			StellarTOML:   stellarTOML,
+			CacheDuration: time.Minute,
+		},
+	}
+
+	r := httptest.NewRequest("GET", "/?account="+account.Address()+"&client_domain=wallet.example.com", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,57 @@
+package serve
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
+// clientDomainSigningKeys looks up the SIGNING_KEY of a client domain's
+// stellar.toml, caching the keys found so that the stellar.toml of a client
+// domain is fetched at most once per cache duration, not once for each
+// challenge and token request.
+type clientDomainSigningKeys struct {
+	StellarTOML   stellartoml.ClientInterface
+	CacheDuration time.Duration
+
+	mu    sync.Mutex
+	cache map[string]clientDomainSigningKey
+}
+
+type clientDomainSigningKey struct {
+	SigningKey string
+	FetchedAt  time.Time
+}
+
+// SigningKey returns the SIGNING_KEY of the client domain's stellar.toml.
+// Errors fetching the stellar.toml are not cached.
+func (c *clientDomainSigningKeys) SigningKey(clientDomain string) (string, error) {
+	c.mu.Lock()
+	k, ok := c.cache[clientDomain]
+	c.mu.Unlock()
+	if ok && time.Since(k.FetchedAt) < c.CacheDuration {
+		return k.SigningKey, nil
+	}
+
+	resp, err := c.StellarTOML.GetStellarToml(clientDomain)
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching stellar.toml of client domain %s", clientDomain)
+	}
+	if !strkey.IsValidEd25519PublicKey(resp.SigningKey) {
+		return "", errors.Errorf("stellar.toml of client domain %s has no valid SIGNING_KEY", clientDomain)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache == nil {
+		c.cache = map[string]clientDomainSigningKey{}
+	}
+	c.cache[clientDomain] = clientDomainSigningKey{
+		SigningKey: resp.SigningKey,
+		FetchedAt:  time.Now(),
+	}
+	return resp.SigningKey, nil
+}
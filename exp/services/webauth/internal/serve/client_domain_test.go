@@ -0,0 +1,89 @@
+package serve
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDomainSigningKeys_cached(t *testing.T) {
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"}, nil).
+		Once()
+
+	c := &clientDomainSigningKeys{
+		StellarTOML:   stellarTOML,
+		CacheDuration: time.Minute,
+	}
+
+	for i := 0; i < 2; i++ {
+		key, err := c.SigningKey("wallet.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", key)
+	}
+	stellarTOML.AssertExpectations(t)
+}
+
+func TestClientDomainSigningKeys_expired(t *testing.T) {
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"}, nil).
+		Once()
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS"}, nil).
+		Once()
+
+	c := &clientDomainSigningKeys{
+		StellarTOML:   stellarTOML,
+		CacheDuration: 0,
+	}
+
+	key, err := c.SigningKey("wallet.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", key)
+
+	key, err = c.SigningKey("wallet.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS", key)
+	stellarTOML.AssertExpectations(t)
+}
+
+func TestClientDomainSigningKeys_errorsNotCached(t *testing.T) {
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return((*stellartoml.Response)(nil), errors.New("http request errored")).
+		Once()
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: "invalid"}, nil).
+		Once()
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"}, nil).
+		Once()
+
+	c := &clientDomainSigningKeys{
+		StellarTOML:   stellarTOML,
+		CacheDuration: time.Minute,
+	}
+
+	_, err := c.SigningKey("wallet.example.com")
+	assert.EqualError(t, err, "fetching stellar.toml of client domain wallet.example.com: http request errored")
+
+	_, err = c.SigningKey("wallet.example.com")
+	assert.EqualError(t, err, "stellar.toml of client domain wallet.example.com has no valid SIGNING_KEY")
+
+	key, err := c.SigningKey("wallet.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", key)
+	stellarTOML.AssertExpectations(t)
+}
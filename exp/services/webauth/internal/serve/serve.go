@@ -8,7 +8,8 @@ import (
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
@@ -22,21 +23,41 @@ type Options struct {
 	Port                        int
 	NetworkPassphrase           string
 	SigningKeys                 string
+	SigningKeyFile              string
+	SigningKeyFilePassphrase    string
+	SigningKeyURL               string
 	AuthHomeDomains             string
 	ChallengeExpiresIn          time.Duration
 	JWK                         string
 	JWTIssuer                   string
 	JWTExpiresIn                time.Duration
+	AuthHomeDomainJWTs          string
 	AllowAccountsThatDoNotExist bool
+	ClientDomainCacheDuration   time.Duration
+	AdminPort                   int
 }
 
 func Serve(opts Options) {
-	handler, err := handler(opts)
+	signingKeys, err := openSigningKeys(opts)
 	if err != nil {
 		opts.Logger.Fatalf("Error: %v", err)
 		return
 	}
 
+	handler, err := handler(opts, signingKeys)
+	if err != nil {
+		opts.Logger.Fatalf("Error: %v", err)
+		return
+	}
+
+	if opts.AdminPort != 0 {
+		adminDeps := adminDeps{
+			Logger:      opts.Logger,
+			SigningKeys: signingKeys,
+		}
+		go serveAdmin(opts, adminDeps)
+	}
+
 	addr := fmt.Sprintf(":%d", opts.Port)
 	supporthttp.Run(supporthttp.Config{
 		ListenAddr: addr,
@@ -48,21 +69,28 @@ func Serve(opts Options) {
 	})
 }
 
-func handler(opts Options) (http.Handler, error) {
-	signingKeys := []*keypair.Full{}
-	signingKeyStrs := strings.Split(opts.SigningKeys, ",")
-	signingAddresses := make([]*keypair.FromAddress, 0, len(signingKeyStrs))
-
-	for i, signingKeyStr := range signingKeyStrs {
-		signingKey, err := keypair.ParseFull(signingKeyStr)
-		if err != nil {
-			return nil, errors.Wrap(err, "parsing signing key seed")
-		}
-		signingKeys = append(signingKeys, signingKey)
-		signingAddresses = append(signingAddresses, signingKey.FromAddress())
-		opts.Logger.Info("Signing key ", i, ": ", signingKey.Address())
+func openSigningKeys(opts Options) (signer.Keyring, error) {
+	signingKeys, err := signer.Open(signer.Options{
+		Logger:            opts.Logger,
+		Keys:              opts.SigningKeys,
+		KeyFile:           opts.SigningKeyFile,
+		KeyFilePassphrase: opts.SigningKeyFilePassphrase,
+		URL:               opts.SigningKeyURL,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "opening signing keys")
+	}
+	keys, err := signingKeys.Keys()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting signing keys")
 	}
+	for i, k := range keys {
+		opts.Logger.Info("Signing key ", i, ": ", k.Address, " (active: ", k.Active, ")")
+	}
+	return signingKeys, nil
+}
 
+func handler(opts Options, signingKeys signer.Keyring) (http.Handler, error) {
 	homeDomains := strings.Split(opts.AuthHomeDomains, ",")
 	trimmedHomeDomains := make([]string, 0, len(homeDomains))
 	for _, homeDomain := range homeDomains {
@@ -79,6 +107,21 @@ func handler(opts Options) (http.Handler, error) {
 		return nil, errors.New("algorithm (alg) field must be set")
 	}
 
+	homeDomainJWTs, err := parseHomeDomainJWTs(opts.AuthHomeDomainJWTs, trimmedHomeDomains)
+	if err != nil {
+		return nil, err
+	}
+	for homeDomain, hdj := range homeDomainJWTs {
+		opts.Logger.Infof("Home domain %s issues JWTs with issuer %s", homeDomain, hdj.Issuer)
+	}
+
+	clientDomains := &clientDomainSigningKeys{
+		StellarTOML: &stellartoml.Client{
+			HTTP: &http.Client{Timeout: 10 * time.Second},
+		},
+		CacheDuration: opts.ClientDomainCacheDuration,
+	}
+
 	horizonTimeout := horizonclient.HorizonTimeout
 	httpClient := &http.Client{
 		Timeout: horizonTimeout,
@@ -98,21 +141,68 @@ func handler(opts Options) (http.Handler, error) {
 	mux.Get("/", challengeHandler{
 		Logger:             opts.Logger,
 		NetworkPassphrase:  opts.NetworkPassphrase,
-		SigningKey:         signingKeys[0],
+		SigningKeys:        signingKeys,
 		ChallengeExpiresIn: opts.ChallengeExpiresIn,
 		HomeDomains:        trimmedHomeDomains,
+		ClientDomains:      clientDomains,
 	}.ServeHTTP)
 	mux.Post("/", tokenHandler{
 		Logger:                      opts.Logger,
 		HorizonClient:               horizonClient,
 		NetworkPassphrase:           opts.NetworkPassphrase,
-		SigningAddresses:            signingAddresses,
+		SigningKeys:                 signingKeys,
 		JWK:                         jwk,
 		JWTIssuer:                   opts.JWTIssuer,
 		JWTExpiresIn:                opts.JWTExpiresIn,
 		AllowAccountsThatDoNotExist: opts.AllowAccountsThatDoNotExist,
 		HomeDomains:                 trimmedHomeDomains,
+		HomeDomainJWTs:              homeDomainJWTs,
+		ClientDomains:               clientDomains,
 	}.ServeHTTP)
 
 	return mux, nil
 }
+
+// parseHomeDomainJWTs parses the JWT issuer and key of home domains that do
+// not use the default issuer and key, configured as a JSON object:
+//
+//	{"<home domain>": {"jwt_issuer": "<issuer>", "jwk": <JWK>}}
+func parseHomeDomainJWTs(config string, homeDomains []string) (map[string]homeDomainJWT, error) {
+	if config == "" {
+		return nil, nil
+	}
+	parsed := map[string]struct {
+		JWTIssuer string          `json:"jwt_issuer"`
+		JWK       jose.JSONWebKey `json:"jwk"`
+	}{}
+	err := json.Unmarshal([]byte(config), &parsed)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing home domain JWTs")
+	}
+
+	homeDomainJWTs := make(map[string]homeDomainJWT, len(parsed))
+	for homeDomain, p := range parsed {
+		homeDomain = strings.TrimSuffix(homeDomain, ".")
+		supported := false
+		for _, d := range homeDomains {
+			if d == homeDomain {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, errors.Errorf("home domain %s of home domain JWTs is not an auth home domain", homeDomain)
+		}
+		if p.JWTIssuer == "" {
+			return nil, errors.Errorf("jwt_issuer of home domain %s must be set", homeDomain)
+		}
+		if p.JWK.Algorithm == "" {
+			return nil, errors.Errorf("algorithm (alg) field of the JWK of home domain %s must be set", homeDomain)
+		}
+		homeDomainJWTs[homeDomain] = homeDomainJWT{
+			Issuer: p.JWTIssuer,
+			JWK:    p.JWK,
+		}
+	}
+	return homeDomainJWTs, nil
+}
@@ -0,0 +1,34 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/stellar/go/exp/support/signer"
+	supporthttp "github.com/stellar/go/support/http"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func serveAdmin(opts Options, deps adminDeps) {
+	adminHandler := adminHandler(deps)
+
+	addr := fmt.Sprintf(":%d", opts.AdminPort)
+	supporthttp.Run(supporthttp.Config{
+		ListenAddr: addr,
+		Handler:    adminHandler,
+		OnStarting: func() {
+			deps.Logger.Infof("Starting admin port server on %s", addr)
+		},
+	})
+}
+
+type adminDeps struct {
+	Logger      *supportlog.Entry
+	SigningKeys signer.KeySource
+}
+
+func adminHandler(deps adminDeps) http.Handler {
+	mux := supporthttp.NewMux(deps.Logger)
+	mux.Handle("/keys", signer.KeysHandler{Logger: deps.Logger, Keys: deps.SigningKeys})
+	return mux
+}
@@ -0,0 +1,43 @@
+package serve
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/exp/support/jwtkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func TestParseHomeDomainJWTs(t *testing.T) {
+	homeDomains := []string{"example.com", "other.example.com"}
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+	jwkJSON, err := json.Marshal(jwk)
+	require.NoError(t, err)
+
+	homeDomainJWTs, err := parseHomeDomainJWTs("", homeDomains)
+	require.NoError(t, err)
+	assert.Empty(t, homeDomainJWTs)
+
+	homeDomainJWTs, err = parseHomeDomainJWTs(`{"other.example.com.": {"jwt_issuer": "https://other.example.com", "jwk": `+string(jwkJSON)+`}}`, homeDomains)
+	require.NoError(t, err)
+	require.Len(t, homeDomainJWTs, 1)
+	assert.Equal(t, "https://other.example.com", homeDomainJWTs["other.example.com"].Issuer)
+	assert.Equal(t, "ES256", homeDomainJWTs["other.example.com"].JWK.Algorithm)
+
+	_, err = parseHomeDomainJWTs(`{"unknown.example.com": {"jwt_issuer": "https://unknown.example.com", "jwk": `+string(jwkJSON)+`}}`, homeDomains)
+	assert.EqualError(t, err, "home domain unknown.example.com of home domain JWTs is not an auth home domain")
+
+	_, err = parseHomeDomainJWTs(`{"example.com": {"jwk": `+string(jwkJSON)+`}}`, homeDomains)
+	assert.EqualError(t, err, "jwt_issuer of home domain example.com must be set")
+
+	_, err = parseHomeDomainJWTs(`{"example.com": {"jwt_issuer": "https://example.com", "jwk": {}}}`, homeDomains)
+	assert.Error(t, err)
+
+	_, err = parseHomeDomainJWTs(`not json`, homeDomains)
+	assert.Error(t, err)
+}
@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
-	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/support/http/httpdecode"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
@@ -19,12 +19,27 @@ type tokenHandler struct {
 	Logger                      *supportlog.Entry
 	HorizonClient               horizonclient.ClientInterface
 	NetworkPassphrase           string
-	SigningAddresses            []*keypair.FromAddress
+	SigningKeys                 signer.KeySource
 	JWK                         jose.JSONWebKey
 	JWTIssuer                   string
 	JWTExpiresIn                time.Duration
 	AllowAccountsThatDoNotExist bool
 	HomeDomains                 []string
+	HomeDomainJWTs              map[string]homeDomainJWT
+	ClientDomains               *clientDomainSigningKeys
+}
+
+// homeDomainJWT is the JWT issuer and key used for tokens of a home domain
+// instead of the default issuer and key.
+type homeDomainJWT struct {
+	Issuer string
+	JWK    jose.JSONWebKey
+}
+
+// tokenClaims are the claims of the JWT in addition to the registered claims.
+type tokenClaims struct {
+	ClientDomain           string `json:"client_domain,omitempty"`
+	ClientDomainSigningKey string `json:"client_domain_signing_key,omitempty"`
 }
 
 type tokenRequest struct {
@@ -46,20 +61,29 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	signingKeys, err := h.SigningKeys.Keys()
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Challenges signed by inactive keys are accepted so that challenges
+	// issued before a key rotation can still be exchanged for a token.
 	var (
 		tx              *txnbuild.Transaction
 		clientAccountID string
-		signingAddress  *keypair.FromAddress
+		signingAddress  string
 		homeDomain      string
 	)
-	for _, s := range h.SigningAddresses {
-		tx, clientAccountID, homeDomain, err = txnbuild.ReadChallengeTx(req.Transaction, s.Address(), h.NetworkPassphrase, h.HomeDomains)
+	for _, k := range signingKeys {
+		tx, clientAccountID, homeDomain, err = txnbuild.ReadChallengeTx(req.Transaction, k.Address, h.NetworkPassphrase, h.HomeDomains)
 		if err == nil {
-			signingAddress = s
+			signingAddress = k.Address
 			break
 		}
 	}
-	if signingAddress == nil {
+	if signingAddress == "" {
 		badRequest.Render(w)
 		return
 	}
@@ -78,11 +102,33 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l := h.Logger.Ctx(ctx).
 		WithField("tx", hash).
 		WithField("account", clientAccountID).
-		WithField("serversigner", signingAddress.Address()).
+		WithField("serversigner", signingAddress).
 		WithField("homedomain", homeDomain)
 
 	l.Info("Start verifying challenge transaction.")
 
+	// If the challenge is attributed to a client domain, the client domain
+	// operation must be sourced by the SIGNING_KEY of the client domain's
+	// stellar.toml. The signature of the key is verified along with the
+	// client's signatures.
+	clientDomain, clientSigningKey := txnbuild.ChallengeTxClientDomain(tx)
+	if clientDomain != "" {
+		l = l.WithField("clientdomain", clientDomain)
+		wantClientSigningKey, err := h.ClientDomains.SigningKey(clientDomain)
+		if err != nil {
+			l.Info("Failed to get client domain signing key: ", err)
+			unauthorized.Render(w)
+			return
+		}
+		if clientSigningKey != wantClientSigningKey {
+			l.
+				WithField("clientsigningkey", clientSigningKey).
+				Info("Failed to verify client domain signing key is the client domain's SIGNING_KEY.")
+			unauthorized.Render(w)
+			return
+		}
+	}
+
 	var clientAccountExists bool
 	clientAccount, err := h.HorizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: clientAccountID})
 	switch {
@@ -102,7 +148,7 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if clientAccountExists {
 		requiredThreshold := txnbuild.Threshold(clientAccount.Thresholds.HighThreshold)
 		clientSignerSummary := clientAccount.SignerSummary()
-		signersVerified, err = txnbuild.VerifyChallengeTxThreshold(req.Transaction, signingAddress.Address(), h.NetworkPassphrase, h.HomeDomains, requiredThreshold, clientSignerSummary)
+		signersVerified, err = txnbuild.VerifyChallengeTxThreshold(req.Transaction, signingAddress, h.NetworkPassphrase, h.HomeDomains, requiredThreshold, clientSignerSummary)
 		if err != nil {
 			l.
 				WithField("signersCount", len(clientSignerSummary)).
@@ -118,7 +164,7 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			unauthorized.Render(w)
 			return
 		}
-		signersVerified, err = txnbuild.VerifyChallengeTxSigners(req.Transaction, signingAddress.Address(), h.NetworkPassphrase, h.HomeDomains, clientAccountID)
+		signersVerified, err = txnbuild.VerifyChallengeTxSigners(req.Transaction, signingAddress, h.NetworkPassphrase, h.HomeDomains, clientAccountID)
 		if err != nil {
 			l.Infof("Failed to verify with account master key as signer.")
 			unauthorized.Render(w)
@@ -130,9 +176,16 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		WithField("signers", strings.Join(signersVerified, ",")).
 		Infof("Successfully verified challenge transaction.")
 
+	jwtIssuer := h.JWTIssuer
+	jwk := h.JWK
+	if hdj, ok := h.HomeDomainJWTs[homeDomain]; ok {
+		jwtIssuer = hdj.Issuer
+		jwk = hdj.JWK
+	}
+
 	jwsOptions := &jose.SignerOptions{}
 	jwsOptions.WithType("JWT")
-	jws, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.SignatureAlgorithm(h.JWK.Algorithm), Key: h.JWK.Key}, jwsOptions)
+	jws, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.SignatureAlgorithm(jwk.Algorithm), Key: jwk.Key}, jwsOptions)
 	if err != nil {
 		l.WithStack(err).Error(err)
 		serverError.Render(w)
@@ -141,12 +194,16 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	now := time.Now().UTC()
 	claims := jwt.Claims{
-		Issuer:   h.JWTIssuer,
+		Issuer:   jwtIssuer,
 		Subject:  clientAccountID,
 		IssuedAt: jwt.NewNumericDate(now),
 		Expiry:   jwt.NewNumericDate(now.Add(h.JWTExpiresIn)),
 	}
-	tokenStr, err := jwt.Signed(jws).Claims(claims).CompactSerialize()
+	extraClaims := tokenClaims{
+		ClientDomain:           clientDomain,
+		ClientDomainSigningKey: clientSigningKey,
+	}
+	tokenStr, err := jwt.Signed(jws).Claims(claims).Claims(extraClaims).CompactSerialize()
 	if err != nil {
 		l.WithStack(err).Error(err)
 		serverError.Render(w)
@@ -14,7 +14,9 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/clients/stellartoml"
 	"github.com/stellar/go/exp/support/jwtkey"
+	"github.com/stellar/go/exp/support/signer"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/protocols/horizon"
@@ -42,6 +44,8 @@ func TestToken_formInputSuccess(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -80,7 +84,7 @@ func TestToken_formInputSuccess(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -142,6 +146,8 @@ func TestToken_jsonInputSuccess(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -180,7 +186,7 @@ func TestToken_jsonInputSuccess(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -236,11 +242,10 @@ func TestToken_jsonInputSuccess(t *testing.T) {
 // key, along with the accounts signing keys.
 func TestToken_jsonInputValidRotatingServerSigners(t *testing.T) {
 	serverKeys := []*keypair.Full{keypair.MustRandom(), keypair.MustRandom()}
-	serverKeyAddresses := []*keypair.FromAddress{}
+	serverKeyAddresses := signer.Keys{}
 	for i, serverKey := range serverKeys {
-		serverKeyAddress := serverKey.FromAddress()
-		serverKeyAddresses = append(serverKeyAddresses, serverKeyAddress)
-		t.Logf("Server signing key %d: %v", i, serverKeyAddress)
+		serverKeyAddresses = append(serverKeyAddresses, signer.Key{Address: serverKey.Address(), Active: i == 0})
+		t.Logf("Server signing key %d: %v", i, serverKey.Address())
 	}
 
 	jwtPrivateKey, err := jwtkey.GenerateKey()
@@ -284,7 +289,7 @@ func TestToken_jsonInputValidRotatingServerSigners(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  serverKeyAddresses,
+		SigningKeys:       serverKeyAddresses,
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -298,6 +303,8 @@ func TestToken_jsonInputValidRotatingServerSigners(t *testing.T) {
 				serverKey.Seed(),
 				account.Address(),
 				homeDomain,
+				"",
+				"",
 				network.TestNetworkPassphrase,
 				time.Minute,
 			)
@@ -384,6 +391,8 @@ func TestToken_jsonInputValidMultipleSigners(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -426,7 +435,7 @@ func TestToken_jsonInputValidMultipleSigners(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -492,6 +501,8 @@ func TestToken_jsonInputNotEnoughWeight(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -530,7 +541,7 @@ func TestToken_jsonInputNotEnoughWeight(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -575,6 +586,8 @@ func TestToken_jsonInputUnrecognizedSigner(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -613,7 +626,7 @@ func TestToken_jsonInputUnrecognizedSigner(t *testing.T) {
 		Logger:            supportlog.DefaultLogger,
 		HorizonClient:     horizonClient,
 		NetworkPassphrase: network.TestNetworkPassphrase,
-		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
 		JWK:               jwk,
 		JWTIssuer:         "https://example.com",
 		JWTExpiresIn:      time.Minute,
@@ -658,6 +671,8 @@ func TestToken_jsonInputAccountNotExistSuccess(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -691,7 +706,7 @@ func TestToken_jsonInputAccountNotExistSuccess(t *testing.T) {
 		Logger:                      supportlog.DefaultLogger,
 		HorizonClient:               horizonClient,
 		NetworkPassphrase:           network.TestNetworkPassphrase,
-		SigningAddresses:            []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
 		JWK:                         jwk,
 		JWTIssuer:                   "https://example.com",
 		JWTExpiresIn:                time.Minute,
@@ -762,6 +777,8 @@ func TestToken_jsonInputAccountNotExistFail(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -795,7 +812,7 @@ func TestToken_jsonInputAccountNotExistFail(t *testing.T) {
 		Logger:                      supportlog.DefaultLogger,
 		HorizonClient:               horizonClient,
 		NetworkPassphrase:           network.TestNetworkPassphrase,
-		SigningAddresses:            []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
 		JWK:                         jwk,
 		JWTIssuer:                   "https://example.com",
 		JWTExpiresIn:                time.Minute,
@@ -841,6 +858,8 @@ func TestToken_jsonInputAccountNotExistNotAllowed(t *testing.T) {
 		serverKey.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -874,7 +893,7 @@ func TestToken_jsonInputAccountNotExistNotAllowed(t *testing.T) {
 		Logger:                      supportlog.DefaultLogger,
 		HorizonClient:               horizonClient,
 		NetworkPassphrase:           network.TestNetworkPassphrase,
-		SigningAddresses:            []*keypair.FromAddress{serverKey.FromAddress()},
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
 		JWK:                         jwk,
 		JWTIssuer:                   "https://example.com",
 		JWTExpiresIn:                time.Minute,
@@ -922,6 +941,8 @@ func TestToken_jsonInputUnrecognizedServerSigner(t *testing.T) {
 		serverKey1.Seed(),
 		account.Address(),
 		homeDomain,
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Minute,
 	)
@@ -955,7 +976,7 @@ func TestToken_jsonInputUnrecognizedServerSigner(t *testing.T) {
 		Logger:                      supportlog.DefaultLogger,
 		HorizonClient:               horizonClient,
 		NetworkPassphrase:           network.TestNetworkPassphrase,
-		SigningAddresses:            []*keypair.FromAddress{serverKey2.FromAddress()},
+		SigningKeys:                 signer.Keys{{Address: serverKey2.Address()}},
 		JWK:                         jwk,
 		JWTIssuer:                   "https://example.com",
 		JWTExpiresIn:                time.Minute,
@@ -984,3 +1005,335 @@ func TestToken_jsonInputUnrecognizedServerSigner(t *testing.T) {
 
 	assert.JSONEq(t, `{"error":"The request was invalid in some way."}`, string(respBodyBytes))
 }
+
+func TestToken_clientDomain(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	t.Logf("Server signing key: %s", serverKey.Address())
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	account := keypair.MustRandom()
+	t.Logf("Client account: %s", account.Address())
+
+	clientDomainKey := keypair.MustRandom()
+	t.Logf("Client domain signing key: %s", clientDomainKey.Address())
+
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTx(
+		serverKey.Seed(),
+		account.Address(),
+		homeDomain,
+		"wallet.example.com",
+		clientDomainKey.Address(),
+		network.TestNetworkPassphrase,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	tx, err = tx.Sign(network.TestNetworkPassphrase, account, clientDomainKey)
+	require.NoError(t, err)
+	txSigned, err := tx.Base64()
+	require.NoError(t, err)
+	t.Logf("Signed: %s", txSigned)
+
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{
+				Thresholds: horizon.AccountThresholds{
+					LowThreshold:  1,
+					MedThreshold:  10,
+					HighThreshold: 100,
+				},
+				Signers: []horizon.Signer{
+					{
+						Key:    account.Address(),
+						Weight: 100,
+					},
+				}},
+			nil,
+		)
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: clientDomainKey.Address()}, nil)
+
+	h := tokenHandler{
+		Logger:            supportlog.DefaultLogger,
+		HorizonClient:     horizonClient,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		SigningKeys:       signer.Keys{{Address: serverKey.Address()}},
+		JWK:               jwk,
+		JWTIssuer:         "https://example.com",
+		JWTExpiresIn:      time.Minute,
+		HomeDomains:       []string{homeDomain},
+		ClientDomains: &clientDomainSigningKeys{
+			StellarTOML:   stellarTOML,
+			CacheDuration: time.Minute,
+		},
+	}
+
+	reqBytes, err := json.Marshal(struct {
+		Transaction string `json:"transaction"`
+	}{txSigned})
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(reqBytes))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := struct {
+		Token string `json:"token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(res.Token, func(token *jwt.Token) (interface{}, error) {
+		return &jwtPrivateKey.PublicKey, nil
+	})
+	require.NoError(t, err)
+
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, account.Address(), claims["sub"])
+	assert.Equal(t, "wallet.example.com", claims["client_domain"])
+	assert.Equal(t, clientDomainKey.Address(), claims["client_domain_signing_key"])
+}
+
+func TestToken_clientDomainNotSignedByClientDomain(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+	clientDomainKey := keypair.MustRandom()
+
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTx(
+		serverKey.Seed(),
+		account.Address(),
+		homeDomain,
+		"wallet.example.com",
+		clientDomainKey.Address(),
+		network.TestNetworkPassphrase,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	tx, err = tx.Sign(network.TestNetworkPassphrase, account)
+	require.NoError(t, err)
+	txSigned, err := tx.Base64()
+	require.NoError(t, err)
+
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{},
+			&horizonclient.Error{
+				Problem: problem.P{
+					Type:   "https://stellar.org/horizon-errors/not_found",
+					Title:  "Resource Missing",
+					Status: 404,
+				},
+			},
+		)
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: clientDomainKey.Address()}, nil)
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	h := tokenHandler{
+		Logger:                      supportlog.DefaultLogger,
+		HorizonClient:               horizonClient,
+		NetworkPassphrase:           network.TestNetworkPassphrase,
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
+		JWK:                         jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)},
+		JWTIssuer:                   "https://example.com",
+		JWTExpiresIn:                time.Minute,
+		AllowAccountsThatDoNotExist: true,
+		HomeDomains:                 []string{homeDomain},
+		ClientDomains: &clientDomainSigningKeys{
+			StellarTOML:   stellarTOML,
+			CacheDuration: time.Minute,
+		},
+	}
+
+	reqBytes, err := json.Marshal(struct {
+		Transaction string `json:"transaction"`
+	}{txSigned})
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(reqBytes))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+// Test that a challenge whose client domain operation is not sourced by the
+// SIGNING_KEY of the client domain is rejected even when signed by the key
+// the operation is sourced by.
+func TestToken_clientDomainSigningKeyMismatch(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+	clientDomainKey := keypair.MustRandom()
+	otherKey := keypair.MustRandom()
+
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTx(
+		serverKey.Seed(),
+		account.Address(),
+		homeDomain,
+		"wallet.example.com",
+		otherKey.Address(),
+		network.TestNetworkPassphrase,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	tx, err = tx.Sign(network.TestNetworkPassphrase, account, otherKey)
+	require.NoError(t, err)
+	txSigned, err := tx.Base64()
+	require.NoError(t, err)
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: clientDomainKey.Address()}, nil)
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	h := tokenHandler{
+		Logger:                      supportlog.DefaultLogger,
+		HorizonClient:               &horizonclient.MockClient{},
+		NetworkPassphrase:           network.TestNetworkPassphrase,
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
+		JWK:                         jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)},
+		JWTIssuer:                   "https://example.com",
+		JWTExpiresIn:                time.Minute,
+		AllowAccountsThatDoNotExist: true,
+		HomeDomains:                 []string{homeDomain},
+		ClientDomains: &clientDomainSigningKeys{
+			StellarTOML:   stellarTOML,
+			CacheDuration: time.Minute,
+		},
+	}
+
+	reqBytes, err := json.Marshal(struct {
+		Transaction string `json:"transaction"`
+	}{txSigned})
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(reqBytes))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+// Test that tokens for a home domain with its own JWT issuer and key are
+// issued by that issuer and signed by that key.
+func TestToken_homeDomainJWT(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+
+	defaultJWTPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	otherJWTPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{},
+			&horizonclient.Error{
+				Problem: problem.P{
+					Type:   "https://stellar.org/horizon-errors/not_found",
+					Title:  "Resource Missing",
+					Status: 404,
+				},
+			},
+		)
+
+	h := tokenHandler{
+		Logger:                      supportlog.DefaultLogger,
+		HorizonClient:               horizonClient,
+		NetworkPassphrase:           network.TestNetworkPassphrase,
+		SigningKeys:                 signer.Keys{{Address: serverKey.Address()}},
+		JWK:                         jose.JSONWebKey{Key: defaultJWTPrivateKey, Algorithm: string(jose.ES256)},
+		JWTIssuer:                   "https://example.com",
+		JWTExpiresIn:                time.Minute,
+		AllowAccountsThatDoNotExist: true,
+		HomeDomains:                 []string{"example.com", "other.example.com"},
+		HomeDomainJWTs: map[string]homeDomainJWT{
+			"other.example.com": {
+				Issuer: "https://other.example.com",
+				JWK:    jose.JSONWebKey{Key: otherJWTPrivateKey, Algorithm: string(jose.ES256)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		homeDomain    string
+		wantIssuer    string
+		wantPublicKey interface{}
+	}{
+		{"example.com", "https://example.com", &defaultJWTPrivateKey.PublicKey},
+		{"other.example.com", "https://other.example.com", &otherJWTPrivateKey.PublicKey},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.homeDomain, func(t *testing.T) {
+			tx, err := txnbuild.BuildChallengeTx(
+				serverKey.Seed(),
+				account.Address(),
+				tc.homeDomain,
+				"",
+				"",
+				network.TestNetworkPassphrase,
+				time.Minute,
+			)
+			require.NoError(t, err)
+			tx, err = tx.Sign(network.TestNetworkPassphrase, account)
+			require.NoError(t, err)
+			txSigned, err := tx.Base64()
+			require.NoError(t, err)
+
+			reqBytes, err := json.Marshal(struct {
+				Transaction string `json:"transaction"`
+			}{txSigned})
+			require.NoError(t, err)
+			r := httptest.NewRequest("POST", "/", bytes.NewReader(reqBytes))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			resp := w.Result()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			res := struct {
+				Token string `json:"token"`
+			}{}
+			err = json.NewDecoder(resp.Body).Decode(&res)
+			require.NoError(t, err)
+
+			token, err := jwt.Parse(res.Token, func(token *jwt.Token) (interface{}, error) {
+				return tc.wantPublicKey, nil
+			})
+			require.NoError(t, err)
+
+			claims := token.Claims.(jwt.MapClaims)
+			assert.Equal(t, tc.wantIssuer, claims["iss"])
+			assert.NotContains(t, claims, "client_domain")
+		})
+	}
+}
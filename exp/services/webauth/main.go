@@ -4,6 +4,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/exp/services/webauth/cmd"
+	"github.com/stellar/go/exp/support/signer"
 	supportlog "github.com/stellar/go/support/log"
 )
 
@@ -21,6 +22,7 @@ func main() {
 
 	rootCmd.AddCommand((&cmd.ServeCommand{Logger: logger}).Command())
 	rootCmd.AddCommand((&cmd.GenJWKCommand{Logger: logger}).Command())
+	rootCmd.AddCommand((&signer.KeysCommand{Logger: logger}).Command())
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -0,0 +1,145 @@
+package signer
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// FileKeyring is a keyring of the keys of an encrypted key file. The file is
+// reloaded when it changes, so keys can be rotated by editing the file while
+// the service is running. If a changed file cannot be loaded the error is
+// logged, the keys last loaded continue to be used and loading is retried
+// after KeysRetryInterval, backing off on consecutive failures, or as soon as
+// the file changes again.
+type FileKeyring struct {
+	path       string
+	passphrase string
+	logger     *supportlog.Entry
+
+	mu sync.Mutex
+	// now returns the current time, and is replaced in tests.
+	now     func() time.Time
+	modTime time.Time
+	size    int64
+	keys    []Key
+	signers map[string]KeypairSigner
+
+	// The modification time and size of the file when it last failed to
+	// load, and when loading it is retried if it has not changed since.
+	failedModTime time.Time
+	failedSize    int64
+	retryAt       time.Time
+	failures      int
+}
+
+// NewFileKeyring returns a keyring of the keys of the key file at the path,
+// decrypted with the passphrase.
+func NewFileKeyring(path, passphrase string, logger *supportlog.Entry) (*FileKeyring, error) {
+	k := &FileKeyring{
+		path:       path,
+		passphrase: passphrase,
+		logger:     logger,
+		now:        time.Now,
+	}
+	fi, err := os.Stat(k.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading signing key file")
+	}
+	err = k.load(fi)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Keys returns the keys of the key file.
+func (k *FileKeyring) Keys() ([]Key, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.reloadIfChanged()
+	keys := make([]Key, len(k.keys))
+	copy(keys, k.keys)
+	return keys, nil
+}
+
+// Signer returns the signer for the key with the address.
+func (k *FileKeyring) Signer(address string) (Signer, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.reloadIfChanged()
+	s, ok := k.signers[address]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+// reloadIfChanged loads the keys of the file if the file changed since it
+// was last loaded, unless it failed to load recently and has not changed
+// since. It must be called with the mutex held.
+func (k *FileKeyring) reloadIfChanged() {
+	now := k.now()
+	backingOff := now.Before(k.retryAt)
+
+	fi, err := os.Stat(k.path)
+	if err == nil {
+		if fi.ModTime().Equal(k.modTime) && fi.Size() == k.size {
+			return
+		}
+		if backingOff && fi.ModTime().Equal(k.failedModTime) && fi.Size() == k.failedSize {
+			return
+		}
+		err = k.load(fi)
+		if err != nil {
+			k.failedModTime = fi.ModTime()
+			k.failedSize = fi.Size()
+		}
+	} else if backingOff {
+		return
+	} else {
+		err = errors.Wrap(err, "reading signing key file")
+	}
+
+	if err != nil {
+		k.failures++
+		k.retryAt = now.Add(retryInterval(k.failures))
+		k.logger.WithField("path", k.path).
+			Error("Error reloading signing key file, continuing to use previous keys: ", err)
+		return
+	}
+	k.failures = 0
+	k.retryAt = time.Time{}
+}
+
+// load loads the keys of the file, which has the given file info. It must be
+// called with the mutex held.
+func (k *FileKeyring) load(fi os.FileInfo) error {
+	f, err := ReadKeyFile(k.path)
+	if err != nil {
+		return errors.Wrap(err, "reading signing key file")
+	}
+	kps, err := f.Decrypt(k.passphrase)
+	if err != nil {
+		return err
+	}
+	keys := f.KeyList()
+	if len(keys) == 0 || !keys[0].Active {
+		return ErrNoActiveKey
+	}
+
+	signers := make(map[string]KeypairSigner, len(kps))
+	for address, kp := range kps {
+		signers[address] = KeypairSigner{Keypair: kp}
+	}
+
+	logKeyChanges(k.logger.WithField("path", k.path), k.keys, keys)
+	k.modTime = fi.ModTime()
+	k.size = fi.Size()
+	k.keys = keys
+	k.signers = signers
+	return nil
+}
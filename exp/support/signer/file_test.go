@@ -0,0 +1,148 @@
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyring_rotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	kp1 := keypair.MustParseFull("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK")
+	kp2 := keypair.MustParseFull("SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD")
+	now := time.Now()
+
+	f, err := NewKeyFile()
+	require.NoError(t, err)
+	require.NoError(t, f.Add(kp1, "passphrase", now))
+	require.NoError(t, f.Write(path))
+
+	// A key file without an active key cannot be used.
+	_, err = NewFileKeyring(path, "passphrase", supportlog.DefaultLogger)
+	assert.Equal(t, ErrNoActiveKey, err)
+
+	require.NoError(t, f.Activate(kp1.Address(), now))
+	require.NoError(t, f.Write(path))
+
+	_, err = NewFileKeyring(path, "wrong passphrase", supportlog.DefaultLogger)
+	assert.Error(t, err)
+
+	k, err := NewFileKeyring(path, "passphrase", supportlog.DefaultLogger)
+	require.NoError(t, err)
+
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Address: kp1.Address(), Active: true}}, keys)
+	s, err := ActiveSigner(k)
+	require.NoError(t, err)
+	assert.Equal(t, kp1.Address(), s.Address())
+
+	// Add a new key and make it active while the old key is still available.
+	require.NoError(t, f.Add(kp2, "passphrase", now))
+	require.NoError(t, f.Activate(kp2.Address(), now))
+	require.NoError(t, f.Write(path))
+	touch(t, path, now.Add(time.Second))
+
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{
+		{Address: kp2.Address(), Active: true},
+		{Address: kp1.Address(), Active: false},
+	}, keys)
+	s, err = ActiveSigner(k)
+	require.NoError(t, err)
+	assert.Equal(t, kp2.Address(), s.Address())
+	s, err = k.Signer(kp1.Address())
+	require.NoError(t, err)
+	sig, err := s.Sign([]byte("data"))
+	require.NoError(t, err)
+	assert.NoError(t, kp1.Verify([]byte("data"), sig))
+
+	// A broken file is ignored and the previous keys are used.
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	touch(t, path, now.Add(2*time.Second))
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	// Remove the old key.
+	require.NoError(t, f.Remove(kp1.Address()))
+	require.NoError(t, f.Write(path))
+	touch(t, path, now.Add(3*time.Second))
+
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Address: kp2.Address(), Active: true}}, keys)
+	_, err = k.Signer(kp1.Address())
+	assert.Equal(t, ErrNotFound, err)
+}
+
+// Test that a file that fails to load is not loaded again until the retry
+// interval passes, unless it changes, and that the previous keys are used
+// meanwhile.
+func TestFileKeyring_backoff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	kp := keypair.MustParseFull("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK")
+	now := time.Now()
+
+	f, err := NewKeyFile()
+	require.NoError(t, err)
+	require.NoError(t, f.Add(kp, "passphrase", now))
+	require.NoError(t, f.Activate(kp.Address(), now))
+	require.NoError(t, f.Write(path))
+
+	k, err := NewFileKeyring(path, "passphrase", supportlog.DefaultLogger)
+	require.NoError(t, err)
+	k.now = func() time.Time { return now }
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	touch(t, path, now.Add(time.Second))
+
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Address: kp.Address(), Active: true}}, keys)
+	assert.Equal(t, 1, k.failures)
+	assert.Equal(t, now.Add(KeysRetryInterval), k.retryAt)
+
+	// The unchanged file is not loaded again while backing off.
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 1, k.failures)
+
+	// The file is loaded again once the retry interval passes, and the
+	// interval doubles.
+	now = now.Add(KeysRetryInterval)
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 2, k.failures)
+	assert.Equal(t, now.Add(2*KeysRetryInterval), k.retryAt)
+
+	// A changed file is loaded straight away.
+	require.NoError(t, f.Write(path))
+	touch(t, path, now.Add(2*time.Second))
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Address: kp.Address(), Active: true}}, keys)
+	assert.Equal(t, 0, k.failures)
+}
+
+// touch sets the modification time of the file, so that changes made within
+// the resolution of the file system's modification times are detected.
+func touch(t *testing.T, path string, modTime time.Time) {
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+// KeysHandler publishes the keys of a keyring as JSON, in the same format as
+// the keys endpoint of the remote signing API.
+type KeysHandler struct {
+	Logger *supportlog.Entry
+	Keys   KeySource
+}
+
+func (h KeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.Keys.Keys()
+	if err != nil {
+		h.Logger.Ctx(r.Context()).Error("Error getting signing keys: ", err)
+		http.Error(w, "error getting signing keys", http.StatusInternalServerError)
+		return
+	}
+	if keys == nil {
+		keys = []Key{}
+	}
+	httpjson.Render(w, remoteKeysResponse{Keys: keys}, httpjson.JSON)
+}
+
+// RemoteHandler serves the remote signing API used by RemoteKeyring for the
+// keys of a keyring. It has no authentication of its own and must only be
+// exposed to the services that use it.
+func RemoteHandler(logger *supportlog.Entry, k Keyring) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/keys", KeysHandler{Logger: logger, Keys: k})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		req := remoteSignRequest{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		s, err := k.Signer(req.Address)
+		if err == ErrNotFound {
+			http.Error(w, "signing key not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			logger.Ctx(r.Context()).Error("Error getting signer: ", err)
+			http.Error(w, "error getting signer", http.StatusInternalServerError)
+			return
+		}
+		sig, err := s.Sign(req.Data)
+		if err != nil {
+			logger.Ctx(r.Context()).Error("Error signing: ", err)
+			http.Error(w, "error signing", http.StatusInternalServerError)
+			return
+		}
+		logger.Ctx(r.Context()).WithField("signing_key", req.Address).Info("Signed data.")
+		httpjson.Render(w, remoteSignResponse{Signature: sig}, httpjson.JSON)
+	})
+	return mux
+}
@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// KeypairSigner is a signer for a key held in memory.
+type KeypairSigner struct {
+	Keypair *keypair.Full
+}
+
+// Address returns the Stellar address of the key.
+func (s KeypairSigner) Address() string {
+	return s.Keypair.Address()
+}
+
+// Sign returns the signature of the data.
+func (s KeypairSigner) Sign(data []byte) ([]byte, error) {
+	return s.Keypair.Sign(data)
+}
+
+// InMemory is a keyring of keys held in memory. Its keys never change.
+type InMemory struct {
+	keys    []Key
+	signers map[string]KeypairSigner
+}
+
+// NewInMemory returns a keyring of the keys with the secret seeds given. The
+// first key is the active key, and the other keys are inactive.
+func NewInMemory(seeds ...string) (*InMemory, error) {
+	if len(seeds) == 0 {
+		return nil, errors.New("no signing keys provided")
+	}
+	k := &InMemory{
+		signers: map[string]KeypairSigner{},
+	}
+	for i, seed := range seeds {
+		kp, err := keypair.ParseFull(seed)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing signing key seed")
+		}
+		if _, ok := k.signers[kp.Address()]; ok {
+			continue
+		}
+		k.keys = append(k.keys, Key{Address: kp.Address(), Active: i == 0})
+		k.signers[kp.Address()] = KeypairSigner{Keypair: kp}
+	}
+	return k, nil
+}
+
+// MustNewInMemory is like NewInMemory but panics on error.
+func MustNewInMemory(seeds ...string) *InMemory {
+	k, err := NewInMemory(seeds...)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// Keys returns the keys of the keyring.
+func (k *InMemory) Keys() ([]Key, error) {
+	keys := make([]Key, len(k.keys))
+	copy(keys, k.keys)
+	return keys, nil
+}
+
+// Signer returns the signer for the key with the address.
+func (k *InMemory) Signer(address string) (Signer, error) {
+	s, ok := k.signers[address]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemory(t *testing.T) {
+	k, err := NewInMemory(
+		"SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+		"SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD", // GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS
+	)
+	require.NoError(t, err)
+
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	wantKeys := []Key{
+		{Address: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", Active: true},
+		{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS", Active: false},
+	}
+	assert.Equal(t, wantKeys, keys)
+
+	s, err := k.Signer("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS")
+	require.NoError(t, err)
+	assert.Equal(t, "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS", s.Address())
+	sig, err := s.Sign([]byte("data"))
+	require.NoError(t, err)
+	err = keypair.MustParseAddress("GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS").Verify([]byte("data"), sig)
+	assert.NoError(t, err)
+
+	s, err = ActiveSigner(k)
+	require.NoError(t, err)
+	assert.Equal(t, "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", s.Address())
+
+	_, err = k.Signer("GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestInMemory_invalid(t *testing.T) {
+	_, err := NewInMemory()
+	assert.EqualError(t, err, "no signing keys provided")
+
+	_, err = NewInMemory("GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H")
+	assert.Error(t, err)
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open(Options{})
+	assert.EqualError(t, err, "exactly one of signing keys, a signing key file or a signing key URL must be configured")
+
+	_, err = Open(Options{Keys: "SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", URL: "http://localhost"})
+	assert.EqualError(t, err, "exactly one of signing keys, a signing key file or a signing key URL must be configured")
+
+	k, err := Open(Options{Keys: "SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK,SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD"})
+	require.NoError(t, err)
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
@@ -0,0 +1,234 @@
+package signer
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const keyFileVersion = 1
+
+// The scrypt parameters used to derive the encryption key of a key file from
+// its passphrase.
+const (
+	keyFileScryptN = 32768
+	keyFileScryptR = 8
+	keyFileScryptP = 1
+)
+
+// KeyFile is a file of signing keys whose secret seeds are encrypted with a
+// key derived from a passphrase. The file records when keys were added,
+// activated and deactivated.
+type KeyFile struct {
+	Version int          `json:"version"`
+	Salt    []byte       `json:"salt"`
+	Keys    []KeyFileKey `json:"keys"`
+}
+
+// KeyFileKey is a key of a key file.
+type KeyFileKey struct {
+	Address       string     `json:"address"`
+	Active        bool       `json:"active"`
+	AddedAt       time.Time  `json:"added_at"`
+	ActivatedAt   *time.Time `json:"activated_at,omitempty"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	Nonce         []byte     `json:"nonce"`
+	EncryptedSeed []byte     `json:"encrypted_seed"`
+}
+
+// NewKeyFile returns an empty key file.
+func NewKeyFile() (*KeyFile, error) {
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating salt")
+	}
+	return &KeyFile{Version: keyFileVersion, Salt: salt}, nil
+}
+
+// ReadKeyFile reads the key file at the path.
+func ReadKeyFile(path string) (*KeyFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &KeyFile{}
+	err = json.Unmarshal(b, f)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing key file")
+	}
+	if f.Version != keyFileVersion {
+		return nil, errors.Errorf("key file version %d unsupported", f.Version)
+	}
+	return f, nil
+}
+
+// Write writes the key file to the path, replacing any existing file
+// atomically so that a keyring reading the file never reads a partial file.
+func (f *KeyFile) Write(path string) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	// TempFile creates files only readable and writable by the owner.
+	return os.Rename(tmp.Name(), path)
+}
+
+// Add adds the key to the file as an inactive key. The passphrase must be the
+// passphrase of the other keys in the file.
+func (f *KeyFile) Add(kp *keypair.Full, passphrase string, now time.Time) error {
+	for _, k := range f.Keys {
+		if k.Address == kp.Address() {
+			return errors.Errorf("key %s already in key file", kp.Address())
+		}
+	}
+
+	secretKey, err := f.secretKey(passphrase)
+	if err != nil {
+		return err
+	}
+	if len(f.Keys) > 0 {
+		_, err = f.Keys[0].decrypt(secretKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	var nonce [24]byte
+	_, err = rand.Read(nonce[:])
+	if err != nil {
+		return errors.Wrap(err, "generating nonce")
+	}
+	f.Keys = append(f.Keys, KeyFileKey{
+		Address:       kp.Address(),
+		AddedAt:       now.UTC(),
+		Nonce:         nonce[:],
+		EncryptedSeed: secretbox.Seal(nil, []byte(kp.Seed()), &nonce, &secretKey),
+	})
+	return nil
+}
+
+// Activate makes the key with the address the only active key of the file.
+// Other active keys are deactivated but remain in the file until they are
+// removed.
+func (f *KeyFile) Activate(address string, now time.Time) error {
+	found := false
+	for _, k := range f.Keys {
+		if k.Address == address {
+			found = true
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+
+	now = now.UTC()
+	for i := range f.Keys {
+		k := &f.Keys[i]
+		if k.Address == address && !k.Active {
+			k.Active = true
+			k.ActivatedAt = &now
+			k.DeactivatedAt = nil
+		} else if k.Address != address && k.Active {
+			k.Active = false
+			k.DeactivatedAt = &now
+		}
+	}
+	return nil
+}
+
+// Remove removes the inactive key with the address from the file.
+func (f *KeyFile) Remove(address string) error {
+	for i, k := range f.Keys {
+		if k.Address != address {
+			continue
+		}
+		if k.Active {
+			return errors.Errorf("key %s is active and must be replaced by activating another key before being removed", address)
+		}
+		f.Keys = append(f.Keys[:i], f.Keys[i+1:]...)
+		return nil
+	}
+	return ErrNotFound
+}
+
+// KeyList returns the keys of the file, active keys first.
+func (f *KeyFile) KeyList() []Key {
+	keys := make([]Key, 0, len(f.Keys))
+	for _, k := range f.Keys {
+		keys = append(keys, Key{Address: k.Address, Active: k.Active})
+	}
+	return sortKeys(keys)
+}
+
+// Decrypt returns the keypairs of the keys of the file indexed by address.
+func (f *KeyFile) Decrypt(passphrase string) (map[string]*keypair.Full, error) {
+	secretKey, err := f.secretKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	kps := map[string]*keypair.Full{}
+	for _, k := range f.Keys {
+		kp, err := k.decrypt(secretKey)
+		if err != nil {
+			return nil, err
+		}
+		kps[k.Address] = kp
+	}
+	return kps, nil
+}
+
+func (f *KeyFile) secretKey(passphrase string) ([32]byte, error) {
+	var secretKey [32]byte
+	if passphrase == "" {
+		return secretKey, errors.New("key file passphrase is empty")
+	}
+	b, err := scrypt.Key([]byte(passphrase), f.Salt, keyFileScryptN, keyFileScryptR, keyFileScryptP, len(secretKey))
+	if err != nil {
+		return secretKey, errors.Wrap(err, "deriving key file secret key")
+	}
+	copy(secretKey[:], b)
+	return secretKey, nil
+}
+
+func (k KeyFileKey) decrypt(secretKey [32]byte) (*keypair.Full, error) {
+	var nonce [24]byte
+	if len(k.Nonce) != len(nonce) {
+		return nil, errors.Errorf("key %s has an invalid nonce", k.Address)
+	}
+	copy(nonce[:], k.Nonce)
+	seed, ok := secretbox.Open(nil, k.EncryptedSeed, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.Errorf("decrypting key %s failed, the passphrase may be incorrect", k.Address)
+	}
+	kp, err := keypair.ParseFull(string(seed))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing key %s", k.Address)
+	}
+	if kp.Address() != k.Address {
+		return nil, errors.Errorf("key %s decrypted to a different key %s", k.Address, kp.Address())
+	}
+	return kp, nil
+}
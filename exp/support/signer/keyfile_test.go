@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	kp1 := keypair.MustParseFull("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK")
+	kp2 := keypair.MustParseFull("SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD")
+	now := time.Date(2020, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	f, err := NewKeyFile()
+	require.NoError(t, err)
+	require.NoError(t, f.Add(kp1, "passphrase", now))
+	require.NoError(t, f.Activate(kp1.Address(), now))
+	require.NoError(t, f.Write(path))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// The seeds are not stored in plain text.
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), kp1.Seed())
+
+	f, err = ReadKeyFile(path)
+	require.NoError(t, err)
+
+	// Keys added must use the same passphrase.
+	err = f.Add(kp2, "other passphrase", now)
+	assert.EqualError(t, err, "decrypting key "+kp1.Address()+" failed, the passphrase may be incorrect")
+	err = f.Add(kp1, "passphrase", now)
+	assert.EqualError(t, err, "key "+kp1.Address()+" already in key file")
+
+	later := now.Add(time.Hour)
+	require.NoError(t, f.Add(kp2, "passphrase", later))
+	assert.Equal(t, []Key{
+		{Address: kp1.Address(), Active: true},
+		{Address: kp2.Address(), Active: false},
+	}, f.KeyList())
+
+	require.NoError(t, f.Activate(kp2.Address(), later))
+	assert.Equal(t, []Key{
+		{Address: kp2.Address(), Active: true},
+		{Address: kp1.Address(), Active: false},
+	}, f.KeyList())
+	assert.Equal(t, &later, f.Keys[0].DeactivatedAt)
+	assert.Equal(t, &later, f.Keys[1].ActivatedAt)
+
+	assert.Equal(t, ErrNotFound, f.Activate("GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6", later))
+	assert.EqualError(t, f.Remove(kp2.Address()), "key "+kp2.Address()+" is active and must be replaced by activating another key before being removed")
+
+	kps, err := f.Decrypt("passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, kp1.Seed(), kps[kp1.Address()].Seed())
+	assert.Equal(t, kp2.Seed(), kps[kp2.Address()].Seed())
+
+	_, err = f.Decrypt("other passphrase")
+	assert.Error(t, err)
+
+	require.NoError(t, f.Remove(kp1.Address()))
+	assert.Equal(t, []Key{{Address: kp2.Address(), Active: true}}, f.KeyList())
+	assert.Equal(t, ErrNotFound, f.Remove(kp1.Address()))
+}
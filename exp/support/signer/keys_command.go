@@ -0,0 +1,191 @@
+package signer
+
+import (
+	"bufio"
+	"fmt"
+	"go/types"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/config"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// KeysCommand is the command services use to manage the signing keys of a
+// signing key file.
+type KeysCommand struct {
+	Logger            *supportlog.Entry
+	KeyFile           string
+	KeyFilePassphrase string
+	Import            bool
+}
+
+func (c *KeysCommand) Command() *cobra.Command {
+	configOpts := config.ConfigOptions{
+		{
+			Name:      "signing-key-file",
+			Usage:     "Path of the encrypted file containing the Stellar signing keys",
+			OptType:   types.String,
+			ConfigKey: &c.KeyFile,
+			Required:  true,
+		},
+		{
+			Name:      "signing-key-file-passphrase",
+			Usage:     "Passphrase the keys of the signing key file are encrypted with",
+			OptType:   types.String,
+			ConfigKey: &c.KeyFilePassphrase,
+			Required:  true,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the signing keys of a signing key file",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	configOpts.Init(cmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the signing keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			c.List()
+		},
+	}
+	cmd.AddCommand(listCmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a new inactive signing key, creating the file if it does not exist",
+		Run: func(cmd *cobra.Command, args []string) {
+			c.Add()
+		},
+	}
+	addCmd.Flags().BoolVar(&c.Import, "import", false, "Import the secret seed read from stdin instead of generating a new key")
+	cmd.AddCommand(addCmd)
+
+	activateCmd := &cobra.Command{
+		Use:   "activate [address]",
+		Short: "Make the signing key the active key, deactivating the previously active key",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				return
+			}
+			c.Activate(args[0])
+		},
+	}
+	cmd.AddCommand(activateCmd)
+
+	removeCmd := &cobra.Command{
+		Use:   "remove [address]",
+		Short: "Remove an inactive signing key",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				return
+			}
+			c.Remove(args[0])
+		},
+	}
+	cmd.AddCommand(removeCmd)
+
+	return cmd
+}
+
+func (c *KeysCommand) List() {
+	f, err := ReadKeyFile(c.KeyFile)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	_, err = f.Decrypt(c.KeyFilePassphrase)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	for _, k := range f.Keys {
+		status := "inactive"
+		if k.Active {
+			status = "active"
+		}
+		fmt.Printf("%s %s added %s\n", k.Address, status, k.AddedAt.Format(time.RFC3339))
+	}
+}
+
+func (c *KeysCommand) Add() {
+	f, err := ReadKeyFile(c.KeyFile)
+	if os.IsNotExist(err) {
+		f, err = NewKeyFile()
+	}
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+
+	var kp *keypair.Full
+	if c.Import {
+		seed, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && seed == "" {
+			c.Logger.Fatal("Error reading secret seed from stdin: ", err)
+		}
+		kp, err = keypair.ParseFull(strings.TrimSpace(seed))
+		if err != nil {
+			c.Logger.Fatal("Error parsing secret seed: ", err)
+		}
+	} else {
+		kp, err = keypair.Random()
+		if err != nil {
+			c.Logger.Fatal(err)
+		}
+	}
+
+	err = f.Add(kp, c.KeyFilePassphrase, time.Now())
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	err = f.Write(c.KeyFile)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	c.Logger.WithField("signing_key", kp.Address()).Info("Signing key added as inactive key.")
+}
+
+func (c *KeysCommand) Activate(address string) {
+	c.edit(address, "Signing key activated.", func(f *KeyFile) error {
+		return f.Activate(address, time.Now())
+	})
+}
+
+func (c *KeysCommand) Remove(address string) {
+	c.edit(address, "Signing key removed.", func(f *KeyFile) error {
+		return f.Remove(address)
+	})
+}
+
+func (c *KeysCommand) edit(address, msg string, fn func(f *KeyFile) error) {
+	f, err := ReadKeyFile(c.KeyFile)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	// Check the passphrase so that keys are only changed by those who can
+	// use them.
+	_, err = f.Decrypt(c.KeyFilePassphrase)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	err = fn(f)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	err = f.Write(c.KeyFile)
+	if err != nil {
+		c.Logger.Fatal(err)
+	}
+	c.Logger.WithField("signing_key", address).Info(msg)
+}
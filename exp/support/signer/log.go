@@ -0,0 +1,37 @@
+package signer
+
+import (
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// logKeyChanges logs the keys added, removed, activated and deactivated
+// between two lists of keys of a keyring, so that the logs of a service
+// contain an audit trail of its key rotations.
+func logKeyChanges(l *supportlog.Entry, before, after []Key) {
+	beforeByAddress := map[string]Key{}
+	for _, k := range before {
+		beforeByAddress[k.Address] = k
+	}
+	afterByAddress := map[string]Key{}
+	for _, k := range after {
+		afterByAddress[k.Address] = k
+	}
+
+	for _, k := range after {
+		kl := l.WithField("signing_key", k.Address).WithField("active", k.Active)
+		b, ok := beforeByAddress[k.Address]
+		switch {
+		case !ok:
+			kl.Info("Signing key added.")
+		case !b.Active && k.Active:
+			kl.Info("Signing key activated.")
+		case b.Active && !k.Active:
+			kl.Info("Signing key deactivated.")
+		}
+	}
+	for _, k := range before {
+		if _, ok := afterByAddress[k.Address]; !ok {
+			l.WithField("signing_key", k.Address).Info("Signing key removed.")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package signer
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// Options configures the keyring returned by Open. Exactly one of Keys,
+// KeyFile or URL must be set.
+type Options struct {
+	Logger *supportlog.Entry
+	// Keys are secret seeds comma separated, the first being the active key.
+	Keys string
+	// KeyFile is the path of an encrypted key file, and KeyFilePassphrase
+	// the passphrase its keys are encrypted with.
+	KeyFile           string
+	KeyFilePassphrase string
+	// URL is the URL of a remote signing API.
+	URL string
+}
+
+// Open returns the keyring configured by the options.
+func Open(opts Options) (Keyring, error) {
+	configured := 0
+	for _, o := range []string{opts.Keys, opts.KeyFile, opts.URL} {
+		if o != "" {
+			configured++
+		}
+	}
+	if configured != 1 {
+		return nil, errors.New("exactly one of signing keys, a signing key file or a signing key URL must be configured")
+	}
+
+	switch {
+	case opts.KeyFile != "":
+		return NewFileKeyring(opts.KeyFile, opts.KeyFilePassphrase, opts.Logger)
+	case opts.URL != "":
+		return NewRemoteKeyring(opts.URL, &http.Client{Timeout: 10 * time.Second}, opts.Logger)
+	default:
+		return NewInMemory(strings.Split(opts.Keys, ",")...)
+	}
+}
@@ -0,0 +1,185 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// RemoteKeysCacheDuration is how long a remote keyring uses the keys it
+// fetched before fetching them again.
+const RemoteKeysCacheDuration = time.Minute
+
+// RemoteKeyring is a keyring of the keys of a remote signing HTTP API. The
+// keys are never held by the keyring, data is sent to the API to be signed.
+//
+// The API must implement two endpoints relative to its URL:
+//
+//	GET /keys returning the keys as {"keys":[{"address":"G...","active":true}]}.
+//	POST /sign accepting {"address":"G...","data":"<base64>"} and returning
+//	the ed25519 signature of the data as {"signature":"<base64>"}.
+//
+// Signatures returned by the API are verified before being used. If fetching
+// the keys fails the error is logged, the keys last fetched continue to be
+// used and fetching is retried after KeysRetryInterval, backing off on
+// consecutive failures. RemoteHandler serves the API for any keyring.
+type RemoteKeyring struct {
+	url    string
+	client *http.Client
+	logger *supportlog.Entry
+
+	mu sync.Mutex
+	// now returns the current time, and is replaced in tests.
+	now  func() time.Time
+	keys []Key
+	// nextFetchAt is when the keys are fetched next, which is later after
+	// failures so that a failing API is not called on every use.
+	nextFetchAt time.Time
+	failures    int
+}
+
+// NewRemoteKeyring returns a keyring of the keys of the signing API at the
+// URL. The keys are fetched before returning.
+func NewRemoteKeyring(url string, client *http.Client, logger *supportlog.Entry) (*RemoteKeyring, error) {
+	k := &RemoteKeyring{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+		logger: logger,
+		now:    time.Now,
+	}
+	_, err := k.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+type remoteKeysResponse struct {
+	Keys []Key `json:"keys"`
+}
+
+type remoteSignRequest struct {
+	Address string `json:"address"`
+	Data    []byte `json:"data"`
+}
+
+type remoteSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// Keys returns the keys of the signing API.
+func (k *RemoteKeyring) Keys() ([]Key, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	if k.keys == nil || !now.Before(k.nextFetchAt) {
+		keys, err := k.fetchKeys()
+		if err != nil {
+			k.failures++
+			k.nextFetchAt = now.Add(retryInterval(k.failures))
+			if k.keys == nil {
+				return nil, err
+			}
+			k.logger.WithField("url", k.url).
+				Error("Error fetching signing keys, continuing to use previous keys: ", err)
+		} else {
+			logKeyChanges(k.logger.WithField("url", k.url), k.keys, keys)
+			k.keys = keys
+			k.failures = 0
+			k.nextFetchAt = now.Add(RemoteKeysCacheDuration)
+		}
+	}
+
+	keys := make([]Key, len(k.keys))
+	copy(keys, k.keys)
+	return keys, nil
+}
+
+func (k *RemoteKeyring) fetchKeys() ([]Key, error) {
+	resp, err := k.client.Get(k.url + "/keys")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching signing keys")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetching signing keys: unexpected status %d", resp.StatusCode)
+	}
+	keysResp := remoteKeysResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&keysResp)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding signing keys")
+	}
+	for _, key := range keysResp.Keys {
+		_, err = keypair.ParseAddress(key.Address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing signing key %q", key.Address)
+		}
+	}
+	keys := sortKeys(keysResp.Keys)
+	if len(keys) == 0 || !keys[0].Active {
+		return nil, ErrNoActiveKey
+	}
+	return keys, nil
+}
+
+// Signer returns a signer that signs with the key with the address using the
+// signing API.
+func (k *RemoteKeyring) Signer(address string) (Signer, error) {
+	keys, err := k.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if key.Address == address {
+			return remoteSigner{keyring: k, address: address}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+type remoteSigner struct {
+	keyring *RemoteKeyring
+	address string
+}
+
+func (s remoteSigner) Address() string {
+	return s.address
+}
+
+func (s remoteSigner) Sign(data []byte) ([]byte, error) {
+	body, err := json.Marshal(remoteSignRequest{Address: s.address, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.keyring.client.Post(s.keyring.url+"/sign", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "requesting signature")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("requesting signature: unexpected status %d", resp.StatusCode)
+	}
+	signResp := remoteSignResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&signResp)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding signature")
+	}
+
+	kp, err := keypair.ParseAddress(s.address)
+	if err != nil {
+		return nil, err
+	}
+	err = kp.Verify(data, signResp.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "verifying signature returned by signing API")
+	}
+	return signResp.Signature, nil
+}
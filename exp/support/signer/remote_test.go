@@ -0,0 +1,149 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteKeyring(t *testing.T) {
+	backing := MustNewInMemory(
+		"SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+		"SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD", // GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS
+	)
+	server := httptest.NewServer(RemoteHandler(supportlog.DefaultLogger, backing))
+	defer server.Close()
+
+	k, err := NewRemoteKeyring(server.URL+"/", server.Client(), supportlog.DefaultLogger)
+	require.NoError(t, err)
+
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	wantKeys := []Key{
+		{Address: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", Active: true},
+		{Address: "GAPE22DOMALCH42VOR4S3HN6KIZZ643G7D3GNTYF4YOWWXP6UVRAF5JS", Active: false},
+	}
+	assert.Equal(t, wantKeys, keys)
+
+	s, err := ActiveSigner(k)
+	require.NoError(t, err)
+	assert.Equal(t, "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", s.Address())
+	sig, err := s.Sign([]byte("data"))
+	require.NoError(t, err)
+	err = keypair.MustParseAddress("GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H").Verify([]byte("data"), sig)
+	assert.NoError(t, err)
+
+	_, err = k.Signer("GD4NGMOTV4QOXWA6PGPIGVWZYMRCJAKLQJKZIP55C5DGB3GBHHET3YC6")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+// Test that keys that fail to be fetched are not fetched again until the
+// retry interval passes, and that the previous keys are used meanwhile.
+func TestRemoteKeyring_backoff(t *testing.T) {
+	backing := MustNewInMemory("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK")
+	handler := RemoteHandler(supportlog.DefaultLogger, backing)
+	fail := false
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	k, err := NewRemoteKeyring(server.URL, server.Client(), supportlog.DefaultLogger)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	now := time.Now()
+	k.now = func() time.Time { return now }
+	wantKeys := []Key{{Address: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", Active: true}}
+
+	fail = true
+	now = now.Add(RemoteKeysCacheDuration)
+	keys, err := k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, wantKeys, keys)
+	assert.Equal(t, 2, requests)
+
+	// The keys are not fetched again while backing off.
+	now = now.Add(KeysRetryInterval - time.Nanosecond)
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, wantKeys, keys)
+	assert.Equal(t, 2, requests)
+
+	// The keys are fetched again once the retry interval passes, and the
+	// interval doubles.
+	now = now.Add(time.Nanosecond)
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	now = now.Add(KeysRetryInterval)
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	now = now.Add(KeysRetryInterval)
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 4, requests)
+
+	// Once fetching succeeds the keys are cached for the cache duration.
+	fail = false
+	now = now.Add(4 * KeysRetryInterval)
+	keys, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, wantKeys, keys)
+	assert.Equal(t, 5, requests)
+	now = now.Add(RemoteKeysCacheDuration - time.Nanosecond)
+	_, err = k.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, 5, requests)
+}
+
+// Test that a signature returned by the signing API for another key than
+// the one requested is rejected.
+func TestRemoteKeyring_invalidSignature(t *testing.T) {
+	other := keypair.MustParseFull("SBJGZKZ7LU2FQNEFBUOBW4LHCA5BOZCABIJTR7BQIFWQ3P763ZW7MYDD")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(remoteKeysResponse{Keys: []Key{
+			{Address: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", Active: true},
+		}})
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		req := remoteSignRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		sig, _ := other.Sign(req.Data)
+		json.NewEncoder(w).Encode(remoteSignResponse{Signature: sig})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	k, err := NewRemoteKeyring(server.URL, server.Client(), supportlog.DefaultLogger)
+	require.NoError(t, err)
+	s, err := ActiveSigner(k)
+	require.NoError(t, err)
+	_, err = s.Sign([]byte("data"))
+	assert.Error(t, err)
+}
+
+func TestRemoteKeyring_noActiveKey(t *testing.T) {
+	server := httptest.NewServer(KeysHandler{
+		Logger: supportlog.DefaultLogger,
+		Keys:   Keys{{Address: "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"}},
+	})
+	defer server.Close()
+
+	_, err := NewRemoteKeyring(server.URL, server.Client(), supportlog.DefaultLogger)
+	assert.Equal(t, ErrNoActiveKey, err)
+}
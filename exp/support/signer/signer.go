@@ -0,0 +1,118 @@
+// Package signer provides Stellar signing keys to services through a Signer
+// interface, so that services do not need to hold raw secret seeds and keys
+// can be rotated without redeploying the service.
+//
+// Keys are provided by a Keyring. A keyring contains one or more keys, each of
+// which is either active or inactive. Active keys are used to create new
+// signatures, the first active key being preferred. Inactive keys are still
+// published and accepted, which allows old and new keys to overlap while keys
+// are rotated.
+//
+// The keyrings available are an in-memory keyring created from secret seeds,
+// a keyring backed by an encrypted key file that is reloaded when the file
+// changes, and a keyring backed by a remote signing HTTP API.
+package signer
+
+import (
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// KeysRetryInterval is how long a keyring that failed to reload its keys
+// waits before trying again. The wait doubles with every consecutive failure,
+// up to KeysMaxRetryInterval. The keys last loaded are used in the meantime.
+const KeysRetryInterval = 5 * time.Second
+
+// KeysMaxRetryInterval is the longest a keyring waits before trying to reload
+// its keys again after failures.
+const KeysMaxRetryInterval = time.Minute
+
+// Signer signs data with a Stellar signing key.
+type Signer interface {
+	// Address returns the Stellar address of the signing key.
+	Address() string
+	// Sign returns the ed25519 signature of the data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// Key describes a key of a keyring.
+type Key struct {
+	Address string `json:"address"`
+	Active  bool   `json:"active"`
+}
+
+// KeySource provides the keys of a keyring without being able to sign with
+// them.
+type KeySource interface {
+	// Keys returns the keys of the keyring, active keys first. The keys
+	// returned may change between calls as keys are rotated.
+	Keys() ([]Key, error)
+}
+
+// Keyring provides the keys of a service and signers for them.
+type Keyring interface {
+	KeySource
+	// Signer returns the signer for the key with the address, or ErrNotFound
+	// if the keyring does not contain the key.
+	Signer(address string) (Signer, error)
+}
+
+// ErrNotFound is returned when a keyring does not contain the key requested.
+var ErrNotFound = errors.New("signing key not found")
+
+// ErrNoActiveKey is returned when a keyring contains no active key.
+var ErrNoActiveKey = errors.New("no active signing key")
+
+// Keys is a static list of keys that can be used as a KeySource where only
+// the addresses of keys are required.
+type Keys []Key
+
+// Keys returns the keys.
+func (k Keys) Keys() ([]Key, error) {
+	return k, nil
+}
+
+// ActiveSigner returns the signer for the preferred active key of the keyring.
+func ActiveSigner(k Keyring) (Signer, error) {
+	keys, err := k.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if key.Active {
+			return k.Signer(key.Address)
+		}
+	}
+	return nil, ErrNoActiveKey
+}
+
+// sortKeys orders keys so that active keys are first, otherwise keeping the
+// order of the keys.
+func sortKeys(keys []Key) []Key {
+	sorted := make([]Key, 0, len(keys))
+	for _, k := range keys {
+		if k.Active {
+			sorted = append(sorted, k)
+		}
+	}
+	for _, k := range keys {
+		if !k.Active {
+			sorted = append(sorted, k)
+		}
+	}
+	return sorted
+}
+
+// retryInterval returns how long to wait before reloading keys again after
+// the given number of consecutive failures.
+func retryInterval(failures int) time.Duration {
+	interval := KeysRetryInterval
+	for i := 1; i < failures && interval < KeysMaxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > KeysMaxRetryInterval {
+		interval = KeysMaxRetryInterval
+	}
+	return interval
+}
@@ -0,0 +1,179 @@
+package federation
+
+import (
+	"container/list"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachingDriver wraps a `Driver` and caches the results of its lookups,
+// including lookups that found no record, so that repeated queries for the
+// same address do not each reach the backend. Errors are not cached.
+//
+// Reverse and forward queries are cached if the wrapped driver implements
+// `ReverseDriver` or `ForwardDriver`, otherwise they are answered as not
+// implemented.
+type CachingDriver struct {
+	Driver Driver
+
+	// Size is the maximum number of results of each query type that are
+	// cached, after which the least recently used are evicted. Defaults to
+	// 1000 if zero.
+	Size int
+
+	// TTL is how long results are cached for.
+	TTL time.Duration
+
+	init           sync.Once
+	records        *lruCache
+	reverseRecords *lruCache
+	forwardRecords *lruCache
+}
+
+// LookupRecord implements `Driver` by returning the cached result for the
+// address, or looking it up with the wrapped driver.
+func (drv *CachingDriver) LookupRecord(name, domain string) (*Record, error) {
+	drv.initCache()
+	key := name + "*" + strings.ToLower(domain)
+	if v, ok := drv.records.get(key, time.Now()); ok {
+		return v.(*Record), nil
+	}
+
+	rec, err := drv.Driver.LookupRecord(name, domain)
+	if err != nil {
+		return nil, err
+	}
+	drv.records.add(key, rec, time.Now())
+	return rec, nil
+}
+
+// LookupReverseRecord implements `ReverseDriver` by returning the cached
+// result for the account id, or looking it up with the wrapped driver.
+func (drv *CachingDriver) LookupReverseRecord(accountID string) (*ReverseRecord, error) {
+	rd, ok := drv.Driver.(ReverseDriver)
+	if !ok {
+		return nil, notImplemented("id type queries are not supported")
+	}
+
+	drv.initCache()
+	if v, ok := drv.reverseRecords.get(accountID, time.Now()); ok {
+		return v.(*ReverseRecord), nil
+	}
+
+	rec, err := rd.LookupReverseRecord(accountID)
+	if err != nil {
+		return nil, err
+	}
+	drv.reverseRecords.add(accountID, rec, time.Now())
+	return rec, nil
+}
+
+// LookupForwardingRecord implements `ForwardDriver` by returning the cached
+// result for the query, or looking it up with the wrapped driver.
+func (drv *CachingDriver) LookupForwardingRecord(query url.Values) (*Record, error) {
+	fd, ok := drv.Driver.(ForwardDriver)
+	if !ok {
+		return nil, notImplemented("forward type queries are not supported")
+	}
+
+	drv.initCache()
+	key := query.Encode()
+	if v, ok := drv.forwardRecords.get(key, time.Now()); ok {
+		return v.(*Record), nil
+	}
+
+	rec, err := fd.LookupForwardingRecord(query)
+	if err != nil {
+		return nil, err
+	}
+	drv.forwardRecords.add(key, rec, time.Now())
+	return rec, nil
+}
+
+func (drv *CachingDriver) initCache() {
+	drv.init.Do(func() {
+		size := drv.Size
+		if size == 0 {
+			size = 1000
+		}
+		drv.records = newLRUCache(size, drv.TTL)
+		drv.reverseRecords = newLRUCache(size, drv.TTL)
+		drv.forwardRecords = newLRUCache(size, drv.TTL)
+	})
+}
+
+func notImplemented(message string) ErrorResponse {
+	return ErrorResponse{
+		StatusCode: http.StatusNotImplemented,
+		Code:       "not_implemented",
+		Message:    message,
+	}
+}
+
+var _ Driver = &CachingDriver{}
+var _ ReverseDriver = &CachingDriver{}
+var _ ForwardDriver = &CachingDriver{}
+
+// lruCache is a cache of a limited number of values that expire after a TTL,
+// evicting the least recently used values first when full.
+type lruCache struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func (c *lruCache) get(key string, now time.Time) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		c.ll.Remove(e)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return entry.value, true
+}
+
+func (c *lruCache) add(key string, value interface{}, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = now.Add(c.ttl)
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expiresAt: now.Add(c.ttl)})
+	for c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.items, e.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,114 @@
+package federation
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingDriver struct {
+	lookups        int
+	reverseLookups int
+	err            error
+}
+
+func (d *countingDriver) LookupRecord(name, domain string) (*Record, error) {
+	d.lookups++
+	if d.err != nil {
+		return nil, d.err
+	}
+	if name != "scott" {
+		return nil, nil
+	}
+	return &Record{AccountID: "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"}, nil
+}
+
+type countingReverseDriver struct {
+	countingDriver
+}
+
+func (d *countingReverseDriver) LookupReverseRecord(accountID string) (*ReverseRecord, error) {
+	d.reverseLookups++
+	return &ReverseRecord{Name: "scott", Domain: "stellar.org"}, nil
+}
+
+func TestCachingDriver(t *testing.T) {
+	backend := &countingReverseDriver{}
+	driver := &CachingDriver{Driver: backend, TTL: time.Hour}
+
+	for i := 0; i < 3; i++ {
+		rec, err := driver.LookupRecord("scott", "stellar.org")
+		require.NoError(t, err)
+		assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", rec.AccountID)
+	}
+	assert.Equal(t, 1, backend.lookups)
+
+	// Domains are case insensitive.
+	_, err := driver.LookupRecord("scott", "Stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, 1, backend.lookups)
+
+	// Records that are not found are cached.
+	for i := 0; i < 3; i++ {
+		rec, err := driver.LookupRecord("jed", "stellar.org")
+		require.NoError(t, err)
+		assert.Nil(t, rec)
+	}
+	assert.Equal(t, 2, backend.lookups)
+
+	for i := 0; i < 3; i++ {
+		rrec, err := driver.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+		require.NoError(t, err)
+		assert.Equal(t, &ReverseRecord{Name: "scott", Domain: "stellar.org"}, rrec)
+	}
+	assert.Equal(t, 1, backend.reverseLookups)
+
+	// Forward queries are not supported by the wrapped driver.
+	_, err = driver.LookupForwardingRecord(url.Values{"acct": []string{"1234"}})
+	assert.Equal(t, notImplemented("forward type queries are not supported"), err)
+}
+
+func TestCachingDriver_errorsNotCached(t *testing.T) {
+	backend := &countingDriver{err: errors.New("backend unavailable")}
+	driver := &CachingDriver{Driver: backend, TTL: time.Hour}
+
+	for i := 0; i < 2; i++ {
+		_, err := driver.LookupRecord("scott", "stellar.org")
+		assert.EqualError(t, err, "backend unavailable")
+	}
+	assert.Equal(t, 2, backend.lookups)
+
+	// Reverse queries are not supported by the wrapped driver.
+	_, err := driver.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	assert.Equal(t, notImplemented("id type queries are not supported"), err)
+}
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	c := newLRUCache(2, time.Minute)
+
+	c.add("a", 1, now)
+	c.add("b", 2, now)
+	_, ok := c.get("a", now)
+	assert.True(t, ok)
+
+	// b is the least recently used and is evicted.
+	c.add("c", 3, now)
+	_, ok = c.get("b", now)
+	assert.False(t, ok)
+	v, ok := c.get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = c.get("c", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	// Values expire after the TTL.
+	_, ok = c.get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.ll.Len())
+}
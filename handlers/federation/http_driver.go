@@ -0,0 +1,155 @@
+package federation
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	proto "github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/support/errors"
+)
+
+// HTTPDriver represents an implementation of `Driver` that resolves federation
+// queries by calling an HTTP service that has access to the user database,
+// so that the federation server does not need direct access to it.
+//
+// The service is called with a GET request to `LookupRecordURL` with the
+// `name` and `domain` query parameters and must respond with a JSON object in
+// the same format as a federation response:
+//
+//	{"account_id": "G...", "memo_type": "id", "memo": "123"}
+//
+// A 404 response means no record was found. Other non-200 responses with a
+// JSON body containing `code` and `message` fields are passed on to the
+// client.
+type HTTPDriver struct {
+	// LookupRecordURL is the URL of the service endpoint that performs "forward"
+	// federation queries.
+	LookupRecordURL string
+
+	// HTTP is the client used to call the service. A client with a 10 second
+	// timeout is used if nil.
+	HTTP *http.Client
+}
+
+// ReverseHTTPDriver provides a `ReverseDriver` implementation based upon an
+// HTTP service. See `HTTPDriver`, the forward only version, for more details.
+//
+// The service is called with a GET request to `LookupReverseRecordURL` with
+// the `account_id` query parameter and must respond with a JSON object:
+//
+//	{"name": "scott", "domain": "stellar.org"}
+type ReverseHTTPDriver struct {
+	HTTPDriver
+
+	// LookupReverseRecordURL is the URL of the service endpoint that performs
+	// "reverse" federation queries.
+	LookupReverseRecordURL string
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+type httpReverseRecord struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+}
+
+// LookupRecord implements `Driver` by calling `drv.LookupRecordURL` with the
+// provided name and domain.
+func (drv *HTTPDriver) LookupRecord(name, domain string) (*Record, error) {
+	var resp proto.NameResponse
+	found, err := drv.get(drv.LookupRecordURL, url.Values{
+		"name":   []string{name},
+		"domain": []string{domain},
+	}, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "http get")
+	}
+	if !found {
+		return nil, nil
+	}
+	if resp.AccountID == "" {
+		return nil, errors.New("http get: response has no account_id")
+	}
+
+	return &Record{
+		AccountID: resp.AccountID,
+		MemoType:  resp.MemoType,
+		Memo:      resp.Memo.Value,
+	}, nil
+}
+
+// LookupReverseRecord implements `ReverseDriver` by calling
+// `drv.LookupReverseRecordURL` with the provided account id.
+func (drv *ReverseHTTPDriver) LookupReverseRecord(accountID string) (*ReverseRecord, error) {
+	var resp httpReverseRecord
+	found, err := drv.get(drv.LookupReverseRecordURL, url.Values{
+		"account_id": []string{accountID},
+	}, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "http get")
+	}
+	if !found {
+		return nil, nil
+	}
+	if resp.Name == "" || resp.Domain == "" {
+		return nil, errors.New("http get: response has no name or domain")
+	}
+
+	return &ReverseRecord{
+		Name:   resp.Name,
+		Domain: resp.Domain,
+	}, nil
+}
+
+// get calls the service and decodes the response into dest, returning false
+// if the service responded that the record was not found.
+func (drv *HTTPDriver) get(rawurl string, query url.Values, dest interface{}) (bool, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return false, errors.Wrap(err, "parsing url")
+	}
+	q := u.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	client := drv.HTTP
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// The response body is limited so that a misbehaving service cannot
+	// exhaust memory.
+	body := io.LimitReader(resp.Body, 1<<20)
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		err = json.NewDecoder(body).Decode(dest)
+		if err != nil {
+			return false, errors.Wrap(err, "decoding response")
+		}
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		errResp := ErrorResponse{StatusCode: resp.StatusCode}
+		b, _ := ioutil.ReadAll(body)
+		if json.Unmarshal(b, &errResp) == nil && errResp.Code != "" {
+			return false, errResp
+		}
+	}
+	return false, errors.Errorf("service responded with status %d", resp.StatusCode)
+}
+
+var _ Driver = &HTTPDriver{}
+var _ ReverseDriver = &ReverseHTTPDriver{}
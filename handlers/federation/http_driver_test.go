@@ -0,0 +1,103 @@
+package federation
+
+import (
+	"fmt"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPDriver(t *testing.T) {
+	backend := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/lookup":
+			assert.Equal(t, "secret", q.Get("token"))
+			switch q.Get("name") + "*" + q.Get("domain") {
+			case "scott*stellar.org":
+				fmt.Fprintln(w, `{"account_id":"GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"}`)
+			case "bartek*stellar.org":
+				fmt.Fprintln(w, `{"account_id":"GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3","memo_type":"id","memo":123}`)
+			case "blocked*stellar.org":
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprintln(w, `{"code":"forbidden","message":"Account is blocked"}`)
+			case "broken*stellar.org":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "/reverse":
+			switch q.Get("account_id") {
+			case "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG":
+				fmt.Fprintln(w, `{"name":"scott","domain":"stellar.org"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer backend.Close()
+
+	driver := &ReverseHTTPDriver{
+		HTTPDriver: HTTPDriver{
+			LookupRecordURL: backend.URL + "/lookup?token=secret",
+		},
+		LookupReverseRecordURL: backend.URL + "/reverse",
+	}
+
+	rec, err := driver.LookupRecord("scott", "stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, &Record{AccountID: "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"}, rec)
+
+	rec, err = driver.LookupRecord("bartek", "stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, &Record{
+		AccountID: "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3",
+		MemoType:  "id",
+		Memo:      "123",
+	}, rec)
+
+	rec, err = driver.LookupRecord("jed", "stellar.org")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	_, err = driver.LookupRecord("blocked", "stellar.org")
+	assert.Equal(t, ErrorResponse{StatusCode: http.StatusForbidden, Code: "forbidden", Message: "Account is blocked"}, errors.Cause(err))
+
+	_, err = driver.LookupRecord("broken", "stellar.org")
+	assert.EqualError(t, err, "http get: service responded with status 500")
+
+	rrec, err := driver.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	require.NoError(t, err)
+	assert.Equal(t, &ReverseRecord{Name: "scott", Domain: "stellar.org"}, rrec)
+
+	rrec, err = driver.LookupReverseRecord("GA3R753JKGXU6ETHNY3U6PYIY7D6UUCXXDYBRF4XURNAGXW3CVGQH2ZA")
+	require.NoError(t, err)
+	assert.Nil(t, rrec)
+
+	// The errors of the service are passed on to the client.
+	server := httptest.NewServer(t, &Handler{Driver: driver})
+	defer server.Close()
+
+	server.GET("/federation").
+		WithQuery("type", "name").
+		WithQuery("q", "blocked*stellar.org").
+		Expect().
+		Status(http.StatusForbidden).
+		JSON().Object().
+		ValueEqual("code", "forbidden")
+
+	server.GET("/federation").
+		WithQuery("type", "id").
+		WithQuery("q", "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		ValueEqual("stellar_address", "scott*stellar.org")
+}
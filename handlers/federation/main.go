@@ -8,6 +8,10 @@
 //
 // A pre-baked implementation of `Driver` and `ReverseDriver` that provides
 // simple access to SQL systems is included. See `SQLDriver` for more details.
+// Another that calls an HTTP service is also included, see `HTTPDriver`.
+//
+// Drivers can be wrapped to cache their results, see `CachingDriver`, and to
+// return records with id memos as muxed accounts, see `MuxedAccountDriver`.
 package federation
 
 import (
@@ -0,0 +1,81 @@
+package federation
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/stellar/go/internal/muxedaccount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// MuxedAccountDriver wraps a `Driver` and returns the records it finds that
+// have an id memo as a muxed account (an M... address, see SEP-23) without a
+// memo. This allows custodial services that identify their users by id memos
+// to be paid by wallets without the sender having to set a memo.
+//
+// Reverse and forward queries are supported if the wrapped driver implements
+// `ReverseDriver` or `ForwardDriver`, otherwise they are answered as not
+// implemented.
+type MuxedAccountDriver struct {
+	Driver Driver
+}
+
+// LookupRecord implements `Driver` by looking up the record with the wrapped
+// driver and converting it to a muxed account.
+func (drv *MuxedAccountDriver) LookupRecord(name, domain string) (*Record, error) {
+	rec, err := drv.Driver.LookupRecord(name, domain)
+	if err != nil || rec == nil {
+		return rec, err
+	}
+	return muxedRecord(rec)
+}
+
+// LookupReverseRecord implements `ReverseDriver` by looking up the record
+// with the wrapped driver.
+func (drv *MuxedAccountDriver) LookupReverseRecord(accountID string) (*ReverseRecord, error) {
+	rd, ok := drv.Driver.(ReverseDriver)
+	if !ok {
+		return nil, notImplemented("id type queries are not supported")
+	}
+	return rd.LookupReverseRecord(accountID)
+}
+
+// LookupForwardingRecord implements `ForwardDriver` by looking up the record
+// with the wrapped driver and converting it to a muxed account.
+func (drv *MuxedAccountDriver) LookupForwardingRecord(query url.Values) (*Record, error) {
+	fd, ok := drv.Driver.(ForwardDriver)
+	if !ok {
+		return nil, notImplemented("forward type queries are not supported")
+	}
+	rec, err := fd.LookupForwardingRecord(query)
+	if err != nil || rec == nil {
+		return rec, err
+	}
+	return muxedRecord(rec)
+}
+
+// muxedRecord returns a copy of the record with the account id and id memo
+// combined into a muxed account. Records without an id memo are returned
+// unchanged.
+func muxedRecord(rec *Record) (*Record, error) {
+	if rec.MemoType != "id" {
+		return rec, nil
+	}
+	id, err := strconv.ParseUint(rec.Memo, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing id memo")
+	}
+	key, err := strkey.Decode(strkey.VersionByteAccountID, rec.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding account id")
+	}
+	med := xdr.MuxedAccountMed25519{Id: xdr.Uint64(id)}
+	copy(med.Ed25519[:], key)
+	return &Record{AccountID: muxedaccount.Encode(med)}, nil
+}
+
+var _ Driver = &MuxedAccountDriver{}
+var _ ReverseDriver = &MuxedAccountDriver{}
+var _ ForwardDriver = &MuxedAccountDriver{}
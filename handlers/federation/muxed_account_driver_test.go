@@ -0,0 +1,72 @@
+package federation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuxedAccountDriver(t *testing.T) {
+	driver := &MuxedAccountDriver{Driver: ForwardTestDriver{}}
+	server := httptest.NewServer(t, &Handler{Driver: driver})
+	defer server.Close()
+
+	// Records with an id memo are returned as a muxed account.
+	server.GET("/federation").
+		WithQuery("type", "forward").
+		WithQuery("forward_type", "bank_account").
+		WithQuery("acct", "1234").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		ValueEqual("account_id", "MD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMEAAAAAAAAAAAAF5EG").
+		NotContainsKey("memo_type")
+
+	// Records with other memos are returned unchanged.
+	server.GET("/federation").
+		WithQuery("type", "forward").
+		WithQuery("forward_type", "bank_account").
+		WithQuery("acct", "4321").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		ValueEqual("account_id", "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG").
+		ValueEqual("memo_type", "text").
+		ValueEqual("memo", "test")
+
+	// Reverse queries are not supported by the wrapped driver.
+	server.GET("/federation").
+		WithQuery("type", "id").
+		WithQuery("q", "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG").
+		Expect().
+		Status(http.StatusNotImplemented).
+		JSON().Object().
+		ValueEqual("code", "not_implemented")
+}
+
+func TestMuxedRecord(t *testing.T) {
+	rec, err := muxedRecord(&Record{
+		AccountID: "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ",
+		MemoType:  "id",
+		Memo:      "9223372036854775808",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Record{AccountID: "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"}, rec)
+
+	_, err = muxedRecord(&Record{
+		AccountID: "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ",
+		MemoType:  "id",
+		Memo:      "not a number",
+	})
+	assert.Error(t, err)
+
+	_, err = muxedRecord(&Record{
+		AccountID: "not an account id",
+		MemoType:  "id",
+		Memo:      "1",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Package muxedaccount encodes and decodes the M... addresses of muxed
+// accounts, see SEP-23. The strkey package does not encode or decode muxed
+// accounts until SEP-23 leaves the Draft status, so the packages of this
+// repository that need to do so in the meantime share this package instead.
+package muxedaccount
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"strings"
+
+	"github.com/stellar/go/crc16"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// versionByte is the strkey version byte of muxed accounts.
+const versionByte = 12 << 3
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Encode returns the M... address of the muxed account.
+func Encode(med xdr.MuxedAccountMed25519) string {
+	var raw bytes.Buffer
+	raw.WriteByte(versionByte)
+	raw.Write(med.Ed25519[:])
+	binary.Write(&raw, binary.BigEndian, uint64(med.Id))
+	raw.Write(crc16.Checksum(raw.Bytes()))
+	return encoding.EncodeToString(raw.Bytes())
+}
+
+// Decode decodes the M... address of a muxed account. Addresses that are not
+// canonically encoded are rejected.
+func Decode(address string) (xdr.MuxedAccountMed25519, error) {
+	raw, err := encoding.DecodeString(address)
+	if err != nil {
+		return xdr.MuxedAccountMed25519{}, errors.Wrap(err, "decoding muxed account")
+	}
+	if len(raw) != 43 || raw[0] != versionByte {
+		return xdr.MuxedAccountMed25519{}, errors.New("invalid muxed account")
+	}
+	err = crc16.Validate(raw[:41], raw[41:])
+	if err != nil {
+		return xdr.MuxedAccountMed25519{}, errors.Wrap(err, "invalid muxed account")
+	}
+	if encoding.EncodeToString(raw) != address {
+		return xdr.MuxedAccountMed25519{}, errors.New("invalid muxed account")
+	}
+
+	var med xdr.MuxedAccountMed25519
+	copy(med.Ed25519[:], raw[1:33])
+	med.Id = xdr.Uint64(binary.BigEndian.Uint64(raw[33:41]))
+	return med, nil
+}
+
+// AccountID returns the account ID (G...) of the address, which is either an
+// account ID, returned unchanged, or the M... address of a muxed account.
+func AccountID(address string) (string, error) {
+	if !strings.HasPrefix(address, "M") {
+		return address, nil
+	}
+	med, err := Decode(address)
+	if err != nil {
+		return "", err
+	}
+	return strkey.Encode(strkey.VersionByteAccountID, med.Ed25519[:])
+}
@@ -271,6 +271,62 @@ func (p Path) PagingToken() string {
 	return ""
 }
 
+// SplitPath is a payment divided across several payment paths which are meant
+// to be executed, in order, as operations of a single transaction. The amounts
+// of each path take into account the offers consumed by the previous ones.
+type SplitPath struct {
+	SourceAssetType        string `json:"source_asset_type"`
+	SourceAssetCode        string `json:"source_asset_code,omitempty"`
+	SourceAssetIssuer      string `json:"source_asset_issuer,omitempty"`
+	SourceAmount           string `json:"source_amount"`
+	DestinationAssetType   string `json:"destination_asset_type"`
+	DestinationAssetCode   string `json:"destination_asset_code,omitempty"`
+	DestinationAssetIssuer string `json:"destination_asset_issuer,omitempty"`
+	DestinationAmount      string `json:"destination_amount"`
+	Paths                  []Path `json:"paths"`
+}
+
+// stub implementation to satisfy pageable interface
+func (p SplitPath) PagingToken() string {
+	return ""
+}
+
+// Quote is the outcome of selling an amount of an asset for another asset
+// through the best payment path in the order book. Prices are expressed in
+// units of the destination asset per unit of the source asset. MidPrice and
+// PriceImpact are omitted when a hop of the path has no asks.
+type Quote struct {
+	SourceAssetType        string        `json:"source_asset_type"`
+	SourceAssetCode        string        `json:"source_asset_code,omitempty"`
+	SourceAssetIssuer      string        `json:"source_asset_issuer,omitempty"`
+	SourceAmount           string        `json:"source_amount"`
+	DestinationAssetType   string        `json:"destination_asset_type"`
+	DestinationAssetCode   string        `json:"destination_asset_code,omitempty"`
+	DestinationAssetIssuer string        `json:"destination_asset_issuer,omitempty"`
+	DestinationAmount      string        `json:"destination_amount"`
+	Path                   []Asset       `json:"path"`
+	BestPrice              string        `json:"best_price"`
+	AveragePrice           string        `json:"average_price"`
+	WorstPrice             string        `json:"worst_price"`
+	MidPrice               string        `json:"mid_price,omitempty"`
+	PriceImpact            string        `json:"price_impact,omitempty"`
+	Slippage               string        `json:"slippage"`
+	Offers                 []QuotedOffer `json:"offers"`
+}
+
+// QuotedOffer is an offer crossed by a quote. Sold is the amount of the selling
+// asset sold by the offer and Bought the amount of the buying asset it bought.
+type QuotedOffer struct {
+	ID      int64  `json:"id,string"`
+	Seller  string `json:"seller"`
+	Selling Asset  `json:"selling"`
+	Buying  Asset  `json:"buying"`
+	PriceR  Price  `json:"price_r"`
+	Price   string `json:"price"`
+	Sold    string `json:"sold"`
+	Bought  string `json:"bought"`
+}
+
 // Price represents a price
 type Price base.Price
 
@@ -655,6 +711,14 @@ type PathsPage struct {
 	} `json:"_embedded"`
 }
 
+// SplitPathsPage contains records of split payment paths found by horizon
+type SplitPathsPage struct {
+	Links    hal.Links `json:"_links"`
+	Embedded struct {
+		Records []SplitPath
+	} `json:"_embedded"`
+}
+
 // ClaimableBalance represents a claimable balance
 type ClaimableBalance struct {
 	Links struct {
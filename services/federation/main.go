@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	stdhttp "net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/spf13/cobra"
@@ -17,16 +19,32 @@ import (
 
 // Config represents the configuration of a federation server
 type Config struct {
-	Port     int `valid:"required"`
+	Port int `valid:"required"`
+	// Driver is the backend federation queries are resolved with, either "sql"
+	// (the default) or "http".
+	Driver   string `valid:"optional"`
 	Database struct {
-		Type string `valid:"matches(^sqlite3|postgres$)"`
-		DSN  string `valid:"required"`
-	} `valid:"required"`
+		Type string `valid:"optional,matches(^sqlite3|postgres$)"`
+		DSN  string `valid:"optional"`
+	} `valid:"optional"`
 	Queries struct {
-		Federation        string `valid:"required"`
+		Federation        string `valid:"optional"`
 		ReverseFederation string `toml:"reverse-federation" valid:"optional"`
-	} `valid:"required"`
-	TLS *config.TLS `valid:"optional"`
+	} `valid:"optional"`
+	HTTP struct {
+		FederationURL        string `toml:"federation-url" valid:"optional"`
+		ReverseFederationURL string `toml:"reverse-federation-url" valid:"optional"`
+		// Timeout is the timeout of requests to the backend in seconds.
+		Timeout int `valid:"optional"`
+	} `toml:"http" valid:"optional"`
+	Cache struct {
+		Size int `valid:"optional"`
+		// TTL is how long results are cached for in seconds. Caching is
+		// disabled if zero.
+		TTL int `toml:"ttl" valid:"optional"`
+	} `valid:"optional"`
+	MuxedAccounts bool        `toml:"muxed-accounts" valid:"optional"`
+	TLS           *config.TLS `valid:"optional"`
 }
 
 func main() {
@@ -64,6 +82,12 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	err = checkConfig(cfg)
+	if err != nil {
+		log.Error("config file: ", err)
+		os.Exit(1)
+	}
+
 	driver, err := initDriver(cfg)
 	if err != nil {
 		log.Error(err)
@@ -84,7 +108,82 @@ func run(cmd *cobra.Command, args []string) {
 	})
 }
 
+// checkConfig checks that the config contains the fields required by the
+// driver it selects.
+func checkConfig(cfg Config) error {
+	switch cfg.Driver {
+	case "", "sql":
+		if cfg.Database.DSN == "" {
+			return errors.New("database.dsn is required")
+		}
+		if cfg.Queries.Federation == "" {
+			return errors.New("queries.federation is required")
+		}
+	case "http":
+		if cfg.HTTP.FederationURL == "" {
+			return errors.New("http.federation-url is required")
+		}
+	}
+	return nil
+}
+
 func initDriver(cfg Config) (federation.Driver, error) {
+	var (
+		driver federation.Driver
+		err    error
+	)
+
+	switch cfg.Driver {
+	case "", "sql":
+		driver, err = initSQLDriver(cfg)
+	case "http":
+		driver, err = initHTTPDriver(cfg)
+	default:
+		return nil, errors.Errorf("Invalid driver: %s", cfg.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MuxedAccounts {
+		driver = &federation.MuxedAccountDriver{Driver: driver}
+	}
+
+	if cfg.Cache.TTL > 0 {
+		driver = &federation.CachingDriver{
+			Driver: driver,
+			Size:   cfg.Cache.Size,
+			TTL:    time.Duration(cfg.Cache.TTL) * time.Second,
+		}
+	}
+
+	return driver, nil
+}
+
+func initHTTPDriver(cfg Config) (federation.Driver, error) {
+	timeout := 10 * time.Second
+	if cfg.HTTP.Timeout > 0 {
+		timeout = time.Duration(cfg.HTTP.Timeout) * time.Second
+	}
+
+	httpd := federation.HTTPDriver{
+		LookupRecordURL: cfg.HTTP.FederationURL,
+		HTTP:            &stdhttp.Client{Timeout: timeout},
+	}
+
+	if cfg.HTTP.ReverseFederationURL == "" {
+		return &httpd, nil
+	}
+
+	rhttpd := federation.ReverseHTTPDriver{
+		HTTPDriver:             httpd,
+		LookupReverseRecordURL: cfg.HTTP.ReverseFederationURL,
+	}
+
+	return &rhttpd, nil
+}
+
+func initSQLDriver(cfg Config) (federation.Driver, error) {
 	var dialect string
 
 	switch cfg.Database.Type {
@@ -2,7 +2,9 @@ package main
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stellar/go/handlers/federation"
 	"github.com/stellar/go/support/db/dbtest"
 	"github.com/stellar/go/support/errors"
 	"github.com/stretchr/testify/assert"
@@ -37,3 +39,45 @@ func TestInitDriver_dialect(t *testing.T) {
 		})
 	}
 }
+
+func TestInitDriver_http(t *testing.T) {
+	c := Config{Driver: "http"}
+	c.HTTP.FederationURL = "http://localhost:8001/federation"
+
+	driver, err := initDriver(c)
+	require.NoError(t, err)
+	assert.IsType(t, &federation.HTTPDriver{}, driver)
+
+	c.HTTP.ReverseFederationURL = "http://localhost:8001/reverse-federation"
+	driver, err = initDriver(c)
+	require.NoError(t, err)
+	assert.IsType(t, &federation.ReverseHTTPDriver{}, driver)
+
+	c.MuxedAccounts = true
+	c.Cache.TTL = 60
+	driver, err = initDriver(c)
+	require.NoError(t, err)
+	require.IsType(t, &federation.CachingDriver{}, driver)
+	cd := driver.(*federation.CachingDriver)
+	assert.Equal(t, time.Minute, cd.TTL)
+	require.IsType(t, &federation.MuxedAccountDriver{}, cd.Driver)
+	assert.IsType(t, &federation.ReverseHTTPDriver{}, cd.Driver.(*federation.MuxedAccountDriver).Driver)
+
+	c.Driver = "bogus"
+	_, err = initDriver(c)
+	assert.EqualError(t, err, "Invalid driver: bogus")
+}
+
+func TestCheckConfig(t *testing.T) {
+	c := Config{}
+	assert.EqualError(t, checkConfig(c), "database.dsn is required")
+	c.Database.DSN = "postgres://localhost/federation"
+	assert.EqualError(t, checkConfig(c), "queries.federation is required")
+	c.Queries.Federation = "SELECT id FROM people WHERE name = ? AND domain = ?"
+	assert.NoError(t, checkConfig(c))
+
+	c = Config{Driver: "http"}
+	assert.EqualError(t, checkConfig(c), "http.federation-url is required")
+	c.HTTP.FederationURL = "http://localhost:8001/federation"
+	assert.NoError(t, checkConfig(c))
+}
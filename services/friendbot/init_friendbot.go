@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/services/friendbot/internal"
@@ -20,6 +22,10 @@ func initFriendbot(
 	startingBalance string,
 	numMinions int,
 	baseFee int64,
+	assetCode string,
+	assetIssuer string,
+	assetAmount string,
+	batching *Batching,
 ) (*internal.Bot, error) {
 	if friendbotSecret == "" || networkPassphrase == "" || horizonURL == "" || startingBalance == "" || numMinions < 0 {
 		return nil, errors.New("invalid input param(s)")
@@ -43,20 +49,88 @@ func initFriendbot(
 	// already confirmed that friendbotSecret is a seed.
 	botKeypair := botKP.(*keypair.Full)
 	botAccount := internal.Account{AccountID: botKeypair.Address()}
+	asset, err := initAsset(assetCode, assetIssuer, assetAmount, botKeypair.Address())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid asset")
+	}
 	minionBalance := "101.00"
 	if numMinions == 0 {
 		numMinions = 1000
 	}
 	log.Printf("Found all valid params, now creating %d minions", numMinions)
-	minions, err := createMinionAccounts(botAccount, botKeypair, networkPassphrase, startingBalance, minionBalance, numMinions, baseFee, hclient)
+	minions, err := createMinionAccounts(botAccount, botKeypair, networkPassphrase, startingBalance, minionBalance, numMinions, baseFee, asset, assetAmount, hclient)
 	if err != nil && len(minions) == 0 {
 		return nil, errors.Wrap(err, "creating minion accounts")
 	}
 	log.Printf("Adding %d minions to friendbot", len(minions))
-	return &internal.Bot{Minions: minions}, nil
+	bot := &internal.Bot{Minions: minions}
+	if batching != nil {
+		bot.Pool, err = initMinionPool(*batching, minions, func(n int) ([]internal.Minion, error) {
+			return createMinionAccounts(botAccount, botKeypair, networkPassphrase, startingBalance, minionBalance, n, baseFee, asset, assetAmount, hclient)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bot, nil
+}
+
+// initMinionPool returns the pool of minions of the batching mode, which
+// creates new minions with createMinions.
+func initMinionPool(cfg Batching, minions []internal.Minion, createMinions func(n int) ([]internal.Minion, error)) (*internal.MinionPool, error) {
+	if cfg.BatchSize < 0 || cfg.BatchSize > internal.MaxBatchSize || cfg.BatchInterval < 0 || cfg.MinMinions < 0 || cfg.MaxMinions < 0 || cfg.IdleTimeout < 0 {
+		return nil, errors.New("invalid batching param(s)")
+	}
+	if cfg.MaxMinions != 0 && cfg.MaxMinions < cfg.MinMinions {
+		return nil, errors.New("invalid batching param(s): max_minions is less than min_minions")
+	}
+
+	pool := &internal.MinionPool{
+		Minions:         minions,
+		BatchSize:       cfg.BatchSize,
+		BatchInterval:   time.Duration(cfg.BatchInterval * float64(time.Second)),
+		MinMinions:      cfg.MinMinions,
+		MaxMinions:      cfg.MaxMinions,
+		IdleTimeout:     time.Duration(cfg.IdleTimeout) * time.Second,
+		RefillThreshold: cfg.RefillThreshold,
+		RefillAmount:    cfg.RefillAmount,
+		CreateMinions:   createMinions,
+	}
+	if pool.RefillThreshold == "" {
+		pool.RefillThreshold = "10"
+	}
+	if pool.RefillAmount == "" {
+		pool.RefillAmount = "100"
+	}
+	for _, a := range []string{pool.RefillThreshold, pool.RefillAmount} {
+		if _, err := amount.Parse(a); err != nil {
+			return nil, errors.Wrap(err, "invalid batching param(s)")
+		}
+	}
+	return pool, nil
+}
+
+// initAsset returns the asset destinations are funded with, or nil if no
+// asset is configured. The asset is issued by the bot account if no issuer is
+// configured.
+func initAsset(assetCode, assetIssuer, assetAmount, botAddress string) (txnbuild.Asset, error) {
+	if assetCode == "" {
+		return nil, nil
+	}
+	if assetAmount == "" {
+		return nil, errors.New("asset_amount is required with asset_code")
+	}
+	if assetIssuer == "" {
+		assetIssuer = botAddress
+	}
+	asset := txnbuild.CreditAsset{Code: assetCode, Issuer: assetIssuer}
+	if _, err := asset.ToXDR(); err != nil {
+		return nil, err
+	}
+	return asset, nil
 }
 
-func createMinionAccounts(botAccount internal.Account, botKeypair *keypair.Full, networkPassphrase, newAccountBalance, minionBalance string, numMinions int, baseFee int64, hclient *horizonclient.Client) ([]internal.Minion, error) {
+func createMinionAccounts(botAccount internal.Account, botKeypair *keypair.Full, networkPassphrase, newAccountBalance, minionBalance string, numMinions int, baseFee int64, asset txnbuild.Asset, assetAmount string, hclient *horizonclient.Client) ([]internal.Minion, error) {
 	var minions []internal.Minion
 	numRemainingMinions := numMinions
 	minionBatchSize := 100
@@ -91,7 +165,10 @@ func createMinionAccounts(botAccount internal.Account, botKeypair *keypair.Full,
 				StartingBalance:      newAccountBalance,
 				SubmitTransaction:    internal.SubmitTransaction,
 				CheckSequenceRefresh: internal.CheckSequenceRefresh,
+				CheckBalance:         internal.CheckBalance,
 				BaseFee:              baseFee,
+				Asset:                asset,
+				AssetAmount:          assetAmount,
 			})
 
 			ops = append(ops, &txnbuild.CreateAccount{
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stellar/go/services/friendbot/internal"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+func initRateLimiter(cfg RateLimit) (*internal.RateLimiter, error) {
+	if cfg.Window < 0 || cfg.MaxPerIP < 0 || cfg.MaxPerDestination < 0 {
+		return nil, errors.New("invalid rate limit param(s)")
+	}
+
+	window := 24 * time.Hour
+	if cfg.Window != 0 {
+		window = time.Duration(cfg.Window) * time.Second
+	}
+
+	limiter := &internal.RateLimiter{
+		Store:             &internal.MemoryRateLimitStore{},
+		Window:            window,
+		MaxPerIP:          cfg.MaxPerIP,
+		MaxPerDestination: cfg.MaxPerDestination,
+	}
+	if cfg.DatabaseURL == "" {
+		return limiter, nil
+	}
+
+	session, err := db.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening rate limit database")
+	}
+	store, err := internal.NewDBRateLimitStore(session)
+	if err != nil {
+		return nil, err
+	}
+	limiter.Store = store
+
+	// Delete the requests that are no longer counted.
+	go func() {
+		for range time.Tick(time.Hour) {
+			err := store.DeleteOld(time.Now().Add(-window))
+			if err != nil {
+				log.Error(errors.Wrap(err, "deleting old rate limit requests"))
+			}
+		}
+	}()
+
+	return limiter, nil
+}
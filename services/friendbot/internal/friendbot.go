@@ -8,9 +8,10 @@ import (
 )
 
 // Bot represents the friendbot subsystem and primarily delegates work
-// to its Minions.
+// to its Minions, or to its Pool of minions in the batching mode.
 type Bot struct {
 	Minions         []Minion
+	Pool            *MinionPool
 	nextMinionIndex int
 	indexMux        sync.Mutex
 }
@@ -21,15 +22,19 @@ type SubmitResult struct {
 	maybeErr                error
 }
 
-// Pay funds the account at `destAddress`.
-func (bot *Bot) Pay(destAddress string) (*hProtocol.Transaction, error) {
+// Pay funds the account at `destAddress` with the funding mode.
+func (bot *Bot) Pay(destAddress string, mode FundingMode) (*hProtocol.Transaction, error) {
+	if bot.Pool != nil {
+		return bot.Pool.Pay(destAddress, mode)
+	}
+
 	bot.indexMux.Lock()
 	log.Printf("Selecting minion at index %d of max length %d", bot.nextMinionIndex, len(bot.Minions))
 	minion := bot.Minions[bot.nextMinionIndex]
 	bot.nextMinionIndex = (bot.nextMinionIndex + 1) % len(bot.Minions)
 	bot.indexMux.Unlock()
 	resultChan := make(chan SubmitResult)
-	go minion.Run(destAddress, resultChan)
+	go minion.Run(destAddress, mode, resultChan)
 	maybeSubmitResult := <-resultChan
 	close(resultChan)
 	return maybeSubmitResult.maybeTransactionSuccess, maybeSubmitResult.maybeErr
@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/strkey"
@@ -10,17 +12,31 @@ import (
 	"github.com/stellar/go/support/render/problem"
 )
 
-// FriendbotHandler causes an account at `Address` to be created.
+// FriendbotHandler causes an account at `Address` to be created, or funded
+// with the funding mode of the `mode` parameter.
 type FriendbotHandler struct {
 	Friendbot *Bot
+
+	// RateLimiter limits how often callers and destinations are funded, or is
+	// nil if they are not limited.
+	RateLimiter *RateLimiter
+	// TrustForwardedFor identifies callers by the last address of the
+	// X-Forwarded-For header, when friendbot is behind a proxy such as
+	// horizon. The last address is the one added by the proxy, the others are
+	// set by the caller and cannot be trusted.
+	TrustForwardedFor bool
+}
+
+// RateLimitedProblem is the problem rendered for ErrRateLimited.
+var RateLimitedProblem = problem.P{
+	Type:   "rate_limit_exceeded",
+	Title:  "Rate Limit Exceeded",
+	Status: http.StatusTooManyRequests,
+	Detail: "Friendbot has funded this address or served requests from your IP address too many times recently. Try again later.",
 }
 
 // Handle is a method that implements http.HandlerFunc
 func (handler *FriendbotHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	accountExistsProblem := problem.BadRequest
-	accountExistsProblem.Detail = ErrAccountExists.Error()
-	problem.RegisterError(ErrAccountExists, accountExistsProblem)
-
 	result, err := handler.doHandle(r)
 	if err != nil {
 		problem.Render(r.Context(), w, err)
@@ -48,7 +64,20 @@ func (handler *FriendbotHandler) doHandle(r *http.Request) (*horizon.Transaction
 	if err != nil {
 		return nil, problem.MakeInvalidFieldProblem("addr", err)
 	}
-	return handler.Friendbot.Pay(address)
+
+	mode, err := ParseFundingMode(r.Form.Get("mode"))
+	if err != nil {
+		return nil, problem.MakeInvalidFieldProblem("mode", err)
+	}
+
+	if handler.RateLimiter != nil {
+		err = handler.RateLimiter.Allow(handler.clientIP(r), address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return handler.Friendbot.Pay(address, mode)
 }
 
 func (handler *FriendbotHandler) checkEnabled() error {
@@ -74,3 +103,18 @@ func (handler *FriendbotHandler) loadAddress(r *http.Request) (string, error) {
 	_, err = strkey.Decode(strkey.VersionByteAccountID, unescaped)
 	return unescaped, err
 }
+
+// clientIP returns the IP address of the caller.
+func (handler *FriendbotHandler) clientIP(r *http.Request) string {
+	if handler.TrustForwardedFor {
+		addresses := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if ip := strings.TrimSpace(addresses[len(addresses)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -51,7 +51,7 @@ func TestFriendbot_Pay(t *testing.T) {
 	fb := &Bot{Minions: []Minion{minion}}
 
 	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
-	txSuccess, err := fb.Pay(recipientAddress)
+	txSuccess, err := fb.Pay(recipientAddress, FundingModeCreateAccount)
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -62,12 +62,12 @@ func TestFriendbot_Pay(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
-		_, err := fb.Pay(recipientAddress)
+		_, err := fb.Pay(recipientAddress, FundingModeCreateAccount)
 		assert.NoError(t, err)
 		wg.Done()
 	}()
 	go func() {
-		_, err := fb.Pay(recipientAddress)
+		_, err := fb.Pay(recipientAddress, FundingModeCreateAccount)
 		assert.NoError(t, err)
 		wg.Done()
 	}()
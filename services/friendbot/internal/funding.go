@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// FundingMode is how friendbot funds a destination address.
+type FundingMode string
+
+const (
+	// FundingModeCreateAccount creates the destination account with the
+	// starting balance of XLM. It is the default mode.
+	FundingModeCreateAccount FundingMode = "create_account"
+	// FundingModeAsset pays the asset amount of the configured asset to an
+	// existing destination account, which must trust the asset.
+	FundingModeAsset FundingMode = "asset"
+	// FundingModeClaimableBalance creates a claimable balance of the asset
+	// amount of the configured asset, or of the starting balance of XLM if no
+	// asset is configured, that the destination can claim. The destination
+	// account does not need to exist yet.
+	FundingModeClaimableBalance FundingMode = "claimable_balance"
+)
+
+// ErrAssetNotConfigured is returned when funding a destination with the
+// asset mode while friendbot is not configured with an asset.
+var ErrAssetNotConfigured = errors.New("friendbot is not configured with an asset")
+
+// ErrAccountNotFound is returned when paying the asset to a destination
+// account that does not exist.
+var ErrAccountNotFound = errors.New("destination account does not exist, fund it with the create_account or claimable_balance mode")
+
+// ErrNoTrustline is returned when paying the asset to a destination account
+// that does not trust the asset.
+var ErrNoTrustline = errors.New("destination account does not trust the asset")
+
+// ParseFundingMode parses a funding mode. The empty string is the create
+// account mode.
+func ParseFundingMode(s string) (FundingMode, error) {
+	switch mode := FundingMode(s); mode {
+	case "":
+		return FundingModeCreateAccount, nil
+	case FundingModeCreateAccount, FundingModeAsset, FundingModeClaimableBalance:
+		return mode, nil
+	default:
+		return "", errors.Errorf("unknown funding mode %q, must be one of %s, %s or %s", s, FundingModeCreateAccount, FundingModeAsset, FundingModeClaimableBalance)
+	}
+}
+
+// fundingOp returns the operation that funds the destination address with
+// the mode. Its source account is the bot account.
+func (minion *Minion) fundingOp(destAddress string, mode FundingMode) (txnbuild.Operation, error) {
+	switch mode {
+	case FundingModeCreateAccount:
+		return &txnbuild.CreateAccount{
+			Destination:   destAddress,
+			SourceAccount: minion.BotAccount,
+			Amount:        minion.StartingBalance,
+		}, nil
+	case FundingModeAsset:
+		if minion.Asset == nil {
+			return nil, ErrAssetNotConfigured
+		}
+		return &txnbuild.Payment{
+			Destination:   destAddress,
+			SourceAccount: minion.BotAccount,
+			Asset:         minion.Asset,
+			Amount:        minion.AssetAmount,
+		}, nil
+	case FundingModeClaimableBalance:
+		var asset txnbuild.Asset = txnbuild.NativeAsset{}
+		amount := minion.StartingBalance
+		if minion.Asset != nil {
+			asset = minion.Asset
+			amount = minion.AssetAmount
+		}
+		return &txnbuild.CreateClaimableBalance{
+			Destinations:  []txnbuild.Claimant{txnbuild.NewClaimant(destAddress, nil)},
+			SourceAccount: minion.BotAccount,
+			Asset:         asset,
+			Amount:        amount,
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown funding mode %q", mode)
+	}
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMinion(t *testing.T) *Minion {
+	botKeypair, err := keypair.Parse("SCWNLYELENPBXN46FHYXETT5LJCYBZD5VUQQVW4KZPHFO2YTQJUWT4D5")
+	require.NoError(t, err)
+	minionKeypair, err := keypair.Parse("SDTNSEERJPJFUE2LSDNYBFHYGVTPIWY7TU2IOJZQQGLWO2THTGB7NU5A")
+	require.NoError(t, err)
+
+	return &Minion{
+		Account: Account{
+			AccountID: minionKeypair.Address(),
+			Sequence:  1,
+		},
+		Keypair:         minionKeypair.(*keypair.Full),
+		BotAccount:      Account{AccountID: botKeypair.Address()},
+		BotKeypair:      botKeypair.(*keypair.Full),
+		Network:         "Test SDF Network ; September 2015",
+		StartingBalance: "10000.00",
+		SubmitTransaction: func(minion *Minion, hclient *horizonclient.Client, tx string) (*hProtocol.Transaction, error) {
+			return &hProtocol.Transaction{EnvelopeXdr: tx, Successful: true}, nil
+		},
+		CheckSequenceRefresh: CheckSequenceRefresh,
+		BaseFee:              txnbuild.MinBaseFee,
+	}
+}
+
+func TestParseFundingMode(t *testing.T) {
+	mode, err := ParseFundingMode("")
+	require.NoError(t, err)
+	assert.Equal(t, FundingModeCreateAccount, mode)
+
+	for _, m := range []FundingMode{FundingModeCreateAccount, FundingModeAsset, FundingModeClaimableBalance} {
+		mode, err = ParseFundingMode(string(m))
+		require.NoError(t, err)
+		assert.Equal(t, m, mode)
+	}
+
+	_, err = ParseFundingMode("unknown")
+	assert.Error(t, err)
+}
+
+func TestMinion_makeTx_fundingModes(t *testing.T) {
+	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
+	asset := txnbuild.CreditAsset{Code: "TEST", Issuer: "GD25B4QI6KWVDWXDW25CIM7EKR6A6PBSWE2RCNSAC4NJQDQJXZJYMMKR"}
+
+	operation := func(minion *Minion, mode FundingMode) txnbuild.Operation {
+		txe, err := minion.makeTx(recipientAddress, mode)
+		require.NoError(t, err)
+		gtx, err := txnbuild.TransactionFromXDR(txe)
+		require.NoError(t, err)
+		tx, ok := gtx.Transaction()
+		require.True(t, ok)
+		require.Len(t, tx.Operations(), 1)
+		return tx.Operations()[0]
+	}
+
+	minion := newTestMinion(t)
+
+	op := operation(minion, FundingModeCreateAccount)
+	createAccount, ok := op.(*txnbuild.CreateAccount)
+	require.True(t, ok)
+	assert.Equal(t, recipientAddress, createAccount.Destination)
+	assert.Equal(t, "10000.0000000", createAccount.Amount)
+
+	// Without an asset, claimable balances are of XLM and the asset mode is
+	// not available.
+	op = operation(minion, FundingModeClaimableBalance)
+	claimableBalance, ok := op.(*txnbuild.CreateClaimableBalance)
+	require.True(t, ok)
+	assert.Equal(t, txnbuild.NativeAsset{}, claimableBalance.Asset)
+	assert.Equal(t, "10000.0000000", claimableBalance.Amount)
+	require.Len(t, claimableBalance.Destinations, 1)
+	assert.Equal(t, recipientAddress, claimableBalance.Destinations[0].Destination)
+
+	_, err := minion.makeTx(recipientAddress, FundingModeAsset)
+	assert.Equal(t, ErrAssetNotConfigured, err)
+
+	minion.Asset = asset
+	minion.AssetAmount = "500"
+
+	op = operation(minion, FundingModeAsset)
+	payment, ok := op.(*txnbuild.Payment)
+	require.True(t, ok)
+	assert.Equal(t, recipientAddress, payment.Destination)
+	assert.Equal(t, asset, payment.Asset)
+	assert.Equal(t, "500.0000000", payment.Amount)
+	assert.Equal(t, minion.BotAccount.GetAccountID(), payment.SourceAccount.GetAccountID())
+
+	op = operation(minion, FundingModeClaimableBalance)
+	claimableBalance, ok = op.(*txnbuild.CreateClaimableBalance)
+	require.True(t, ok)
+	assert.Equal(t, asset, claimableBalance.Asset)
+	assert.Equal(t, "500.0000000", claimableBalance.Amount)
+}
+
+func TestFriendbotHandler_modeAndRateLimit(t *testing.T) {
+	// The server registers the problems of the funding errors on start.
+	assetNotConfiguredProblem := problem.BadRequest
+	assetNotConfiguredProblem.Detail = ErrAssetNotConfigured.Error()
+	problem.RegisterError(ErrAssetNotConfigured, assetNotConfiguredProblem)
+	problem.RegisterError(ErrRateLimited, RateLimitedProblem)
+
+	handler := &FriendbotHandler{
+		Friendbot: &Bot{Minions: []Minion{*newTestMinion(t)}},
+		RateLimiter: &RateLimiter{
+			Store:             &MemoryRateLimitStore{},
+			Window:            time.Hour,
+			MaxPerDestination: 1,
+		},
+		TrustForwardedFor: true,
+	}
+
+	request := func(form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("X-Forwarded-For", "1.2.3.4, 10.0.0.1")
+		w := httptest.NewRecorder()
+		handler.Handle(w, r)
+		return w
+	}
+
+	w := request(url.Values{"addr": {"GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"}, "mode": {"unknown"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = request(url.Values{"addr": {"GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"}, "mode": {"asset"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrAssetNotConfigured.Error())
+
+	// Requests count towards the limits even if funding fails.
+	w = request(url.Values{"addr": {"GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"}, "mode": {"claimable_balance"}})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = request(url.Values{"addr": {"GD4AGPPDFFHKK3Z2X4XZDRXX6GZQKP4FMLVQ5T55NDEYGG3GIP7BQUHM"}, "mode": {"claimable_balance"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only the address added by the proxy is trusted.
+	r := httptest.NewRequest("POST", "/", nil)
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 10.0.0.1")
+	assert.Equal(t, "10.0.0.1", handler.clientIP(r))
+	handler.TrustForwardedFor = false
+	assert.Equal(t, "192.0.2.1", handler.clientIP(r))
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
@@ -14,6 +15,17 @@ const createAccountAlreadyExistXDR = "AAAAAAAAAGT/////AAAAAQAAAAAAAAAA/////AAAAA
 
 var ErrAccountExists error = errors.New(fmt.Sprintf("createAccountAlreadyExist (%s)", createAccountAlreadyExistXDR))
 
+// OperationsFailedError is returned when a transaction with more than one
+// operation fails because of its operations. Codes are the result codes of the
+// operations, in order.
+type OperationsFailedError struct {
+	Codes []string
+}
+
+func (e *OperationsFailedError) Error() string {
+	return "operations failed: " + strings.Join(e.Codes, ", ")
+}
+
 // Minion contains a Stellar channel account and Go channels to communicate with friendbot.
 type Minion struct {
 	Account         Account
@@ -25,17 +37,25 @@ type Minion struct {
 	StartingBalance string
 	BaseFee         int64
 
+	// Asset is the asset destinations are funded with in the asset and
+	// claimable balance modes, or nil if friendbot is not configured with an
+	// asset. AssetAmount is the amount of it they are funded with.
+	Asset       txnbuild.Asset
+	AssetAmount string
+
 	// Mockable functions
 	SubmitTransaction    func(minion *Minion, hclient *horizonclient.Client, tx string) (*hProtocol.Transaction, error)
 	CheckSequenceRefresh func(minion *Minion, hclient *horizonclient.Client) error
+	CheckBalance         func(minion *Minion, hclient *horizonclient.Client) (string, error)
 
 	// Uninitialized.
 	forceRefreshSequence bool
 }
 
-// Run reads a payment destination address and an output channel. It attempts
-// to pay that address and submits the result to the channel.
-func (minion *Minion) Run(destAddress string, resultChan chan SubmitResult) {
+// Run reads a payment destination address, a funding mode and an output
+// channel. It attempts to fund that address with the mode and submits the
+// result to the channel.
+func (minion *Minion) Run(destAddress string, mode FundingMode, resultChan chan SubmitResult) {
 	err := minion.CheckSequenceRefresh(minion, minion.Horizon)
 	if err != nil {
 		resultChan <- SubmitResult{
@@ -44,7 +64,7 @@ func (minion *Minion) Run(destAddress string, resultChan chan SubmitResult) {
 		}
 		return
 	}
-	txStr, err := minion.makeTx(destAddress)
+	txStr, err := minion.makeTx(destAddress, mode)
 	if err != nil {
 		resultChan <- SubmitResult{
 			maybeTransactionSuccess: nil,
@@ -72,6 +92,8 @@ func SubmitTransaction(minion *Minion, hclient *horizonclient.Client, tx string)
 				errStr += ": error getting horizon error code: " + resErr.Error()
 			} else if resStr == createAccountAlreadyExistXDR {
 				return nil, errors.Wrap(ErrAccountExists, errStr)
+			} else if opErr := operationsError(e); opErr != nil {
+				return nil, errors.Wrap(opErr, errStr)
 			} else {
 				errStr += ": horizon error string: " + resStr
 			}
@@ -105,17 +127,62 @@ func (minion *Minion) checkHandleBadSequence(err *horizonclient.Error) {
 	minion.forceRefreshSequence = true
 }
 
-func (minion *Minion) makeTx(destAddress string) (string, error) {
-	createAccountOp := txnbuild.CreateAccount{
-		Destination:   destAddress,
-		SourceAccount: minion.BotAccount,
-		Amount:        minion.StartingBalance,
+// CheckBalance returns the minion's XLM balance.
+// This should also be passed to the minion.
+func CheckBalance(minion *Minion, hclient *horizonclient.Client) (string, error) {
+	accountDetail, err := hclient.AccountDetail(horizonclient.AccountRequest{AccountID: minion.Account.GetAccountID()})
+	if err != nil {
+		return "", errors.Wrap(err, "getting account detail")
+	}
+	return accountDetail.GetNativeBalance()
+}
+
+// operationsError returns the error of a transaction that failed because of
+// its operations: an OperationsFailedError if it has more than one operation,
+// or the error of the result code of its operation if that is caused by the
+// destination. It returns nil otherwise.
+func operationsError(err *horizonclient.Error) error {
+	resCode, e := err.ResultCodes()
+	if e != nil || len(resCode.OperationCodes) == 0 {
+		return nil
+	}
+	if len(resCode.OperationCodes) > 1 {
+		return &OperationsFailedError{Codes: resCode.OperationCodes}
 	}
+	return operationError(resCode.OperationCodes[0])
+}
+
+// operationError returns the error of the result code of a failed funding
+// operation that is caused by the destination, or nil.
+func operationError(code string) error {
+	switch code {
+	case "op_already_exists":
+		return ErrAccountExists
+	case "op_no_destination":
+		return ErrAccountNotFound
+	case "op_no_trust":
+		return ErrNoTrustline
+	default:
+		return nil
+	}
+}
+
+func (minion *Minion) makeTx(destAddress string, mode FundingMode) (string, error) {
+	op, err := minion.fundingOp(destAddress, mode)
+	if err != nil {
+		return "", err
+	}
+	return minion.makeTxOps([]txnbuild.Operation{op}, minion.Keypair, minion.BotKeypair)
+}
+
+// makeTxOps returns a transaction of the operations from the minion's account,
+// signed by the signers.
+func (minion *Minion) makeTxOps(ops []txnbuild.Operation, signers ...*keypair.Full) (string, error) {
 	tx, err := txnbuild.NewTransaction(
 		txnbuild.TransactionParams{
 			SourceAccount:        minion.Account,
 			IncrementSequenceNum: true,
-			Operations:           []txnbuild.Operation{&createAccountOp},
+			Operations:           ops,
 			BaseFee:              minion.BaseFee,
 			Timebounds:           txnbuild.NewInfiniteTimeout(),
 		},
@@ -124,7 +191,7 @@ func (minion *Minion) makeTx(destAddress string) (string, error) {
 		return "", errors.Wrap(err, "unable to build tx")
 	}
 
-	tx, err = tx.Sign(minion.Network, minion.Keypair, minion.BotKeypair)
+	tx, err = tx.Sign(minion.Network, signers...)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to sign tx")
 	}
@@ -135,9 +202,8 @@ func (minion *Minion) makeTx(destAddress string) (string, error) {
 	}
 
 	// Increment the in-memory sequence number, since the tx will be submitted.
-	_, err = minion.Account.IncrementSequenceNumber()
-	if err != nil {
-		return "", errors.Wrap(err, "incrementing minion seq")
-	}
-	return txe, err
+	// Account.IncrementSequenceNumber increments a copy, so the sequence number
+	// is taken from the tx.
+	minion.Account.Sequence = tx.SourceAccount().Sequence
+	return txe, nil
 }
@@ -0,0 +1,454 @@
+package internal
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/amount"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// MaxBatchSize is the maximum number of destinations funded by a single
+// transaction, the maximum number of operations in a transaction.
+const MaxBatchSize = 100
+
+// MinionPool funds destinations in batches. Pending requests are packed into
+// transactions of up to BatchSize operations, each submitted by an idle
+// minion, so that each minion funds up to BatchSize destinations per ledger.
+//
+// Minions whose XLM balance falls below RefillThreshold are refilled from the
+// bot account. Minions are created with CreateMinions when requests are
+// waiting for an idle minion, up to MaxMinions, and minions idle for longer
+// than IdleTimeout are merged back into the bot account, down to MinMinions.
+type MinionPool struct {
+	// Minions are the minions the pool starts with.
+	Minions []Minion
+
+	// BatchSize is the maximum number of destinations funded by each
+	// transaction. Defaults to MaxBatchSize if zero.
+	BatchSize int
+	// BatchInterval is how long requests are collected for before their
+	// batch is submitted, unless the batch is full first. Defaults to one
+	// second if zero.
+	BatchInterval time.Duration
+
+	// MinMinions and MaxMinions bound the number of minions. They default to
+	// the number of Minions, in which case the pool is not resized.
+	MinMinions int
+	MaxMinions int
+	// IdleTimeout is how long a minion can be idle for before it is merged
+	// back into the bot account. Defaults to ten minutes if zero.
+	IdleTimeout time.Duration
+
+	// RefillThreshold is the XLM balance below which minions are refilled
+	// with RefillAmount XLM from the bot account. Minions are not refilled if
+	// RefillThreshold is empty.
+	RefillThreshold string
+	RefillAmount    string
+	// RefillCheckInterval is how often the balance of each minion is checked.
+	// Defaults to one minute if zero.
+	RefillCheckInterval time.Duration
+
+	// Mockable functions
+	CreateMinions func(n int) ([]Minion, error)
+
+	// Uninitialized.
+	init    sync.Once
+	queue   chan *fundingRequest
+	idle    chan *pooledMinion
+	mu      sync.Mutex
+	size    int
+	growing bool
+	metrics poolMetrics
+}
+
+// fundingRequest is a pending request to fund a destination.
+type fundingRequest struct {
+	destAddress string
+	mode        FundingMode
+	resultChan  chan SubmitResult
+}
+
+// pooledMinion is a minion of a MinionPool.
+type pooledMinion struct {
+	*Minion
+	lastUsed       time.Time
+	balanceChecked time.Time
+}
+
+type poolMetrics struct {
+	queueDepth      prometheus.Gauge
+	requestDuration prometheus.Summary
+	batchSize       prometheus.Summary
+	minions         prometheus.GaugeFunc
+	idleMinions     prometheus.GaugeFunc
+	refills         prometheus.Counter
+	retirements     prometheus.Counter
+}
+
+// Pay funds the account at `destAddress` with the funding mode, in the next
+// batch.
+func (p *MinionPool) Pay(destAddress string, mode FundingMode) (*hProtocol.Transaction, error) {
+	p.start()
+
+	start := time.Now()
+	defer func() {
+		p.metrics.requestDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	req := &fundingRequest{
+		destAddress: destAddress,
+		mode:        mode,
+		resultChan:  make(chan SubmitResult, 1),
+	}
+	p.metrics.queueDepth.Inc()
+	p.queue <- req
+	result := <-req.resultChan
+	return result.maybeTransactionSuccess, result.maybeErr
+}
+
+// RegisterMetrics registers the pool's metrics: the number of requests
+// waiting to be submitted, the duration of requests, the size of batches, the
+// number of minions and idle minions, and the number of minions refilled and
+// merged.
+func (p *MinionPool) RegisterMetrics(registry *prometheus.Registry) {
+	p.start()
+	registry.MustRegister(
+		p.metrics.queueDepth,
+		p.metrics.requestDuration,
+		p.metrics.batchSize,
+		p.metrics.minions,
+		p.metrics.idleMinions,
+		p.metrics.refills,
+		p.metrics.retirements,
+	)
+}
+
+func (p *MinionPool) start() {
+	p.init.Do(func() {
+		if p.BatchSize <= 0 || p.BatchSize > MaxBatchSize {
+			p.BatchSize = MaxBatchSize
+		}
+		if p.BatchInterval == 0 {
+			p.BatchInterval = time.Second
+		}
+		if p.MinMinions == 0 {
+			p.MinMinions = len(p.Minions)
+		}
+		if p.MaxMinions < len(p.Minions) {
+			p.MaxMinions = len(p.Minions)
+		}
+		if p.IdleTimeout == 0 {
+			p.IdleTimeout = 10 * time.Minute
+		}
+		if p.RefillCheckInterval == 0 {
+			p.RefillCheckInterval = time.Minute
+		}
+
+		p.initMetrics()
+		p.queue = make(chan *fundingRequest)
+		p.idle = make(chan *pooledMinion, p.MaxMinions)
+		for i := range p.Minions {
+			p.addMinion(&p.Minions[i])
+		}
+
+		go p.dispatch()
+		go p.retireIdleMinions()
+	})
+}
+
+func (p *MinionPool) initMetrics() {
+	p.metrics = poolMetrics{
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "queue_depth",
+			Help: "number of requests waiting to be submitted",
+		}),
+		requestDuration: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "request_duration_seconds",
+			Help: "duration of requests, from being queued to the result of their transaction",
+		}),
+		batchSize: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "batch_size",
+			Help: "number of destinations funded by each transaction",
+		}),
+		minions: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "minions",
+			Help: "number of minions",
+		}, func() float64 {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return float64(p.size)
+		}),
+		idleMinions: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "idle_minions",
+			Help: "number of minions waiting for a batch",
+		}, func() float64 {
+			return float64(len(p.idle))
+		}),
+		refills: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "refills_total",
+			Help: "number of times minions were refilled from the bot account",
+		}),
+		retirements: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "friendbot", Subsystem: "pool", Name: "retirements_total",
+			Help: "number of idle minions merged back into the bot account",
+		}),
+	}
+}
+
+func (p *MinionPool) addMinion(minion *Minion) {
+	p.mu.Lock()
+	p.size++
+	p.mu.Unlock()
+	p.idle <- &pooledMinion{Minion: minion, lastUsed: time.Now()}
+}
+
+// dispatch collects batches of requests and submits each with an idle
+// minion.
+func (p *MinionPool) dispatch() {
+	for {
+		batch := []*fundingRequest{<-p.queue}
+		timeout := time.After(p.BatchInterval)
+	collect:
+		for len(batch) < p.BatchSize {
+			select {
+			case req := <-p.queue:
+				batch = append(batch, req)
+			case <-timeout:
+				break collect
+			}
+		}
+
+		// Keep adding requests to the batch while waiting for an idle minion.
+		var minion *pooledMinion
+		select {
+		case minion = <-p.idle:
+		default:
+			p.grow()
+		}
+		for minion == nil {
+			queue := p.queue
+			if len(batch) >= p.BatchSize {
+				queue = nil
+			}
+			select {
+			case minion = <-p.idle:
+			case req := <-queue:
+				batch = append(batch, req)
+			}
+		}
+
+		p.metrics.queueDepth.Sub(float64(len(batch)))
+		p.metrics.batchSize.Observe(float64(len(batch)))
+		go func() {
+			p.submitBatch(minion, batch)
+			p.checkRefill(minion)
+			minion.lastUsed = time.Now()
+			p.idle <- minion
+		}()
+	}
+}
+
+// grow creates more minions in the background, if the pool is not already
+// growing and has fewer than MaxMinions minions.
+func (p *MinionPool) grow() {
+	p.mu.Lock()
+	n := p.MaxMinions - p.size
+	if n > MaxBatchSize {
+		n = MaxBatchSize
+	}
+	if p.growing || n <= 0 || p.CreateMinions == nil {
+		p.mu.Unlock()
+		return
+	}
+	p.growing = true
+	p.mu.Unlock()
+
+	go func() {
+		log.Printf("All minions are busy, creating %d minions", n)
+		minions, err := p.CreateMinions(n)
+		if err != nil {
+			log.Printf("Error creating minions: %s", err)
+		}
+		for i := range minions {
+			p.addMinion(&minions[i])
+		}
+		p.mu.Lock()
+		p.growing = false
+		p.mu.Unlock()
+	}()
+}
+
+// submitBatch funds the destinations of the batch with a transaction of the
+// minion, and sends the result to each request. If the transaction fails
+// because of some of its operations, their requests fail and the transaction
+// is submitted again without them.
+func (p *MinionPool) submitBatch(minion *pooledMinion, batch []*fundingRequest) {
+	for len(batch) > 0 {
+		var ops []txnbuild.Operation
+		var requests []*fundingRequest
+		for _, req := range batch {
+			op, err := minion.fundingOp(req.destAddress, req.mode)
+			if err != nil {
+				req.resultChan <- SubmitResult{maybeErr: errors.Wrap(err, "making payment tx")}
+				continue
+			}
+			ops = append(ops, op)
+			requests = append(requests, req)
+		}
+		if len(requests) == 0 {
+			return
+		}
+
+		succ, err := minion.submit(ops)
+		if err == nil {
+			for _, req := range requests {
+				req.resultChan <- SubmitResult{maybeTransactionSuccess: succ}
+			}
+			return
+		}
+
+		opsErr, ok := errors.Cause(err).(*OperationsFailedError)
+		if !ok || len(opsErr.Codes) != len(requests) {
+			for _, req := range requests {
+				req.resultChan <- SubmitResult{maybeErr: err}
+			}
+			return
+		}
+
+		batch = nil
+		for i, code := range opsErr.Codes {
+			if code == "op_success" {
+				batch = append(batch, requests[i])
+				continue
+			}
+			opErr := operationError(code)
+			if opErr == nil {
+				opErr = errors.Errorf("operation failed: %s", code)
+			}
+			requests[i].resultChan <- SubmitResult{maybeErr: errors.Wrap(opErr, "submitting tx to minion")}
+		}
+	}
+}
+
+// submit submits a transaction of the operations signed by the minion and
+// the bot.
+func (minion *Minion) submit(ops []txnbuild.Operation) (*hProtocol.Transaction, error) {
+	err := minion.CheckSequenceRefresh(minion, minion.Horizon)
+	if err != nil {
+		return nil, errors.Wrap(err, "checking minion seq")
+	}
+	txStr, err := minion.makeTxOps(ops, minion.Keypair, minion.BotKeypair)
+	if err != nil {
+		return nil, errors.Wrap(err, "making payment tx")
+	}
+	succ, err := minion.SubmitTransaction(minion, minion.Horizon, txStr)
+	return succ, errors.Wrap(err, "submitting tx to minion")
+}
+
+// checkRefill refills the minion from the bot account if its balance is
+// below the refill threshold, checking its balance at most once every
+// RefillCheckInterval.
+func (p *MinionPool) checkRefill(minion *pooledMinion) {
+	if p.RefillThreshold == "" || minion.CheckBalance == nil || time.Since(minion.balanceChecked) < p.RefillCheckInterval {
+		return
+	}
+	minion.balanceChecked = time.Now()
+
+	err := p.refill(minion.Minion)
+	if err != nil {
+		log.Printf("Error refilling minion %s: %s", minion.Account.AccountID, err)
+	}
+}
+
+func (p *MinionPool) refill(minion *Minion) error {
+	threshold, err := amount.ParseInt64(p.RefillThreshold)
+	if err != nil {
+		return errors.Wrap(err, "parsing refill threshold")
+	}
+	balanceStr, err := minion.CheckBalance(minion, minion.Horizon)
+	if err != nil {
+		return errors.Wrap(err, "checking balance")
+	}
+	balance, err := amount.ParseInt64(balanceStr)
+	if err != nil {
+		return errors.Wrap(err, "parsing balance")
+	}
+	if balance >= threshold {
+		return nil
+	}
+
+	log.Printf("Refilling minion %s with balance %s", minion.Account.AccountID, balanceStr)
+	_, err = minion.submit([]txnbuild.Operation{&txnbuild.Payment{
+		Destination:   minion.Account.AccountID,
+		SourceAccount: minion.BotAccount,
+		Asset:         txnbuild.NativeAsset{},
+		Amount:        p.RefillAmount,
+	}})
+	if err != nil {
+		return err
+	}
+	p.metrics.refills.Inc()
+	return nil
+}
+
+// retireIdleMinions periodically merges minions that have been idle for
+// longer than IdleTimeout back into the bot account, while there are more
+// than MinMinions minions.
+func (p *MinionPool) retireIdleMinions() {
+	for range time.Tick(p.IdleTimeout / 2) {
+		// Idle minions are queued in the order they became idle, so minions
+		// that are idle for less than IdleTimeout are put back at the end of
+		// the queue in the same order.
+		for n := len(p.idle); n > 0; n-- {
+			var minion *pooledMinion
+			select {
+			case minion = <-p.idle:
+			default:
+			}
+			if minion == nil {
+				break
+			}
+
+			p.mu.Lock()
+			retire := p.size > p.MinMinions && time.Since(minion.lastUsed) > p.IdleTimeout
+			if retire {
+				p.size--
+			}
+			p.mu.Unlock()
+
+			if !retire {
+				p.idle <- minion
+				continue
+			}
+			err := p.retire(minion.Minion)
+			if err != nil {
+				log.Printf("Error merging minion %s: %s", minion.Account.AccountID, err)
+				p.addMinion(minion.Minion)
+				continue
+			}
+			p.metrics.retirements.Inc()
+		}
+	}
+}
+
+// retire merges the minion's account into the bot account.
+func (p *MinionPool) retire(minion *Minion) error {
+	log.Printf("Merging idle minion %s", minion.Account.AccountID)
+	err := minion.CheckSequenceRefresh(minion, minion.Horizon)
+	if err != nil {
+		return errors.Wrap(err, "checking minion seq")
+	}
+	txStr, err := minion.makeTxOps([]txnbuild.Operation{&txnbuild.AccountMerge{
+		Destination: minion.BotAccount.GetAccountID(),
+	}}, minion.Keypair)
+	if err != nil {
+		return errors.Wrap(err, "making merge tx")
+	}
+	_, err = minion.SubmitTransaction(minion, minion.Horizon, txStr)
+	return errors.Wrap(err, "submitting merge tx")
+}
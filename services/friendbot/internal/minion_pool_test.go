@@ -0,0 +1,228 @@
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// submittedTxs records the transactions submitted by minions.
+type submittedTxs struct {
+	mu  sync.Mutex
+	txs []*txnbuild.Transaction
+}
+
+func (s *submittedTxs) add(t *testing.T, tx string) *txnbuild.Transaction {
+	gtx, err := txnbuild.TransactionFromXDR(tx)
+	require.NoError(t, err)
+	parsed, ok := gtx.Transaction()
+	require.True(t, ok)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs = append(s.txs, parsed)
+	return parsed
+}
+
+func (s *submittedTxs) get() []*txnbuild.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*txnbuild.Transaction(nil), s.txs...)
+}
+
+func newPoolTestMinion(t *testing.T, submitted *submittedTxs) Minion {
+	minion := *newTestMinion(t)
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	minion.Keypair = kp
+	minion.Account = Account{AccountID: kp.Address(), Sequence: 1}
+	minion.SubmitTransaction = func(minion *Minion, hclient *horizonclient.Client, tx string) (*hProtocol.Transaction, error) {
+		submitted.add(t, tx)
+		return &hProtocol.Transaction{EnvelopeXdr: tx, Successful: true}, nil
+	}
+	return minion
+}
+
+func payConcurrently(pool *MinionPool, destinations []string) []error {
+	errs := make([]error, len(destinations))
+	var wg sync.WaitGroup
+	for i := range destinations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = pool.Pay(destinations[i], FundingModeCreateAccount)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+func randomAddresses(t *testing.T, n int) []string {
+	var addresses []string
+	for i := 0; i < n; i++ {
+		kp, err := keypair.Random()
+		require.NoError(t, err)
+		addresses = append(addresses, kp.Address())
+	}
+	return addresses
+}
+
+func TestMinionPool_batches(t *testing.T) {
+	submitted := &submittedTxs{}
+	pool := &MinionPool{
+		Minions:       []Minion{newPoolTestMinion(t, submitted)},
+		BatchInterval: 100 * time.Millisecond,
+	}
+
+	destinations := randomAddresses(t, 150)
+	for _, err := range payConcurrently(pool, destinations) {
+		assert.NoError(t, err)
+	}
+
+	txs := submitted.get()
+	require.True(t, len(txs) >= 2)
+	funded := map[string]bool{}
+	for i, tx := range txs {
+		assert.True(t, len(tx.Operations()) <= MaxBatchSize)
+		// Each transaction uses the next sequence number of the minion.
+		assert.Equal(t, int64(2+i), tx.SourceAccount().Sequence)
+		for _, op := range tx.Operations() {
+			funded[op.(*txnbuild.CreateAccount).Destination] = true
+		}
+	}
+	assert.Len(t, funded, len(destinations))
+}
+
+func TestMinionPool_failedOperations(t *testing.T) {
+	destinations := randomAddresses(t, 3)
+	existing := destinations[1]
+
+	submitted := &submittedTxs{}
+	minion := newPoolTestMinion(t, submitted)
+	minion.SubmitTransaction = func(minion *Minion, hclient *horizonclient.Client, txStr string) (*hProtocol.Transaction, error) {
+		tx := submitted.add(t, txStr)
+		var codes []string
+		failed := false
+		for _, op := range tx.Operations() {
+			if op.(*txnbuild.CreateAccount).Destination == existing {
+				codes = append(codes, "op_already_exists")
+				failed = true
+			} else {
+				codes = append(codes, "op_success")
+			}
+		}
+		if failed && len(codes) > 1 {
+			return nil, errors.Wrap(&OperationsFailedError{Codes: codes}, "submitting tx to horizon")
+		}
+		return &hProtocol.Transaction{EnvelopeXdr: txStr, Successful: true}, nil
+	}
+	pool := &MinionPool{Minions: []Minion{minion}, BatchInterval: 100 * time.Millisecond}
+
+	errs := payConcurrently(pool, destinations)
+	assert.NoError(t, errs[0])
+	assert.Equal(t, ErrAccountExists, errors.Cause(errs[1]))
+	assert.NoError(t, errs[2])
+
+	// The transaction was submitted again without the failed operation.
+	txs := submitted.get()
+	require.Len(t, txs, 2)
+	assert.Len(t, txs[0].Operations(), 3)
+	assert.Len(t, txs[1].Operations(), 2)
+}
+
+func TestMinionPool_refill(t *testing.T) {
+	submitted := &submittedTxs{}
+	minion := newPoolTestMinion(t, submitted)
+	minion.CheckBalance = func(minion *Minion, hclient *horizonclient.Client) (string, error) {
+		return "5.0000000", nil
+	}
+	pool := &MinionPool{
+		Minions:         []Minion{minion},
+		BatchInterval:   time.Millisecond,
+		RefillThreshold: "10",
+		RefillAmount:    "100",
+	}
+
+	_, err := pool.Pay(randomAddresses(t, 1)[0], FundingModeCreateAccount)
+	require.NoError(t, err)
+
+	// The refill is submitted after the batch, before the minion is idle.
+	_, err = pool.Pay(randomAddresses(t, 1)[0], FundingModeCreateAccount)
+	require.NoError(t, err)
+
+	txs := submitted.get()
+	require.Len(t, txs, 3)
+	payment, ok := txs[1].Operations()[0].(*txnbuild.Payment)
+	require.True(t, ok)
+	assert.Equal(t, minion.Account.AccountID, payment.Destination)
+	assert.Equal(t, minion.BotAccount.GetAccountID(), payment.SourceAccount.GetAccountID())
+	assert.Equal(t, "100.0000000", payment.Amount)
+	assert.Equal(t, txnbuild.NativeAsset{}, payment.Asset)
+}
+
+func TestMinionPool_resize(t *testing.T) {
+	submitted := &submittedTxs{}
+	release := make(chan struct{})
+	blocking := newPoolTestMinion(t, submitted)
+	blocking.SubmitTransaction = func(minion *Minion, hclient *horizonclient.Client, tx string) (*hProtocol.Transaction, error) {
+		<-release
+		submitted.add(t, tx)
+		return &hProtocol.Transaction{EnvelopeXdr: tx, Successful: true}, nil
+	}
+
+	created := 0
+	pool := &MinionPool{
+		Minions:       []Minion{blocking},
+		BatchInterval: time.Millisecond,
+		MaxMinions:    2,
+		IdleTimeout:   100 * time.Millisecond,
+		CreateMinions: func(n int) ([]Minion, error) {
+			created += n
+			return []Minion{newPoolTestMinion(t, submitted)}, nil
+		},
+	}
+
+	// The first request blocks the only minion, so a minion is created for
+	// the second.
+	firstDone := make(chan error)
+	go func() {
+		_, err := pool.Pay(randomAddresses(t, 1)[0], FundingModeCreateAccount)
+		firstDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	_, err := pool.Pay(randomAddresses(t, 1)[0], FundingModeCreateAccount)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	close(release)
+	require.NoError(t, <-firstDone)
+
+	// One of the idle minions is merged back into the bot account.
+	require.Eventually(t, func() bool {
+		return len(submitted.get()) == 3
+	}, time.Second, 10*time.Millisecond)
+	pool.mu.Lock()
+	assert.Equal(t, 1, pool.size)
+	pool.mu.Unlock()
+
+	txs := submitted.get()
+	merge, ok := txs[2].Operations()[0].(*txnbuild.AccountMerge)
+	require.True(t, ok)
+	assert.Equal(t, blocking.BotAccount.GetAccountID(), merge.Destination)
+	assert.Len(t, txs[2].Signatures(), 1)
+}
+
+func TestOperationsFailedError(t *testing.T) {
+	err := &OperationsFailedError{Codes: []string{"op_success", "op_already_exists"}}
+	assert.Equal(t, "operations failed: op_success, op_already_exists", err.Error())
+	assert.Equal(t, ErrAccountExists, operationError("op_already_exists"))
+	assert.Nil(t, operationError("op_underfunded"))
+}
@@ -65,7 +65,7 @@ func TestMinion_NoChannelErrors(t *testing.T) {
 
 	for i := 0; i < numTests; i++ {
 		go func() {
-			fb.Pay(recipientAddress)
+			fb.Pay(recipientAddress, FundingModeCreateAccount)
 			wg.Done()
 		}()
 	}
@@ -128,7 +128,7 @@ func TestMinion_CorrectNumberOfTxSubmissions(t *testing.T) {
 
 	for i := 0; i < numTests; i++ {
 		go func() {
-			fb.Pay(recipientAddress)
+			fb.Pay(recipientAddress, FundingModeCreateAccount)
 			wg.Done()
 		}()
 	}
@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+)
+
+// ErrRateLimited is returned when a caller or a destination has been funded
+// too many times recently.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimiter limits how many times each IP address and each destination
+// address are funded within a window of time. Requests are counted whether or
+// not funding succeeds.
+type RateLimiter struct {
+	Store RateLimitStore
+	// Window is the duration requests are counted over.
+	Window time.Duration
+	// MaxPerIP is the number of requests each IP address can make within the
+	// window. IP addresses are not limited if zero.
+	MaxPerIP int
+	// MaxPerDestination is the number of times each destination can be funded
+	// within the window. Destinations are not limited if zero.
+	MaxPerDestination int
+}
+
+// RateLimitStore records requests and counts them.
+type RateLimitStore interface {
+	// Allow reports whether fewer than limit requests for the key have been
+	// recorded since `since`, and if so records a request for the key at
+	// `now`.
+	Allow(key string, limit int, since, now time.Time) (bool, error)
+}
+
+// Allow records a request from the IP address to fund the destination, or
+// returns ErrRateLimited if either has reached its limit.
+func (l *RateLimiter) Allow(ip, destination string) error {
+	now := time.Now()
+	since := now.Add(-l.Window)
+
+	if l.MaxPerIP > 0 {
+		ok, err := l.Store.Allow("ip:"+ip, l.MaxPerIP, since, now)
+		if err != nil {
+			return errors.Wrap(err, "checking ip rate limit")
+		}
+		if !ok {
+			return ErrRateLimited
+		}
+	}
+
+	if l.MaxPerDestination > 0 {
+		ok, err := l.Store.Allow("destination:"+destination, l.MaxPerDestination, since, now)
+		if err != nil {
+			return errors.Wrap(err, "checking destination rate limit")
+		}
+		if !ok {
+			return ErrRateLimited
+		}
+	}
+
+	return nil
+}
+
+// MemoryRateLimitStore is a RateLimitStore that records requests in memory.
+// Requests are forgotten when friendbot restarts.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	requests map[string][]time.Time
+	pruned   time.Time
+}
+
+// Allow implements RateLimitStore.
+func (s *MemoryRateLimitStore) Allow(key string, limit int, since, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.requests == nil {
+		s.requests = map[string][]time.Time{}
+	}
+	s.prune(since, now)
+
+	recent := recentRequests(s.requests[key], since)
+	if len(recent) >= limit {
+		s.requests[key] = recent
+		return false, nil
+	}
+	s.requests[key] = append(recent, now)
+	return true, nil
+}
+
+// prune forgets the requests of keys that have not been requested since
+// `since`, at most once a minute so that it does not run for every request.
+func (s *MemoryRateLimitStore) prune(since, now time.Time) {
+	if now.Sub(s.pruned) < time.Minute {
+		return
+	}
+	for key, times := range s.requests {
+		if len(recentRequests(times, since)) == 0 {
+			delete(s.requests, key)
+		}
+	}
+	s.pruned = now
+}
+
+// recentRequests returns the times, which are in ascending order, that are
+// after `since`.
+func recentRequests(times []time.Time, since time.Time) []time.Time {
+	for i, t := range times {
+		if t.After(since) {
+			return times[i:]
+		}
+	}
+	return nil
+}
+
+// DBRateLimitStore is a RateLimitStore that records requests in a PostgreSQL
+// database, so that they are remembered when friendbot restarts and shared
+// between friendbot instances.
+type DBRateLimitStore struct {
+	Session *db.Session
+}
+
+// NewDBRateLimitStore returns a DBRateLimitStore that uses the database,
+// creating its table if it does not exist.
+func NewDBRateLimitStore(session *db.Session) (*DBRateLimitStore, error) {
+	err := session.ExecAll(`
+		CREATE TABLE IF NOT EXISTS friendbot_requests (
+			key text NOT NULL,
+			created_at timestamptz NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS friendbot_requests_key_created_at_idx ON friendbot_requests (key, created_at);
+		CREATE INDEX IF NOT EXISTS friendbot_requests_created_at_idx ON friendbot_requests (created_at);
+	`)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating friendbot_requests table")
+	}
+	return &DBRateLimitStore{Session: session}, nil
+}
+
+// Allow implements RateLimitStore.
+func (s *DBRateLimitStore) Allow(key string, limit int, since, now time.Time) (allowed bool, err error) {
+	session := s.Session.Clone()
+	err = session.Begin()
+	if err != nil {
+		return false, errors.Wrap(err, "beginning transaction")
+	}
+	defer func() {
+		if err != nil || !allowed {
+			session.Rollback()
+		}
+	}()
+
+	// Serialize the requests for the key so that concurrent requests are
+	// counted correctly.
+	_, err = session.ExecRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, key)
+	if err != nil {
+		return false, errors.Wrap(err, "locking key")
+	}
+
+	var count int
+	err = session.GetRaw(&count, `SELECT count(*) FROM friendbot_requests WHERE key = $1 AND created_at > $2`, key, since)
+	if err != nil {
+		return false, errors.Wrap(err, "counting requests")
+	}
+	if count >= limit {
+		return false, nil
+	}
+
+	_, err = session.ExecRaw(`INSERT INTO friendbot_requests (key, created_at) VALUES ($1, $2)`, key, now)
+	if err != nil {
+		return false, errors.Wrap(err, "recording request")
+	}
+	err = session.Commit()
+	if err != nil {
+		return false, errors.Wrap(err, "committing transaction")
+	}
+	return true, nil
+}
+
+// DeleteOld deletes the requests recorded before `since`, which are no
+// longer counted. It should be called periodically so that the table does not
+// grow without bound.
+func (s *DBRateLimitStore) DeleteOld(since time.Time) error {
+	_, err := s.Session.ExecRaw(`DELETE FROM friendbot_requests WHERE created_at <= $1`, since)
+	return errors.Wrap(err, "deleting old requests")
+}
+
+var _ RateLimitStore = &MemoryRateLimitStore{}
+var _ RateLimitStore = &DBRateLimitStore{}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := &RateLimiter{
+		Store:             &MemoryRateLimitStore{},
+		Window:            time.Hour,
+		MaxPerIP:          3,
+		MaxPerDestination: 2,
+	}
+	dest1 := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
+	dest2 := "GD4AGPPDFFHKK3Z2X4XZDRXX6GZQKP4FMLVQ5T55NDEYGG3GIP7BQUHM"
+
+	assert.NoError(t, limiter.Allow("1.2.3.4", dest1))
+	assert.NoError(t, limiter.Allow("5.6.7.8", dest1))
+	// The destination has been funded twice.
+	assert.Equal(t, ErrRateLimited, limiter.Allow("9.9.9.9", dest1))
+
+	assert.NoError(t, limiter.Allow("1.2.3.4", dest2))
+	assert.NoError(t, limiter.Allow("1.2.3.4", dest2+"X"))
+	// The IP address has made three requests.
+	assert.Equal(t, ErrRateLimited, limiter.Allow("1.2.3.4", "GA3R753JKGXU6ETHNY3U6PYIY7D6UUCXXDYBRF4XURNAGXW3CVGQH2ZA"))
+
+	// Limits that are zero are not checked.
+	unlimited := &RateLimiter{Store: &MemoryRateLimitStore{}, Window: time.Hour}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, unlimited.Allow("1.2.3.4", dest1))
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	s := &MemoryRateLimitStore{}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		ok, err := s.Allow("key", 2, now.Add(-time.Minute), now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := s.Allow("key", 2, now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Requests before the window are not counted.
+	later := now.Add(time.Minute)
+	ok, err = s.Allow("key", 2, later.Add(-time.Minute), later)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, s.requests["key"], 1)
+
+	// Keys without recent requests are forgotten.
+	muchLater := now.Add(time.Hour)
+	ok, err = s.Allow("other", 2, muchLater.Add(-time.Minute), muchLater)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.NotContains(t, s.requests, "key")
+}
+
+func TestDBRateLimitStore(t *testing.T) {
+	dbt := dbtest.Postgres(t)
+	defer dbt.Close()
+
+	s, err := NewDBRateLimitStore(&db.Session{DB: dbt.Open(), Ctx: context.Background()})
+	require.NoError(t, err)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		ok, err := s.Allow("key", 2, now.Add(-time.Minute), now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := s.Allow("key", 2, now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.Allow("other", 2, now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Requests before the window are not counted, and can be deleted.
+	later := now.Add(time.Minute)
+	ok, err = s.Allow("key", 2, later.Add(-time.Minute), later)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, s.DeleteOld(later.Add(-time.Minute)))
+	var count int
+	require.NoError(t, s.Session.GetRaw(&count, `SELECT count(*) FROM friendbot_requests`))
+	assert.Equal(t, 1, count)
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/services/friendbot/internal"
 	"github.com/stellar/go/support/app"
@@ -27,6 +29,34 @@ type Config struct {
 	TLS               *config.TLS `valid:"optional"`
 	NumMinions        int         `toml:"num_minions" valid:"optional"`
 	BaseFee           int64       `toml:"base_fee" valid:"optional"`
+	AssetCode         string      `toml:"asset_code" valid:"optional"`
+	AssetIssuer       string      `toml:"asset_issuer" valid:"optional"`
+	AssetAmount       string      `toml:"asset_amount" valid:"optional"`
+	RateLimit         *RateLimit  `toml:"rate_limit" valid:"optional"`
+	Batching          *Batching   `toml:"batching" valid:"optional"`
+	AdminPort         int         `toml:"admin_port" valid:"optional"`
+}
+
+// RateLimit represents the configuration of the rate limits of a friendbot
+// server
+type RateLimit struct {
+	Window            int    `toml:"window" valid:"optional"`
+	MaxPerIP          int    `toml:"max_per_ip" valid:"optional"`
+	MaxPerDestination int    `toml:"max_per_destination" valid:"optional"`
+	DatabaseURL       string `toml:"database_url" valid:"optional"`
+	TrustForwardedFor bool   `toml:"trust_forwarded_for" valid:"optional"`
+}
+
+// Batching represents the configuration of the batching mode of a friendbot
+// server
+type Batching struct {
+	BatchSize       int     `toml:"batch_size" valid:"optional"`
+	BatchInterval   float64 `toml:"batch_interval" valid:"optional"`
+	MinMinions      int     `toml:"min_minions" valid:"optional"`
+	MaxMinions      int     `toml:"max_minions" valid:"optional"`
+	IdleTimeout     int     `toml:"idle_timeout" valid:"optional"`
+	RefillThreshold string  `toml:"refill_threshold" valid:"optional"`
+	RefillAmount    string  `toml:"refill_amount" valid:"optional"`
 }
 
 func main() {
@@ -60,12 +90,24 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fb, err := initFriendbot(cfg.FriendbotSecret, cfg.NetworkPassphrase, cfg.HorizonURL, cfg.StartingBalance, cfg.NumMinions, cfg.BaseFee)
+	fb, err := initFriendbot(cfg.FriendbotSecret, cfg.NetworkPassphrase, cfg.HorizonURL, cfg.StartingBalance, cfg.NumMinions, cfg.BaseFee, cfg.AssetCode, cfg.AssetIssuer, cfg.AssetAmount, cfg.Batching)
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
-	router := initRouter(fb)
+	if cfg.AdminPort != 0 {
+		go runAdminServer(cfg.AdminPort, fb)
+	}
+	handler := &internal.FriendbotHandler{Friendbot: fb}
+	if cfg.RateLimit != nil {
+		handler.RateLimiter, err = initRateLimiter(*cfg.RateLimit)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		handler.TrustForwardedFor = cfg.RateLimit.TrustForwardedFor
+	}
+	router := initRouter(handler)
 	registerProblems()
 
 	addr := fmt.Sprintf("0.0.0.0:%d", cfg.Port)
@@ -81,10 +123,9 @@ func run(cmd *cobra.Command, args []string) {
 	})
 }
 
-func initRouter(fb *internal.Bot) *chi.Mux {
+func initRouter(handler *internal.FriendbotHandler) *chi.Mux {
 	mux := http.NewAPIMux(log.DefaultLogger)
 
-	handler := &internal.FriendbotHandler{Friendbot: fb}
 	mux.Get("/", handler.Handle)
 	mux.Post("/", handler.Handle)
 	mux.NotFound(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
@@ -94,6 +135,37 @@ func initRouter(fb *internal.Bot) *chi.Mux {
 	return mux
 }
 
+// runAdminServer serves the Prometheus metrics of the friendbot at /metrics.
+func runAdminServer(port int, fb *internal.Bot) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGoCollector())
+	if fb.Pool != nil {
+		fb.Pool.RegisterMetrics(registry)
+	}
+
+	mux := chi.NewMux()
+	mux.Get("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
+
+	addr := fmt.Sprintf("0.0.0.0:%d", port)
+	log.Infof("starting admin server on %s", addr)
+	err := stdhttp.ListenAndServe(addr, mux)
+	if err != nil {
+		log.Error(errors.Wrap(err, "running admin server"))
+	}
+}
+
 func registerProblems() {
 	problem.RegisterError(sql.ErrNoRows, problem.NotFound)
+
+	for _, err := range []error{
+		internal.ErrAccountExists,
+		internal.ErrAssetNotConfigured,
+		internal.ErrAccountNotFound,
+		internal.ErrNoTrustline,
+	} {
+		p := problem.BadRequest
+		p.Detail = err.Error()
+		problem.RegisterError(err, p)
+	}
+	problem.RegisterError(internal.ErrRateLimited, internal.RateLimitedProblem)
 }
@@ -3,7 +3,9 @@ package actions
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/stellar/go/amount"
@@ -13,6 +15,7 @@ import (
 	horizonProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/services/horizon/internal/simplepath"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
@@ -36,6 +39,12 @@ type StrictReceivePathsQuery struct {
 	DestinationAssetIssuer string `schema:"destination_asset_issuer" valid:"accountID,optional"`
 	DestinationAssetCode   string `schema:"destination_asset_code" valid:"-"`
 	DestinationAmount      string `schema:"destination_amount" valid:"amount"`
+	MaxSplits              uint   `schema:"max_splits" valid:"-"`
+	ExcludedAssets         string `schema:"excluded_assets" valid:"-"`
+	ExcludedIssuers        string `schema:"excluded_issuers" valid:"-"`
+	IntermediateAssets     string `schema:"intermediate_assets" valid:"-"`
+	MaxHops                string `schema:"max_hops" valid:"-"`
+	MaxPrice               string `schema:"max_price" valid:"-"`
 }
 
 // Assets returns a list of xdr.Asset
@@ -98,6 +107,115 @@ func (q StrictReceivePathsQuery) Validate() error {
 		)
 	}
 
+	constraints, err := q.Constraints()
+	if err != nil {
+		return err
+	}
+
+	return validateMaxSplits(q.MaxSplits, constraints)
+}
+
+// Constraints returns the constraints on the payment paths
+func (q StrictReceivePathsQuery) Constraints() (paths.Constraints, error) {
+	constraints, err := parseConstraints(q.ExcludedAssets, q.ExcludedIssuers, q.IntermediateAssets, q.MaxHops)
+	if err != nil {
+		return paths.Constraints{}, err
+	}
+
+	if q.MaxPrice != "" {
+		maxPrice, ok := new(big.Rat).SetString(q.MaxPrice)
+		if !ok || maxPrice.Sign() <= 0 {
+			return paths.Constraints{}, problem.MakeInvalidFieldProblem(
+				"max_price",
+				errors.New("max_price must be a positive number"),
+			)
+		}
+		constraints.MaxPrice = maxPrice
+	}
+	return constraints, nil
+}
+
+// parseConstraints parses the comma separated lists of assets, issuers and
+// hop limits which constrain the payment paths of both path finding end-points
+func parseConstraints(excludedAssets, excludedIssuers, intermediateAssets, maxHops string) (paths.Constraints, error) {
+	var constraints paths.Constraints
+	var err error
+
+	constraints.ExcludedAssets, err = xdr.BuildAssets(excludedAssets)
+	if err != nil {
+		return paths.Constraints{}, problem.MakeInvalidFieldProblem("excluded_assets", err)
+	}
+
+	constraints.IntermediateAssets, err = xdr.BuildAssets(intermediateAssets)
+	if err != nil {
+		return paths.Constraints{}, problem.MakeInvalidFieldProblem("intermediate_assets", err)
+	}
+
+	if excludedIssuers != "" {
+		for _, address := range strings.Split(excludedIssuers, ",") {
+			var issuer xdr.AccountId
+			address = strings.TrimSpace(address)
+			if err = issuer.SetAddress(address); err != nil {
+				return paths.Constraints{}, problem.MakeInvalidFieldProblem(
+					"excluded_issuers",
+					fmt.Errorf("%s is not a valid account id", address),
+				)
+			}
+			constraints.ExcludedIssuers = append(constraints.ExcludedIssuers, issuer)
+		}
+	}
+
+	constraints.MaxHops, err = parseMaxHops(maxHops)
+	if err != nil {
+		return paths.Constraints{}, problem.MakeInvalidFieldProblem("max_hops", err)
+	}
+
+	return constraints, nil
+}
+
+// parseMaxHops parses a comma separated list of hop limits, each of which is
+// an asset followed by a colon and the number of hops, e.g. `native:1`
+func parseMaxHops(s string) ([]paths.AssetHops, error) {
+	var result []paths.AssetHops
+	if s == "" {
+		return result, nil
+	}
+
+	for _, limit := range strings.Split(s, ",") {
+		limit = strings.TrimSpace(limit)
+		i := strings.LastIndex(limit, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("%s is not a valid hop limit", limit)
+		}
+		assets, err := xdr.BuildAssets(limit[:i])
+		if err != nil {
+			return nil, err
+		}
+		if len(assets) != 1 {
+			return nil, fmt.Errorf("%s is not a valid hop limit", limit)
+		}
+		hops, err := strconv.ParseUint(limit[i+1:], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid number of hops", limit[i+1:])
+		}
+		result = append(result, paths.AssetHops{Asset: assets[0], Hops: int(hops)})
+	}
+	return result, nil
+}
+
+func validateMaxSplits(maxSplits uint, constraints paths.Constraints) error {
+	if maxSplits > simplepath.MaxInMemorySplits {
+		return problem.MakeInvalidFieldProblem(
+			"max_splits",
+			fmt.Errorf("max_splits cannot exceed %d", simplepath.MaxInMemorySplits),
+		)
+	}
+	if maxSplits > 1 && !constraints.IsEmpty() {
+		return problem.MakeInvalidFieldProblem(
+			"max_splits",
+			errors.New("path constraints cannot be combined with split payments"),
+		)
+	}
 	return nil
 }
 
@@ -122,6 +240,7 @@ func (handler FindPathsHandler) GetResource(w HeaderWriter, r *http.Request) (in
 
 	query := paths.Query{}
 	query.DestinationAmount = qp.Amount()
+	query.Constraints, _ = qp.Constraints()
 	sourceAccount := qp.SourceAccount
 	query.SourceAssets, _ = qp.Assets()
 
@@ -147,9 +266,18 @@ func (handler FindPathsHandler) GetResource(w HeaderWriter, r *http.Request) (in
 	}
 
 	records := []paths.Path{}
+	splitRecords := []paths.SplitPath{}
 	if len(query.SourceAssets) > 0 {
 		var lastIngestedLedger uint32
-		records, lastIngestedLedger, err = handler.PathFinder.Find(query, handler.MaxPathLength)
+		if qp.MaxSplits > 1 {
+			splitRecords, lastIngestedLedger, err = handler.PathFinder.FindSplitPaths(
+				query,
+				handler.MaxPathLength,
+				qp.MaxSplits,
+			)
+		} else {
+			records, lastIngestedLedger, err = handler.PathFinder.Find(query, handler.MaxPathLength)
+		}
 		if err == simplepath.ErrEmptyInMemoryOrderBook {
 			err = horizonProblem.StillIngesting
 		}
@@ -165,6 +293,9 @@ func (handler FindPathsHandler) GetResource(w HeaderWriter, r *http.Request) (in
 		}
 	}
 
+	if qp.MaxSplits > 1 {
+		return renderSplitPaths(ctx, splitRecords)
+	}
 	return renderPaths(ctx, records)
 }
 
@@ -181,6 +312,19 @@ func renderPaths(ctx context.Context, records []paths.Path) (hal.BasePage, error
 	return page, nil
 }
 
+func renderSplitPaths(ctx context.Context, records []paths.SplitPath) (hal.BasePage, error) {
+	var page hal.BasePage
+	page.Init()
+	for _, p := range records {
+		var res horizon.SplitPath
+		if err := resourceadapter.PopulateSplitPath(ctx, &res, p); err != nil {
+			return hal.BasePage{}, err
+		}
+		page.Add(res)
+	}
+	return page, nil
+}
+
 // FindFixedPathsHandler is the http handler for the find fixed payment paths endpoint
 // Fixed payment paths are payment paths where both the source and destination asset are fixed
 type FindFixedPathsHandler struct {
@@ -207,6 +351,12 @@ type FindFixedPathsQuery struct {
 	SourceAssetIssuer  string `schema:"source_asset_issuer" valid:"accountID,optional"`
 	SourceAssetCode    string `schema:"source_asset_code" valid:"-"`
 	SourceAmount       string `schema:"source_amount" valid:"amount"`
+	MaxSplits          uint   `schema:"max_splits" valid:"-"`
+	ExcludedAssets     string `schema:"excluded_assets" valid:"-"`
+	ExcludedIssuers    string `schema:"excluded_issuers" valid:"-"`
+	IntermediateAssets string `schema:"intermediate_assets" valid:"-"`
+	MaxHops            string `schema:"max_hops" valid:"-"`
+	DestinationMin     string `schema:"destination_min" valid:"amount,optional"`
 }
 
 // URITemplate returns a rfc6570 URI template for the query struct
@@ -240,7 +390,28 @@ func (q FindFixedPathsQuery) Validate() error {
 		)
 	}
 
-	return nil
+	constraints, err := q.Constraints()
+	if err != nil {
+		return err
+	}
+
+	return validateMaxSplits(q.MaxSplits, constraints)
+}
+
+// Constraints returns the constraints on the payment paths
+func (q FindFixedPathsQuery) Constraints() (paths.Constraints, error) {
+	constraints, err := parseConstraints(q.ExcludedAssets, q.ExcludedIssuers, q.IntermediateAssets, q.MaxHops)
+	if err != nil {
+		return paths.Constraints{}, err
+	}
+
+	if q.DestinationMin != "" {
+		constraints.MinDestinationAmount, err = amount.Parse(q.DestinationMin)
+		if err != nil {
+			return paths.Constraints{}, problem.MakeInvalidFieldProblem("destination_min", err)
+		}
+	}
+	return constraints, nil
 }
 
 // Assets returns a list of xdr.Asset
@@ -301,16 +472,29 @@ func (handler FindFixedPathsHandler) GetResource(w HeaderWriter, r *http.Request
 
 	sourceAsset := qp.SourceAsset()
 	amountToSpend := qp.Amount()
+	constraints, _ := qp.Constraints()
 
 	records := []paths.Path{}
+	splitRecords := []paths.SplitPath{}
 	if len(destinationAssets) > 0 {
 		var lastIngestedLedger uint32
-		records, lastIngestedLedger, err = handler.PathFinder.FindFixedPaths(
-			sourceAsset,
-			amountToSpend,
-			destinationAssets,
-			handler.MaxPathLength,
-		)
+		if qp.MaxSplits > 1 {
+			splitRecords, lastIngestedLedger, err = handler.PathFinder.FindFixedSplitPaths(
+				sourceAsset,
+				amountToSpend,
+				destinationAssets,
+				handler.MaxPathLength,
+				qp.MaxSplits,
+			)
+		} else {
+			records, lastIngestedLedger, err = handler.PathFinder.FindFixedPaths(
+				sourceAsset,
+				amountToSpend,
+				destinationAssets,
+				constraints,
+				handler.MaxPathLength,
+			)
+		}
 		if err == simplepath.ErrEmptyInMemoryOrderBook {
 			err = horizonProblem.StillIngesting
 		}
@@ -326,6 +510,9 @@ func (handler FindFixedPathsHandler) GetResource(w HeaderWriter, r *http.Request
 		}
 	}
 
+	if qp.MaxSplits > 1 {
+		return renderSplitPaths(ctx, splitRecords)
+	}
 	return renderPaths(ctx, records)
 }
 
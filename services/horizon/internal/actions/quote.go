@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/paths"
+	horizonProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/services/horizon/internal/simplepath"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// QuoteHandler is the http handler for the quote endpoint
+type QuoteHandler struct {
+	MaxPathLength       uint
+	SetLastLedgerHeader bool
+	PathFinder          paths.Finder
+}
+
+// QuoteQuery query struct for the quote end-point
+type QuoteQuery struct {
+	SourceAssetType        string `schema:"source_asset_type" valid:"assetType"`
+	SourceAssetIssuer      string `schema:"source_asset_issuer" valid:"accountID,optional"`
+	SourceAssetCode        string `schema:"source_asset_code" valid:"-"`
+	SourceAmount           string `schema:"source_amount" valid:"amount"`
+	DestinationAssetType   string `schema:"destination_asset_type" valid:"assetType"`
+	DestinationAssetIssuer string `schema:"destination_asset_issuer" valid:"accountID,optional"`
+	DestinationAssetCode   string `schema:"destination_asset_code" valid:"-"`
+}
+
+// URITemplate returns a rfc6570 URI template for the query struct
+func (q QuoteQuery) URITemplate() string {
+	return "/quote{?" + strings.Join(getURIParams(&q, false), ",") + "}"
+}
+
+// Validate runs custom validations.
+func (q QuoteQuery) Validate() error {
+	err := validateAssetParams(
+		q.SourceAssetType,
+		q.SourceAssetCode,
+		q.SourceAssetIssuer,
+		"source_",
+	)
+	if err != nil {
+		return err
+	}
+
+	err = validateAssetParams(
+		q.DestinationAssetType,
+		q.DestinationAssetCode,
+		q.DestinationAssetIssuer,
+		"destination_",
+	)
+	if err != nil {
+		return err
+	}
+
+	if q.SourceAsset().Equals(q.DestinationAsset()) {
+		return InvalidQuoteAssets
+	}
+
+	return nil
+}
+
+// InvalidQuoteAssets is returned when the source and destination assets of a quote are equal
+var InvalidQuoteAssets = problem.P{
+	Type:   "bad_request",
+	Title:  "Bad Request",
+	Status: http.StatusBadRequest,
+	Detail: "The source and destination assets must be different.",
+}
+
+// Amount returns source amount
+func (q QuoteQuery) Amount() xdr.Int64 {
+	parsed, err := amount.Parse(q.SourceAmount)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SourceAsset returns an xdr.Asset
+func (q QuoteQuery) SourceAsset() xdr.Asset {
+	asset, err := xdr.BuildAsset(
+		q.SourceAssetType,
+		q.SourceAssetIssuer,
+		q.SourceAssetCode,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return asset
+}
+
+// DestinationAsset returns an xdr.Asset
+func (q QuoteQuery) DestinationAsset() xdr.Asset {
+	asset, err := xdr.BuildAsset(
+		q.DestinationAssetType,
+		q.DestinationAssetIssuer,
+		q.DestinationAssetCode,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return asset
+}
+
+// GetResource returns the quote for selling the source amount for the destination asset
+func (handler QuoteHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	qp := QuoteQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	quote, lastIngestedLedger, err := handler.PathFinder.Quote(
+		qp.SourceAsset(),
+		qp.Amount(),
+		qp.DestinationAsset(),
+		handler.MaxPathLength,
+	)
+	if err == simplepath.ErrEmptyInMemoryOrderBook {
+		err = horizonProblem.StillIngesting
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if handler.SetLastLedgerHeader {
+		// To make the Last-Ledger header consistent with the response content,
+		// we need to extract it from the ledger and not the DB.
+		// Thus, we overwrite the header if it was previously set.
+		SetLastLedgerHeader(w, lastIngestedLedger)
+	}
+
+	if quote == nil {
+		return nil, problem.NotFound
+	}
+
+	var res horizon.Quote
+	if err = resourceadapter.PopulateQuote(r.Context(), &res, *quote); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
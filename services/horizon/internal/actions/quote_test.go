@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/keypair"
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/services/horizon/internal/simplepath"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteGetResource(t *testing.T) {
+	issuer := keypair.MustRandom().Address()
+	seller := xdr.MustAddress(keypair.MustRandom().Address())
+	usd := xdr.MustNewCreditAsset("USD", issuer)
+	native := xdr.MustNewNativeAsset()
+
+	graph := orderbook.NewOrderBookGraph()
+	handler := QuoteHandler{
+		MaxPathLength:       3,
+		SetLastLedgerHeader: true,
+		PathFinder:          simplepath.NewInMemoryFinder(graph),
+	}
+	params := map[string]string{
+		"source_asset_type":        "native",
+		"source_amount":            "10",
+		"destination_asset_type":   "credit_alphanum4",
+		"destination_asset_code":   "USD",
+		"destination_asset_issuer": issuer,
+	}
+
+	_, err := handler.GetResource(httptest.NewRecorder(), makeRequest(t, params, map[string]string{}, nil))
+	assert.Equal(t, horizonProblem.StillIngesting, err)
+
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: seller,
+		OfferId:  1,
+		Selling:  usd,
+		Buying:   native,
+		Price:    xdr.Price{N: 1, D: 1},
+		Amount:   50000000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: seller,
+		OfferId:  2,
+		Selling:  usd,
+		Buying:   native,
+		Price:    xdr.Price{N: 2, D: 1},
+		Amount:   1000000000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: seller,
+		OfferId:  3,
+		Selling:  native,
+		Buying:   usd,
+		Price:    xdr.Price{N: 3, D: 2},
+		Amount:   1000000000,
+	})
+	require.NoError(t, graph.Apply(7))
+
+	w := httptest.NewRecorder()
+	response, err := handler.GetResource(w, makeRequest(t, params, map[string]string{}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "7", w.Header().Get(LastLedgerHeaderName))
+
+	quote := response.(protocol.Quote)
+	assert.Equal(t, "10.0000000", quote.SourceAmount)
+	assert.Equal(t, "7.5000000", quote.DestinationAmount)
+	assert.Empty(t, quote.Path)
+	assert.Equal(t, "1.0000000", quote.BestPrice)
+	assert.Equal(t, "0.7500000", quote.AveragePrice)
+	assert.Equal(t, "0.5000000", quote.WorstPrice)
+	assert.Equal(t, "1.2500000", quote.MidPrice)
+	assert.Equal(t, "0.4000000", quote.PriceImpact)
+	assert.Equal(t, "0.2500000", quote.Slippage)
+	require.Len(t, quote.Offers, 2)
+	assert.Equal(t, protocol.QuotedOffer{
+		ID:      2,
+		Seller:  seller.Address(),
+		Selling: protocol.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer},
+		Buying:  protocol.Asset{Type: "native"},
+		PriceR:  protocol.Price{N: 2, D: 1},
+		Price:   "2.0000000",
+		Sold:    "2.5000000",
+		Bought:  "5.0000000",
+	}, quote.Offers[1])
+
+	params["destination_asset_code"] = "EUR"
+	w = httptest.NewRecorder()
+	_, err = handler.GetResource(w, makeRequest(t, params, map[string]string{}, nil))
+	assert.Equal(t, problem.NotFound, err)
+	assert.Equal(t, "7", w.Header().Get(LastLedgerHeaderName))
+
+	params["destination_asset_type"] = "native"
+	delete(params, "destination_asset_code")
+	delete(params, "destination_asset_issuer")
+	_, err = handler.GetResource(httptest.NewRecorder(), makeRequest(t, params, map[string]string{}, nil))
+	assert.Equal(t, InvalidQuoteAssets, err)
+}
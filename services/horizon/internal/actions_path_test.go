@@ -83,7 +83,7 @@ func TestPathActionsStillIngesting(t *testing.T) {
 	finder := paths.MockFinder{}
 	finder.On("Find", mock.Anything, uint(3)).
 		Return([]paths.Path{}, uint32(0), simplepath.ErrEmptyInMemoryOrderBook).Times(2)
-	finder.On("FindFixedPaths", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	finder.On("FindFixedPaths", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]paths.Path{}, uint32(0), simplepath.ErrEmptyInMemoryOrderBook).Times(1)
 
 	rh := mockPathFindingClient(
@@ -372,6 +372,21 @@ func TestPathActionsSourceAssetsValidation(t *testing.T) {
 			"SEK:GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
 	)
 
+	invalidMaxPrice, err := url.ParseQuery(
+		missingSourceAccountAndAssets.Encode(),
+	)
+	tt.Assert.NoError(err)
+	invalidMaxPrice.Add("source_assets", "native")
+	invalidMaxPrice.Add("max_price", "-1")
+
+	constraintsWithSplits, err := url.ParseQuery(
+		missingSourceAccountAndAssets.Encode(),
+	)
+	tt.Assert.NoError(err)
+	constraintsWithSplits.Add("source_assets", "native")
+	constraintsWithSplits.Add("excluded_assets", "native")
+	constraintsWithSplits.Add("max_splits", "2")
+
 	for _, testCase := range []struct {
 		name            string
 		q               url.Values
@@ -382,6 +397,22 @@ func TestPathActionsSourceAssetsValidation(t *testing.T) {
 			missingSourceAccountAndAssets,
 			actions.SourceAssetsOrSourceAccountProblem,
 		},
+		{
+			"max_price is not positive",
+			invalidMaxPrice,
+			*problem.MakeInvalidFieldProblem(
+				"max_price",
+				fmt.Errorf("max_price must be a positive number"),
+			),
+		},
+		{
+			"constraints are combined with max_splits",
+			constraintsWithSplits,
+			*problem.MakeInvalidFieldProblem(
+				"max_splits",
+				fmt.Errorf("path constraints cannot be combined with split payments"),
+			),
+		},
 		{
 			"both destination asset and destination account are present",
 			sourceAccountAndAssets,
@@ -455,6 +486,20 @@ func TestPathActionsDestinationAssetsValidation(t *testing.T) {
 			"SEK:GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
 	)
 
+	invalidExcludedIssuers, err := url.ParseQuery(
+		missingDestinationAccountAndAssets.Encode(),
+	)
+	tt.Assert.NoError(err)
+	invalidExcludedIssuers.Add("destination_assets", "native")
+	invalidExcludedIssuers.Add("excluded_issuers", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN,invalid")
+
+	invalidMaxHops, err := url.ParseQuery(
+		missingDestinationAccountAndAssets.Encode(),
+	)
+	tt.Assert.NoError(err)
+	invalidMaxHops.Add("destination_assets", "native")
+	invalidMaxHops.Add("max_hops", "native:x")
+
 	for _, testCase := range []struct {
 		name            string
 		q               url.Values
@@ -465,6 +510,22 @@ func TestPathActionsDestinationAssetsValidation(t *testing.T) {
 			missingDestinationAccountAndAssets,
 			actions.DestinationAssetsOrDestinationAccountProblem,
 		},
+		{
+			"excluded_issuers contains an invalid account",
+			invalidExcludedIssuers,
+			*problem.MakeInvalidFieldProblem(
+				"excluded_issuers",
+				fmt.Errorf("invalid is not a valid account id"),
+			),
+		},
+		{
+			"max_hops contains an invalid number of hops",
+			invalidMaxHops,
+			*problem.MakeInvalidFieldProblem(
+				"max_hops",
+				fmt.Errorf("x is not a valid number of hops"),
+			),
+		},
 		{
 			"both destination asset and destination account are present",
 			destinationAccountAndAssets,
@@ -571,7 +632,7 @@ func TestPathActionsStrictSend(t *testing.T) {
 	// withSourceAssetsBalance := true
 	sourceAsset := xdr.MustNewCreditAsset("USD", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
 
-	finder.On("FindFixedPaths", sourceAsset, xdr.Int64(100000000), mock.Anything, uint(3)).Return([]paths.Path{}, uint32(1234), nil).Run(func(args mock.Arguments) {
+	finder.On("FindFixedPaths", sourceAsset, xdr.Int64(100000000), mock.Anything, paths.Constraints{}, uint(3)).Return([]paths.Path{}, uint32(1234), nil).Run(func(args mock.Arguments) {
 		destinationAssets := args.Get(2).([]xdr.Asset)
 		for _, asset := range destinationAssets {
 			var assetType, code, issuer string
@@ -620,6 +681,89 @@ func TestPathActionsStrictSend(t *testing.T) {
 	finder.AssertExpectations(t)
 }
 
+func TestPathActionsSplitPaths(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	assertions := &test.Assertions{tt.Assert}
+
+	native := xdr.MustNewNativeAsset()
+	eur := xdr.MustNewCreditAsset("EUR", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
+	usd := xdr.MustNewCreditAsset("USD", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
+	split := paths.SplitPath{
+		Source:            native,
+		SourceAmount:      150000000,
+		Destination:       eur,
+		DestinationAmount: 100000000,
+		Paths: []paths.Path{
+			{
+				Path:              []xdr.Asset{},
+				Source:            native,
+				SourceAmount:      50000000,
+				Destination:       eur,
+				DestinationAmount: 50000000,
+			},
+			{
+				Path:              []xdr.Asset{usd},
+				Source:            native,
+				SourceAmount:      100000000,
+				Destination:       eur,
+				DestinationAmount: 50000000,
+			},
+		},
+	}
+
+	finder := paths.MockFinder{}
+	finder.On("FindSplitPaths", mock.Anything, uint(3), uint(2)).
+		Return([]paths.SplitPath{split}, uint32(1234), nil).Once()
+	finder.On("FindFixedSplitPaths", eur, xdr.Int64(100000000), []xdr.Asset{native}, uint(3), uint(2)).
+		Return([]paths.SplitPath{}, uint32(1234), nil).Once()
+
+	rh := mockPathFindingClient(
+		tt,
+		&finder,
+		2,
+		tt.HorizonSession(),
+	)
+
+	var q = make(url.Values)
+	q.Add("source_assets", "native")
+	q.Add("destination_asset_issuer", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
+	q.Add("destination_asset_type", "credit_alphanum4")
+	q.Add("destination_asset_code", "EUR")
+	q.Add("destination_amount", "10")
+	q.Add("max_splits", "2")
+
+	w := rh.Get("/paths/strict-receive?" + q.Encode())
+	assertions.Equal(http.StatusOK, w.Code)
+	assertions.Equal("1234", w.Header().Get(actions.LastLedgerHeaderName))
+	var records []horizon.SplitPath
+	tt.UnmarshalPage(w.Body, &records)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Equal("15.0000000", records[0].SourceAmount)
+	tt.Assert.Equal("10.0000000", records[0].DestinationAmount)
+	tt.Assert.Len(records[0].Paths, 2)
+	tt.Assert.Equal("USD", records[0].Paths[1].Path[0].Code)
+
+	q.Set("max_splits", "6")
+	w = rh.Get("/paths/strict-receive?" + q.Encode())
+	assertions.Equal(http.StatusBadRequest, w.Code)
+
+	q = make(url.Values)
+	q.Add("destination_assets", "native")
+	q.Add("source_asset_issuer", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
+	q.Add("source_asset_type", "credit_alphanum4")
+	q.Add("source_asset_code", "EUR")
+	q.Add("source_amount", "10")
+	q.Add("max_splits", "2")
+
+	w = rh.Get("/paths/strict-send?" + q.Encode())
+	assertions.Equal(http.StatusOK, w.Code)
+	assertions.Equal("1234", w.Header().Get(actions.LastLedgerHeaderName))
+
+	finder.AssertExpectations(t)
+}
+
 func assetsToURLParam(xdrAssets []xdr.Asset) string {
 	var assets []string
 	for _, xdrAsset := range xdrAssets {
@@ -644,12 +788,39 @@ func TestFindFixedPathsQueryQueryURLTemplate(t *testing.T) {
 		"source_asset_issuer",
 		"source_asset_code",
 		"source_amount",
+		"max_splits",
+		"excluded_assets",
+		"excluded_issuers",
+		"intermediate_assets",
+		"max_hops",
+		"destination_min",
 	}
 	expected := "/paths/strict-send{?" + strings.Join(params, ",") + "}"
 	qp := actions.FindFixedPathsQuery{}
 	tt.Equal(expected, qp.URITemplate())
 }
 
+func TestFindFixedPathsQueryMaxHops(t *testing.T) {
+	tt := assert.New(t)
+	usd := xdr.MustNewCreditAsset("USD", "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN")
+
+	qp := actions.FindFixedPathsQuery{
+		MaxHops: "native:1, USD:GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN:2",
+	}
+	constraints, err := qp.Constraints()
+	tt.NoError(err)
+	tt.Equal([]paths.AssetHops{
+		{Asset: xdr.MustNewNativeAsset(), Hops: 1},
+		{Asset: usd, Hops: 2},
+	}, constraints.MaxHops)
+
+	for _, maxHops := range []string{"native", "native:-1", ":1", "EUR:2"} {
+		qp.MaxHops = maxHops
+		_, err = qp.Constraints()
+		tt.Error(err, maxHops)
+	}
+}
+
 func TestStrictReceivePathsQueryURLTemplate(t *testing.T) {
 	tt := assert.New(t)
 	params := []string{
@@ -660,6 +831,12 @@ func TestStrictReceivePathsQueryURLTemplate(t *testing.T) {
 		"destination_asset_issuer",
 		"destination_asset_code",
 		"destination_amount",
+		"max_splits",
+		"excluded_assets",
+		"excluded_issuers",
+		"intermediate_assets",
+		"max_hops",
+		"max_price",
 	}
 	expected := "/paths/strict-receive{?" + strings.Join(params, ",") + "}"
 	qp := actions.StrictReceivePathsQuery{}
@@ -61,6 +61,12 @@ func TestRootAction(t *testing.T) {
 			"source_asset_issuer",
 			"source_asset_code",
 			"source_amount",
+			"max_splits",
+			"excluded_assets",
+			"excluded_issuers",
+			"intermediate_assets",
+			"max_hops",
+			"destination_min",
 		}
 
 		ht.Assert.Equal(
@@ -76,6 +82,12 @@ func TestRootAction(t *testing.T) {
 			"destination_asset_issuer",
 			"destination_asset_code",
 			"destination_amount",
+			"max_splits",
+			"excluded_assets",
+			"excluded_issuers",
+			"intermediate_assets",
+			"max_hops",
+			"max_price",
 		}
 
 		ht.Assert.Equal(
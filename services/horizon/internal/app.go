@@ -112,7 +112,9 @@ func (a *App) Serve() {
 	}
 
 	go a.run()
-	go a.orderBookStream.Run(a.ctx)
+	if a.orderBookStream != nil {
+		go a.orderBookStream.Run(a.ctx)
+	}
 
 	// WaitGroup for all go routines. Makes sure that DB is closed when
 	// all services gracefully shutdown.
@@ -40,6 +40,13 @@ type Config struct {
 	SentryDSN         string
 	LogglyToken       string
 	LogglyTag         string
+	// OrderBookSnapshotPath is the file where the in memory order book graph
+	// is persisted so that it does not need to be rebuilt on startup.
+	OrderBookSnapshotPath string
+	// PathFindingServerURL is the URL of a standalone path finding server.
+	// If set, path finding queries are delegated to it instead of being
+	// served from an in memory order book.
+	PathFindingServerURL string
 	// TLSCert is a path to a certificate file to use for horizon's TLS config
 	TLSCert string
 	// TLSKey is the path to a private key file to use for horizon's TLS config
@@ -260,6 +260,18 @@ func Flags() (*Config, support.ConfigOptions) {
 			FlagDefault: uint(3),
 			Usage:       "the maximum number of assets on the path in `/paths` endpoint, warning: increasing this value will increase /paths response time",
 		},
+		&support.ConfigOption{
+			Name:      "order-book-snapshot-path",
+			ConfigKey: &config.OrderBookSnapshotPath,
+			OptType:   types.String,
+			Usage:     "file where the in memory order book used for path finding is persisted, if set the order book is loaded from it on startup instead of being rebuilt from all offers in the DB",
+		},
+		&support.ConfigOption{
+			Name:      "path-finding-server-url",
+			ConfigKey: &config.PathFindingServerURL,
+			OptType:   types.String,
+			Usage:     "URL of a standalone path finding server, if set path finding queries are sent to it and Horizon does not keep an in memory order book",
+		},
 		&support.ConfigOption{
 			Name:      "network-passphrase",
 			ConfigKey: &config.NetworkPassphrase,
@@ -172,6 +172,11 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 		r.Method(http.MethodGet, "/paths", findPaths)
 		r.Method(http.MethodGet, "/paths/strict-receive", findPaths)
 		r.Method(http.MethodGet, "/paths/strict-send", findFixedPaths)
+		r.Method(http.MethodGet, "/quote", ObjectActionHandler{actions.QuoteHandler{
+			MaxPathLength:       config.MaxPathLength,
+			SetLastLedgerHeader: true,
+			PathFinder:          config.PathFinder,
+		}})
 
 		r.Method(
 			http.MethodGet,
@@ -1,9 +1,11 @@
 package ingest
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"math/rand"
+	"os"
 	"sort"
 	"time"
 
@@ -32,8 +34,13 @@ type OrderBookStream struct {
 	// LatestLedgerGauge exposes the local (order book graph)
 	// latest processed ledger
 	LatestLedgerGauge prometheus.Gauge
+	// SnapshotPath, if not empty, is the file where the order book graph is
+	// persisted. On startup the graph is loaded from the snapshot and caught
+	// up with the offers updated since then, instead of loading all offers.
+	SnapshotPath      string
 	lastLedger        uint32
 	lastVerification  time.Time
+	snapshotAttempted bool
 }
 
 // NewOrderBookStream constructs and initializes an OrderBookStream instance
@@ -48,6 +55,21 @@ func NewOrderBookStream(historyQ history.IngestionQ, graph orderbook.OBGraph) *O
 	}
 }
 
+// updateResult describes how update brought the order book graph up to date.
+type updateResult int
+
+const (
+	// graphUpdated means the graph was caught up with the offers updated
+	// since its last ledger, if any.
+	graphUpdated updateResult = iota
+	// graphReset means the graph was cleared and, unless ingestion is not
+	// ready yet, rebuilt from all the offers in the Horizon DB.
+	graphReset
+	// graphLoadedFromSnapshot means the graph was cleared, loaded from the
+	// snapshot and caught up with the offers updated since the snapshot.
+	graphLoadedFromSnapshot
+)
+
 type ingestionStatus struct {
 	HistoryConsistentWithState bool
 	StateInvalid               bool
@@ -102,8 +124,9 @@ func addOfferToGraph(graph orderbook.OBGraph, offer history.Offer) {
 	})
 }
 
-// update returns true if the order book graph was reset
-func (o *OrderBookStream) update(status ingestionStatus) (bool, error) {
+// update brings the order book graph up to date with the Horizon DB and
+// returns how it did so
+func (o *OrderBookStream) update(status ingestionStatus) (updateResult, error) {
 	reset := o.lastLedger == 0
 	if status.StateInvalid {
 		log.WithField("status", status).Warn("ingestion state is invalid")
@@ -124,44 +147,52 @@ func (o *OrderBookStream) update(status ingestionStatus) (bool, error) {
 		reset = true
 	}
 
+	result := graphUpdated
 	if reset {
+		result = graphReset
 		o.graph.Clear()
 		o.lastLedger = 0
 
 		// wait until offers in horizon db is valid before populating order book graph
 		if status.StateInvalid || !status.HistoryConsistentWithState {
-			return true, nil
+			return result, nil
 		}
 
-		defer o.graph.Discard()
+		// when the graph is loaded from a snapshot it only needs to be
+		// caught up with the offers updated since the snapshot ledger
+		if o.loadSnapshot(status) {
+			result = graphLoadedFromSnapshot
+		} else {
+			defer o.graph.Discard()
 
-		offers, err := o.historyQ.GetAllOffers()
-		if err != nil {
-			return true, errors.Wrap(err, "Error from GetAllOffers")
-		}
+			offers, err := o.historyQ.GetAllOffers()
+			if err != nil {
+				return result, errors.Wrap(err, "Error from GetAllOffers")
+			}
 
-		for _, offer := range offers {
-			addOfferToGraph(o.graph, offer)
-		}
+			for _, offer := range offers {
+				addOfferToGraph(o.graph, offer)
+			}
 
-		if err := o.graph.Apply(status.LastIngestedLedger); err != nil {
-			return true, errors.Wrap(err, "Error applying changes to order book")
-		}
+			if err := o.graph.Apply(status.LastIngestedLedger); err != nil {
+				return result, errors.Wrap(err, "Error applying changes to order book")
+			}
 
-		o.lastLedger = status.LastIngestedLedger
-		o.LatestLedgerGauge.Set(float64(status.LastIngestedLedger))
-		return true, nil
+			o.lastLedger = status.LastIngestedLedger
+			o.LatestLedgerGauge.Set(float64(status.LastIngestedLedger))
+			return result, nil
+		}
 	}
 
 	if status.LastIngestedLedger == o.lastLedger {
-		return false, nil
+		return result, nil
 	}
 
 	defer o.graph.Discard()
 
 	offers, err := o.historyQ.GetUpdatedOffers(o.lastLedger)
 	if err != nil {
-		return false, errors.Wrap(err, "Error from GetUpdatedOffers")
+		return result, errors.Wrap(err, "Error from GetUpdatedOffers")
 	}
 	for _, offer := range offers {
 		if offer.Deleted {
@@ -172,12 +203,101 @@ func (o *OrderBookStream) update(status ingestionStatus) (bool, error) {
 	}
 
 	if err = o.graph.Apply(status.LastIngestedLedger); err != nil {
-		return false, errors.Wrap(err, "Error applying changes to order book")
+		return result, errors.Wrap(err, "Error applying changes to order book")
 	}
 
 	o.lastLedger = status.LastIngestedLedger
 	o.LatestLedgerGauge.Set(float64(status.LastIngestedLedger))
-	return false, nil
+	return result, nil
+}
+
+// loadSnapshot populates the empty order book graph from the snapshot at
+// SnapshotPath and returns true if it succeeds. The snapshot is only used
+// once, on startup, and is ignored when it is corrupt or when the offers
+// updated since its ledger cannot be obtained from the Horizon DB.
+func (o *OrderBookStream) loadSnapshot(status ingestionStatus) bool {
+	if o.SnapshotPath == "" || o.snapshotAttempted {
+		return false
+	}
+	o.snapshotAttempted = true
+
+	file, err := os.Open(o.SnapshotPath)
+	if os.IsNotExist(err) {
+		return false
+	} else if err != nil {
+		log.WithError(err).Warn("could not open order book snapshot")
+		return false
+	}
+	defer file.Close()
+
+	snapshotLedger, offers, err := orderbook.ReadSnapshot(bufio.NewReader(file))
+	if err != nil {
+		log.WithError(err).Warn("could not read order book snapshot")
+		return false
+	}
+	// offers removed before the last offer compaction ledger are no longer
+	// present in the Horizon DB so the snapshot cannot be caught up
+	if snapshotLedger == 0 ||
+		snapshotLedger > status.LastIngestedLedger ||
+		snapshotLedger < status.LastOfferCompactionLedger {
+		log.WithField("status", status).
+			WithField("snapshot_ledger", snapshotLedger).
+			Info("order book snapshot is stale")
+		return false
+	}
+
+	defer o.graph.Discard()
+	for _, offer := range offers {
+		o.graph.AddOffer(offer)
+	}
+	if err := o.graph.Apply(snapshotLedger); err != nil {
+		log.WithError(err).Warn("could not apply offers from order book snapshot")
+		o.graph.Clear()
+		return false
+	}
+
+	log.WithField("snapshot_ledger", snapshotLedger).
+		WithField("offers", len(offers)).
+		Info("loaded order book snapshot")
+	o.lastLedger = snapshotLedger
+	o.LatestLedgerGauge.Set(float64(snapshotLedger))
+	return true
+}
+
+// writeSnapshot persists the order book graph to SnapshotPath. The snapshot
+// is written to a temporary file first so that a crash cannot leave a
+// partially written snapshot behind.
+func (o *OrderBookStream) writeSnapshot() error {
+	if o.SnapshotPath == "" || o.lastLedger == 0 {
+		return nil
+	}
+
+	tmpPath := o.SnapshotPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrap(err, "could not create order book snapshot")
+	}
+	defer os.Remove(tmpPath)
+
+	writer := bufio.NewWriter(file)
+	err = orderbook.WriteSnapshot(writer, o.lastLedger, o.graph.Offers())
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not write order book snapshot")
+	}
+
+	if err = os.Rename(tmpPath, o.SnapshotPath); err != nil {
+		return errors.Wrap(err, "could not replace order book snapshot")
+	}
+	return nil
 }
 
 func (o *OrderBookStream) verifyAllOffers() {
@@ -224,6 +344,10 @@ func (o *OrderBookStream) verifyAllOffers() {
 		o.lastLedger = 0
 	} else {
 		log.Info("order book stream verification succeeded")
+		// the graph is known to be correct so it is a good time to persist it
+		if err := o.writeSnapshot(); err != nil {
+			log.WithError(err).Warn("could not persist order book graph")
+		}
 	}
 }
 
@@ -242,9 +366,18 @@ func (o *OrderBookStream) Update() error {
 		return errors.Wrap(err, "Error obtaining ingestion status")
 	}
 
-	if reset, err := o.update(status); err != nil {
+	if result, err := o.update(status); err != nil {
 		return errors.Wrap(err, "Error updating")
-	} else if reset {
+	} else if result == graphReset {
+		// persist the rebuilt graph so that the next startup can avoid
+		// loading all offers again
+		if err := o.writeSnapshot(); err != nil {
+			log.WithError(err).Warn("could not persist order book graph")
+		}
+		return nil
+	} else if result == graphLoadedFromSnapshot {
+		// the graph was just read from the snapshot, which is rewritten
+		// the next time the graph is verified
 		return nil
 	}
 
@@ -273,6 +406,9 @@ func (o *OrderBookStream) Run(ctx context.Context) {
 			}
 		case <-ctx.Done():
 			log.Info("shutting down OrderBookStream")
+			if err := o.writeSnapshot(); err != nil {
+				log.WithError(err).Warn("could not persist order book graph")
+			}
 			return
 		}
 	}
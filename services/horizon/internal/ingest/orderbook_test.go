@@ -3,9 +3,13 @@ package ingest
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/stellar/go/exp/orderbook"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/suite"
@@ -285,10 +289,10 @@ func (t *UpdateOrderBookStreamTestSuite) TestFirstUpdateSucceeds() {
 	}
 	t.mockReset(status)
 
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(201), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestInvalidState() {
@@ -300,19 +304,19 @@ func (t *UpdateOrderBookStreamTestSuite) TestInvalidState() {
 	}
 	t.graph.On("Clear").Return().Once()
 
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(0), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 
 	t.stream.lastLedger = 123
 
 	t.graph.On("Clear").Return().Once()
 
-	reset, err = t.stream.update(status)
+	result, err = t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(0), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestHistoryInconsistentWithState() {
@@ -324,19 +328,19 @@ func (t *UpdateOrderBookStreamTestSuite) TestHistoryInconsistentWithState() {
 	}
 	t.graph.On("Clear").Return().Once()
 
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(0), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 
 	t.stream.lastLedger = 123
 
 	t.graph.On("Clear").Return().Once()
 
-	reset, err = t.stream.update(status)
+	result, err = t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(0), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestLastIngestedLedgerBehindStream() {
@@ -349,10 +353,10 @@ func (t *UpdateOrderBookStreamTestSuite) TestLastIngestedLedgerBehindStream() {
 	t.mockReset(status)
 
 	t.stream.lastLedger = 300
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(201), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestStreamBehindLastCompactionLedger() {
@@ -365,10 +369,10 @@ func (t *UpdateOrderBookStreamTestSuite) TestStreamBehindLastCompactionLedger()
 	t.mockReset(status)
 
 	t.stream.lastLedger = 99
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(201), t.stream.lastLedger)
-	t.Assert().True(reset)
+	t.Assert().Equal(graphReset, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestStreamLedgerEqualsLastIngestedLedger() {
@@ -380,10 +384,10 @@ func (t *UpdateOrderBookStreamTestSuite) TestStreamLedgerEqualsLastIngestedLedge
 	}
 
 	t.stream.lastLedger = 201
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(uint32(201), t.stream.lastLedger)
-	t.Assert().False(reset)
+	t.Assert().Equal(graphUpdated, result)
 }
 
 func (t *UpdateOrderBookStreamTestSuite) TestGetUpdatedOffersError() {
@@ -464,10 +468,117 @@ func (t *UpdateOrderBookStreamTestSuite) TestApplyUpdatesSucceeds() {
 		Return(nil).
 		Once()
 
-	reset, err := t.stream.update(status)
+	result, err := t.stream.update(status)
 	t.Assert().NoError(err)
 	t.Assert().Equal(status.LastIngestedLedger, t.stream.lastLedger)
-	t.Assert().False(reset)
+	t.Assert().Equal(graphUpdated, result)
+}
+
+func (t *UpdateOrderBookStreamTestSuite) writeSnapshot(lastLedger uint32, offers []xdr.OfferEntry) {
+	dir, err := ioutil.TempDir("", "orderbook")
+	t.Require().NoError(err)
+	t.stream.SnapshotPath = filepath.Join(dir, "snapshot")
+
+	file, err := os.Create(t.stream.SnapshotPath)
+	t.Require().NoError(err)
+	t.Require().NoError(orderbook.WriteSnapshot(file, lastLedger, offers))
+	t.Require().NoError(file.Close())
+}
+
+func (t *UpdateOrderBookStreamTestSuite) TestLoadSnapshot() {
+	status := ingestionStatus{
+		HistoryConsistentWithState: true,
+		StateInvalid:               false,
+		LastIngestedLedger:         201,
+		LastOfferCompactionLedger:  100,
+	}
+	snapshotOffer := xdr.OfferEntry{
+		SellerId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+		OfferId:  5,
+	}
+	t.writeSnapshot(100, []xdr.OfferEntry{snapshotOffer})
+	defer os.RemoveAll(filepath.Dir(t.stream.SnapshotPath))
+
+	t.graph.On("Clear").Return().Once()
+	t.graph.On("Discard").Return().Once()
+	t.graph.On("AddOffer", snapshotOffer).Return().Once()
+	t.graph.On("Apply", uint32(100)).Return(nil).Once()
+	// catch up from the snapshot ledger
+	t.mockUpdate()
+	t.graph.On("Apply", status.LastIngestedLedger).Return(nil).Once()
+
+	t.stream.lastLedger = 0
+	result, err := t.stream.update(status)
+	t.Assert().NoError(err)
+	t.Assert().Equal(status.LastIngestedLedger, t.stream.lastLedger)
+	t.Assert().Equal(graphLoadedFromSnapshot, result)
+
+	// the snapshot is only used on startup
+	t.mockReset(status)
+	t.stream.lastLedger = 0
+	result, err = t.stream.update(status)
+	t.Assert().NoError(err)
+	t.Assert().Equal(status.LastIngestedLedger, t.stream.lastLedger)
+	t.Assert().Equal(graphReset, result)
+}
+
+func (t *UpdateOrderBookStreamTestSuite) TestStaleSnapshot() {
+	status := ingestionStatus{
+		HistoryConsistentWithState: true,
+		StateInvalid:               false,
+		LastIngestedLedger:         201,
+		LastOfferCompactionLedger:  100,
+	}
+	t.writeSnapshot(99, nil)
+	defer os.RemoveAll(filepath.Dir(t.stream.SnapshotPath))
+
+	t.mockReset(status)
+	result, err := t.stream.update(status)
+	t.Assert().NoError(err)
+	t.Assert().Equal(status.LastIngestedLedger, t.stream.lastLedger)
+	t.Assert().Equal(graphReset, result)
+}
+
+func (t *UpdateOrderBookStreamTestSuite) TestCorruptSnapshot() {
+	status := ingestionStatus{
+		HistoryConsistentWithState: true,
+		StateInvalid:               false,
+		LastIngestedLedger:         201,
+		LastOfferCompactionLedger:  100,
+	}
+	t.writeSnapshot(150, nil)
+	defer os.RemoveAll(filepath.Dir(t.stream.SnapshotPath))
+	t.Require().NoError(ioutil.WriteFile(t.stream.SnapshotPath, []byte("corrupt"), 0600))
+
+	t.mockReset(status)
+	result, err := t.stream.update(status)
+	t.Assert().NoError(err)
+	t.Assert().Equal(status.LastIngestedLedger, t.stream.lastLedger)
+	t.Assert().Equal(graphReset, result)
+}
+
+func (t *UpdateOrderBookStreamTestSuite) TestWriteSnapshot() {
+	offers := []xdr.OfferEntry{{
+		SellerId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+		OfferId:  5,
+		Amount:   10,
+	}}
+	t.writeSnapshot(1, nil)
+	defer os.RemoveAll(filepath.Dir(t.stream.SnapshotPath))
+
+	t.graph.On("Offers").Return(offers).Once()
+	t.stream.lastLedger = 201
+	t.Assert().NoError(t.stream.writeSnapshot())
+
+	file, err := os.Open(t.stream.SnapshotPath)
+	t.Require().NoError(err)
+	defer file.Close()
+	lastLedger, loaded, err := orderbook.ReadSnapshot(file)
+	t.Assert().NoError(err)
+	t.Assert().Equal(uint32(201), lastLedger)
+	t.Assert().Equal(offers, loaded)
+	_, err = os.Stat(t.stream.SnapshotPath + ".tmp")
+	t.Assert().True(os.IsNotExist(err))
 }
 
 type VerifyOrderBookStreamTestSuite struct {
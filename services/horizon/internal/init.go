@@ -80,11 +80,21 @@ func initExpIngester(app *App) {
 }
 
 func initPathFinder(app *App) {
+	if app.config.PathFindingServerURL != "" {
+		finder, err := simplepath.NewRemoteFinder(app.config.PathFindingServerURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		app.paths = finder
+		return
+	}
+
 	orderBookGraph := orderbook.NewOrderBookGraph()
 	app.orderBookStream = ingest.NewOrderBookStream(
 		&history.Q{app.HorizonSession(app.ctx)},
 		orderBookGraph,
 	)
+	app.orderBookStream.SnapshotPath = app.config.OrderBookSnapshotPath
 
 	app.paths = simplepath.NewInMemoryFinder(orderBookGraph)
 }
@@ -214,7 +224,9 @@ func initDbMetrics(app *App) {
 	)
 	app.prometheusRegistry.MustRegister(app.dbWaitDurationCounter)
 
-	app.prometheusRegistry.MustRegister(app.orderBookStream.LatestLedgerGauge)
+	if app.orderBookStream != nil {
+		app.prometheusRegistry.MustRegister(app.orderBookStream.LatestLedgerGauge)
+	}
 }
 
 // initGoMetrics registers the Go collector provided by prometheus package which
@@ -1,6 +1,8 @@
 package paths
 
 import (
+	"math/big"
+
 	"github.com/stellar/go/xdr"
 )
 
@@ -14,6 +16,43 @@ type Query struct {
 	// which require a source asset amount which exceeds the balance present in `SourceAssetBalances`
 	ValidateSourceBalance bool
 	SourceAccount         *xdr.AccountId
+	Constraints           Constraints
+}
+
+// Constraints restricts the payment paths considered by a path finder.
+// The zero value does not restrict payment paths.
+type Constraints struct {
+	// ExcludedAssets may not appear anywhere on a payment path
+	ExcludedAssets []xdr.Asset
+	// ExcludedIssuers are accounts whose assets may not appear anywhere on a payment path
+	ExcludedIssuers []xdr.AccountId
+	// IntermediateAssets, if not empty, are the only assets which may be interior nodes of a payment path
+	IntermediateAssets []xdr.Asset
+	// MaxPrice, if not nil, is the highest acceptable price of a payment path
+	// in units of the source asset per unit of the destination asset
+	MaxPrice *big.Rat
+	// MinDestinationAmount is the smallest acceptable destination amount of a payment path.
+	// It only applies to FindFixedPaths.
+	MinDestinationAmount xdr.Int64
+	// MaxHops limits the number of order books crossed by the payment paths of
+	// individual assets: the source assets of Find and the destination assets of FindFixedPaths
+	MaxHops []AssetHops
+}
+
+// AssetHops is the maximum number of order books crossed by the payment paths of an asset
+type AssetHops struct {
+	Asset xdr.Asset
+	Hops  int
+}
+
+// IsEmpty returns true if the constraints do not restrict payment paths
+func (c Constraints) IsEmpty() bool {
+	return len(c.ExcludedAssets) == 0 &&
+		len(c.ExcludedIssuers) == 0 &&
+		len(c.IntermediateAssets) == 0 &&
+		c.MaxPrice == nil &&
+		c.MinDestinationAmount == 0 &&
+		len(c.MaxHops) == 0
 }
 
 // Path is the result returned by a path finder and is tied to the DestinationAmount used in the input query
@@ -25,6 +64,41 @@ type Path struct {
 	DestinationAmount xdr.Int64
 }
 
+// SplitPath is a payment divided across several payment paths which are
+// meant to be executed, in order, as operations of a single transaction
+type SplitPath struct {
+	Source            xdr.Asset
+	SourceAmount      xdr.Int64
+	Destination       xdr.Asset
+	DestinationAmount xdr.Int64
+	Paths             []Path
+}
+
+// QuotedOffer is an offer crossed by a Quote along with the amounts it trades
+type QuotedOffer struct {
+	Offer  xdr.OfferEntry
+	Sold   xdr.Int64
+	Bought xdr.Int64
+}
+
+// Quote is the outcome of selling SourceAmount of Source for Destination through
+// the best payment path. Prices are in units of Destination per unit of Source.
+// MidPrice and PriceImpact are nil when a hop of the path has no asks.
+type Quote struct {
+	Path              []xdr.Asset
+	Source            xdr.Asset
+	SourceAmount      xdr.Int64
+	Destination       xdr.Asset
+	DestinationAmount xdr.Int64
+	BestPrice         *big.Rat
+	AveragePrice      *big.Rat
+	WorstPrice        *big.Rat
+	MidPrice          *big.Rat
+	PriceImpact       *big.Rat
+	Slippage          *big.Rat
+	Offers            []QuotedOffer
+}
+
 // Finder finds paths.
 type Finder interface {
 	// Return a list of payment paths and the most recent ledger
@@ -34,11 +108,38 @@ type Finder interface {
 	// FindFixedPaths return a list of payment paths the most recent ledger
 	// Each of the payment paths start by spending `amountToSpend` of `sourceAsset` and end
 	// with delivering a postive amount of `destinationAsset`.
+	// Only payment paths which satisfy `constraints` are returned.
 	// The payment paths are accurate and consistent with the returned ledger sequence number
 	FindFixedPaths(
 		sourceAsset xdr.Asset,
 		amountToSpend xdr.Int64,
 		destinationAssets []xdr.Asset,
+		constraints Constraints,
 		maxLength uint,
 	) ([]Path, uint32, error)
+	// FindSplitPaths is like Find but, for each source asset, divides the
+	// destination amount across at most `maxSplits` payment paths
+	// so that the total source amount is as small as possible.
+	// The constraints of the query are not supported.
+	FindSplitPaths(q Query, maxLength, maxSplits uint) ([]SplitPath, uint32, error)
+	// FindFixedSplitPaths is like FindFixedPaths but, for each destination asset,
+	// divides `amountToSpend` across at most `maxSplits` payment paths
+	// so that the total destination amount is as large as possible.
+	FindFixedSplitPaths(
+		sourceAsset xdr.Asset,
+		amountToSpend xdr.Int64,
+		destinationAssets []xdr.Asset,
+		maxLength uint,
+		maxSplits uint,
+	) ([]SplitPath, uint32, error)
+	// Quote returns the quote for selling `amountToSpend` of `sourceAsset` for
+	// `destinationAsset` through the best payment path of a maximum length `maxLength`,
+	// or nil if `destinationAsset` cannot be reached.
+	// The quote is accurate and consistent with the returned ledger sequence number
+	Quote(
+		sourceAsset xdr.Asset,
+		amountToSpend xdr.Int64,
+		destinationAsset xdr.Asset,
+		maxLength uint,
+	) (*Quote, uint32, error)
 }
@@ -22,9 +22,39 @@ func (m *MockFinder) FindFixedPaths(
 	sourceAsset xdr.Asset,
 	amountToSpend xdr.Int64,
 	destinationAssets []xdr.Asset,
+	constraints Constraints,
 	maxLength uint,
 ) ([]Path, uint32, error) {
-	args := m.Called(sourceAsset, amountToSpend, destinationAssets, maxLength)
+	args := m.Called(sourceAsset, amountToSpend, destinationAssets, constraints, maxLength)
 
 	return args.Get(0).([]Path), args.Get(1).(uint32), args.Error(2)
 }
+
+func (m *MockFinder) FindSplitPaths(q Query, maxLength, maxSplits uint) ([]SplitPath, uint32, error) {
+	args := m.Called(q, maxLength, maxSplits)
+
+	return args.Get(0).([]SplitPath), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockFinder) FindFixedSplitPaths(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxLength uint,
+	maxSplits uint,
+) ([]SplitPath, uint32, error) {
+	args := m.Called(sourceAsset, amountToSpend, destinationAssets, maxLength, maxSplits)
+
+	return args.Get(0).([]SplitPath), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockFinder) Quote(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+	maxLength uint,
+) (*Quote, uint32, error) {
+	args := m.Called(sourceAsset, amountToSpend, destinationAsset, maxLength)
+
+	return args.Get(0).(*Quote), args.Get(1).(uint32), args.Error(2)
+}
@@ -41,3 +41,34 @@ func PopulatePath(ctx context.Context, dest *horizon.Path, p paths.Path) (err er
 	}
 	return
 }
+
+// PopulateSplitPath converts the paths.SplitPath into a SplitPath
+func PopulateSplitPath(ctx context.Context, dest *horizon.SplitPath, p paths.SplitPath) (err error) {
+	dest.DestinationAmount = amount.String(p.DestinationAmount)
+	dest.SourceAmount = amount.String(p.SourceAmount)
+
+	err = p.Source.Extract(
+		&dest.SourceAssetType,
+		&dest.SourceAssetCode,
+		&dest.SourceAssetIssuer)
+	if err != nil {
+		return
+	}
+
+	err = p.Destination.Extract(
+		&dest.DestinationAssetType,
+		&dest.DestinationAssetCode,
+		&dest.DestinationAssetIssuer)
+	if err != nil {
+		return
+	}
+
+	dest.Paths = make([]horizon.Path, len(p.Paths))
+	for i, path := range p.Paths {
+		err = PopulatePath(ctx, &dest.Paths[i], path)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package resourceadapter
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/paths"
+)
+
+// PopulateQuote converts the paths.Quote into a Quote
+func PopulateQuote(ctx context.Context, dest *horizon.Quote, q paths.Quote) (err error) {
+	dest.DestinationAmount = amount.String(q.DestinationAmount)
+	dest.SourceAmount = amount.String(q.SourceAmount)
+
+	err = q.Source.Extract(
+		&dest.SourceAssetType,
+		&dest.SourceAssetCode,
+		&dest.SourceAssetIssuer)
+	if err != nil {
+		return
+	}
+
+	err = q.Destination.Extract(
+		&dest.DestinationAssetType,
+		&dest.DestinationAssetCode,
+		&dest.DestinationAssetIssuer)
+	if err != nil {
+		return
+	}
+
+	dest.Path = make([]horizon.Asset, len(q.Path))
+	for i, a := range q.Path {
+		err = a.Extract(
+			&dest.Path[i].Type,
+			&dest.Path[i].Code,
+			&dest.Path[i].Issuer)
+		if err != nil {
+			return
+		}
+	}
+
+	dest.BestPrice = q.BestPrice.FloatString(7)
+	dest.AveragePrice = q.AveragePrice.FloatString(7)
+	dest.WorstPrice = q.WorstPrice.FloatString(7)
+	dest.Slippage = q.Slippage.FloatString(7)
+	if q.MidPrice != nil {
+		dest.MidPrice = q.MidPrice.FloatString(7)
+	}
+	if q.PriceImpact != nil {
+		dest.PriceImpact = q.PriceImpact.FloatString(7)
+	}
+
+	dest.Offers = make([]horizon.QuotedOffer, len(q.Offers))
+	for i, o := range q.Offers {
+		offer := &dest.Offers[i]
+		offer.ID = int64(o.Offer.OfferId)
+		offer.Seller = o.Offer.SellerId.Address()
+		offer.PriceR.N = int32(o.Offer.Price.N)
+		offer.PriceR.D = int32(o.Offer.Price.D)
+		offer.Price = big.NewRat(int64(o.Offer.Price.N), int64(o.Offer.Price.D)).FloatString(7)
+		offer.Sold = amount.String(o.Sold)
+		offer.Bought = amount.String(o.Bought)
+
+		err = o.Offer.Selling.Extract(&offer.Selling.Type, &offer.Selling.Code, &offer.Selling.Issuer)
+		if err != nil {
+			return
+		}
+		err = o.Offer.Buying.Extract(&offer.Buying.Type, &offer.Buying.Code, &offer.Buying.Issuer)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -11,11 +11,20 @@ const (
 	maxAssetsPerPath = 5
 	// MaxInMemoryPathLength is the maximum path length which can be queried by the InMemoryFinder
 	MaxInMemoryPathLength = 5
+	// MaxInMemorySplits is the maximum number of payment paths a payment can be
+	// split across by the InMemoryFinder
+	MaxInMemorySplits = 5
 )
 
 var (
 	// ErrEmptyInMemoryOrderBook indicates that the in memory order book is not yet populated
 	ErrEmptyInMemoryOrderBook = errors.New("Empty orderbook")
+	// ErrInvalidMaxSplits indicates that the number of payment paths a payment
+	// can be split across is not supported by the InMemoryFinder
+	ErrInvalidMaxSplits = errors.New("invalid value of maxSplits")
+	// ErrUnsupportedConstraints indicates that path constraints were given
+	// to a query which does not support them
+	ErrUnsupportedConstraints = errors.New("path constraints are not supported by split paths")
 )
 
 // InMemoryFinder is an implementation of the path finding interface
@@ -53,18 +62,9 @@ func (finder InMemoryFinder) Find(q paths.Query, maxLength uint) ([]paths.Path,
 		q.SourceAssetBalances,
 		q.ValidateSourceBalance,
 		maxAssetsPerPath,
+		convertConstraints(q.Constraints),
 	)
-	results := make([]paths.Path, len(orderbookPaths))
-	for i, path := range orderbookPaths {
-		results[i] = paths.Path{
-			Path:              path.InteriorNodes,
-			Source:            path.SourceAsset,
-			SourceAmount:      path.SourceAmount,
-			Destination:       path.DestinationAsset,
-			DestinationAmount: path.DestinationAmount,
-		}
-	}
-	return results, lastLedger, err
+	return convertPaths(orderbookPaths), lastLedger, err
 }
 
 // FindFixedPaths returns a list of payment paths where the source and destination
@@ -76,6 +76,7 @@ func (finder InMemoryFinder) FindFixedPaths(
 	sourceAsset xdr.Asset,
 	amountToSpend xdr.Int64,
 	destinationAssets []xdr.Asset,
+	constraints paths.Constraints,
 	maxLength uint,
 ) ([]paths.Path, uint32, error) {
 	if finder.graph.IsEmpty() {
@@ -95,7 +96,94 @@ func (finder InMemoryFinder) FindFixedPaths(
 		amountToSpend,
 		destinationAssets,
 		maxAssetsPerPath,
+		convertConstraints(constraints),
+	)
+	return convertPaths(orderbookPaths), lastLedger, err
+}
+
+// FindSplitPaths implements the path payments finder interface
+func (finder InMemoryFinder) FindSplitPaths(
+	q paths.Query,
+	maxLength uint,
+	maxSplits uint,
+) ([]paths.SplitPath, uint32, error) {
+	if finder.graph.IsEmpty() {
+		return nil, 0, ErrEmptyInMemoryOrderBook
+	}
+
+	if maxLength == 0 {
+		maxLength = MaxInMemoryPathLength
+	}
+	if maxLength > MaxInMemoryPathLength {
+		return nil, 0, errors.New("invalid value of maxLength")
+	}
+	if maxSplits == 0 || maxSplits > MaxInMemorySplits {
+		return nil, 0, ErrInvalidMaxSplits
+	}
+	if !q.Constraints.IsEmpty() {
+		return nil, 0, ErrUnsupportedConstraints
+	}
+
+	splits, lastLedger, err := finder.graph.FindSplitPaths(
+		int(maxLength),
+		q.DestinationAsset,
+		q.DestinationAmount,
+		q.SourceAccount,
+		q.SourceAssets,
+		q.SourceAssetBalances,
+		q.ValidateSourceBalance,
+		int(maxSplits),
+	)
+	return convertSplitPaths(splits), lastLedger, err
+}
+
+// FindFixedSplitPaths implements the path payments finder interface
+func (finder InMemoryFinder) FindFixedSplitPaths(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxLength uint,
+	maxSplits uint,
+) ([]paths.SplitPath, uint32, error) {
+	if finder.graph.IsEmpty() {
+		return nil, 0, ErrEmptyInMemoryOrderBook
+	}
+
+	if maxLength == 0 {
+		maxLength = MaxInMemoryPathLength
+	}
+	if maxLength > MaxInMemoryPathLength {
+		return nil, 0, errors.New("invalid value of maxLength")
+	}
+	if maxSplits == 0 || maxSplits > MaxInMemorySplits {
+		return nil, 0, ErrInvalidMaxSplits
+	}
+
+	splits, lastLedger, err := finder.graph.FindFixedSplitPaths(
+		int(maxLength),
+		sourceAsset,
+		amountToSpend,
+		destinationAssets,
+		int(maxSplits),
 	)
+	return convertSplitPaths(splits), lastLedger, err
+}
+
+func convertConstraints(constraints paths.Constraints) orderbook.PathConstraints {
+	result := orderbook.PathConstraints{
+		ExcludedAssets:       constraints.ExcludedAssets,
+		ExcludedIssuers:      constraints.ExcludedIssuers,
+		IntermediateAssets:   constraints.IntermediateAssets,
+		MaxPrice:             constraints.MaxPrice,
+		MinDestinationAmount: constraints.MinDestinationAmount,
+	}
+	for _, limit := range constraints.MaxHops {
+		result.MaxHops = append(result.MaxHops, orderbook.AssetHops{Asset: limit.Asset, Hops: limit.Hops})
+	}
+	return result
+}
+
+func convertPaths(orderbookPaths []orderbook.Path) []paths.Path {
 	results := make([]paths.Path, len(orderbookPaths))
 	for i, path := range orderbookPaths {
 		results[i] = paths.Path{
@@ -106,5 +194,75 @@ func (finder InMemoryFinder) FindFixedPaths(
 			DestinationAmount: path.DestinationAmount,
 		}
 	}
-	return results, lastLedger, err
+	return results
+}
+
+func convertSplitPaths(splits []orderbook.SplitPath) []paths.SplitPath {
+	results := make([]paths.SplitPath, len(splits))
+	for i, split := range splits {
+		results[i] = paths.SplitPath{
+			Source:            split.SourceAsset,
+			SourceAmount:      split.SourceAmount,
+			Destination:       split.DestinationAsset,
+			DestinationAmount: split.DestinationAmount,
+			Paths:             convertPaths(split.Paths),
+		}
+	}
+	return results
+}
+
+// Quote implements the path payments finder interface
+func (finder InMemoryFinder) Quote(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+	maxLength uint,
+) (*paths.Quote, uint32, error) {
+	if finder.graph.IsEmpty() {
+		return nil, 0, ErrEmptyInMemoryOrderBook
+	}
+
+	if maxLength == 0 {
+		maxLength = MaxInMemoryPathLength
+	}
+	if maxLength > MaxInMemoryPathLength {
+		return nil, 0, errors.New("invalid value of maxLength")
+	}
+
+	quote, lastLedger, err := finder.graph.Quote(
+		int(maxLength),
+		sourceAsset,
+		amountToSpend,
+		destinationAsset,
+	)
+	if err != nil || quote == nil {
+		return nil, lastLedger, err
+	}
+
+	return convertQuote(quote), lastLedger, nil
+}
+
+func convertQuote(quote *orderbook.Quote) *paths.Quote {
+	result := &paths.Quote{
+		Path:              quote.InteriorNodes,
+		Source:            quote.SourceAsset,
+		SourceAmount:      quote.SourceAmount,
+		Destination:       quote.DestinationAsset,
+		DestinationAmount: quote.DestinationAmount,
+		BestPrice:         quote.BestPrice,
+		AveragePrice:      quote.AveragePrice,
+		WorstPrice:        quote.WorstPrice,
+		MidPrice:          quote.MidPrice,
+		PriceImpact:       quote.PriceImpact,
+		Slippage:          quote.Slippage,
+		Offers:            make([]paths.QuotedOffer, len(quote.Offers)),
+	}
+	for i, offer := range quote.Offers {
+		result.Offers[i] = paths.QuotedOffer{
+			Offer:  offer.Offer,
+			Sold:   offer.Sold,
+			Bought: offer.Bought,
+		}
+	}
+	return result
 }
@@ -0,0 +1,182 @@
+package simplepath
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/stellar/go/exp/orderbook/remote"
+	"github.com/stellar/go/services/horizon/internal/paths"
+	"github.com/stellar/go/xdr"
+)
+
+// RemoteFinder is an implementation of the path finding interface
+// which delegates queries to a standalone path finding server
+type RemoteFinder struct {
+	client remote.Client
+}
+
+// NewRemoteFinder constructs a new RemoteFinder instance which queries
+// the path finding server at `serverURL`
+func NewRemoteFinder(serverURL string) (RemoteFinder, error) {
+	client, err := remote.NewClient(serverURL)
+	if err != nil {
+		return RemoteFinder{}, err
+	}
+	return RemoteFinder{client: client}, nil
+}
+
+func validateMaxLength(maxLength uint) (uint, error) {
+	if maxLength == 0 {
+		maxLength = MaxInMemoryPathLength
+	}
+	if maxLength > MaxInMemoryPathLength {
+		return 0, errors.New("invalid value of maxLength")
+	}
+	return maxLength, nil
+}
+
+// remoteError maps remote order book errors to the errors of the InMemoryFinder
+func remoteError(err error) error {
+	if err == remote.ErrOrderBookNotReady {
+		return ErrEmptyInMemoryOrderBook
+	}
+	return err
+}
+
+// Find implements the path payments finder interface
+func (finder RemoteFinder) Find(q paths.Query, maxLength uint) ([]paths.Path, uint32, error) {
+	maxLength, err := validateMaxLength(maxLength)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderbookPaths, lastLedger, err := finder.client.FindPaths(
+		int(maxLength),
+		q.DestinationAsset,
+		q.DestinationAmount,
+		q.SourceAccount,
+		q.SourceAssets,
+		q.SourceAssetBalances,
+		q.ValidateSourceBalance,
+		maxAssetsPerPath,
+		convertConstraints(q.Constraints),
+	)
+	if err != nil {
+		return nil, 0, remoteError(err)
+	}
+	return convertPaths(orderbookPaths), lastLedger, nil
+}
+
+// FindFixedPaths implements the path payments finder interface
+func (finder RemoteFinder) FindFixedPaths(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	constraints paths.Constraints,
+	maxLength uint,
+) ([]paths.Path, uint32, error) {
+	maxLength, err := validateMaxLength(maxLength)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderbookPaths, lastLedger, err := finder.client.FindFixedPaths(
+		int(maxLength),
+		sourceAsset,
+		amountToSpend,
+		destinationAssets,
+		maxAssetsPerPath,
+		convertConstraints(constraints),
+	)
+	if err != nil {
+		return nil, 0, remoteError(err)
+	}
+	return convertPaths(orderbookPaths), lastLedger, nil
+}
+
+// FindSplitPaths implements the path payments finder interface
+func (finder RemoteFinder) FindSplitPaths(
+	q paths.Query,
+	maxLength uint,
+	maxSplits uint,
+) ([]paths.SplitPath, uint32, error) {
+	maxLength, err := validateMaxLength(maxLength)
+	if err != nil {
+		return nil, 0, err
+	}
+	if maxSplits == 0 || maxSplits > MaxInMemorySplits {
+		return nil, 0, ErrInvalidMaxSplits
+	}
+	if !q.Constraints.IsEmpty() {
+		return nil, 0, ErrUnsupportedConstraints
+	}
+
+	splits, lastLedger, err := finder.client.FindSplitPaths(
+		int(maxLength),
+		q.DestinationAsset,
+		q.DestinationAmount,
+		q.SourceAccount,
+		q.SourceAssets,
+		q.SourceAssetBalances,
+		q.ValidateSourceBalance,
+		int(maxSplits),
+	)
+	if err != nil {
+		return nil, 0, remoteError(err)
+	}
+	return convertSplitPaths(splits), lastLedger, nil
+}
+
+// FindFixedSplitPaths implements the path payments finder interface
+func (finder RemoteFinder) FindFixedSplitPaths(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxLength uint,
+	maxSplits uint,
+) ([]paths.SplitPath, uint32, error) {
+	maxLength, err := validateMaxLength(maxLength)
+	if err != nil {
+		return nil, 0, err
+	}
+	if maxSplits == 0 || maxSplits > MaxInMemorySplits {
+		return nil, 0, ErrInvalidMaxSplits
+	}
+
+	splits, lastLedger, err := finder.client.FindFixedSplitPaths(
+		int(maxLength),
+		sourceAsset,
+		amountToSpend,
+		destinationAssets,
+		int(maxSplits),
+	)
+	if err != nil {
+		return nil, 0, remoteError(err)
+	}
+	return convertSplitPaths(splits), lastLedger, nil
+}
+
+// Quote implements the path payments finder interface
+func (finder RemoteFinder) Quote(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAsset xdr.Asset,
+	maxLength uint,
+) (*paths.Quote, uint32, error) {
+	maxLength, err := validateMaxLength(maxLength)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	quote, lastLedger, err := finder.client.Quote(
+		int(maxLength),
+		sourceAsset,
+		amountToSpend,
+		destinationAsset,
+	)
+	if err != nil {
+		return nil, 0, remoteError(err)
+	}
+	if quote == nil {
+		return nil, lastLedger, nil
+	}
+	return convertQuote(quote), lastLedger, nil
+}
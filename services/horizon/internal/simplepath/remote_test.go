@@ -0,0 +1,96 @@
+package simplepath
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/exp/orderbook/remote"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/horizon/internal/paths"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+func TestRemoteFinder(t *testing.T) {
+	issuer := xdr.MustAddress(keypair.MustRandom().Address())
+	nativeAsset := xdr.MustNewNativeAsset()
+	usdAsset := xdr.MustNewCreditAsset("USD", issuer.Address())
+	eurAsset := xdr.MustNewCreditAsset("EUR", issuer.Address())
+
+	graph := orderbook.NewOrderBookGraph()
+	server := httptest.NewServer(remote.Handler(graph, MaxInMemoryPathLength, log.New()))
+	defer server.Close()
+	finder, err := NewRemoteFinder(server.URL)
+	assert.NoError(t, err)
+	inMemory := NewInMemoryFinder(graph)
+
+	_, _, err = finder.FindFixedPaths(nativeAsset, 10, []xdr.Asset{usdAsset}, paths.Constraints{}, 0)
+	assert.Equal(t, ErrEmptyInMemoryOrderBook, err)
+
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  1,
+		Selling:  eurAsset,
+		Buying:   nativeAsset,
+		Price:    xdr.Price{N: 1, D: 1},
+		Amount:   1000,
+	})
+	graph.AddOffer(xdr.OfferEntry{
+		SellerId: issuer,
+		OfferId:  2,
+		Selling:  usdAsset,
+		Buying:   eurAsset,
+		Price:    xdr.Price{N: 2, D: 1},
+		Amount:   1000,
+	})
+	assert.NoError(t, graph.Apply(10))
+
+	query := paths.Query{
+		DestinationAsset:    usdAsset,
+		DestinationAmount:   10,
+		SourceAssets:        []xdr.Asset{nativeAsset},
+		SourceAssetBalances: []xdr.Int64{0},
+	}
+	expectedPaths, expectedLedger, err := inMemory.Find(query, 3)
+	assert.NoError(t, err)
+	remotePaths, lastLedger, err := finder.Find(query, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedLedger, lastLedger)
+	assert.Len(t, remotePaths, 1)
+	assert.Equal(t, expectedPaths, remotePaths)
+
+	expectedPaths, _, err = inMemory.FindFixedPaths(nativeAsset, 20, []xdr.Asset{usdAsset}, paths.Constraints{}, 3)
+	assert.NoError(t, err)
+	remotePaths, _, err = finder.FindFixedPaths(nativeAsset, 20, []xdr.Asset{usdAsset}, paths.Constraints{}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPaths, remotePaths)
+
+	expectedSplits, _, err := inMemory.FindFixedSplitPaths(nativeAsset, 20, []xdr.Asset{usdAsset}, 3, 2)
+	assert.NoError(t, err)
+	remoteSplits, _, err := finder.FindFixedSplitPaths(nativeAsset, 20, []xdr.Asset{usdAsset}, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSplits, remoteSplits)
+
+	_, _, err = finder.FindSplitPaths(query, 3, MaxInMemorySplits+1)
+	assert.Equal(t, ErrInvalidMaxSplits, err)
+
+	constrainedQuery := query
+	constrainedQuery.Constraints = paths.Constraints{ExcludedAssets: []xdr.Asset{eurAsset}}
+	_, _, err = finder.FindSplitPaths(constrainedQuery, 3, 2)
+	assert.Equal(t, ErrUnsupportedConstraints, err)
+	remotePaths, _, err = finder.Find(constrainedQuery, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, remotePaths)
+
+	expectedQuote, _, err := inMemory.Quote(nativeAsset, 20, usdAsset, 3)
+	assert.NoError(t, err)
+	remoteQuote, lastLedger, err := finder.Quote(nativeAsset, 20, usdAsset, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), lastLedger)
+	assert.Equal(t, expectedQuote.DestinationAmount, remoteQuote.DestinationAmount)
+	assert.Equal(t, expectedQuote.AveragePrice.RatString(), remoteQuote.AveragePrice.RatString())
+	assert.Equal(t, expectedQuote.Offers, remoteQuote.Offers)
+}
@@ -2,17 +2,14 @@ package keystore
 
 import (
 	"database/sql"
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net"
 	"net/http"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/rs/cors"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/support/render/problem"
@@ -36,7 +33,10 @@ func (s *Service) wrapMiddleware(handler http.Handler) http.Handler {
 
 func ServeMux(s *Service) http.Handler {
 	mux := http.NewServeMux()
-	mux.Handle("/keys", s.wrapMiddleware(s.keysHTTPMethodHandler()))
+	mux.Handle("/keys", s.wrapMiddleware(s.auditHandler(s.keysHTTPMethodHandler())))
+	mux.Handle("/keys/versions", s.wrapMiddleware(s.auditHandler(methodHandler(http.MethodGet, jsonHandler(s.listKeysVersions)))))
+	mux.Handle("/keys/versions/", s.wrapMiddleware(s.auditHandler(methodHandler(http.MethodGet, s.keysVersionHandler()))))
+	mux.Handle("/keys/restore", s.wrapMiddleware(s.auditHandler(methodHandler(http.MethodPost, s.restoreKeysHandler()))))
 	mux.Handle("/health", s.wrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})))
@@ -47,10 +47,20 @@ func (s *Service) keysHTTPMethodHandler() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		switch req.Method {
 		case http.MethodGet:
-			jsonHandler(s.getKeys).ServeHTTP(rw, req)
+			etagHandler(func(req *http.Request) (*encryptedKeysData, error) {
+				return s.getKeys(req.Context())
+			}).ServeHTTP(rw, req)
 
 		case http.MethodPut:
-			jsonHandler(s.putKeys).ServeHTTP(rw, req)
+			etagHandler(func(req *http.Request) (*encryptedKeysData, error) {
+				var in putKeysRequest
+				err := httpdecode.DecodeJSON(req, &in)
+				if err != nil {
+					return nil, httpjson.ErrBadRequest
+				}
+				in.IfMatch = req.Header.Get("If-Match")
+				return s.putKeys(req.Context(), in)
+			}).ServeHTTP(rw, req)
 
 		case http.MethodDelete:
 			jsonHandler(s.deleteKeys).ServeHTTP(rw, req)
@@ -61,92 +71,99 @@ func (s *Service) keysHTTPMethodHandler() http.Handler {
 	})
 }
 
-type authResponse struct {
-	UserID string `json:"userID"`
-}
-
-var forwardHeaders = map[string]struct{}{
-	"authorization": struct{}{},
-	"cookie":        struct{}{},
-}
-
-func authHandler(next http.Handler, authenticator *Authenticator) http.Handler {
+func (s *Service) keysVersionHandler() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		if authenticator == nil {
-			// to facilitate API testing
-			next.ServeHTTP(rw, req.WithContext(withUserID(req.Context(), "test-user")))
+		ctx := req.Context()
+		version, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/keys/versions/"))
+		if err != nil || version <= 0 {
+			problem.Render(ctx, rw, probInvalidVersion)
 			return
 		}
-
-		var (
-			proxyReq *http.Request
-			err      error
-			clientIP string
-		)
-		ctx := req.Context()
-		// set a 5-second timeout
-		client := http.Client{Timeout: time.Duration(5 * time.Second)}
-
-		switch authenticator.APIType {
-		case REST:
-			proxyReq, err = http.NewRequest("GET", authenticator.URL, nil)
-			if err != nil {
-				problem.Render(ctx, rw, errors.Wrap(err, "creating the auth proxy request"))
-				return
-			}
-
-		case GraphQL:
-			// to be implemented later
-		default:
-			problem.Render(ctx, rw, probNotAuthorized)
+		out, err := s.getKeysVersion(ctx, version)
+		if err != nil {
+			problem.Render(ctx, rw, err)
 			return
 		}
+		httpjson.Render(rw, out, httpjson.JSON)
+	})
+}
 
-		proxyReq.Header = make(http.Header)
-		for k, v := range req.Header {
-			// http headers are case-insensitive
-			// https://www.ietf.org/rfc/rfc2616.txt
-			if _, ok := forwardHeaders[strings.ToLower(k)]; ok {
-				proxyReq.Header[k] = v
-			}
+func (s *Service) restoreKeysHandler() http.Handler {
+	return etagHandler(func(req *http.Request) (*encryptedKeysData, error) {
+		var in restoreKeysRequest
+		err := httpdecode.DecodeJSON(req, &in)
+		if err != nil {
+			return nil, httpjson.ErrBadRequest
 		}
+		in.IfMatch = req.Header.Get("If-Match")
+		return s.restoreKeys(req.Context(), in)
+	})
+}
 
-		if clientIP, _, err = net.SplitHostPort(req.RemoteAddr); err == nil {
-			proxyReq.Header.Set("X-Forwarded-For", clientIP)
+// methodHandler responds with method not allowed to requests that do not use
+// the method.
+func methodHandler(method string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != method {
+			problem.Render(req.Context(), rw, probMethodNotAllowed)
+			return
 		}
-		proxyReq.Header.Set("Accept-Encoding", "identity")
+		next.ServeHTTP(rw, req)
+	})
+}
 
-		resp, err := client.Do(proxyReq)
+// etagHandler renders the keys blob returned by f with an ETag header
+// identifying its version, which clients send in the If-Match header of
+// requests that change the keys blob so that they do not overwrite changes
+// they have not seen.
+func etagHandler(f func(req *http.Request) (*encryptedKeysData, error)) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		out, err := f(req)
 		if err != nil {
-			problem.Render(ctx, rw, errors.Wrap(err, "sending the auth proxy request"))
+			problem.Render(req.Context(), rw, err)
 			return
 		}
-		defer resp.Body.Close()
+		rw.Header().Set("ETag", etag(out.Version))
+		httpjson.Render(rw, out, httpjson.JSON)
+	})
+}
 
-		if resp.StatusCode != http.StatusOK {
-			problem.Render(ctx, rw, probNotAuthorized)
-			return
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// etagMatches returns true if the If-Match header value matches the ETag of
+// the version. A version of zero means there is no keys blob, which only
+// matches if the header lists an ETag for it. Weak ETags never match.
+func etagMatches(ifMatch string, version int) bool {
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" && version > 0 {
+			return true
+		}
+		if tag == etag(version) {
+			return true
 		}
+	}
+	return false
+}
 
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			problem.Render(ctx, rw, errors.Wrap(err, "reading the auth response"))
+func authHandler(next http.Handler, authenticator AuthBackend) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if authenticator == nil {
+			// to facilitate API testing
+			next.ServeHTTP(rw, req.WithContext(withUserID(req.Context(), "test-user")))
 			return
 		}
 
-		var authResp authResponse
-		err = json.Unmarshal(body, &authResp)
+		ctx := req.Context()
+		userID, err := authenticator.Authenticate(req)
 		if err != nil {
-			log.Ctx(ctx).Infof("Response body as a plain string: %s\n. Response body as a hex dump string: %s\n", string(body), hex.Dump(body))
-			problem.Render(ctx, rw, errors.Wrap(err, "unmarshaling the auth response"))
-			return
-		}
-		if authResp.UserID == "" {
-			problem.Render(ctx, rw, probNotAuthorized)
+			problem.Render(ctx, rw, err)
 			return
 		}
 
-		next.ServeHTTP(rw, req.WithContext(withUserID(ctx, authResp.UserID)))
+		next.ServeHTTP(rw, req.WithContext(withUserID(ctx, userID)))
 	})
 }
 
@@ -199,3 +199,89 @@ func TestDeleteKeysAPI(t *testing.T) {
 		t.Errorf("expect the keys blob of the user %s to be deleted", userID(ctx))
 	}
 }
+
+func TestPutKeysAPI_eTag(t *testing.T) {
+	db := openKeystoreDB(t)
+	defer db.Close() // drop test db
+
+	conn := db.Open()
+	defer conn.Close() // close db connection
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"userID":"test-user"}`)
+	}))
+	defer ts.Close()
+
+	s := &Service{
+		db: conn.DB,
+		authenticator: &Authenticator{
+			URL:     ts.URL,
+			APIType: REST,
+		},
+	}
+	h := ServeMux(s)
+
+	blob := `[{
+		"id": "test-id",
+		"salt": "test-salt",
+		"encrypterName": "test-encrypter-name",
+		"encryptedBlob": "test-encryptedblob"
+	}]`
+	keysBlob := base64.RawURLEncoding.EncodeToString([]byte(blob))
+	body, err := json.Marshal(putKeysRequest{KeysBlob: keysBlob})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("PUT", "/keys", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT %s responded with %s, want %s", req.URL, http.StatusText(rr.Code), http.StatusText(http.StatusOK))
+	}
+	if got, want := rr.Header().Get("ETag"), `"1"`; got != want {
+		t.Errorf("got ETag=%s, want ETag=%s", got, want)
+	}
+
+	// A stale If-Match is rejected and does not create a new version.
+	req = httptest.NewRequest("PUT", "/keys", bytes.NewReader(body))
+	req.Header.Set("If-Match", `"0"`)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT %s responded with %s, want %s", req.URL, http.StatusText(rr.Code), http.StatusText(http.StatusPreconditionFailed))
+	}
+
+	req = httptest.NewRequest("PUT", "/keys", bytes.NewReader(body))
+	req.Header.Set("If-Match", `"1"`)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT %s responded with %s, want %s", req.URL, http.StatusText(rr.Code), http.StatusText(http.StatusOK))
+	}
+	if got, want := rr.Header().Get("ETag"), `"2"`; got != want {
+		t.Errorf("got ETag=%s, want ETag=%s", got, want)
+	}
+
+	// Every request is recorded in the audit log.
+	var n int
+	err = conn.DB.QueryRow(`SELECT COUNT(*) FROM keys_audit_log WHERE user_id = 'test-user'`).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("got %d audit log entries, want 3", n)
+	}
+
+	var status int
+	err = conn.DB.QueryRow(`SELECT status FROM keys_audit_log WHERE user_id = 'test-user' AND action = 'PUT /keys' ORDER BY id LIMIT 1 OFFSET 1`).Scan(&status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusPreconditionFailed {
+		t.Errorf("got audit log status %d, want %d", status, http.StatusPreconditionFailed)
+	}
+}
@@ -0,0 +1,51 @@
+package keystore
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/mutil"
+	"github.com/stellar/go/support/log"
+)
+
+// auditHandler records every authenticated request in the audit log with the
+// user, the IP address of the client, the action requested and the status of
+// the response. It must be wrapped by the auth handler so that the user is
+// known.
+func (s *Service) auditHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ww := mutil.WrapWriter(rw)
+		next.ServeHTTP(ww, req)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		clientIP, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			clientIP = req.RemoteAddr
+		}
+		action := req.Method + " " + req.URL.Path
+
+		// The request context may be cancelled once the response is written,
+		// and the access must be recorded regardless.
+		err = s.recordAccess(context.Background(), userID(req.Context()), clientIP, action, status)
+		if err != nil {
+			log.Ctx(req.Context()).WithStack(err).Error(err)
+		}
+	})
+}
+
+func (s *Service) recordAccess(ctx context.Context, userID, clientIP, action string, status int) error {
+	q := `
+		INSERT INTO keys_audit_log (user_id, client_ip, action, status)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := s.db.ExecContext(ctx, q, userID, clientIP, action, status)
+	if err != nil {
+		return errors.Wrap(err, "recording access in audit log")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package keystore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	// AuthForward authenticates requests by forwarding them to a client
+	// configured endpoint, see Authenticator.
+	AuthForward = "FORWARD"
+	// AuthJWT authenticates requests with SEP-10 JWTs, see JWTAuthenticator.
+	AuthJWT = "JWT"
+	// AuthAPIKey authenticates requests with API keys, see
+	// APIKeyAuthenticator.
+	AuthAPIKey = "APIKEY"
+)
+
+// AuthBackend derives the userID of the user making a request. It returns
+// probNotAuthorized if the request is not authorized.
+type AuthBackend interface {
+	Authenticate(req *http.Request) (string, error)
+}
+
+type authResponse struct {
+	UserID string `json:"userID"`
+}
+
+var forwardHeaders = map[string]struct{}{
+	"authorization": struct{}{},
+	"cookie":        struct{}{},
+}
+
+// Authenticate forwards the Authorization and Cookie headers of the request
+// to the auth forwarding endpoint, which responds with the userID.
+func (a *Authenticator) Authenticate(req *http.Request) (string, error) {
+	var (
+		proxyReq *http.Request
+		err      error
+		clientIP string
+	)
+	ctx := req.Context()
+	// set a 5-second timeout
+	client := http.Client{Timeout: time.Duration(5 * time.Second)}
+
+	switch a.APIType {
+	case REST:
+		proxyReq, err = http.NewRequest("GET", a.URL, nil)
+		if err != nil {
+			return "", errors.Wrap(err, "creating the auth proxy request")
+		}
+
+	case GraphQL:
+		// to be implemented later
+		return "", errors.New("auth forwarding to a GraphQL endpoint is not implemented")
+	default:
+		return "", probNotAuthorized
+	}
+
+	proxyReq.Header = make(http.Header)
+	for k, v := range req.Header {
+		// http headers are case-insensitive
+		// https://www.ietf.org/rfc/rfc2616.txt
+		if _, ok := forwardHeaders[strings.ToLower(k)]; ok {
+			proxyReq.Header[k] = v
+		}
+	}
+
+	if clientIP, _, err = net.SplitHostPort(req.RemoteAddr); err == nil {
+		proxyReq.Header.Set("X-Forwarded-For", clientIP)
+	}
+	proxyReq.Header.Set("Accept-Encoding", "identity")
+
+	resp, err := client.Do(proxyReq)
+	if err != nil {
+		return "", errors.Wrap(err, "sending the auth proxy request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", probNotAuthorized
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "reading the auth response")
+	}
+
+	var authResp authResponse
+	err = json.Unmarshal(body, &authResp)
+	if err != nil {
+		log.Ctx(ctx).Infof("Response body as a plain string: %s\n. Response body as a hex dump string: %s\n", string(body), hex.Dump(body))
+		return "", errors.Wrap(err, "unmarshaling the auth response")
+	}
+	if authResp.UserID == "" {
+		return "", probNotAuthorized
+	}
+
+	return authResp.UserID, nil
+}
+
+// CachingAuthenticator caches the userIDs derived by another AuthBackend,
+// such as the forwarding Authenticator, so that repeated requests with the
+// same credentials do not each cost a round trip. Only requests that are
+// authorized are cached.
+type CachingAuthenticator struct {
+	Authenticator AuthBackend
+	TTL           time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedUserID
+}
+
+type cachedUserID struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// Authenticate returns the cached userID for the credentials in the
+// request, or authenticates the request with the wrapped AuthBackend if there
+// is none or it has expired.
+func (a *CachingAuthenticator) Authenticate(req *http.Request) (string, error) {
+	key := credentialsKey(req)
+	now := time.Now()
+
+	a.mu.Lock()
+	c, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok && now.Before(c.expiresAt) {
+		return c.userID, nil
+	}
+
+	userID, err := a.Authenticator.Authenticate(req)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cache == nil {
+		a.cache = map[string]cachedUserID{}
+	}
+	for k, c := range a.cache {
+		if !now.Before(c.expiresAt) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = cachedUserID{userID: userID, expiresAt: now.Add(a.TTL)}
+
+	return userID, nil
+}
+
+// credentialsKey returns a hash of the headers that are forwarded to
+// authenticate a request, so that credentials are not kept in memory.
+func credentialsKey(req *http.Request) string {
+	h := sha256.New()
+	for _, k := range []string{"Authorization", "Cookie"} {
+		for _, v := range req.Header[k] {
+			h.Write([]byte(k + ": " + v + "\n"))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bearerToken returns the token in the Authorization header of the request
+// if it uses the Bearer scheme.
+func bearerToken(req *http.Request) string {
+	const prefix = "bearer "
+	authorization := req.Header.Get("Authorization")
+	if len(authorization) < len(prefix) || strings.ToLower(authorization[:len(prefix)]) != prefix {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(prefix):])
+}
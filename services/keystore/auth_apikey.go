@@ -0,0 +1,55 @@
+package keystore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// APIKeyAuthenticator authenticates requests with static API keys in the
+// Authorization header using the Bearer scheme. An API key is the userID
+// followed by a dot and the base64-URL-encoded HMAC-SHA256 of the userID with
+// the secret, so API keys do not need to be stored and are issued with
+// NewAPIKey by anyone holding the secret.
+type APIKeyAuthenticator struct {
+	Secret []byte
+}
+
+// NewAPIKey returns the API key of the user for the secret.
+func NewAPIKey(secret []byte, userID string) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("secret cannot be empty")
+	}
+	if userID == "" {
+		return "", errors.New("userID cannot be empty")
+	}
+	return userID + "." + base64.RawURLEncoding.EncodeToString(apiKeyMAC(secret, userID)), nil
+}
+
+func apiKeyMAC(secret []byte, userID string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(userID))
+	return mac.Sum(nil)
+}
+
+// Authenticate verifies the API key in the request and returns its userID.
+func (a *APIKeyAuthenticator) Authenticate(req *http.Request) (string, error) {
+	apiKey := bearerToken(req)
+	i := strings.LastIndex(apiKey, ".")
+	if i <= 0 {
+		return "", probNotAuthorized
+	}
+	userID := apiKey[:i]
+	mac, err := base64.RawURLEncoding.DecodeString(apiKey[i+1:])
+	if err != nil {
+		return "", probNotAuthorized
+	}
+	if len(a.Secret) == 0 || !hmac.Equal(mac, apiKeyMAC(a.Secret, userID)) {
+		return "", probNotAuthorized
+	}
+	return userID, nil
+}
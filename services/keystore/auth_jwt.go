@@ -0,0 +1,90 @@
+package keystore
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// JWTAuthenticator authenticates requests with the SEP-10 JWTs issued by a
+// web authentication server, such as exp/services/webauth, in the
+// Authorization header using the Bearer scheme. The JWTs are verified locally
+// with the public keys of the server and the userID is the subject of the
+// JWT, which is the Stellar account that authenticated.
+type JWTAuthenticator struct {
+	// Keys are the keys that JWTs may be signed with. JWTs issued by webauth
+	// do not identify their key, so every key is tried.
+	Keys jose.JSONWebKeySet
+	// Issuer is the issuer JWTs must have. Any issuer is accepted if empty.
+	Issuer string
+}
+
+// ParseJWKS parses a JSON Web Key Set, or a single JSON Web Key such as the
+// JWK webauth is configured with. Private keys are reduced to their public
+// keys. Symmetric keys are rejected, as they have no public key to verify
+// JWTs with.
+func ParseJWKS(s string) (jose.JSONWebKeySet, error) {
+	jwks := jose.JSONWebKeySet{}
+	err := json.Unmarshal([]byte(s), &jwks)
+	if err != nil {
+		return jose.JSONWebKeySet{}, errors.Wrap(err, "parsing JSON Web Key Set")
+	}
+	if len(jwks.Keys) == 0 {
+		jwk := jose.JSONWebKey{}
+		err = json.Unmarshal([]byte(s), &jwk)
+		if err != nil {
+			return jose.JSONWebKeySet{}, errors.Wrap(err, "parsing JSON Web Key")
+		}
+		jwks.Keys = []jose.JSONWebKey{jwk}
+	}
+	for i, k := range jwks.Keys {
+		if _, ok := k.Key.([]byte); ok {
+			return jose.JSONWebKeySet{}, errors.Errorf("JSON Web Key %d is a symmetric key, only asymmetric keys are supported", i)
+		}
+		if !k.Valid() {
+			return jose.JSONWebKeySet{}, errors.Errorf("JSON Web Key %d is not valid", i)
+		}
+		if !k.IsPublic() {
+			jwks.Keys[i] = k.Public()
+		}
+	}
+	return jwks, nil
+}
+
+// Authenticate verifies the JWT in the request and returns its subject.
+func (a *JWTAuthenticator) Authenticate(req *http.Request) (string, error) {
+	token := bearerToken(req)
+	if token == "" {
+		return "", probNotAuthorized
+	}
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return "", probNotAuthorized
+	}
+
+	claims := jwt.Claims{}
+	verified := false
+	for _, k := range a.Keys.Keys {
+		if tok.Claims(k.Key, &claims) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return "", probNotAuthorized
+	}
+
+	err = claims.ValidateWithLeeway(jwt.Expected{
+		Issuer: a.Issuer,
+		Time:   time.Now(),
+	}, 0)
+	if err != nil || claims.Expiry == nil || claims.Subject == "" {
+		return "", probNotAuthorized
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,256 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/render/problem"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func newTestJWK(t *testing.T) jose.JSONWebKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jose.JSONWebKey{Key: k, Algorithm: string(jose.ES256)}
+}
+
+func newTestJWT(t *testing.T, jwk jose.JSONWebKey, claims jwt.Claims) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: jwk.Key}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func isNotAuthorized(err error) bool {
+	p, ok := err.(problem.P)
+	return ok && p.Type == probNotAuthorized.Type
+}
+
+func newAuthRequest(authorization string) *http.Request {
+	req := httptest.NewRequest("GET", "/keys", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return req
+}
+
+func TestParseJWKS(t *testing.T) {
+	jwk := newTestJWK(t)
+
+	// A single private JWK, as webauth is configured with, is reduced to its
+	// public key.
+	b, err := json.Marshal(jwk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := ParseJWKS(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("got %d keys, want 1", len(jwks.Keys))
+	}
+	if !jwks.Keys[0].IsPublic() {
+		t.Error("got a private key, want a public key")
+	}
+
+	otherJWK := newTestJWK(t)
+	b, err = json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{jwk.Public(), otherJWK.Public()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err = ParseJWKS(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("got %d keys, want 2", len(jwks.Keys))
+	}
+
+	_, err = ParseJWKS(`{}`)
+	if err == nil {
+		t.Error("got no error parsing an empty JWK, want an error")
+	}
+	_, err = ParseJWKS(`not json`)
+	if err == nil {
+		t.Error("got no error parsing invalid JSON, want an error")
+	}
+	_, err = ParseJWKS(`{"kty":"oct","alg":"HS256","k":"c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0"}`)
+	if err == nil {
+		t.Error("got no error parsing a symmetric JWK, want an error")
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	jwk := newTestJWK(t)
+	otherJWK := newTestJWK(t)
+	a := &JWTAuthenticator{
+		Keys:   jose.JSONWebKeySet{Keys: []jose.JSONWebKey{otherJWK.Public(), jwk.Public()}},
+		Issuer: "https://example.com/auth",
+	}
+
+	now := time.Now()
+	valid := jwt.Claims{
+		Issuer:   "https://example.com/auth",
+		Subject:  "GA6UIXXPEWYFILNUIWAC37Y4QPEZMQVDJHDKVWFZJ2KCWUBIU5IXZNDA",
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+
+	userID, err := a.Authenticate(newAuthRequest("Bearer " + newTestJWT(t, jwk, valid)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != valid.Subject {
+		t.Errorf("got userID %s, want %s", userID, valid.Subject)
+	}
+
+	expired := valid
+	expired.Expiry = jwt.NewNumericDate(now.Add(-time.Minute))
+	wrongIssuer := valid
+	wrongIssuer.Issuer = "https://example.org/auth"
+	noExpiry := valid
+	noExpiry.Expiry = nil
+	noSubject := valid
+	noSubject.Subject = ""
+
+	testCases := []struct {
+		name          string
+		authorization string
+	}{
+		{"no authorization", ""},
+		{"not bearer", "Basic " + newTestJWT(t, jwk, valid)},
+		{"not a jwt", "Bearer token"},
+		{"unknown key", "Bearer " + newTestJWT(t, newTestJWK(t), valid)},
+		{"expired", "Bearer " + newTestJWT(t, jwk, expired)},
+		{"wrong issuer", "Bearer " + newTestJWT(t, jwk, wrongIssuer)},
+		{"no expiry", "Bearer " + newTestJWT(t, jwk, noExpiry)},
+		{"no subject", "Bearer " + newTestJWT(t, jwk, noSubject)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := a.Authenticate(newAuthRequest(tc.authorization))
+			if !isNotAuthorized(err) {
+				t.Errorf("got error %v, want %v", err, probNotAuthorized)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	secret := []byte("test-secret")
+	a := &APIKeyAuthenticator{Secret: secret}
+
+	apiKey, err := NewAPIKey(secret, "test.user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := a.Authenticate(newAuthRequest("Bearer " + apiKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != "test.user" {
+		t.Errorf("got userID %s, want test.user", userID)
+	}
+
+	otherAPIKey, err := NewAPIKey([]byte("other-secret"), "test.user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, authorization := range []string{
+		"",
+		"Bearer " + otherAPIKey,
+		"Bearer other.user" + apiKey[len("test.user"):],
+		"Bearer test.user",
+		"Bearer " + apiKey[len("test.user"):],
+	} {
+		_, err = a.Authenticate(newAuthRequest(authorization))
+		if !isNotAuthorized(err) {
+			t.Errorf("Authorization %q: got error %v, want %v", authorization, err, probNotAuthorized)
+		}
+	}
+
+	_, err = NewAPIKey(nil, "test-user")
+	if err == nil {
+		t.Error("got no error creating an API key without a secret, want an error")
+	}
+}
+
+type countingAuthenticator struct {
+	calls int
+}
+
+func (a *countingAuthenticator) Authenticate(req *http.Request) (string, error) {
+	a.calls++
+	if req.Header.Get("Authorization") == "" {
+		return "", probNotAuthorized
+	}
+	return "user-" + req.Header.Get("Authorization"), nil
+}
+
+func TestCachingAuthenticator(t *testing.T) {
+	backend := &countingAuthenticator{}
+	a := &CachingAuthenticator{Authenticator: backend, TTL: time.Hour}
+
+	for i := 0; i < 2; i++ {
+		userID, err := a.Authenticate(newAuthRequest("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if userID != "user-a" {
+			t.Errorf("got userID %s, want user-a", userID)
+		}
+	}
+	if backend.calls != 1 {
+		t.Errorf("got %d calls to the authenticator, want 1", backend.calls)
+	}
+
+	userID, err := a.Authenticate(newAuthRequest("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != "user-b" {
+		t.Errorf("got userID %s, want user-b", userID)
+	}
+	if backend.calls != 2 {
+		t.Errorf("got %d calls to the authenticator, want 2", backend.calls)
+	}
+
+	// Requests that are not authorized are not cached.
+	for i := 0; i < 2; i++ {
+		_, err = a.Authenticate(newAuthRequest(""))
+		if !isNotAuthorized(err) {
+			t.Errorf("got error %v, want %v", err, probNotAuthorized)
+		}
+	}
+	if backend.calls != 4 {
+		t.Errorf("got %d calls to the authenticator, want 4", backend.calls)
+	}
+
+	// Expired userIDs are authenticated again.
+	a.TTL = 0
+	a.cache = nil
+	for i := 0; i < 2; i++ {
+		_, err = a.Authenticate(newAuthRequest("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if backend.calls != 6 {
+		t.Errorf("got %d calls to the authenticator, want 6", backend.calls)
+	}
+}
@@ -13,6 +13,12 @@ func getConfig() *keystore.Config {
 		MaxIdleDBConns: env.Int("DB_MAX_IDLE_CONNS", 5),
 		MaxOpenDBConns: env.Int("DB_MAX_OPEN_CONNS", 5),
 		AUTHURL:        env.String("KEYSTORE_AUTHFORWARDING_URL", ""),
+		AuthType:       env.String("KEYSTORE_AUTH_TYPE", keystore.AuthForward),
+		AuthCacheTTL:   env.Duration("KEYSTORE_AUTH_CACHE_TTL", 0),
+		JWKS:           env.String("KEYSTORE_JWKS", ""),
+		JWTIssuer:      env.String("KEYSTORE_JWT_ISSUER", ""),
+		APIKeySecret:   env.String("KEYSTORE_APIKEY_SECRET", ""),
 		ListenerPort:   env.Int("KEYSTORE_LISTENER_PORT", 8000),
+		EnvelopeKeys:   env.String("KEYSTORE_ENVELOPE_KEYS", ""),
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"net"
@@ -72,6 +73,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	// apikey does not use the database so that API keys can be issued from
+	// anywhere the secret is available.
+	if flag.Arg(0) == "apikey" {
+		if flag.NArg() != 2 {
+			fmt.Fprintln(os.Stderr, "apikey requires exactly one userID argument")
+			os.Exit(1)
+		}
+		apiKey, err := keystore.NewAPIKey([]byte(cfg.APIKeySecret), flag.Arg(1))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error creating API key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stdout, apiKey)
+		os.Exit(0)
+	}
+
 	db, err := sql.Open(dbDriverName, cfg.DBURL)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error opening database: %v\n", err)
@@ -86,38 +103,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	envelopeKeys, err := keystore.ParseEnvelopeKeys(cfg.EnvelopeKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error parsing envelope keys: %v\n", err)
+		os.Exit(1)
+	}
+
 	cmd := flag.Arg(0)
 	switch cmd {
 	case "serve":
+		var authenticator keystore.AuthBackend
 		if *auth {
-			if cfg.AUTHURL == "" {
-				fmt.Fprintln(os.Stderr, "Auth is enabled but auth forwarding URL is not set")
-				os.Exit(1)
-			}
-			if _, err := url.Parse(cfg.AUTHURL); err != nil {
-				fmt.Fprintln(os.Stderr, "Invalid auth forwarding URL")
+			authenticator, err = newAuthenticator(cfg, *apiType)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
 				os.Exit(1)
 			}
 		}
 
-		aType := strings.ToUpper(*apiType)
-		if aType != keystore.REST && aType != keystore.GraphQL {
-			fmt.Fprintln(os.Stderr, `Auth forwarding endpoint type can only be either "REST" or "GRAPHQL"`)
-			os.Exit(1)
-		}
-
 		addr := ":" + strconv.Itoa(cfg.ListenerPort)
-		var authenticator *keystore.Authenticator
-		if *auth {
-			authenticator = &keystore.Authenticator{
-				URL:     cfg.AUTHURL,
-				APIType: aType,
-			}
-		}
-
 		server := &http.Server{
 			Addr:        addr,
-			Handler:     keystore.ServeMux(keystore.NewService(ctx, db, authenticator)),
+			Handler:     keystore.ServeMux(keystore.NewService(ctx, db, authenticator, envelopeKeys)),
 			ReadTimeout: 5 * time.Second,
 		}
 
@@ -150,6 +157,20 @@ func main() {
 		// the goroutine containing ListenAndServe is still working
 		select {}
 
+	case "reencrypt":
+		if envelopeKeys == nil {
+			fmt.Fprintln(os.Stderr, "Envelope keys are not set")
+			os.Exit(1)
+		}
+
+		n, err := keystore.NewService(ctx, db, nil, envelopeKeys).ReencryptKeys(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error re-encrypting keys blobs after re-encrypting %d: %v\n", n, err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stdout, "Re-encrypted %d keys blobs with envelope key %s!\n", n, envelopeKeys.ActiveID())
+
 	case "migrate":
 		migrateCmd := flag.Arg(1)
 		switch migrateCmd {
@@ -219,6 +240,60 @@ func main() {
 	}
 }
 
+// newAuthenticator returns the authenticator of the auth type in the config.
+func newAuthenticator(cfg *keystore.Config, apiType string) (keystore.AuthBackend, error) {
+	switch strings.ToUpper(cfg.AuthType) {
+	case keystore.AuthForward:
+		if cfg.AUTHURL == "" {
+			return nil, errors.New("Auth is enabled but auth forwarding URL is not set")
+		}
+		if _, err := url.Parse(cfg.AUTHURL); err != nil {
+			return nil, errors.New("Invalid auth forwarding URL")
+		}
+
+		aType := strings.ToUpper(apiType)
+		if aType != keystore.REST && aType != keystore.GraphQL {
+			return nil, errors.New(`Auth forwarding endpoint type can only be either "REST" or "GRAPHQL"`)
+		}
+
+		var authenticator keystore.AuthBackend = &keystore.Authenticator{
+			URL:     cfg.AUTHURL,
+			APIType: aType,
+		}
+		if cfg.AuthCacheTTL > 0 {
+			authenticator = &keystore.CachingAuthenticator{
+				Authenticator: authenticator,
+				TTL:           cfg.AuthCacheTTL,
+			}
+		}
+		return authenticator, nil
+
+	case keystore.AuthJWT:
+		if cfg.JWKS == "" {
+			return nil, errors.New("Auth type is JWT but JWKS is not set")
+		}
+		jwks, err := keystore.ParseJWKS(cfg.JWKS)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JWKS: %v", err)
+		}
+		return &keystore.JWTAuthenticator{
+			Keys:   jwks,
+			Issuer: cfg.JWTIssuer,
+		}, nil
+
+	case keystore.AuthAPIKey:
+		if cfg.APIKeySecret == "" {
+			return nil, errors.New("Auth type is APIKEY but API key secret is not set")
+		}
+		return &keystore.APIKeyAuthenticator{
+			Secret: []byte(cfg.APIKeySecret),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf(`Auth type can only be one of "FORWARD", "JWT" or "APIKEY", got %q`, cfg.AuthType)
+	}
+}
+
 // https://github.com/golang/go/blob/c5cf6624076a644906aa7ec5c91c4e01ccd375d3/src/net/http/server.go#L3272-L3288
 type tcpKeepAliveListener struct {
 	*net.TCPListener
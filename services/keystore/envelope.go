@@ -0,0 +1,200 @@
+package keystore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// EnvelopeKeys are the keys the service encrypts keys blobs with at rest, in
+// addition to the encryption performed by clients. New blobs are encrypted
+// with the active key, and blobs encrypted with any of the keys can be
+// decrypted, so that the active key can be rotated and existing blobs
+// re-encrypted with ReencryptKeys.
+//
+// A nil *EnvelopeKeys stores keys blobs without encrypting them.
+type EnvelopeKeys struct {
+	activeID string
+	keys     map[string]*[32]byte
+}
+
+// ParseEnvelopeKeys parses a comma separated list of envelope keys in the
+// form id:key, where key is a base64 encoded 32 byte key. The first key is the
+// active key.
+func ParseEnvelopeKeys(s string) (*EnvelopeKeys, error) {
+	if s == "" {
+		return nil, nil
+	}
+	k := &EnvelopeKeys{keys: map[string]*[32]byte{}}
+	for i, part := range strings.Split(s, ",") {
+		sep := strings.Index(part, ":")
+		if sep <= 0 {
+			return nil, errors.Errorf("envelope key %d is not in the form id:key", i)
+		}
+		id := strings.TrimSpace(part[:sep])
+		if _, ok := k.keys[id]; ok {
+			return nil, errors.Errorf("envelope key %s is specified more than once", id)
+		}
+		keyBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(part[sep+1:]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding envelope key %s", id)
+		}
+		if len(keyBytes) != 32 {
+			return nil, errors.Errorf("envelope key %s is %d bytes but must be 32 bytes", id, len(keyBytes))
+		}
+		key := [32]byte{}
+		copy(key[:], keyBytes)
+		k.keys[id] = &key
+		if i == 0 {
+			k.activeID = id
+		}
+	}
+	return k, nil
+}
+
+// ActiveID returns the ID of the key that new keys blobs are encrypted with.
+func (k *EnvelopeKeys) ActiveID() string {
+	if k == nil {
+		return ""
+	}
+	return k.activeID
+}
+
+// seal encrypts the data with the active key, returning the encrypted data
+// and the ID of the key.
+func (k *EnvelopeKeys) seal(data []byte) ([]byte, string, error) {
+	if k == nil {
+		return data, "", nil
+	}
+	nonce := [24]byte{}
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generating nonce")
+	}
+	sealed := secretbox.Seal(nonce[:], data, &nonce, k.keys[k.activeID])
+	return sealed, k.activeID, nil
+}
+
+// open decrypts data encrypted by seal with the key with the ID. Data with an
+// empty key ID is not encrypted.
+func (k *EnvelopeKeys) open(sealed []byte, keyID string) ([]byte, error) {
+	if keyID == "" {
+		return sealed, nil
+	}
+	var key *[32]byte
+	if k != nil {
+		key = k.keys[keyID]
+	}
+	if key == nil {
+		return nil, errors.Errorf("envelope key %s is not configured", keyID)
+	}
+	if len(sealed) < 24 {
+		return nil, errors.New("encrypted keys data is too short")
+	}
+	nonce := [24]byte{}
+	copy(nonce[:], sealed[:24])
+	data, ok := secretbox.Open(nil, sealed[24:], &nonce, key)
+	if !ok {
+		return nil, errors.Errorf("decrypting keys data with envelope key %s", keyID)
+	}
+	return data, nil
+}
+
+// reencryptBatchSize is the number of keys blobs re-encrypted in each
+// database transaction by ReencryptKeys.
+const reencryptBatchSize = 100
+
+// ReencryptKeys re-encrypts every version of every keys blob that is not
+// encrypted with the active envelope key, including blobs stored before
+// envelope keys were configured. It returns the number of blobs re-encrypted.
+// The keys blobs are re-encrypted in batches so that it can be run while the
+// service is serving requests.
+func (s *Service) ReencryptKeys(ctx context.Context) (int, error) {
+	if s.envelopeKeys == nil {
+		return 0, errors.New("no envelope keys are configured")
+	}
+
+	n := 0
+	for {
+		batchN, err := s.reencryptKeysBatch(ctx)
+		if err != nil {
+			return n, err
+		}
+		if batchN == 0 {
+			return n, nil
+		}
+		n += batchN
+	}
+}
+
+func (s *Service) reencryptKeysBatch(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	q := `
+		SELECT user_id, version, encrypted_keys_data, envelope_key_id
+		FROM encrypted_keys_versions
+		WHERE envelope_key_id <> $1
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, q, s.envelopeKeys.activeID, reencryptBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting keys blobs to re-encrypt")
+	}
+	type keysVersion struct {
+		userID  string
+		version int
+		data    []byte
+		keyID   string
+	}
+	var versions []keysVersion
+	for rows.Next() {
+		var v keysVersion
+		err = rows.Scan(&v.userID, &v.version, &v.data, &v.keyID)
+		if err != nil {
+			rows.Close()
+			return 0, errors.Wrap(err, "scanning keys blob")
+		}
+		versions = append(versions, v)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, errors.Wrap(err, "getting keys blobs to re-encrypt")
+	}
+
+	for _, v := range versions {
+		var data []byte
+		data, err = s.envelopeKeys.open(v.data, v.keyID)
+		if err != nil {
+			return 0, errors.Wrapf(err, "decrypting version %d of keys blob of user %s", v.version, v.userID)
+		}
+		var sealed []byte
+		var keyID string
+		sealed, keyID, err = s.envelopeKeys.seal(data)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE encrypted_keys_versions
+			SET encrypted_keys_data = $3, envelope_key_id = $4
+			WHERE user_id = $1 AND version = $2
+		`, v.userID, v.version, sealed, keyID)
+		if err != nil {
+			return 0, errors.Wrap(err, "storing re-encrypted keys blob")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.Wrap(err, "committing transaction")
+	}
+	return len(versions), nil
+}
@@ -0,0 +1,212 @@
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseEnvelopeKeys(t *testing.T) {
+	key1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	key2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+
+	k, err := ParseEnvelopeKeys("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k != nil {
+		t.Errorf("got envelope keys %v, want nil", k)
+	}
+
+	k, err = ParseEnvelopeKeys("k2:" + key2 + ", k1:" + key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.ActiveID() != "k2" {
+		t.Errorf("got active envelope key %s, want k2", k.ActiveID())
+	}
+	if len(k.keys) != 2 {
+		t.Errorf("got %d envelope keys, want 2", len(k.keys))
+	}
+
+	invalid := []struct {
+		keys    string
+		wantErr string
+	}{
+		{"k1", "envelope key 0 is not in the form id:key"},
+		{":" + key1, "envelope key 0 is not in the form id:key"},
+		{"k1:" + key1 + ",k1:" + key2, "envelope key k1 is specified more than once"},
+		{"k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "envelope key k1 is 5 bytes but must be 32 bytes"},
+		{"k1:not base64", "decoding envelope key k1: illegal base64 data at input byte 3"},
+	}
+	for _, tc := range invalid {
+		_, err = ParseEnvelopeKeys(tc.keys)
+		if err == nil || err.Error() != tc.wantErr {
+			t.Errorf("ParseEnvelopeKeys(%q) got error %v, want %s", tc.keys, err, tc.wantErr)
+		}
+	}
+}
+
+func TestEnvelopeKeys_sealOpen(t *testing.T) {
+	key1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	key2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	data := []byte(`[{"id":"test-id"}]`)
+
+	// Without envelope keys data is stored as is.
+	var none *EnvelopeKeys
+	sealed, keyID, err := none.seal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "" || !bytes.Equal(sealed, data) {
+		t.Errorf("got sealed data %q with key %q, want unencrypted data", sealed, keyID)
+	}
+
+	old, err := ParseEnvelopeKeys("k1:" + key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, keyID, err = old.seal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "k1" {
+		t.Errorf("got key %s, want k1", keyID)
+	}
+	if bytes.Contains(sealed, data) {
+		t.Errorf("sealed data contains the data")
+	}
+
+	// After rotation data sealed with the previous key can still be opened.
+	rotated, err := ParseEnvelopeKeys("k2:" + key2 + ",k1:" + key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := rotated.open(sealed, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, data) {
+		t.Errorf("got opened data %q, want %q", opened, data)
+	}
+	opened, err = rotated.open(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, data) {
+		t.Errorf("got opened data %q, want %q", opened, data)
+	}
+
+	// Data cannot be opened once its key is removed or if it is modified.
+	removed, err := ParseEnvelopeKeys("k2:" + key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = removed.open(sealed, keyID)
+	if err == nil || err.Error() != "envelope key k1 is not configured" {
+		t.Errorf("got error %v, want envelope key k1 is not configured", err)
+	}
+	_, err = none.open(sealed, keyID)
+	if err == nil || err.Error() != "envelope key k1 is not configured" {
+		t.Errorf("got error %v, want envelope key k1 is not configured", err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	_, err = rotated.open(sealed, keyID)
+	if err == nil || err.Error() != "decrypting keys data with envelope key k1" {
+		t.Errorf("got error %v, want decrypting keys data with envelope key k1", err)
+	}
+}
+
+func TestReencryptKeys(t *testing.T) {
+	db := openKeystoreDB(t)
+	defer db.Close() // drop test db
+
+	conn := db.Open()
+	defer conn.Close() // close db connection
+
+	ctx := withUserID(context.Background(), "test-user")
+
+	blob := `[{
+		"id": "test-id",
+		"salt": "test-salt",
+		"encrypterName": "test-encrypter-name",
+		"encryptedBlob": "test-encryptedblob"
+	}]`
+	keysBlob := base64.RawURLEncoding.EncodeToString([]byte(blob))
+
+	// Store a version unencrypted and a version with the first envelope key.
+	s := &Service{db: conn.DB}
+	_, err := s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1, err := ParseEnvelopeKeys("k1:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = &Service{db: conn.DB, envelopeKeys: k1}
+	_, err = s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate to the second envelope key.
+	k2, err := ParseEnvelopeKeys(
+		"k2:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)) +
+			",k1:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = &Service{db: conn.DB, envelopeKeys: k2}
+	n, err := s.ReencryptKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d keys blobs re-encrypted, want 2", n)
+	}
+
+	var keyIDs []string
+	rows, err := conn.DB.Query(`SELECT envelope_key_id FROM encrypted_keys_versions ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var keyID string
+		if err = rows.Scan(&keyID); err != nil {
+			t.Fatal(err)
+		}
+		keyIDs = append(keyIDs, keyID)
+	}
+	if len(keyIDs) != 2 || keyIDs[0] != "k2" || keyIDs[1] != "k2" {
+		t.Errorf("got envelope keys %v, want [k2 k2]", keyIDs)
+	}
+
+	// Once re-encrypted the first envelope key is no longer needed.
+	k2Only, err := ParseEnvelopeKeys("k2:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = &Service{db: conn.DB, envelopeKeys: k2Only}
+	got, err := s.getKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyKeysBlob(t, got.KeysBlob, keysBlob)
+	got, err = s.getKeysVersion(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyKeysBlob(t, got.KeysBlob, keysBlob)
+
+	n, err = s.ReencryptKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("got %d keys blobs re-encrypted, want 0", n)
+	}
+}
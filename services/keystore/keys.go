@@ -13,6 +13,7 @@ import (
 
 type encryptedKeysData struct {
 	KeysBlob   string     `json:"keysBlob"`
+	Version    int        `json:"version"`
 	CreatedAt  time.Time  `json:"createdAt"`
 	ModifiedAt *time.Time `json:"modifiedAt,omitempty"`
 }
@@ -26,6 +27,9 @@ type encryptedKeyData struct {
 
 type putKeysRequest struct {
 	KeysBlob string `json:"keysBlob"`
+	// IfMatch is the If-Match header of the request, which if set must match
+	// the ETag of the current version of the keys blob.
+	IfMatch string `json:"-"`
 }
 
 func (s *Service) putKeys(ctx context.Context, in putKeysRequest) (*encryptedKeysData, error) {
@@ -69,23 +73,86 @@ func (s *Service) putKeys(ctx context.Context, in putKeysRequest) (*encryptedKey
 		}
 	}
 
-	q := `
-		INSERT INTO encrypted_keys (user_id, encrypted_keys_data)
-		VALUES ($1, $2)
-		ON CONFLICT (user_id) DO UPDATE SET encrypted_keys_data = excluded.encrypted_keys_data, modified_at = NOW()
-		RETURNING encrypted_keys_data, created_at, modified_at
-	`
+	sealed, envelopeKeyID, err := s.envelopeKeys.seal(keysData)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting keys blob")
+	}
+
+	out, err := s.storeKeysVersion(ctx, userID, in.IfMatch, sealed, envelopeKeyID, nil)
+	if err != nil {
+		return nil, err
+	}
+	out.KeysBlob = in.KeysBlob
+	return out, nil
+}
+
+// storeKeysVersion stores the sealed keys blob as a new version of the keys
+// blob of the user and makes it the current version. If ifMatch is set it
+// must match the ETag of the current version. The keys blob of the data
+// returned is not set.
+func (s *Service) storeKeysVersion(ctx context.Context, userID, ifMatch string, sealed []byte, envelopeKeyID string, restoredFrom *int) (*encryptedKeysData, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	// Lock the user's keys so that concurrent requests create consecutive
+	// versions and If-Match is checked against the version being replaced.
+	// The row is inserted first if it does not exist so that there is a row
+	// to lock, and is removed by the rollback if the request fails.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO encrypted_keys (user_id, version)
+		VALUES ($1, 0)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "locking keys blob")
+	}
+	var currentVersion int
+	err = tx.QueryRowContext(ctx, `
+		SELECT version
+		FROM encrypted_keys
+		WHERE user_id = $1
+		FOR UPDATE
+	`, userID).Scan(&currentVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "locking keys blob")
+	}
+
+	if ifMatch != "" && !etagMatches(ifMatch, currentVersion) {
+		return nil, probPreconditionFailed
+	}
+
+	version := currentVersion + 1
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO encrypted_keys_versions (user_id, version, encrypted_keys_data, envelope_key_id, restored_from)
+		VALUES ($1, $2, $3, $4, $5)
+	`, userID, version, sealed, envelopeKeyID, restoredFrom)
+	if err != nil {
+		return nil, errors.Wrap(err, "storing keys blob")
+	}
+
 	var (
-		keysBlob   []byte
-		out        encryptedKeysData
+		out        = encryptedKeysData{Version: version}
 		modifiedAt pq.NullTime
 	)
-	err = s.db.QueryRowContext(ctx, q, userID, keysData).Scan(&keysBlob, &out.CreatedAt, &modifiedAt)
+	// The keys blob is only modified if it replaces an existing version.
+	err = tx.QueryRowContext(ctx, `
+		UPDATE encrypted_keys
+		SET version = $2, modified_at = CASE WHEN version = 0 THEN NULL ELSE NOW() END
+		WHERE user_id = $1
+		RETURNING created_at, modified_at
+	`, userID, version).Scan(&out.CreatedAt, &modifiedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "storing keys blob")
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, errors.Wrap(err, "storing keys blob")
 	}
 
-	out.KeysBlob = base64.RawURLEncoding.EncodeToString(keysBlob)
 	if modifiedAt.Valid {
 		out.ModifiedAt = &modifiedAt.Time
 	}
@@ -99,20 +166,27 @@ func (s *Service) getKeys(ctx context.Context) (*encryptedKeysData, error) {
 	}
 
 	q := `
-		SELECT encrypted_keys_data, created_at, modified_at
-		FROM encrypted_keys
-		WHERE user_id = $1
+		SELECT v.encrypted_keys_data, v.envelope_key_id, k.version, k.created_at, k.modified_at
+		FROM encrypted_keys k
+		JOIN encrypted_keys_versions v ON v.user_id = k.user_id AND v.version = k.version
+		WHERE k.user_id = $1
 	`
 	var (
-		keysBlob   []byte
-		out        encryptedKeysData
-		modifiedAt pq.NullTime
+		sealed        []byte
+		envelopeKeyID string
+		out           encryptedKeysData
+		modifiedAt    pq.NullTime
 	)
-	err := s.db.QueryRowContext(ctx, q, userID).Scan(&keysBlob, &out.CreatedAt, &modifiedAt)
+	err := s.db.QueryRowContext(ctx, q, userID).Scan(&sealed, &envelopeKeyID, &out.Version, &out.CreatedAt, &modifiedAt)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting keys blob")
 	}
 
+	keysBlob, err := s.envelopeKeys.open(sealed, envelopeKeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting keys blob")
+	}
+
 	out.KeysBlob = base64.RawURLEncoding.EncodeToString(keysBlob)
 	if modifiedAt.Valid {
 		out.ModifiedAt = &modifiedAt.Time
@@ -120,6 +194,7 @@ func (s *Service) getKeys(ctx context.Context) (*encryptedKeysData, error) {
 	return &out, nil
 }
 
+// deleteKeys deletes the keys blob of the user and all of its versions.
 func (s *Service) deleteKeys(ctx context.Context) error {
 	userID := userID(ctx)
 	if userID == "" {
@@ -20,7 +20,7 @@ func TestPutKeys(t *testing.T) {
 	defer conn.Close() // close db connection
 
 	ctx := withUserID(context.Background(), "test-user")
-	s := &Service{conn.DB, nil}
+	s := &Service{db: conn.DB}
 
 	blob := `[{
 		"id": "test-id",
@@ -50,7 +50,7 @@ func TestGetKeys(t *testing.T) {
 	defer conn.Close() // close db connection
 
 	ctx := withUserID(context.Background(), "test-user")
-	s := &Service{conn.DB, nil}
+	s := &Service{db: conn.DB}
 
 	blob := `[{
 		"id": "test-id",
@@ -85,7 +85,7 @@ func TestDeleteKeys(t *testing.T) {
 	defer conn.Close() // close db connection
 
 	ctx := withUserID(context.Background(), "test-user")
-	s := &Service{conn.DB, nil}
+	s := &Service{db: conn.DB}
 
 	blob := `[{
 		"id": "test-id",
@@ -0,0 +1,40 @@
+-- +migrate Up
+
+CREATE TABLE public.encrypted_keys_versions (
+    user_id text NOT NULL REFERENCES public.encrypted_keys (user_id) ON DELETE CASCADE,
+    version integer NOT NULL,
+    encrypted_keys_data bytea NOT NULL,
+    envelope_key_id text NOT NULL DEFAULT '',
+    restored_from integer,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, version)
+);
+
+INSERT INTO public.encrypted_keys_versions (user_id, version, encrypted_keys_data, created_at)
+SELECT user_id, 1, convert_to(encrypted_keys_data::text, 'UTF8'), COALESCE(modified_at, created_at)
+FROM public.encrypted_keys;
+
+ALTER TABLE public.encrypted_keys
+	ADD COLUMN version integer NOT NULL DEFAULT 1;
+
+ALTER TABLE public.encrypted_keys
+	ALTER COLUMN version DROP DEFAULT,
+	DROP COLUMN encrypted_keys_data;
+
+-- +migrate Down
+
+-- The current versions must not be encrypted with an envelope key to be
+-- migrated down, see the reencrypt command.
+ALTER TABLE public.encrypted_keys
+	ADD COLUMN encrypted_keys_data jsonb;
+
+UPDATE public.encrypted_keys k
+SET encrypted_keys_data = convert_from(v.encrypted_keys_data, 'UTF8')::jsonb
+FROM public.encrypted_keys_versions v
+WHERE v.user_id = k.user_id AND v.version = k.version;
+
+ALTER TABLE public.encrypted_keys
+	ALTER COLUMN encrypted_keys_data SET NOT NULL,
+	DROP COLUMN version;
+
+DROP TABLE public.encrypted_keys_versions;
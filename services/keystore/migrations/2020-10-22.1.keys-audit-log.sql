@@ -0,0 +1,16 @@
+-- +migrate Up
+
+CREATE TABLE public.keys_audit_log (
+    id bigserial PRIMARY KEY,
+    user_id text NOT NULL,
+    client_ip text NOT NULL,
+    action text NOT NULL,
+    status integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX keys_audit_log_user_id_created_at ON public.keys_audit_log (user_id, created_at);
+
+-- +migrate Down
+
+DROP TABLE public.keys_audit_log;
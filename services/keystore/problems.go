@@ -22,7 +22,8 @@ var (
 		Title:  "Method Not Allowed",
 		Status: http.StatusMethodNotAllowed,
 		Detail: "This endpoint does not support the request method you used. " +
-			"The server supports HTTP GET/PUT/DELETE for the /keys endpoint.",
+			"The server supports HTTP GET/PUT/DELETE for the /keys endpoint, HTTP GET for the " +
+			"/keys/versions endpoints, and HTTP POST for the /keys/restore endpoint.",
 	}
 
 	probInvalidKeysBlob = problem.P{
@@ -35,6 +36,21 @@ var (
 			"make sure the encoded content matches EncryptedKeys type specified in the spec and try again.",
 	}
 
+	probPreconditionFailed = problem.P{
+		Type:   "precondition_failed",
+		Title:  "Precondition Failed",
+		Status: http.StatusPreconditionFailed,
+		Detail: "The keys blob has been modified since the version in the If-Match header of your request. " +
+			"Please get the current keys blob and try again.",
+	}
+
+	probInvalidVersion = problem.P{
+		Type:   "invalid_version",
+		Title:  "Invalid Version",
+		Status: 400,
+		Detail: "The version in the URL of your request is not a positive integer.",
+	}
+
 	probNotAuthorized = problem.P{
 		Type:   "not_authorized",
 		Title:  "Not Authorized",
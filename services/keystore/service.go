@@ -3,6 +3,7 @@ package keystore
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const (
@@ -17,9 +18,29 @@ type Config struct {
 
 	AUTHURL string
 
+	// AuthType is the authenticator requests are authenticated with, one of
+	// AuthForward, AuthJWT or AuthAPIKey.
+	AuthType string
+	// AuthCacheTTL is how long the userIDs derived by the forwarding
+	// authenticator are cached for. Caching is disabled if zero.
+	AuthCacheTTL time.Duration
+	// JWKS is the JSON Web Key Set that JWTs are verified with, see
+	// ParseJWKS.
+	JWKS string
+	// JWTIssuer is the issuer that JWTs must have.
+	JWTIssuer string
+	// APIKeySecret is the secret that API keys are verified with.
+	APIKeySecret string
+
+	// EnvelopeKeys are the keys keys blobs are encrypted with at rest, see
+	// ParseEnvelopeKeys.
+	EnvelopeKeys string
+
 	ListenerPort int
 }
 
+// Authenticator authenticates requests by forwarding them to an endpoint of
+// the client server that responds with the userID.
 type Authenticator struct {
 	URL     string
 	APIType string
@@ -28,9 +49,10 @@ type Authenticator struct {
 
 type Service struct {
 	db            *sql.DB
-	authenticator *Authenticator
+	authenticator AuthBackend
+	envelopeKeys  *EnvelopeKeys
 }
 
-func NewService(ctx context.Context, db *sql.DB, authenticator *Authenticator) *Service {
-	return &Service{db: db, authenticator: authenticator}
+func NewService(ctx context.Context, db *sql.DB, authenticator AuthBackend, envelopeKeys *EnvelopeKeys) *Service {
+	return &Service{db: db, authenticator: authenticator, envelopeKeys: envelopeKeys}
 }
@@ -0,0 +1,147 @@
+package keystore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+type keysVersionData struct {
+	Version      int       `json:"version"`
+	CreatedAt    time.Time `json:"createdAt"`
+	RestoredFrom *int      `json:"restoredFrom,omitempty"`
+}
+
+type keysVersionsData struct {
+	CurrentVersion int               `json:"currentVersion"`
+	Versions       []keysVersionData `json:"versions"`
+}
+
+// listKeysVersions returns the versions of the keys blob of the user, most
+// recent first, without the keys blobs.
+func (s *Service) listKeysVersions(ctx context.Context) (*keysVersionsData, error) {
+	userID := userID(ctx)
+	if userID == "" {
+		return nil, probNotAuthorized
+	}
+
+	out := keysVersionsData{Versions: []keysVersionData{}}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT version
+		FROM encrypted_keys
+		WHERE user_id = $1
+	`, userID).Scan(&out.CurrentVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting keys blob")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT version, created_at, restored_from
+		FROM encrypted_keys_versions
+		WHERE user_id = $1
+		ORDER BY version DESC
+	`, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting keys blob versions")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			v            keysVersionData
+			restoredFrom sql.NullInt64
+		)
+		err = rows.Scan(&v.Version, &v.CreatedAt, &restoredFrom)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting keys blob versions")
+		}
+		if restoredFrom.Valid {
+			rf := int(restoredFrom.Int64)
+			v.RestoredFrom = &rf
+		}
+		out.Versions = append(out.Versions, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "getting keys blob versions")
+	}
+
+	return &out, nil
+}
+
+// getKeysVersion returns a version of the keys blob of the user.
+func (s *Service) getKeysVersion(ctx context.Context, version int) (*encryptedKeysData, error) {
+	userID := userID(ctx)
+	if userID == "" {
+		return nil, probNotAuthorized
+	}
+
+	var (
+		sealed        []byte
+		envelopeKeyID string
+		out           = encryptedKeysData{Version: version}
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT encrypted_keys_data, envelope_key_id, created_at
+		FROM encrypted_keys_versions
+		WHERE user_id = $1 AND version = $2
+	`, userID, version).Scan(&sealed, &envelopeKeyID, &out.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting keys blob version")
+	}
+
+	keysBlob, err := s.envelopeKeys.open(sealed, envelopeKeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting keys blob")
+	}
+	out.KeysBlob = base64.RawURLEncoding.EncodeToString(keysBlob)
+	return &out, nil
+}
+
+type restoreKeysRequest struct {
+	Version int `json:"version"`
+	// IfMatch is the If-Match header of the request, which if set must match
+	// the ETag of the current version of the keys blob.
+	IfMatch string `json:"-"`
+}
+
+// restoreKeys makes a previous version of the keys blob of the user the
+// current version by storing a copy of it as a new version, so that the
+// versions replaced remain in the history.
+func (s *Service) restoreKeys(ctx context.Context, in restoreKeysRequest) (*encryptedKeysData, error) {
+	userID := userID(ctx)
+	if userID == "" {
+		return nil, probNotAuthorized
+	}
+
+	if in.Version <= 0 {
+		return nil, problem.MakeInvalidFieldProblem("version", errors.New("version must be a positive integer"))
+	}
+
+	var (
+		sealed        []byte
+		envelopeKeyID string
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT encrypted_keys_data, envelope_key_id
+		FROM encrypted_keys_versions
+		WHERE user_id = $1 AND version = $2
+	`, userID, in.Version).Scan(&sealed, &envelopeKeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting keys blob version")
+	}
+
+	keysBlob, err := s.envelopeKeys.open(sealed, envelopeKeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting keys blob")
+	}
+
+	out, err := s.storeKeysVersion(ctx, userID, in.IfMatch, sealed, envelopeKeyID, &in.Version)
+	if err != nil {
+		return nil, err
+	}
+	out.KeysBlob = base64.RawURLEncoding.EncodeToString(keysBlob)
+	return out, nil
+}
@@ -0,0 +1,205 @@
+package keystore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestKeysVersions(t *testing.T) {
+	db := openKeystoreDB(t)
+	defer db.Close() // drop test db
+
+	conn := db.Open()
+	defer conn.Close() // close db connection
+
+	ctx := withUserID(context.Background(), "test-user")
+	s := &Service{db: conn.DB}
+
+	blob1 := `[{
+		"id": "test-id-1",
+		"salt": "test-salt",
+		"encrypterName": "test-encrypter-name",
+		"encryptedBlob": "test-encryptedblob"
+	}]`
+	keysBlob1 := base64.RawURLEncoding.EncodeToString([]byte(blob1))
+	blob2 := `[{
+		"id": "test-id-2",
+		"salt": "test-salt",
+		"encrypterName": "test-encrypter-name",
+		"encryptedBlob": "test-encryptedblob"
+	}]`
+	keysBlob2 := base64.RawURLEncoding.EncodeToString([]byte(blob2))
+
+	got, err := s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 1 {
+		t.Errorf("got version %d, want 1", got.Version)
+	}
+	if got.ModifiedAt != nil {
+		t.Errorf("got ModifiedAt=%s, want no ModifiedAt for a new keys blob", got.ModifiedAt)
+	}
+
+	got, err = s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 2 {
+		t.Errorf("got version %d, want 2", got.Version)
+	}
+	if got.ModifiedAt == nil {
+		t.Errorf("got no ModifiedAt, want ModifiedAt for a replaced keys blob")
+	}
+
+	versions, err := s.listKeysVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions.CurrentVersion != 2 {
+		t.Errorf("got current version %d, want 2", versions.CurrentVersion)
+	}
+	if len(versions.Versions) != 2 || versions.Versions[0].Version != 2 || versions.Versions[1].Version != 1 {
+		t.Errorf("got versions %+v, want versions 2 and 1", versions.Versions)
+	}
+
+	// The previous version is still available.
+	got, err = s.getKeysVersion(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyKeysBlob(t, got.KeysBlob, keysBlob1)
+
+	// Restoring the previous version stores it as a new version.
+	got, err = s.restoreKeys(ctx, restoreKeysRequest{Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 3 {
+		t.Errorf("got version %d, want 3", got.Version)
+	}
+	verifyKeysBlob(t, got.KeysBlob, keysBlob1)
+
+	got, err = s.getKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 3 {
+		t.Errorf("got version %d, want 3", got.Version)
+	}
+	verifyKeysBlob(t, got.KeysBlob, keysBlob1)
+
+	versions, err = s.listKeysVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions.Versions) != 3 || versions.Versions[0].RestoredFrom == nil || *versions.Versions[0].RestoredFrom != 1 {
+		t.Errorf("got versions %+v, want version 3 restored from version 1", versions.Versions)
+	}
+
+	_, err = s.getKeysVersion(ctx, 4)
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Errorf("got error %v, want %v", err, sql.ErrNoRows)
+	}
+	_, err = s.restoreKeys(ctx, restoreKeysRequest{Version: 4})
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Errorf("got error %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// Deleting the keys blob deletes its versions.
+	err = s.deleteKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.getKeysVersion(ctx, 1)
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Errorf("got error %v, want %v", err, sql.ErrNoRows)
+	}
+	_, err = s.listKeysVersions(ctx)
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Errorf("got error %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestPutKeys_ifMatch(t *testing.T) {
+	db := openKeystoreDB(t)
+	defer db.Close() // drop test db
+
+	conn := db.Open()
+	defer conn.Close() // close db connection
+
+	ctx := withUserID(context.Background(), "test-user")
+	s := &Service{db: conn.DB}
+
+	blob := `[{
+		"id": "test-id",
+		"salt": "test-salt",
+		"encrypterName": "test-encrypter-name",
+		"encryptedBlob": "test-encryptedblob"
+	}]`
+	keysBlob := base64.RawURLEncoding.EncodeToString([]byte(blob))
+
+	// No keys blob exists to match.
+	_, err := s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob, IfMatch: "*"})
+	if p, ok := err.(problem.P); !ok || p.Type != probPreconditionFailed.Type {
+		t.Errorf("got error %v, want %v", err, probPreconditionFailed)
+	}
+
+	_, err = s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob, IfMatch: `"1"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 2 {
+		t.Errorf("got version %d, want 2", got.Version)
+	}
+
+	// The client has not seen version 2.
+	_, err = s.putKeys(ctx, putKeysRequest{KeysBlob: keysBlob, IfMatch: `"1"`})
+	if p, ok := err.(problem.P); !ok || p.Type != probPreconditionFailed.Type {
+		t.Errorf("got error %v, want %v", err, probPreconditionFailed)
+	}
+	_, err = s.restoreKeys(ctx, restoreKeysRequest{Version: 1, IfMatch: `"1"`})
+	if p, ok := err.(problem.P); !ok || p.Type != probPreconditionFailed.Type {
+		t.Errorf("got error %v, want %v", err, probPreconditionFailed)
+	}
+
+	got, err = s.getKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 2 {
+		t.Errorf("got version %d, want 2", got.Version)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	testCases := []struct {
+		ifMatch string
+		version int
+		want    bool
+	}{
+		{`"1"`, 1, true},
+		{`"1"`, 2, false},
+		{`"1", "2"`, 2, true},
+		{`W/"1"`, 1, false},
+		{`*`, 1, true},
+		{`*`, 0, false},
+		{`1`, 1, false},
+	}
+	for _, tc := range testCases {
+		got := etagMatches(tc.ifMatch, tc.version)
+		if got != tc.want {
+			t.Errorf("etagMatches(%q, %d) = %v, want %v", tc.ifMatch, tc.version, got, tc.want)
+		}
+	}
+}
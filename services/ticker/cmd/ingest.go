@@ -5,18 +5,30 @@ import (
 
 	"github.com/lib/pq"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
 	ticker "github.com/stellar/go/services/ticker/internal"
+	"github.com/stellar/go/services/ticker/internal/scraper"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
 )
 
 var ShouldStream bool
 var BackfillHours int
+var StellarCoreBinaryPath string
+var StellarCoreConfigPath string
+var RemoteCaptiveCoreURL string
+var HistoryArchiveURL string
+var StartLedger uint32
 
 func init() {
 	rootCmd.AddCommand(cmdIngest)
 	cmdIngest.AddCommand(cmdIngestAssets)
 	cmdIngest.AddCommand(cmdIngestTrades)
 	cmdIngest.AddCommand(cmdIngestOrderbooks)
+	cmdIngest.AddCommand(cmdIngestLedgers)
+	cmdIngest.AddCommand(cmdIngestCandles)
 
 	cmdIngestTrades.Flags().BoolVar(
 		&ShouldStream,
@@ -31,6 +43,37 @@ func init() {
 		7*24,
 		"Number of past hours to backfill trade data",
 	)
+
+	cmdIngestLedgers.Flags().StringVar(
+		&StellarCoreBinaryPath,
+		"stellar-core-binary-path",
+		"",
+		"Path to the stellar-core binary used to stream ledgers, required unless --remote-captive-core-url is set",
+	)
+	cmdIngestLedgers.Flags().StringVar(
+		&StellarCoreConfigPath,
+		"stellar-core-config-path",
+		"",
+		"Path to the stellar-core configuration file",
+	)
+	cmdIngestLedgers.Flags().StringVar(
+		&RemoteCaptiveCoreURL,
+		"remote-captive-core-url",
+		"",
+		"URL of a remote captive core server to stream ledgers from instead of running stellar-core",
+	)
+	cmdIngestLedgers.Flags().StringVar(
+		&HistoryArchiveURL,
+		"history-archive-url",
+		"",
+		"URL of the history archive the orderbook is loaded from (defaults to an SDF archive of the selected network)",
+	)
+	cmdIngestLedgers.Flags().Uint32Var(
+		&StartLedger,
+		"start-ledger",
+		0,
+		"Ledger after which ingestion starts if no ledger was ingested yet (defaults to the latest checkpoint)",
+	)
 }
 
 var cmdIngest = &cobra.Command{
@@ -90,7 +133,7 @@ var cmdIngestTrades = &cobra.Command{
 		if ShouldStream {
 			Logger.Info("Streaming new data (this is a continuous process)")
 			ctx := context.Background()
-			err = ticker.StreamTrades(ctx, &session, Client, Logger)
+			err = ticker.StreamTrades(ctx, &session, Client, Logger, nil)
 			if err != nil {
 				Logger.Fatal("could not refresh trade database:", err)
 			}
@@ -120,3 +163,103 @@ var cmdIngestOrderbooks = &cobra.Command{
 		}
 	},
 }
+
+var cmdIngestLedgers = &cobra.Command{
+	Use:   "ledgers",
+	Short: "Continuously ingests trades and orderbook stats derived directly from ledger data.",
+	Long: "Streams ledgers from stellar-core and stores the trades and orderbook stats derived " +
+		"from them. The progress is stored in the database so that ingestion resumes right " +
+		"after the last ingested ledger when restarted.",
+	Run: func(cmd *cobra.Command, args []string) {
+		dbInfo, err := pq.ParseURL(DatabaseURL)
+		if err != nil {
+			Logger.Fatal("could not parse db-url:", err)
+		}
+
+		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		if err != nil {
+			Logger.Fatal("could not connect to db:", err)
+		}
+		defer session.DB.Close()
+
+		passphrase := network.PublicNetworkPassphrase
+		archiveURL := "https://history.stellar.org/prd/core-live/core_live_001"
+		if UseTestNet {
+			passphrase = network.TestNetworkPassphrase
+			archiveURL = "https://history.stellar.org/prd/core-testnet/core_testnet_001"
+		}
+		if HistoryArchiveURL != "" {
+			archiveURL = HistoryArchiveURL
+		}
+
+		ctx := context.Background()
+		archive, err := historyarchive.Connect(
+			archiveURL,
+			historyarchive.ConnectOptions{
+				Context:           ctx,
+				NetworkPassphrase: passphrase,
+			},
+		)
+		if err != nil {
+			Logger.Fatal("could not connect to history archive:", err)
+		}
+
+		var backend ledgerbackend.LedgerBackend
+		if RemoteCaptiveCoreURL != "" {
+			backend, err = ledgerbackend.NewRemoteCaptive(RemoteCaptiveCoreURL)
+		} else if StellarCoreBinaryPath != "" {
+			backend, err = ledgerbackend.NewCaptive(ledgerbackend.CaptiveCoreConfig{
+				StellarCoreBinaryPath: StellarCoreBinaryPath,
+				StellarCoreConfigPath: StellarCoreConfigPath,
+				NetworkPassphrase:     passphrase,
+				HistoryArchiveURLs:    []string{archiveURL},
+			})
+		} else {
+			Logger.Fatal("either --stellar-core-binary-path or --remote-captive-core-url must be set")
+		}
+		if err != nil {
+			Logger.Fatal("could not create captive core instance:", err)
+		}
+		defer backend.Close()
+
+		sc := &scraper.LedgerScraper{
+			Backend:           backend,
+			Archive:           archive,
+			NetworkPassphrase: passphrase,
+			Logger:            Logger,
+			Graph:             orderbook.NewOrderBookGraph(),
+		}
+
+		Logger.Info("Ingesting ledgers (this is a continuous process)")
+		err = ticker.IngestLedgers(ctx, &session, sc, StartLedger, Logger)
+		if err != nil {
+			Logger.Fatal("could not ingest ledgers:", err)
+		}
+	},
+}
+
+var cmdIngestCandles = &cobra.Command{
+	Use:   "candles",
+	Short: "Backfills the OHLCV candles of every market from the trades in the database.",
+	Long: "Recomputes the candles of every market and resolution from the stored trades. " +
+		"Candles are kept up to date as trades are ingested, so this is only needed to " +
+		"backfill the candles of trades ingested before candles were introduced.",
+	Run: func(cmd *cobra.Command, args []string) {
+		Logger.Info("Backfilling candles")
+		dbInfo, err := pq.ParseURL(DatabaseURL)
+		if err != nil {
+			Logger.Fatal("could not parse db-url:", err)
+		}
+
+		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		if err != nil {
+			Logger.Fatal("could not connect to db:", err)
+		}
+		defer session.DB.Close()
+
+		err = ticker.BackfillCandles(&session, Logger)
+		if err != nil {
+			Logger.Fatal("could not backfill candles:", err)
+		}
+	},
+}
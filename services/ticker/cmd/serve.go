@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/lib/pq"
 	"github.com/spf13/cobra"
 	ticker "github.com/stellar/go/services/ticker/internal"
@@ -8,6 +10,7 @@ import (
 )
 
 var ServerAddr string
+var ShouldStreamTrades bool
 
 func init() {
 	rootCmd.AddCommand(cmdServe)
@@ -18,6 +21,13 @@ func init() {
 		"0.0.0.0:3000",
 		"Server address and port",
 	)
+
+	cmdServe.Flags().BoolVar(
+		&ShouldStreamTrades,
+		"stream",
+		false,
+		"Stream new trades from Horizon, ingest them and publish them to GraphQL subscribers",
+	)
 }
 
 var cmdServe = &cobra.Command{
@@ -36,6 +46,10 @@ var cmdServe = &cobra.Command{
 		}
 		defer session.DB.Close()
 
+		if ShouldStreamTrades {
+			ticker.StartStreamingGraphQLServer(context.Background(), &session, Client, Logger, ServerAddr)
+			return
+		}
 		ticker.StartGraphQLServer(&session, Logger, ServerAddr)
 	},
 }
@@ -0,0 +1,19 @@
+package ticker
+
+import (
+	"time"
+
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	hlog "github.com/stellar/go/support/log"
+)
+
+// BackfillCandles recomputes the candles of every market from all the trades
+// stored in the database. Candles are otherwise refreshed as trades are ingested.
+func BackfillCandles(s *tickerdb.TickerSession, l *hlog.Entry) error {
+	start := time.Now()
+	if err := s.RefreshCandles(time.Time{}); err != nil {
+		return err
+	}
+	l.Infof("Candles backfilled in %s\n", time.Since(start))
+	return nil
+}
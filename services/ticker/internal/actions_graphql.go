@@ -1,13 +1,121 @@
 package ticker
 
 import (
+	"context"
+	"time"
+
+	horizonclient "github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/ticker/internal/gql"
+	"github.com/stellar/go/services/ticker/internal/scraper"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/errors"
 	hlog "github.com/stellar/go/support/log"
 )
 
+// orderbookRefreshInterval is the minimum time between two refreshes of the
+// orderbook stats of a market triggered by its trades.
+const orderbookRefreshInterval = 5 * time.Second
+
 func StartGraphQLServer(s *tickerdb.TickerSession, l *hlog.Entry, port string) {
 	graphql := gql.New(s, l)
 
 	graphql.Serve(port)
 }
+
+// StartStreamingGraphQLServer starts the GraphQL server and streams new trades from
+// Horizon. Streamed trades are ingested and published to the GraphQL subscribers, along
+// with the refreshed orderbook stats of their markets.
+func StartStreamingGraphQLServer(
+	ctx context.Context,
+	s *tickerdb.TickerSession,
+	c *horizonclient.Client,
+	l *hlog.Entry,
+	port string,
+) {
+	graphql := gql.New(s, l)
+	p := &tradePublisher{
+		s:           s,
+		sc:          scraper.ScraperConfig{Client: c, Logger: l},
+		l:           l,
+		subscribers: graphql,
+		lastRefresh: map[[2]int32]time.Time{},
+	}
+
+	go func() {
+		l.Info("Streaming new trades to GraphQL subscribers")
+		if err := StreamTrades(ctx, s, c, l, p.publish); err != nil {
+			l.Errorln("could not stream trades:", err)
+		}
+	}()
+
+	graphql.Serve(port)
+}
+
+// subscriptionPublisher notifies GraphQL subscribers of new data.
+type subscriptionPublisher interface {
+	PublishTrade(trade hProtocol.Trade)
+	PublishOrderbook(baseAssetCode, baseAssetIssuer, counterAssetCode, counterAssetIssuer string, stats tickerdb.OrderbookStats)
+}
+
+// tradePublisher publishes streamed trades and the orderbook stats of their markets.
+type tradePublisher struct {
+	s           *tickerdb.TickerSession
+	sc          scraper.ScraperConfig
+	l           *hlog.Entry
+	subscribers subscriptionPublisher
+
+	// lastRefresh is the time the orderbook stats of each market, identified
+	// by its base and counter asset ids, were last refreshed.
+	lastRefresh map[[2]int32]time.Time
+}
+
+// publish is called by StreamTrades with every stored trade.
+func (p *tradePublisher) publish(trade hProtocol.Trade, dbTrade tickerdb.Trade) {
+	p.subscribers.PublishTrade(trade)
+
+	market := [2]int32{dbTrade.BaseAssetID, dbTrade.CounterAssetID}
+	if time.Since(p.lastRefresh[market]) < orderbookRefreshInterval {
+		return
+	}
+	p.lastRefresh[market] = time.Now()
+
+	err := p.refreshOrderbook(
+		trade.BaseAssetType, trade.BaseAssetCode, trade.BaseAssetIssuer, dbTrade.BaseAssetID,
+		trade.CounterAssetType, trade.CounterAssetCode, trade.CounterAssetIssuer, dbTrade.CounterAssetID,
+	)
+	if err != nil {
+		p.l.Error(errors.Wrap(err, "could not refresh orderbook"))
+		return
+	}
+
+	// Refresh the orderbook stats for the reverse market as well.
+	err = p.refreshOrderbook(
+		trade.CounterAssetType, trade.CounterAssetCode, trade.CounterAssetIssuer, dbTrade.CounterAssetID,
+		trade.BaseAssetType, trade.BaseAssetCode, trade.BaseAssetIssuer, dbTrade.BaseAssetID,
+	)
+	if err != nil {
+		p.l.Error(errors.Wrap(err, "could not refresh reverse orderbook"))
+	}
+}
+
+// refreshOrderbook fetches the orderbook of a market from Horizon, stores its stats
+// and publishes them.
+func (p *tradePublisher) refreshOrderbook(
+	bType, bCode, bIssuer string, bID int32,
+	cType, cCode, cIssuer string, cID int32,
+) error {
+	ob, err := p.sc.FetchOrderbookForAssets(bType, bCode, bIssuer, cType, cCode, cIssuer)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch orderbook for assets")
+	}
+
+	dbOS := orderbookStatsToDBOrderbookStats(ob, bID, cID)
+	err = p.s.InsertOrUpdateOrderbookStats(&dbOS, []string{"base_asset_id", "counter_asset_id"})
+	if err != nil {
+		return errors.Wrap(err, "could not insert orderbook stats into db")
+	}
+
+	p.subscribers.PublishOrderbook(bCode, bIssuer, cCode, cIssuer, dbOS)
+	return nil
+}
@@ -0,0 +1,206 @@
+package ticker
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/ingest/io"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/ticker/internal/scraper"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/errors"
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// ledgerCursorName is the name of the ingestion cursor tracking the last ledger
+// ingested by IngestLedgers.
+const ledgerCursorName = "ledgers"
+
+// ledgerPollInterval is the time to wait before retrying to fetch a ledger which
+// has not been closed yet.
+const ledgerPollInterval = time.Second
+
+// IngestLedgers constantly ingests trades and orderbook stats derived directly from
+// ledger data. Each ledger is ingested in a single database transaction which also
+// records the ledger as the last ingested one, so that ingestion resumes exactly
+// after it when restarted. If no ledger was ingested yet, ingestion starts after
+// startLedger, or after the latest checkpoint if startLedger is 0.
+func IngestLedgers(
+	ctx context.Context,
+	s *tickerdb.TickerSession,
+	sc *scraper.LedgerScraper,
+	startLedger uint32,
+	l *hlog.Entry,
+) error {
+	lastLedger, found, err := s.GetIngestionCursor(ledgerCursorName)
+	if err != nil {
+		return errors.Wrap(err, "could not get ingestion cursor")
+	}
+	if found {
+		l.Infof("Resuming ingestion after ledger %d\n", lastLedger)
+	} else if startLedger > 0 {
+		lastLedger = startLedger
+	} else {
+		lastLedger, err = sc.LatestCheckpoint()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = sc.LoadOrderbook(ctx, lastLedger); err != nil {
+		return errors.Wrap(err, "could not load orderbook")
+	}
+
+	for sequence := lastLedger + 1; ; {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		data, err := sc.ScrapeLedger(sequence)
+		if err == io.ErrNotFound {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(ledgerPollInterval):
+				continue
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not scrape ledger %d", sequence)
+		}
+
+		if err = ingestLedgerData(s, sc, data); err != nil {
+			return errors.Wrapf(err, "could not ingest ledger %d", sequence)
+		}
+		l.Infof("Ingested ledger %d: %d trade(s), %d market(s) changed\n", sequence, len(data.Trades), len(data.Markets))
+		sequence++
+	}
+}
+
+// ingestLedgerData stores the trades and the orderbook stats of the changed markets
+// of a ledger, along with the updated ingestion cursor, in a single transaction.
+func ingestLedgerData(
+	s *tickerdb.TickerSession,
+	sc *scraper.LedgerScraper,
+	data scraper.LedgerData,
+) error {
+	if err := s.Begin(); err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	defer s.Rollback()
+
+	var dbTrades []tickerdb.Trade
+	for _, trade := range data.Trades {
+		bID, cID, found, err := findTradeAssetIDs(s, trade)
+		if err != nil {
+			return err
+		}
+		if !found {
+			// Trades of assets which are not tracked by the ticker are ignored,
+			// like they are when backfilling trades from Horizon.
+			continue
+		}
+
+		dbTrade, err := hProtocolTradeToDBTrade(trade, bID, cID)
+		if err != nil {
+			return errors.Wrap(err, "could not convert trade")
+		}
+		dbTrades = append(dbTrades, dbTrade)
+	}
+	if err := s.BulkInsertTrades(dbTrades); err != nil {
+		return errors.Wrap(err, "could not insert trades")
+	}
+
+	// Only the candles of the markets traded in the ledger change. All the
+	// trades of a ledger share the same close time.
+	refreshed := map[[2]int32]bool{}
+	for _, dbTrade := range dbTrades {
+		market := [2]int32{dbTrade.BaseAssetID, dbTrade.CounterAssetID}
+		if refreshed[market] {
+			continue
+		}
+		refreshed[market] = true
+
+		err := s.RefreshMarketCandles(dbTrade.BaseAssetID, dbTrade.CounterAssetID, dbTrade.LedgerCloseTime)
+		if err != nil {
+			return errors.Wrap(err, "could not refresh candles")
+		}
+	}
+
+	for _, mkt := range data.Markets {
+		bID, bFound, err := findAssetID(s, mkt.Base)
+		if err != nil {
+			return err
+		}
+		cID, cFound, err := findAssetID(s, mkt.Counter)
+		if err != nil {
+			return err
+		}
+		if !bFound || !cFound {
+			continue
+		}
+
+		// Compute the orderbook stats for both the market and the reverse market.
+		if err = upsertLedgerOrderbookStats(s, sc, mkt.Base, mkt.Counter, bID, cID); err != nil {
+			return err
+		}
+		if err = upsertLedgerOrderbookStats(s, sc, mkt.Counter, mkt.Base, cID, bID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.UpdateIngestionCursor(ledgerCursorName, data.Sequence); err != nil {
+		return errors.Wrap(err, "could not update ingestion cursor")
+	}
+	if err := s.Commit(); err != nil {
+		return errors.Wrap(err, "could not commit transaction")
+	}
+	return nil
+}
+
+// findAssetID returns the database id of an asset.
+func findAssetID(s *tickerdb.TickerSession, asset xdr.Asset) (id int32, found bool, err error) {
+	_, code, issuer := scraper.AssetFields(asset)
+	found, id, err = s.GetAssetByCodeAndIssuerAccount(code, issuer)
+	if err != nil {
+		err = errors.Wrap(err, "could not find asset")
+	}
+	return
+}
+
+// findTradeAssetIDs returns the IDs of the base and counter assets of the
+// trade, and whether both assets are tracked by the ticker.
+func findTradeAssetIDs(s *tickerdb.TickerSession, trade hProtocol.Trade) (bID, cID int32, found bool, err error) {
+	bFound, bID, err := s.GetAssetByCodeAndIssuerAccount(trade.BaseAssetCode, trade.BaseAssetIssuer)
+	if err != nil {
+		err = errors.Wrap(err, "could not find base asset")
+		return
+	}
+	cFound, cID, err := s.GetAssetByCodeAndIssuerAccount(trade.CounterAssetCode, trade.CounterAssetIssuer)
+	if err != nil {
+		err = errors.Wrap(err, "could not find counter asset")
+		return
+	}
+	found = bFound && cFound
+	return
+}
+
+func upsertLedgerOrderbookStats(
+	s *tickerdb.TickerSession,
+	sc *scraper.LedgerScraper,
+	base, counter xdr.Asset,
+	bID, cID int32,
+) error {
+	ob, err := sc.FetchOrderbookForMarket(base, counter)
+	if err != nil {
+		return errors.Wrap(err, "could not compute orderbook stats")
+	}
+
+	dbOS := orderbookStatsToDBOrderbookStats(ob, bID, cID)
+	err = s.InsertOrUpdateOrderbookStats(&dbOS, []string{"base_asset_id", "counter_asset_id"})
+	if err != nil {
+		return errors.Wrap(err, "could not insert orderbook stats into db")
+	}
+	return nil
+}
@@ -15,11 +15,13 @@ import (
 )
 
 // StreamTrades constantly streams and ingests new trades directly from horizon.
+// If onTrade is not nil, it is called with every trade once it is stored.
 func StreamTrades(
 	ctx context.Context,
 	s *tickerdb.TickerSession,
 	c *horizonclient.Client,
 	l *hlog.Entry,
+	onTrade func(hProtocol.Trade, tickerdb.Trade),
 ) error {
 	sc := scraper.ScraperConfig{
 		Client: c,
@@ -43,6 +45,16 @@ func StreamTrades(
 		err = s.BulkInsertTrades([]tickerdb.Trade{dbTrade})
 		if err != nil {
 			l.Errorln("Could not insert trade in database: ", trade.ID)
+			return
+		}
+
+		err = s.RefreshMarketCandles(bID, cID, trade.LedgerCloseTime)
+		if err != nil {
+			l.Errorln("Could not refresh candles for trade: ", trade.ID, err)
+		}
+
+		if onTrade != nil {
+			onTrade(trade, dbTrade)
 		}
 	}
 
@@ -100,6 +112,14 @@ func BackfillTrades(
 		fmt.Println(err)
 	}
 
+	if len(dbTrades) > 0 {
+		l.Infoln("Refreshing the candles of the inserted trades.")
+		err = s.RefreshCandles(since)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
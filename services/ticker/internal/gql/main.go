@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/stellar/go/services/ticker/internal/gql/static"
@@ -80,6 +81,7 @@ type orderbookStats struct {
 type resolver struct {
 	db     *tickerdb.TickerSession
 	logger *hlog.Entry
+	events *pubSub
 }
 
 // New creates a new GraphQL resolver
@@ -87,18 +89,29 @@ func New(s *tickerdb.TickerSession, l *hlog.Entry) *resolver {
 	if s == nil {
 		panic("A valid database session must be provided for the GraphQL server")
 	}
-	return &resolver{db: s, logger: l}
+	return &resolver{db: s, logger: l, events: newPubSub()}
 }
 
-// Serve creates a GraphQL interface on <address>/graphql and a GraphiQL explorer on /graphiql
+// Serve creates a GraphQL interface on <address>/graphql and a GraphiQL explorer on /graphiql.
+// Subscriptions are served on <address>/graphql over WebSocket, and candles are also served
+// as JSON on <address>/candles.
 func (r *resolver) Serve(address string) {
 	relayHandler := r.NewRelayHandler()
+	wsHandler := r.NewWebSocketHandler(relayHandler.Schema)
 	mux := http.NewServeMux()
 	mux.Handle("/graphql", http.HandlerFunc(func(wr http.ResponseWriter, re *http.Request) {
 		r.logger.Infof("%s %s %s\n", re.RemoteAddr, re.Method, re.URL)
+		if websocket.IsWebSocketUpgrade(re) {
+			wsHandler.ServeHTTP(wr, re)
+			return
+		}
 		relayHandler.ServeHTTP(wr, re)
 	}))
 	mux.Handle("/graphiql", GraphiQL{})
+	mux.Handle("/candles", http.HandlerFunc(func(wr http.ResponseWriter, re *http.Request) {
+		r.logger.Infof("%s %s %s\n", re.RemoteAddr, re.Method, re.URL)
+		r.ServeCandles(wr, re)
+	}))
 
 	server := &http.Server{
 		Addr:        address,
@@ -0,0 +1,90 @@
+package gql
+
+import (
+	"sync"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+)
+
+// subscriberBufferSize is the number of events buffered for each subscriber.
+// Events published to subscribers whose buffer is full are dropped, so that
+// slow subscribers cannot hold back the trade stream.
+const subscriberBufferSize = 64
+
+// orderbookEvent represents a change of the orderbook stats of a pair of assets.
+type orderbookEvent struct {
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	Stats              tickerdb.OrderbookStats
+}
+
+// pubSub fans out the events published by the trade stream to the
+// subscriptions of every connected client.
+type pubSub struct {
+	mu          sync.Mutex
+	subscribers map[chan interface{}]struct{}
+}
+
+func newPubSub() *pubSub {
+	return &pubSub{subscribers: map[chan interface{}]struct{}{}}
+}
+
+// subscribe registers a new subscriber which receives every event published
+// until it is unsubscribed.
+func (p *pubSub) subscribe() chan interface{} {
+	ch := make(chan interface{}, subscriberBufferSize)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (p *pubSub) unsubscribe(ch chan interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.subscribers, ch)
+}
+
+// publish sends an event to every subscriber and returns the number of
+// subscribers which dropped the event because their buffer was full.
+func (p *pubSub) publish(event interface{}) (dropped int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for ch := range p.subscribers {
+		select {
+		case ch <- event:
+		default:
+			dropped++
+		}
+	}
+	return
+}
+
+// PublishTrade notifies the subscribers of a new trade. The trade must be
+// normalized with scraper.NormalizeTradeAssets.
+func (r *resolver) PublishTrade(trade hProtocol.Trade) {
+	if dropped := r.events.publish(trade); dropped > 0 {
+		r.logger.Warnf("Trade %s was dropped by %d slow subscriber(s)\n", trade.ID, dropped)
+	}
+}
+
+// PublishOrderbook notifies the subscribers of new orderbook stats of the
+// given pair of assets.
+func (r *resolver) PublishOrderbook(
+	baseAssetCode, baseAssetIssuer, counterAssetCode, counterAssetIssuer string,
+	stats tickerdb.OrderbookStats,
+) {
+	event := orderbookEvent{
+		BaseAssetCode:      baseAssetCode,
+		BaseAssetIssuer:    baseAssetIssuer,
+		CounterAssetCode:   counterAssetCode,
+		CounterAssetIssuer: counterAssetIssuer,
+		Stats:              stats,
+	}
+	if dropped := r.events.publish(event); dropped > 0 {
+		r.logger.Warnf("Orderbook update was dropped by %d slow subscriber(s)\n", dropped)
+	}
+}
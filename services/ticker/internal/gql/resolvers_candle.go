@@ -0,0 +1,253 @@
+package gql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+)
+
+// maxCandles is the maximum number of candles returned by a single query.
+const maxCandles = 1000
+
+// candle represents the OHLCV data of a pair of assets over a time interval,
+// with some type adaptations to match the GraphQL type system
+type candle struct {
+	OpenTime      graphql.Time `json:"open_time"`
+	CloseTime     graphql.Time `json:"close_time"`
+	Open          float64      `json:"open"`
+	High          float64      `json:"high"`
+	Low           float64      `json:"low"`
+	Close         float64      `json:"close"`
+	BaseVolume    float64      `json:"base_volume"`
+	CounterVolume float64      `json:"counter_volume"`
+	TradeCount    int32        `json:"trade_count"`
+	VWAP          float64      `json:"vwap"`
+	TWAP          float64      `json:"twap"`
+}
+
+// candlesQuery is a validated request for the candles of a pair of assets.
+type candlesQuery struct {
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	Resolution         int32
+	From               time.Time
+	To                 time.Time
+}
+
+// Candles resolves the candles() GraphQL query.
+func (r *resolver) Candles(args struct {
+	Pair       string
+	Resolution string
+	From       *graphql.Time
+	To         *graphql.Time
+}) ([]*candle, error) {
+	var from, to *time.Time
+	if args.From != nil {
+		from = &args.From.Time
+	}
+	if args.To != nil {
+		to = &args.To.Time
+	}
+
+	q, err := newCandlesQuery(args.Pair, args.Resolution, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	candles, err := r.retrieveCandles(q)
+	if err != nil {
+		// obfuscating sql errors to avoid exposing underlying
+		// implementation
+		return nil, errors.New("could not retrieve the requested data")
+	}
+	return candles, nil
+}
+
+// ServeCandles serves the candles of a pair of assets as JSON. It accepts the same
+// parameters as the candles() GraphQL query, with times given either as RFC3339
+// strings or UNIX timestamps.
+func (r *resolver) ServeCandles(w http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	from, err := parseCandlesTimeParam(params.Get("from"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid from: "+err.Error())
+		return
+	}
+	to, err := parseCandlesTimeParam(params.Get("to"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid to: "+err.Error())
+		return
+	}
+
+	q, err := newCandlesQuery(params.Get("pair"), params.Get("resolution"), from, to, time.Now())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	candles, err := r.retrieveCandles(q)
+	if err != nil {
+		r.logger.Errorln("could not retrieve candles:", err)
+		writeJSONError(w, http.StatusInternalServerError, "could not retrieve the requested data")
+		return
+	}
+	if candles == nil {
+		candles = []*candle{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(struct {
+		Pair       string    `json:"pair"`
+		Resolution string    `json:"resolution"`
+		Candles    []*candle `json:"candles"`
+	}{params.Get("pair"), params.Get("resolution"), candles})
+	if err != nil {
+		r.logger.Errorln("could not write candles:", err)
+	}
+}
+
+// retrieveCandles retrieves the candles requested by q, expressed in the
+// direction of the requested pair. No candles are returned for unknown assets.
+func (r *resolver) retrieveCandles(q candlesQuery) ([]*candle, error) {
+	bFound, bID, err := r.db.GetAssetByCodeAndIssuerAccount(q.BaseAssetCode, q.BaseAssetIssuer)
+	if err != nil {
+		return nil, err
+	}
+	cFound, cID, err := r.db.GetAssetByCodeAndIssuerAccount(q.CounterAssetCode, q.CounterAssetIssuer)
+	if err != nil {
+		return nil, err
+	}
+	if !bFound || !cFound {
+		return nil, nil
+	}
+
+	dbCandles, err := r.db.RetrieveCandles(bID, cID, q.Resolution, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var candles []*candle
+	for _, dbCandle := range dbCandles {
+		candles = append(candles, dbCandleToCandle(dbCandle, dbCandle.BaseAssetID != bID))
+	}
+	return candles, nil
+}
+
+// newCandlesQuery validates the arguments of a candles request. pair identifies
+// the base and counter assets, separated by an underscore, with each asset given
+// as CODE:ISSUER, or XLM for lumens. to defaults to now, and from to the value
+// returning the last maxCandles candles before to.
+func newCandlesQuery(pair, resolution string, from, to *time.Time, now time.Time) (q candlesQuery, err error) {
+	assets := strings.Split(pair, "_")
+	if len(assets) != 2 {
+		return q, fmt.Errorf("invalid pair %q, expected BASE_COUNTER", pair)
+	}
+	q.BaseAssetCode, q.BaseAssetIssuer, err = parseCandlesAsset(assets[0])
+	if err != nil {
+		return
+	}
+	q.CounterAssetCode, q.CounterAssetIssuer, err = parseCandlesAsset(assets[1])
+	if err != nil {
+		return
+	}
+
+	var ok bool
+	q.Resolution, ok = tickerdb.CandleResolutions[resolution]
+	if !ok {
+		return q, fmt.Errorf("invalid resolution %q, expected one of 1m, 5m, 15m, 1h, 4h or 1d", resolution)
+	}
+	interval := time.Duration(q.Resolution) * time.Second
+
+	q.To = now
+	if to != nil {
+		q.To = *to
+	}
+	q.From = q.To.Add(-(maxCandles - 1) * interval)
+	if from != nil {
+		q.From = *from
+	}
+	// The candle containing from is included.
+	q.From = q.From.Truncate(interval)
+
+	if !q.From.Before(q.To) {
+		return q, errors.New("from must be before to")
+	}
+	if q.To.Sub(q.From) > maxCandles*interval {
+		return q, fmt.Errorf("too many candles requested, at most %d candles can be retrieved at once", maxCandles)
+	}
+	return q, nil
+}
+
+// parseCandlesAsset parses an asset given as CODE:ISSUER, or XLM for lumens.
+func parseCandlesAsset(s string) (code, issuer string, err error) {
+	if s == "XLM" {
+		return "XLM", "native", nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid asset %q, expected CODE:ISSUER or XLM", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// parseCandlesTimeParam parses an optional time given as a RFC3339 string or
+// a UNIX timestamp.
+func parseCandlesTimeParam(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		t := time.Unix(ts, 0)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// dbCandleToCandle converts a tickerdb.Candle to a *candle, optionally
+// swapping its base and counter assets.
+func dbCandleToCandle(c tickerdb.Candle, reversed bool) *candle {
+	res := &candle{
+		OpenTime:      graphql.Time{Time: c.OpenTime},
+		CloseTime:     graphql.Time{Time: c.OpenTime.Add(time.Duration(c.Resolution) * time.Second)},
+		Open:          c.Open,
+		High:          c.High,
+		Low:           c.Low,
+		Close:         c.Close,
+		BaseVolume:    c.BaseVolume,
+		CounterVolume: c.CounterVolume,
+		TradeCount:    c.TradeCount,
+		VWAP:          c.VWAP,
+		TWAP:          c.TWAP,
+	}
+	if reversed {
+		res.Open = invertIfNonZero(c.Open)
+		res.High = invertIfNonZero(c.Low)
+		res.Low = invertIfNonZero(c.High)
+		res.Close = invertIfNonZero(c.Close)
+		res.BaseVolume, res.CounterVolume = c.CounterVolume, c.BaseVolume
+		res.VWAP = invertIfNonZero(c.VWAP)
+		res.TWAP = c.InverseTWAP
+	}
+	return res
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{message})
+}
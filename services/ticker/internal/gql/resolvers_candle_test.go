@@ -0,0 +1,116 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCandlesQuery(t *testing.T) {
+	now := time.Date(2020, 10, 1, 10, 30, 15, 0, time.UTC)
+
+	q, err := newCandlesQuery("XLM_USD:"+testIssuer, "1h", nil, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, candlesQuery{
+		BaseAssetCode:      "XLM",
+		BaseAssetIssuer:    "native",
+		CounterAssetCode:   "USD",
+		CounterAssetIssuer: testIssuer,
+		Resolution:         3600,
+		From:               time.Date(2020, 8, 20, 19, 0, 0, 0, time.UTC),
+		To:                 now,
+	}, q)
+
+	// The candle containing from is included:
+	from := now.Add(-time.Hour)
+	q, err = newCandlesQuery("USD:"+testIssuer+"_XLM", "15m", &from, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", q.BaseAssetCode)
+	assert.Equal(t, "native", q.CounterAssetIssuer)
+	assert.Equal(t, time.Date(2020, 10, 1, 9, 30, 0, 0, time.UTC), q.From)
+
+	for _, tc := range []struct {
+		pair       string
+		resolution string
+		from       time.Time
+		err        string
+	}{
+		{"XLM", "1h", from, `invalid pair "XLM", expected BASE_COUNTER`},
+		{"XLM_USD", "1h", from, `invalid asset "USD", expected CODE:ISSUER or XLM`},
+		{"XLM_USD:", "1h", from, `invalid asset "USD:", expected CODE:ISSUER or XLM`},
+		{"XLM_USD:" + testIssuer, "2h", from, `invalid resolution "2h", expected one of 1m, 5m, 15m, 1h, 4h or 1d`},
+		{"XLM_USD:" + testIssuer, "1h", now.Add(time.Hour), "from must be before to"},
+		{"XLM_USD:" + testIssuer, "1m", now.Add(-24 * time.Hour), "too many candles requested, at most 1000 candles can be retrieved at once"},
+	} {
+		t.Run(tc.pair+"/"+tc.resolution, func(t *testing.T) {
+			from := tc.from
+			_, err := newCandlesQuery(tc.pair, tc.resolution, &from, nil, now)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestDBCandleToCandle(t *testing.T) {
+	dbCandle := tickerdb.Candle{
+		Resolution:    60,
+		OpenTime:      time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC),
+		Open:          1,
+		High:          4,
+		Low:           0.5,
+		Close:         2,
+		BaseVolume:    10,
+		CounterVolume: 15,
+		TradeCount:    3,
+		VWAP:          1.5,
+		TWAP:          1.25,
+		InverseTWAP:   0.9,
+	}
+
+	c := dbCandleToCandle(dbCandle, false)
+	assert.Equal(t, time.Date(2020, 10, 1, 10, 1, 0, 0, time.UTC), c.CloseTime.Time)
+	assert.Equal(t, 4.0, c.High)
+	assert.Equal(t, 1.25, c.TWAP)
+
+	c = dbCandleToCandle(dbCandle, true)
+	assert.Equal(t, &candle{
+		OpenTime:      c.OpenTime,
+		CloseTime:     c.CloseTime,
+		Open:          1,
+		High:          2,
+		Low:           0.25,
+		Close:         0.5,
+		BaseVolume:    15,
+		CounterVolume: 10,
+		TradeCount:    3,
+		VWAP:          1 / 1.5,
+		TWAP:          0.9,
+	}, c)
+}
+
+func TestCandlesValidation(t *testing.T) {
+	r, schema := newTestSchema(t)
+
+	response := schema.Exec(context.Background(), `{ candles(pair: "XLM_USD:`+testIssuer+`", resolution: "2h") { open } }`, "", nil)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, `invalid resolution "2h", expected one of 1m, 5m, 15m, 1h, 4h or 1d`, response.Errors[0].Message)
+
+	w := httptest.NewRecorder()
+	r.ServeCandles(w, httptest.NewRequest(http.MethodGet, "/candles?pair=XLM_USD:"+testIssuer+"&resolution=1h&from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var body struct{ Error string }
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Contains(t, body.Error, "invalid from")
+
+	w = httptest.NewRecorder()
+	r.ServeCandles(w, httptest.NewRequest(http.MethodGet, "/candles?pair=XLM_USD:"+testIssuer+"&resolution=1h&from=1601546400&to=1601539200", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "from must be before to"}`, w.Body.String())
+}
@@ -0,0 +1,233 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/ticker/internal/utils"
+)
+
+// trade represents a trade of a pair of assets, with some type
+// adaptations to match the GraphQL type system
+type trade struct {
+	HorizonID          string
+	LedgerCloseTime    graphql.Time
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	BaseAmount         float64
+	CounterAmount      float64
+	Price              float64
+	BaseIsSeller       bool
+}
+
+// pairArgs are the arguments identifying the pair of assets of a subscription.
+type pairArgs struct {
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+}
+
+// matchPair reports whether the given pair of assets is the pair of the
+// subscription, either as is or with base and counter swapped.
+func (p pairArgs) matchPair(bCode, bIssuer, cCode, cIssuer string) (match, reversed bool) {
+	if p.BaseAssetCode == bCode && p.BaseAssetIssuer == bIssuer &&
+		p.CounterAssetCode == cCode && p.CounterAssetIssuer == cIssuer {
+		return true, false
+	}
+	if p.BaseAssetCode == cCode && p.BaseAssetIssuer == cIssuer &&
+		p.CounterAssetCode == bCode && p.CounterAssetIssuer == bIssuer {
+		return true, true
+	}
+	return false, false
+}
+
+// forwardEvents calls handle with every event published until ctx is done
+// or handle returns false.
+func (r *resolver) forwardEvents(ctx context.Context, handle func(event interface{}) bool) error {
+	if r.events == nil {
+		return errors.New("subscriptions are not supported by this server")
+	}
+
+	events := r.events.subscribe()
+	go func() {
+		defer r.events.unsubscribe(events)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				if !handle(event) {
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Trades resolves the trades() GraphQL subscription.
+func (r *resolver) Trades(ctx context.Context, args pairArgs) (<-chan *trade, error) {
+	trades := make(chan *trade)
+	err := r.forwardEvents(ctx, func(event interface{}) bool {
+		hpt, ok := event.(hProtocol.Trade)
+		if !ok {
+			return true
+		}
+		match, reversed := args.matchPair(hpt.BaseAssetCode, hpt.BaseAssetIssuer, hpt.CounterAssetCode, hpt.CounterAssetIssuer)
+		if !match {
+			return true
+		}
+
+		t, err := hProtocolTradeToTrade(hpt, reversed)
+		if err != nil {
+			r.logger.Errorln("could not convert trade:", err)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case trades <- t:
+			return true
+		}
+	})
+	return trades, err
+}
+
+// MarketUpdates resolves the marketUpdates() GraphQL subscription.
+func (r *resolver) MarketUpdates(ctx context.Context, args struct {
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	NumHoursAgo        *int32
+}) (<-chan *partialMarket, error) {
+	if _, err := validateNumHoursAgo(args.NumHoursAgo); err != nil {
+		return nil, err
+	}
+
+	pair := pairArgs{
+		BaseAssetCode:      args.BaseAssetCode,
+		BaseAssetIssuer:    args.BaseAssetIssuer,
+		CounterAssetCode:   args.CounterAssetCode,
+		CounterAssetIssuer: args.CounterAssetIssuer,
+	}
+	markets := make(chan *partialMarket)
+	err := r.forwardEvents(ctx, func(event interface{}) bool {
+		hpt, ok := event.(hProtocol.Trade)
+		if !ok {
+			return true
+		}
+		if match, _ := pair.matchPair(hpt.BaseAssetCode, hpt.BaseAssetIssuer, hpt.CounterAssetCode, hpt.CounterAssetIssuer); !match {
+			return true
+		}
+
+		// The trade is already stored when it is published, so the market
+		// stats retrieved from the database include it.
+		partialMarkets, err := r.Markets(struct {
+			BaseAssetCode      *string
+			BaseAssetIssuer    *string
+			CounterAssetCode   *string
+			CounterAssetIssuer *string
+			NumHoursAgo        *int32
+		}{
+			BaseAssetCode:      &args.BaseAssetCode,
+			BaseAssetIssuer:    &args.BaseAssetIssuer,
+			CounterAssetCode:   &args.CounterAssetCode,
+			CounterAssetIssuer: &args.CounterAssetIssuer,
+			NumHoursAgo:        args.NumHoursAgo,
+		})
+		if err != nil {
+			r.logger.Errorln("could not retrieve market:", err)
+			return true
+		}
+		if len(partialMarkets) == 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case markets <- partialMarkets[0]:
+			return true
+		}
+	})
+	return markets, err
+}
+
+// OrderbookUpdates resolves the orderbookUpdates() GraphQL subscription.
+func (r *resolver) OrderbookUpdates(ctx context.Context, args pairArgs) (<-chan *orderbookStats, error) {
+	updates := make(chan *orderbookStats)
+	err := r.forwardEvents(ctx, func(event interface{}) bool {
+		ob, ok := event.(orderbookEvent)
+		if !ok {
+			return true
+		}
+		// Orderbook stats are published for both directions of a market, so
+		// only exact matches are forwarded.
+		if match, reversed := args.matchPair(ob.BaseAssetCode, ob.BaseAssetIssuer, ob.CounterAssetCode, ob.CounterAssetIssuer); !match || reversed {
+			return true
+		}
+
+		spread, spreadMidPoint := utils.CalcSpread(ob.Stats.HighestBid, ob.Stats.LowestAsk)
+		os := &orderbookStats{
+			BidCount:       BigInt(ob.Stats.NumBids),
+			BidVolume:      ob.Stats.BidVolume,
+			BidMax:         ob.Stats.HighestBid,
+			AskCount:       BigInt(ob.Stats.NumAsks),
+			AskVolume:      ob.Stats.AskVolume,
+			AskMin:         ob.Stats.LowestAsk,
+			Spread:         spread,
+			SpreadMidPoint: spreadMidPoint,
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case updates <- os:
+			return true
+		}
+	})
+	return updates, err
+}
+
+// hProtocolTradeToTrade converts a hProtocol.Trade to a *trade, optionally
+// swapping its base and counter assets.
+func hProtocolTradeToTrade(hpt hProtocol.Trade, reversed bool) (*trade, error) {
+	baseAmount, err := strconv.ParseFloat(hpt.BaseAmount, 64)
+	if err != nil {
+		return nil, err
+	}
+	counterAmount, err := strconv.ParseFloat(hpt.CounterAmount, 64)
+	if err != nil {
+		return nil, err
+	}
+	if hpt.Price == nil || hpt.Price.D == 0 {
+		return nil, errors.New("trade has no price")
+	}
+
+	t := &trade{
+		HorizonID:          hpt.ID,
+		LedgerCloseTime:    graphql.Time{Time: hpt.LedgerCloseTime},
+		BaseAssetCode:      hpt.BaseAssetCode,
+		BaseAssetIssuer:    hpt.BaseAssetIssuer,
+		CounterAssetCode:   hpt.CounterAssetCode,
+		CounterAssetIssuer: hpt.CounterAssetIssuer,
+		BaseAmount:         baseAmount,
+		CounterAmount:      counterAmount,
+		Price:              float64(hpt.Price.N) / float64(hpt.Price.D),
+		BaseIsSeller:       hpt.BaseIsSeller,
+	}
+	if reversed {
+		t.BaseAssetCode, t.CounterAssetCode = t.CounterAssetCode, t.BaseAssetCode
+		t.BaseAssetIssuer, t.CounterAssetIssuer = t.CounterAssetIssuer, t.BaseAssetIssuer
+		t.BaseAmount, t.CounterAmount = t.CounterAmount, t.BaseAmount
+		t.Price = invertIfNonZero(t.Price)
+		t.BaseIsSeller = !t.BaseIsSeller
+	}
+	return t, nil
+}
@@ -0,0 +1,189 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/ticker/internal/gql/static"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer       = "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB"
+	tradesQuery      = `subscription { trades(baseAssetCode: "XLM", baseAssetIssuer: "native", counterAssetCode: "USD", counterAssetIssuer: "` + testIssuer + `") { horizonID, baseAssetCode, counterAssetCode, baseAmount, counterAmount, price, baseIsSeller } }`
+	reversedQuery    = `subscription { trades(baseAssetCode: "USD", baseAssetIssuer: "` + testIssuer + `", counterAssetCode: "XLM", counterAssetIssuer: "native") { horizonID, baseAssetCode, counterAssetCode, baseAmount, counterAmount, price, baseIsSeller } }`
+	orderbookQuery   = `subscription { orderbookUpdates(baseAssetCode: "XLM", baseAssetIssuer: "native", counterAssetCode: "USD", counterAssetIssuer: "` + testIssuer + `") { bidCount, bidMax, askCount, askMin, spread } }`
+	subscribeTimeout = 5 * time.Second
+)
+
+func newTestSchema(t *testing.T) (*resolver, *graphql.Schema) {
+	r := &resolver{logger: hlog.New(), events: newPubSub()}
+	schema, err := graphql.ParseSchema(static.Schema(), r, graphql.UseFieldResolvers())
+	require.NoError(t, err)
+	return r, schema
+}
+
+func testTrade(id string) hProtocol.Trade {
+	return hProtocol.Trade{
+		ID:                 id,
+		LedgerCloseTime:    time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+		BaseAssetType:      "native",
+		BaseAssetCode:      "XLM",
+		BaseAssetIssuer:    "native",
+		BaseAmount:         "20.0000000",
+		CounterAssetType:   "credit_alphanum4",
+		CounterAssetCode:   "USD",
+		CounterAssetIssuer: testIssuer,
+		CounterAmount:      "5.0000000",
+		BaseIsSeller:       true,
+		Price:              &hProtocol.Price{N: 1, D: 4},
+	}
+}
+
+func receive(t *testing.T, responses <-chan interface{}) string {
+	select {
+	case response, ok := <-responses:
+		require.True(t, ok, "subscription was closed")
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+		return string(data)
+	case <-time.After(subscribeTimeout):
+		t.Fatal("timed out waiting for subscription response")
+	}
+	return ""
+}
+
+func TestTradesSubscription(t *testing.T) {
+	r, schema := newTestSchema(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trades, err := schema.Subscribe(ctx, tradesQuery, "", nil)
+	require.NoError(t, err)
+	reversedTrades, err := schema.Subscribe(ctx, reversedQuery, "", nil)
+	require.NoError(t, err)
+
+	// Trades of other pairs are filtered out.
+	other := testTrade("1-0")
+	other.CounterAssetCode = "EUR"
+	r.PublishTrade(other)
+	r.PublishTrade(testTrade("2-0"))
+
+	assert.JSONEq(t, `{"data": {"trades": {
+		"horizonID": "2-0",
+		"baseAssetCode": "XLM",
+		"counterAssetCode": "USD",
+		"baseAmount": 20,
+		"counterAmount": 5,
+		"price": 0.25,
+		"baseIsSeller": true
+	}}}`, receive(t, trades))
+	assert.JSONEq(t, `{"data": {"trades": {
+		"horizonID": "2-0",
+		"baseAssetCode": "USD",
+		"counterAssetCode": "XLM",
+		"baseAmount": 5,
+		"counterAmount": 20,
+		"price": 4,
+		"baseIsSeller": false
+	}}}`, receive(t, reversedTrades))
+
+	// Subscriptions end when their context is done.
+	cancel()
+	for range trades {
+	}
+	for range reversedTrades {
+	}
+}
+
+func TestOrderbookUpdatesSubscription(t *testing.T) {
+	r, schema := newTestSchema(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates, err := schema.Subscribe(ctx, orderbookQuery, "", nil)
+	require.NoError(t, err)
+
+	stats := tickerdb.OrderbookStats{NumBids: 2, HighestBid: 0.2, NumAsks: 3, LowestAsk: 0.3}
+	// Only the stats of the requested direction of the market are forwarded.
+	r.PublishOrderbook("USD", testIssuer, "XLM", "native", stats)
+	r.PublishOrderbook("XLM", "native", "USD", testIssuer, stats)
+
+	assert.JSONEq(t, `{"data": {"orderbookUpdates": {
+		"bidCount": 2,
+		"bidMax": 0.2,
+		"askCount": 3,
+		"askMin": 0.3,
+		"spread": 0.33333333333333326
+	}}}`, receive(t, updates))
+}
+
+func TestMarketUpdatesValidation(t *testing.T) {
+	_, schema := newTestSchema(t)
+	responses, err := schema.Subscribe(context.Background(), `subscription { marketUpdates(baseAssetCode: "XLM", baseAssetIssuer: "native", counterAssetCode: "USD", counterAssetIssuer: "`+testIssuer+`", numHoursAgo: 200) { tradePair } }`, "", nil)
+	require.NoError(t, err)
+	assert.Contains(t, receive(t, responses), "numHoursAgo cannot be greater than 168 (7 days)")
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	r, schema := newTestSchema(t)
+	server := httptest.NewServer(r.NewWebSocketHandler(schema))
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "graphql-ws", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	read := func() operationMessage {
+		var msg operationMessage
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(subscribeTimeout)))
+		require.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+
+	require.NoError(t, conn.WriteJSON(operationMessage{Type: gqlConnectionInit}))
+	assert.Equal(t, gqlConnectionAck, read().Type)
+	assert.Equal(t, gqlConnectionKeepAlive, read().Type)
+
+	payload, err := json.Marshal(startPayload{Query: tradesQuery})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(operationMessage{ID: "1", Type: gqlStart, Payload: payload}))
+	// Messages are handled in order, so the subscription is started once the
+	// error for the unknown message is received.
+	require.NoError(t, conn.WriteJSON(operationMessage{ID: "2", Type: "unknown"}))
+	msg := read()
+	assert.Equal(t, operationMessage{ID: "2", Type: gqlError, Payload: json.RawMessage(`{"message":"unknown message type: unknown"}`)}, msg)
+
+	r.PublishTrade(testTrade("3-0"))
+	msg = read()
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, gqlData, msg.Type)
+	assert.JSONEq(t, `{"data": {"trades": {
+		"horizonID": "3-0",
+		"baseAssetCode": "XLM",
+		"counterAssetCode": "USD",
+		"baseAmount": 20,
+		"counterAmount": 5,
+		"price": 0.25,
+		"baseIsSeller": true
+	}}}`, string(msg.Payload))
+
+	require.NoError(t, conn.WriteJSON(operationMessage{ID: "1", Type: gqlStop}))
+	assert.Equal(t, operationMessage{ID: "1", Type: gqlComplete}, read())
+
+	require.NoError(t, conn.WriteJSON(operationMessage{Type: gqlConnectionTerminate}))
+	_, _, err = conn.ReadMessage()
+	assert.Error(t, err)
+}
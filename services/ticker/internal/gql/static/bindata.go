@@ -1,7 +1,7 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
 // graphiql.html (1.182kB)
-// schema.gql (2.514kB)
+// schema.gql (4.566kB)
 
 package static
 
@@ -90,7 +90,7 @@ func graphiqlHtml() (*asset, error) {
 	return a, nil
 }
 
-var _schemaGql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x54\x51\x6f\xe3\x36\x0c\x7e\xb6\x7f\x05\xdb\x7b\x69\x81\x21\x0f\xc3\x9e\x8c\x6d\x40\xda\x6e\x58\xb1\xcb\xed\x76\x69\x87\x01\x41\x31\x30\x16\xe3\x10\x91\x25\x1f\x25\xa5\x0d\x0e\xfd\xef\x83\xe4\x24\x95\xe3\xb6\xc0\x9e\xf7\x64\xf3\x23\x3f\x8a\xfa\x44\xd2\xd5\x6b\x6a\x11\xbe\x95\xc5\xd7\x40\xb2\xab\xa0\xf8\x33\x7e\xcb\xe7\xb2\xf4\xbb\x8e\x20\x59\xd1\xfd\x01\x84\xbc\x30\x6d\x09\x50\x6b\xd8\xa2\x66\x85\x9e\x14\xa0\x73\xe4\x1d\x58\x03\x7e\x4d\x30\xf7\xa4\x35\x0a\x18\xf2\x8f\x56\x36\x93\xb2\xe8\xfd\x15\x2c\xa6\xf1\xe7\xec\xe1\xac\x7c\x27\x19\x3b\x17\x48\xde\xc9\xb6\x0f\xa8\x60\x71\x9b\xfe\x46\xf9\xbc\xa0\x22\x70\x1e\xbd\x83\x95\xd8\x36\xe5\xd1\xe8\x3c\xfc\x68\x42\xfb\x9b\x0d\xe2\xa6\x8d\xfd\x19\xd6\xf1\x2f\x32\x2f\x14\xad\x30\x68\x0f\x3f\xc1\xf7\x3f\xf4\xf0\xe5\x04\x6c\xe7\xd9\x1a\xd4\x7a\x07\x9d\xd8\x2d\x2b\x82\xda\x06\xe3\x49\x00\x8d\x8a\xbc\x25\x3a\xea\x2f\x0f\x6c\x56\x16\x56\x56\x60\xc5\xda\x93\xb0\x69\x26\x65\xd1\xa2\x6c\xc8\xbb\x8b\xb2\x28\x62\x68\xba\xfd\xb5\x55\x54\xc1\xdc\xc7\x90\x1c\xef\xef\x92\x79\xf6\x67\xbd\x46\xca\x5d\x23\x5e\x76\xc5\x0a\x6e\x8d\x2f\x8b\xcb\x0a\x16\xb3\x54\xca\x48\xf9\xa6\x11\x6a\x92\xec\x03\xd1\xac\xbc\xa1\x59\x64\x27\x7d\x5e\x95\x07\x41\xb3\xf3\x60\x57\xd0\x21\xcb\x27\x6c\xc9\xc1\x05\x4d\xa2\x14\x1f\x60\x71\xfe\xf7\xc7\xd9\x3f\x57\x77\xd7\xe7\xdf\x41\xfa\xbd\x9f\xdf\x9c\x3f\x5c\x82\x15\x40\x70\x6c\x1a\x4d\x50\x07\x11\x32\xf5\xae\x67\xa5\xb0\xf3\xcb\x48\x1e\x28\x0b\x42\x2e\x68\xef\x26\x65\xe1\xb9\xde\x90\x5c\x24\x4d\x06\x12\x1d\x0b\xa8\x60\xd1\x83\x0f\x6f\x6a\x33\x3d\xaa\x70\x54\xe9\xb9\x2c\x5d\x8d\xb1\xef\xae\xb8\x89\x81\x7b\xeb\x8e\x5b\xda\x8f\x45\x52\x3f\x8e\x45\x7e\xf2\xd9\xa1\x3d\xa7\x75\x7a\xa4\x0c\x8f\xa4\xcc\x34\xa1\xdd\xc7\xb8\x54\xca\x59\x59\x60\xf0\xeb\x2f\xf4\x35\xb0\x90\xaa\xe0\xca\x5a\x4d\x68\x8e\xf8\xd6\xd6\xb8\xd4\x34\x70\xb4\xfd\x19\xbf\x6a\x8b\x29\x41\xdf\x2b\xc6\x8b\xd5\x9a\xd4\xd5\xee\xc6\xb6\xc8\x66\x40\x31\xf5\xda\x8e\x9b\x6a\xe8\xb9\x1b\x96\xca\x2e\xa1\xd3\x14\x30\x2c\x4d\xb1\xeb\x34\xee\x6e\xa8\xe6\x16\xb5\xab\xf6\x72\xc5\xfb\x61\x9b\xe7\x50\xe4\xea\xcc\xac\xad\x51\x1c\xfb\xc7\x65\xe0\x8a\x9f\x48\x7d\x0a\xed\x32\xf6\xf3\x31\x51\x8b\x4f\x23\x8c\xdd\xbd\xd1\xdc\xb2\x1f\x56\x23\xa4\xa8\x4d\x6d\x79\x6b\x9c\x97\x50\x9f\x9e\x50\x5b\xad\xd1\x93\xa0\x9e\x2a\x25\xe4\x1c\xbd\xeb\x9d\x73\x63\xd0\x07\x39\x89\x0a\x26\x8e\x4f\x8e\xc5\xb1\x09\x6e\xd4\x04\xb7\x37\xfb\xa7\x3d\xac\xd2\xbe\xbf\x62\xd3\xa4\x71\xfb\x8c\x2c\x19\xe9\xd5\x1d\x91\xe3\xc3\x59\x3f\xd4\xf2\xca\x8e\x38\x71\x8d\x78\x31\xe3\x5f\x56\x87\xf8\x44\x87\xe6\xd9\x13\x4e\xe1\x54\xe8\x75\xdf\x67\xbd\xf8\xb6\x23\xf3\xe2\xd7\xf6\xf1\xc5\x58\x73\xb3\xce\x32\xae\xd1\x34\xf9\x09\xda\xba\xcc\xe4\x78\xdc\x16\xf5\xdc\xa3\xf8\x2a\x8d\x56\x6a\x02\x71\xfe\x23\xa9\x86\xe4\x3a\xc6\x47\xf8\xe8\x8c\x0b\xe9\x2d\x9f\x15\x45\xb2\xb4\x76\x33\x8f\x3b\xac\x82\x3f\x06\xf6\xcb\x1b\x9c\x4e\xfb\x7f\x7d\x8d\x77\x34\xff\x3f\xcb\x3a\xc4\xe1\x5b\x09\xc5\x92\xd5\xfe\x86\xc7\xc1\x5d\xb2\x3a\x55\x62\xc9\x6a\x86\x4f\xf9\x12\xdb\x9c\xb2\xd0\x6d\x4e\x59\xe8\x36\x33\xce\xf4\x72\x9d\x10\xaa\x53\x7b\xc6\xea\xb3\xe5\x6c\x45\x1e\xaa\xed\x27\x22\x3e\x7d\x17\x96\x9a\xeb\xdf\x69\x97\xef\xe6\xe1\xee\x0a\xa2\xf3\x3d\x6e\x5b\x7d\xff\xe5\x63\xbe\xb7\x48\x91\x60\xdc\x35\x73\x92\xed\x60\xd0\xe2\xea\x1e\x81\x5e\xd0\xb8\x15\xc9\xc8\xf1\x48\xcb\x69\xf0\xeb\x5f\x8c\xea\xfa\xaa\xb3\xf5\xd9\x59\xc7\x7e\xc4\xb0\xd2\xdc\x3d\xb2\xf7\x39\xf8\x5c\xfe\x1b\x00\x00\xff\xff\x0b\x65\xd4\x2d\xd2\x09\x00\x00")
+var _schemaGql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xe5\x58\xdf\x6f\xdb\x36\x10\x7e\xb6\xff\x0a\x3a\x79\x89\x01\x27\x6b\x86\xee\xc5\xc8\x02\x38\x4e\x87\x06\x4b\x9a\xae\x4e\xba\x01\x45\xb1\xd2\xd2\x59\x22\x42\x91\x0a\x49\xd9\xf1\x86\xfe\xef\xbb\x23\x25\x9b\xf2\x2f\x6c\x18\xd0\x3d\xec\x21\x89\x74\x24\x8f\x77\xdf\x7d\xf7\x91\x8a\x4d\x72\x28\x38\xfb\xb3\xdb\x79\xae\xc0\x2c\x87\xac\xf3\x0b\xfd\xed\x76\x6c\x35\xb5\x89\x11\xa5\x13\x5a\x0d\xd9\x24\x7a\xeb\x7e\xed\x76\xdd\xb2\x04\xe6\x67\xd2\xd2\x63\x66\xc0\x19\x01\x73\x60\x5c\x4a\x36\xe7\x52\xa4\xdc\x41\xca\xb8\xb5\xe0\x2c\xd3\x8a\xb9\x1c\xd8\xc4\x81\x94\xdc\x30\x05\x6e\xa1\xcd\xd3\x59\xb7\x13\xc6\x87\xec\xd3\x88\x1e\x7a\x9f\x7b\xdd\x03\xce\x84\xb5\xb8\xe1\x01\x6f\xf5\x04\x74\x77\xe3\x9f\xb6\xfc\x39\xc3\x53\x60\xd6\x71\x8c\x69\x66\x74\xe1\xfd\x48\x6e\x1d\xbb\x50\x55\xf1\x56\x57\xc6\x8e\x32\x7d\xc9\x72\x7a\xa2\x95\x27\x29\xcc\x78\x25\x1d\xfb\x91\x7d\xff\x3a\x98\xfb\x67\x4c\x7b\x18\x30\xb8\x25\x2b\x8d\x9e\x0b\xf4\x99\xe8\x4a\x39\x30\x8c\xab\x94\xd6\x4d\xb9\x85\x90\x3c\x13\x6a\xa6\xd9\x4c\x1b\x36\x13\x12\x67\x08\x95\x61\xa4\x05\x37\x4f\x98\xf8\x49\xb7\xd3\xa1\xa9\x3e\xfb\xb1\x4e\x01\x81\x76\x34\x25\xb6\x87\x5c\xa2\x91\x7a\xaf\x5d\x8b\xe2\xa1\xad\x75\x51\x8a\x43\x76\xa3\x5c\xb7\xd3\x47\xa8\xee\x7c\x28\x5b\xc8\x67\x99\x81\xcc\xc3\xde\x02\x0d\xf3\xd8\x8d\x19\xad\xf6\xf8\xec\x84\x87\x33\x29\x70\x85\x9e\xb1\x92\x0b\xf3\x8e\x17\x60\xd9\x09\x9c\x11\x14\xc7\xec\xd3\xd1\x6f\xb7\x77\xbf\x5f\x3d\x8c\x8f\x06\xcc\x3f\x3e\x4e\xae\x8f\x3e\xf7\x19\xee\xc5\x99\xc5\xd8\x25\xe2\x5b\x19\x03\x2a\x59\x86\x55\x7e\xda\x51\x9f\x16\xb7\x90\xc5\xf8\x2d\x56\xcb\xa2\x5b\x27\x92\x27\x30\x27\x1e\x93\x16\x44\xab\x00\x30\xf7\x60\xfc\xbc\x17\x9b\xd1\x0a\x85\xdd\x28\x11\x14\xf7\x6f\x6f\xc7\x1f\x59\x82\x85\x97\x98\x14\x66\xc8\x7d\x8e\xfe\x29\xb0\x7f\x1d\x32\x25\x39\xbc\x08\x34\xbd\x0c\xf1\x73\xe7\xbd\x64\x62\x0e\x8a\xc2\xd7\xb2\x22\xf4\xd8\xc9\x79\x31\x60\x3f\xe0\xcf\xb9\xff\x95\x0f\xd8\xeb\x9c\x10\x39\x4f\xfb\x03\x44\x18\x14\x56\x66\x8a\xcc\x07\x50\xe4\xe6\x82\xe8\x7c\x19\xf3\xf5\xfc\xd5\xab\x57\xab\xb0\xa6\x80\x38\x01\xbb\x70\xfa\xb2\x4f\x1c\xf5\x4f\xf1\x6c\xa5\x17\x7d\x5f\x8c\x3a\x66\x6e\x9a\x98\xb8\x65\xe3\xfb\xeb\x37\xc3\x9b\xc9\xe4\xf1\xcd\x87\x01\xc5\x80\x99\x78\xdc\x65\x55\x80\xc2\x82\x63\x0e\x85\xc6\xf2\xd2\x96\xe4\xa3\xd9\x15\xff\xe2\xce\x2b\xb8\x52\x9a\xa8\x55\x02\xb8\x51\x3d\xe5\xa4\x2e\x48\x53\x9f\x1e\xbe\xaf\x41\x88\xad\x94\xdf\x90\x3d\x88\x02\xf0\xc5\xe9\xe6\x91\xaa\x34\xf6\xbe\xa8\xd9\x51\x98\x8e\x59\x2c\x5c\x21\x11\x0b\x86\x76\xd7\x73\x6c\xd0\x5f\x61\x3a\xd1\x48\x0d\xd7\xc8\xc8\x77\x99\xe1\x65\xfe\x2c\x19\xa8\xb4\xd4\x42\xb9\x01\xab\x88\x74\xe8\xc9\x17\x26\x8c\x9e\x2e\x2c\x91\xd9\xe9\x44\xcb\x33\x86\xc9\xa8\x1a\x24\xad\x88\xe6\xd5\x14\xf9\x9d\x53\x49\x96\x61\x37\x94\xa9\x45\x2e\x92\x1c\xfb\xc6\x00\x2f\xd0\x99\x82\x45\x68\x25\x24\x84\x05\x60\x5f\x02\x43\xc3\x74\x76\x7a\x1a\x26\x7e\xc1\x2a\x78\x6d\x8d\x05\xb7\x91\xd8\x04\x44\x4d\xba\xb8\x27\x91\x68\x6b\x06\x6d\x50\xaf\x25\x72\x9e\x27\xdb\x3a\xb7\x53\xe4\x30\x7a\xa4\x18\x01\xc6\xd7\x91\xa3\x63\xf2\x41\xfe\xfc\x3e\xc2\xa2\xba\x65\x60\xb1\x3f\x56\x9a\xf6\x58\x92\x58\xef\x57\xb6\xde\x7e\x69\xeb\x1d\xd0\xb6\xde\x41\x71\xeb\xed\xeb\xe0\xd0\xb6\x4d\xd7\x06\xfc\x28\xab\x65\x2b\xa9\xbd\xf8\x91\x8e\xf8\x92\x7d\xf3\x74\x30\xf6\x07\xda\xb9\x1d\x3a\xc5\xa9\x4d\x0a\x66\xaa\xf5\xd3\xdf\x28\x7f\x53\xc4\xba\x6a\x4b\xcf\x58\x03\x33\x6c\x31\x22\x2b\x9f\xb9\x76\x81\x31\xdf\x95\xfb\xff\xaa\x90\x98\xf9\x7d\x13\xc3\x84\x32\xf4\x6d\x6d\x13\x4e\x67\xfd\x95\xc8\xa8\xb4\xf5\x9b\x97\x80\xd0\x2e\xde\x17\xf5\x49\xd2\xde\x2b\x68\xed\x28\xf1\x5b\x46\x76\x5a\x14\xbd\x22\x79\xea\x39\xd6\x93\x07\x4d\xbc\x72\xf9\x07\x78\xae\x84\x81\x74\xc8\xae\xb4\x96\xc0\xd5\xca\x3e\xd7\x09\x9f\x4a\x68\x0d\x14\x61\x8f\x9f\xa4\xe6\xde\x41\x48\x5d\x39\xa3\xa5\x84\xf4\x6a\x79\xad\x0b\x2e\x54\x6b\x89\x4a\x72\xbd\x13\xa3\x68\xe4\xa1\x1d\xaa\xb0\xde\x3a\xf2\x13\xda\xa1\xa5\xc2\x96\x92\x2f\xaf\x21\x11\x05\x97\x98\x49\x80\x8b\xf2\xc3\x03\x2f\xf2\x81\x8c\x4e\xa2\xd7\x44\xab\x54\x78\xb9\x8c\x8c\x33\xf1\x02\xe9\xbb\xaa\x98\x52\x75\x56\x8e\x0a\xfe\xb2\x65\x13\xf6\x51\x49\x51\x08\xd7\x8e\x06\x83\x83\xc2\xeb\xd7\x8d\x42\x71\xab\x92\xcd\x1d\x50\x4a\x25\x52\xcc\x70\x39\x4a\x53\xa4\xa4\x85\x83\xa3\x13\x91\x29\xee\x2a\xb3\x31\x0b\x31\xc7\xd3\x25\xb6\x51\x5f\x54\x76\x8b\x04\x37\xd7\x75\x69\x9b\xeb\x6b\x10\x07\x22\x8d\x27\xff\xfb\xf6\x39\xb4\x8f\xf4\xfb\x39\x7f\x80\xf2\x07\x19\x4f\x1e\x3f\x6a\x3a\x4c\xd7\xe4\xa9\x17\x6c\x9a\x7d\xa0\xe3\xc0\xb3\x00\x3e\x5d\x05\xd6\xe3\x52\x2f\xd6\x2f\xb9\xc8\xf2\xc8\x63\xce\x51\xab\xa3\x77\xa9\x6d\xf4\x2a\x68\x3b\xbc\x6b\x63\xcb\x19\x17\x4e\x57\x4f\x02\x63\xdd\x2d\xa4\x19\x98\x31\xcd\x27\xf3\x6a\x90\xce\x94\x7d\x63\xba\xd5\xc2\x3b\x5b\x3a\xf4\xed\xc6\x0d\xeb\x9f\x56\xe3\x00\xe6\xff\x67\x58\xfd\xd9\x41\x58\xa2\x46\x88\x3f\xb0\x03\xaf\x23\x60\xe4\x1e\xe7\xdf\x96\xf1\xa3\x0d\xb9\x6c\x16\x6c\x98\x4b\x23\x92\x08\x4f\x5a\x79\x63\x27\xf8\xe5\xe7\x25\xa8\x11\x9b\x26\xef\x36\x1e\x08\x00\xeb\x4c\x45\x5a\x57\x76\x25\x58\x68\xda\x64\x00\x9a\xee\xf8\x4b\x2c\xde\x4f\x9b\xab\xd0\xb4\xb9\x0a\x4d\x77\x22\xe2\x89\x2d\xf1\x0e\x97\x6e\xbe\xdf\x89\xf4\x3d\x5d\x2c\x57\xf6\x26\xda\x80\x0b\x95\xc9\x5f\x20\x93\x9f\x61\x19\x9f\x49\x6d\xcd\xae\x8c\x8c\xcf\x2f\x5d\xc8\xc7\x0f\xb7\xb1\x5e\xa3\xdc\x1a\x4e\x1a\x3b\xf1\x17\xd0\xf8\x30\xc1\x23\x6b\xcb\x88\x9c\x57\x76\x06\x66\x6b\x60\x01\xd3\x11\x2e\x78\x53\x5f\x87\x5b\xc7\x46\xa9\xad\x70\x5b\x2b\xb4\xc9\x1e\x16\xc2\xb9\xd8\xe8\xaf\xe2\xbe\x78\xe1\x9e\x0c\x2f\xa5\x97\x78\xfc\x8c\x57\xac\x52\x62\x7d\x73\x59\x7d\x3a\xfb\x23\xbc\xc4\x27\x1a\xae\x47\xbb\xf1\xb7\xf4\x19\x9b\x2f\x78\x49\xd7\x4e\x5a\x37\xf7\xc5\x38\x5d\x00\xf6\xa3\xff\x5f\x03\x06\xc5\x33\x08\x9b\xfa\x8f\x1c\x47\xb3\x83\x13\x27\xf6\x4f\x6d\xe2\xf0\x9f\x11\xf5\xf5\x3b\x7c\x53\x50\x69\x48\x09\x5a\x6d\x92\x6c\x77\x65\x4b\x2c\xda\xfa\xd0\x92\x8e\x0d\x71\xf8\x57\x0a\x45\x50\x44\xe3\xf1\xdb\xd7\xee\x5f\xf1\x4e\x07\x2e\xd6\x11\x00\x00")
 
 func schemaGqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -106,7 +106,7 @@ func schemaGql() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "schema.gql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7a, 0x78, 0xf7, 0x84, 0xb0, 0x32, 0xc9, 0x9f, 0xd3, 0x8b, 0x8e, 0x30, 0x2d, 0xe2, 0xf7, 0xba, 0x39, 0xd2, 0x82, 0xc, 0xea, 0xcf, 0x1f, 0xd9, 0xd2, 0xfd, 0xf8, 0xc4, 0xb0, 0xd8, 0x9d, 0x69}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe1, 0xf2, 0xdc, 0xfb, 0xef, 0x79, 0xa4, 0x9c, 0x84, 0x92, 0xc, 0x88, 0x40, 0xdc, 0xf8, 0x49, 0x88, 0x48, 0x4b, 0x62, 0x6, 0x8d, 0x24, 0x6f, 0x31, 0x25, 0x91, 0x5c, 0x16, 0x62, 0xe9, 0x36}}
 	return a, nil
 }
 
@@ -0,0 +1,241 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	hlog "github.com/stellar/go/support/log"
+)
+
+// Message types of the graphql-ws protocol, see
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionKeepAlive = "ka"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+const (
+	// wsWriteTimeout is the time allowed to write a message to a client.
+	wsWriteTimeout = 10 * time.Second
+	// wsKeepAliveInterval is the interval between keep-alive messages.
+	wsKeepAliveInterval = 30 * time.Second
+	// wsMaxMessageSize is the maximum size of a message sent by a client.
+	wsMaxMessageSize = 64 * 1024
+)
+
+// operationMessage is a message of the graphql-ws protocol.
+type operationMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// startPayload is the payload of a "start" message.
+type startPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// WebSocketHandler serves GraphQL operations, including subscriptions, over
+// WebSocket connections using the graphql-ws protocol.
+type WebSocketHandler struct {
+	Schema *graphql.Schema
+	Logger *hlog.Entry
+
+	upgrader websocket.Upgrader
+}
+
+// NewWebSocketHandler sets up the WebSocket handler for the given schema.
+func (r *resolver) NewWebSocketHandler(schema *graphql.Schema) *WebSocketHandler {
+	return &WebSocketHandler{
+		Schema: schema,
+		Logger: r.logger,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{"graphql-ws"},
+			// The ticker data is public, so connections are accepted from
+			// any origin, like regular GraphQL requests.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied to the client with an HTTP error.
+		h.Logger.Errorln("could not upgrade connection:", err)
+		return
+	}
+
+	c := &wsConnection{
+		conn:       conn,
+		schema:     h.Schema,
+		logger:     h.Logger,
+		operations: map[string]*wsOperation{},
+	}
+	c.serve()
+}
+
+// wsConnection is a WebSocket connection with a client and the operations
+// the client started.
+type wsConnection struct {
+	conn   *websocket.Conn
+	schema *graphql.Schema
+	logger *hlog.Entry
+
+	writeMu sync.Mutex
+
+	operationsMu sync.Mutex
+	operations   map[string]*wsOperation
+}
+
+// wsOperation is an operation started by a client.
+type wsOperation struct {
+	cancel context.CancelFunc
+}
+
+// serve reads the messages of the client until the connection is closed or
+// terminated, stopping all the operations of the client afterwards.
+func (c *wsConnection) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer c.conn.Close()
+
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	initialized := false
+	for {
+		var msg operationMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.logger.Debugln("closing websocket connection:", err)
+			}
+			return
+		}
+
+		switch msg.Type {
+		case gqlConnectionInit:
+			c.write(operationMessage{Type: gqlConnectionAck})
+			if !initialized {
+				initialized = true
+				c.write(operationMessage{Type: gqlConnectionKeepAlive})
+				go c.keepAlive(ctx)
+			}
+		case gqlConnectionTerminate:
+			return
+		case gqlStart:
+			c.start(ctx, msg)
+		case gqlStop:
+			c.stop(msg.ID)
+		default:
+			c.writeError(msg.ID, "unknown message type: "+msg.Type)
+		}
+	}
+}
+
+// start starts the operation of a "start" message and sends its results to
+// the client until the operation completes or is stopped.
+func (c *wsConnection) start(ctx context.Context, msg operationMessage) {
+	var payload startPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		c.writeError(msg.ID, "invalid payload")
+		return
+	}
+
+	c.operationsMu.Lock()
+	if _, exists := c.operations[msg.ID]; exists {
+		c.operationsMu.Unlock()
+		c.writeError(msg.ID, "an operation with this id is already running")
+		return
+	}
+	opCtx, cancel := context.WithCancel(ctx)
+	op := &wsOperation{cancel: cancel}
+	c.operations[msg.ID] = op
+	c.operationsMu.Unlock()
+
+	responses, err := c.schema.Subscribe(opCtx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		c.finish(msg.ID, op)
+		c.writeError(msg.ID, err.Error())
+		return
+	}
+
+	go func() {
+		// responses must be drained until it is closed, which happens once
+		// the operation completes or its context is cancelled.
+		for response := range responses {
+			data, err := json.Marshal(response)
+			if err != nil {
+				c.logger.Errorln("could not marshal response:", err)
+				continue
+			}
+			c.write(operationMessage{ID: msg.ID, Type: gqlData, Payload: data})
+		}
+		c.finish(msg.ID, op)
+		c.write(operationMessage{ID: msg.ID, Type: gqlComplete})
+	}()
+}
+
+// stop cancels the operation with the given id, if it is running.
+func (c *wsConnection) stop(id string) {
+	c.operationsMu.Lock()
+	defer c.operationsMu.Unlock()
+	if op, ok := c.operations[id]; ok {
+		op.cancel()
+		delete(c.operations, id)
+	}
+}
+
+// finish releases an operation once it is completed. The operation is only
+// removed if it was not stopped and replaced by a new operation with the same id.
+func (c *wsConnection) finish(id string, op *wsOperation) {
+	c.operationsMu.Lock()
+	defer c.operationsMu.Unlock()
+	op.cancel()
+	if c.operations[id] == op {
+		delete(c.operations, id)
+	}
+}
+
+func (c *wsConnection) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(wsKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.write(operationMessage{Type: gqlConnectionKeepAlive})
+		}
+	}
+}
+
+func (c *wsConnection) writeError(id, message string) {
+	payload, _ := json.Marshal(struct {
+		Message string `json:"message"`
+	}{message})
+	c.write(operationMessage{ID: id, Type: gqlError, Payload: payload})
+}
+
+// write sends a message to the client. Write errors are only logged since
+// they cause the next read of the connection to fail, which closes it.
+func (c *wsConnection) write(msg operationMessage) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		c.logger.Debugln("could not write websocket message:", err)
+	}
+}
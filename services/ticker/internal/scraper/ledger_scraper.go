@@ -0,0 +1,372 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/exp/orderbook/ingest"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/ticker/internal/utils"
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// maxOrderbookPriceLevels mirrors the limit used when fetching orderbooks from Horizon.
+const maxOrderbookPriceLevels = 200
+
+// LedgerScraper derives trades and orderbooks directly from ledger data instead of
+// scraping Horizon. The offers of the network are kept in an in-memory orderbook graph
+// which is updated with the offer changes of every scraped ledger.
+type LedgerScraper struct {
+	Backend           ledgerbackend.LedgerBackend
+	Archive           historyarchive.ArchiveInterface
+	NetworkPassphrase string
+	Logger            *hlog.Entry
+	Graph             *orderbook.OrderBookGraph
+
+	newStateReader func(ctx context.Context, archive historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error)
+}
+
+// Market is a pair of assets, ordered according to the rules of NormalizeTradeAssets.
+type Market struct {
+	Base    xdr.Asset
+	Counter xdr.Asset
+}
+
+// LedgerData represents the trades and the markets with changed orderbooks of a single ledger.
+type LedgerData struct {
+	Sequence  uint32
+	CloseTime time.Time
+	Trades    []hProtocol.Trade
+	Markets   []Market
+}
+
+// LatestCheckpoint returns the sequence of the latest checkpoint in the history archive.
+func (c *LedgerScraper) LatestCheckpoint() (uint32, error) {
+	has, err := c.Archive.GetRootHAS()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get root HAS")
+	}
+	return has.CurrentLedger, nil
+}
+
+// LoadOrderbook rebuilds the orderbook graph as of the end of the given ledger, starting
+// from the latest checkpoint at or before it and replaying the offer changes of the
+// subsequent ledgers. The ledger backend is left prepared to stream every ledger after it.
+func (c *LedgerScraper) LoadOrderbook(ctx context.Context, ledger uint32) error {
+	if ledger < historyarchive.CheckpointFreq-1 {
+		return errors.Errorf("ledger %d precedes the first checkpoint", ledger)
+	}
+	checkpoint := historyarchive.PrevCheckpoint(ledger)
+
+	// Prepare an unbounded range so that the backend can keep streaming the
+	// ledgers after the replayed ones.
+	ledgerRange := ledgerbackend.UnboundedRange(checkpoint + 1)
+	prepared, err := c.Backend.IsPrepared(ledgerRange)
+	if err != nil {
+		return errors.Wrap(err, "error checking prepared range")
+	}
+	if !prepared {
+		if err = c.Backend.PrepareRange(ledgerRange); err != nil {
+			return errors.Wrap(err, "error preparing range")
+		}
+	}
+
+	ingester := &ingest.Ingester{
+		Graph:             c.Graph,
+		LedgerBackend:     c.Backend,
+		HistoryArchive:    c.Archive,
+		NetworkPassphrase: c.NetworkPassphrase,
+		Log:               c.Logger,
+		NewStateReader:    c.newStateReader,
+	}
+	return ingester.ReplayTo(ctx, ledger)
+}
+
+// ScrapeLedger applies the offer changes of the given ledger to the orderbook graph and
+// returns the trades of the ledger along with the markets whose orderbooks changed.
+// Ledgers must be scraped in order. io.ErrNotFound is returned if the ledger is not
+// available yet.
+func (c *LedgerScraper) ScrapeLedger(sequence uint32) (LedgerData, error) {
+	reader, err := io.NewLedgerChangeReader(c.Backend, c.NetworkPassphrase, sequence)
+	if err == io.ErrNotFound {
+		return LedgerData{}, err
+	}
+	if err != nil {
+		return LedgerData{}, errors.Wrap(err, "could not create ledger reader")
+	}
+	defer reader.Close()
+
+	header := reader.GetHeader()
+	data := LedgerData{
+		Sequence:  sequence,
+		CloseTime: time.Unix(int64(header.Header.ScpValue.CloseTime), 0).UTC(),
+	}
+
+	for {
+		var tx io.LedgerTransaction
+		tx, err = reader.LedgerTransactionReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return LedgerData{}, errors.Wrap(err, "could not read transaction")
+		}
+
+		var trades []hProtocol.Trade
+		trades, err = extractTrades(header, tx)
+		if err != nil {
+			return LedgerData{}, errors.Wrapf(err, "could not extract trades from transaction %d", tx.Index)
+		}
+		data.Trades = append(data.Trades, trades...)
+	}
+	reader.LedgerTransactionReader.Rewind()
+
+	data.Markets, err = applyOfferChanges(c.Graph, reader)
+	if err != nil {
+		c.Graph.Discard()
+		return LedgerData{}, err
+	}
+	if err = c.Graph.Apply(sequence); err != nil {
+		return LedgerData{}, err
+	}
+	return data, nil
+}
+
+// FetchOrderbookForMarket computes the orderbook stats of the base and counter assets
+// from the orderbook graph, in the same way as FetchOrderbookForAssets does for orderbooks
+// fetched from Horizon.
+func (c *LedgerScraper) FetchOrderbookForMarket(base, counter xdr.Asset) (OrderbookStats, error) {
+	bType, bCode, bIssuer := AssetFields(base)
+	cType, cCode, cIssuer := AssetFields(counter)
+	obStats := OrderbookStats{
+		BaseAssetCode:      bCode,
+		BaseAssetType:      bType,
+		BaseAssetIssuer:    bIssuer,
+		CounterAssetCode:   cCode,
+		CounterAssetType:   cType,
+		CounterAssetIssuer: cIssuer,
+		HighestBid:         math.Inf(-1), // start with -Inf to make sure we catch the correct max bid
+		LowestAsk:          math.Inf(1),  // start with +Inf to make sure we catch the correct min ask
+	}
+
+	summary, err := ingest.FindOrderBookSummary(c.Graph, base, counter, maxOrderbookPriceLevels)
+	if err != nil {
+		return obStats, errors.Wrap(err, "could not find orderbook")
+	}
+
+	err = calcOrderbookStats(&obStats, summary)
+	if err != nil {
+		return obStats, errors.Wrap(err, "could not calculate orderbook stats")
+	}
+	return obStats, nil
+}
+
+// AssetFields returns the type, code and issuer of an asset as they are stored by the
+// ticker, i.e. the native asset has a "XLM" code and a "native" issuer.
+func AssetFields(asset xdr.Asset) (assetType, code, issuer string) {
+	if err := asset.Extract(&assetType, &code, &issuer); err != nil {
+		panic(err)
+	}
+	if assetType == "native" {
+		code = "XLM"
+		issuer = "native"
+	}
+	return
+}
+
+// NewMarket returns the market of two assets, ordered according to the rules of
+// NormalizeTradeAssets.
+func NewMarket(a, b xdr.Asset) Market {
+	if a.Type == xdr.AssetTypeAssetTypeNative {
+		return Market{Base: a, Counter: b}
+	}
+	if b.Type == xdr.AssetTypeAssetTypeNative {
+		return Market{Base: b, Counter: a}
+	}
+
+	aType, aCode, aIssuer := AssetFields(a)
+	bType, bCode, bIssuer := AssetFields(b)
+	if utils.GetAssetString(aType, aCode, aIssuer) > utils.GetAssetString(bType, bCode, bIssuer) {
+		return Market{Base: b, Counter: a}
+	}
+	return Market{Base: a, Counter: b}
+}
+
+// applyOfferChanges applies the offer changes read from reader to the graph, without
+// committing them, and returns the markets of the changed offers.
+func applyOfferChanges(graph *orderbook.OrderBookGraph, reader io.ChangeReader) ([]Market, error) {
+	var markets []Market
+	seen := map[string]bool{}
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			return markets, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read change")
+		}
+		if change.Type != xdr.LedgerEntryTypeOffer {
+			continue
+		}
+
+		var offer xdr.OfferEntry
+		if change.Post == nil {
+			offer = change.Pre.Data.MustOffer()
+			graph.RemoveOffer(offer.OfferId)
+		} else {
+			offer = change.Post.Data.MustOffer()
+			graph.AddOffer(offer)
+		}
+
+		market := NewMarket(offer.Selling, offer.Buying)
+		key := market.Base.String() + "/" + market.Counter.String()
+		if !seen[key] {
+			seen[key] = true
+			markets = append(markets, market)
+		}
+	}
+}
+
+// operationID returns the id Horizon assigns to an operation, see
+// services/horizon/internal/toid.
+func operationID(ledger uint32, txIndex uint32, opIndex int) int64 {
+	return int64(ledger)<<32 | int64(txIndex)<<12 | int64(opIndex+1)
+}
+
+// synthetic offer ids are used by Horizon for trades of offers which were filled
+// immediately and were never assigned an offer id.
+const toidOfferIDType = uint64(1) << 62
+
+// findTradeSellPrice returns the price of the offer claimed by a trade, before the trade.
+func findTradeSellPrice(tx io.LedgerTransaction, opIndex int, trade xdr.ClaimOfferAtom) (xdr.Price, error) {
+	key := xdr.LedgerKey{}
+	if err := key.SetOffer(trade.SellerId, uint64(trade.OfferId)); err != nil {
+		return xdr.Price{}, errors.Wrap(err, "could not create offer ledger key")
+	}
+
+	changes, err := tx.GetOperationChanges(uint32(opIndex))
+	if err != nil {
+		return xdr.Price{}, errors.Wrap(err, "could not determine changes for operation")
+	}
+
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.Pre != nil && key.Equals(change.Pre.LedgerKey()) {
+			return change.Pre.Data.MustOffer().Price, nil
+		}
+	}
+	return xdr.Price{}, errors.New("could not find change for trade offer")
+}
+
+// extractTrades returns the trades of a transaction in the format of the Horizon trades
+// endpoint, normalized with NormalizeTradeAssets. Trade ids match the ids assigned by Horizon.
+func extractTrades(header xdr.LedgerHeaderHistoryEntry, tx io.LedgerTransaction) ([]hProtocol.Trade, error) {
+	if !tx.Result.Successful() {
+		return nil, nil
+	}
+
+	opResults, ok := tx.Result.OperationResults()
+	if !ok {
+		return nil, errors.New("transaction has no operation results")
+	}
+
+	closeTime := time.Unix(int64(header.Header.ScpValue.CloseTime), 0).UTC()
+	var trades []hProtocol.Trade
+	for opIndex, op := range tx.Envelope.Operations() {
+		var claimed []xdr.ClaimOfferAtom
+		var buyOffer xdr.OfferEntry
+		var buyOfferExists bool
+
+		switch op.Body.Type {
+		case xdr.OperationTypePathPaymentStrictReceive:
+			claimed = opResults[opIndex].MustTr().MustPathPaymentStrictReceiveResult().MustSuccess().Offers
+		case xdr.OperationTypePathPaymentStrictSend:
+			claimed = opResults[opIndex].MustTr().MustPathPaymentStrictSendResult().MustSuccess().Offers
+		case xdr.OperationTypeManageBuyOffer:
+			result := opResults[opIndex].MustTr().MustManageBuyOfferResult().MustSuccess()
+			claimed = result.OffersClaimed
+			buyOffer, buyOfferExists = result.Offer.GetOffer()
+		case xdr.OperationTypeManageSellOffer:
+			result := opResults[opIndex].MustTr().MustManageSellOfferResult().MustSuccess()
+			claimed = result.OffersClaimed
+			buyOffer, buyOfferExists = result.Offer.GetOffer()
+		case xdr.OperationTypeCreatePassiveSellOffer:
+			result := opResults[opIndex].MustTr()
+			// stellar-core creates results for CreatePassiveSellOffer operations
+			// with the ManageSellOffer result arm set.
+			if result.Type == xdr.OperationTypeManageSellOffer {
+				success := result.MustManageSellOfferResult().MustSuccess()
+				claimed = success.OffersClaimed
+				buyOffer, buyOfferExists = success.Offer.GetOffer()
+			} else {
+				success := result.MustCreatePassiveSellOfferResult().MustSuccess()
+				claimed = success.OffersClaimed
+				buyOffer, buyOfferExists = success.Offer.GetOffer()
+			}
+		default:
+			continue
+		}
+
+		buyer := tx.Envelope.SourceAccount().ToAccountId()
+		if op.SourceAccount != nil {
+			buyer = op.SourceAccount.ToAccountId()
+		}
+
+		opID := operationID(uint32(header.Header.LedgerSeq), tx.Index, opIndex)
+		counterOfferID := fmt.Sprintf("%d", uint64(opID)|toidOfferIDType)
+		if buyOfferExists {
+			counterOfferID = fmt.Sprintf("%d", buyOffer.OfferId)
+		}
+
+		for order, claim := range claimed {
+			// stellar-core garbage collects offers whose owner cannot fulfill them
+			// any longer and includes them in the result with zero amounts. These
+			// are not trades.
+			if claim.AmountBought == 0 && claim.AmountSold == 0 {
+				continue
+			}
+
+			sellPrice, err := findTradeSellPrice(tx, opIndex, claim)
+			if err != nil {
+				return nil, err
+			}
+
+			trade := hProtocol.Trade{
+				ID:              fmt.Sprintf("%d-%d", opID, order),
+				LedgerCloseTime: closeTime,
+				OfferID:         fmt.Sprintf("%d", claim.OfferId),
+				BaseOfferID:     fmt.Sprintf("%d", claim.OfferId),
+				BaseAccount:     claim.SellerId.Address(),
+				BaseAmount:      amount.String(claim.AmountSold),
+				CounterOfferID:  counterOfferID,
+				CounterAccount:  buyer.Address(),
+				CounterAmount:   amount.String(claim.AmountBought),
+				BaseIsSeller:    true,
+				Price:           &hProtocol.Price{N: int32(sellPrice.N), D: int32(sellPrice.D)},
+			}
+			trade.PT = trade.ID
+			trade.BaseAssetType, trade.BaseAssetCode, trade.BaseAssetIssuer = assetStrings(claim.AssetSold)
+			trade.CounterAssetType, trade.CounterAssetCode, trade.CounterAssetIssuer = assetStrings(claim.AssetBought)
+			NormalizeTradeAssets(&trade)
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
+
+// assetStrings returns the type, code and issuer of an asset as they are returned by Horizon.
+func assetStrings(asset xdr.Asset) (assetType, code, issuer string) {
+	if err := asset.Extract(&assetType, &code, &issuer); err != nil {
+		panic(err)
+	}
+	return
+}
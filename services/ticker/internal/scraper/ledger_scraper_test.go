@@ -0,0 +1,280 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/orderbook"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/io"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sellerAccount = keypair.MustRandom().Address()
+	buyerAccount  = keypair.MustRandom().Address()
+	issuerAccount = keypair.MustRandom().Address()
+	nativeAsset   = xdr.MustNewNativeAsset()
+	usdAsset      = xdr.MustNewCreditAsset("USD", issuerAccount)
+	eurAsset      = xdr.MustNewCreditAsset("EUR", issuerAccount)
+)
+
+func offerLedgerEntry(id int64, amount int64) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeOffer,
+			Offer: &xdr.OfferEntry{
+				SellerId: xdr.MustAddress(sellerAccount),
+				OfferId:  xdr.Int64(id),
+				Selling:  usdAsset,
+				Buying:   nativeAsset,
+				Price:    xdr.Price{N: 2, D: 1},
+				Amount:   xdr.Int64(amount),
+			},
+		},
+	}
+}
+
+// tradeLedger returns a ledger with a single transaction in which the buyer
+// sells 20 XLM for 10 USD, consuming part of the seller's offer 7.
+func tradeLedger(t *testing.T, sequence uint32) xdr.LedgerCloseMeta {
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(buyerAccount),
+				Fee:           100,
+				SeqNum:        1,
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type: xdr.OperationTypeManageSellOffer,
+							ManageSellOfferOp: &xdr.ManageSellOfferOp{
+								Selling: nativeAsset,
+								Buying:  usdAsset,
+								Amount:  200000000,
+								Price:   xdr.Price{N: 1, D: 2},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	results := []xdr.OperationResult{
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypeManageSellOffer,
+				ManageSellOfferResult: &xdr.ManageSellOfferResult{
+					Code: xdr.ManageSellOfferResultCodeManageSellOfferSuccess,
+					Success: &xdr.ManageOfferSuccessResult{
+						OffersClaimed: []xdr.ClaimOfferAtom{
+							{
+								SellerId:     xdr.MustAddress(sellerAccount),
+								OfferId:      7,
+								AssetSold:    usdAsset,
+								AmountSold:   100000000,
+								AssetBought:  nativeAsset,
+								AmountBought: 200000000,
+							},
+						},
+						Offer: xdr.ManageOfferSuccessResultOffer{
+							Effect: xdr.ManageOfferEffectManageOfferDeleted,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerVersion: 14,
+					LedgerSeq:     xdr.Uint32(sequence),
+					ScpValue:      xdr.StellarValue{CloseTime: 1600000000},
+				},
+			},
+			TxSet: xdr.TransactionSet{Txs: []xdr.TransactionEnvelope{envelope}},
+			TxProcessing: []xdr.TransactionResultMeta{
+				{
+					Result: xdr.TransactionResultPair{
+						TransactionHash: hash,
+						Result: xdr.TransactionResult{
+							FeeCharged: 100,
+							Result: xdr.TransactionResultResult{
+								Code:    xdr.TransactionResultCodeTxSuccess,
+								Results: &results,
+							},
+						},
+					},
+					TxApplyProcessing: xdr.TransactionMeta{
+						V: 2,
+						V2: &xdr.TransactionMetaV2{
+							Operations: []xdr.OperationMeta{
+								{
+									Changes: xdr.LedgerEntryChanges{
+										{
+											Type:  xdr.LedgerEntryChangeTypeLedgerEntryState,
+											State: offerLedgerEntry(7, 300000000),
+										},
+										{
+											Type:    xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+											Updated: offerLedgerEntry(7, 200000000),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestLedgerScraper() (*LedgerScraper, *historyarchive.MockArchive, *ledgerbackend.MockDatabaseBackend) {
+	archive := &historyarchive.MockArchive{}
+	backend := &ledgerbackend.MockDatabaseBackend{}
+	return &LedgerScraper{
+		Backend:           backend,
+		Archive:           archive,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		Logger:            hlog.New(),
+		Graph:             orderbook.NewOrderBookGraph(),
+	}, archive, backend
+}
+
+func TestScrapeLedger(t *testing.T) {
+	sc, _, backend := newTestLedgerScraper()
+	backend.On("GetLedger", uint32(100)).Return(true, tradeLedger(t, 100), nil).Once()
+	backend.On("GetLedger", uint32(101)).Return(false, xdr.LedgerCloseMeta{}, nil).Once()
+
+	data, err := sc.ScrapeLedger(100)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), data.Sequence)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), data.CloseTime)
+	assert.Equal(t, []Market{{Base: nativeAsset, Counter: usdAsset}}, data.Markets)
+
+	// The trade is normalized so that XLM is the base asset.
+	require.Len(t, data.Trades, 1)
+	trade := data.Trades[0]
+	assert.Equal(t, hProtocol.Trade{
+		ID:                 "429496733697-0",
+		PT:                 "429496733697-0",
+		LedgerCloseTime:    time.Unix(1600000000, 0).UTC(),
+		OfferID:            "7",
+		BaseOfferID:        "7",
+		BaseAccount:        buyerAccount,
+		BaseAmount:         "20.0000000",
+		BaseAssetType:      "native",
+		BaseAssetCode:      "XLM",
+		BaseAssetIssuer:    "native",
+		CounterOfferID:     "4611686447924121601",
+		CounterAccount:     sellerAccount,
+		CounterAmount:      "10.0000000",
+		CounterAssetType:   "credit_alphanum4",
+		CounterAssetCode:   "USD",
+		CounterAssetIssuer: issuerAccount,
+		BaseIsSeller:       false,
+		Price:              &hProtocol.Price{N: 1, D: 2},
+	}, trade)
+
+	offers := sc.Graph.OffersMap()
+	assert.Len(t, offers, 1)
+	assert.Equal(t, xdr.Int64(200000000), offers[7].Amount)
+
+	_, err = sc.ScrapeLedger(101)
+	assert.Equal(t, io.ErrNotFound, err)
+	backend.AssertExpectations(t)
+}
+
+func TestLoadOrderbook(t *testing.T) {
+	sc, _, backend := newTestLedgerScraper()
+	sc.newStateReader = func(_ context.Context, _ historyarchive.ArchiveInterface, sequence uint32) (io.ChangeReader, error) {
+		assert.Equal(t, uint32(63), sequence)
+		reader := &io.MockChangeReader{}
+		reader.On("Read").Return(io.Change{Type: xdr.LedgerEntryTypeOffer, Post: offerLedgerEntry(7, 300000000)}, nil).Once()
+		reader.On("Read").Return(io.Change{}, io.EOF).Once()
+		reader.On("Close").Return(nil).Once()
+		return reader, nil
+	}
+	backend.On("IsPrepared", ledgerbackend.UnboundedRange(64)).Return(false, nil).Once()
+	backend.On("PrepareRange", ledgerbackend.UnboundedRange(64)).Return(nil).Once()
+	backend.On("IsPrepared", ledgerbackend.BoundedRange(64, 64)).Return(true, nil).Once()
+	backend.On("GetLedger", uint32(64)).Return(true, tradeLedger(t, 64), nil).Once()
+
+	require.NoError(t, sc.LoadOrderbook(context.Background(), 64))
+	offers := sc.Graph.OffersMap()
+	assert.Len(t, offers, 1)
+	assert.Equal(t, xdr.Int64(200000000), offers[7].Amount)
+	backend.AssertExpectations(t)
+
+	assert.EqualError(t, sc.LoadOrderbook(context.Background(), 10), "ledger 10 precedes the first checkpoint")
+}
+
+func TestFetchOrderbookForMarket(t *testing.T) {
+	sc, _, _ := newTestLedgerScraper()
+	sc.Graph.AddOffer(xdr.OfferEntry{
+		SellerId: xdr.MustAddress(sellerAccount),
+		OfferId:  1,
+		Selling:  nativeAsset,
+		Buying:   usdAsset,
+		Price:    xdr.Price{N: 1, D: 2},
+		Amount:   100000000,
+	})
+	sc.Graph.AddOffer(xdr.OfferEntry{
+		SellerId: xdr.MustAddress(sellerAccount),
+		OfferId:  2,
+		Selling:  nativeAsset,
+		Buying:   usdAsset,
+		Price:    xdr.Price{N: 2, D: 4},
+		Amount:   100000000,
+	})
+	sc.Graph.AddOffer(xdr.OfferEntry{
+		SellerId: xdr.MustAddress(sellerAccount),
+		OfferId:  3,
+		Selling:  usdAsset,
+		Buying:   nativeAsset,
+		Price:    xdr.Price{N: 5, D: 2},
+		Amount:   100000000,
+	})
+	require.NoError(t, sc.Graph.Apply(1))
+
+	stats, err := sc.FetchOrderbookForMarket(nativeAsset, usdAsset)
+	require.NoError(t, err)
+	assert.Equal(t, "XLM", stats.BaseAssetCode)
+	assert.Equal(t, "USD", stats.CounterAssetCode)
+	assert.Equal(t, 1, stats.NumAsks)
+	assert.Equal(t, 0.5, stats.LowestAsk)
+	assert.Equal(t, 10.0, stats.AskVolume)
+	assert.Equal(t, 1, stats.NumBids)
+	assert.Equal(t, 0.4, stats.HighestBid)
+	assert.Equal(t, 10.0, stats.BidVolume)
+
+	stats, err = sc.FetchOrderbookForMarket(nativeAsset, eurAsset)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.NumAsks)
+	assert.Equal(t, 0, stats.NumBids)
+}
+
+func TestNewMarket(t *testing.T) {
+	assert.Equal(t, Market{Base: nativeAsset, Counter: usdAsset}, NewMarket(usdAsset, nativeAsset))
+	assert.Equal(t, Market{Base: nativeAsset, Counter: usdAsset}, NewMarket(nativeAsset, usdAsset))
+	assert.Equal(t, Market{Base: eurAsset, Counter: usdAsset}, NewMarket(usdAsset, eurAsset))
+	assert.Equal(t, Market{Base: eurAsset, Counter: usdAsset}, NewMarket(eurAsset, usdAsset))
+}
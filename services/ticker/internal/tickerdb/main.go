@@ -100,6 +100,34 @@ type OrderbookStats struct {
 	UpdatedAt      time.Time `db:"updated_at"`
 }
 
+// IngestionCursor represents an entry on the ingestion_cursors table
+type IngestionCursor struct {
+	Name      string    `db:"name"`
+	Ledger    int32     `db:"ledger"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Candle represents an entry on the candles table. Prices are expressed in
+// units of the counter asset per unit of the base asset.
+type Candle struct {
+	ID             int32     `db:"id"`
+	BaseAssetID    int32     `db:"base_asset_id"`
+	CounterAssetID int32     `db:"counter_asset_id"`
+	Resolution     int32     `db:"resolution"`
+	OpenTime       time.Time `db:"open_time"`
+	Open           float64   `db:"open"`
+	High           float64   `db:"high"`
+	Low            float64   `db:"low"`
+	Close          float64   `db:"close"`
+	BaseVolume     float64   `db:"base_volume"`
+	CounterVolume  float64   `db:"counter_volume"`
+	TradeCount     int32     `db:"trade_count"`
+	VWAP           float64   `db:"vwap"`
+	TWAP           float64   `db:"twap"`
+	InverseTWAP    float64   `db:"inverse_twap"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
 // Market represent the aggregated market data retrieved from the database.
 // Note: this struct does *not* directly map to a db entity.
 type Market struct {
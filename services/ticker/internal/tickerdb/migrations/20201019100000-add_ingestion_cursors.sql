@@ -0,0 +1,10 @@
+
+-- +migrate Up
+CREATE TABLE ingestion_cursors (
+    name text NOT NULL PRIMARY KEY,
+    ledger integer NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE ingestion_cursors;
@@ -10,6 +10,8 @@
 // migrations/20190411165735-data_seed_and_indices.sql (1.522kB)
 // migrations/20190425110313-add_orderbook_stats.sql (749B)
 // migrations/20190426092321-add_aggregated_orderbook_view.sql (831B)
+// migrations/20201019100000-add_ingestion_cursors.sql (201B)
+// migrations/20201020100000-add_candles_table.sql (883B)
 
 package bdata
 
@@ -278,6 +280,46 @@ func migrations20190426092321Add_aggregated_orderbook_viewSql() (*asset, error)
 	return a, nil
 }
 
+var _migrations20201019100000Add_ingestion_cursorsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x8e\x31\x0b\xc2\x30\x14\x84\xf7\xf7\x2b\xde\xa8\x68\x7f\x41\xa7\x68\x33\x88\xb1\x2d\xa1\x1d\x3a\x95\x60\x1f\x25\x60\xd2\x92\xbc\xa2\xf8\xeb\x8d\x8a\xe2\xe2\x2d\x37\xdc\xc7\xdd\x41\x96\xe1\xc6\xd9\x31\x18\x26\x6c\x67\xd8\x6b\x29\x1a\x89\x8d\xd8\x29\x89\xd6\x8f\x14\xd9\x4e\xbe\x3f\x2f\x21\x4e\x21\xe2\x0a\x30\xc9\x1b\x47\xc8\x74\x63\x2c\xab\x06\xcb\x56\x29\xac\xf5\xe1\x24\x74\x87\x47\xd9\x6d\x5f\xcc\x85\x86\x91\x42\xaa\x60\x7a\xfa\x07\x7c\x87\xcb\x3c\xa4\xbd\xa1\x37\x8c\x6c\x5d\xda\x30\x6e\xe6\xfb\x17\x82\x75\x0e\xf0\x7b\xac\x98\xae\x1e\x0a\x5d\xd5\xff\x8e\xe5\xf0\x00\x7e\xd8\x4e\xa7\xc9\x00\x00\x00")
+
+func migrations20201019100000Add_ingestion_cursorsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20201019100000Add_ingestion_cursorsSql,
+		"migrations/20201019100000-add_ingestion_cursors.sql",
+	)
+}
+
+func migrations20201019100000Add_ingestion_cursorsSql() (*asset, error) {
+	bytes, err := migrations20201019100000Add_ingestion_cursorsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20201019100000-add_ingestion_cursors.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xca, 0x45, 0x1d, 0x20, 0x1b, 0x5a, 0xa, 0x8c, 0xb5, 0xc1, 0xb, 0x73, 0x55, 0xd6, 0xd8, 0x86, 0xfa, 0x98, 0x35, 0x4, 0x17, 0xb3, 0xf, 0xea, 0xb8, 0x6a, 0xc9, 0xd6, 0xfa, 0x46, 0x37, 0x3b}}
+	return a, nil
+}
+
+var _migrations20201020100000Add_candles_tableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x9d\x93\xb1\x6e\xc2\x30\x10\x86\x77\x3f\xc5\x8d\xa0\x86\xbe\x00\x53\x4a\x5c\x09\x35\x4d\xa8\x49\x06\x26\xcb\xc4\x27\xb0\x48\xe2\xc8\x76\x40\xed\xd3\xd7\x44\x10\x88\xda\x2a\xa8\x1e\xbc\xf8\xbb\x5f\xbe\xf3\xe7\xd9\x0c\x9e\x2a\xb5\x33\xc2\x21\xe4\x0d\x59\x30\x1a\x66\x14\xb2\xf0\x25\xa6\x50\x88\x5a\x96\x68\x61\x42\xc0\x2f\x25\xc1\xa2\x51\xa2\x84\x24\xcd\x20\xc9\xe3\x18\x56\x6c\xf9\x1e\xb2\x0d\xbc\xd1\x4d\x40\x3a\x68\x2b\x2c\x72\x61\x2d\x3a\xee\x79\x55\x3b\xdc\xa1\x01\x46\x5f\x29\xa3\xc9\x82\xae\xa1\x3b\xf3\x91\x4a\x4e\xfb\x9c\xa0\x2b\x2d\x74\xeb\x71\xf3\xcf\x6a\x83\x56\x97\xad\x53\xba\xee\xeb\x86\x80\x6e\xb0\xe6\x4e\x55\x08\xe7\xcd\x3a\x51\x35\xee\xeb\x8e\xe9\x21\x90\xba\xdd\x96\x08\x8d\xc1\x42\xd9\x73\xe0\x30\x68\xaf\x76\xfb\x31\xa6\xd4\xa7\x31\xa4\x28\xb5\xc5\x31\xa8\x1b\xe7\xd1\x37\x56\x8d\xa2\xd7\xf1\x3d\x46\x3b\x23\x24\xf2\xae\xe6\x8f\x79\x1d\x4f\xa2\x19\x4d\x79\x80\x51\xf5\x11\x8d\xef\x62\x8c\xed\xe0\xb6\x91\x5e\x44\xc9\x85\xfb\xf5\x95\xc8\x74\x4e\xc2\x38\xa3\xec\x22\x68\x9a\xc4\x1b\x68\x7c\xa4\x2a\x9e\x2f\xb2\x76\x31\x61\x14\xc1\x22\x4d\xd6\x19\x0b\x97\x49\x76\xf5\x98\x57\xc2\x1c\xbc\x5a\x37\x55\x78\x2f\x05\x3f\xe0\x27\xe4\xc9\xf2\x23\xa7\x30\x19\x48\x1c\xfc\x10\x33\xb8\x93\x2d\xb8\x79\xe5\xef\x46\x66\x77\xbf\x29\xd2\xa7\x9a\x44\x2c\x5d\x0d\x7f\xd3\x9c\x7c\x03\xc5\x92\x74\x37\x73\x03\x00\x00")
+
+func migrations20201020100000Add_candles_tableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20201020100000Add_candles_tableSql,
+		"migrations/20201020100000-add_candles_table.sql",
+	)
+}
+
+func migrations20201020100000Add_candles_tableSql() (*asset, error) {
+	bytes, err := migrations20201020100000Add_candles_tableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20201020100000-add_candles_table.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc0, 0x13, 0xdd, 0xbb, 0xd7, 0xde, 0xb6, 0x8b, 0x14, 0x7d, 0x51, 0x38, 0xc4, 0x3f, 0xd0, 0xde, 0x56, 0x28, 0xb3, 0xfa, 0x44, 0x3, 0x70, 0xd9, 0x45, 0x99, 0x90, 0xd9, 0x6e, 0x8d, 0x0, 0x3f}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -379,17 +421,21 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/20190411165735-data_seed_and_indices.sql":           migrations20190411165735Data_seed_and_indicesSql,
 	"migrations/20190425110313-add_orderbook_stats.sql":             migrations20190425110313Add_orderbook_statsSql,
 	"migrations/20190426092321-add_aggregated_orderbook_view.sql":   migrations20190426092321Add_aggregated_orderbook_viewSql,
+	"migrations/20201019100000-add_ingestion_cursors.sql":           migrations20201019100000Add_ingestion_cursorsSql,
+	"migrations/20201020100000-add_candles_table.sql":               migrations20201020100000Add_candles_tableSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -433,6 +479,8 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"20190411165735-data_seed_and_indices.sql":           &bintree{migrations20190411165735Data_seed_and_indicesSql, map[string]*bintree{}},
 		"20190425110313-add_orderbook_stats.sql":             &bintree{migrations20190425110313Add_orderbook_statsSql, map[string]*bintree{}},
 		"20190426092321-add_aggregated_orderbook_view.sql":   &bintree{migrations20190426092321Add_aggregated_orderbook_viewSql, map[string]*bintree{}},
+		"20201019100000-add_ingestion_cursors.sql":           &bintree{migrations20201019100000Add_ingestion_cursorsSql, map[string]*bintree{}},
+		"20201020100000-add_candles_table.sql":               &bintree{migrations20201020100000Add_candles_tableSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -0,0 +1,148 @@
+package tickerdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// CandleResolutions are the resolutions, in seconds, of the candles maintained
+// for every market, indexed by their name. Every resolution divides a day, so
+// that candles are aligned on UTC days.
+var CandleResolutions = map[string]int32{
+	"1m":  60,
+	"5m":  5 * 60,
+	"15m": 15 * 60,
+	"1h":  60 * 60,
+	"4h":  4 * 60 * 60,
+	"1d":  24 * 60 * 60,
+}
+
+// RefreshCandles recomputes, from the trades table, the candles of every market and
+// resolution which contain or follow since. Passing the zero time backfills the
+// candles of every stored trade.
+func (s *TickerSession) RefreshCandles(since time.Time) error {
+	for _, resolution := range CandleResolutions {
+		if err := s.refreshCandles(resolution, since, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshMarketCandles recomputes, from the trades table, the candles of the market
+// with the given base and counter assets which contain or follow since.
+func (s *TickerSession) RefreshMarketCandles(baseAssetID, counterAssetID int32, since time.Time) error {
+	for _, resolution := range CandleResolutions {
+		err := s.refreshCandles(
+			resolution,
+			since,
+			"AND base_asset_id = ? AND counter_asset_id = ?",
+			baseAssetID,
+			counterAssetID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RetrieveCandles returns the candles of the given resolution of the market between
+// the two given assets, in either direction, opened in the [from, to) interval and
+// sorted by opening time.
+func (s *TickerSession) RetrieveCandles(
+	baseAssetID, counterAssetID int32,
+	resolution int32,
+	from, to time.Time,
+) (candles []Candle, err error) {
+	err = s.SelectRaw(&candles, `
+		SELECT * FROM candles
+		WHERE resolution = ? AND open_time >= ? AND open_time < ?
+		AND (
+			(base_asset_id = ? AND counter_asset_id = ?)
+			OR (base_asset_id = ? AND counter_asset_id = ?)
+		)
+		ORDER BY open_time ASC`,
+		resolution, from, to,
+		baseAssetID, counterAssetID,
+		counterAssetID, baseAssetID,
+	)
+	return
+}
+
+// refreshCandles upserts the candles of the given resolution which contain or follow
+// since, for the trades matching the extra filter.
+//
+// The VWAP of a candle is its counter volume over its base volume. For the TWAP, the
+// price of each trade is weighted by the time until the next trade of the candle, or
+// until the end of the candle (or now, for the current candle) for its last trade.
+// The inverse TWAP is computed the same way with the inverted prices, since it is not
+// the inverse of the TWAP.
+func (s *TickerSession) refreshCandles(resolution int32, since time.Time, filter string, filterArgs ...interface{}) error {
+	query := fmt.Sprintf(`
+		INSERT INTO candles (
+			base_asset_id, counter_asset_id, resolution, open_time,
+			open, high, low, close, base_volume, counter_volume, trade_count,
+			vwap, twap, inverse_twap, updated_at
+		)
+		SELECT
+			base_asset_id,
+			counter_asset_id,
+			%[1]d,
+			open_time,
+			(array_agg(price ORDER BY ledger_close_time ASC, horizon_id ASC))[1],
+			max(price),
+			min(price),
+			(array_agg(price ORDER BY ledger_close_time DESC, horizon_id DESC))[1],
+			sum(base_amount),
+			sum(counter_amount),
+			count(*),
+			COALESCE(sum(counter_amount) / NULLIF(sum(base_amount), 0), avg(price)),
+			COALESCE(sum(price * duration) / NULLIF(sum(duration), 0), avg(price)),
+			COALESCE(
+				sum(duration / NULLIF(price, 0)) / NULLIF(sum(duration), 0),
+				avg(1 / NULLIF(price, 0)),
+				0
+			),
+			now()
+		FROM (
+			SELECT t.*, GREATEST(EXTRACT(epoch FROM LEAST(
+				COALESCE(
+					lead(t.ledger_close_time) OVER (
+						PARTITION BY t.base_asset_id, t.counter_asset_id, t.open_time
+						ORDER BY t.ledger_close_time ASC, t.horizon_id ASC
+					),
+					t.open_time + interval '%[1]d seconds'
+				),
+				now()
+			) - t.ledger_close_time), 0) AS duration
+			FROM (
+				SELECT
+					base_asset_id, counter_asset_id, horizon_id, ledger_close_time,
+					price, base_amount, counter_amount,
+					to_timestamp(floor(EXTRACT(epoch FROM ledger_close_time) / %[1]d) * %[1]d) AS open_time
+				FROM trades
+				WHERE ledger_close_time >= to_timestamp(floor(EXTRACT(epoch FROM ?::timestamptz) / %[1]d) * %[1]d)
+				%[2]s
+			) t
+		) d
+		GROUP BY base_asset_id, counter_asset_id, open_time
+		ON CONFLICT ON CONSTRAINT candles_market_resolution_open_time_key DO UPDATE SET
+			open = EXCLUDED.open,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			close = EXCLUDED.close,
+			base_volume = EXCLUDED.base_volume,
+			counter_volume = EXCLUDED.counter_volume,
+			trade_count = EXCLUDED.trade_count,
+			vwap = EXCLUDED.vwap,
+			twap = EXCLUDED.twap,
+			inverse_twap = EXCLUDED.inverse_twap,
+			updated_at = EXCLUDED.updated_at`,
+		resolution, filter,
+	)
+
+	args := append([]interface{}{since}, filterArgs...)
+	_, err := s.ExecRaw(query, args...)
+	return err
+}
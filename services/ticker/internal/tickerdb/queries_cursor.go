@@ -0,0 +1,33 @@
+package tickerdb
+
+import (
+	"time"
+)
+
+// GetIngestionCursor returns the last ledger ingested by the ingestion process with the
+// given name. found is false if the process has not ingested any ledger yet.
+func (s *TickerSession) GetIngestionCursor(name string) (ledger uint32, found bool, err error) {
+	var cursor IngestionCursor
+	err = s.GetRaw(&cursor, "SELECT * FROM ingestion_cursors WHERE name = ?", name)
+	if s.NoRows(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return
+	}
+	return uint32(cursor.Ledger), true, nil
+}
+
+// UpdateIngestionCursor records the given ledger as the last ledger ingested by the
+// ingestion process with the given name.
+func (s *TickerSession) UpdateIngestionCursor(name string, ledger uint32) error {
+	_, err := s.ExecRaw(`
+		INSERT INTO ingestion_cursors (name, ledger, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			ledger = EXCLUDED.ledger,
+			updated_at = EXCLUDED.updated_at`,
+		name, int32(ledger), time.Now(),
+	)
+	return err
+}
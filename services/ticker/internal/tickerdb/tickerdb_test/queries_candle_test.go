@@ -0,0 +1,152 @@
+package tickerdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandles(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+
+	var session tickerdb.TickerSession
+	session.DB = db.Open()
+	session.Ctx = context.Background()
+	defer session.DB.Close()
+
+	// Run migrations to make sure the tests are run
+	// on the most updated schema version
+	migrations := &migrate.FileMigrationSource{
+		Dir: "../migrations",
+	}
+	_, err := migrate.Exec(session.DB.DB, "postgres", migrations, migrate.Up)
+	require.NoError(t, err)
+
+	// Adding a seed issuer to be used later:
+	tbl := session.GetTable("issuers")
+	_, err = tbl.Insert(tickerdb.Issuer{
+		PublicKey: "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB",
+		Name:      "FOO BAR",
+	}).IgnoreCols("id").Exec()
+	require.NoError(t, err)
+	var issuer tickerdb.Issuer
+	err = session.GetRaw(&issuer, `
+		SELECT *
+		FROM issuers
+		ORDER BY id DESC
+		LIMIT 1`,
+	)
+	require.NoError(t, err)
+
+	// Adding the two assets of the market:
+	var assets [2]tickerdb.Asset
+	for i, code := range []string{"XLM", "BTC"} {
+		err = session.InsertOrUpdateAsset(&tickerdb.Asset{
+			Code:     code,
+			IssuerID: issuer.ID,
+		}, []string{"code", "issuer_id"})
+		require.NoError(t, err)
+		err = session.GetRaw(&assets[i], `
+			SELECT *
+			FROM assets
+			ORDER BY id DESC
+			LIMIT 1`,
+		)
+		require.NoError(t, err)
+	}
+	bID, cID := assets[0].ID, assets[1].ID
+
+	start := time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC)
+	newTrade := func(id string, offset time.Duration, baseAmount, counterAmount float64) tickerdb.Trade {
+		return tickerdb.Trade{
+			HorizonID:       id,
+			BaseAssetID:     bID,
+			CounterAssetID:  cID,
+			LedgerCloseTime: start.Add(offset),
+			BaseAmount:      baseAmount,
+			CounterAmount:   counterAmount,
+			Price:           counterAmount / baseAmount,
+		}
+	}
+	err = session.BulkInsertTrades([]tickerdb.Trade{
+		newTrade("hrzid1", 0, 10, 10),
+		newTrade("hrzid2", 30*time.Second, 10, 20),
+		newTrade("hrzid3", 70*time.Second, 1, 4),
+	})
+	require.NoError(t, err)
+
+	// Backfill the candles of every stored trade:
+	err = session.RefreshCandles(time.Time{})
+	require.NoError(t, err)
+
+	candles, err := session.RetrieveCandles(bID, cID, 60, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+
+	c := candles[0]
+	assert.True(t, start.Equal(c.OpenTime))
+	assert.Equal(t, int32(60), c.Resolution)
+	assert.Equal(t, 1.0, c.Open)
+	assert.Equal(t, 2.0, c.High)
+	assert.Equal(t, 1.0, c.Low)
+	assert.Equal(t, 2.0, c.Close)
+	assert.Equal(t, 20.0, c.BaseVolume)
+	assert.Equal(t, 30.0, c.CounterVolume)
+	assert.Equal(t, int32(2), c.TradeCount)
+	assert.InDelta(t, 1.5, c.VWAP, 1e-9)
+	// Both prices last 30 seconds:
+	assert.InDelta(t, 1.5, c.TWAP, 1e-9)
+	assert.InDelta(t, 0.75, c.InverseTWAP, 1e-9)
+
+	c = candles[1]
+	assert.True(t, start.Add(time.Minute).Equal(c.OpenTime))
+	assert.Equal(t, 4.0, c.Open)
+	assert.Equal(t, 4.0, c.Close)
+	assert.Equal(t, int32(1), c.TradeCount)
+	assert.InDelta(t, 4.0, c.TWAP, 1e-9)
+	assert.InDelta(t, 0.25, c.InverseTWAP, 1e-9)
+
+	// Candles of every resolution are maintained:
+	candles, err = session.RetrieveCandles(bID, cID, 3600, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, 1.0, candles[0].Open)
+	assert.Equal(t, 4.0, candles[0].Close)
+	assert.Equal(t, 4.0, candles[0].High)
+	assert.Equal(t, int32(3), candles[0].TradeCount)
+	assert.InDelta(t, (30*1.0+40*2.0+3530*4.0)/3600, candles[0].TWAP, 1e-9)
+
+	// Markets can be retrieved in both directions, and only the candles
+	// opened in the requested interval are returned:
+	candles, err = session.RetrieveCandles(cID, bID, 60, start.Add(time.Minute), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, bID, candles[0].BaseAssetID)
+	assert.True(t, start.Add(time.Minute).Equal(candles[0].OpenTime))
+
+	// New trades update the candles they belong to:
+	err = session.BulkInsertTrades([]tickerdb.Trade{newTrade("hrzid4", 100*time.Second, 2, 4)})
+	require.NoError(t, err)
+	err = session.RefreshMarketCandles(bID, cID, start.Add(100*time.Second))
+	require.NoError(t, err)
+
+	candles, err = session.RetrieveCandles(bID, cID, 60, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	assert.Equal(t, int32(2), candles[0].TradeCount)
+	c = candles[1]
+	assert.Equal(t, 4.0, c.Open)
+	assert.Equal(t, 2.0, c.Close)
+	assert.Equal(t, 3.0, c.BaseVolume)
+	assert.Equal(t, 8.0, c.CounterVolume)
+	assert.Equal(t, int32(2), c.TradeCount)
+	assert.InDelta(t, 8.0/3.0, c.VWAP, 1e-9)
+	assert.InDelta(t, (30*4.0+20*2.0)/50, c.TWAP, 1e-9)
+}
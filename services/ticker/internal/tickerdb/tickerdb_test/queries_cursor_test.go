@@ -0,0 +1,60 @@
+package tickerdb_test
+
+import (
+	"context"
+	"testing"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionCursor(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+
+	var session tickerdb.TickerSession
+	session.DB = db.Open()
+	session.Ctx = context.Background()
+	defer session.DB.Close()
+
+	// Run migrations to make sure the tests are run
+	// on the most updated schema version
+	migrations := &migrate.FileMigrationSource{
+		Dir: "../migrations",
+	}
+	_, err := migrate.Exec(session.DB.DB, "postgres", migrations, migrate.Up)
+	require.NoError(t, err)
+
+	_, found, err := session.GetIngestionCursor("ledgers")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	err = session.UpdateIngestionCursor("ledgers", 100)
+	require.NoError(t, err)
+	ledger, found, err := session.GetIngestionCursor("ledgers")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(100), ledger)
+
+	// Cursors are updated in place and are independent of each other:
+	err = session.UpdateIngestionCursor("ledgers", 101)
+	require.NoError(t, err)
+	ledger, _, err = session.GetIngestionCursor("ledgers")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledger)
+
+	_, found, err = session.GetIngestionCursor("other")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Updates are discarded when the transaction they belong to is rolled back:
+	require.NoError(t, session.Begin())
+	require.NoError(t, session.UpdateIngestionCursor("ledgers", 102))
+	require.NoError(t, session.Rollback())
+	ledger, _, err = session.GetIngestionCursor("ledgers")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledger)
+}
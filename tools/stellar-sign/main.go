@@ -2,10 +2,16 @@
 // signature to a transaction envelope.
 //
 // It prompts you for a key
+//
+// It can also convert a transaction between its base64 XDR, JSON and YAML
+// representations, so that it can be reviewed before signing:
+//
+//	stellar-sign -infile tx.xdr -convert yaml
 package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"github.com/stellar/go/keypair"
@@ -16,13 +22,16 @@ import (
 	"strings"
 
 	"github.com/howeyc/gopass"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
+	"gopkg.in/yaml.v2"
 )
 
 var in *bufio.Reader
 
-var infile = flag.String("infile", "", "transaction envelope")
+var infile = flag.String("infile", "", "transaction envelope, in base64 XDR, JSON or YAML")
+var convert = flag.String("convert", "", "print the transaction in the given format (xdr, json or yaml) instead of signing it")
 
 func main() {
 	flag.Parse()
@@ -57,6 +66,25 @@ func main() {
 	}
 
 	// parse the envelope
+	parsed, err := parseTransaction(env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *convert != "" {
+		var out string
+		out, err = formatTransaction(parsed, *convert)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(out)
+		return
+	}
+
+	env, err = formatTransaction(parsed, "xdr")
+	if err != nil {
+		log.Fatal(err)
+	}
 	var txe xdr.TransactionEnvelope
 	err = xdr.SafeUnmarshalBase64(env, &txe)
 	if err != nil {
@@ -75,7 +103,12 @@ func main() {
 	}
 	fmt.Println("")
 
-	// TODO: add operation details
+	details, err := formatTransaction(parsed, "yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Transaction Details:")
+	fmt.Println(details)
 
 	// read seed
 	seed, err := readLine("Enter seed: ", true)
@@ -89,11 +122,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	parsed, err := txnbuild.TransactionFromXDR(env)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	var newEnv string
 	if tx, ok := parsed.Transaction(); ok {
 		tx, err = tx.Sign(network.PublicNetworkPassphrase, kp)
@@ -123,6 +151,49 @@ func main() {
 
 }
 
+// parseTransaction parses a transaction in base64 XDR, JSON or YAML.
+func parseTransaction(input string) (*txnbuild.GenericTransaction, error) {
+	input = strings.TrimSpace(input)
+	if parsed, err := txnbuild.TransactionFromXDR(input); err == nil {
+		return parsed, nil
+	}
+	if strings.HasPrefix(input, "{") {
+		return txnbuild.TransactionFromJSON([]byte(input))
+	}
+	parsed, err := txnbuild.TransactionFromYAML([]byte(input))
+	if err != nil {
+		return nil, errors.Wrap(err, "input is not a transaction in base64 XDR, JSON or YAML")
+	}
+	return parsed, nil
+}
+
+// formatTransaction returns the transaction in the given format.
+func formatTransaction(parsed *txnbuild.GenericTransaction, format string) (string, error) {
+	var tx interface {
+		json.Marshaler
+		yaml.Marshaler
+		Base64() (string, error)
+	}
+	if simple, ok := parsed.Transaction(); ok {
+		tx = simple
+	} else {
+		tx, _ = parsed.FeeBump()
+	}
+
+	switch format {
+	case "xdr":
+		return tx.Base64()
+	case "json":
+		out, err := json.MarshalIndent(tx, "", "  ")
+		return string(out), err
+	case "yaml":
+		out, err := yaml.Marshal(tx)
+		return strings.TrimSuffix(string(out), "\n"), err
+	default:
+		return "", errors.Errorf("unknown format %q, expected xdr, json or yaml", format)
+	}
+}
+
 func readLine(prompt string, private bool) (string, error) {
 	fmt.Println(prompt)
 	var line string
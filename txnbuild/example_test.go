@@ -755,7 +755,7 @@ func ExampleBuildChallengeTx() {
 	anchorName := "SDF"
 	timebound := time.Duration(5 * time.Minute)
 
-	tx, err := BuildChallengeTx(serverSignerSeed, clientAccountID, anchorName, network.TestNetworkPassphrase, timebound)
+	tx, err := BuildChallengeTx(serverSignerSeed, clientAccountID, anchorName, "", "", network.TestNetworkPassphrase, timebound)
 	check(err)
 
 	txeBase64, err := tx.Base64()
@@ -0,0 +1,1009 @@
+package txnbuild
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	pricepkg "github.com/stellar/go/price"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+	"gopkg.in/yaml.v2"
+)
+
+// The JSON representation of transactions is meant for humans reviewing,
+// diffing or storing transactions. It is a lossless view of the transaction
+// envelope: converting an envelope to JSON and back yields the same XDR.
+//
+// A transaction is represented as:
+//
+//	{
+//	  "type": "transaction",
+//	  "source_account": "GA...",
+//	  "sequence": "123",
+//	  "fee": 200,
+//	  "time_bounds": {"min_time": 0, "max_time": 1600000000},
+//	  "memo": {"type": "text", "value": "hello"},
+//	  "operations": [
+//	    {"type": "payment", "destination": "GB...", "asset": "native", "amount": "10.0000000"},
+//	    {"type": "change_trust", "source_account": "GC...", "asset": "USD:GD...", "limit": "922337203685.4775807"}
+//	  ],
+//	  "signatures": [{"hint": "c0ffee01", "signature": "base64..."}]
+//	}
+//
+// The type is "transaction_v0" for transactions in a pre-protocol 13
+// envelope. Memo types are "text", "id" (value as a decimal string), "hash"
+// and "return" (values in base64). Assets are written in their canonical form,
+// "native" or "CODE:ISSUER". Operation types and fields follow the names used
+// by Horizon. A fee bump transaction is represented as:
+//
+//	{
+//	  "type": "fee_bump_transaction",
+//	  "fee_account": "GA...",
+//	  "fee": 400,
+//	  "inner_transaction": {"type": "transaction", ...},
+//	  "signatures": []
+//	}
+//
+// The YAML representation has the same structure as the JSON one.
+
+type transactionJSON struct {
+	Type             string           `json:"type"`
+	SourceAccount    string           `json:"source_account,omitempty"`
+	Sequence         int64            `json:"sequence,string,omitempty"`
+	FeeAccount       string           `json:"fee_account,omitempty"`
+	Fee              int64            `json:"fee"`
+	TimeBounds       *timeBoundsJSON  `json:"time_bounds,omitempty"`
+	Memo             *memoJSON        `json:"memo,omitempty"`
+	Operations       []operationJSON  `json:"operations,omitempty"`
+	InnerTransaction *transactionJSON `json:"inner_transaction,omitempty"`
+	Signatures       []signatureJSON  `json:"signatures"`
+}
+
+type timeBoundsJSON struct {
+	MinTime uint64 `json:"min_time"`
+	MaxTime uint64 `json:"max_time"`
+}
+
+type memoJSON struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type signatureJSON struct {
+	Hint      string `json:"hint"`
+	Signature string `json:"signature"`
+}
+
+type operationJSON struct {
+	Type                           string                 `json:"type"`
+	SourceAccount                  string                 `json:"source_account,omitempty"`
+	Destination                    string                 `json:"destination,omitempty"`
+	StartingBalance                string                 `json:"starting_balance,omitempty"`
+	Asset                          string                 `json:"asset,omitempty"`
+	AssetCode                      string                 `json:"asset_code,omitempty"`
+	Amount                         string                 `json:"amount,omitempty"`
+	SendAsset                      string                 `json:"send_asset,omitempty"`
+	SendMax                        string                 `json:"send_max,omitempty"`
+	SendAmount                     string                 `json:"send_amount,omitempty"`
+	DestAsset                      string                 `json:"dest_asset,omitempty"`
+	DestAmount                     string                 `json:"dest_amount,omitempty"`
+	DestMin                        string                 `json:"dest_min,omitempty"`
+	Path                           []string               `json:"path,omitempty"`
+	Selling                        string                 `json:"selling,omitempty"`
+	Buying                         string                 `json:"buying,omitempty"`
+	Price                          string                 `json:"price,omitempty"`
+	PriceR                         *priceJSON             `json:"price_r,omitempty"`
+	OfferID                        int64                  `json:"offer_id,string,omitempty"`
+	InflationDestination           *string                `json:"inflation_destination,omitempty"`
+	SetFlags                       []string               `json:"set_flags,omitempty"`
+	ClearFlags                     []string               `json:"clear_flags,omitempty"`
+	MasterWeight                   *Threshold             `json:"master_weight,omitempty"`
+	LowThreshold                   *Threshold             `json:"low_threshold,omitempty"`
+	MedThreshold                   *Threshold             `json:"med_threshold,omitempty"`
+	HighThreshold                  *Threshold             `json:"high_threshold,omitempty"`
+	HomeDomain                     *string                `json:"home_domain,omitempty"`
+	Signer                         *signerJSON            `json:"signer,omitempty"`
+	Limit                          string                 `json:"limit,omitempty"`
+	Trustor                        string                 `json:"trustor,omitempty"`
+	Authorize                      bool                   `json:"authorize,omitempty"`
+	AuthorizeToMaintainLiabilities bool                   `json:"authorize_to_maintain_liabilities,omitempty"`
+	Name                           string                 `json:"name,omitempty"`
+	Value                          *string                `json:"value,omitempty"`
+	BumpTo                         int64                  `json:"bump_to,string,omitempty"`
+	Claimants                      []claimantJSON         `json:"claimants,omitempty"`
+	BalanceID                      string                 `json:"balance_id,omitempty"`
+	SponsoredID                    string                 `json:"sponsored_id,omitempty"`
+	Sponsorship                    *revokeSponsorshipJSON `json:"sponsorship,omitempty"`
+}
+
+type priceJSON struct {
+	N int32 `json:"n"`
+	D int32 `json:"d"`
+}
+
+type signerJSON struct {
+	Key    string    `json:"key"`
+	Weight Threshold `json:"weight"`
+}
+
+type claimantJSON struct {
+	Destination string             `json:"destination"`
+	Predicate   xdr.ClaimPredicate `json:"predicate"`
+}
+
+type revokeSponsorshipJSON struct {
+	Type      string `json:"type"`
+	Account   string `json:"account,omitempty"`
+	Asset     string `json:"asset,omitempty"`
+	OfferID   int64  `json:"offer_id,string,omitempty"`
+	DataName  string `json:"data_name,omitempty"`
+	BalanceID string `json:"balance_id,omitempty"`
+	SignerKey string `json:"signer_key,omitempty"`
+}
+
+var accountFlagNames = []struct {
+	flag AccountFlag
+	name string
+}{
+	{AuthRequired, "auth_required"},
+	{AuthRevocable, "auth_revocable"},
+	{AuthImmutable, "auth_immutable"},
+}
+
+var revokeSponsorshipTypeNames = map[RevokeSponsorshipType]string{
+	RevokeSponsorshipTypeAccount:          "account",
+	RevokeSponsorshipTypeTrustLine:        "trustline",
+	RevokeSponsorshipTypeOffer:            "offer",
+	RevokeSponsorshipTypeData:             "data",
+	RevokeSponsorshipTypeClaimableBalance: "claimable_balance",
+	RevokeSponsorshipTypeSigner:           "signer",
+}
+
+// MarshalJSON returns the JSON representation of the transaction.
+func (t *Transaction) MarshalJSON() ([]byte, error) {
+	doc, err := transactionToJSON(t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// MarshalYAML implements yaml.Marshaler, producing the YAML representation of
+// the transaction.
+func (t *Transaction) MarshalYAML() (interface{}, error) {
+	return marshalYAML(t)
+}
+
+// MarshalJSON returns the JSON representation of the fee bump transaction.
+func (t *FeeBumpTransaction) MarshalJSON() ([]byte, error) {
+	inner, err := transactionToJSON(t.inner)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(transactionJSON{
+		Type:             "fee_bump_transaction",
+		FeeAccount:       t.feeAccount,
+		Fee:              t.maxFee,
+		InnerTransaction: &inner,
+		Signatures:       signaturesToJSON(t.signatures),
+	})
+}
+
+// MarshalYAML implements yaml.Marshaler, producing the YAML representation of
+// the fee bump transaction.
+func (t *FeeBumpTransaction) MarshalYAML() (interface{}, error) {
+	return marshalYAML(t)
+}
+
+// TransactionFromJSON parses the JSON representation of a transaction or fee
+// bump transaction.
+func TransactionFromJSON(data []byte) (*GenericTransaction, error) {
+	var doc transactionJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal transaction json")
+	}
+
+	env, err := doc.toXDR()
+	if err != nil {
+		return nil, err
+	}
+	return transactionFromParsedXDR(env)
+}
+
+// TransactionFromYAML parses the YAML representation of a transaction or fee
+// bump transaction.
+func TransactionFromYAML(data []byte) (*GenericTransaction, error) {
+	jsonData, err := yamlToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return TransactionFromJSON(jsonData)
+}
+
+// OperationToJSON returns the JSON representation of an operation.
+func OperationToJSON(op Operation) ([]byte, error) {
+	doc, err := operationToJSON(op)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// OperationFromJSON parses the JSON representation of an operation.
+func OperationFromJSON(data []byte) (Operation, error) {
+	var doc operationJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal operation json")
+	}
+	return doc.toOperation()
+}
+
+// OperationToYAML returns the YAML representation of an operation.
+func OperationToYAML(op Operation) ([]byte, error) {
+	jsonData, err := OperationToJSON(op)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := jsonToYAML(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// OperationFromYAML parses the YAML representation of an operation.
+func OperationFromYAML(data []byte) (Operation, error) {
+	jsonData, err := yamlToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return OperationFromJSON(jsonData)
+}
+
+func marshalYAML(v json.Marshaler) (interface{}, error) {
+	jsonData, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(jsonData)
+}
+
+// jsonToYAML decodes JSON into a yaml.MapSlice so the YAML representation
+// keeps the field order of the JSON one. JSON is valid YAML.
+func jsonToYAML(data []byte) (yaml.MapSlice, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "unable to convert json to yaml")
+	}
+	return doc, nil
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal yaml")
+	}
+	doc, err := yamlValueToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// yamlValueToJSON converts the maps decoded by the yaml package, which are
+// keyed by interface{}, into maps encodable as JSON.
+func yamlValueToJSON(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.Errorf("unsupported yaml key %v", k)
+			}
+			convertedValue, err := yamlValueToJSON(v)
+			if err != nil {
+				return nil, err
+			}
+			converted[key] = convertedValue
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, v := range value {
+			convertedValue, err := yamlValueToJSON(v)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = convertedValue
+		}
+		return converted, nil
+	default:
+		return value, nil
+	}
+}
+
+func transactionToJSON(t *Transaction) (transactionJSON, error) {
+	doc := transactionJSON{
+		Type:          "transaction",
+		SourceAccount: t.sourceAccount.AccountID,
+		Sequence:      t.envelope.SeqNum(),
+		Fee:           t.maxFee,
+		Signatures:    signaturesToJSON(t.signatures),
+		Operations:    make([]operationJSON, 0, len(t.operations)),
+	}
+	if t.envelope.Type == xdr.EnvelopeTypeEnvelopeTypeTxV0 {
+		doc.Type = "transaction_v0"
+	}
+	if tb := t.envelope.TimeBounds(); tb != nil {
+		doc.TimeBounds = &timeBoundsJSON{MinTime: uint64(tb.MinTime), MaxTime: uint64(tb.MaxTime)}
+	}
+
+	memo, err := memoToJSON(t.memo)
+	if err != nil {
+		return doc, err
+	}
+	doc.Memo = memo
+
+	for i, op := range t.operations {
+		opDoc, err := operationToJSON(op)
+		if err != nil {
+			return doc, errors.Wrapf(err, "unable to convert operation %d", i)
+		}
+		doc.Operations = append(doc.Operations, opDoc)
+	}
+	return doc, nil
+}
+
+func (doc transactionJSON) toXDR() (xdr.TransactionEnvelope, error) {
+	switch doc.Type {
+	case "fee_bump_transaction":
+		if doc.InnerTransaction == nil || doc.InnerTransaction.Type != "transaction" {
+			return xdr.TransactionEnvelope{}, errors.New("fee bump transaction must wrap a transaction")
+		}
+		inner, err := doc.InnerTransaction.toXDR()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "invalid inner transaction")
+		}
+		var feeSource xdr.MuxedAccount
+		if err := feeSource.SetAddress(doc.FeeAccount); err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "invalid fee_account")
+		}
+		signatures, err := signaturesFromJSON(doc.Signatures)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, err
+		}
+		return xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+			FeeBump: &xdr.FeeBumpTransactionEnvelope{
+				Tx: xdr.FeeBumpTransaction{
+					FeeSource: feeSource,
+					Fee:       xdr.Int64(doc.Fee),
+					InnerTx: xdr.FeeBumpTransactionInnerTx{
+						Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+						V1:   inner.V1,
+					},
+				},
+				Signatures: signatures,
+			},
+		}, nil
+	case "transaction", "transaction_v0":
+	default:
+		return xdr.TransactionEnvelope{}, errors.Errorf("unknown transaction type %q", doc.Type)
+	}
+
+	var source xdr.MuxedAccount
+	if err := source.SetAddress(doc.SourceAccount); err != nil {
+		return xdr.TransactionEnvelope{}, errors.Wrap(err, "invalid source_account")
+	}
+	if doc.Fee < 0 || doc.Fee > int64(^uint32(0)) {
+		return xdr.TransactionEnvelope{}, errors.Errorf("invalid fee %d", doc.Fee)
+	}
+
+	var timeBounds *xdr.TimeBounds
+	if doc.TimeBounds != nil {
+		timeBounds = &xdr.TimeBounds{
+			MinTime: xdr.TimePoint(doc.TimeBounds.MinTime),
+			MaxTime: xdr.TimePoint(doc.TimeBounds.MaxTime),
+		}
+	}
+
+	memo := xdr.Memo{Type: xdr.MemoTypeMemoNone}
+	if doc.Memo != nil {
+		parsed, err := doc.Memo.toMemo()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, err
+		}
+		if memo, err = parsed.ToXDR(); err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "invalid memo")
+		}
+	}
+
+	operations := make([]xdr.Operation, 0, len(doc.Operations))
+	for i, opDoc := range doc.Operations {
+		op, err := opDoc.toOperation()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrapf(err, "invalid operation %d", i)
+		}
+		xdrOp, err := op.BuildXDR()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrapf(err, "invalid operation %d", i)
+		}
+		operations = append(operations, xdrOp)
+	}
+
+	signatures, err := signaturesFromJSON(doc.Signatures)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, err
+	}
+
+	if doc.Type == "transaction_v0" {
+		if source.Type != xdr.CryptoKeyTypeKeyTypeEd25519 {
+			return xdr.TransactionEnvelope{}, errors.New("invalid source_account: transaction_v0 source account must be an account id")
+		}
+		return xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTxV0,
+			V0: &xdr.TransactionV0Envelope{
+				Tx: xdr.TransactionV0{
+					SourceAccountEd25519: *source.Ed25519,
+					Fee:                  xdr.Uint32(doc.Fee),
+					SeqNum:               xdr.SequenceNumber(doc.Sequence),
+					TimeBounds:           timeBounds,
+					Memo:                 memo,
+					Operations:           operations,
+				},
+				Signatures: signatures,
+			},
+		}, nil
+	}
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source,
+				Fee:           xdr.Uint32(doc.Fee),
+				SeqNum:        xdr.SequenceNumber(doc.Sequence),
+				TimeBounds:    timeBounds,
+				Memo:          memo,
+				Operations:    operations,
+			},
+			Signatures: signatures,
+		},
+	}, nil
+}
+
+func signaturesToJSON(signatures []xdr.DecoratedSignature) []signatureJSON {
+	docs := make([]signatureJSON, 0, len(signatures))
+	for _, sig := range signatures {
+		docs = append(docs, signatureJSON{
+			Hint:      hex.EncodeToString(sig.Hint[:]),
+			Signature: base64.StdEncoding.EncodeToString(sig.Signature),
+		})
+	}
+	return docs
+}
+
+func signaturesFromJSON(docs []signatureJSON) ([]xdr.DecoratedSignature, error) {
+	signatures := make([]xdr.DecoratedSignature, 0, len(docs))
+	for i, doc := range docs {
+		var sig xdr.DecoratedSignature
+		hint, err := hex.DecodeString(doc.Hint)
+		if err != nil || len(hint) != len(sig.Hint) {
+			return nil, errors.Errorf("invalid hint of signature %d", i)
+		}
+		copy(sig.Hint[:], hint)
+		if sig.Signature, err = base64.StdEncoding.DecodeString(doc.Signature); err != nil {
+			return nil, errors.Errorf("invalid signature %d", i)
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+func memoToJSON(memo Memo) (*memoJSON, error) {
+	switch memo := memo.(type) {
+	case nil:
+		return nil, nil
+	case MemoText:
+		return &memoJSON{Type: "text", Value: string(memo)}, nil
+	case MemoID:
+		return &memoJSON{Type: "id", Value: strconv.FormatUint(uint64(memo), 10)}, nil
+	case MemoHash:
+		return &memoJSON{Type: "hash", Value: base64.StdEncoding.EncodeToString(memo[:])}, nil
+	case MemoReturn:
+		return &memoJSON{Type: "return", Value: base64.StdEncoding.EncodeToString(memo[:])}, nil
+	default:
+		return nil, errors.Errorf("unsupported memo type %T", memo)
+	}
+}
+
+func (doc memoJSON) toMemo() (Memo, error) {
+	switch doc.Type {
+	case "text":
+		return MemoText(doc.Value), nil
+	case "id":
+		id, err := strconv.ParseUint(doc.Value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid id memo")
+		}
+		return MemoID(id), nil
+	case "hash", "return":
+		decoded, err := base64.StdEncoding.DecodeString(doc.Value)
+		if err != nil || len(decoded) != 32 {
+			return nil, errors.Errorf("invalid %s memo", doc.Type)
+		}
+		var hash [32]byte
+		copy(hash[:], decoded)
+		if doc.Type == "hash" {
+			return MemoHash(hash), nil
+		}
+		return MemoReturn(hash), nil
+	default:
+		return nil, errors.Errorf("unknown memo type %q", doc.Type)
+	}
+}
+
+func assetToString(asset Asset) string {
+	if asset == nil {
+		return ""
+	}
+	if asset.IsNative() {
+		return "native"
+	}
+	return asset.GetCode() + ":" + asset.GetIssuer()
+}
+
+func assetFromString(field, canonical string) (Asset, error) {
+	if canonical == "" {
+		return nil, errors.Errorf("%s is missing", field)
+	}
+	asset, err := ParseAssetString(canonical)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", field)
+	}
+	return asset, nil
+}
+
+func pathToStrings(path []Asset) []string {
+	var strs []string
+	for _, asset := range path {
+		strs = append(strs, assetToString(asset))
+	}
+	return strs
+}
+
+func pathFromStrings(strs []string) ([]Asset, error) {
+	var path []Asset
+	for _, s := range strs {
+		asset, err := assetFromString("path", s)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, asset)
+	}
+	return path, nil
+}
+
+// priceToJSON returns the exact price of an offer. Offers parsed from XDR
+// keep their fraction, which may not be representable by the decimal Price.
+func priceToJSON(p price, s string) (*priceJSON, error) {
+	if p.s == s && p.d != 0 {
+		return &priceJSON{N: int32(p.n), D: int32(p.d)}, nil
+	}
+	xdrPrice, err := pricepkg.Parse(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid price")
+	}
+	return &priceJSON{N: int32(xdrPrice.N), D: int32(xdrPrice.D)}, nil
+}
+
+func (doc operationJSON) price() price {
+	if doc.PriceR == nil {
+		return price{}
+	}
+	return price{n: int(doc.PriceR.N), d: int(doc.PriceR.D), s: doc.Price}
+}
+
+func flagsToStrings(flags []AccountFlag) ([]string, error) {
+	var names []string
+	for _, flag := range flags {
+		found := false
+		for _, f := range accountFlagNames {
+			if f.flag == flag {
+				names = append(names, f.name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown account flag %d", flag)
+		}
+	}
+	return names, nil
+}
+
+func flagsFromStrings(names []string) ([]AccountFlag, error) {
+	var flags []AccountFlag
+	for _, name := range names {
+		found := false
+		for _, f := range accountFlagNames {
+			if f.name == name {
+				flags = append(flags, f.flag)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown account flag %q", name)
+		}
+	}
+	return flags, nil
+}
+
+func operationToJSON(op Operation) (operationJSON, error) {
+	var doc operationJSON
+	if source := op.GetSourceAccount(); source != nil {
+		doc.SourceAccount = source.GetAccountID()
+	}
+
+	var err error
+	switch op := op.(type) {
+	case *CreateAccount:
+		doc.Type = "create_account"
+		doc.Destination = op.Destination
+		doc.StartingBalance = op.Amount
+	case *Payment:
+		doc.Type = "payment"
+		doc.Destination = op.Destination
+		doc.Asset = assetToString(op.Asset)
+		doc.Amount = op.Amount
+	case *PathPaymentStrictReceive:
+		doc.Type = "path_payment_strict_receive"
+		doc.SendAsset = assetToString(op.SendAsset)
+		doc.SendMax = op.SendMax
+		doc.Destination = op.Destination
+		doc.DestAsset = assetToString(op.DestAsset)
+		doc.DestAmount = op.DestAmount
+		doc.Path = pathToStrings(op.Path)
+	case *PathPaymentStrictSend:
+		doc.Type = "path_payment_strict_send"
+		doc.SendAsset = assetToString(op.SendAsset)
+		doc.SendAmount = op.SendAmount
+		doc.Destination = op.Destination
+		doc.DestAsset = assetToString(op.DestAsset)
+		doc.DestMin = op.DestMin
+		doc.Path = pathToStrings(op.Path)
+	case *ManageSellOffer:
+		doc.Type = "manage_sell_offer"
+		doc.Selling = assetToString(op.Selling)
+		doc.Buying = assetToString(op.Buying)
+		doc.Amount = op.Amount
+		doc.Price = op.Price
+		doc.PriceR, err = priceToJSON(op.price, op.Price)
+		doc.OfferID = op.OfferID
+	case *ManageBuyOffer:
+		doc.Type = "manage_buy_offer"
+		doc.Selling = assetToString(op.Selling)
+		doc.Buying = assetToString(op.Buying)
+		doc.Amount = op.Amount
+		doc.Price = op.Price
+		doc.PriceR, err = priceToJSON(op.price, op.Price)
+		doc.OfferID = op.OfferID
+	case *CreatePassiveSellOffer:
+		doc.Type = "create_passive_sell_offer"
+		doc.Selling = assetToString(op.Selling)
+		doc.Buying = assetToString(op.Buying)
+		doc.Amount = op.Amount
+		doc.Price = op.Price
+		doc.PriceR, err = priceToJSON(op.price, op.Price)
+	case *SetOptions:
+		doc.Type = "set_options"
+		doc.InflationDestination = op.InflationDestination
+		if doc.SetFlags, err = flagsToStrings(op.SetFlags); err != nil {
+			break
+		}
+		doc.ClearFlags, err = flagsToStrings(op.ClearFlags)
+		doc.MasterWeight = op.MasterWeight
+		doc.LowThreshold = op.LowThreshold
+		doc.MedThreshold = op.MediumThreshold
+		doc.HighThreshold = op.HighThreshold
+		doc.HomeDomain = op.HomeDomain
+		if op.Signer != nil {
+			doc.Signer = &signerJSON{Key: op.Signer.Address, Weight: op.Signer.Weight}
+		}
+	case *ChangeTrust:
+		doc.Type = "change_trust"
+		doc.Asset = assetToString(op.Line)
+		doc.Limit = op.Limit
+	case *AllowTrust:
+		doc.Type = "allow_trust"
+		doc.Trustor = op.Trustor
+		if op.Type != nil {
+			doc.AssetCode = op.Type.GetCode()
+		}
+		doc.Authorize = op.Authorize
+		doc.AuthorizeToMaintainLiabilities = op.AuthorizeToMaintainLiabilities
+	case *AccountMerge:
+		doc.Type = "account_merge"
+		doc.Destination = op.Destination
+	case *Inflation:
+		doc.Type = "inflation"
+	case *ManageData:
+		doc.Type = "manage_data"
+		doc.Name = op.Name
+		if op.Value != nil {
+			value := base64.StdEncoding.EncodeToString(op.Value)
+			doc.Value = &value
+		}
+	case *BumpSequence:
+		doc.Type = "bump_sequence"
+		doc.BumpTo = op.BumpTo
+	case *CreateClaimableBalance:
+		doc.Type = "create_claimable_balance"
+		doc.Asset = assetToString(op.Asset)
+		doc.Amount = op.Amount
+		for _, claimant := range op.Destinations {
+			doc.Claimants = append(doc.Claimants, claimantJSON{
+				Destination: claimant.Destination,
+				Predicate:   claimant.Predicate,
+			})
+		}
+	case *ClaimClaimableBalance:
+		doc.Type = "claim_claimable_balance"
+		doc.BalanceID = op.BalanceID
+	case *BeginSponsoringFutureReserves:
+		doc.Type = "begin_sponsoring_future_reserves"
+		doc.SponsoredID = op.SponsoredID
+	case *EndSponsoringFutureReserves:
+		doc.Type = "end_sponsoring_future_reserves"
+	case *RevokeSponsorship:
+		doc.Type = "revoke_sponsorship"
+		doc.Sponsorship, err = revokeSponsorshipToJSON(op)
+	default:
+		err = errors.Errorf("unsupported operation type %T", op)
+	}
+	return doc, err
+}
+
+func revokeSponsorshipToJSON(op *RevokeSponsorship) (*revokeSponsorshipJSON, error) {
+	doc := &revokeSponsorshipJSON{Type: revokeSponsorshipTypeNames[op.SponsorshipType]}
+	switch op.SponsorshipType {
+	case RevokeSponsorshipTypeAccount:
+		if op.Account != nil {
+			doc.Account = *op.Account
+		}
+	case RevokeSponsorshipTypeTrustLine:
+		if op.TrustLine != nil {
+			doc.Account = op.TrustLine.Account
+			doc.Asset = assetToString(op.TrustLine.Asset)
+		}
+	case RevokeSponsorshipTypeOffer:
+		if op.Offer != nil {
+			doc.Account = op.Offer.SellerAccountAddress
+			doc.OfferID = op.Offer.OfferID
+		}
+	case RevokeSponsorshipTypeData:
+		if op.Data != nil {
+			doc.Account = op.Data.Account
+			doc.DataName = op.Data.DataName
+		}
+	case RevokeSponsorshipTypeClaimableBalance:
+		if op.ClaimableBalance != nil {
+			doc.BalanceID = *op.ClaimableBalance
+		}
+	case RevokeSponsorshipTypeSigner:
+		if op.Signer != nil {
+			doc.Account = op.Signer.AccountID
+			doc.SignerKey = op.Signer.SignerAddress
+		}
+	default:
+		return nil, errors.Errorf("unknown sponsorship type %d", op.SponsorshipType)
+	}
+	return doc, nil
+}
+
+func (doc operationJSON) toOperation() (Operation, error) {
+	var source Account
+	if doc.SourceAccount != "" {
+		source = &SimpleAccount{AccountID: doc.SourceAccount}
+	}
+
+	var err error
+	asset := func(field, canonical string) Asset {
+		if err != nil {
+			return nil
+		}
+		var parsed Asset
+		parsed, err = assetFromString(field, canonical)
+		return parsed
+	}
+
+	var op Operation
+	switch doc.Type {
+	case "create_account":
+		op = &CreateAccount{
+			Destination:   doc.Destination,
+			Amount:        doc.StartingBalance,
+			SourceAccount: source,
+		}
+	case "payment":
+		op = &Payment{
+			Destination:   doc.Destination,
+			Amount:        doc.Amount,
+			Asset:         asset("asset", doc.Asset),
+			SourceAccount: source,
+		}
+	case "path_payment_strict_receive":
+		pp := &PathPaymentStrictReceive{
+			SendAsset:     asset("send_asset", doc.SendAsset),
+			SendMax:       doc.SendMax,
+			Destination:   doc.Destination,
+			DestAsset:     asset("dest_asset", doc.DestAsset),
+			DestAmount:    doc.DestAmount,
+			SourceAccount: source,
+		}
+		if err == nil {
+			pp.Path, err = pathFromStrings(doc.Path)
+		}
+		op = pp
+	case "path_payment_strict_send":
+		pp := &PathPaymentStrictSend{
+			SendAsset:     asset("send_asset", doc.SendAsset),
+			SendAmount:    doc.SendAmount,
+			Destination:   doc.Destination,
+			DestAsset:     asset("dest_asset", doc.DestAsset),
+			DestMin:       doc.DestMin,
+			SourceAccount: source,
+		}
+		if err == nil {
+			pp.Path, err = pathFromStrings(doc.Path)
+		}
+		op = pp
+	case "manage_sell_offer":
+		op = &ManageSellOffer{
+			Selling:       asset("selling", doc.Selling),
+			Buying:        asset("buying", doc.Buying),
+			Amount:        doc.Amount,
+			Price:         doc.Price,
+			price:         doc.price(),
+			OfferID:       doc.OfferID,
+			SourceAccount: source,
+		}
+	case "manage_buy_offer":
+		op = &ManageBuyOffer{
+			Selling:       asset("selling", doc.Selling),
+			Buying:        asset("buying", doc.Buying),
+			Amount:        doc.Amount,
+			Price:         doc.Price,
+			price:         doc.price(),
+			OfferID:       doc.OfferID,
+			SourceAccount: source,
+		}
+	case "create_passive_sell_offer":
+		op = &CreatePassiveSellOffer{
+			Selling:       asset("selling", doc.Selling),
+			Buying:        asset("buying", doc.Buying),
+			Amount:        doc.Amount,
+			Price:         doc.Price,
+			price:         doc.price(),
+			SourceAccount: source,
+		}
+	case "set_options":
+		so := &SetOptions{
+			InflationDestination: doc.InflationDestination,
+			MasterWeight:         doc.MasterWeight,
+			LowThreshold:         doc.LowThreshold,
+			MediumThreshold:      doc.MedThreshold,
+			HighThreshold:        doc.HighThreshold,
+			HomeDomain:           doc.HomeDomain,
+			SourceAccount:        source,
+		}
+		if so.SetFlags, err = flagsFromStrings(doc.SetFlags); err == nil {
+			so.ClearFlags, err = flagsFromStrings(doc.ClearFlags)
+		}
+		if doc.Signer != nil {
+			so.Signer = &Signer{Address: doc.Signer.Key, Weight: doc.Signer.Weight}
+		}
+		op = so
+	case "change_trust":
+		op = &ChangeTrust{
+			Line:          asset("asset", doc.Asset),
+			Limit:         doc.Limit,
+			SourceAccount: source,
+		}
+	case "allow_trust":
+		op = &AllowTrust{
+			Trustor:                        doc.Trustor,
+			Type:                           CreditAsset{Code: doc.AssetCode},
+			Authorize:                      doc.Authorize,
+			AuthorizeToMaintainLiabilities: doc.AuthorizeToMaintainLiabilities,
+			SourceAccount:                  source,
+		}
+	case "account_merge":
+		op = &AccountMerge{Destination: doc.Destination, SourceAccount: source}
+	case "inflation":
+		op = &Inflation{SourceAccount: source}
+	case "manage_data":
+		md := &ManageData{Name: doc.Name, SourceAccount: source}
+		if doc.Value != nil {
+			if md.Value, err = base64.StdEncoding.DecodeString(*doc.Value); err != nil {
+				err = errors.Wrap(err, "invalid value")
+			}
+		}
+		op = md
+	case "bump_sequence":
+		op = &BumpSequence{BumpTo: doc.BumpTo, SourceAccount: source}
+	case "create_claimable_balance":
+		cb := &CreateClaimableBalance{
+			Asset:         asset("asset", doc.Asset),
+			Amount:        doc.Amount,
+			SourceAccount: source,
+		}
+		for _, claimant := range doc.Claimants {
+			cb.Destinations = append(cb.Destinations, Claimant{
+				Destination: claimant.Destination,
+				Predicate:   claimant.Predicate,
+			})
+		}
+		op = cb
+	case "claim_claimable_balance":
+		op = &ClaimClaimableBalance{BalanceID: doc.BalanceID, SourceAccount: source}
+	case "begin_sponsoring_future_reserves":
+		op = &BeginSponsoringFutureReserves{SponsoredID: doc.SponsoredID, SourceAccount: source}
+	case "end_sponsoring_future_reserves":
+		op = &EndSponsoringFutureReserves{SourceAccount: source}
+	case "revoke_sponsorship":
+		if doc.Sponsorship == nil {
+			return nil, errors.New("sponsorship is missing")
+		}
+		var rs *RevokeSponsorship
+		rs, err = doc.Sponsorship.toOperation()
+		if rs != nil {
+			rs.SourceAccount = source
+		}
+		op = rs
+	default:
+		return nil, errors.Errorf("unknown operation type %q", doc.Type)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid %s operation", doc.Type))
+	}
+	return op, nil
+}
+
+func (doc revokeSponsorshipJSON) toOperation() (*RevokeSponsorship, error) {
+	op := &RevokeSponsorship{}
+	for sponsorshipType, name := range revokeSponsorshipTypeNames {
+		if name == doc.Type {
+			op.SponsorshipType = sponsorshipType
+		}
+	}
+
+	switch op.SponsorshipType {
+	case RevokeSponsorshipTypeAccount:
+		account := doc.Account
+		op.Account = &account
+	case RevokeSponsorshipTypeTrustLine:
+		asset, err := assetFromString("sponsorship asset", doc.Asset)
+		if err != nil {
+			return nil, err
+		}
+		op.TrustLine = &TrustLineID{Account: doc.Account, Asset: asset}
+	case RevokeSponsorshipTypeOffer:
+		op.Offer = &OfferID{SellerAccountAddress: doc.Account, OfferID: doc.OfferID}
+	case RevokeSponsorshipTypeData:
+		op.Data = &DataID{Account: doc.Account, DataName: doc.DataName}
+	case RevokeSponsorshipTypeClaimableBalance:
+		balanceID := doc.BalanceID
+		op.ClaimableBalance = &balanceID
+	case RevokeSponsorshipTypeSigner:
+		op.Signer = &SignerID{AccountID: doc.Account, SignerAddress: doc.SignerKey}
+	default:
+		return nil, errors.Errorf("unknown sponsorship type %q", doc.Type)
+	}
+	return op, nil
+}
@@ -0,0 +1,298 @@
+package txnbuild
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func allOperations() []Operation {
+	kp1 := newKeypair1().Address()
+	kp2 := newKeypair2().Address()
+	usd := CreditAsset{Code: "USD", Issuer: kp1}
+	eur := CreditAsset{Code: "EURTOKEN", Issuer: kp2}
+	balanceID := "00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072"
+	account := kp2
+	homeDomain := "example.com"
+	weight := Threshold(2)
+	relBefore := xdr.Int64(3600)
+
+	return []Operation{
+		&CreateAccount{Destination: kp1, Amount: "10"},
+		&Payment{Destination: kp1, Amount: "1.5", Asset: usd, SourceAccount: &SimpleAccount{AccountID: kp2}},
+		&PathPaymentStrictReceive{SendAsset: NativeAsset{}, SendMax: "10", Destination: kp1, DestAsset: usd, DestAmount: "1", Path: []Asset{eur}},
+		&PathPaymentStrictSend{SendAsset: usd, SendAmount: "1", Destination: kp1, DestAsset: NativeAsset{}, DestMin: "9"},
+		&ManageSellOffer{Selling: usd, Buying: NativeAsset{}, Amount: "100", Price: "0.5", OfferID: 12},
+		&ManageBuyOffer{Selling: NativeAsset{}, Buying: eur, Amount: "100", Price: "2"},
+		&CreatePassiveSellOffer{Selling: eur, Buying: usd, Amount: "3", Price: "1.25"},
+		&SetOptions{
+			InflationDestination: &account,
+			SetFlags:             []AccountFlag{AuthRequired, AuthRevocable},
+			ClearFlags:           []AccountFlag{AuthImmutable},
+			MasterWeight:         &weight,
+			HighThreshold:        &weight,
+			HomeDomain:           &homeDomain,
+			Signer:               &Signer{Address: kp1, Weight: 1},
+		},
+		&ChangeTrust{Line: usd, Limit: "1000"},
+		&AllowTrust{Trustor: kp2, Type: CreditAsset{Code: "USD"}, Authorize: true},
+		&AccountMerge{Destination: kp1},
+		&Inflation{},
+		&ManageData{Name: "key", Value: []byte("value")},
+		&ManageData{Name: "deleted"},
+		&BumpSequence{BumpTo: 1 << 40},
+		&CreateClaimableBalance{Asset: usd, Amount: "5", Destinations: []Claimant{
+			NewClaimant(kp1, nil),
+			NewClaimant(kp2, &xdr.ClaimPredicate{
+				Type:      xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime,
+				RelBefore: &relBefore,
+			}),
+		}},
+		&ClaimClaimableBalance{BalanceID: balanceID},
+		&BeginSponsoringFutureReserves{SponsoredID: kp1},
+		&EndSponsoringFutureReserves{SourceAccount: &SimpleAccount{AccountID: kp1}},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeAccount, Account: &account},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeTrustLine, TrustLine: &TrustLineID{Account: kp1, Asset: usd}},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeOffer, Offer: &OfferID{SellerAccountAddress: kp1, OfferID: 7}},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeData, Data: &DataID{Account: kp1, DataName: "key"}},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeClaimableBalance, ClaimableBalance: &balanceID},
+		&RevokeSponsorship{SponsorshipType: RevokeSponsorshipTypeSigner, Signer: &SignerID{AccountID: kp1, SignerAddress: kp2}},
+	}
+}
+
+func TestTransactionJSONRoundTrip(t *testing.T) {
+	kp0 := newKeypair0()
+	tx, err := NewTransaction(TransactionParams{
+		SourceAccount:        &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           allOperations(),
+		BaseFee:              MinBaseFee,
+		Memo:                 MemoHash{1, 2, 3},
+		Timebounds:           NewTimebounds(10, 1000),
+	})
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	require.NoError(t, err)
+	expected, err := tx.Base64()
+	require.NoError(t, err)
+
+	parsed, err := TransactionFromXDR(expected)
+	require.NoError(t, err)
+	parsedTx, _ := parsed.Transaction()
+
+	jsonData, err := json.Marshal(parsedTx)
+	require.NoError(t, err)
+	fromJSON, err := TransactionFromJSON(jsonData)
+	require.NoError(t, err)
+	fromJSONTx, ok := fromJSON.Transaction()
+	require.True(t, ok)
+	actual, err := fromJSONTx.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	yamlData, err := yaml.Marshal(parsedTx)
+	require.NoError(t, err)
+	fromYAML, err := TransactionFromYAML(yamlData)
+	require.NoError(t, err)
+	fromYAMLTx, ok := fromYAML.Transaction()
+	require.True(t, ok)
+	actual, err = fromYAMLTx.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestTransactionJSON(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	tx, err := NewTransaction(TransactionParams{
+		SourceAccount:        &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations: []Operation{
+			&Payment{Destination: kp1.Address(), Amount: "10", Asset: NativeAsset{}},
+		},
+		BaseFee:    MinBaseFee,
+		Memo:       MemoID(42),
+		Timebounds: NewTimebounds(0, 1600000000),
+	})
+	require.NoError(t, err)
+
+	jsonData, err := json.Marshal(tx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "transaction",
+		"source_account": "GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3",
+		"sequence": "2",
+		"fee": 100,
+		"time_bounds": {"min_time": 0, "max_time": 1600000000},
+		"memo": {"type": "id", "value": "42"},
+		"operations": [{
+			"type": "payment",
+			"destination": "GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP",
+			"asset": "native",
+			"amount": "10"
+		}],
+		"signatures": []
+	}`, string(jsonData))
+
+	yamlData, err := yaml.Marshal(tx)
+	require.NoError(t, err)
+	assert.Equal(t, `type: transaction
+source_account: GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3
+sequence: "2"
+fee: 100
+time_bounds:
+  min_time: 0
+  max_time: 1600000000
+memo:
+  type: id
+  value: "42"
+operations:
+- type: payment
+  destination: GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP
+  asset: native
+  amount: "10"
+signatures: []
+`, string(yamlData))
+}
+
+func TestTransactionV0JSONRoundTrip(t *testing.T) {
+	kp0 := newKeypair0()
+	tx, err := NewTransaction(TransactionParams{
+		SourceAccount:        &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           []Operation{&BumpSequence{BumpTo: 10}},
+		BaseFee:              MinBaseFee,
+		Memo:                 MemoText("hello"),
+		Timebounds:           NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	convertToV0(tx)
+	expected, err := tx.Base64()
+	require.NoError(t, err)
+
+	jsonData, err := json.Marshal(tx)
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"type":"transaction_v0"`)
+
+	parsed, err := TransactionFromJSON(jsonData)
+	require.NoError(t, err)
+	parsedTx, _ := parsed.Transaction()
+	actual, err := parsedTx.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestOfferPriceJSONRoundTrip(t *testing.T) {
+	kp0 := newKeypair0()
+	tx, err := NewTransaction(TransactionParams{
+		SourceAccount:        &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations: []Operation{
+			&ManageSellOffer{Selling: NativeAsset{}, Buying: CreditAsset{"USD", kp0.Address()}, Amount: "1", Price: "1"},
+		},
+		BaseFee:    MinBaseFee,
+		Timebounds: NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	// A price which cannot be represented exactly as a decimal.
+	tx.envelope.V1.Tx.Operations[0].Body.ManageSellOfferOp.Price = xdr.Price{N: 1, D: 3}
+	expected, err := tx.Base64()
+	require.NoError(t, err)
+
+	parsed, err := TransactionFromXDR(expected)
+	require.NoError(t, err)
+	parsedTx, _ := parsed.Transaction()
+	jsonData, err := json.Marshal(parsedTx)
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"price_r":{"n":1,"d":3}`)
+
+	fromJSON, err := TransactionFromJSON(jsonData)
+	require.NoError(t, err)
+	fromJSONTx, _ := fromJSON.Transaction()
+	actual, err := fromJSONTx.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestFeeBumpTransactionJSONRoundTrip(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	inner, err := NewTransaction(TransactionParams{
+		SourceAccount:        &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           []Operation{&Payment{Destination: kp1.Address(), Amount: "1", Asset: NativeAsset{}}},
+		BaseFee:              MinBaseFee,
+		Timebounds:           NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	inner, err = inner.Sign(network.TestNetworkPassphrase, kp0)
+	require.NoError(t, err)
+	expected, err := newSignedFeeBumpTransaction(FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: kp1.Address(),
+		BaseFee:    2 * MinBaseFee,
+	}, network.TestNetworkPassphrase, kp1)
+	require.NoError(t, err)
+
+	parsed, err := TransactionFromXDR(expected)
+	require.NoError(t, err)
+	feeBump, ok := parsed.FeeBump()
+	require.True(t, ok)
+
+	yamlData, err := yaml.Marshal(feeBump)
+	require.NoError(t, err)
+	assert.Contains(t, string(yamlData), "type: fee_bump_transaction\nfee_account: "+kp1.Address())
+
+	fromYAML, err := TransactionFromYAML(yamlData)
+	require.NoError(t, err)
+	fromYAMLTx, ok := fromYAML.FeeBump()
+	require.True(t, ok)
+	actual, err := fromYAMLTx.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestOperationJSON(t *testing.T) {
+	for _, op := range allOperations() {
+		expected, err := op.BuildXDR()
+		require.NoError(t, err)
+
+		jsonData, err := OperationToJSON(op)
+		require.NoError(t, err)
+		parsed, err := OperationFromJSON(jsonData)
+		require.NoError(t, err, string(jsonData))
+		actual, err := parsed.BuildXDR()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, string(jsonData))
+
+		yamlData, err := OperationToYAML(op)
+		require.NoError(t, err)
+		parsed, err = OperationFromYAML(yamlData)
+		require.NoError(t, err, string(yamlData))
+		actual, err = parsed.BuildXDR()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, string(yamlData))
+	}
+}
+
+func TestTransactionJSONErrors(t *testing.T) {
+	for data, expected := range map[string]string{
+		`{"type": "unknown"}`: `unknown transaction type "unknown"`,
+		`{"type": "transaction", "source_account": "GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3", "operations": [{"type": "foo"}]}`:                     `invalid operation 0: unknown operation type "foo"`,
+		`{"type": "transaction", "source_account": "GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3", "operations": [{"type": "payment", "asset": "EUR"}]}`: `invalid operation 0: invalid payment operation: invalid asset`,
+		`{"type": "transaction", "source_account": "GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3", "memo": {"type": "hash", "value": "AA=="}}`:           `invalid hash memo`,
+		`{"type": "fee_bump_transaction"}`: `fee bump transaction must wrap a transaction`,
+		// Muxed accounts cannot be the source of v0 transactions.
+		`{"type": "transaction_v0", "source_account": "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"}`: `invalid source_account`,
+	} {
+		_, err := TransactionFromJSON([]byte(data))
+		if assert.Error(t, err, data) {
+			assert.Contains(t, err.Error(), expected, data)
+		}
+	}
+}
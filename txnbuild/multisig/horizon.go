@@ -0,0 +1,35 @@
+package multisig
+
+import (
+	"github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// LoadAccounts returns the signers and thresholds of the given accounts, as
+// returned by Horizon.
+func LoadAccounts(client horizonclient.ClientInterface, accountIDs ...string) (map[string]AccountSigners, error) {
+	accounts := make(map[string]AccountSigners, len(accountIDs))
+	for _, accountID := range accountIDs {
+		record, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load account %s", accountID)
+		}
+		accounts[accountID] = AccountSignersFromHorizon(record)
+	}
+	return accounts, nil
+}
+
+// AccountSignersFromHorizon returns the signers and thresholds of an account
+// returned by Horizon.
+func AccountSignersFromHorizon(record hProtocol.Account) AccountSigners {
+	return AccountSigners{
+		Signers: txnbuild.SignerSummary(record.SignerSummary()),
+		Thresholds: Thresholds{
+			Low:    txnbuild.Threshold(record.Thresholds.LowThreshold),
+			Medium: txnbuild.Threshold(record.Thresholds.MedThreshold),
+			High:   txnbuild.Threshold(record.Thresholds.HighThreshold),
+		},
+	}
+}
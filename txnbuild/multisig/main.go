@@ -0,0 +1,540 @@
+/*
+Package multisig coordinates the collection of signatures for transactions
+whose source accounts are controlled by several signers.
+
+A Coordinator is created for a transaction together with the signers and
+thresholds of the accounts involved. Signatures can then be merged in from
+partially signed envelopes produced by each party, and the Coordinator reports
+which signatures are still required before the transaction can be submitted:
+
+	accounts, err := multisig.LoadAccounts(client, multisig.SourceAccounts(tx)...)
+	c, err := multisig.NewCoordinator(network.PublicNetworkPassphrase, tx, accounts)
+	err = c.AddEnvelope(envelopeFromAlice)
+	err = c.AddEnvelope(envelopeFromBob)
+	if c.FullyAuthorized() {
+		signed, err := c.Transaction()
+		...
+	}
+
+Signatures which do not belong to any signer of the accounts involved are
+rejected, since Stellar Core fails transactions carrying unused signatures with
+tx_bad_auth_extra.
+*/
+package multisig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"sort"
+
+	"github.com/stellar/go/internal/muxedaccount"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxSignatures is the maximum number of signatures a transaction envelope
+// can carry.
+const MaxSignatures = 20
+
+// ErrHashMismatch is returned when an envelope for a different transaction is
+// merged into a Coordinator.
+var ErrHashMismatch = errors.New("envelope is for a different transaction")
+
+// ErrTooManySignatures is returned when merging signatures would exceed
+// MaxSignatures.
+var ErrTooManySignatures = errors.New("too many signatures")
+
+// ThresholdCategory is the threshold category an operation or transaction is
+// checked against.
+type ThresholdCategory int
+
+// Threshold categories, in increasing order of privilege.
+const (
+	ThresholdLow ThresholdCategory = iota
+	ThresholdMedium
+	ThresholdHigh
+)
+
+// String returns the name of the category.
+func (c ThresholdCategory) String() string {
+	switch c {
+	case ThresholdLow:
+		return "low"
+	case ThresholdMedium:
+		return "medium"
+	case ThresholdHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+// Thresholds are the thresholds of an account.
+type Thresholds struct {
+	Low    txnbuild.Threshold
+	Medium txnbuild.Threshold
+	High   txnbuild.Threshold
+}
+
+// AccountSigners describes who can sign for an account. Signers must include
+// the master key of the account with its weight, like the signers returned by
+// Horizon. Signer keys can be ed25519 public keys (G...), pre-authorized
+// transaction hashes (T...) or sha256 hashes (X...).
+type AccountSigners struct {
+	Signers    txnbuild.SignerSummary
+	Thresholds Thresholds
+}
+
+func (a AccountSigners) threshold(category ThresholdCategory) txnbuild.Threshold {
+	switch category {
+	case ThresholdLow:
+		return a.Thresholds.Low
+	case ThresholdMedium:
+		return a.Thresholds.Medium
+	default:
+		return a.Thresholds.High
+	}
+}
+
+// Requirement is the weight of signatures an account must provide for a
+// transaction to be authorized.
+type Requirement struct {
+	// Account is the account which must authorize the transaction.
+	Account string
+	// Category is the highest threshold category required from the account.
+	Category ThresholdCategory
+	// Threshold is the weight required by Category.
+	Threshold txnbuild.Threshold
+	// Envelope is true if the account is the source of the transaction or
+	// the fee account of a fee bump transaction.
+	Envelope bool
+	// Operations are the indexes of the operations sourced from the account.
+	Operations []int
+	// Weight is the weight of the signatures collected so far.
+	Weight int32
+	// Signed are the signers of the account who have signed.
+	Signed []string
+	// Missing are the signers of the account who have not signed yet.
+	Missing []string
+}
+
+// Satisfied returns true if the collected signatures meet the threshold. Like
+// in Stellar Core at least one signature is required even when the threshold
+// is 0.
+func (r Requirement) Satisfied() bool {
+	return r.Weight > 0 && r.Weight >= int32(r.Threshold)
+}
+
+// OperationCategory returns the threshold category an operation is checked
+// against.
+func OperationCategory(op txnbuild.Operation) ThresholdCategory {
+	switch op := op.(type) {
+	case *txnbuild.BumpSequence, *txnbuild.ClaimClaimableBalance, *txnbuild.AllowTrust:
+		return ThresholdLow
+	case *txnbuild.AccountMerge:
+		return ThresholdHigh
+	case *txnbuild.SetOptions:
+		if op.MasterWeight != nil || op.LowThreshold != nil || op.MediumThreshold != nil ||
+			op.HighThreshold != nil || op.Signer != nil {
+			return ThresholdHigh
+		}
+	}
+	return ThresholdMedium
+}
+
+// SourceAccounts returns the accounts whose signers must be known to
+// coordinate the signatures of tx: its source account followed by the
+// distinct operation source accounts. Muxed accounts are returned as the
+// account they belong to.
+func SourceAccounts(tx *txnbuild.Transaction) []string {
+	accounts := []string{accountID(tx.SourceAccount().AccountID)}
+	seen := map[string]bool{accounts[0]: true}
+	for _, op := range tx.Operations() {
+		if op.GetSourceAccount() == nil {
+			continue
+		}
+		source := accountID(op.GetSourceAccount().GetAccountID())
+		if seen[source] {
+			continue
+		}
+		seen[source] = true
+		accounts = append(accounts, source)
+	}
+	return accounts
+}
+
+// accountID returns the account ID of the address, which may be a muxed
+// account whose signers are those of the account it belongs to. Invalid
+// addresses are returned unchanged.
+func accountID(address string) string {
+	id, err := muxedaccount.AccountID(address)
+	if err != nil {
+		return address
+	}
+	return id
+}
+
+// need is the requirement of an account before signatures are counted.
+type need struct {
+	account    string
+	category   ThresholdCategory
+	envelope   bool
+	operations []int
+}
+
+// Coordinator collects the signatures of a transaction or fee bump
+// transaction.
+type Coordinator struct {
+	networkPassphrase string
+	hash              [32]byte
+	tx                *txnbuild.Transaction
+	feeBump           *txnbuild.FeeBumpTransaction
+	accounts          map[string]AccountSigners
+	needs             []need
+	signatures        []xdr.DecoratedSignature
+}
+
+// NewCoordinator returns a Coordinator for tx. accounts must contain the
+// signers of the transaction source account and of every operation source
+// account. Signatures already present on tx are merged in.
+func NewCoordinator(networkPassphrase string, tx *txnbuild.Transaction, accounts map[string]AccountSigners) (*Coordinator, error) {
+	hash, err := tx.Hash(networkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash transaction")
+	}
+
+	byAccount := map[string]*need{}
+	var order []string
+	add := func(account string, category ThresholdCategory) *need {
+		n, ok := byAccount[account]
+		if !ok {
+			n = &need{account: account, category: category}
+			byAccount[account] = n
+			order = append(order, account)
+		}
+		if category > n.category {
+			n.category = category
+		}
+		return n
+	}
+
+	source := accountID(tx.SourceAccount().AccountID)
+	add(source, ThresholdLow).envelope = true
+	for i, op := range tx.Operations() {
+		account := source
+		if op.GetSourceAccount() != nil {
+			account = accountID(op.GetSourceAccount().GetAccountID())
+		}
+		n := add(account, OperationCategory(op))
+		n.operations = append(n.operations, i)
+	}
+
+	c := &Coordinator{
+		networkPassphrase: networkPassphrase,
+		hash:              hash,
+		tx:                tx,
+		accounts:          accounts,
+	}
+	for _, account := range order {
+		c.needs = append(c.needs, *byAccount[account])
+	}
+	if err := c.init(tx.Signatures()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewFeeBumpCoordinator returns a Coordinator for the signatures of the fee
+// account of tx. The inner transaction cannot be signed once wrapped, so its
+// signatures should be coordinated before building the fee bump transaction.
+func NewFeeBumpCoordinator(networkPassphrase string, tx *txnbuild.FeeBumpTransaction, accounts map[string]AccountSigners) (*Coordinator, error) {
+	hash, err := tx.Hash(networkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash transaction")
+	}
+
+	c := &Coordinator{
+		networkPassphrase: networkPassphrase,
+		hash:              hash,
+		feeBump:           tx,
+		accounts:          accounts,
+		needs:             []need{{account: tx.FeeAccount(), category: ThresholdLow, envelope: true}},
+	}
+	if err := c.init(tx.Signatures()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Coordinator) init(signatures []xdr.DecoratedSignature) error {
+	for _, n := range c.needs {
+		if _, ok := c.accounts[n.account]; !ok {
+			return errors.Errorf("signers of account %s are unknown", n.account)
+		}
+	}
+	return c.AddSignatures(signatures...)
+}
+
+// Hash returns the hash of the transaction being signed.
+func (c *Coordinator) Hash() [32]byte {
+	return c.hash
+}
+
+// Signatures returns the signatures collected so far. The contents of the
+// returned slice should not be modified.
+func (c *Coordinator) Signatures() []xdr.DecoratedSignature {
+	return c.signatures
+}
+
+// AddEnvelope merges the signatures of a partially signed base64 encoded
+// transaction envelope. The envelope must be for the same transaction.
+func (c *Coordinator) AddEnvelope(txeB64 string) error {
+	parsed, err := txnbuild.TransactionFromXDR(txeB64)
+	if err != nil {
+		return errors.Wrap(err, "could not parse envelope")
+	}
+
+	var hash [32]byte
+	var signatures []xdr.DecoratedSignature
+	if tx, ok := parsed.Transaction(); ok && c.tx != nil {
+		hash, err = tx.Hash(c.networkPassphrase)
+		signatures = tx.Signatures()
+	} else if feeBump, ok := parsed.FeeBump(); ok && c.feeBump != nil {
+		hash, err = feeBump.Hash(c.networkPassphrase)
+		signatures = feeBump.Signatures()
+	} else {
+		return ErrHashMismatch
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not hash envelope")
+	}
+	if hash != c.hash {
+		return ErrHashMismatch
+	}
+
+	return c.AddSignatures(signatures...)
+}
+
+// AddSignatureBase64 adds a base64 encoded signature by publicKey.
+func (c *Coordinator) AddSignatureBase64(publicKey, signature string) error {
+	kp, err := keypair.ParseAddress(publicKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature encoding")
+	}
+	return c.AddSignatures(xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(kp.Hint()),
+		Signature: decoded,
+	})
+}
+
+// Sign adds signatures from the given keypairs.
+func (c *Coordinator) Sign(kps ...*keypair.Full) error {
+	signatures := make([]xdr.DecoratedSignature, 0, len(kps))
+	for _, kp := range kps {
+		sig, err := kp.SignDecorated(c.hash[:])
+		if err != nil {
+			return errors.Wrap(err, "failed to sign transaction")
+		}
+		signatures = append(signatures, sig)
+	}
+	return c.AddSignatures(signatures...)
+}
+
+// AddSignatures merges signatures, ignoring those already collected. Either
+// all signatures are added or none: an error is returned if any of them does
+// not belong to a signer of the accounts required to authorize the
+// transaction.
+func (c *Coordinator) AddSignatures(signatures ...xdr.DecoratedSignature) error {
+	merged := append([]xdr.DecoratedSignature{}, c.signatures...)
+	for i, sig := range signatures {
+		if containsSignature(merged, sig) {
+			continue
+		}
+		if c.signer(sig) == "" {
+			return errors.Errorf("signature %d does not belong to any signer of the transaction", i)
+		}
+		merged = append(merged, sig)
+	}
+	if len(merged) > MaxSignatures {
+		return ErrTooManySignatures
+	}
+	c.signatures = merged
+	return nil
+}
+
+func containsSignature(signatures []xdr.DecoratedSignature, sig xdr.DecoratedSignature) bool {
+	for _, s := range signatures {
+		if s.Hint == sig.Hint && bytes.Equal(s.Signature, sig.Signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// signer returns the key of a signer of the required accounts which produced
+// sig, or an empty string.
+func (c *Coordinator) signer(sig xdr.DecoratedSignature) string {
+	for _, n := range c.needs {
+		for key := range c.accounts[n.account].Signers {
+			if SignatureMatches(c.hash, key, sig) {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// SignatureMatches returns true if sig was produced for the transaction hash
+// by the signer key, which is an ed25519 public key (G...) or a sha256 hash
+// (X...). Pre-authorized transaction signers (T...) authorize the transaction
+// of their hash without a signature, see PreAuthorizes.
+func SignatureMatches(hash [32]byte, key string, sig xdr.DecoratedSignature) bool {
+	if kp, err := keypair.ParseAddress(key); err == nil {
+		return sig.Hint == xdr.SignatureHint(kp.Hint()) && kp.Verify(hash[:], sig.Signature) == nil
+	}
+	hashX, err := strkey.Decode(strkey.VersionByteHashX, key)
+	if err != nil || !bytes.Equal(sig.Hint[:], hashX[len(hashX)-4:]) {
+		return false
+	}
+	preimageHash := sha256.Sum256(sig.Signature)
+	return bytes.Equal(preimageHash[:], hashX)
+}
+
+// PreAuthorizes returns true if the signer key is a pre-authorized
+// transaction signer (T...) for the transaction hash.
+func PreAuthorizes(hash [32]byte, key string) bool {
+	preAuth, err := strkey.Decode(strkey.VersionByteHashTx, key)
+	return err == nil && bytes.Equal(preAuth, hash[:])
+}
+
+// signed returns true if the signer key has authorized the transaction.
+func (c *Coordinator) signed(key string) bool {
+	if PreAuthorizes(c.hash, key) {
+		return true
+	}
+	for _, sig := range c.signatures {
+		if SignatureMatches(c.hash, key, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// Requirements returns the requirement of every account which must authorize
+// the transaction, with the signatures collected so far.
+func (c *Coordinator) Requirements() []Requirement {
+	requirements := make([]Requirement, 0, len(c.needs))
+	for _, n := range c.needs {
+		account := c.accounts[n.account]
+		r := Requirement{
+			Account:    n.account,
+			Category:   n.category,
+			Threshold:  account.threshold(n.category),
+			Envelope:   n.envelope,
+			Operations: n.operations,
+			Signed:     []string{},
+			Missing:    []string{},
+		}
+
+		keys := make([]string, 0, len(account.Signers))
+		for key := range account.Signers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			weight := account.Signers[key]
+			if weight <= 0 {
+				continue
+			}
+			if !c.signed(key) {
+				r.Missing = append(r.Missing, key)
+				continue
+			}
+			if weight > 255 {
+				weight = 255
+			}
+			r.Weight += weight
+			r.Signed = append(r.Signed, key)
+		}
+		requirements = append(requirements, r)
+	}
+	return requirements
+}
+
+// Pending returns the requirements which are not satisfied yet.
+func (c *Coordinator) Pending() []Requirement {
+	pending := []Requirement{}
+	for _, r := range c.Requirements() {
+		if !r.Satisfied() {
+			pending = append(pending, r)
+		}
+	}
+	return pending
+}
+
+// FullyAuthorized returns true if every requirement is satisfied.
+func (c *Coordinator) FullyAuthorized() bool {
+	return len(c.Pending()) == 0
+}
+
+// Transaction returns the transaction with the collected signatures. It
+// returns an error if the Coordinator was created for a fee bump transaction.
+func (c *Coordinator) Transaction() (*txnbuild.Transaction, error) {
+	if c.tx == nil {
+		return nil, errors.New("coordinator is for a fee bump transaction")
+	}
+	env, err := c.tx.TxEnvelope()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := c.withSignatures(env)
+	if err != nil {
+		return nil, err
+	}
+	tx, _ := parsed.Transaction()
+	return tx, nil
+}
+
+// FeeBumpTransaction returns the fee bump transaction with the collected
+// signatures. It returns an error if the Coordinator was created for a
+// regular transaction.
+func (c *Coordinator) FeeBumpTransaction() (*txnbuild.FeeBumpTransaction, error) {
+	if c.feeBump == nil {
+		return nil, errors.New("coordinator is not for a fee bump transaction")
+	}
+	env, err := c.feeBump.TxEnvelope()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := c.withSignatures(env)
+	if err != nil {
+		return nil, err
+	}
+	tx, _ := parsed.FeeBump()
+	return tx, nil
+}
+
+func (c *Coordinator) withSignatures(env xdr.TransactionEnvelope) (*txnbuild.GenericTransaction, error) {
+	signatures := append([]xdr.DecoratedSignature{}, c.signatures...)
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		env.V1.Signatures = signatures
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		env.V0.Signatures = signatures
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		env.FeeBump.Signatures = signatures
+	}
+
+	txeB64, err := xdr.MarshalBase64(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal envelope")
+	}
+	return txnbuild.TransactionFromXDR(txeB64)
+}
@@ -0,0 +1,272 @@
+package multisig
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/internal/muxedaccount"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice   = keypair.MustParseFull("SBPQUZ6G4FZNWFHKUWC5BEYWF6R52E3SEP7R3GWYSM2XTKGF5LNTWW4R")
+	bob     = keypair.MustParseFull("SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4")
+	carol   = keypair.MustParseFull("SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY")
+	mallory = keypair.MustRandom()
+)
+
+func buildTx(t *testing.T, ops ...txnbuild.Operation) *txnbuild.Transaction {
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: alice.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           ops,
+		BaseFee:              txnbuild.MinBaseFee,
+		Timebounds:           txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+// accounts returns alice, a 2 of 3 account on medium and 3 of 3 on high, and
+// carol, a regular account.
+func accounts() map[string]AccountSigners {
+	return map[string]AccountSigners{
+		alice.Address(): {
+			Signers: txnbuild.SignerSummary{
+				alice.Address(): 1,
+				bob.Address():   1,
+				carol.Address(): 1,
+			},
+			Thresholds: Thresholds{Low: 1, Medium: 2, High: 3},
+		},
+		carol.Address(): {
+			Signers: txnbuild.SignerSummary{carol.Address(): 1},
+		},
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	tx := buildTx(t,
+		&txnbuild.BumpSequence{BumpTo: 10},
+		&txnbuild.Payment{Destination: alice.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}, SourceAccount: &txnbuild.SimpleAccount{AccountID: carol.Address()}},
+		&txnbuild.SetOptions{HomeDomain: txnbuild.NewHomeDomain("example.com")},
+	)
+	assert.Equal(t, []string{alice.Address(), carol.Address()}, SourceAccounts(tx))
+
+	c, err := NewCoordinator(network.TestNetworkPassphrase, tx, accounts())
+	require.NoError(t, err)
+
+	requirements := c.Requirements()
+	require.Len(t, requirements, 2)
+	assert.Equal(t, Requirement{
+		Account:    alice.Address(),
+		Category:   ThresholdMedium,
+		Threshold:  2,
+		Envelope:   true,
+		Operations: []int{0, 2},
+		Signed:     []string{},
+		Missing:    requirements[0].Missing,
+	}, requirements[0])
+	assert.ElementsMatch(t, []string{alice.Address(), bob.Address(), carol.Address()}, requirements[0].Missing)
+	assert.Equal(t, carol.Address(), requirements[1].Account)
+	assert.Equal(t, []int{1}, requirements[1].Operations)
+	assert.False(t, requirements[1].Envelope)
+	assert.False(t, c.FullyAuthorized())
+
+	require.NoError(t, c.Sign(carol))
+	pending := c.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, alice.Address(), pending[0].Account)
+	assert.Equal(t, int32(1), pending[0].Weight)
+	assert.Equal(t, []string{carol.Address()}, pending[0].Signed)
+	assert.False(t, c.FullyAuthorized())
+
+	require.NoError(t, c.Sign(bob))
+	assert.True(t, c.FullyAuthorized())
+	assert.Len(t, c.Signatures(), 2)
+}
+
+func TestMuxedSourceAccounts(t *testing.T) {
+	med := xdr.MuxedAccountMed25519{Id: 7}
+	copy(med.Ed25519[:], strkey.MustDecode(strkey.VersionByteAccountID, carol.Address()))
+	muxedCarol := muxedaccount.Encode(med)
+
+	tx := buildTx(t,
+		&txnbuild.BumpSequence{BumpTo: 10, SourceAccount: &txnbuild.SimpleAccount{AccountID: muxedCarol}},
+		&txnbuild.BumpSequence{BumpTo: 11, SourceAccount: &txnbuild.SimpleAccount{AccountID: carol.Address()}},
+	)
+	// The signers of a muxed account are those of the account it belongs
+	// to, which is the account loaded from Horizon.
+	assert.Equal(t, []string{alice.Address(), carol.Address()}, SourceAccounts(tx))
+}
+
+func TestHighThreshold(t *testing.T) {
+	tx := buildTx(t, &txnbuild.SetOptions{Signer: &txnbuild.Signer{Address: mallory.Address(), Weight: 1}})
+	c, err := NewCoordinator(network.TestNetworkPassphrase, tx, accounts())
+	require.NoError(t, err)
+
+	require.NoError(t, c.Sign(alice, bob))
+	assert.False(t, c.FullyAuthorized())
+	assert.Equal(t, ThresholdHigh, c.Pending()[0].Category)
+	require.NoError(t, c.Sign(carol))
+	assert.True(t, c.FullyAuthorized())
+}
+
+func TestMergeEnvelopes(t *testing.T) {
+	tx := buildTx(t, &txnbuild.Payment{Destination: carol.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}})
+
+	fromAlice, err := tx.Sign(network.TestNetworkPassphrase, alice)
+	require.NoError(t, err)
+	fromAliceB64, err := fromAlice.Base64()
+	require.NoError(t, err)
+	fromBob, err := tx.Sign(network.TestNetworkPassphrase, bob)
+	require.NoError(t, err)
+	fromBobB64, err := fromBob.Base64()
+	require.NoError(t, err)
+
+	c, err := NewCoordinator(network.TestNetworkPassphrase, tx, accounts())
+	require.NoError(t, err)
+	require.NoError(t, c.AddEnvelope(fromAliceB64))
+	require.NoError(t, c.AddEnvelope(fromAliceB64))
+	assert.Len(t, c.Signatures(), 1)
+	assert.False(t, c.FullyAuthorized())
+	require.NoError(t, c.AddEnvelope(fromBobB64))
+	assert.True(t, c.FullyAuthorized())
+
+	signed, err := c.Transaction()
+	require.NoError(t, err)
+	assert.Len(t, signed.Signatures(), 2)
+	assert.Len(t, tx.Signatures(), 0)
+	hash, err := signed.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, c.Hash(), hash)
+
+	other := buildTx(t, &txnbuild.Payment{Destination: carol.Address(), Amount: "2", Asset: txnbuild.NativeAsset{}})
+	other, err = other.Sign(network.TestNetworkPassphrase, carol)
+	require.NoError(t, err)
+	otherB64, err := other.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, ErrHashMismatch, c.AddEnvelope(otherB64))
+}
+
+func TestRejectExtraneousSignatures(t *testing.T) {
+	tx := buildTx(t, &txnbuild.Payment{Destination: carol.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}})
+	c, err := NewCoordinator(network.TestNetworkPassphrase, tx, accounts())
+	require.NoError(t, err)
+
+	err = c.Sign(alice, mallory)
+	assert.EqualError(t, err, "signature 1 does not belong to any signer of the transaction")
+	assert.Empty(t, c.Signatures())
+
+	// carol signs for her own account which is not involved in the
+	// transaction, but she is also a signer of alice.
+	require.NoError(t, c.Sign(carol))
+
+	signed, err := tx.Sign(network.TestNetworkPassphrase, mallory)
+	require.NoError(t, err)
+	_, err = NewCoordinator(network.TestNetworkPassphrase, signed, accounts())
+	assert.Error(t, err)
+
+	_, err = NewCoordinator(network.TestNetworkPassphrase, tx, map[string]AccountSigners{})
+	assert.EqualError(t, err, "signers of account "+alice.Address()+" are unknown")
+}
+
+func TestHashXAndPreAuthSigners(t *testing.T) {
+	preimage := []byte("secret")
+	preimageHash := sha256.Sum256(preimage)
+	hashX, err := strkey.Encode(strkey.VersionByteHashX, preimageHash[:])
+	require.NoError(t, err)
+
+	tx := buildTx(t, &txnbuild.Payment{Destination: carol.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}})
+	hash, err := tx.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	preAuth, err := strkey.Encode(strkey.VersionByteHashTx, hash[:])
+	require.NoError(t, err)
+
+	accounts := map[string]AccountSigners{
+		alice.Address(): {
+			Signers:    txnbuild.SignerSummary{alice.Address(): 0, hashX: 1},
+			Thresholds: Thresholds{Medium: 1},
+		},
+	}
+	hashXTx, err := tx.SignHashX(preimage)
+	require.NoError(t, err)
+	c, err := NewCoordinator(network.TestNetworkPassphrase, hashXTx, accounts)
+	require.NoError(t, err)
+	assert.True(t, c.FullyAuthorized())
+
+	accounts[alice.Address()] = AccountSigners{
+		Signers:    txnbuild.SignerSummary{alice.Address(): 0, preAuth: 1},
+		Thresholds: Thresholds{Medium: 1},
+	}
+	c, err = NewCoordinator(network.TestNetworkPassphrase, tx, accounts)
+	require.NoError(t, err)
+	assert.True(t, c.FullyAuthorized())
+	assert.Empty(t, c.Signatures())
+}
+
+func TestFeeBump(t *testing.T) {
+	inner, err := buildTx(t, &txnbuild.Payment{Destination: carol.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}}).
+		Sign(network.TestNetworkPassphrase, alice, bob)
+	require.NoError(t, err)
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: alice.Address(),
+		BaseFee:    2 * txnbuild.MinBaseFee,
+	})
+	require.NoError(t, err)
+
+	c, err := NewFeeBumpCoordinator(network.TestNetworkPassphrase, feeBump, accounts())
+	require.NoError(t, err)
+	requirements := c.Requirements()
+	require.Len(t, requirements, 1)
+	assert.Equal(t, ThresholdLow, requirements[0].Category)
+	assert.True(t, requirements[0].Envelope)
+
+	signed, err := feeBump.Sign(network.TestNetworkPassphrase, bob)
+	require.NoError(t, err)
+	signedB64, err := signed.Base64()
+	require.NoError(t, err)
+	require.NoError(t, c.AddEnvelope(signedB64))
+	assert.True(t, c.FullyAuthorized())
+
+	result, err := c.FeeBumpTransaction()
+	require.NoError(t, err)
+	assert.Len(t, result.Signatures(), 1)
+	_, err = c.Transaction()
+	assert.Error(t, err)
+
+	innerB64, err := inner.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, ErrHashMismatch, c.AddEnvelope(innerB64))
+}
+
+func TestLoadAccounts(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: alice.Address()}).Return(hProtocol.Account{
+		AccountID: alice.Address(),
+		Signers: []hProtocol.Signer{
+			{Key: alice.Address(), Weight: 1},
+			{Key: bob.Address(), Weight: 2},
+		},
+		Thresholds: hProtocol.AccountThresholds{LowThreshold: 1, MedThreshold: 2, HighThreshold: 3},
+	}, nil)
+
+	loaded, err := LoadAccounts(client, alice.Address())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]AccountSigners{
+		alice.Address(): {
+			Signers:    txnbuild.SignerSummary{alice.Address(): 1, bob.Address(): 2},
+			Thresholds: Thresholds{Low: 1, Medium: 2, High: 3},
+		},
+	}, loaded)
+	client.AssertExpectations(t)
+}
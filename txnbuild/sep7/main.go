@@ -0,0 +1,430 @@
+/*
+Package sep7 builds and parses SEP-7 URIs, which are used to delegate the
+signing of transactions and payments to a wallet, for example through a link or
+a QR code.
+
+Two operations are defined by SEP-7: `web+stellar:tx`, which requests the
+signing of a transaction, and `web+stellar:pay`, which requests a payment to a
+destination. They are represented by TransactionRequest and PayRequest.
+
+Requests may be signed by the domain that issued them, whose
+URI_REQUEST_SIGNING_KEY is published in its stellar.toml file. See Sign and
+VerifyOriginDomain.
+
+See https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0007.md
+*/
+package sep7
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Scheme is the URI scheme of SEP-7 requests.
+const Scheme = "web+stellar:"
+
+const (
+	operationTx  = "tx"
+	operationPay = "pay"
+
+	// MaxMessageLength is the maximum length of the msg parameter.
+	MaxMessageLength = 300
+
+	callbackPrefix = "url:"
+)
+
+// Memo types used by the memo_type parameter of pay requests.
+const (
+	MemoTypeText   = "MEMO_TEXT"
+	MemoTypeID     = "MEMO_ID"
+	MemoTypeHash   = "MEMO_HASH"
+	MemoTypeReturn = "MEMO_RETURN"
+)
+
+var (
+	// ErrInvalidScheme is returned when parsing a URI which does not use the
+	// web+stellar scheme.
+	ErrInvalidScheme = errors.New("uri does not use the web+stellar scheme")
+	// ErrUnknownOperation is returned when parsing a URI with an operation
+	// other than tx or pay.
+	ErrUnknownOperation = errors.New("unknown operation")
+	// ErrMissingOriginDomain is returned when signing or verifying a request
+	// without origin_domain.
+	ErrMissingOriginDomain = errors.New("origin_domain is missing")
+	// ErrMissingSignature is returned when verifying a request without
+	// signature.
+	ErrMissingSignature = errors.New("signature is missing")
+	// ErrInvalidSignature is returned when the signature of a request does
+	// not match the signing key.
+	ErrInvalidSignature = errors.New("signature is invalid")
+)
+
+// Request is a SEP-7 request, either a *TransactionRequest or a *PayRequest.
+type Request interface {
+	// URI returns the request encoded as a web+stellar URI. The signature is
+	// included if the request was signed.
+	URI() (string, error)
+	params() ([]param, error)
+	common() *Common
+}
+
+// Common contains the parameters shared by every SEP-7 operation.
+type Common struct {
+	// Callback is the URL the signed transaction is posted to instead of
+	// being submitted to the network. It is encoded with the url: prefix.
+	Callback string
+	// Message is shown to the user. It is limited to MaxMessageLength
+	// characters.
+	Message string
+	// NetworkPassphrase defaults to the public network when empty.
+	NetworkPassphrase string
+	// OriginDomain is the fully qualified domain name of the issuer of the
+	// request. It is required for signed requests.
+	OriginDomain string
+	// Signature is the base64 encoded signature of the request by the
+	// URI_REQUEST_SIGNING_KEY of OriginDomain.
+	Signature string
+}
+
+func (c *Common) common() *Common {
+	return c
+}
+
+func (c *Common) callbackParam() param {
+	if c.Callback == "" {
+		return param{key: "callback"}
+	}
+	return param{"callback", callbackPrefix + c.Callback}
+}
+
+func (c *Common) commonParams() ([]param, error) {
+	if len(c.Message) > MaxMessageLength {
+		return nil, errors.Errorf("msg is longer than %d characters", MaxMessageLength)
+	}
+	return []param{
+		{"msg", c.Message},
+		{"network_passphrase", c.NetworkPassphrase},
+		{"origin_domain", c.OriginDomain},
+	}, nil
+}
+
+// TransactionRequest is a web+stellar:tx request asking a wallet to sign a
+// transaction.
+type TransactionRequest struct {
+	// XDR is the base64 encoded transaction envelope to sign.
+	XDR string
+	// Replace lists the fields of the transaction the wallet should replace,
+	// using the Txrep notation of SEP-11.
+	Replace string
+	// Pubkey is the account which should sign the transaction.
+	Pubkey string
+	// Chain is a SEP-7 request which spawned this request.
+	Chain string
+	Common
+}
+
+// NewTransactionRequest returns a request asking a wallet to sign tx.
+func NewTransactionRequest(tx *txnbuild.Transaction) (*TransactionRequest, error) {
+	envelope, err := tx.Base64()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode transaction")
+	}
+	return &TransactionRequest{XDR: envelope}, nil
+}
+
+// Transaction decodes the transaction of the request.
+func (r *TransactionRequest) Transaction() (*txnbuild.GenericTransaction, error) {
+	return txnbuild.TransactionFromXDR(r.XDR)
+}
+
+// URI returns the request encoded as a web+stellar:tx URI.
+func (r *TransactionRequest) URI() (string, error) {
+	return encode(r)
+}
+
+func (r *TransactionRequest) params() ([]param, error) {
+	if r.XDR == "" {
+		return nil, errors.New("xdr is missing")
+	}
+
+	common, err := r.commonParams()
+	if err != nil {
+		return nil, err
+	}
+	params := []param{
+		{"xdr", r.XDR},
+		{"replace", r.Replace},
+		r.callbackParam(),
+		{"pubkey", r.Pubkey},
+		{"chain", r.Chain},
+	}
+	return append(params, common...), nil
+}
+
+// PayRequest is a web+stellar:pay request asking a wallet to pay a
+// destination.
+type PayRequest struct {
+	// Destination is an account ID, a muxed account or a federation address.
+	Destination string
+	// Amount is optional, in which case the wallet asks the user for it.
+	Amount string
+	// Asset defaults to XLM when nil.
+	Asset txnbuild.Asset
+	// Memo is optional.
+	Memo txnbuild.Memo
+	Common
+}
+
+// NewPayRequest returns a request asking a wallet to pay amount of asset to
+// destination, with an optional memo.
+func NewPayRequest(destination, amount string, asset txnbuild.Asset, memo txnbuild.Memo) *PayRequest {
+	return &PayRequest{
+		Destination: destination,
+		Amount:      amount,
+		Asset:       asset,
+		Memo:        memo,
+	}
+}
+
+// URI returns the request encoded as a web+stellar:pay URI.
+func (r *PayRequest) URI() (string, error) {
+	return encode(r)
+}
+
+func (r *PayRequest) params() ([]param, error) {
+	if r.Destination == "" {
+		return nil, errors.New("destination is missing")
+	}
+
+	params := []param{
+		{"destination", r.Destination},
+		{"amount", r.Amount},
+	}
+	if r.Asset != nil && !r.Asset.IsNative() {
+		params = append(params,
+			param{"asset_code", r.Asset.GetCode()},
+			param{"asset_issuer", r.Asset.GetIssuer()},
+		)
+	}
+
+	if r.Memo != nil {
+		memo, memoType, err := encodeMemo(r.Memo)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param{"memo", memo}, param{"memo_type", memoType})
+	}
+
+	common, err := r.commonParams()
+	if err != nil {
+		return nil, err
+	}
+	params = append(params, r.callbackParam())
+	return append(params, common...), nil
+}
+
+func encodeMemo(memo txnbuild.Memo) (string, string, error) {
+	switch memo := memo.(type) {
+	case txnbuild.MemoText:
+		if len(memo) > txnbuild.MemoTextMaxLength {
+			return "", "", errors.Errorf("memo text can't be longer than %d bytes", txnbuild.MemoTextMaxLength)
+		}
+		return string(memo), MemoTypeText, nil
+	case txnbuild.MemoID:
+		return strconv.FormatUint(uint64(memo), 10), MemoTypeID, nil
+	case txnbuild.MemoHash:
+		return base64.StdEncoding.EncodeToString(memo[:]), MemoTypeHash, nil
+	case txnbuild.MemoReturn:
+		return base64.StdEncoding.EncodeToString(memo[:]), MemoTypeReturn, nil
+	}
+	return "", "", errors.Errorf("unsupported memo type %T", memo)
+}
+
+func decodeMemo(value, memoType string) (txnbuild.Memo, error) {
+	switch memoType {
+	case "", MemoTypeText:
+		if len(value) > txnbuild.MemoTextMaxLength {
+			return nil, errors.Errorf("memo text can't be longer than %d bytes", txnbuild.MemoTextMaxLength)
+		}
+		return txnbuild.MemoText(value), nil
+	case MemoTypeID:
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid memo id")
+		}
+		return txnbuild.MemoID(id), nil
+	case MemoTypeHash, MemoTypeReturn:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(decoded) != 32 {
+			return nil, errors.New("memo hash must be 32 base64 encoded bytes")
+		}
+		var hash [32]byte
+		copy(hash[:], decoded)
+		if memoType == MemoTypeHash {
+			return txnbuild.MemoHash(hash), nil
+		}
+		return txnbuild.MemoReturn(hash), nil
+	}
+	return nil, errors.Errorf("unknown memo_type %s", memoType)
+}
+
+type param struct {
+	key   string
+	value string
+}
+
+// escape encodes a parameter value like encodeURIComponent, which SEP-7
+// uses, so spaces are encoded as %20 rather than +.
+func escape(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+// encodeUnsigned returns the URI of a request without its signature.
+func encodeUnsigned(r Request) (string, error) {
+	params, err := r.params()
+	if err != nil {
+		return "", err
+	}
+
+	operation := operationTx
+	if _, ok := r.(*PayRequest); ok {
+		operation = operationPay
+	}
+
+	var b strings.Builder
+	b.WriteString(Scheme)
+	b.WriteString(operation)
+	separator := "?"
+	for _, p := range params {
+		if p.value == "" {
+			continue
+		}
+		b.WriteString(separator)
+		b.WriteString(p.key)
+		b.WriteString("=")
+		b.WriteString(escape(p.value))
+		separator = "&"
+	}
+	return b.String(), nil
+}
+
+// encode returns the URI of a request, with its signature as the last
+// parameter if it is signed.
+func encode(r Request) (string, error) {
+	uri, err := encodeUnsigned(r)
+	if err != nil {
+		return "", err
+	}
+	if signature := r.common().Signature; signature != "" {
+		uri += "&signature=" + escape(signature)
+	}
+	return uri, nil
+}
+
+// Parse parses a web+stellar URI, returning either a *TransactionRequest or a
+// *PayRequest. The signature, if any, is not verified.
+func Parse(uri string) (Request, error) {
+	if !strings.HasPrefix(uri, Scheme) {
+		return nil, ErrInvalidScheme
+	}
+
+	operation := strings.TrimPrefix(uri, Scheme)
+	query := ""
+	if i := strings.Index(operation, "?"); i >= 0 {
+		operation, query = operation[:i], operation[i+1:]
+	}
+
+	values, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	common := Common{
+		Message:           values["msg"],
+		NetworkPassphrase: values["network_passphrase"],
+		OriginDomain:      values["origin_domain"],
+		Signature:         values["signature"],
+	}
+	if callback, ok := values["callback"]; ok {
+		if !strings.HasPrefix(callback, callbackPrefix) {
+			return nil, errors.New("callback must start with url:")
+		}
+		common.Callback = strings.TrimPrefix(callback, callbackPrefix)
+	}
+	if len(common.Message) > MaxMessageLength {
+		return nil, errors.Errorf("msg is longer than %d characters", MaxMessageLength)
+	}
+
+	switch operation {
+	case operationTx:
+		r := &TransactionRequest{
+			XDR:     values["xdr"],
+			Replace: values["replace"],
+			Pubkey:  values["pubkey"],
+			Chain:   values["chain"],
+			Common:  common,
+		}
+		if r.XDR == "" {
+			return nil, errors.New("xdr is missing")
+		}
+		if _, err := r.Transaction(); err != nil {
+			return nil, errors.Wrap(err, "invalid xdr")
+		}
+		return r, nil
+	case operationPay:
+		r := &PayRequest{
+			Destination: values["destination"],
+			Amount:      values["amount"],
+			Common:      common,
+		}
+		if r.Destination == "" {
+			return nil, errors.New("destination is missing")
+		}
+		code, issuer := values["asset_code"], values["asset_issuer"]
+		switch {
+		case code != "" && issuer != "":
+			r.Asset = txnbuild.CreditAsset{Code: code, Issuer: issuer}
+		case code == "" && issuer == "":
+			r.Asset = txnbuild.NativeAsset{}
+		default:
+			return nil, errors.New("asset_code and asset_issuer must be set together")
+		}
+		if memo, ok := values["memo"]; ok {
+			if r.Memo, err = decodeMemo(memo, values["memo_type"]); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	return nil, errors.Wrap(ErrUnknownOperation, operation)
+}
+
+// parseQuery decodes the parameters of a URI. Values are unescaped like
+// decodeURIComponent does, so + is not turned into a space, which would
+// corrupt base64 encoded values.
+func parseQuery(query string) (map[string]string, error) {
+	values := map[string]string{}
+	if query == "" {
+		return values, nil
+	}
+	for _, pair := range strings.Split(query, "&") {
+		parts := strings.SplitN(pair, "=", 2)
+		value := ""
+		if len(parts) == 2 {
+			var err error
+			if value, err = url.PathUnescape(parts[1]); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for %s", parts[0])
+			}
+		}
+		if _, ok := values[parts[0]]; ok {
+			return nil, errors.Errorf("duplicate parameter %s", parts[0])
+		}
+		values[parts[0]] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,140 @@
+package sep7
+
+import (
+	"testing"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const destination = "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO"
+
+func TestPayRequest(t *testing.T) {
+	asset := txnbuild.CreditAsset{Code: "USD", Issuer: "GCRCUE2C5TBNIPYHMEP7NK5RWTT2WBSZ75CMARH7GDOHDDCQH3XANFOB"}
+	r := NewPayRequest(destination, "120.1234567", asset, txnbuild.MemoText("skdjfasf"))
+	r.Message = "pay me with lumens"
+
+	uri, err := r.URI()
+	require.NoError(t, err)
+	assert.Equal(t, "web+stellar:pay?destination="+destination+
+		"&amount=120.1234567&asset_code=USD&asset_issuer=GCRCUE2C5TBNIPYHMEP7NK5RWTT2WBSZ75CMARH7GDOHDDCQH3XANFOB"+
+		"&memo=skdjfasf&memo_type=MEMO_TEXT&msg=pay%20me%20with%20lumens", uri)
+
+	parsed, err := Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, r, parsed)
+}
+
+func TestPayRequestMemos(t *testing.T) {
+	for _, memo := range []txnbuild.Memo{
+		txnbuild.MemoID(123),
+		txnbuild.MemoHash{1, 2, 3},
+		txnbuild.MemoReturn{4, 5, 6},
+	} {
+		r := NewPayRequest(destination, "", txnbuild.NativeAsset{}, memo)
+		uri, err := r.URI()
+		require.NoError(t, err)
+
+		parsed, err := Parse(uri)
+		require.NoError(t, err)
+		assert.Equal(t, memo, parsed.(*PayRequest).Memo)
+	}
+}
+
+func TestTransactionRequest(t *testing.T) {
+	kp := keypair.MustParseFull("SBPQUZ6G4FZNWFHKUWC5BEYWF6R52E3SEP7R3GWYSM2XTKGF5LNTWW4R")
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: destination, Amount: "10", Asset: txnbuild.NativeAsset{}},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+
+	r, err := NewTransactionRequest(tx)
+	require.NoError(t, err)
+	r.Callback = "https://example.com/callback?a=b"
+	r.Pubkey = kp.Address()
+	r.NetworkPassphrase = network.TestNetworkPassphrase
+
+	uri, err := r.URI()
+	require.NoError(t, err)
+	assert.Contains(t, uri, "&callback=url%3Ahttps%3A%2F%2Fexample.com%2Fcallback%3Fa%3Db&pubkey=")
+	assert.NotContains(t, uri[len(Scheme):], "+")
+
+	parsed, err := Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, r, parsed)
+
+	decoded, err := parsed.(*TransactionRequest).Transaction()
+	require.NoError(t, err)
+	decodedTx, ok := decoded.Transaction()
+	require.True(t, ok)
+	expectedHash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	hash, err := decodedTx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, expectedHash, hash)
+}
+
+func TestParseErrors(t *testing.T) {
+	for uri, expected := range map[string]string{
+		"https://example.com":                                           "uri does not use the web+stellar scheme",
+		"web+stellar:foo?destination=" + destination:                    "foo: unknown operation",
+		"web+stellar:pay?amount=1":                                      "destination is missing",
+		"web+stellar:tx?xdr=AAAA":                                       "invalid xdr",
+		"web+stellar:pay?destination=a&callback=foo":                    "callback must start with url:",
+		"web+stellar:pay?destination=a&memo=1&memo_type=MEMO_ID&memo=2": "duplicate parameter memo",
+		"web+stellar:pay?destination=a&asset_code=USD":                  "asset_code and asset_issuer must be set together",
+		"web+stellar:pay?destination=a&asset_issuer=" + destination:     "asset_code and asset_issuer must be set together",
+	} {
+		_, err := Parse(uri)
+		if assert.Error(t, err, uri) {
+			assert.Contains(t, err.Error(), expected, uri)
+		}
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer := keypair.MustParseFull("SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4")
+	other := keypair.MustParseFull("SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY")
+
+	r := NewPayRequest(destination, "1", nil, nil)
+	_, err := Sign(r, signer)
+	assert.Equal(t, ErrMissingOriginDomain, err)
+
+	r.OriginDomain = "example.com"
+	uri, err := Sign(r, signer)
+	require.NoError(t, err)
+	assert.NotEmpty(t, r.Signature)
+	assert.Contains(t, uri, "&origin_domain=example.com&signature=")
+
+	assert.NoError(t, Verify(uri, signer.Address()))
+	assert.Equal(t, ErrInvalidSignature, Verify(uri, other.Address()))
+	assert.Equal(t, ErrInvalidSignature, Verify(uri[:len("web+stellar:pay?destination=")]+other.Address()+
+		uri[len("web+stellar:pay?destination=")+len(destination):], signer.Address()))
+
+	unsigned, err := NewPayRequest(destination, "1", nil, nil).URI()
+	require.NoError(t, err)
+	assert.Equal(t, ErrMissingSignature, Verify(unsigned, signer.Address()))
+
+	client := &stellartoml.MockClient{}
+	client.On("GetStellarToml", "example.com").
+		Return(&stellartoml.Response{UriRequestSigningKey: signer.Address()}, nil).Once()
+	parsed, err := VerifyOriginDomain(client, uri)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", parsed.(*PayRequest).OriginDomain)
+
+	client.On("GetStellarToml", "example.com").
+		Return(&stellartoml.Response{UriRequestSigningKey: other.Address()}, nil).Once()
+	_, err = VerifyOriginDomain(client, uri)
+	assert.Equal(t, ErrInvalidSignature, err)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,102 @@
+package sep7
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+const signaturePrefix = "stellar.sep.7 - URI Scheme"
+
+// signaturePayload returns the payload signed by the origin domain: 35 zero
+// bytes and the byte 4, followed by the prefix and the URI without its
+// signature.
+func signaturePayload(uri string) []byte {
+	payload := make([]byte, 36, 36+len(signaturePrefix)+len(uri))
+	payload[35] = 4
+	payload = append(payload, signaturePrefix...)
+	return append(payload, uri...)
+}
+
+// Sign signs the request with the URI_REQUEST_SIGNING_KEY of its origin
+// domain, sets its Signature and returns the signed URI.
+func Sign(r Request, signer *keypair.Full) (string, error) {
+	if r.common().OriginDomain == "" {
+		return "", ErrMissingOriginDomain
+	}
+
+	uri, err := encodeUnsigned(r)
+	if err != nil {
+		return "", err
+	}
+	signature, err := signer.Sign(signaturePayload(uri))
+	if err != nil {
+		return "", errors.Wrap(err, "could not sign uri")
+	}
+
+	r.common().Signature = base64.StdEncoding.EncodeToString(signature)
+	return encode(r)
+}
+
+// Verify checks that uri was signed by signingKey.
+func Verify(uri, signingKey string) error {
+	// The signature covers the URI exactly as it was encoded by the signer,
+	// so it is checked against the raw URI rather than a re-encoded request.
+	i := strings.LastIndex(uri, "&signature=")
+	if i < 0 {
+		return ErrMissingSignature
+	}
+	unsigned, encodedSignature := uri[:i], uri[i+len("&signature="):]
+
+	value, err := url.PathUnescape(encodedSignature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature encoding")
+	}
+	signature, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature encoding")
+	}
+
+	kp, err := keypair.ParseAddress(signingKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid signing key")
+	}
+	if err := kp.Verify(signaturePayload(unsigned), signature); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyOriginDomain parses uri and checks that it was signed by the
+// URI_REQUEST_SIGNING_KEY published in the stellar.toml of its origin domain.
+func VerifyOriginDomain(client stellartoml.ClientInterface, uri string) (Request, error) {
+	r, err := Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	common := r.common()
+	if common.OriginDomain == "" {
+		return nil, ErrMissingOriginDomain
+	}
+	if common.Signature == "" {
+		return nil, ErrMissingSignature
+	}
+
+	toml, err := client.GetStellarToml(common.OriginDomain)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not fetch stellar.toml of %s", common.OriginDomain)
+	}
+	if toml.UriRequestSigningKey == "" {
+		return nil, errors.Errorf("stellar.toml of %s has no URI_REQUEST_SIGNING_KEY", common.OriginDomain)
+	}
+
+	if err := Verify(uri, toml.UriRequestSigningKey); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,54 @@
+package simulator
+
+// Transaction result codes, as reported by Horizon.
+const (
+	TxSuccess             = "tx_success"
+	TxFailed              = "tx_failed"
+	TxTooEarly            = "tx_too_early"
+	TxTooLate             = "tx_too_late"
+	TxMissingOperation    = "tx_missing_operation"
+	TxBadSeq              = "tx_bad_seq"
+	TxBadAuth             = "tx_bad_auth"
+	TxInsufficientBalance = "tx_insufficient_balance"
+	TxNoSourceAccount     = "tx_no_source_account"
+	TxInsufficientFee     = "tx_insufficient_fee"
+	TxBadAuthExtra        = "tx_bad_auth_extra"
+	TxBadSponsorship      = "tx_bad_sponsorship"
+	TxFeeBumpInnerSuccess = "tx_fee_bump_inner_success"
+	TxFeeBumpInnerFailed  = "tx_fee_bump_inner_failed"
+)
+
+// Operation result codes, as reported by Horizon.
+const (
+	OpSuccess           = "op_success"
+	OpBadAuth           = "op_bad_auth"
+	OpNoSourceAccount   = "op_no_source_account"
+	OpMalformed         = "op_malformed"
+	OpUnderfunded       = "op_underfunded"
+	OpLowReserve        = "op_low_reserve"
+	OpLineFull          = "op_line_full"
+	OpNoIssuer          = "op_no_issuer"
+	OpNoTrust           = "op_no_trust"
+	OpNotAuthorized     = "op_not_authorized"
+	OpSrcNoTrust        = "op_src_no_trust"
+	OpSrcNotAuthorized  = "op_src_not_authorized"
+	OpNoDestination     = "op_no_destination"
+	OpAlreadyExists     = "op_already_exists"
+	OpInvalidLimit      = "op_invalid_limit"
+	OpSelfNotAllowed    = "op_self_not_allowed"
+	OpDataNameNotFound  = "op_data_name_not_found"
+	OpDataInvalidName   = "op_data_invalid_name"
+	OpTooManySigners    = "op_too_many_signers"
+	OpBadFlags          = "op_bad_flags"
+	OpInvalidInflation  = "op_invalid_inflation"
+	OpCantChange        = "op_cant_change"
+	OpUnknownFlag       = "op_unknown_flag"
+	OpBadSigner         = "op_bad_signer"
+	OpInvalidHomeDomain = "op_invalid_home_domain"
+	OpBadSeq            = "op_bad_seq"
+	OpDoesNotExist      = "op_does_not_exist"
+	OpCannotClaim       = "op_cannot_claim"
+	OpAlreadySponsored  = "op_already_sponsored"
+	OpRecursive         = "op_recursive"
+	OpNotSponsored      = "op_not_sponsored"
+)
@@ -0,0 +1,168 @@
+package simulator
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// LoadLedger returns a ledger snapshot containing the given accounts, as
+// returned by Horizon. Callers should include every account touched by the
+// transactions they want to simulate, including destinations and asset
+// issuers.
+func LoadLedger(client horizonclient.ClientInterface, networkPassphrase string, accountIDs ...string) (*Ledger, error) {
+	ledger := NewLedger(networkPassphrase)
+	for _, accountID := range accountIDs {
+		record, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+		if err != nil {
+			if horizonclient.IsNotFoundError(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "could not load account %s", accountID)
+		}
+
+		account, err := AccountFromHorizon(record)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse account %s", accountID)
+		}
+		ledger.AddAccount(account)
+	}
+	return ledger, nil
+}
+
+// AccountFromHorizon converts an account returned by Horizon to an Account.
+func AccountFromHorizon(record hProtocol.Account) (Account, error) {
+	sequence, err := strconv.ParseInt(record.Sequence, 10, 64)
+	if err != nil {
+		return Account{}, errors.Wrap(err, "invalid sequence")
+	}
+
+	account := Account{
+		AccountID:            record.AccountID,
+		Sequence:             sequence,
+		NumSubEntries:        uint32(record.SubentryCount),
+		NumSponsoring:        record.NumSponsoring,
+		NumSponsored:         record.NumSponsored,
+		Sponsor:              record.Sponsor,
+		LowThreshold:         txnbuild.Threshold(record.Thresholds.LowThreshold),
+		MediumThreshold:      txnbuild.Threshold(record.Thresholds.MedThreshold),
+		HighThreshold:        txnbuild.Threshold(record.Thresholds.HighThreshold),
+		HomeDomain:           record.HomeDomain,
+		InflationDestination: record.InflationDestination,
+		Trustlines:           map[string]Trustline{},
+		Data:                 map[string]DataEntry{},
+	}
+
+	if record.Flags.AuthRequired {
+		account.Flags |= txnbuild.AuthRequired
+	}
+	if record.Flags.AuthRevocable {
+		account.Flags |= txnbuild.AuthRevocable
+	}
+	if record.Flags.AuthImmutable {
+		account.Flags |= txnbuild.AuthImmutable
+	}
+
+	for _, signer := range record.Signers {
+		if signer.Key == record.AccountID {
+			account.MasterWeight = txnbuild.Threshold(signer.Weight)
+			continue
+		}
+		account.Signers = append(account.Signers, Signer{
+			Key:     signer.Key,
+			Weight:  signer.Weight,
+			Sponsor: signer.Sponsor,
+		})
+	}
+
+	for _, balance := range record.Balances {
+		var amounts [3]int64
+		for i, value := range []string{balance.Balance, balance.BuyingLiabilities, balance.SellingLiabilities} {
+			if amounts[i], err = parseAmount(value); err != nil {
+				return Account{}, err
+			}
+		}
+
+		if balance.Type == "native" {
+			account.Balance = amounts[0]
+			account.BuyingLiabilities = amounts[1]
+			account.SellingLiabilities = amounts[2]
+			continue
+		}
+
+		limit, err := parseAmount(balance.Limit)
+		if err != nil {
+			return Account{}, err
+		}
+		line := Trustline{
+			Asset:              txnbuild.CreditAsset{Code: balance.Code, Issuer: balance.Issuer},
+			Balance:            amounts[0],
+			Limit:              limit,
+			BuyingLiabilities:  amounts[1],
+			SellingLiabilities: amounts[2],
+			Sponsor:            balance.Sponsor,
+		}
+		if balance.IsAuthorized != nil {
+			line.Authorized = *balance.IsAuthorized
+		}
+		if balance.IsAuthorizedToMaintainLiabilities != nil {
+			line.AuthorizedToMaintainLiabilities = *balance.IsAuthorizedToMaintainLiabilities
+		}
+		account.Trustlines[assetKey(line.Asset)] = line
+	}
+
+	for name, value := range record.Data {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return Account{}, errors.Wrapf(err, "invalid value for data entry %s", name)
+		}
+		account.Data[name] = DataEntry{Value: decoded}
+	}
+
+	return account, nil
+}
+
+// ClaimableBalanceFromHorizon converts a claimable balance returned by
+// Horizon to a ClaimableBalance.
+func ClaimableBalanceFromHorizon(record hProtocol.ClaimableBalance) (ClaimableBalance, error) {
+	asset, err := txnbuild.ParseAssetString(record.Asset)
+	if err != nil {
+		return ClaimableBalance{}, errors.Wrap(err, "invalid asset")
+	}
+	amt, err := parseAmount(record.Amount)
+	if err != nil {
+		return ClaimableBalance{}, err
+	}
+
+	claimants := make([]txnbuild.Claimant, len(record.Claimants))
+	for i, claimant := range record.Claimants {
+		claimants[i] = txnbuild.Claimant{
+			Destination: claimant.Destination,
+			Predicate:   claimant.Predicate,
+		}
+	}
+
+	return ClaimableBalance{
+		BalanceID: record.BalanceID,
+		Asset:     asset,
+		Amount:    amt,
+		Claimants: claimants,
+		Sponsor:   record.Sponsor,
+	}, nil
+}
+
+func parseAmount(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := amount.ParseInt64(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid amount %s", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,333 @@
+/*
+Package simulator predicts the outcome of submitting a txnbuild transaction
+without sending it to the network.
+
+A Ledger holds a snapshot of the accounts and claimable balances touched by a
+transaction. The snapshot can be built by hand or loaded from Horizon with
+LoadAccounts. Simulate applies the transaction to a copy of the snapshot the
+same way Stellar Core would, and returns the result codes Horizon would report,
+the fee charged and the resulting ledger state.
+
+Only the most common operations are supported: Payment, CreateAccount,
+ChangeTrust, ManageData, SetOptions, BumpSequence, CreateClaimableBalance,
+ClaimClaimableBalance, BeginSponsoringFutureReserves and
+EndSponsoringFutureReserves. Simulating a transaction containing any other
+operation returns ErrUnsupportedOperation.
+
+Accounts which are not present in the snapshot are considered not to exist.
+Offers are not modelled, so liabilities are taken as they are in the snapshot.
+*/
+package simulator
+
+import (
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/txnbuild/multisig"
+)
+
+// DefaultBaseReserve is the base reserve, in stroops, used when
+// Ledger.BaseReserve is not set.
+const DefaultBaseReserve = 5000000
+
+// ErrUnsupportedOperation is returned when a transaction contains an
+// operation the simulator does not know how to apply.
+var ErrUnsupportedOperation = errors.New("operation is not supported by the simulator")
+
+// Signer is an additional signer of an account. Key is either an account ID
+// (G...), a pre-authorized transaction hash (T...) or a hash(x) (X...).
+type Signer struct {
+	Key     string
+	Weight  int32
+	Sponsor string
+}
+
+// Trustline is the state of an account's trustline to a credit asset.
+type Trustline struct {
+	Asset                           txnbuild.CreditAsset
+	Balance                         int64
+	Limit                           int64
+	BuyingLiabilities               int64
+	SellingLiabilities              int64
+	Authorized                      bool
+	AuthorizedToMaintainLiabilities bool
+	Sponsor                         string
+}
+
+// DataEntry is a data entry attached to an account.
+type DataEntry struct {
+	Value   []byte
+	Sponsor string
+}
+
+// Account is the state of an account relevant to simulating transactions.
+// All the amounts are expressed in stroops.
+type Account struct {
+	AccountID            string
+	Sequence             int64
+	Balance              int64
+	BuyingLiabilities    int64
+	SellingLiabilities   int64
+	NumSubEntries        uint32
+	NumSponsoring        uint32
+	NumSponsored         uint32
+	Sponsor              string
+	Flags                txnbuild.AccountFlag
+	MasterWeight         txnbuild.Threshold
+	LowThreshold         txnbuild.Threshold
+	MediumThreshold      txnbuild.Threshold
+	HighThreshold        txnbuild.Threshold
+	HomeDomain           string
+	InflationDestination string
+	Signers              []Signer
+	// Trustlines are keyed by "CODE:ISSUER".
+	Trustlines map[string]Trustline
+	Data       map[string]DataEntry
+}
+
+// ClaimableBalance is the state of a claimable balance. The predicates of the
+// claimants are expected to be absolute, like the ones returned by Horizon.
+type ClaimableBalance struct {
+	BalanceID string
+	Asset     txnbuild.Asset
+	Amount    int64
+	Claimants []txnbuild.Claimant
+	Sponsor   string
+}
+
+// Ledger is a snapshot of the ledger state a transaction is simulated
+// against.
+type Ledger struct {
+	// NetworkPassphrase is used to hash transactions when checking signatures.
+	NetworkPassphrase string
+	// BaseFee is the network base fee per operation. It defaults to
+	// txnbuild.MinBaseFee.
+	BaseFee int64
+	// BaseReserve is the network base reserve. It defaults to
+	// DefaultBaseReserve.
+	BaseReserve int64
+	// LedgerSequence is the sequence of the ledger the transaction would be
+	// included in. It is used to compute the sequence number of new accounts.
+	LedgerSequence uint32
+	// CloseTime is the close time of the ledger the transaction would be
+	// included in. It defaults to the current time.
+	CloseTime time.Time
+	// SkipSignatures disables the signature and threshold checks, which is
+	// useful to simulate transactions before they are signed.
+	SkipSignatures bool
+
+	Accounts          map[string]*Account
+	ClaimableBalances map[string]*ClaimableBalance
+}
+
+// Result is the predicted outcome of a transaction.
+type Result struct {
+	// Successful is true if the transaction would be applied successfully.
+	Successful bool
+	// ResultCodes are the result codes Horizon would report for the
+	// transaction.
+	ResultCodes hProtocol.TransactionResultCodes
+	// FeeCharged is the fee, in stroops, charged to the fee account. It is 0
+	// when the transaction would be rejected without being included in a
+	// ledger.
+	FeeCharged int64
+	// Ledger is the state of the snapshot after the transaction is applied.
+	// When the transaction fails only the fee and sequence number changes are
+	// reflected.
+	Ledger *Ledger
+}
+
+// NewLedger returns a ledger snapshot for the given network containing the
+// given accounts.
+func NewLedger(networkPassphrase string, accounts ...Account) *Ledger {
+	l := &Ledger{
+		NetworkPassphrase: networkPassphrase,
+		Accounts:          map[string]*Account{},
+		ClaimableBalances: map[string]*ClaimableBalance{},
+	}
+	for _, account := range accounts {
+		l.AddAccount(account)
+	}
+	return l
+}
+
+// AddAccount adds or replaces an account in the snapshot.
+func (l *Ledger) AddAccount(account Account) {
+	if l.Accounts == nil {
+		l.Accounts = map[string]*Account{}
+	}
+	account = account.clone()
+	l.Accounts[account.AccountID] = &account
+}
+
+// AddClaimableBalance adds or replaces a claimable balance in the snapshot.
+func (l *Ledger) AddClaimableBalance(balance ClaimableBalance) {
+	if l.ClaimableBalances == nil {
+		l.ClaimableBalances = map[string]*ClaimableBalance{}
+	}
+	balance.Claimants = append([]txnbuild.Claimant(nil), balance.Claimants...)
+	l.ClaimableBalances[balance.BalanceID] = &balance
+}
+
+// Balance returns the balance of asset held by accountID, in stroops. The
+// second return value is false if the account or the trustline do not exist.
+func (l *Ledger) Balance(accountID string, asset txnbuild.Asset) (int64, bool) {
+	account, ok := l.Accounts[accountID]
+	if !ok {
+		return 0, false
+	}
+	if asset.IsNative() {
+		return account.Balance, true
+	}
+	line, ok := account.Trustlines[assetKey(asset)]
+	return line.Balance, ok
+}
+
+// MinimumBalance returns the minimum native balance the account must hold
+// given its subentries and sponsorships.
+func (l *Ledger) MinimumBalance(account *Account) int64 {
+	entries := 2 + int64(account.NumSubEntries) + int64(account.NumSponsoring) - int64(account.NumSponsored)
+	return entries * l.baseReserve()
+}
+
+func (l *Ledger) baseFee() int64 {
+	if l.BaseFee == 0 {
+		return txnbuild.MinBaseFee
+	}
+	return l.BaseFee
+}
+
+func (l *Ledger) baseReserve() int64 {
+	if l.BaseReserve == 0 {
+		return DefaultBaseReserve
+	}
+	return l.BaseReserve
+}
+
+func (l *Ledger) closeTime() time.Time {
+	if l.CloseTime.IsZero() {
+		return time.Now()
+	}
+	return l.CloseTime
+}
+
+// clone returns a deep copy of the ledger, with CloseTime resolved so that
+// every step of a simulation sees the same time.
+func (l *Ledger) clone() *Ledger {
+	c := *l
+	c.CloseTime = l.closeTime()
+	c.Accounts = make(map[string]*Account, len(l.Accounts))
+	for id, account := range l.Accounts {
+		a := account.clone()
+		c.Accounts[id] = &a
+	}
+	c.ClaimableBalances = make(map[string]*ClaimableBalance, len(l.ClaimableBalances))
+	for id, balance := range l.ClaimableBalances {
+		b := *balance
+		b.Claimants = append([]txnbuild.Claimant(nil), balance.Claimants...)
+		c.ClaimableBalances[id] = &b
+	}
+	return &c
+}
+
+func (a Account) clone() Account {
+	a.Signers = append([]Signer(nil), a.Signers...)
+	trustlines := make(map[string]Trustline, len(a.Trustlines))
+	for key, line := range a.Trustlines {
+		trustlines[key] = line
+	}
+	a.Trustlines = trustlines
+	data := make(map[string]DataEntry, len(a.Data))
+	for key, entry := range a.Data {
+		data[key] = entry
+	}
+	a.Data = data
+	return a
+}
+
+// Simulate predicts the outcome of submitting tx. The snapshot is not
+// modified. An error is returned when the transaction cannot be simulated,
+// for example when it contains an unsupported operation.
+func (l *Ledger) Simulate(tx *txnbuild.Transaction) (Result, error) {
+	s := newSimulation(l)
+	if err := checkSupported(tx); err != nil {
+		return Result{}, err
+	}
+
+	source := tx.SourceAccount().AccountID
+	fee := s.ledger.baseFee() * int64(len(tx.Operations()))
+	if code := s.checkTransaction(tx); code != "" {
+		return s.reject(code), nil
+	}
+	if code := s.checkFee(source, tx.MaxFee(), fee); code != "" {
+		return s.reject(code), nil
+	}
+
+	signatures, err := s.signatureChecker(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	if !signatures.check(s.ledger.Accounts[source], multisig.ThresholdLow) {
+		return s.reject(TxBadAuth), nil
+	}
+
+	s.chargeFee(source, fee)
+	txCode, opCodes, err := s.apply(tx, signatures)
+	if err != nil {
+		return Result{}, err
+	}
+	return s.result(txCode, opCodes, fee), nil
+}
+
+// SimulateFeeBump predicts the outcome of submitting a fee bump transaction.
+// The fee is charged to the fee account and the inner transaction is applied
+// like in Simulate.
+func (l *Ledger) SimulateFeeBump(tx *txnbuild.FeeBumpTransaction) (Result, error) {
+	s := newSimulation(l)
+	inner := tx.InnerTransaction()
+	if err := checkSupported(inner); err != nil {
+		return Result{}, err
+	}
+
+	feeAccount := tx.FeeAccount()
+	fee := s.ledger.baseFee() * int64(len(inner.Operations())+1)
+	if code := s.checkTransaction(inner); code != "" {
+		return s.reject(TxFeeBumpInnerFailed), nil
+	}
+	if code := s.checkFee(feeAccount, tx.MaxFee(), fee); code != "" {
+		return s.reject(code), nil
+	}
+
+	outer, err := s.signatureChecker(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	if !outer.check(s.ledger.Accounts[feeAccount], multisig.ThresholdLow) {
+		return s.reject(TxBadAuth), nil
+	}
+	if !outer.allUsed() {
+		return s.reject(TxBadAuthExtra), nil
+	}
+
+	signatures, err := s.signatureChecker(inner)
+	if err != nil {
+		return Result{}, err
+	}
+	if !signatures.check(s.ledger.Accounts[inner.SourceAccount().AccountID], multisig.ThresholdLow) {
+		return s.reject(TxFeeBumpInnerFailed), nil
+	}
+
+	s.chargeFee(feeAccount, fee)
+	txCode, opCodes, err := s.apply(inner, signatures)
+	if err != nil {
+		return Result{}, err
+	}
+	if txCode == TxSuccess {
+		txCode = TxFeeBumpInnerSuccess
+	} else {
+		txCode = TxFeeBumpInnerFailed
+	}
+	return s.result(txCode, opCodes, fee), nil
+}
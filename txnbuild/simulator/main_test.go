@@ -0,0 +1,344 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice  = keypair.MustParseFull("SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4")
+	bob    = keypair.MustParseFull("SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY")
+	issuer = keypair.MustParseFull("SBPQUZ6G4FZNWFHKUWC5BEYWF6R52E3SEP7R3GWYSM2XTKGF5LNTWW4R")
+	usd    = txnbuild.CreditAsset{Code: "USD", Issuer: issuer.Address()}
+)
+
+func newAccount(kp *keypair.Full, balance int64) Account {
+	return Account{
+		AccountID:    kp.Address(),
+		Sequence:     100,
+		Balance:      balance,
+		MasterWeight: 1,
+	}
+}
+
+func testLedger(accounts ...Account) *Ledger {
+	l := NewLedger(network.TestNetworkPassphrase, accounts...)
+	l.CloseTime = time.Unix(1600000000, 0)
+	l.LedgerSequence = 1000
+	return l
+}
+
+func buildTx(t *testing.T, source *keypair.Full, ops []txnbuild.Operation, signers ...*keypair.Full) *txnbuild.Transaction {
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 100},
+		IncrementSequenceNum: true,
+		Operations:           ops,
+		BaseFee:              txnbuild.MinBaseFee,
+		Timebounds:           txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	if len(signers) > 0 {
+		tx, err = tx.Sign(network.TestNetworkPassphrase, signers...)
+		require.NoError(t, err)
+	}
+	return tx
+}
+
+func TestPayment(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000), newAccount(bob, 50000000))
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "2", Asset: txnbuild.NativeAsset{}},
+	}, alice)
+
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.True(t, result.Successful)
+	assert.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+	assert.Equal(t, []string{OpSuccess}, result.ResultCodes.OperationCodes)
+	assert.Equal(t, int64(100), result.FeeCharged)
+
+	balance, _ := result.Ledger.Balance(alice.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(100000000-20000000-100), balance)
+	balance, _ = result.Ledger.Balance(bob.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(70000000), balance)
+	assert.Equal(t, int64(101), result.Ledger.Accounts[alice.Address()].Sequence)
+
+	// the snapshot is left untouched
+	balance, _ = ledger.Balance(alice.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(100000000), balance)
+}
+
+func TestPaymentUnderfunded(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000), newAccount(bob, 50000000))
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "9", Asset: txnbuild.NativeAsset{}},
+	}, alice)
+
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.False(t, result.Successful)
+	assert.Equal(t, TxFailed, result.ResultCodes.TransactionCode)
+	assert.Equal(t, []string{OpSuccess, OpUnderfunded}, result.ResultCodes.OperationCodes)
+	assert.Equal(t, int64(200), result.FeeCharged)
+
+	// only the fee and sequence number changes are kept
+	balance, _ := result.Ledger.Balance(alice.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(100000000-200), balance)
+	balance, _ = result.Ledger.Balance(bob.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(50000000), balance)
+	assert.Equal(t, int64(101), result.Ledger.Accounts[alice.Address()].Sequence)
+}
+
+func TestCreditPayment(t *testing.T) {
+	sender := newAccount(alice, 100000000)
+	sender.NumSubEntries = 1
+	sender.Trustlines = map[string]Trustline{
+		assetKey(usd): {Asset: usd, Balance: 100000000, Limit: 1000000000, Authorized: true},
+	}
+	ledger := testLedger(sender, newAccount(bob, 50000000), newAccount(issuer, 50000000))
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "1", Asset: usd},
+		&txnbuild.Payment{Destination: issuer.Address(), Amount: "1", Asset: usd},
+	}, alice)
+
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.Equal(t, TxFailed, result.ResultCodes.TransactionCode)
+	assert.Equal(t, []string{OpNoTrust, OpSuccess}, result.ResultCodes.OperationCodes)
+
+	// bob adds a trustline first
+	ledger = result.Ledger
+	ledger.Accounts[alice.Address()].Sequence = 100
+	tx = buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.ChangeTrust{Line: usd, Limit: "5", SourceAccount: &txnbuild.SimpleAccount{AccountID: bob.Address()}},
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "4", Asset: usd},
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "2", Asset: usd},
+	}, alice, bob)
+
+	result, err = ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{OpSuccess, OpSuccess, OpLineFull}, result.ResultCodes.OperationCodes)
+}
+
+func TestTransactionRejected(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000), newAccount(bob, 50000000))
+	ops := []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}},
+	}
+
+	ledger.Accounts[alice.Address()].Sequence = 120
+	result, err := ledger.Simulate(buildTx(t, alice, ops, alice))
+	require.NoError(t, err)
+	assert.Equal(t, TxBadSeq, result.ResultCodes.TransactionCode)
+	assert.Equal(t, int64(0), result.FeeCharged)
+	ledger.Accounts[alice.Address()].Sequence = 100
+
+	result, err = ledger.Simulate(buildTx(t, alice, ops))
+	require.NoError(t, err)
+	assert.Equal(t, TxBadAuth, result.ResultCodes.TransactionCode)
+
+	result, err = ledger.Simulate(buildTx(t, alice, ops, alice, bob))
+	require.NoError(t, err)
+	assert.Equal(t, TxBadAuthExtra, result.ResultCodes.TransactionCode)
+
+	ledger.SkipSignatures = true
+	result, err = ledger.Simulate(buildTx(t, alice, ops))
+	require.NoError(t, err)
+	assert.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+
+	result, err = testLedger(newAccount(bob, 50000000)).Simulate(buildTx(t, alice, ops, alice))
+	require.NoError(t, err)
+	assert.Equal(t, TxNoSourceAccount, result.ResultCodes.TransactionCode)
+}
+
+func TestThresholds(t *testing.T) {
+	account := newAccount(alice, 100000000)
+	account.NumSubEntries = 1
+	account.MediumThreshold = 2
+	account.HighThreshold = 3
+	account.Signers = []Signer{{Key: bob.Address(), Weight: 1}}
+	ledger := testLedger(account, newAccount(bob, 50000000))
+
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.SetOptions{HighThreshold: txnbuild.NewThreshold(2)},
+	}, alice, bob)
+
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.Equal(t, TxFailed, result.ResultCodes.TransactionCode)
+	assert.Equal(t, []string{OpSuccess, OpBadAuth}, result.ResultCodes.OperationCodes)
+}
+
+func TestSponsoredAccountCreation(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000))
+	ops := []txnbuild.Operation{
+		&txnbuild.BeginSponsoringFutureReserves{SponsoredID: bob.Address()},
+		&txnbuild.CreateAccount{Destination: bob.Address(), Amount: "0"},
+		&txnbuild.EndSponsoringFutureReserves{SourceAccount: &txnbuild.SimpleAccount{AccountID: bob.Address()}},
+	}
+
+	result, err := ledger.Simulate(buildTx(t, alice, ops, alice, bob))
+	require.NoError(t, err)
+	assert.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+	created := result.Ledger.Accounts[bob.Address()]
+	require.NotNil(t, created)
+	assert.Equal(t, alice.Address(), created.Sponsor)
+	assert.Equal(t, uint32(2), created.NumSponsored)
+	assert.Equal(t, int64(1000)<<32, created.Sequence)
+	assert.Equal(t, uint32(2), result.Ledger.Accounts[alice.Address()].NumSponsoring)
+
+	result, err = ledger.Simulate(buildTx(t, alice, ops[:2], alice))
+	require.NoError(t, err)
+	assert.Equal(t, TxBadSponsorship, result.ResultCodes.TransactionCode)
+	assert.Nil(t, result.Ledger.Accounts[bob.Address()])
+
+	result, err = ledger.Simulate(buildTx(t, alice, ops[1:2], alice))
+	require.NoError(t, err)
+	assert.Equal(t, []string{OpLowReserve}, result.ResultCodes.OperationCodes)
+}
+
+func TestChangeTrustAndManageData(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 19000000), newAccount(issuer, 50000000))
+	ledger.Accounts[issuer.Address()].Flags = txnbuild.AuthRequired
+
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.ChangeTrust{Line: usd, Limit: "100"},
+		&txnbuild.ManageData{Name: "key", Value: []byte("value")},
+	}, alice)
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{OpSuccess, OpLowReserve}, result.ResultCodes.OperationCodes)
+
+	ledger.Accounts[alice.Address()].Balance = 100000000
+	result, err = ledger.Simulate(tx)
+	require.NoError(t, err)
+	assert.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+	account := result.Ledger.Accounts[alice.Address()]
+	assert.Equal(t, uint32(2), account.NumSubEntries)
+	assert.False(t, account.Trustlines[assetKey(usd)].Authorized)
+	assert.Equal(t, []byte("value"), account.Data["key"].Value)
+}
+
+func TestClaimableBalances(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000), newAccount(bob, 50000000))
+	tx := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.CreateClaimableBalance{
+			Amount: "3",
+			Asset:  txnbuild.NativeAsset{},
+			Destinations: []txnbuild.Claimant{
+				txnbuild.NewClaimant(bob.Address(), nil),
+			},
+		},
+	}, alice)
+
+	result, err := ledger.Simulate(tx)
+	require.NoError(t, err)
+	require.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+	balanceID, err := tx.ClaimableBalanceID(0)
+	require.NoError(t, err)
+	require.Contains(t, result.Ledger.ClaimableBalances, balanceID)
+	assert.Equal(t, uint32(1), result.Ledger.Accounts[alice.Address()].NumSponsoring)
+
+	ledger = result.Ledger
+	ledger.Accounts[alice.Address()].Sequence = 100
+	ledger.Accounts[bob.Address()].Sequence = 100
+	claim := []txnbuild.Operation{&txnbuild.ClaimClaimableBalance{BalanceID: balanceID}}
+
+	result, err = ledger.Simulate(buildTx(t, alice, claim, alice))
+	require.NoError(t, err)
+	assert.Equal(t, []string{OpCannotClaim}, result.ResultCodes.OperationCodes)
+
+	result, err = ledger.Simulate(buildTx(t, bob, claim, bob))
+	require.NoError(t, err)
+	assert.Equal(t, TxSuccess, result.ResultCodes.TransactionCode)
+	assert.Empty(t, result.Ledger.ClaimableBalances)
+	assert.Equal(t, uint32(0), result.Ledger.Accounts[alice.Address()].NumSponsoring)
+	balance, _ := result.Ledger.Balance(bob.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(50000000+30000000-100), balance)
+}
+
+func TestUnsupportedOperation(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000))
+	tx := buildTx(t, alice, []txnbuild.Operation{&txnbuild.Inflation{}}, alice)
+
+	_, err := ledger.Simulate(tx)
+	assert.EqualError(t, err, "*txnbuild.Inflation: operation is not supported by the simulator")
+}
+
+func TestAccountFromHorizon(t *testing.T) {
+	authorized := true
+	record := hProtocol.Account{
+		AccountID:     alice.Address(),
+		Sequence:      "12345",
+		SubentryCount: 2,
+		Thresholds:    hProtocol.AccountThresholds{LowThreshold: 1, MedThreshold: 2, HighThreshold: 3},
+		Flags:         hProtocol.AccountFlags{AuthRequired: true},
+		Balances: []hProtocol.Balance{
+			{Balance: "10.0000000", BuyingLiabilities: "1.0000000", SellingLiabilities: "0.0000000", Asset: base.Asset{Type: "native"}},
+			{
+				Balance:            "5.0000000",
+				Limit:              "100.0000000",
+				BuyingLiabilities:  "0.0000000",
+				SellingLiabilities: "0.0000000",
+				IsAuthorized:       &authorized,
+				Asset:              base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()},
+			},
+		},
+		Signers: []hProtocol.Signer{
+			{Key: bob.Address(), Weight: 2},
+			{Key: alice.Address(), Weight: 1},
+		},
+		Data: map[string]string{"key": "dmFsdWU="},
+	}
+
+	account, err := AccountFromHorizon(record)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12345), account.Sequence)
+	assert.Equal(t, int64(100000000), account.Balance)
+	assert.Equal(t, int64(10000000), account.BuyingLiabilities)
+	assert.Equal(t, txnbuild.AuthRequired, account.Flags)
+	assert.Equal(t, txnbuild.Threshold(1), account.MasterWeight)
+	assert.Equal(t, []Signer{{Key: bob.Address(), Weight: 2}}, account.Signers)
+	assert.Equal(t, Trustline{
+		Asset:      usd,
+		Balance:    50000000,
+		Limit:      1000000000,
+		Authorized: true,
+	}, account.Trustlines[assetKey(usd)])
+	assert.Equal(t, []byte("value"), account.Data["key"].Value)
+}
+
+func TestFeeBump(t *testing.T) {
+	ledger := testLedger(newAccount(alice, 100000000), newAccount(bob, 50000000))
+	inner := buildTx(t, alice, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: bob.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}},
+	}, alice)
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: bob.Address(),
+		BaseFee:    txnbuild.MinBaseFee,
+	})
+	require.NoError(t, err)
+	feeBump, err = feeBump.Sign(network.TestNetworkPassphrase, bob)
+	require.NoError(t, err)
+
+	result, err := ledger.SimulateFeeBump(feeBump)
+	require.NoError(t, err)
+	assert.True(t, result.Successful)
+	assert.Equal(t, TxFeeBumpInnerSuccess, result.ResultCodes.TransactionCode)
+	assert.Equal(t, int64(200), result.FeeCharged)
+	balance, _ := result.Ledger.Balance(bob.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(60000000-200), balance)
+	balance, _ = result.Ledger.Balance(alice.Address(), txnbuild.NativeAsset{})
+	assert.Equal(t, int64(90000000), balance)
+}
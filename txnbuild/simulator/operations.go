@@ -0,0 +1,523 @@
+package simulator
+
+import (
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	maxSigners          = 20
+	maxClaimants        = 10
+	maxDataNameLength   = 64
+	maxHomeDomainLength = 32
+	knownAccountFlags   = txnbuild.AuthRequired | txnbuild.AuthRevocable | txnbuild.AuthImmutable
+)
+
+func (s *simulation) payment(op *txnbuild.Payment, sourceID string) (string, error) {
+	amt, err := amount.ParseInt64(op.Amount)
+	if err != nil || amt <= 0 {
+		return OpMalformed, nil
+	}
+	destinationID, err := accountIDFromAddress(op.Destination)
+	if err != nil {
+		return OpMalformed, nil
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	destination, ok := s.ledger.Accounts[destinationID]
+	if !ok {
+		return OpNoDestination, nil
+	}
+
+	if op.Asset.IsNative() {
+		if s.availableBalance(source) < amt {
+			return OpUnderfunded, nil
+		}
+		if sourceID == destinationID {
+			return OpSuccess, nil
+		}
+		if !addBalance(&destination.Balance, amt, maxBalance(destination)) {
+			return OpLineFull, nil
+		}
+		source.Balance -= amt
+		return OpSuccess, nil
+	}
+
+	key := assetKey(op.Asset)
+	issuer := op.Asset.GetIssuer()
+	if sourceID != issuer {
+		line, ok := source.Trustlines[key]
+		if !ok {
+			return OpSrcNoTrust, nil
+		}
+		if !line.Authorized {
+			return OpSrcNotAuthorized, nil
+		}
+		if line.Balance-line.SellingLiabilities < amt {
+			return OpUnderfunded, nil
+		}
+		if sourceID == destinationID {
+			return OpSuccess, nil
+		}
+		line.Balance -= amt
+		source.Trustlines[key] = line
+	}
+
+	if destinationID != issuer {
+		line, ok := destination.Trustlines[key]
+		if !ok {
+			return OpNoTrust, nil
+		}
+		if !line.Authorized {
+			return OpNotAuthorized, nil
+		}
+		if !addBalance(&line.Balance, amt, line.Limit-line.BuyingLiabilities) {
+			return OpLineFull, nil
+		}
+		destination.Trustlines[key] = line
+	}
+	return OpSuccess, nil
+}
+
+func (s *simulation) createAccount(op *txnbuild.CreateAccount, sourceID string) (string, error) {
+	amt, err := amount.ParseInt64(op.Amount)
+	if err != nil || amt < 0 {
+		return OpMalformed, nil
+	}
+	destinationID, err := accountIDFromAddress(op.Destination)
+	if err != nil || destinationID == sourceID {
+		return OpMalformed, nil
+	}
+	if _, ok := s.ledger.Accounts[destinationID]; ok {
+		return OpAlreadyExists, nil
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	account := Account{
+		AccountID:    destinationID,
+		Sequence:     int64(s.ledger.LedgerSequence) << 32,
+		Balance:      amt,
+		MasterWeight: 1,
+		Trustlines:   map[string]Trustline{},
+		Data:         map[string]DataEntry{},
+	}
+
+	if sponsorID, ok := s.sponsors[destinationID]; ok {
+		sponsor, ok := s.ledger.Accounts[sponsorID]
+		if !ok || s.availableBalance(sponsor) < 2*s.ledger.baseReserve() {
+			return OpLowReserve, nil
+		}
+		sponsor.NumSponsoring += 2
+		account.NumSponsored = 2
+		account.Sponsor = sponsorID
+	} else if amt < s.ledger.MinimumBalance(&account) {
+		return OpLowReserve, nil
+	}
+
+	if s.availableBalance(source) < amt {
+		return OpUnderfunded, nil
+	}
+	source.Balance -= amt
+	s.ledger.Accounts[destinationID] = &account
+	return OpSuccess, nil
+}
+
+func (s *simulation) changeTrust(op *txnbuild.ChangeTrust, sourceID string) (string, error) {
+	if op.Line.IsNative() {
+		return OpMalformed, nil
+	}
+	limit, err := amount.ParseInt64(op.Limit)
+	if err != nil || limit < 0 {
+		return OpMalformed, nil
+	}
+	if op.Line.GetIssuer() == sourceID {
+		return OpSelfNotAllowed, nil
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	key := assetKey(op.Line)
+	line, ok := source.Trustlines[key]
+	if ok {
+		if limit == 0 {
+			if line.Balance > 0 || line.BuyingLiabilities > 0 {
+				return OpInvalidLimit, nil
+			}
+			s.releaseSubentry(source, line.Sponsor)
+			delete(source.Trustlines, key)
+			return OpSuccess, nil
+		}
+		if limit < line.Balance+line.BuyingLiabilities {
+			return OpInvalidLimit, nil
+		}
+		line.Limit = limit
+		source.Trustlines[key] = line
+		return OpSuccess, nil
+	}
+
+	if limit == 0 {
+		return OpInvalidLimit, nil
+	}
+	issuer, ok := s.ledger.Accounts[op.Line.GetIssuer()]
+	if !ok {
+		return OpNoIssuer, nil
+	}
+	sponsor, ok := s.reserveSubentry(source)
+	if !ok {
+		return OpLowReserve, nil
+	}
+	source.Trustlines[key] = Trustline{
+		Asset:      txnbuild.CreditAsset{Code: op.Line.GetCode(), Issuer: op.Line.GetIssuer()},
+		Limit:      limit,
+		Authorized: issuer.Flags&txnbuild.AuthRequired == 0,
+		Sponsor:    sponsor,
+	}
+	return OpSuccess, nil
+}
+
+func (s *simulation) manageData(op *txnbuild.ManageData, sourceID string) string {
+	if len(op.Name) == 0 || len(op.Name) > maxDataNameLength {
+		return OpDataInvalidName
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	entry, ok := source.Data[op.Name]
+	if op.Value == nil {
+		if !ok {
+			return OpDataNameNotFound
+		}
+		s.releaseSubentry(source, entry.Sponsor)
+		delete(source.Data, op.Name)
+		return OpSuccess
+	}
+
+	if !ok {
+		sponsor, reserved := s.reserveSubentry(source)
+		if !reserved {
+			return OpLowReserve
+		}
+		entry.Sponsor = sponsor
+	}
+	entry.Value = append([]byte(nil), op.Value...)
+	source.Data[op.Name] = entry
+	return OpSuccess
+}
+
+func (s *simulation) setOptions(op *txnbuild.SetOptions, sourceID string) (string, error) {
+	source := s.ledger.Accounts[sourceID]
+
+	if op.InflationDestination != nil {
+		destinationID, err := accountIDFromAddress(*op.InflationDestination)
+		if err != nil {
+			return OpMalformed, nil
+		}
+		if _, ok := s.ledger.Accounts[destinationID]; !ok {
+			return OpInvalidInflation, nil
+		}
+		source.InflationDestination = destinationID
+	}
+
+	var setFlags, clearFlags txnbuild.AccountFlag
+	for _, flag := range op.SetFlags {
+		setFlags |= flag
+	}
+	for _, flag := range op.ClearFlags {
+		clearFlags |= flag
+	}
+	if (setFlags|clearFlags)&^knownAccountFlags != 0 {
+		return OpUnknownFlag, nil
+	}
+	if setFlags&clearFlags != 0 {
+		return OpBadFlags, nil
+	}
+	if setFlags|clearFlags != 0 {
+		if source.Flags&txnbuild.AuthImmutable != 0 {
+			return OpCantChange, nil
+		}
+		source.Flags = (source.Flags | setFlags) &^ clearFlags
+	}
+
+	if op.MasterWeight != nil {
+		source.MasterWeight = *op.MasterWeight
+	}
+	if op.LowThreshold != nil {
+		source.LowThreshold = *op.LowThreshold
+	}
+	if op.MediumThreshold != nil {
+		source.MediumThreshold = *op.MediumThreshold
+	}
+	if op.HighThreshold != nil {
+		source.HighThreshold = *op.HighThreshold
+	}
+
+	if op.HomeDomain != nil {
+		if len(*op.HomeDomain) > maxHomeDomainLength {
+			return OpInvalidHomeDomain, nil
+		}
+		source.HomeDomain = *op.HomeDomain
+	}
+
+	if op.Signer != nil {
+		return s.setSigner(source, *op.Signer), nil
+	}
+	return OpSuccess, nil
+}
+
+func (s *simulation) setSigner(source *Account, signer txnbuild.Signer) string {
+	if signer.Address == source.AccountID {
+		return OpBadSigner
+	}
+	if _, _, err := strkey.DecodeAny(signer.Address); err != nil {
+		return OpBadSigner
+	}
+
+	for i, existing := range source.Signers {
+		if existing.Key != signer.Address {
+			continue
+		}
+		if signer.Weight == 0 {
+			s.releaseSubentry(source, existing.Sponsor)
+			source.Signers = append(source.Signers[:i], source.Signers[i+1:]...)
+		} else {
+			source.Signers[i].Weight = int32(signer.Weight)
+		}
+		return OpSuccess
+	}
+
+	if signer.Weight == 0 {
+		return OpSuccess
+	}
+	if len(source.Signers) >= maxSigners {
+		return OpTooManySigners
+	}
+	sponsor, ok := s.reserveSubentry(source)
+	if !ok {
+		return OpLowReserve
+	}
+	source.Signers = append(source.Signers, Signer{
+		Key:     signer.Address,
+		Weight:  int32(signer.Weight),
+		Sponsor: sponsor,
+	})
+	return OpSuccess
+}
+
+func (s *simulation) bumpSequence(op *txnbuild.BumpSequence, sourceID string) string {
+	if op.BumpTo < 0 {
+		return OpBadSeq
+	}
+	source := s.ledger.Accounts[sourceID]
+	if op.BumpTo > source.Sequence {
+		source.Sequence = op.BumpTo
+	}
+	return OpSuccess
+}
+
+func (s *simulation) createClaimableBalance(
+	tx *txnbuild.Transaction,
+	index int,
+	op *txnbuild.CreateClaimableBalance,
+	sourceID string,
+) (string, error) {
+	amt, err := amount.ParseInt64(op.Amount)
+	if err != nil || amt <= 0 {
+		return OpMalformed, nil
+	}
+	if len(op.Destinations) == 0 || len(op.Destinations) > maxClaimants {
+		return OpMalformed, nil
+	}
+	seen := map[string]bool{}
+	for _, claimant := range op.Destinations {
+		if seen[claimant.Destination] {
+			return OpMalformed, nil
+		}
+		seen[claimant.Destination] = true
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	if op.Asset.IsNative() {
+		if s.availableBalance(source) < amt {
+			return OpUnderfunded, nil
+		}
+		source.Balance -= amt
+	} else if op.Asset.GetIssuer() != sourceID {
+		key := assetKey(op.Asset)
+		line, ok := source.Trustlines[key]
+		if !ok {
+			return OpNoTrust, nil
+		}
+		if !line.Authorized {
+			return OpNotAuthorized, nil
+		}
+		if line.Balance-line.SellingLiabilities < amt {
+			return OpUnderfunded, nil
+		}
+		line.Balance -= amt
+		source.Trustlines[key] = line
+	}
+
+	// Claimable balances are always sponsored, by the source account unless
+	// another account is sponsoring its reserves.
+	sponsorID, ok := s.sponsors[sourceID]
+	if !ok {
+		sponsorID = sourceID
+	}
+	sponsor, ok := s.ledger.Accounts[sponsorID]
+	reserve := int64(len(op.Destinations)) * s.ledger.baseReserve()
+	if !ok || s.availableBalance(sponsor) < reserve {
+		return OpLowReserve, nil
+	}
+	sponsor.NumSponsoring += uint32(len(op.Destinations))
+
+	balanceID, err := tx.ClaimableBalanceID(index)
+	if err != nil {
+		return "", err
+	}
+	closeTime := s.ledger.CloseTime.Unix()
+	claimants := make([]txnbuild.Claimant, len(op.Destinations))
+	for i, claimant := range op.Destinations {
+		claimants[i] = txnbuild.Claimant{
+			Destination: claimant.Destination,
+			Predicate:   absolutePredicate(claimant.Predicate, closeTime),
+		}
+	}
+	s.ledger.ClaimableBalances[balanceID] = &ClaimableBalance{
+		BalanceID: balanceID,
+		Asset:     op.Asset,
+		Amount:    amt,
+		Claimants: claimants,
+		Sponsor:   sponsorID,
+	}
+	return OpSuccess, nil
+}
+
+func (s *simulation) claimClaimableBalance(op *txnbuild.ClaimClaimableBalance, sourceID string) (string, error) {
+	balance, ok := s.ledger.ClaimableBalances[op.BalanceID]
+	if !ok {
+		return OpDoesNotExist, nil
+	}
+
+	closeTime := s.ledger.CloseTime.Unix()
+	canClaim := false
+	for _, claimant := range balance.Claimants {
+		if claimant.Destination == sourceID && predicateSatisfied(claimant.Predicate, closeTime) {
+			canClaim = true
+			break
+		}
+	}
+	if !canClaim {
+		return OpCannotClaim, nil
+	}
+
+	source := s.ledger.Accounts[sourceID]
+	if balance.Asset.IsNative() {
+		if !addBalance(&source.Balance, balance.Amount, maxBalance(source)) {
+			return OpLineFull, nil
+		}
+	} else if balance.Asset.GetIssuer() != sourceID {
+		key := assetKey(balance.Asset)
+		line, ok := source.Trustlines[key]
+		if !ok {
+			return OpNoTrust, nil
+		}
+		if !line.Authorized {
+			return OpNotAuthorized, nil
+		}
+		if !addBalance(&line.Balance, balance.Amount, line.Limit-line.BuyingLiabilities) {
+			return OpLineFull, nil
+		}
+		source.Trustlines[key] = line
+	}
+
+	if sponsor, ok := s.ledger.Accounts[balance.Sponsor]; ok {
+		sponsor.NumSponsoring -= uint32(len(balance.Claimants))
+	}
+	delete(s.ledger.ClaimableBalances, op.BalanceID)
+	return OpSuccess, nil
+}
+
+func (s *simulation) beginSponsoring(op *txnbuild.BeginSponsoringFutureReserves, sourceID string) (string, error) {
+	sponsoredID, err := accountIDFromAddress(op.SponsoredID)
+	if err != nil || sponsoredID == sourceID {
+		return OpMalformed, nil
+	}
+	if _, ok := s.sponsors[sponsoredID]; ok {
+		return OpAlreadySponsored, nil
+	}
+	if _, ok := s.sponsors[sourceID]; ok {
+		return OpRecursive, nil
+	}
+	for _, sponsor := range s.sponsors {
+		if sponsor == sponsoredID {
+			return OpRecursive, nil
+		}
+	}
+	s.sponsors[sponsoredID] = sourceID
+	return OpSuccess, nil
+}
+
+func (s *simulation) endSponsoring(sourceID string) string {
+	if _, ok := s.sponsors[sourceID]; !ok {
+		return OpNotSponsored
+	}
+	delete(s.sponsors, sourceID)
+	return OpSuccess
+}
+
+// absolutePredicate converts the relative time predicates of a claimant to
+// absolute ones, like Stellar Core does when a claimable balance is created.
+func absolutePredicate(predicate xdr.ClaimPredicate, closeTime int64) xdr.ClaimPredicate {
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		absBefore := xdr.Int64(closeTime) + *predicate.RelBefore
+		return xdr.ClaimPredicate{
+			Type:      xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime,
+			AbsBefore: &absBefore,
+		}
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		and := make([]xdr.ClaimPredicate, len(*predicate.AndPredicates))
+		for i, p := range *predicate.AndPredicates {
+			and[i] = absolutePredicate(p, closeTime)
+		}
+		return xdr.ClaimPredicate{Type: predicate.Type, AndPredicates: &and}
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		or := make([]xdr.ClaimPredicate, len(*predicate.OrPredicates))
+		for i, p := range *predicate.OrPredicates {
+			or[i] = absolutePredicate(p, closeTime)
+		}
+		return xdr.ClaimPredicate{Type: predicate.Type, OrPredicates: &or}
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		not := absolutePredicate(**predicate.NotPredicate, closeTime)
+		notPtr := &not
+		return xdr.ClaimPredicate{Type: predicate.Type, NotPredicate: &notPtr}
+	}
+	return predicate
+}
+
+// predicateSatisfied evaluates an absolute claim predicate at closeTime.
+func predicateSatisfied(predicate xdr.ClaimPredicate, closeTime int64) bool {
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateUnconditional:
+		return true
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		return closeTime < int64(*predicate.AbsBefore)
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		for _, p := range *predicate.AndPredicates {
+			if !predicateSatisfied(p, closeTime) {
+				return false
+			}
+		}
+		return true
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		for _, p := range *predicate.OrPredicates {
+			if predicateSatisfied(p, closeTime) {
+				return true
+			}
+		}
+		return false
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		return !predicateSatisfied(**predicate.NotPredicate, closeTime)
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package simulator
+
+import (
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/txnbuild/multisig"
+	"github.com/stellar/go/xdr"
+)
+
+// signatureChecker checks the signatures of a transaction against the signers
+// of accounts, keeping track of the signatures which were used. A nil
+// signatureChecker accepts everything.
+type signatureChecker struct {
+	hash       [32]byte
+	signatures []xdr.DecoratedSignature
+	used       []bool
+}
+
+func newSignatureChecker(hash [32]byte, signatures []xdr.DecoratedSignature) *signatureChecker {
+	return &signatureChecker{
+		hash:       hash,
+		signatures: signatures,
+		used:       make([]bool, len(signatures)),
+	}
+}
+
+// check returns true if the signatures carry enough weight to meet the given
+// threshold of the account. Like in Stellar Core at least one signature is
+// required even when the threshold is 0.
+func (c *signatureChecker) check(account *Account, level multisig.ThresholdCategory) bool {
+	if c == nil {
+		return true
+	}
+
+	var needed txnbuild.Threshold
+	switch level {
+	case multisig.ThresholdLow:
+		needed = account.LowThreshold
+	case multisig.ThresholdMedium:
+		needed = account.MediumThreshold
+	case multisig.ThresholdHigh:
+		needed = account.HighThreshold
+	}
+
+	signers := append([]Signer{{Key: account.AccountID, Weight: int32(account.MasterWeight)}}, account.Signers...)
+	total := int32(0)
+	for _, signer := range signers {
+		if signer.Weight <= 0 || !c.signedBy(signer.Key) {
+			continue
+		}
+		weight := signer.Weight
+		if weight > 255 {
+			weight = 255
+		}
+		total += weight
+		if total >= int32(needed) {
+			return true
+		}
+	}
+	return false
+}
+
+// signedBy returns true if the transaction is signed by the given signer key,
+// marking the matching signatures as used.
+func (c *signatureChecker) signedBy(key string) bool {
+	if multisig.PreAuthorizes(c.hash, key) {
+		return true
+	}
+	found := false
+	for i, sig := range c.signatures {
+		if multisig.SignatureMatches(c.hash, key, sig) {
+			c.used[i] = true
+			found = true
+		}
+	}
+	return found
+}
+
+// allUsed returns true if every signature was used to meet a threshold.
+func (c *signatureChecker) allUsed() bool {
+	if c == nil {
+		return true
+	}
+	for _, used := range c.used {
+		if !used {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,306 @@
+package simulator
+
+import (
+	"math"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/txnbuild/multisig"
+	"github.com/stellar/go/xdr"
+)
+
+// simulation is the state of a single transaction being simulated.
+type simulation struct {
+	original *Ledger
+	ledger   *Ledger
+	// sponsors maps the sponsored accounts to their sponsor while a
+	// BeginSponsoringFutureReserves operation is active.
+	sponsors map[string]string
+}
+
+func newSimulation(l *Ledger) *simulation {
+	ledger := l.clone()
+	return &simulation{
+		original: ledger.clone(),
+		ledger:   ledger,
+		sponsors: map[string]string{},
+	}
+}
+
+func checkSupported(tx *txnbuild.Transaction) error {
+	for _, op := range tx.Operations() {
+		switch op.(type) {
+		case *txnbuild.Payment,
+			*txnbuild.CreateAccount,
+			*txnbuild.ChangeTrust,
+			*txnbuild.ManageData,
+			*txnbuild.SetOptions,
+			*txnbuild.BumpSequence,
+			*txnbuild.CreateClaimableBalance,
+			*txnbuild.ClaimClaimableBalance,
+			*txnbuild.BeginSponsoringFutureReserves,
+			*txnbuild.EndSponsoringFutureReserves:
+		default:
+			return errors.Wrapf(ErrUnsupportedOperation, "%T", op)
+		}
+	}
+	return nil
+}
+
+// checkTransaction performs the validity checks which reject a transaction
+// before it is included in a ledger, except for the fee and signature checks.
+func (s *simulation) checkTransaction(tx *txnbuild.Transaction) string {
+	if len(tx.Operations()) == 0 {
+		return TxMissingOperation
+	}
+
+	closeTime := s.ledger.CloseTime.Unix()
+	timebounds := tx.Timebounds()
+	if timebounds.MinTime > 0 && closeTime < timebounds.MinTime {
+		return TxTooEarly
+	}
+	if timebounds.MaxTime > 0 && closeTime > timebounds.MaxTime {
+		return TxTooLate
+	}
+
+	source, ok := s.ledger.Accounts[tx.SourceAccount().AccountID]
+	if !ok {
+		return TxNoSourceAccount
+	}
+	if tx.SourceAccount().Sequence != source.Sequence+1 {
+		return TxBadSeq
+	}
+	return ""
+}
+
+// checkFee checks that accountID can pay fee and that the maximum fee of the
+// transaction covers it.
+func (s *simulation) checkFee(accountID string, maxFee, fee int64) string {
+	account, ok := s.ledger.Accounts[accountID]
+	if !ok {
+		return TxNoSourceAccount
+	}
+	if maxFee < fee {
+		return TxInsufficientFee
+	}
+	if s.availableBalance(account) < fee {
+		return TxInsufficientBalance
+	}
+	return ""
+}
+
+func (s *simulation) signatureChecker(tx interface {
+	Hash(string) ([32]byte, error)
+	Signatures() []xdr.DecoratedSignature
+}) (*signatureChecker, error) {
+	if s.ledger.SkipSignatures {
+		return nil, nil
+	}
+	hash, err := tx.Hash(s.ledger.NetworkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash transaction")
+	}
+	return newSignatureChecker(hash, tx.Signatures()), nil
+}
+
+func (s *simulation) chargeFee(accountID string, fee int64) {
+	s.ledger.Accounts[accountID].Balance -= fee
+}
+
+// apply applies the operations of tx. Operations which fail leave the ledger
+// untouched, and if any operation fails the ledger is reverted to its state
+// after the fee was charged.
+func (s *simulation) apply(tx *txnbuild.Transaction, signatures *signatureChecker) (string, []string, error) {
+	source := tx.SourceAccount().AccountID
+	s.ledger.Accounts[source].Sequence = tx.SourceAccount().Sequence
+	afterFee := s.ledger.clone()
+
+	failed := false
+	codes := make([]string, len(tx.Operations()))
+	for i, op := range tx.Operations() {
+		opSource := source
+		if op.GetSourceAccount() != nil {
+			var err error
+			if opSource, err = accountIDFromAddress(op.GetSourceAccount().GetAccountID()); err != nil {
+				return "", nil, errors.Wrapf(err, "invalid source account for operation %d", i)
+			}
+		}
+
+		account, ok := s.ledger.Accounts[opSource]
+		if !ok {
+			codes[i] = OpNoSourceAccount
+			failed = true
+			continue
+		}
+		if !signatures.check(account, multisig.OperationCategory(op)) {
+			codes[i] = OpBadAuth
+			failed = true
+			continue
+		}
+
+		code, err := s.applyOperation(tx, i, opSource)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "could not apply operation %d", i)
+		}
+		codes[i] = code
+		if code != OpSuccess {
+			failed = true
+		}
+	}
+
+	if !signatures.allUsed() {
+		s.ledger = afterFee
+		return TxBadAuthExtra, nil, nil
+	}
+	if failed {
+		s.ledger = afterFee
+		return TxFailed, codes, nil
+	}
+	if len(s.sponsors) > 0 {
+		s.ledger = afterFee
+		return TxBadSponsorship, nil, nil
+	}
+	return TxSuccess, codes, nil
+}
+
+// applyOperation applies the operation at index i on a copy of the ledger
+// which replaces the current one only if the operation succeeds.
+func (s *simulation) applyOperation(tx *txnbuild.Transaction, i int, source string) (string, error) {
+	ledger := s.ledger
+	sponsors := make(map[string]string, len(s.sponsors))
+	for sponsored, sponsor := range s.sponsors {
+		sponsors[sponsored] = sponsor
+	}
+	s.ledger = ledger.clone()
+
+	var code string
+	var err error
+	switch op := tx.Operations()[i].(type) {
+	case *txnbuild.Payment:
+		code, err = s.payment(op, source)
+	case *txnbuild.CreateAccount:
+		code, err = s.createAccount(op, source)
+	case *txnbuild.ChangeTrust:
+		code, err = s.changeTrust(op, source)
+	case *txnbuild.ManageData:
+		code = s.manageData(op, source)
+	case *txnbuild.SetOptions:
+		code, err = s.setOptions(op, source)
+	case *txnbuild.BumpSequence:
+		code = s.bumpSequence(op, source)
+	case *txnbuild.CreateClaimableBalance:
+		code, err = s.createClaimableBalance(tx, i, op, source)
+	case *txnbuild.ClaimClaimableBalance:
+		code, err = s.claimClaimableBalance(op, source)
+	case *txnbuild.BeginSponsoringFutureReserves:
+		code, err = s.beginSponsoring(op, source)
+	case *txnbuild.EndSponsoringFutureReserves:
+		code = s.endSponsoring(source)
+	default:
+		err = ErrUnsupportedOperation
+	}
+
+	if err != nil || code != OpSuccess {
+		s.ledger = ledger
+		s.sponsors = sponsors
+	}
+	return code, err
+}
+
+func (s *simulation) reject(code string) Result {
+	return Result{
+		ResultCodes: hProtocol.TransactionResultCodes{TransactionCode: code},
+		Ledger:      s.original,
+	}
+}
+
+func (s *simulation) result(txCode string, opCodes []string, fee int64) Result {
+	return Result{
+		Successful: txCode == TxSuccess || txCode == TxFeeBumpInnerSuccess,
+		ResultCodes: hProtocol.TransactionResultCodes{
+			TransactionCode: txCode,
+			OperationCodes:  opCodes,
+		},
+		FeeCharged: fee,
+		Ledger:     s.ledger,
+	}
+}
+
+// availableBalance returns the native balance an account can spend without
+// going below its minimum balance.
+func (s *simulation) availableBalance(account *Account) int64 {
+	return account.Balance - s.ledger.MinimumBalance(account) - account.SellingLiabilities
+}
+
+// reserveSubentry accounts for a new subentry of account, charging the
+// reserve to its active sponsor if there is one. It returns the sponsor of
+// the subentry, and false if the account paying the reserve cannot afford it.
+func (s *simulation) reserveSubentry(account *Account) (string, bool) {
+	if account.NumSubEntries >= maxSubentries {
+		return "", false
+	}
+
+	sponsorID, sponsored := s.sponsors[account.AccountID]
+	if !sponsored {
+		if s.availableBalance(account) < s.ledger.baseReserve() {
+			return "", false
+		}
+		account.NumSubEntries++
+		return "", true
+	}
+
+	sponsor, ok := s.ledger.Accounts[sponsorID]
+	if !ok || s.availableBalance(sponsor) < s.ledger.baseReserve() {
+		return "", false
+	}
+	sponsor.NumSponsoring++
+	account.NumSponsored++
+	account.NumSubEntries++
+	return sponsorID, true
+}
+
+// releaseSubentry accounts for a removed subentry of account which was
+// sponsored by sponsorID, if not empty.
+func (s *simulation) releaseSubentry(account *Account, sponsorID string) {
+	account.NumSubEntries--
+	if sponsorID == "" {
+		return
+	}
+	account.NumSponsored--
+	if sponsor, ok := s.ledger.Accounts[sponsorID]; ok {
+		sponsor.NumSponsoring--
+	}
+}
+
+const maxSubentries = 1000
+
+// addBalance adds delta to balance unless the result would be negative or
+// exceed limit.
+func addBalance(balance *int64, delta, limit int64) bool {
+	if delta > 0 && *balance > limit-delta {
+		return false
+	}
+	if *balance+delta < 0 {
+		return false
+	}
+	*balance += delta
+	return true
+}
+
+func assetKey(asset txnbuild.Asset) string {
+	return asset.GetCode() + ":" + asset.GetIssuer()
+}
+
+func accountIDFromAddress(address string) (string, error) {
+	var muxed xdr.MuxedAccount
+	if err := muxed.SetAddress(address); err != nil {
+		return "", err
+	}
+	aid := muxed.ToAccountId()
+	return aid.Address(), nil
+}
+
+func maxBalance(account *Account) int64 {
+	return math.MaxInt64 - account.BuyingLiabilities
+}
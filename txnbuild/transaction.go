@@ -848,18 +848,45 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 	return tx, nil
 }
 
+// ClientDomainOperationName is the name of the Manage Data operation of a SEP 10 challenge that attributes the
+// challenge to the client domain, e.g. the domain of the wallet the client is using.
+const ClientDomainOperationName = "client_domain"
+
 // BuildChallengeTx is a factory method that creates a valid SEP 10 challenge, for use in web authentication.
 // "timebound" is the time duration the transaction should be valid for, and must be greater than 1s (300s is recommended).
+// "clientDomain" is optional, and if set the challenge includes a client_domain Manage Data operation whose source
+// account is "clientSigningKey", the SIGNING_KEY of the client domain's stellar.toml, which must then also sign the
+// challenge.
 // More details on SEP 10: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md
-func BuildChallengeTx(serverSignerSecret, clientAccountID, homeDomain, network string, timebound time.Duration) (*Transaction, error) {
-	if timebound < time.Second {
-		return nil, errors.New("provided timebound must be at least 1s (300s is recommended)")
+func BuildChallengeTx(serverSignerSecret, clientAccountID, homeDomain, clientDomain, clientSigningKey, network string, timebound time.Duration) (*Transaction, error) {
+	serverKP, err := keypair.Parse(serverSignerSecret)
+	if err != nil {
+		return nil, err
 	}
 
-	serverKP, err := keypair.Parse(serverSignerSecret)
+	tx, err := BuildUnsignedChallengeTx(serverKP.Address(), clientAccountID, homeDomain, clientDomain, clientSigningKey, timebound)
 	if err != nil {
 		return nil, err
 	}
+	tx, err = tx.Sign(network, serverKP.(*keypair.Full))
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// BuildUnsignedChallengeTx creates a valid SEP 10 challenge like BuildChallengeTx, but without signing it, for
+// servers whose signing key is not held in memory, e.g. a key in a remote signing service. The challenge must be
+// signed by the server account before being sent to the client, e.g. using Transaction.AddSignatureBase64.
+func BuildUnsignedChallengeTx(serverAccountID, clientAccountID, homeDomain, clientDomain, clientSigningKey string, timebound time.Duration) (*Transaction, error) {
+	if timebound < time.Second {
+		return nil, errors.New("provided timebound must be at least 1s (300s is recommended)")
+	}
+
+	if _, err := xdr.AddressToAccountId(serverAccountID); err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid account id", serverAccountID)
+	}
 
 	// SEP10 spec requires 48 byte cryptographic-quality random string
 	randomNonce, err := generateRandomNonce(48)
@@ -878,7 +905,7 @@ func BuildChallengeTx(serverSignerSecret, clientAccountID, homeDomain, network s
 
 	// represent server signing account as SimpleAccount
 	sa := SimpleAccount{
-		AccountID: serverKP.Address(),
+		AccountID: serverAccountID,
 		Sequence:  0,
 	}
 
@@ -887,36 +914,39 @@ func BuildChallengeTx(serverSignerSecret, clientAccountID, homeDomain, network s
 		AccountID: clientAccountID,
 	}
 
+	operations := []Operation{
+		&ManageData{
+			SourceAccount: &ca,
+			Name:          homeDomain + " auth",
+			Value:         []byte(randomNonceToString),
+		},
+	}
+	if clientDomain != "" {
+		if _, err = xdr.AddressToAccountId(clientSigningKey); err != nil {
+			return nil, errors.Wrapf(err, "%s is not a valid client signing key", clientSigningKey)
+		}
+		operations = append(operations, &ManageData{
+			SourceAccount: &SimpleAccount{AccountID: clientSigningKey},
+			Name:          ClientDomainOperationName,
+			Value:         []byte(clientDomain),
+		})
+	}
+
 	currentTime := time.Now().UTC()
 	maxTime := currentTime.Add(timebound)
 
 	// Create a SEP 10 compatible response. See
 	// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md#response
-	tx, err := NewTransaction(
+	return NewTransaction(
 		TransactionParams{
 			SourceAccount:        &sa,
 			IncrementSequenceNum: false,
-			Operations: []Operation{
-				&ManageData{
-					SourceAccount: &ca,
-					Name:          homeDomain + " auth",
-					Value:         []byte(randomNonceToString),
-				},
-			},
-			BaseFee:    MinBaseFee,
-			Memo:       nil,
-			Timebounds: NewTimebounds(currentTime.Unix(), maxTime.Unix()),
+			Operations:           operations,
+			BaseFee:              MinBaseFee,
+			Memo:                 nil,
+			Timebounds:           NewTimebounds(currentTime.Unix(), maxTime.Unix()),
 		},
 	)
-	if err != nil {
-		return nil, err
-	}
-	tx, err = tx.Sign(network, serverKP.(*keypair.Full))
-	if err != nil {
-		return nil, err
-	}
-
-	return tx, nil
 }
 
 // generateRandomNonce creates a cryptographically secure random slice of `n` bytes.
@@ -1031,7 +1061,9 @@ func ReadChallengeTx(challengeTx, serverAccountID, network string, homeDomains [
 		return tx, clientAccountID, matchedHomeDomain, errors.New("random nonce before encoding as base64 should be 48 bytes long")
 	}
 
-	// verify subsequent operations are manage data ops with source account set to server account
+	// verify subsequent operations are manage data ops with source account
+	// set to server account, except for a client domain operation
+	clientDomainFound := false
 	for _, op := range operations[1:] {
 		op, ok := op.(*ManageData)
 		if !ok {
@@ -1040,6 +1072,16 @@ func ReadChallengeTx(challengeTx, serverAccountID, network string, homeDomains [
 		if op.SourceAccount == nil {
 			return tx, clientAccountID, matchedHomeDomain, errors.New("operation should have a source account")
 		}
+		if op.Name == ClientDomainOperationName {
+			if clientDomainFound {
+				return tx, clientAccountID, matchedHomeDomain, errors.New("transaction has more than one client_domain operation")
+			}
+			if len(op.Value) == 0 {
+				return tx, clientAccountID, matchedHomeDomain, errors.New("client_domain operation value should be the client domain")
+			}
+			clientDomainFound = true
+			continue
+		}
 		if op.SourceAccount.GetAccountID() != serverAccountID {
 			return tx, clientAccountID, matchedHomeDomain, errors.New("subsequent operations are unrecognized")
 		}
@@ -1053,6 +1095,23 @@ func ReadChallengeTx(challengeTx, serverAccountID, network string, homeDomains [
 	return tx, clientAccountID, matchedHomeDomain, nil
 }
 
+// ChallengeTxClientDomain returns the client domain and the client domain's
+// signing key of a SEP 10 challenge transaction read with ReadChallengeTx, or
+// empty strings if the challenge has no client_domain operation.
+//
+// Before accepting the challenge the signing key must be checked to match the
+// SIGNING_KEY included in the TOML file hosted on the client domain.
+func ChallengeTxClientDomain(tx *Transaction) (clientDomain string, clientSigningKey string) {
+	for _, op := range tx.Operations() {
+		op, ok := op.(*ManageData)
+		if !ok || op.Name != ClientDomainOperationName || op.SourceAccount == nil {
+			continue
+		}
+		return string(op.Value), op.SourceAccount.GetAccountID()
+	}
+	return "", ""
+}
+
 // VerifyChallengeTxThreshold verifies that for a SEP 10 challenge transaction
 // all signatures on the transaction are accounted for and that the signatures
 // meet a threshold on an account. A transaction is verified if it is signed by
@@ -1125,6 +1184,10 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network string, home
 		return nil, err
 	}
 
+	// If the challenge is attributed to a client domain, the client domain's
+	// signing key must also have signed it.
+	_, clientDomainSigningKey := ChallengeTxClientDomain(tx)
+
 	// Deduplicate the client signers and ensure the server is not included
 	// anywhere we check or output the list of signers.
 	clientSigners := []string{}
@@ -1138,6 +1201,12 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network string, home
 		if signer == serverKP.Address() {
 			continue
 		}
+		// Ignore the client domain signer for the same reason, the client
+		// domain attributes the challenge and does not authenticate the
+		// client.
+		if signer == clientDomainSigningKey {
+			continue
+		}
 		// Deduplicate.
 		if _, seen := clientSignersSeen[signer]; seen {
 			continue
@@ -1164,19 +1233,28 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network string, home
 	// checked in the ReadChallengeTx to ensure that every signature and signer
 	// are consumed only once on the transaction.
 	allSigners := append([]string{serverKP.Address()}, clientSigners...)
+	if clientDomainSigningKey != "" {
+		allSigners = append(allSigners, clientDomainSigningKey)
+	}
 	allSignersFound, err := verifyTxSignatures(tx, network, allSigners...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Confirm the server is in the list of signers found and remove it.
+	// Confirm the server and client domain are in the list of signers found
+	// and remove them.
 	serverSignerFound := false
+	clientDomainSignerFound := false
 	signersFound := make([]string, 0, len(allSignersFound)-1)
 	for _, signer := range allSignersFound {
 		if signer == serverKP.Address() {
 			serverSignerFound = true
 			continue
 		}
+		if signer == clientDomainSigningKey {
+			clientDomainSignerFound = true
+			continue
+		}
 		signersFound = append(signersFound, signer)
 	}
 
@@ -1185,6 +1263,11 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network string, home
 		return nil, errors.Errorf("transaction not signed by %s", serverKP.Address())
 	}
 
+	// Confirm we matched a signature to the client domain signer.
+	if clientDomainSigningKey != "" && !clientDomainSignerFound {
+		return nil, errors.Errorf("transaction not signed by client domain signing key %s", clientDomainSigningKey)
+	}
+
 	// Confirm we matched signatures to the client signers.
 	if len(signersFound) == 0 {
 		return nil, errors.Errorf("transaction not signed by %s", strings.Join(clientSigners, ", "))
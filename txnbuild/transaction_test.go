@@ -973,7 +973,7 @@ func TestBuildChallengeTx(t *testing.T) {
 
 	{
 		// 1 minute timebound
-		tx, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute)
+		tx, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "testanchor.stellar.org", "", "", network.TestNetworkPassphrase, time.Minute)
 		assert.NoError(t, err)
 		txeBase64, err := tx.Base64()
 		assert.NoError(t, err)
@@ -994,7 +994,7 @@ func TestBuildChallengeTx(t *testing.T) {
 
 	{
 		// 5 minutes timebound
-		tx, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "testanchor.stellar.org", network.TestNetworkPassphrase, time.Duration(5*time.Minute))
+		tx, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "testanchor.stellar.org", "", "", network.TestNetworkPassphrase, time.Duration(5*time.Minute))
 		assert.NoError(t, err)
 		txeBase64, err := tx.Base64()
 		assert.NoError(t, err)
@@ -1014,12 +1014,67 @@ func TestBuildChallengeTx(t *testing.T) {
 	}
 
 	//transaction with infinite timebound
-	_, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "sdf", network.TestNetworkPassphrase, 0)
+	_, err := BuildChallengeTx(kp0.Seed(), kp0.Address(), "sdf", "", "", network.TestNetworkPassphrase, 0)
 	if assert.Error(t, err) {
 		assert.Contains(t, err.Error(), "provided timebound must be at least 1s (300s is recommended)")
 	}
 }
 
+func TestBuildChallengeTx_clientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+
+	tx, err := BuildChallengeTx(serverKP.Seed(), clientKP.Address(), "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+
+	ops := tx.Operations()
+	require.Len(t, ops, 2)
+	op, ok := ops[1].(*ManageData)
+	require.True(t, ok)
+	assert.Equal(t, "client_domain", op.Name)
+	assert.Equal(t, []byte("testwallet.stellar.org"), op.Value)
+	assert.Equal(t, clientDomainKP.Address(), op.SourceAccount.GetAccountID())
+
+	clientDomain, clientSigningKey := ChallengeTxClientDomain(tx)
+	assert.Equal(t, "testwallet.stellar.org", clientDomain)
+	assert.Equal(t, clientDomainKP.Address(), clientSigningKey)
+
+	_, err = BuildChallengeTx(serverKP.Seed(), clientKP.Address(), "testanchor.stellar.org", "testwallet.stellar.org", "", network.TestNetworkPassphrase, time.Minute)
+	assert.EqualError(t, err, " is not a valid client signing key: strkey is 0 bytes long; minimum valid length is 5")
+}
+
+func TestBuildUnsignedChallengeTx(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+
+	tx, err := BuildUnsignedChallengeTx(serverKP.Address(), clientKP.Address(), "testanchor.stellar.org", "", "", time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, tx.Signatures(), "challenge should not be signed")
+
+	// Signing the challenge afterwards makes it a valid challenge.
+	hash, err := tx.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	sig, err := serverKP.Sign(hash[:])
+	require.NoError(t, err)
+	tx, err = tx.AddSignatureBase64(network.TestNetworkPassphrase, serverKP.Address(), base64.StdEncoding.EncodeToString(sig))
+	require.NoError(t, err)
+	txeBase64, err := tx.Base64()
+	require.NoError(t, err)
+
+	readTx, clientAccountID, _, err := ReadChallengeTx(txeBase64, serverKP.Address(), network.TestNetworkPassphrase, []string{"testanchor.stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, clientKP.Address(), clientAccountID)
+	assert.Equal(t, serverKP.Address(), readTx.SourceAccount().AccountID)
+	assert.Equal(t, int64(0), readTx.SourceAccount().Sequence)
+
+	_, err = BuildUnsignedChallengeTx("SABC", clientKP.Address(), "testanchor.stellar.org", "", "", time.Minute)
+	assert.EqualError(t, err, "SABC is not a valid account id: strkey is 4 bytes long; minimum valid length is 5")
+
+	_, err = BuildUnsignedChallengeTx(serverKP.Address(), clientKP.Address(), "testanchor.stellar.org", "", "", 0)
+	assert.EqualError(t, err, "provided timebound must be at least 1s (300s is recommended)")
+}
+
 func TestHashHex(t *testing.T) {
 	kp0 := newKeypair0()
 	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
@@ -1906,6 +1961,8 @@ func TestReadChallengeTx_acceptsV0AndV1Transactions(t *testing.T) {
 		kp0.Seed(),
 		kp0.Address(),
 		"testanchor.stellar.org",
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Hour,
 	)
@@ -1944,6 +2001,8 @@ func TestReadChallengeTx_forbidsFeeBumpTransactions(t *testing.T) {
 		kp0.Seed(),
 		kp0.Address(),
 		"testanchor.stellar.org",
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Hour,
 	)
@@ -1979,6 +2038,8 @@ func TestReadChallengeTx_forbidsMuxedAccounts(t *testing.T) {
 		kp0.Seed(),
 		kp0.Address(),
 		"testanchor.stellar.org",
+		"",
+		"",
 		network.TestNetworkPassphrase,
 		time.Hour,
 	)
@@ -2102,6 +2163,62 @@ func TestReadChallengeTx_allowsAdditionalManageDataOpsWithSourceAccountSetToServ
 	assert.NoError(t, err)
 }
 
+func TestReadChallengeTx_allowsClientDomainOperation(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTx(serverKP.Seed(), clientKP.Address(), "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	readTx, readClientAccountID, _, err := ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, []string{"testanchor.stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, clientKP.Address(), readClientAccountID)
+	clientDomain, clientSigningKey := ChallengeTxClientDomain(readTx)
+	assert.Equal(t, "testwallet.stellar.org", clientDomain)
+	assert.Equal(t, clientDomainKP.Address(), clientSigningKey)
+}
+
+func TestReadChallengeTx_disallowsMultipleClientDomainOperations(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	txSource := NewSimpleAccount(serverKP.Address(), -1)
+	opSource := NewSimpleAccount(clientKP.Address(), 0)
+	clientDomainSource := NewSimpleAccount(clientDomainKP.Address(), 0)
+	op1 := ManageData{
+		SourceAccount: &opSource,
+		Name:          "testanchor.stellar.org auth",
+		Value:         []byte(base64.StdEncoding.EncodeToString(make([]byte, 48))),
+	}
+	op2 := ManageData{
+		SourceAccount: &clientDomainSource,
+		Name:          "client_domain",
+		Value:         []byte("testwallet.stellar.org"),
+	}
+	op3 := ManageData{
+		SourceAccount: &clientDomainSource,
+		Name:          "client_domain",
+		Value:         []byte("otherwallet.stellar.org"),
+	}
+	tx64, err := newSignedTransaction(
+		TransactionParams{
+			SourceAccount:        &txSource,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&op1, &op2, &op3},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewTimeout(1000),
+		},
+		network.TestNetworkPassphrase,
+		serverKP,
+	)
+	require.NoError(t, err)
+
+	_, _, _, err = ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, []string{"testanchor.stellar.org"})
+	assert.EqualError(t, err, "transaction has more than one client_domain operation")
+}
+
 func TestReadChallengeTx_disallowsAdditionalManageDataOpsWithoutSourceAccountSetToServerAccount(t *testing.T) {
 	serverKP := newKeypair0()
 	clientKP := newKeypair1()
@@ -3411,6 +3528,40 @@ func TestVerifyChallengeTxSigners_allowsAdditionalManageDataOpsWithSourceAccount
 	assert.NoError(t, err)
 }
 
+func TestVerifyChallengeTxSigners_validClientDomainSigner(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTx(serverKP.Seed(), clientKP.Address(), "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientKP, clientDomainKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	// The client domain signing key is not returned as a signer of the
+	// client account, even if it is passed as a signer.
+	signersFound, err := VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, []string{"testanchor.stellar.org"}, clientKP.Address(), clientDomainKP.Address())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{clientKP.Address()}, signersFound)
+}
+
+func TestVerifyChallengeTxSigners_invalidClientDomainNotSigned(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTx(serverKP.Seed(), clientKP.Address(), "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	signersFound, err := VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, []string{"testanchor.stellar.org"}, clientKP.Address())
+	assert.EqualError(t, err, "transaction not signed by client domain signing key "+clientDomainKP.Address())
+	assert.Empty(t, signersFound)
+}
+
 func TestVerifyChallengeTxSigners_disallowsAdditionalManageDataOpsWithoutSourceAccountSetToServerAccount(t *testing.T) {
 	serverKP := newKeypair0()
 	clientKP := newKeypair1()